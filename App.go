@@ -34,6 +34,7 @@ import (
 	"github.com/devtron-labs/devtron/api/router"
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/internal/middleware"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/go-pg/pg"
 	_ "github.com/lib/pq"
@@ -54,6 +55,7 @@ type App struct {
 	serveTls           bool
 	sessionManager2    *authMiddleware.SessionManager
 	OtelTracingService *otel.OtelTracingServiceImpl
+	auditLogService    auditLog.AuditLogService
 }
 
 func NewApp(router *router.MuxRouter,
@@ -64,6 +66,7 @@ func NewApp(router *router.MuxRouter,
 	pubsubClient *pubsub.PubSubClientServiceImpl,
 	sessionManager2 *authMiddleware.SessionManager,
 	posthogClient *telemetry.PosthogClient,
+	auditLogService auditLog.AuditLogService,
 ) *App {
 	//check argo connection
 	//todo - check argo-cd version on acd integration installation
@@ -78,6 +81,7 @@ func NewApp(router *router.MuxRouter,
 		sessionManager2:    sessionManager2,
 		posthogClient:      posthogClient,
 		OtelTracingService: otel.NewOtelTracingServiceImpl(Logger),
+		auditLogService:    auditLogService,
 	}
 	return app
 }
@@ -139,6 +143,8 @@ func (app *App) Stop() {
 
 	app.OtelTracingService.Shutdown()
 
+	app.auditLogService.Stop()
+
 	app.Logger.Infow("closing db connection")
 	err = app.db.Close()
 	if err != nil {
@@ -24,19 +24,24 @@ import (
 	"github.com/devtron-labs/authenticator/middleware"
 	pubsub1 "github.com/devtron-labs/common-lib/pubsub-lib"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appBundle"
 	appStoreRestHandler "github.com/devtron-labs/devtron/api/appStore"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/appSync"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	client "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/api/k8s"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/releaseTrain"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	pipeline2 "github.com/devtron-labs/devtron/api/restHandler/app"
 	"github.com/devtron-labs/devtron/api/router"
@@ -60,6 +65,7 @@ import (
 	"github.com/devtron-labs/devtron/client/grafana"
 	jClient "github.com/devtron-labs/devtron/client/jira"
 	"github.com/devtron-labs/devtron/client/lens"
+	"github.com/devtron-labs/devtron/client/ociRegistry"
 	"github.com/devtron-labs/devtron/client/telemetry"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
@@ -75,10 +81,12 @@ import (
 	security2 "github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/internal/util/ArgoUtil"
+	apiToken2 "github.com/devtron-labs/devtron/pkg/apiToken"
 	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/app/status"
 	"github.com/devtron-labs/devtron/pkg/appClone"
 	"github.com/devtron-labs/devtron/pkg/appClone/batch"
+	"github.com/devtron-labs/devtron/pkg/appDefinition"
 	"github.com/devtron-labs/devtron/pkg/appGroup"
 	"github.com/devtron-labs/devtron/pkg/appStatus"
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
@@ -95,6 +103,8 @@ import (
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	"github.com/devtron-labs/devtron/pkg/deploymentGroup"
 	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/doraMetrics"
+	"github.com/devtron-labs/devtron/pkg/externalSecret"
 	"github.com/devtron-labs/devtron/pkg/git"
 	"github.com/devtron-labs/devtron/pkg/gitops"
 	jira2 "github.com/devtron-labs/devtron/pkg/jira"
@@ -102,6 +112,8 @@ import (
 	repository7 "github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/notifier"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/pipeline/drift"
+	repository9 "github.com/devtron-labs/devtron/pkg/pipeline/drift/repository"
 	history3 "github.com/devtron-labs/devtron/pkg/pipeline/history"
 	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
 	repository5 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
@@ -125,6 +137,10 @@ func InitializeApp() (*App, error) {
 		sql.PgSqlWireSet,
 		user.SelfRegistrationWireSet,
 		externalLink.ExternalLinkWireSet,
+		deploymentWindow.DeploymentWindowWireSet,
+		releaseTrain.ReleaseTrainWireSet,
+		appSync.AppSyncWireSet,
+		appBundle.AppBundleWireSet,
 		team.TeamsWireSet,
 		AuthWireSet,
 		util4.NewK8sUtil,
@@ -141,6 +157,7 @@ func InitializeApp() (*App, error) {
 		server.ServerWireSet,
 		module.ModuleWireSet,
 		apiToken.ApiTokenWireSet,
+		auditLog.AuditLogWireSet,
 		webhookHelm.WebhookHelmWireSet,
 		terminal.TerminalWireSet,
 		// -------wireset end ----------
@@ -471,6 +488,11 @@ func InitializeApp() (*App, error) {
 		repository.NewSMTPNotificationRepositoryImpl,
 		wire.Bind(new(repository.SMTPNotificationRepository), new(*repository.SMTPNotificationRepositoryImpl)),
 
+		notifier.NewNotificationChannelServiceImpl,
+		wire.Bind(new(notifier.NotificationChannelService), new(*notifier.NotificationChannelServiceImpl)),
+		repository.NewNotificationChannelConfigRepositoryImpl,
+		wire.Bind(new(repository.NotificationChannelConfigRepository), new(*repository.NotificationChannelConfigRepositoryImpl)),
+
 		notifier.NewNotificationConfigBuilderImpl,
 		wire.Bind(new(notifier.NotificationConfigBuilder), new(*notifier.NotificationConfigBuilderImpl)),
 		appStoreRestHandler.NewAppStoreStatusTimelineRestHandlerImpl,
@@ -506,6 +528,10 @@ func InitializeApp() (*App, error) {
 
 		app.NewReleaseDataServiceImpl,
 		wire.Bind(new(app.ReleaseDataService), new(*app.ReleaseDataServiceImpl)),
+		doraMetrics.NewDoraMetricsRepositoryImpl,
+		wire.Bind(new(doraMetrics.DoraMetricsRepository), new(*doraMetrics.DoraMetricsRepositoryImpl)),
+		doraMetrics.NewDoraMetricsServiceImpl,
+		wire.Bind(new(doraMetrics.DoraMetricsService), new(*doraMetrics.DoraMetricsServiceImpl)),
 		restHandler.NewReleaseMetricsRestHandlerImpl,
 		wire.Bind(new(restHandler.ReleaseMetricsRestHandler), new(*restHandler.ReleaseMetricsRestHandlerImpl)),
 		router.NewReleaseMetricsRouterImpl,
@@ -659,6 +685,8 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(router.CoreAppRouter), new(*router.CoreAppRouterImpl)),
 		restHandler.NewCoreAppRestHandlerImpl,
 		wire.Bind(new(restHandler.CoreAppRestHandler), new(*restHandler.CoreAppRestHandlerImpl)),
+		appDefinition.NewAppDefinitionServiceImpl,
+		wire.Bind(new(appDefinition.AppDefinitionService), new(*appDefinition.AppDefinitionServiceImpl)),
 
 		// Webhook
 		repository.NewGitHostRepositoryImpl,
@@ -790,6 +818,84 @@ func InitializeApp() (*App, error) {
 		cron.NewCiStatusUpdateCronImpl,
 		wire.Bind(new(cron.CiStatusUpdateCron), new(*cron.CiStatusUpdateCronImpl)),
 
+		pipelineConfig.NewCiPipelineScheduleRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CiPipelineScheduleRepository), new(*pipelineConfig.CiPipelineScheduleRepositoryImpl)),
+		pipeline.NewCiPipelineScheduleServiceImpl,
+		wire.Bind(new(pipeline.CiPipelineScheduleService), new(*pipeline.CiPipelineScheduleServiceImpl)),
+		restHandler.NewCiPipelineScheduleRestHandlerImpl,
+		wire.Bind(new(restHandler.CiPipelineScheduleRestHandler), new(*restHandler.CiPipelineScheduleRestHandlerImpl)),
+		cron.GetCiPipelineScheduleConfig,
+		cron.NewCiPipelineScheduleCronImpl,
+		wire.Bind(new(cron.CiPipelineScheduleCron), new(*cron.CiPipelineScheduleCronImpl)),
+
+		pipelineConfig.NewDeploymentApprovalRepositoryImpl,
+		wire.Bind(new(pipelineConfig.DeploymentApprovalRepository), new(*pipelineConfig.DeploymentApprovalRepositoryImpl)),
+		pipeline.NewDeploymentApprovalServiceImpl,
+		wire.Bind(new(pipeline.DeploymentApprovalService), new(*pipeline.DeploymentApprovalServiceImpl)),
+		restHandler.NewDeploymentApprovalRestHandlerImpl,
+		wire.Bind(new(restHandler.DeploymentApprovalRestHandler), new(*restHandler.DeploymentApprovalRestHandlerImpl)),
+
+		cron.GetScheduledDeploymentConfig,
+		cron.NewScheduledDeploymentCronImpl,
+		wire.Bind(new(cron.ScheduledDeploymentCron), new(*cron.ScheduledDeploymentCronImpl)),
+
+		pipelineConfig.NewCanaryAnalysisRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CanaryAnalysisRepository), new(*pipelineConfig.CanaryAnalysisRepositoryImpl)),
+		pipeline.NewCanaryAnalysisServiceImpl,
+		wire.Bind(new(pipeline.CanaryAnalysisService), new(*pipeline.CanaryAnalysisServiceImpl)),
+		restHandler.NewCanaryAnalysisRestHandlerImpl,
+		wire.Bind(new(restHandler.CanaryAnalysisRestHandler), new(*restHandler.CanaryAnalysisRestHandlerImpl)),
+		cron.GetCanaryAnalysisConfig,
+		cron.NewCanaryAnalysisCronImpl,
+		wire.Bind(new(cron.CanaryAnalysisCron), new(*cron.CanaryAnalysisCronImpl)),
+
+		pipelineConfig.NewPreviewEnvironmentRepositoryImpl,
+		wire.Bind(new(pipelineConfig.PreviewEnvironmentRepository), new(*pipelineConfig.PreviewEnvironmentRepositoryImpl)),
+		pipeline.NewPreviewEnvironmentServiceImpl,
+		wire.Bind(new(pipeline.PreviewEnvironmentService), new(*pipeline.PreviewEnvironmentServiceImpl)),
+		restHandler.NewPreviewEnvironmentRestHandlerImpl,
+		wire.Bind(new(restHandler.PreviewEnvironmentRestHandler), new(*restHandler.PreviewEnvironmentRestHandlerImpl)),
+		cron.GetPreviewEnvironmentCronConfig,
+		cron.NewPreviewEnvironmentCronImpl,
+		wire.Bind(new(cron.PreviewEnvironmentCron), new(*cron.PreviewEnvironmentCronImpl)),
+
+		pipelineConfig.NewCiBuildCacheRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CiBuildCacheRepository), new(*pipelineConfig.CiBuildCacheRepositoryImpl)),
+		pipeline.NewCiBuildCacheServiceImpl,
+		wire.Bind(new(pipeline.CiBuildCacheService), new(*pipeline.CiBuildCacheServiceImpl)),
+		restHandler.NewCiBuildCacheRestHandlerImpl,
+		wire.Bind(new(restHandler.CiBuildCacheRestHandler), new(*restHandler.CiBuildCacheRestHandlerImpl)),
+		cron.GetBuildCacheRetentionConfig,
+		cron.NewBuildCacheRetentionCronImpl,
+		wire.Bind(new(cron.BuildCacheRetentionCron), new(*cron.BuildCacheRetentionCronImpl)),
+
+		cron.GetUserAccessGrantCronConfig,
+		cron.NewUserAccessGrantCronImpl,
+		wire.Bind(new(cron.UserAccessGrantCron), new(*cron.UserAccessGrantCronImpl)),
+
+		apiToken2.NewApiTokenExpiryNotificationServiceImpl,
+		wire.Bind(new(apiToken2.ApiTokenExpiryNotificationService), new(*apiToken2.ApiTokenExpiryNotificationServiceImpl)),
+		cron.GetApiTokenExpiryNotificationCronConfig,
+		cron.NewApiTokenExpiryNotificationCronImpl,
+		wire.Bind(new(cron.ApiTokenExpiryNotificationCron), new(*cron.ApiTokenExpiryNotificationCronImpl)),
+
+		pipeline.NewImagePlatformValidationServiceImpl,
+		wire.Bind(new(pipeline.ImagePlatformValidationService), new(*pipeline.ImagePlatformValidationServiceImpl)),
+
+		security2.NewSbomRepositoryImpl,
+		wire.Bind(new(security2.SbomRepository), new(*security2.SbomRepositoryImpl)),
+		pipeline.NewSbomServiceImpl,
+		wire.Bind(new(pipeline.SbomService), new(*pipeline.SbomServiceImpl)),
+		restHandler.NewSbomRestHandlerImpl,
+		wire.Bind(new(restHandler.SbomRestHandler), new(*restHandler.SbomRestHandlerImpl)),
+
+		security2.NewImageSigningRepositoryImpl,
+		wire.Bind(new(security2.ImageSigningRepository), new(*security2.ImageSigningRepositoryImpl)),
+		pipeline.NewImageSigningServiceImpl,
+		wire.Bind(new(pipeline.ImageSigningService), new(*pipeline.ImageSigningServiceImpl)),
+		restHandler.NewImageSigningRestHandlerImpl,
+		wire.Bind(new(restHandler.ImageSigningRestHandler), new(*restHandler.ImageSigningRestHandlerImpl)),
+
 		restHandler.NewPipelineStatusTimelineRestHandlerImpl,
 		wire.Bind(new(restHandler.PipelineStatusTimelineRestHandler), new(*restHandler.PipelineStatusTimelineRestHandlerImpl)),
 
@@ -858,10 +964,34 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(appGroup2.AppGroupMappingRepository), new(*appGroup2.AppGroupMappingRepositoryImpl)),
 		pipeline.NewArgoWorkflowExecutorImpl,
 		wire.Bind(new(pipeline.ArgoWorkflowExecutor), new(*pipeline.ArgoWorkflowExecutorImpl)),
+		pipeline.NewSystemWorkflowExecutorImpl,
+		wire.Bind(new(pipeline.SystemWorkflowExecutor), new(*pipeline.SystemWorkflowExecutorImpl)),
+		repository.NewSchedulerLeaseRepositoryImpl,
+		wire.Bind(new(repository.SchedulerLeaseRepository), new(*repository.SchedulerLeaseRepositoryImpl)),
 		repository5.NewManifestPushConfigRepository,
 		wire.Bind(new(repository5.ManifestPushConfigRepository), new(*repository5.ManifestPushConfigRepositoryImpl)),
 		app.NewGitOpsManifestPushServiceImpl,
 		wire.Bind(new(app.GitOpsPushService), new(*app.GitOpsManifestPushServiceImpl)),
+		ociRegistry.GetOCIRegistryClientConfig,
+		ociRegistry.NewOCIRegistryClientImpl,
+		wire.Bind(new(ociRegistry.OCIRegistryClient), new(*ociRegistry.OCIRegistryClientImpl)),
+		app.NewOCIManifestPushServiceImpl,
+		wire.Bind(new(app.OCIPushService), new(*app.OCIManifestPushServiceImpl)),
+
+		externalSecret.GetExternalSecretConfig,
+		externalSecret.NewExternalSecretServiceImpl,
+		wire.Bind(new(externalSecret.ExternalSecretService), new(*externalSecret.ExternalSecretServiceImpl)),
+
+		repository9.NewConfigDriftScanRepositoryImpl,
+		wire.Bind(new(repository9.ConfigDriftScanRepository), new(*repository9.ConfigDriftScanRepositoryImpl)),
+		drift.NewConfigDriftServiceImpl,
+		wire.Bind(new(drift.ConfigDriftService), new(*drift.ConfigDriftServiceImpl)),
+		cron.GetConfigDriftScanConfig,
+		cron.NewConfigDriftScanCronImpl,
+		wire.Bind(new(cron.ConfigDriftScanCron), new(*cron.ConfigDriftScanCronImpl)),
+		cron.GetAppSyncConfig,
+		cron.NewAppSyncCronImpl,
+		wire.Bind(new(cron.AppSyncCron), new(*cron.AppSyncCronImpl)),
 	)
 	return &App{}, nil
 }
@@ -6,6 +6,7 @@ import (
 
 type ApiTokenRouter interface {
 	InitApiTokenRouter(configRouter *mux.Router)
+	InitApiTokenMiddleware(router *mux.Router)
 }
 
 type ApiTokenRouterImpl struct {
@@ -21,5 +22,10 @@ func (impl ApiTokenRouterImpl) InitApiTokenRouter(configRouter *mux.Router) {
 	configRouter.Path("").HandlerFunc(impl.apiTokenRestHandler.CreateApiToken).Methods("POST")
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.UpdateApiToken).Methods("PUT")
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.DeleteApiToken).Methods("DELETE")
+	configRouter.Path("/{id}/rotate").HandlerFunc(impl.apiTokenRestHandler.RotateApiToken).Methods("POST")
 	configRouter.Path("/webhook").HandlerFunc(impl.apiTokenRestHandler.GetAllApiTokensForWebhook).Methods("GET")
 }
+
+func (impl ApiTokenRouterImpl) InitApiTokenMiddleware(router *mux.Router) {
+	router.Use(impl.apiTokenRestHandler.ApiTokenMiddleware)
+}
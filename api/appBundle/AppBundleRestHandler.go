@@ -0,0 +1,150 @@
+package appBundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appBundle"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strings"
+)
+
+type AppBundleRestHandler interface {
+	ExportApp(w http.ResponseWriter, r *http.Request)
+	ValidateImport(w http.ResponseWriter, r *http.Request)
+	ImportApp(w http.ResponseWriter, r *http.Request)
+}
+
+type AppBundleRestHandlerImpl struct {
+	logger           *zap.SugaredLogger
+	userService      user.UserService
+	validator        *validator.Validate
+	enforcer         casbin.Enforcer
+	enforcerUtil     rbac.EnforcerUtil
+	appBundleService appBundle.AppBundleService
+}
+
+func NewAppBundleRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil, appBundleService appBundle.AppBundleService) *AppBundleRestHandlerImpl {
+	return &AppBundleRestHandlerImpl{
+		logger:           logger,
+		userService:      userService,
+		validator:        validator,
+		enforcer:         enforcer,
+		enforcerUtil:     enforcerUtil,
+		appBundleService: appBundleService,
+	}
+}
+
+func (handler *AppBundleRestHandlerImpl) ExportApp(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request appBundle.ExportRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, ExportApp", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, ExportApp", "appId", request.AppId, "secretMode", request.SecretMode)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, ExportApp", "err", err, "appId", request.AppId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// rbac, the bundle carries the complete config of the app so user should be admin of the app
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	// environments are checked while building the bundle, export fails if any of them is not accessible
+	request.Enforce = func(resource string, action string, object string) bool {
+		return handler.enforcer.Enforce(token, resource, action, object)
+	}
+	// rbac ends
+
+	res, err := handler.appBundleService.Export(r.Context(), &request)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportApp", "err", err, "appId", request.AppId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppBundleRestHandlerImpl) ValidateImport(w http.ResponseWriter, r *http.Request) {
+	handler.importApp(w, r, true)
+}
+
+func (handler *AppBundleRestHandlerImpl) ImportApp(w http.ResponseWriter, r *http.Request) {
+	handler.importApp(w, r, false)
+}
+
+func (handler *AppBundleRestHandlerImpl) importApp(w http.ResponseWriter, r *http.Request, validateOnly bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request appBundle.ImportRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, importApp", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, importApp", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	report, err := handler.appBundleService.ValidateImport(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, ValidateImport", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	handler.logger.Infow("request payload, importApp", "appName", report.AppName, "projectName", report.ProjectName, "validateOnly", validateOnly)
+
+	// rbac, with admin roles, you have to access for all the apps of the project to create new app
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, fmt.Sprintf("%s/%s", strings.ToLower(report.ProjectName), "*")); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	if validateOnly || !report.Valid {
+		common.WriteJsonResp(w, nil, report, http.StatusOK)
+		return
+	}
+	for _, env := range report.Environments {
+		object := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(report.AppName, env.Id)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionCreate, object); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	// rbac ends
+
+	report, err = handler.appBundleService.Import(r.Context(), &request)
+	if err != nil {
+		handler.logger.Errorw("service err, ImportApp", "err", err, "appName", request.AppName)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, report, http.StatusOK)
+}
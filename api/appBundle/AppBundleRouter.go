@@ -0,0 +1,23 @@
+package appBundle
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AppBundleRouter interface {
+	InitAppBundleRouter(router *mux.Router)
+}
+
+type AppBundleRouterImpl struct {
+	appBundleRestHandler AppBundleRestHandler
+}
+
+func NewAppBundleRouterImpl(appBundleRestHandler AppBundleRestHandler) *AppBundleRouterImpl {
+	return &AppBundleRouterImpl{appBundleRestHandler: appBundleRestHandler}
+}
+
+func (impl AppBundleRouterImpl) InitAppBundleRouter(router *mux.Router) {
+	router.Path("/export").HandlerFunc(impl.appBundleRestHandler.ExportApp).Methods("POST")
+	router.Path("/import/validate").HandlerFunc(impl.appBundleRestHandler.ValidateImport).Methods("POST")
+	router.Path("/import").HandlerFunc(impl.appBundleRestHandler.ImportApp).Methods("POST")
+}
@@ -0,0 +1,15 @@
+package appBundle
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appBundle"
+	"github.com/google/wire"
+)
+
+var AppBundleWireSet = wire.NewSet(
+	appBundle.NewAppBundleServiceImpl,
+	wire.Bind(new(appBundle.AppBundleService), new(*appBundle.AppBundleServiceImpl)),
+	NewAppBundleRestHandlerImpl,
+	wire.Bind(new(AppBundleRestHandler), new(*AppBundleRestHandlerImpl)),
+	NewAppBundleRouterImpl,
+	wire.Bind(new(AppBundleRouter), new(*AppBundleRouterImpl)),
+)
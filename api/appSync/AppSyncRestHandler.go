@@ -0,0 +1,218 @@
+package appSync
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appSync"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type AppSyncRestHandler interface {
+	CreateSource(w http.ResponseWriter, r *http.Request)
+	UpdateSource(w http.ResponseWriter, r *http.Request)
+	DeleteSource(w http.ResponseWriter, r *http.Request)
+	GetSource(w http.ResponseWriter, r *http.Request)
+	GetAllSources(w http.ResponseWriter, r *http.Request)
+	Sync(w http.ResponseWriter, r *http.Request)
+	GetSyncStatus(w http.ResponseWriter, r *http.Request)
+}
+
+type AppSyncRestHandlerImpl struct {
+	logger         *zap.SugaredLogger
+	userService    user.UserService
+	validator      *validator.Validate
+	enforcer       casbin.Enforcer
+	appSyncService appSync.AppSyncService
+}
+
+func NewAppSyncRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, appSyncService appSync.AppSyncService) *AppSyncRestHandlerImpl {
+	return &AppSyncRestHandlerImpl{
+		logger:         logger,
+		userService:    userService,
+		validator:      validator,
+		enforcer:       enforcer,
+		appSyncService: appSyncService,
+	}
+}
+
+func (handler *AppSyncRestHandlerImpl) CreateSource(w http.ResponseWriter, r *http.Request) {
+	handler.saveSource(w, r, false)
+}
+
+func (handler *AppSyncRestHandlerImpl) UpdateSource(w http.ResponseWriter, r *http.Request) {
+	handler.saveSource(w, r, true)
+}
+
+func (handler *AppSyncRestHandlerImpl) saveSource(w http.ResponseWriter, r *http.Request, isUpdate bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request appSync.AppSyncSourceDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, saveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, saveSource", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, saveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	var res *appSync.AppSyncSourceDto
+	if isUpdate {
+		res, err = handler.appSyncService.UpdateSource(&request)
+	} else {
+		res, err = handler.appSyncService.CreateSource(&request)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, saveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppSyncRestHandlerImpl) DeleteSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	err = handler.appSyncService.DeleteSource(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSource", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *AppSyncRestHandlerImpl) GetSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	res, err := handler.appSyncService.GetSource(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSource", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppSyncRestHandlerImpl) GetAllSources(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	res, err := handler.appSyncService.GetAllSources()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllSources", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppSyncRestHandlerImpl) Sync(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	dryRun := false
+	if dryRunParam := r.URL.Query().Get("dryRun"); len(dryRunParam) > 0 {
+		dryRun, err = strconv.ParseBool(dryRunParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	request := &appSync.SyncRequest{SyncSourceId: id, DryRun: dryRun, UserId: userId}
+	res, err := handler.appSyncService.Sync(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("service err, Sync", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppSyncRestHandlerImpl) GetSyncStatus(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isSuperAdmin(w, r) {
+		return
+	}
+	res, err := handler.appSyncService.GetSyncStatus(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSyncStatus", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// isSuperAdmin writes forbidden for other users, sync sources create and delete apps across projects
+func (handler *AppSyncRestHandlerImpl) isSuperAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package appSync
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AppSyncRouter interface {
+	InitAppSyncRouter(router *mux.Router)
+}
+
+type AppSyncRouterImpl struct {
+	appSyncRestHandler AppSyncRestHandler
+}
+
+func NewAppSyncRouterImpl(appSyncRestHandler AppSyncRestHandler) *AppSyncRouterImpl {
+	return &AppSyncRouterImpl{appSyncRestHandler: appSyncRestHandler}
+}
+
+func (impl AppSyncRouterImpl) InitAppSyncRouter(router *mux.Router) {
+	router.Path("/source").HandlerFunc(impl.appSyncRestHandler.CreateSource).Methods("POST")
+	router.Path("/source").HandlerFunc(impl.appSyncRestHandler.UpdateSource).Methods("PUT")
+	router.Path("/source").HandlerFunc(impl.appSyncRestHandler.GetAllSources).Methods("GET")
+	router.Path("/source/{id}").HandlerFunc(impl.appSyncRestHandler.GetSource).Methods("GET")
+	router.Path("/source/{id}").HandlerFunc(impl.appSyncRestHandler.DeleteSource).Methods("DELETE")
+
+	router.Path("/source/{id}/sync").HandlerFunc(impl.appSyncRestHandler.Sync).Methods("POST")
+	router.Path("/source/{id}/status").HandlerFunc(impl.appSyncRestHandler.GetSyncStatus).Methods("GET")
+}
@@ -0,0 +1,20 @@
+package appSync
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appSync"
+	"github.com/google/wire"
+)
+
+var AppSyncWireSet = wire.NewSet(
+	appSync.NewAppSyncRepositoryImpl,
+	wire.Bind(new(appSync.AppSyncRepository), new(*appSync.AppSyncRepositoryImpl)),
+	appSync.NewGitManagedAppServiceImpl,
+	wire.Bind(new(appSync.GitManagedAppService), new(*appSync.GitManagedAppServiceImpl)),
+
+	appSync.NewAppSyncServiceImpl,
+	wire.Bind(new(appSync.AppSyncService), new(*appSync.AppSyncServiceImpl)),
+	NewAppSyncRestHandlerImpl,
+	wire.Bind(new(AppSyncRestHandler), new(*AppSyncRestHandlerImpl)),
+	NewAppSyncRouterImpl,
+	wire.Bind(new(AppSyncRouter), new(*AppSyncRouterImpl)),
+)
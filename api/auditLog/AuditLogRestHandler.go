@@ -0,0 +1,161 @@
+package auditLog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"go.uber.org/zap"
+)
+
+type AuditLogRestHandler interface {
+	GetAuditLogs(w http.ResponseWriter, r *http.Request)
+	// AuditLogMiddleware records every mutating call with the user, client ip, resource and the response status
+	AuditLogMiddleware(next http.Handler) http.Handler
+}
+
+type AuditLogRestHandlerImpl struct {
+	logger          *zap.SugaredLogger
+	auditLogService auditLog.AuditLogService
+	userService     user.UserService
+	enforcer        casbin.Enforcer
+}
+
+func NewAuditLogRestHandlerImpl(logger *zap.SugaredLogger, auditLogService auditLog.AuditLogService, userService user.UserService,
+	enforcer casbin.Enforcer) *AuditLogRestHandlerImpl {
+	return &AuditLogRestHandlerImpl{
+		logger:          logger,
+		auditLogService: auditLogService,
+		userService:     userService,
+		enforcer:        enforcer,
+	}
+}
+
+func (impl AuditLogRestHandlerImpl) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	filter, err := getAuditLogFilter(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// service call
+	res, err := impl.auditLogService.GetAuditLogs(filter)
+	if err != nil {
+		impl.logger.Errorw("service err, GetAuditLogs", "filter", filter, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func getAuditLogFilter(r *http.Request) (*auditLog.AuditLogFilter, error) {
+	v := r.URL.Query()
+	filter := &auditLog.AuditLogFilter{
+		EmailId:      v.Get("emailId"),
+		UserType:     v.Get("userType"),
+		ApiTokenName: v.Get("apiTokenName"),
+		Method:       strings.ToUpper(v.Get("method")),
+		Resource:     v.Get("resource"),
+		Action:       strings.ToUpper(v.Get("action")),
+	}
+	for param, value := range map[string]*int{"statusCode": &filter.StatusCode, "offset": &filter.Offset, "size": &filter.Size} {
+		if len(v.Get(param)) == 0 {
+			continue
+		}
+		intValue, err := strconv.Atoi(v.Get(param))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", param, v.Get(param))
+		}
+		*value = intValue
+	}
+	if len(v.Get("userId")) > 0 {
+		userId, err := strconv.Atoi(v.Get("userId"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid userId %q", v.Get("userId"))
+		}
+		filter.UserId = int32(userId)
+	}
+	for param, value := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if len(v.Get(param)) == 0 {
+			continue
+		}
+		timeValue, err := time.Parse(time.RFC3339, v.Get(param))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q, expected RFC3339 time", param, v.Get(param))
+		}
+		*value = timeValue
+	}
+	return filter, nil
+}
+
+func (impl AuditLogRestHandlerImpl) AuditLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !impl.auditLogService.IsEnabled() || !auditLog.IsMutatingMethod(r.Method) || impl.auditLogService.IsExcluded(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		entry := auditLog.NewAuditLogEntry(r, impl.auditLogService.GetTrustedProxies())
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, auditLog.WithAuditLogEntry(r, entry))
+		entry.AuditLog.StatusCode = recorder.statusCode
+		entry.AuditLog.DurationInMs = time.Since(entry.AuditLog.ActionTime).Milliseconds()
+		impl.auditLogService.Record(entry)
+	})
+}
+
+// statusRecorder captures the response status, hijacking and flushing are passed through for streaming handlers
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (recorder *statusRecorder) WriteHeader(statusCode int) {
+	if !recorder.wroteHeader {
+		recorder.statusCode = statusCode
+		recorder.wroteHeader = true
+	}
+	recorder.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (recorder *statusRecorder) Write(data []byte) (int, error) {
+	recorder.wroteHeader = true
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	recorder.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -0,0 +1,26 @@
+package auditLog
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AuditLogRouter interface {
+	InitAuditLogRouter(auditLogRouter *mux.Router)
+	InitAuditLogMiddleware(router *mux.Router)
+}
+
+type AuditLogRouterImpl struct {
+	auditLogRestHandler AuditLogRestHandler
+}
+
+func NewAuditLogRouterImpl(auditLogRestHandler AuditLogRestHandler) *AuditLogRouterImpl {
+	return &AuditLogRouterImpl{auditLogRestHandler: auditLogRestHandler}
+}
+
+func (impl AuditLogRouterImpl) InitAuditLogRouter(auditLogRouter *mux.Router) {
+	auditLogRouter.Path("").HandlerFunc(impl.auditLogRestHandler.GetAuditLogs).Methods("GET")
+}
+
+func (impl AuditLogRouterImpl) InitAuditLogMiddleware(router *mux.Router) {
+	router.Use(impl.auditLogRestHandler.AuditLogMiddleware)
+}
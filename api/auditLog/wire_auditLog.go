@@ -0,0 +1,17 @@
+package auditLog
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/google/wire"
+)
+
+var AuditLogWireSet = wire.NewSet(
+	auditLog.NewAuditLogRepositoryImpl,
+	wire.Bind(new(auditLog.AuditLogRepository), new(*auditLog.AuditLogRepositoryImpl)),
+	auditLog.NewAuditLogServiceImpl,
+	wire.Bind(new(auditLog.AuditLogService), new(*auditLog.AuditLogServiceImpl)),
+	NewAuditLogRestHandlerImpl,
+	wire.Bind(new(AuditLogRestHandler), new(*AuditLogRestHandlerImpl)),
+	NewAuditLogRouterImpl,
+	wire.Bind(new(AuditLogRouter), new(*AuditLogRouterImpl)),
+)
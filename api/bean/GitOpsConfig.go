@@ -12,6 +12,7 @@ type GitOpsConfigDto struct {
 	AzureProjectName     string `json:"azureProjectName"`
 	BitBucketWorkspaceId string `json:"bitBucketWorkspaceId"`
 	BitBucketProjectKey  string `json:"bitBucketProjectKey"`
+	GiteaOrgId           string `json:"giteaOrgId"`
 
 	GitRepoName string `json:"gitRepoName"`
 	UserEmailId string `json:"userEmailId"`
@@ -31,4 +32,5 @@ type GitRepoRequestDto struct {
 	AzureProjectName     string `json:"azureProjectName"`
 	BitBucketWorkspaceId string `json:"bitBucketWorkspaceId"`
 	BitBucketProjectKey  string `json:"bitBucketProjectKey"`
+	GiteaOrgId           string `json:"giteaOrgId"`
 }
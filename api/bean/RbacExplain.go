@@ -0,0 +1,65 @@
+package bean
+
+// RbacCheck is a single enforcer check, like resource applications, action trigger and object team/app
+type RbacCheck struct {
+	Resource string `json:"resource" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+	Object   string `json:"object" validate:"required"`
+}
+
+// RbacExplainRequest is a check to be explained for a user or api token, identified by id or email
+type RbacExplainRequest struct {
+	UserId  int32  `json:"userId"`
+	EmailId string `json:"emailId"`
+	RbacCheck
+}
+
+// RbacPolicyDto is a policy line of a role, Path is the chain of groups and roles through which the user has it
+type RbacPolicyDto struct {
+	Role     string   `json:"role"`
+	Path     []string `json:"path"`
+	Resource string   `json:"resource"`
+	Action   string   `json:"action"`
+	Object   string   `json:"object"`
+	Effect   string   `json:"effect"`
+}
+
+type RbacExplainDto struct {
+	UserId  int32  `json:"userId"`
+	EmailId string `json:"emailId"`
+	RbacCheck
+	Allowed         bool             `json:"allowed"`
+	Reason          string           `json:"reason"`
+	MatchedPolicies []*RbacPolicyDto `json:"matchedPolicies"`
+}
+
+// EffectiveRoleDto is a role the user has directly or through groups, RoleFilter is set for roles managed through the
+// user and group apis
+type EffectiveRoleDto struct {
+	Role       string           `json:"role"`
+	Path       []string         `json:"path"`
+	Groups     []string         `json:"groups,omitempty"`
+	RoleFilter *RoleFilter      `json:"roleFilter,omitempty"`
+	Policies   []*RbacPolicyDto `json:"policies"`
+}
+
+type EffectivePermissionsDto struct {
+	UserId     int32               `json:"userId"`
+	EmailId    string              `json:"emailId"`
+	UserType   string              `json:"userType,omitempty"`
+	SuperAdmin bool                `json:"superAdmin"`
+	Roles      []*EffectiveRoleDto `json:"roles"`
+}
+
+// RbacAccessReviewRequest looks up the users allowed all the checks, like trigger needing both the app and the
+// environment checks
+type RbacAccessReviewRequest struct {
+	Checks []*RbacCheck `json:"checks" validate:"required,min=1,dive"`
+}
+
+type RbacSubjectAccessDto struct {
+	UserId          int32            `json:"userId"`
+	EmailId         string           `json:"emailId"`
+	UserType        string           `json:"userType,omitempty"`
+	MatchedPolicies []*RbacPolicyDto `json:"matchedPolicies"`
+}
@@ -0,0 +1,70 @@
+package bean
+
+import "time"
+
+// AccessGrantRequest grants the roles of the role filters and the groups to a user for a window of time, access is
+// effective from StartsOn, or immediately when not set, till ExpiresOn
+type AccessGrantRequest struct {
+	UserId      int32        `json:"userId" validate:"required"`
+	RoleFilters []RoleFilter `json:"roleFilters"`
+	Groups      []string     `json:"groups"`
+	StartsOn    *time.Time   `json:"startsOn,omitempty"`
+	ExpiresOn   time.Time    `json:"expiresOn" validate:"required"`
+	Reason      string       `json:"reason,omitempty"`
+	GrantedBy   int32        `json:"-"`
+}
+
+type AccessGrantDto struct {
+	Id              int         `json:"id"`
+	UserId          int32       `json:"userId"`
+	EmailId         string      `json:"emailId,omitempty"`
+	RoleFilter      *RoleFilter `json:"roleFilter,omitempty"`
+	Group           string      `json:"group,omitempty"`
+	AccessRequestId int         `json:"accessRequestId,omitempty"`
+	StartsOn        time.Time   `json:"startsOn"`
+	ExpiresOn       time.Time   `json:"expiresOn"`
+	Status          string      `json:"status"`
+	Reason          string      `json:"reason,omitempty"`
+	GrantedBy       int32       `json:"grantedBy"`
+	GrantedOn       time.Time   `json:"grantedOn"`
+	RevokedBy       int32       `json:"revokedBy,omitempty"`
+	RevokedOn       *time.Time  `json:"revokedOn,omitempty"`
+}
+
+// AccessRequestDto is access asked for by the logged in user, either the roles of one role filter like trigger on an
+// app and environment or exec on a cluster namespace, or a role group
+type AccessRequestDto struct {
+	Id            int         `json:"id"`
+	UserId        int32       `json:"userId"`
+	EmailId       string      `json:"emailId,omitempty"`
+	RoleFilter    *RoleFilter `json:"roleFilter,omitempty"`
+	Group         string      `json:"group,omitempty"`
+	Reason        string      `json:"reason" validate:"required"`
+	StartsOn      *time.Time  `json:"startsOn,omitempty"`
+	ExpiresOn     time.Time   `json:"expiresOn" validate:"required"`
+	Status        string      `json:"status"`
+	ReviewedBy    int32       `json:"reviewedBy,omitempty"`
+	ReviewedOn    *time.Time  `json:"reviewedOn,omitempty"`
+	ReviewComment string      `json:"reviewComment,omitempty"`
+	RequestedOn   time.Time   `json:"requestedOn"`
+}
+
+type AccessRequestReviewDto struct {
+	Id      int    `json:"id" validate:"required"`
+	Approve bool   `json:"approve"`
+	Comment string `json:"comment,omitempty"`
+	// ExpiresOn shortens the window asked for, it can not be later than the expiry requested
+	ExpiresOn  *time.Time `json:"expiresOn,omitempty"`
+	ReviewedBy int32      `json:"-"`
+}
+
+type AccessAuditDto struct {
+	Id              int       `json:"id"`
+	UserId          int32     `json:"userId"`
+	AccessGrantId   int       `json:"accessGrantId,omitempty"`
+	AccessRequestId int       `json:"accessRequestId,omitempty"`
+	Action          string    `json:"action"`
+	Details         string    `json:"details,omitempty"`
+	PerformedBy     int32     `json:"performedBy"`
+	PerformedOn     time.Time `json:"performedOn"`
+}
@@ -52,6 +52,11 @@ type ValuesOverrideRequest struct {
 	CdWorkflowType                        WorkflowType                `json:"cdWorkflowType,notnull"`
 	WfrId                                 int                         `json:"wfrId,notnull"`
 	CdWorkflowId                          int                         `json:"cdWorkflowId"`
+	DeploymentWindowOverride              bool                        `json:"deploymentWindowOverride"`
+	DeploymentWindowOverrideReason        string                      `json:"deploymentWindowOverrideReason"`
+	IsRollback                            bool                        `json:"-"` //set for automated rollbacks, skips deployment window and approval checks and audits the bypass
+	RollbackReason                        string                      `json:"-"` //recorded in the deployment window and approval audit of a rollback
+	CanaryAnalysisRunId                   int                         `json:"-"` //failed canary analysis run rolled back by this deployment
 	UserId                                int32                       `json:"-"`
 	DeploymentType                        models.DeploymentType       `json:"-"`
 	EnvId                                 int                         `json:"-"`
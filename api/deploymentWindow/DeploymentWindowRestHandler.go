@@ -0,0 +1,285 @@
+package deploymentWindow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type DeploymentWindowRestHandler interface {
+	CreateWindow(w http.ResponseWriter, r *http.Request)
+	UpdateWindow(w http.ResponseWriter, r *http.Request)
+	DeleteWindow(w http.ResponseWriter, r *http.Request)
+	GetWindows(w http.ResponseWriter, r *http.Request)
+	GetWindowState(w http.ResponseWriter, r *http.Request)
+	ScheduleDeployment(w http.ResponseWriter, r *http.Request)
+	GetScheduledDeployments(w http.ResponseWriter, r *http.Request)
+	CancelScheduledDeployment(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentWindowRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	userService             user.UserService
+	validator               *validator.Validate
+	enforcer                casbin.Enforcer
+	enforcerUtil            rbac.EnforcerUtil
+	pipelineRepository      pipelineConfig.PipelineRepository
+	deploymentWindowService deploymentWindow.DeploymentWindowService
+}
+
+func NewDeploymentWindowRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	deploymentWindowService deploymentWindow.DeploymentWindowService) *DeploymentWindowRestHandlerImpl {
+	return &DeploymentWindowRestHandlerImpl{
+		logger:                  logger,
+		userService:             userService,
+		validator:               validator,
+		enforcer:                enforcer,
+		enforcerUtil:            enforcerUtil,
+		pipelineRepository:      pipelineRepository,
+		deploymentWindowService: deploymentWindowService,
+	}
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) CreateWindow(w http.ResponseWriter, r *http.Request) {
+	handler.saveWindow(w, r, false)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) UpdateWindow(w http.ResponseWriter, r *http.Request) {
+	handler.saveWindow(w, r, true)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) saveWindow(w http.ResponseWriter, r *http.Request, isUpdate bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request deploymentWindow.DeploymentWindowDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, saveWindow", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, saveWindow", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, saveWindow", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// deployment windows are governance policy, only super admin can manage them
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	var res *deploymentWindow.DeploymentWindowDto
+	if isUpdate {
+		res, err = handler.deploymentWindowService.UpdateWindow(&request)
+	} else {
+		res, err = handler.deploymentWindowService.CreateWindow(&request)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, saveWindow", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) DeleteWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.deploymentWindowService.DeleteWindow(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteWindow", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetWindows(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(r.URL.Query().Get("envId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+		return
+	}
+	res, err := handler.deploymentWindowService.GetWindows(envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetWindows", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetWindowState(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(r.URL.Query().Get("envId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+		return
+	}
+	res, err := handler.deploymentWindowService.GetDeploymentWindowState(envId, time.Now())
+	if err != nil {
+		handler.logger.Errorw("service err, GetWindowState", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) ScheduleDeployment(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request deploymentWindow.ScheduledDeploymentDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, ScheduleDeployment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, ScheduleDeployment", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, ScheduleDeployment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, err := handler.pipelineRepository.FindById(request.PipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", request.PipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkTriggerAccess(w, r.Header.Get("token"), cdPipeline.AppId, cdPipeline.Id) {
+		return
+	}
+	res, err := handler.deploymentWindowService.ScheduleDeployment(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, ScheduleDeployment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetScheduledDeployments(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(r.URL.Query().Get("pipelineId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid pipelineId", http.StatusBadRequest)
+		return
+	}
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.deploymentWindowService.GetPendingScheduledDeployments(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetScheduledDeployments", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) CancelScheduledDeployment(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	scheduledDeployment, err := handler.deploymentWindowService.GetScheduledDeploymentById(id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching scheduled deployment", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	cdPipeline, err := handler.pipelineRepository.FindById(scheduledDeployment.PipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", scheduledDeployment.PipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkTriggerAccess(w, r.Header.Get("token"), cdPipeline.AppId, cdPipeline.Id) {
+		return
+	}
+	err = handler.deploymentWindowService.CancelScheduledDeployment(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelScheduledDeployment", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) checkTriggerAccess(w http.ResponseWriter, token string, appId int, pipelineId int) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	object = handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(appId, pipelineId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
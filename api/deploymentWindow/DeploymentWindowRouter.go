@@ -0,0 +1,29 @@
+package deploymentWindow
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentWindowRouter interface {
+	InitDeploymentWindowRouter(router *mux.Router)
+}
+
+type DeploymentWindowRouterImpl struct {
+	deploymentWindowRestHandler DeploymentWindowRestHandler
+}
+
+func NewDeploymentWindowRouterImpl(deploymentWindowRestHandler DeploymentWindowRestHandler) *DeploymentWindowRouterImpl {
+	return &DeploymentWindowRouterImpl{deploymentWindowRestHandler: deploymentWindowRestHandler}
+}
+
+func (impl DeploymentWindowRouterImpl) InitDeploymentWindowRouter(router *mux.Router) {
+	router.Path("").HandlerFunc(impl.deploymentWindowRestHandler.CreateWindow).Methods("POST")
+	router.Path("").HandlerFunc(impl.deploymentWindowRestHandler.UpdateWindow).Methods("PUT")
+	router.Path("").HandlerFunc(impl.deploymentWindowRestHandler.GetWindows).Queries("envId", "{envId}").Methods("GET")
+	router.Path("/{id}").HandlerFunc(impl.deploymentWindowRestHandler.DeleteWindow).Methods("DELETE")
+	router.Path("/state").HandlerFunc(impl.deploymentWindowRestHandler.GetWindowState).Queries("envId", "{envId}").Methods("GET")
+
+	router.Path("/schedule-deploy").HandlerFunc(impl.deploymentWindowRestHandler.ScheduleDeployment).Methods("POST")
+	router.Path("/schedule-deploy").HandlerFunc(impl.deploymentWindowRestHandler.GetScheduledDeployments).Queries("pipelineId", "{pipelineId}").Methods("GET")
+	router.Path("/schedule-deploy/{id}").HandlerFunc(impl.deploymentWindowRestHandler.CancelScheduledDeployment).Methods("DELETE")
+}
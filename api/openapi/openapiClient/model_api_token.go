@@ -36,6 +36,14 @@ type ApiToken struct {
 	LastUsedByIp *string `json:"lastUsedByIp,omitempty"`
 	// token last updatedAt
 	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Rbac checks the api-token is limited to, all roles of the api-token user apply when empty
+	Scopes *[]ApiTokenScope `json:"scopes,omitempty"`
+	// IPs or CIDRs the api-token can be used from, any IP when empty
+	IpAllowlist *[]string `json:"ipAllowlist,omitempty"`
+	// Notification channel configs notified before the api-token expires
+	ExpiryNotificationConfigIds *[]int32 `json:"expiryNotificationConfigIds,omitempty"`
+	// Time till which the token replaced on rotation is accepted, in milliseconds
+	PreviousTokenExpireAtInMs *int64 `json:"previousTokenExpireAtInMs,omitempty"`
 }
 
 // NewApiToken instantiates a new ApiToken object
@@ -375,6 +383,134 @@ func (o *ApiToken) SetUpdatedAt(v string) {
 	o.UpdatedAt = &v
 }
 
+// GetScopes returns the Scopes field value if set, zero value otherwise.
+func (o *ApiToken) GetScopes() []ApiTokenScope {
+	if o == nil || o.Scopes == nil {
+		var ret []ApiTokenScope
+		return ret
+	}
+	return *o.Scopes
+}
+
+// GetScopesOk returns a tuple with the Scopes field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetScopesOk() (*[]ApiTokenScope, bool) {
+	if o == nil || o.Scopes == nil {
+		return nil, false
+	}
+	return o.Scopes, true
+}
+
+// HasScopes returns a boolean if a field has been set.
+func (o *ApiToken) HasScopes() bool {
+	if o != nil && o.Scopes != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScopes gets a reference to the given []ApiTokenScope and assigns it to the Scopes field.
+func (o *ApiToken) SetScopes(v []ApiTokenScope) {
+	o.Scopes = &v
+}
+
+// GetIpAllowlist returns the IpAllowlist field value if set, zero value otherwise.
+func (o *ApiToken) GetIpAllowlist() []string {
+	if o == nil || o.IpAllowlist == nil {
+		var ret []string
+		return ret
+	}
+	return *o.IpAllowlist
+}
+
+// GetIpAllowlistOk returns a tuple with the IpAllowlist field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetIpAllowlistOk() (*[]string, bool) {
+	if o == nil || o.IpAllowlist == nil {
+		return nil, false
+	}
+	return o.IpAllowlist, true
+}
+
+// HasIpAllowlist returns a boolean if a field has been set.
+func (o *ApiToken) HasIpAllowlist() bool {
+	if o != nil && o.IpAllowlist != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetIpAllowlist gets a reference to the given []string and assigns it to the IpAllowlist field.
+func (o *ApiToken) SetIpAllowlist(v []string) {
+	o.IpAllowlist = &v
+}
+
+// GetExpiryNotificationConfigIds returns the ExpiryNotificationConfigIds field value if set, zero value otherwise.
+func (o *ApiToken) GetExpiryNotificationConfigIds() []int32 {
+	if o == nil || o.ExpiryNotificationConfigIds == nil {
+		var ret []int32
+		return ret
+	}
+	return *o.ExpiryNotificationConfigIds
+}
+
+// GetExpiryNotificationConfigIdsOk returns a tuple with the ExpiryNotificationConfigIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetExpiryNotificationConfigIdsOk() (*[]int32, bool) {
+	if o == nil || o.ExpiryNotificationConfigIds == nil {
+		return nil, false
+	}
+	return o.ExpiryNotificationConfigIds, true
+}
+
+// HasExpiryNotificationConfigIds returns a boolean if a field has been set.
+func (o *ApiToken) HasExpiryNotificationConfigIds() bool {
+	if o != nil && o.ExpiryNotificationConfigIds != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetExpiryNotificationConfigIds gets a reference to the given []int32 and assigns it to the ExpiryNotificationConfigIds field.
+func (o *ApiToken) SetExpiryNotificationConfigIds(v []int32) {
+	o.ExpiryNotificationConfigIds = &v
+}
+
+// GetPreviousTokenExpireAtInMs returns the PreviousTokenExpireAtInMs field value if set, zero value otherwise.
+func (o *ApiToken) GetPreviousTokenExpireAtInMs() int64 {
+	if o == nil || o.PreviousTokenExpireAtInMs == nil {
+		var ret int64
+		return ret
+	}
+	return *o.PreviousTokenExpireAtInMs
+}
+
+// GetPreviousTokenExpireAtInMsOk returns a tuple with the PreviousTokenExpireAtInMs field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetPreviousTokenExpireAtInMsOk() (*int64, bool) {
+	if o == nil || o.PreviousTokenExpireAtInMs == nil {
+		return nil, false
+	}
+	return o.PreviousTokenExpireAtInMs, true
+}
+
+// HasPreviousTokenExpireAtInMs returns a boolean if a field has been set.
+func (o *ApiToken) HasPreviousTokenExpireAtInMs() bool {
+	if o != nil && o.PreviousTokenExpireAtInMs != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPreviousTokenExpireAtInMs gets a reference to the given int64 and assigns it to the PreviousTokenExpireAtInMs field.
+func (o *ApiToken) SetPreviousTokenExpireAtInMs(v int64) {
+	o.PreviousTokenExpireAtInMs = &v
+}
+
 func (o ApiToken) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Id != nil {
@@ -407,6 +543,18 @@ func (o ApiToken) MarshalJSON() ([]byte, error) {
 	if o.UpdatedAt != nil {
 		toSerialize["updatedAt"] = o.UpdatedAt
 	}
+	if o.Scopes != nil {
+		toSerialize["scopes"] = o.Scopes
+	}
+	if o.IpAllowlist != nil {
+		toSerialize["ipAllowlist"] = o.IpAllowlist
+	}
+	if o.ExpiryNotificationConfigIds != nil {
+		toSerialize["expiryNotificationConfigIds"] = o.ExpiryNotificationConfigIds
+	}
+	if o.PreviousTokenExpireAtInMs != nil {
+		toSerialize["previousTokenExpireAtInMs"] = o.PreviousTokenExpireAtInMs
+	}
 	return json.Marshal(toSerialize)
 }
 
@@ -23,6 +23,12 @@ type CreateApiTokenRequest struct {
 	Description *string `json:"description,omitempty,notnull" validate:"required"`
 	// Expiration time of api-token in milliseconds
 	ExpireAtInMs *int64 `json:"expireAtInMs,omitempty"`
+	// Rbac checks the api-token is limited to, all roles of the api-token user apply when empty
+	Scopes *[]ApiTokenScope `json:"scopes,omitempty"`
+	// IPs or CIDRs the api-token can be used from, any IP when empty
+	IpAllowlist *[]string `json:"ipAllowlist,omitempty"`
+	// Notification channel configs notified before the api-token expires
+	ExpiryNotificationConfigIds *[]int32 `json:"expiryNotificationConfigIds,omitempty"`
 }
 
 // NewCreateApiTokenRequest instantiates a new CreateApiTokenRequest object
@@ -138,6 +144,102 @@ func (o *CreateApiTokenRequest) SetExpireAtInMs(v int64) {
 	o.ExpireAtInMs = &v
 }
 
+// GetScopes returns the Scopes field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetScopes() []ApiTokenScope {
+	if o == nil || o.Scopes == nil {
+		var ret []ApiTokenScope
+		return ret
+	}
+	return *o.Scopes
+}
+
+// GetScopesOk returns a tuple with the Scopes field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetScopesOk() (*[]ApiTokenScope, bool) {
+	if o == nil || o.Scopes == nil {
+		return nil, false
+	}
+	return o.Scopes, true
+}
+
+// HasScopes returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasScopes() bool {
+	if o != nil && o.Scopes != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScopes gets a reference to the given []ApiTokenScope and assigns it to the Scopes field.
+func (o *CreateApiTokenRequest) SetScopes(v []ApiTokenScope) {
+	o.Scopes = &v
+}
+
+// GetIpAllowlist returns the IpAllowlist field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetIpAllowlist() []string {
+	if o == nil || o.IpAllowlist == nil {
+		var ret []string
+		return ret
+	}
+	return *o.IpAllowlist
+}
+
+// GetIpAllowlistOk returns a tuple with the IpAllowlist field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetIpAllowlistOk() (*[]string, bool) {
+	if o == nil || o.IpAllowlist == nil {
+		return nil, false
+	}
+	return o.IpAllowlist, true
+}
+
+// HasIpAllowlist returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasIpAllowlist() bool {
+	if o != nil && o.IpAllowlist != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetIpAllowlist gets a reference to the given []string and assigns it to the IpAllowlist field.
+func (o *CreateApiTokenRequest) SetIpAllowlist(v []string) {
+	o.IpAllowlist = &v
+}
+
+// GetExpiryNotificationConfigIds returns the ExpiryNotificationConfigIds field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetExpiryNotificationConfigIds() []int32 {
+	if o == nil || o.ExpiryNotificationConfigIds == nil {
+		var ret []int32
+		return ret
+	}
+	return *o.ExpiryNotificationConfigIds
+}
+
+// GetExpiryNotificationConfigIdsOk returns a tuple with the ExpiryNotificationConfigIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetExpiryNotificationConfigIdsOk() (*[]int32, bool) {
+	if o == nil || o.ExpiryNotificationConfigIds == nil {
+		return nil, false
+	}
+	return o.ExpiryNotificationConfigIds, true
+}
+
+// HasExpiryNotificationConfigIds returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasExpiryNotificationConfigIds() bool {
+	if o != nil && o.ExpiryNotificationConfigIds != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetExpiryNotificationConfigIds gets a reference to the given []int32 and assigns it to the ExpiryNotificationConfigIds field.
+func (o *CreateApiTokenRequest) SetExpiryNotificationConfigIds(v []int32) {
+	o.ExpiryNotificationConfigIds = &v
+}
+
 func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Name != nil {
@@ -149,6 +251,15 @@ func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	if o.ExpireAtInMs != nil {
 		toSerialize["expireAtInMs"] = o.ExpireAtInMs
 	}
+	if o.Scopes != nil {
+		toSerialize["scopes"] = o.Scopes
+	}
+	if o.IpAllowlist != nil {
+		toSerialize["ipAllowlist"] = o.IpAllowlist
+	}
+	if o.ExpiryNotificationConfigIds != nil {
+		toSerialize["expiryNotificationConfigIds"] = o.ExpiryNotificationConfigIds
+	}
 	return json.Marshal(toSerialize)
 }
 
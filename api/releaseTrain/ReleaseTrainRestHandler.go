@@ -0,0 +1,394 @@
+package releaseTrain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/releaseTrain"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ReleaseTrainRestHandler interface {
+	CreateReleaseTrain(w http.ResponseWriter, r *http.Request)
+	UpdateReleaseTrain(w http.ResponseWriter, r *http.Request)
+	DeleteReleaseTrain(w http.ResponseWriter, r *http.Request)
+	GetReleaseTrain(w http.ResponseWriter, r *http.Request)
+	GetAllReleaseTrains(w http.ResponseWriter, r *http.Request)
+	GetEligibility(w http.ResponseWriter, r *http.Request)
+	Promote(w http.ResponseWriter, r *http.Request)
+	BulkPromote(w http.ResponseWriter, r *http.Request)
+	GetPromotionHistory(w http.ResponseWriter, r *http.Request)
+}
+
+type ReleaseTrainRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	validator           *validator.Validate
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac.EnforcerUtil
+	argoUserService     argo.ArgoUserService
+	releaseTrainService releaseTrain.ReleaseTrainService
+}
+
+func NewReleaseTrainRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	argoUserService argo.ArgoUserService, releaseTrainService releaseTrain.ReleaseTrainService) *ReleaseTrainRestHandlerImpl {
+	return &ReleaseTrainRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		validator:           validator,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+		argoUserService:     argoUserService,
+		releaseTrainService: releaseTrainService,
+	}
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) CreateReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	handler.saveReleaseTrain(w, r, false)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) UpdateReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	handler.saveReleaseTrain(w, r, true)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) saveReleaseTrain(w http.ResponseWriter, r *http.Request, isUpdate bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request releaseTrain.ReleaseTrainDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, saveReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, saveReleaseTrain", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, saveReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// release trains define promotion policy across environments, only super admin can manage them
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	var res *releaseTrain.ReleaseTrainDto
+	if isUpdate {
+		res, err = handler.releaseTrainService.UpdateReleaseTrain(&request)
+	} else {
+		res, err = handler.releaseTrainService.CreateReleaseTrain(&request)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, saveReleaseTrain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) DeleteReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	err = handler.releaseTrainService.DeleteReleaseTrain(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteReleaseTrain", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetReleaseTrain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	appId, err := getOptionalIntQueryParam(r, "appId")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if appId > 0 && !handler.checkAppGetAccess(token, appId) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseTrainService.GetReleaseTrain(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetReleaseTrain", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkReleaseTrainAccess(token, appId, res) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetAllReleaseTrains(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := getOptionalIntQueryParam(r, "appId")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if appId > 0 && !handler.checkAppGetAccess(token, appId) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	releaseTrains, err := handler.releaseTrainService.GetAllReleaseTrains()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllReleaseTrains", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res := make([]*releaseTrain.ReleaseTrainDto, 0, len(releaseTrains))
+	for _, releaseTrainDto := range releaseTrains {
+		if handler.checkReleaseTrainAccess(token, appId, releaseTrainDto) {
+			res = append(res, releaseTrainDto)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetEligibility(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	appId, err := strconv.Atoi(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	ciArtifactId, err := getOptionalIntQueryParam(r, "ciArtifactId")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid ciArtifactId", http.StatusBadRequest)
+		return
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseTrainService.GetEligibility(id, appId, ciArtifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEligibility", "err", err, "id", id, "appId", appId, "ciArtifactId", ciArtifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) Promote(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request releaseTrain.PromotionRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, Promote", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, Promote", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, Promote", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkTriggerAccess(r.Header.Get("token"), request.AppId, request.DestinationEnvironmentId) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	ctx, err := handler.getTriggerContext(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res, err := handler.releaseTrainService.Promote(ctx, &request)
+	if err != nil {
+		handler.logger.Errorw("service err, Promote", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) BulkPromote(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request releaseTrain.BulkPromotionRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, BulkPromote", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, BulkPromote", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, BulkPromote", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	// apps of the group the user can not deploy are reported as skipped
+	request.CheckTriggerAuth = func(appId int, envId int) bool {
+		return handler.checkTriggerAccess(token, appId, envId)
+	}
+	ctx, err := handler.getTriggerContext(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res, err := handler.releaseTrainService.BulkPromote(ctx, &request)
+	if err != nil {
+		handler.logger.Errorw("service err, BulkPromote", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) GetPromotionHistory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(mux.Vars(r)["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	ciArtifactId, err := getOptionalIntQueryParam(r, "ciArtifactId")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid ciArtifactId", http.StatusBadRequest)
+		return
+	}
+	offset, err := getOptionalIntQueryParam(r, "offset")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid offset", http.StatusBadRequest)
+		return
+	}
+	size, err := getOptionalIntQueryParam(r, "size")
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid size", http.StatusBadRequest)
+		return
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseTrainService.GetPromotionHistory(appId, ciArtifactId, offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPromotionHistory", "err", err, "appId", appId, "ciArtifactId", ciArtifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) checkAppGetAccess(token string, appId int) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	return handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object)
+}
+
+// checkReleaseTrainAccess a release train exposes the policy of every stage, so it is visible only if all of its
+// environments are, for the given app like promotion does or as environments when no app is given
+func (handler *ReleaseTrainRestHandlerImpl) checkReleaseTrainAccess(token string, appId int, releaseTrainDto *releaseTrain.ReleaseTrainDto) bool {
+	for _, stage := range releaseTrainDto.Stages {
+		var ok bool
+		if appId > 0 {
+			object := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, stage.EnvironmentId)
+			ok = handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, object)
+		} else {
+			ok = handler.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionGet, stage.EnvironmentIdentifier)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) checkTriggerAccess(token string, appId int, envId int) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, object); !ok {
+		return false
+	}
+	object = handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	return handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object)
+}
+
+func (handler *ReleaseTrainRestHandlerImpl) getTriggerContext(r *http.Request) (context.Context, error) {
+	acdToken, err := handler.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		handler.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	return context.WithValue(r.Context(), "token", acdToken), nil
+}
+
+func getOptionalIntQueryParam(r *http.Request, param string) (int, error) {
+	value := r.URL.Query().Get(param)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,31 @@
+package releaseTrain
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ReleaseTrainRouter interface {
+	InitReleaseTrainRouter(router *mux.Router)
+}
+
+type ReleaseTrainRouterImpl struct {
+	releaseTrainRestHandler ReleaseTrainRestHandler
+}
+
+func NewReleaseTrainRouterImpl(releaseTrainRestHandler ReleaseTrainRestHandler) *ReleaseTrainRouterImpl {
+	return &ReleaseTrainRouterImpl{releaseTrainRestHandler: releaseTrainRestHandler}
+}
+
+func (impl ReleaseTrainRouterImpl) InitReleaseTrainRouter(router *mux.Router) {
+	router.Path("").HandlerFunc(impl.releaseTrainRestHandler.CreateReleaseTrain).Methods("POST")
+	router.Path("").HandlerFunc(impl.releaseTrainRestHandler.UpdateReleaseTrain).Methods("PUT")
+	router.Path("").HandlerFunc(impl.releaseTrainRestHandler.GetAllReleaseTrains).Methods("GET")
+
+	router.Path("/promote").HandlerFunc(impl.releaseTrainRestHandler.Promote).Methods("POST")
+	router.Path("/promote/bulk").HandlerFunc(impl.releaseTrainRestHandler.BulkPromote).Methods("POST")
+	router.Path("/promotion/history/{appId}").HandlerFunc(impl.releaseTrainRestHandler.GetPromotionHistory).Methods("GET")
+
+	router.Path("/{id}").HandlerFunc(impl.releaseTrainRestHandler.GetReleaseTrain).Methods("GET")
+	router.Path("/{id}").HandlerFunc(impl.releaseTrainRestHandler.DeleteReleaseTrain).Methods("DELETE")
+	router.Path("/{id}/eligibility").HandlerFunc(impl.releaseTrainRestHandler.GetEligibility).Queries("appId", "{appId}").Methods("GET")
+}
@@ -0,0 +1,20 @@
+package releaseTrain
+
+import (
+	"github.com/devtron-labs/devtron/pkg/releaseTrain"
+	"github.com/google/wire"
+)
+
+var ReleaseTrainWireSet = wire.NewSet(
+	releaseTrain.NewReleaseTrainRepositoryImpl,
+	wire.Bind(new(releaseTrain.ReleaseTrainRepository), new(*releaseTrain.ReleaseTrainRepositoryImpl)),
+	releaseTrain.NewArtifactPromotionRepositoryImpl,
+	wire.Bind(new(releaseTrain.ArtifactPromotionRepository), new(*releaseTrain.ArtifactPromotionRepositoryImpl)),
+
+	releaseTrain.NewReleaseTrainServiceImpl,
+	wire.Bind(new(releaseTrain.ReleaseTrainService), new(*releaseTrain.ReleaseTrainServiceImpl)),
+	NewReleaseTrainRestHandlerImpl,
+	wire.Bind(new(ReleaseTrainRestHandler), new(*ReleaseTrainRestHandlerImpl)),
+	NewReleaseTrainRouterImpl,
+	wire.Bind(new(ReleaseTrainRouter), new(*ReleaseTrainRouterImpl)),
+)
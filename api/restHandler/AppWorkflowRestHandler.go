@@ -24,6 +24,7 @@ import (
 	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
 	"github.com/devtron-labs/devtron/internal/util"
 	appGroup2 "github.com/devtron-labs/devtron/pkg/appGroup"
+	"github.com/devtron-labs/devtron/pkg/appSync"
 	"github.com/devtron-labs/devtron/pkg/appWorkflow"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
@@ -49,28 +50,30 @@ type AppWorkflowRestHandler interface {
 }
 
 type AppWorkflowRestHandlerImpl struct {
-	Logger             *zap.SugaredLogger
-	appWorkflowService appWorkflow.AppWorkflowService
-	userAuthService    user.UserService
-	teamService        team.TeamService
-	enforcer           casbin.Enforcer
-	pipelineBuilder    pipeline.PipelineBuilder
-	appRepository      app.AppRepository
-	enforcerUtil       rbac.EnforcerUtil
+	Logger               *zap.SugaredLogger
+	appWorkflowService   appWorkflow.AppWorkflowService
+	userAuthService      user.UserService
+	teamService          team.TeamService
+	enforcer             casbin.Enforcer
+	pipelineBuilder      pipeline.PipelineBuilder
+	appRepository        app.AppRepository
+	enforcerUtil         rbac.EnforcerUtil
+	gitManagedAppService appSync.GitManagedAppService
 }
 
 func NewAppWorkflowRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService user.UserService, appWorkflowService appWorkflow.AppWorkflowService,
 	teamService team.TeamService, enforcer casbin.Enforcer, pipelineBuilder pipeline.PipelineBuilder,
-	appRepository app.AppRepository, enforcerUtil rbac.EnforcerUtil) *AppWorkflowRestHandlerImpl {
+	appRepository app.AppRepository, enforcerUtil rbac.EnforcerUtil, gitManagedAppService appSync.GitManagedAppService) *AppWorkflowRestHandlerImpl {
 	return &AppWorkflowRestHandlerImpl{
-		Logger:             Logger,
-		appWorkflowService: appWorkflowService,
-		userAuthService:    userAuthService,
-		teamService:        teamService,
-		enforcer:           enforcer,
-		pipelineBuilder:    pipelineBuilder,
-		appRepository:      appRepository,
-		enforcerUtil:       enforcerUtil,
+		Logger:               Logger,
+		appWorkflowService:   appWorkflowService,
+		userAuthService:      userAuthService,
+		teamService:          teamService,
+		enforcer:             enforcer,
+		pipelineBuilder:      pipelineBuilder,
+		appRepository:        appRepository,
+		enforcerUtil:         enforcerUtil,
+		gitManagedAppService: gitManagedAppService,
 	}
 }
 
@@ -98,6 +101,11 @@ func (handler AppWorkflowRestHandlerImpl) CreateAppWorkflow(w http.ResponseWrite
 		return
 	}
 	//rback block ends here
+	err = handler.gitManagedAppService.CheckAppEditable(request.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	request.UserId = userId
 
 	res, err := handler.appWorkflowService.CreateAppWorkflow(request)
@@ -138,6 +146,11 @@ func (handler AppWorkflowRestHandlerImpl) DeleteAppWorkflow(w http.ResponseWrite
 		return
 	}
 	//rback block ends here
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	err = handler.appWorkflowService.DeleteAppWorkflow(appWorkflowId, userId)
 	if err != nil {
@@ -0,0 +1,231 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type CanaryAnalysisRestHandler interface {
+	SaveCanaryAnalysisConfig(w http.ResponseWriter, r *http.Request)
+	GetCanaryAnalysisConfig(w http.ResponseWriter, r *http.Request)
+	DeleteCanaryAnalysisConfig(w http.ResponseWriter, r *http.Request)
+	GetCanaryAnalysisRuns(w http.ResponseWriter, r *http.Request)
+	GetCanaryAnalysisRun(w http.ResponseWriter, r *http.Request)
+}
+
+type CanaryAnalysisRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	userAuthService       user.UserService
+	validator             *validator.Validate
+	enforcer              casbin.Enforcer
+	enforcerUtil          rbac.EnforcerUtil
+	pipelineRepository    pipelineConfig.PipelineRepository
+	canaryAnalysisService pipeline.CanaryAnalysisService
+}
+
+func NewCanaryAnalysisRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	canaryAnalysisService pipeline.CanaryAnalysisService) *CanaryAnalysisRestHandlerImpl {
+	return &CanaryAnalysisRestHandlerImpl{
+		logger:                logger,
+		userAuthService:       userAuthService,
+		validator:             validator,
+		enforcer:              enforcer,
+		enforcerUtil:          enforcerUtil,
+		pipelineRepository:    pipelineRepository,
+		canaryAnalysisService: canaryAnalysisService,
+	}
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) SaveCanaryAnalysisConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.CanaryAnalysisConfigDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveCanaryAnalysisConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, SaveCanaryAnalysisConfig", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveCanaryAnalysisConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) {
+		return
+	}
+	res, err := handler.canaryAnalysisService.SaveConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveCanaryAnalysisConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) GetCanaryAnalysisConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.canaryAnalysisService.GetConfig(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCanaryAnalysisConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) DeleteCanaryAnalysisConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) {
+		return
+	}
+	err = handler.canaryAnalysisService.DeleteConfig(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteCanaryAnalysisConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) GetCanaryAnalysisRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	limit := 0
+	if limitParam := r.URL.Query().Get("size"); len(limitParam) > 0 {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.canaryAnalysisService.GetRuns(pipelineId, limit)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCanaryAnalysisRuns", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) GetCanaryAnalysisRun(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	runId, err := strconv.Atoi(mux.Vars(r)["runId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.canaryAnalysisService.GetRunById(runId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCanaryAnalysisRun", "err", err, "runId", runId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, res.PipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) getPipeline(w http.ResponseWriter, pipelineId int) (*pipelineConfig.Pipeline, bool) {
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching cd pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	return cdPipeline, true
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) checkAppRbac(w http.ResponseWriter, token string, appId int, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) checkEnvRbac(w http.ResponseWriter, token string, cdPipeline *pipelineConfig.Pipeline, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, cdPipeline.Id)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
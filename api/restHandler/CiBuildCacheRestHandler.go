@@ -0,0 +1,198 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type CiBuildCacheRestHandler interface {
+	SaveBuildCacheConfig(w http.ResponseWriter, r *http.Request)
+	GetBuildCacheConfig(w http.ResponseWriter, r *http.Request)
+	DeleteBuildCacheConfig(w http.ResponseWriter, r *http.Request)
+	InvalidateBuildCache(w http.ResponseWriter, r *http.Request)
+	GetBuildCacheEntries(w http.ResponseWriter, r *http.Request)
+}
+
+type CiBuildCacheRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	userAuthService      user.UserService
+	validator            *validator.Validate
+	enforcer             casbin.Enforcer
+	enforcerUtil         rbac.EnforcerUtil
+	ciPipelineRepository pipelineConfig.CiPipelineRepository
+	ciBuildCacheService  pipeline.CiBuildCacheService
+}
+
+func NewCiBuildCacheRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciBuildCacheService pipeline.CiBuildCacheService) *CiBuildCacheRestHandlerImpl {
+	return &CiBuildCacheRestHandlerImpl{
+		logger:               logger,
+		userAuthService:      userAuthService,
+		validator:            validator,
+		enforcer:             enforcer,
+		enforcerUtil:         enforcerUtil,
+		ciPipelineRepository: ciPipelineRepository,
+		ciBuildCacheService:  ciBuildCacheService,
+	}
+}
+
+func (handler *CiBuildCacheRestHandlerImpl) SaveBuildCacheConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.CiBuildCacheConfigDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveBuildCacheConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.CiPipelineId = pipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, SaveBuildCacheConfig", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveBuildCacheConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionUpdate) {
+		return
+	}
+	res, err := handler.ciBuildCacheService.SaveConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveBuildCacheConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiBuildCacheRestHandlerImpl) GetBuildCacheConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.ciBuildCacheService.GetConfig(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBuildCacheConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiBuildCacheRestHandlerImpl) DeleteBuildCacheConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionUpdate) {
+		return
+	}
+	err = handler.ciBuildCacheService.DeleteConfig(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteBuildCacheConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler *CiBuildCacheRestHandlerImpl) InvalidateBuildCache(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// same access as a build triggered with cache invalidation
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionTrigger) {
+		return
+	}
+	res, err := handler.ciBuildCacheService.InvalidateCache(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, InvalidateBuildCache", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiBuildCacheRestHandlerImpl) GetBuildCacheEntries(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.ciBuildCacheService.GetCacheEntries(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBuildCacheEntries", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiBuildCacheRestHandlerImpl) checkRbac(w http.ResponseWriter, token string, ciPipelineId int, action string) bool {
+	ciPipeline, err := handler.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(ciPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+const DEFAULT_SCHEDULE_RUNS_LIMIT = 20
+
+type CiPipelineScheduleRestHandler interface {
+	SaveSchedule(w http.ResponseWriter, r *http.Request)
+	GetSchedule(w http.ResponseWriter, r *http.Request)
+	DeleteSchedule(w http.ResponseWriter, r *http.Request)
+	PauseSchedule(w http.ResponseWriter, r *http.Request)
+	ResumeSchedule(w http.ResponseWriter, r *http.Request)
+	GetScheduleRuns(w http.ResponseWriter, r *http.Request)
+}
+
+type CiPipelineScheduleRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	userAuthService           user.UserService
+	validator                 *validator.Validate
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+	ciPipelineRepository      pipelineConfig.CiPipelineRepository
+	ciPipelineScheduleService pipeline.CiPipelineScheduleService
+}
+
+func NewCiPipelineScheduleRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciPipelineScheduleService pipeline.CiPipelineScheduleService) *CiPipelineScheduleRestHandlerImpl {
+	return &CiPipelineScheduleRestHandlerImpl{
+		logger:                    logger,
+		userAuthService:           userAuthService,
+		validator:                 validator,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+		ciPipelineRepository:      ciPipelineRepository,
+		ciPipelineScheduleService: ciPipelineScheduleService,
+	}
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) SaveSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.CiPipelineScheduleDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.CiPipelineId = pipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, SaveSchedule", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// scheduled builds run on behalf of the user who configured the schedule, so trigger access is needed as well
+	token := r.Header.Get("token")
+	if !handler.checkRbac(w, token, pipelineId, casbin.ActionUpdate) || !handler.checkRbac(w, token, pipelineId, casbin.ActionTrigger) {
+		return
+	}
+	res, err := handler.ciPipelineScheduleService.SaveSchedule(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.ciPipelineScheduleService.GetSchedule(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSchedule", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionUpdate) {
+		return
+	}
+	err = handler.ciPipelineScheduleService.DeleteSchedule(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSchedule", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) PauseSchedule(w http.ResponseWriter, r *http.Request) {
+	handler.updatePauseState(w, r, true)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	handler.updatePauseState(w, r, false)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) updatePauseState(w http.ResponseWriter, r *http.Request, paused bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionTrigger) {
+		return
+	}
+	res, err := handler.ciPipelineScheduleService.UpdatePauseState(pipelineId, paused, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdatePauseState", "err", err, "pipelineId", pipelineId, "paused", paused)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) GetScheduleRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	limit := DEFAULT_SCHEDULE_RUNS_LIMIT
+	if limitParam := r.URL.Query().Get("size"); len(limitParam) > 0 {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 {
+			common.WriteJsonResp(w, fmt.Errorf("invalid size"), nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if !handler.checkRbac(w, r.Header.Get("token"), pipelineId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.ciPipelineScheduleService.GetScheduleRuns(pipelineId, limit)
+	if err != nil {
+		handler.logger.Errorw("service err, GetScheduleRuns", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *CiPipelineScheduleRestHandlerImpl) checkRbac(w http.ResponseWriter, token string, ciPipelineId int, action string) bool {
+	ciPipeline, err := handler.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(ciPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/appSync"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/team"
@@ -60,34 +62,45 @@ type ConfigMapRestHandler interface {
 }
 
 type ConfigMapRestHandlerImpl struct {
-	pipelineBuilder    pipeline.PipelineBuilder
-	Logger             *zap.SugaredLogger
-	chartService       chart.ChartService
-	userAuthService    user.UserService
-	teamService        team.TeamService
-	enforcer           casbin.Enforcer
-	pipelineRepository pipelineConfig.PipelineRepository
-	enforcerUtil       rbac.EnforcerUtil
-	configMapService   pipeline.ConfigMapService
+	pipelineBuilder      pipeline.PipelineBuilder
+	Logger               *zap.SugaredLogger
+	chartService         chart.ChartService
+	userAuthService      user.UserService
+	teamService          team.TeamService
+	enforcer             casbin.Enforcer
+	pipelineRepository   pipelineConfig.PipelineRepository
+	enforcerUtil         rbac.EnforcerUtil
+	configMapService     pipeline.ConfigMapService
+	gitManagedAppService appSync.GitManagedAppService
 }
 
 func NewConfigMapRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger *zap.SugaredLogger,
 	chartService chart.ChartService, userAuthService user.UserService, teamService team.TeamService,
 	enforcer casbin.Enforcer, pipelineRepository pipelineConfig.PipelineRepository,
-	enforcerUtil rbac.EnforcerUtil, configMapService pipeline.ConfigMapService) *ConfigMapRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, configMapService pipeline.ConfigMapService,
+	gitManagedAppService appSync.GitManagedAppService) *ConfigMapRestHandlerImpl {
 	return &ConfigMapRestHandlerImpl{
-		pipelineBuilder:    pipelineBuilder,
-		Logger:             Logger,
-		chartService:       chartService,
-		userAuthService:    userAuthService,
-		teamService:        teamService,
-		enforcer:           enforcer,
-		pipelineRepository: pipelineRepository,
-		enforcerUtil:       enforcerUtil,
-		configMapService:   configMapService,
+		pipelineBuilder:      pipelineBuilder,
+		Logger:               Logger,
+		chartService:         chartService,
+		userAuthService:      userAuthService,
+		teamService:          teamService,
+		enforcer:             enforcer,
+		pipelineRepository:   pipelineRepository,
+		enforcerUtil:         enforcerUtil,
+		configMapService:     configMapService,
+		gitManagedAppService: gitManagedAppService,
 	}
 }
 
+const (
+	CONFIG_MAP_CHANGE_NAME = "configMap"
+	SECRET_CHANGE_NAME     = "secret"
+)
+
+// SECRET_MASKED_FIELDS are the fields of a secret holding its values, which are kept out of the audit log
+var SECRET_MASKED_FIELDS = []string{"data", "defaultData"}
+
 func (handler ConfigMapRestHandlerImpl) CMGlobalAddUpdate(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
@@ -115,12 +128,24 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalAddUpdate(w http.ResponseWriter,
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// current configs are kept for the audit log diff
+	currentConfigs, err := handler.configMapService.CMGlobalFetch(configMapRequest.AppId)
+	if err != nil {
+		handler.Logger.Errorw("error in fetching current configs, CMGlobalAddUpdate", "err", err, "appId", configMapRequest.AppId, "envId", configMapRequest.EnvironmentId)
+	}
 	res, err := handler.configMapService.CMGlobalAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMGlobalAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	recordConfigChanges(r, CONFIG_MAP_CHANGE_NAME, currentConfigs, &configMapRequest)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -155,12 +180,24 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentAddUpdate(w http.ResponseWr
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// current configs are kept for the audit log diff
+	currentConfigs, err := handler.configMapService.CMEnvironmentFetch(configMapRequest.AppId, configMapRequest.EnvironmentId)
+	if err != nil {
+		handler.Logger.Errorw("error in fetching current configs, CMEnvironmentAddUpdate", "err", err, "appId", configMapRequest.AppId, "envId", configMapRequest.EnvironmentId)
+	}
 	res, err := handler.configMapService.CMEnvironmentAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMEnvironmentAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	recordConfigChanges(r, CONFIG_MAP_CHANGE_NAME, currentConfigs, &configMapRequest)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -261,12 +298,24 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalAddUpdate(w http.ResponseWriter,
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// current configs are kept for the audit log diff
+	currentConfigs, err := handler.fetchCurrentSecrets(&configMapRequest)
+	if err != nil {
+		handler.Logger.Errorw("error in fetching current configs, CSGlobalAddUpdate", "err", err, "appId", configMapRequest.AppId, "envId", configMapRequest.EnvironmentId)
+	}
 	res, err := handler.configMapService.CSGlobalAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSGlobalAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	recordConfigChanges(r, SECRET_CHANGE_NAME, currentConfigs, &configMapRequest, SECRET_MASKED_FIELDS...)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -302,12 +351,24 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentAddUpdate(w http.ResponseWr
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// current configs are kept for the audit log diff
+	currentConfigs, err := handler.fetchCurrentSecrets(&configMapRequest)
+	if err != nil {
+		handler.Logger.Errorw("error in fetching current configs, CSEnvironmentAddUpdate", "err", err, "appId", configMapRequest.AppId, "envId", configMapRequest.EnvironmentId)
+	}
 	res, err := handler.configMapService.CSEnvironmentAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSEnvironmentAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	recordConfigChanges(r, SECRET_CHANGE_NAME, currentConfigs, &configMapRequest, SECRET_MASKED_FIELDS...)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -412,6 +473,12 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalDelete(w http.ResponseWriter, r
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
 	res, err := handler.configMapService.CMGlobalDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMGlobalDelete", "err", err, "appId", appId, "id", id, "name", name)
@@ -463,6 +530,12 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentDelete(w http.ResponseWrite
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
 	res, err := handler.configMapService.CMEnvironmentDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMEnvironmentDelete", "err", err, "appId", appId, "envId", envId, "id", id)
@@ -503,6 +576,12 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalDelete(w http.ResponseWriter, r
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
 	res, err := handler.configMapService.CSGlobalDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSGlobalDelete", "err", err, "appId", appId, "id", id, "name", name)
@@ -554,6 +633,12 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentDelete(w http.ResponseWrite
 	}
 	//RBAC END
 
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
 	res, err := handler.configMapService.CSEnvironmentDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSEnvironmentDelete", "err", err, "appId", appId, "envId", envId, "id", id)
@@ -813,3 +898,46 @@ func (handler ConfigMapRestHandlerImpl) GetEnvironmentsForJob(w http.ResponseWri
 
 	common.WriteJsonResp(w, err, resp, http.StatusOK)
 }
+
+// fetchCurrentSecrets returns the secrets of the request as they are before the update, with their values unlike the
+// fetch apis which blank them
+func (handler ConfigMapRestHandlerImpl) fetchCurrentSecrets(configMapRequest *pipeline.ConfigDataRequest) (*pipeline.ConfigDataRequest, error) {
+	currentSecrets := &pipeline.ConfigDataRequest{}
+	for _, configData := range configMapRequest.ConfigData {
+		var secret *pipeline.ConfigDataRequest
+		var err error
+		if configMapRequest.EnvironmentId > 0 {
+			secret, err = handler.configMapService.CSEnvironmentFetchForEdit(configData.Name, configMapRequest.Id, configMapRequest.AppId, configMapRequest.EnvironmentId)
+		} else if configMapRequest.Id > 0 {
+			secret, err = handler.configMapService.CSGlobalFetchForEdit(configData.Name, configMapRequest.Id)
+		} else {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		currentSecrets.ConfigData = append(currentSecrets.ConfigData, secret.ConfigData...)
+	}
+	return currentSecrets, nil
+}
+
+// recordConfigChanges adds the diff of each config map or secret of the request to the audit log, env level ones are
+// named after the environment
+func recordConfigChanges(r *http.Request, name string, currentConfigs *pipeline.ConfigDataRequest, configMapRequest *pipeline.ConfigDataRequest, maskedFields ...string) {
+	if configMapRequest.EnvironmentId > 0 {
+		name = fmt.Sprintf("environment.%d.%s", configMapRequest.EnvironmentId, name)
+	}
+	currentConfigsByName := make(map[string]*pipeline.ConfigData)
+	if currentConfigs != nil {
+		for _, configData := range currentConfigs.ConfigData {
+			currentConfigsByName[configData.Name] = configData
+		}
+	}
+	for _, configData := range configMapRequest.ConfigData {
+		var before interface{}
+		if currentConfig, ok := currentConfigsByName[configData.Name]; ok {
+			before = currentConfig
+		}
+		auditLog.RecordMaskedChange(r, fmt.Sprintf("%s.%s", name, configData.Name), before, configData, maskedFields...)
+	}
+}
@@ -0,0 +1,331 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type DeploymentApprovalRestHandler interface {
+	SaveApprovalConfig(w http.ResponseWriter, r *http.Request)
+	GetApprovalConfig(w http.ResponseWriter, r *http.Request)
+	DeleteApprovalConfig(w http.ResponseWriter, r *http.Request)
+	RaiseApprovalRequest(w http.ResponseWriter, r *http.Request)
+	GetApprovalRequests(w http.ResponseWriter, r *http.Request)
+	ActOnApprovalRequest(w http.ResponseWriter, r *http.Request)
+	CancelApprovalRequest(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentApprovalRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	userAuthService           user.UserService
+	validator                 *validator.Validate
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+	pipelineRepository        pipelineConfig.PipelineRepository
+	deploymentApprovalService pipeline.DeploymentApprovalService
+}
+
+func NewDeploymentApprovalRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	deploymentApprovalService pipeline.DeploymentApprovalService) *DeploymentApprovalRestHandlerImpl {
+	return &DeploymentApprovalRestHandlerImpl{
+		logger:                    logger,
+		userAuthService:           userAuthService,
+		validator:                 validator,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+		pipelineRepository:        pipelineRepository,
+		deploymentApprovalService: deploymentApprovalService,
+	}
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) SaveApprovalConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.DeploymentApprovalConfigDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveApprovalConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, SaveApprovalConfig", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveApprovalConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) {
+		return
+	}
+	res, err := handler.deploymentApprovalService.SaveApprovalConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveApprovalConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) GetApprovalConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.deploymentApprovalService.GetApprovalConfig(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetApprovalConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) DeleteApprovalConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) {
+		return
+	}
+	err = handler.deploymentApprovalService.DeleteApprovalConfig(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteApprovalConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) RaiseApprovalRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.DeploymentApprovalRequestDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, RaiseApprovalRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, RaiseApprovalRequest", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, RaiseApprovalRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	// only users who could deploy the artifact once approved can ask for the approval
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionTrigger) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionTrigger) {
+		return
+	}
+	res, err := handler.deploymentApprovalService.RaiseApprovalRequest(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, RaiseApprovalRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) GetApprovalRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	limit := pipeline.DEFAULT_APPROVAL_REQUESTS_LIMIT
+	if limitParam := r.URL.Query().Get("size"); len(limitParam) > 0 {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 {
+			common.WriteJsonResp(w, fmt.Errorf("invalid size"), nil, http.StatusBadRequest)
+			return
+		}
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.deploymentApprovalService.GetApprovalRequests(pipelineId, limit)
+	if err != nil {
+		handler.logger.Errorw("service err, GetApprovalRequests", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) ActOnApprovalRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request pipelineBean.DeploymentApprovalActionDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, ActOnApprovalRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, ActOnApprovalRequest", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, ActOnApprovalRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	approvalRequest, err := handler.deploymentApprovalService.GetApprovalRequestById(request.ApprovalRequestId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetApprovalRequestById", "err", err, "approvalRequestId", request.ApprovalRequestId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, approvalRequest.PipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, cdPipeline.Id)
+	request.HasTriggerAccess = handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)) &&
+		handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object)
+	res, err := handler.deploymentApprovalService.ActOnApprovalRequest(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, ActOnApprovalRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) CancelApprovalRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	approvalRequestId, err := strconv.Atoi(mux.Vars(r)["approvalRequestId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	approvalRequest, err := handler.deploymentApprovalService.GetApprovalRequestById(approvalRequestId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetApprovalRequestById", "err", err, "approvalRequestId", approvalRequestId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, approvalRequest.PipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	err = handler.deploymentApprovalService.CancelApprovalRequest(approvalRequestId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelApprovalRequest", "err", err, "approvalRequestId", approvalRequestId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, approvalRequestId, http.StatusOK)
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) getPipeline(w http.ResponseWriter, pipelineId int) (*pipelineConfig.Pipeline, bool) {
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching cd pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	return cdPipeline, true
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) checkAppRbac(w http.ResponseWriter, token string, appId int, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *DeploymentApprovalRestHandlerImpl) checkEnvRbac(w http.ResponseWriter, token string, cdPipeline *pipelineConfig.Pipeline, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, cdPipeline.Id)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
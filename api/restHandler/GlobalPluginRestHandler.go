@@ -1,15 +1,19 @@
 package restHandler
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/plugin"
 	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"github.com/devtron-labs/devtron/util/rbac"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 )
@@ -18,16 +22,26 @@ type GlobalPluginRestHandler interface {
 	GetAllGlobalVariables(w http.ResponseWriter, r *http.Request)
 	ListAllPlugins(w http.ResponseWriter, r *http.Request)
 	GetPluginDetailById(w http.ResponseWriter, r *http.Request)
+
+	CreatePlugin(w http.ResponseWriter, r *http.Request)
+	ImportPlugin(w http.ResponseWriter, r *http.Request)
+	ExportPlugin(w http.ResponseWriter, r *http.Request)
+	UpdatePluginMetadata(w http.ResponseWriter, r *http.Request)
+	DeletePlugin(w http.ResponseWriter, r *http.Request)
+	GetPluginVersions(w http.ResponseWriter, r *http.Request)
 }
 
 func NewGlobalPluginRestHandler(logger *zap.SugaredLogger, globalPluginService plugin.GlobalPluginService,
-	enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, pipelineBuilder pipeline.PipelineBuilder) *GlobalPluginRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, pipelineBuilder pipeline.PipelineBuilder,
+	userAuthService user.UserService, validator *validator.Validate) *GlobalPluginRestHandlerImpl {
 	return &GlobalPluginRestHandlerImpl{
 		logger:              logger,
 		globalPluginService: globalPluginService,
 		enforcerUtil:        enforcerUtil,
 		enforcer:            enforcer,
 		pipelineBuilder:     pipelineBuilder,
+		userAuthService:     userAuthService,
+		validator:           validator,
 	}
 }
 
@@ -37,6 +51,8 @@ type GlobalPluginRestHandlerImpl struct {
 	enforcerUtil        rbac.EnforcerUtil
 	enforcer            casbin.Enforcer
 	pipelineBuilder     pipeline.PipelineBuilder
+	userAuthService     user.UserService
+	validator           *validator.Validate
 }
 
 func (handler *GlobalPluginRestHandlerImpl) GetAllGlobalVariables(w http.ResponseWriter, r *http.Request) {
@@ -150,3 +166,181 @@ func (handler *GlobalPluginRestHandlerImpl) GetPluginDetailById(w http.ResponseW
 	}
 	common.WriteJsonResp(w, err, pluginDetail, http.StatusOK)
 }
+
+// checkSuperAdmin allows authoring plugins only to super admins as published plugins are usable by every pipeline
+func (handler *GlobalPluginRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	isSuperAdmin, err := handler.userAuthService.IsSuperAdmin(int(userId))
+	if !isSuperAdmin || err != nil {
+		if err != nil {
+			handler.logger.Errorw("request err, checkSuperAdmin", "err", err, "userId", userId)
+		}
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (handler *GlobalPluginRestHandlerImpl) createPlugin(w http.ResponseWriter, definition *plugin.PluginDefinitionDto, userId int32) {
+	err := handler.validator.Struct(definition)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreatePlugin", "err", err, "payload", definition)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.globalPluginService.CreatePlugin(definition, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CreatePlugin", "err", err, "pluginIdentifier", definition.PluginIdentifier, "pluginVersion", definition.PluginVersion)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *GlobalPluginRestHandlerImpl) CreatePlugin(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	var definition plugin.PluginDefinitionDto
+	err := json.NewDecoder(r.Body).Decode(&definition)
+	if err != nil {
+		handler.logger.Errorw("request err, CreatePlugin", "err", err, "payload", definition)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	definition.Id = 0
+	handler.createPlugin(w, &definition, userId)
+}
+
+func (handler *GlobalPluginRestHandlerImpl) ImportPlugin(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		handler.logger.Errorw("request err, ImportPlugin", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	definition, err := handler.globalPluginService.ParsePluginDefinition(data)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.createPlugin(w, definition, userId)
+}
+
+func (handler *GlobalPluginRestHandlerImpl) ExportPlugin(w http.ResponseWriter, r *http.Request) {
+	_, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	pluginId, err := strconv.Atoi(mux.Vars(r)["pluginId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	data, err := handler.globalPluginService.ExportPlugin(pluginId)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportPlugin", "err", err, "pluginId", pluginId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(data)
+	if err != nil {
+		handler.logger.Errorw("error in writing exported plugin", "err", err, "pluginId", pluginId)
+	}
+}
+
+func (handler *GlobalPluginRestHandlerImpl) UpdatePluginMetadata(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	pluginId, err := strconv.Atoi(mux.Vars(r)["pluginId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request plugin.PluginMetadataUpdateDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, UpdatePluginMetadata", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, UpdatePluginMetadata", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.globalPluginService.UpdatePluginMetadata(pluginId, &request, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdatePluginMetadata", "err", err, "pluginId", pluginId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *GlobalPluginRestHandlerImpl) DeletePlugin(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	pluginId, err := strconv.Atoi(mux.Vars(r)["pluginId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.globalPluginService.DeletePlugin(pluginId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePlugin", "err", err, "pluginId", pluginId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pluginId, http.StatusOK)
+}
+
+func (handler *GlobalPluginRestHandlerImpl) GetPluginVersions(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	appIdQueryParam := r.URL.Query().Get("appId")
+	appId, err := strconv.Atoi(appIdQueryParam)
+	if appIdQueryParam == "" || err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	app, err := handler.pipelineBuilder.GetApp(appId)
+	if err != nil {
+		handler.logger.Infow("service error, GetPluginVersions", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//same rbac as plugin list, versions are needed by anyone who can configure pipeline steps
+	resourceName := handler.enforcerUtil.GetAppRBACName(app.AppName)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	pluginId, err := strconv.Atoi(mux.Vars(r)["pluginId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	versions, err := handler.globalPluginService.GetPluginVersions(pluginId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPluginVersions", "err", err, "pluginId", pluginId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, versions, http.StatusOK)
+}
@@ -0,0 +1,353 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ImageSigningRestHandler interface {
+	SaveSigningKey(w http.ResponseWriter, r *http.Request)
+	GetSigningKeys(w http.ResponseWriter, r *http.Request)
+	DeleteSigningKey(w http.ResponseWriter, r *http.Request)
+	SaveCiSigningConfig(w http.ResponseWriter, r *http.Request)
+	GetCiSigningConfig(w http.ResponseWriter, r *http.Request)
+	DeleteCiSigningConfig(w http.ResponseWriter, r *http.Request)
+	SavePolicy(w http.ResponseWriter, r *http.Request)
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	GetImageSignatures(w http.ResponseWriter, r *http.Request)
+}
+
+type ImageSigningRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	userService          user.UserService
+	validator            *validator.Validate
+	enforcer             casbin.Enforcer
+	enforcerUtil         rbac.EnforcerUtil
+	ciPipelineRepository pipelineConfig.CiPipelineRepository
+	ciArtifactRepository repository.CiArtifactRepository
+	imageSigningService  pipeline.ImageSigningService
+}
+
+func NewImageSigningRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, ciArtifactRepository repository.CiArtifactRepository,
+	imageSigningService pipeline.ImageSigningService) *ImageSigningRestHandlerImpl {
+	return &ImageSigningRestHandlerImpl{
+		logger:               logger,
+		userService:          userService,
+		validator:            validator,
+		enforcer:             enforcer,
+		enforcerUtil:         enforcerUtil,
+		ciPipelineRepository: ciPipelineRepository,
+		ciArtifactRepository: ciArtifactRepository,
+		imageSigningService:  imageSigningService,
+	}
+}
+
+func (handler *ImageSigningRestHandlerImpl) SaveSigningKey(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	var request pipelineBean.ImageSigningKeyDto
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSigningKey", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSigningKey", "err", err, "name", request.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.imageSigningService.SaveSigningKey(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSigningKey", "err", err, "name", request.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetSigningKeys(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	res, err := handler.imageSigningService.GetSigningKeys()
+	if err != nil {
+		handler.logger.Errorw("service err, GetSigningKeys", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) DeleteSigningKey(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.imageSigningService.DeleteSigningKey(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSigningKey", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) SaveCiSigningConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciPipelineId, err := strconv.Atoi(mux.Vars(r)["ciPipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.ImageSigningConfigDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveCiSigningConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.CiPipelineId = ciPipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, SaveCiSigningConfig", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveCiSigningConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkCiPipelineRbac(w, r.Header.Get("token"), ciPipelineId, casbin.ActionUpdate) {
+		return
+	}
+	res, err := handler.imageSigningService.SaveCiSigningConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveCiSigningConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetCiSigningConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciPipelineId, err := strconv.Atoi(mux.Vars(r)["ciPipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkCiPipelineRbac(w, r.Header.Get("token"), ciPipelineId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.imageSigningService.GetCiSigningConfig(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCiSigningConfig", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) DeleteCiSigningConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciPipelineId, err := strconv.Atoi(mux.Vars(r)["ciPipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkCiPipelineRbac(w, r.Header.Get("token"), ciPipelineId, casbin.ActionUpdate) {
+		return
+	}
+	err = handler.imageSigningService.DeleteCiSigningConfig(ciPipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteCiSigningConfig", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, ciPipelineId, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request pipelineBean.ImageSigningPolicyDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePolicy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, SavePolicy", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//AUTH - same as vulnerability policies
+	token := r.Header.Get("token")
+	if request.AppId > 0 && request.EnvId > 0 {
+		object := handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, object); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+		object = handler.enforcerUtil.GetEnvRBACNameByAppId(request.AppId, request.EnvId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionCreate, object); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	} else if request.AppId == 0 && request.EnvId > 0 {
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionCreate, "*"); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+			return
+		}
+	} else if _, ok := handler.checkSuperAdmin(w, r); !ok {
+		return
+	}
+	res, err := handler.imageSigningService.SavePolicy(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var ids [3]int
+	for i, param := range []string{"clusterId", "envId", "appId"} {
+		value := r.URL.Query().Get(param)
+		if len(value) == 0 {
+			continue
+		}
+		ids[i], err = strconv.Atoi(value)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	clusterId, envId, appId := ids[0], ids[1], ids[2]
+	if appId > 0 {
+		object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+		if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	res, err := handler.imageSigningService.GetApplicablePolicy(clusterId, envId, appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPolicy", "err", err, "clusterId", clusterId, "envId", envId, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetImageSignatures(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, err := strconv.Atoi(mux.Vars(r)["artifactId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	artifact, err := handler.ciArtifactRepository.Get(artifactId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching artifact", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if artifact.PipelineId > 0 {
+		if !handler.checkCiPipelineRbac(w, r.Header.Get("token"), artifact.PipelineId, casbin.ActionGet) {
+			return
+		}
+	} else if _, ok := handler.checkSuperAdmin(w, r); !ok {
+		return
+	}
+	res, err := handler.imageSigningService.GetImageSignatures(artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetImageSignatures", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	isSuperAdmin, err := handler.userService.IsSuperAdmin(int(userId))
+	if err != nil || !isSuperAdmin {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (handler *ImageSigningRestHandlerImpl) checkCiPipelineRbac(w http.ResponseWriter, token string, ciPipelineId int, action string) bool {
+	ciPipeline, err := handler.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(ciPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
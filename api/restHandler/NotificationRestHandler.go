@@ -49,6 +49,7 @@ const (
 	WEBHOOK_CONFIG_DELETE_SUCCESS_RESP = "Webhook config deleted successfully."
 	SES_CONFIG_DELETE_SUCCESS_RESP     = "SES config deleted successfully."
 	SMTP_CONFIG_DELETE_SUCCESS_RESP    = "SMTP config deleted successfully."
+	CHANNEL_CONFIG_DELETE_SUCCESS_RESP = "Channel config deleted successfully."
 )
 
 type NotificationRestHandler interface {
@@ -59,6 +60,9 @@ type NotificationRestHandler interface {
 	FindSlackConfig(w http.ResponseWriter, r *http.Request)
 	FindSMTPConfig(w http.ResponseWriter, r *http.Request)
 	FindWebhookConfig(w http.ResponseWriter, r *http.Request)
+	FindNotificationChannelConfig(w http.ResponseWriter, r *http.Request)
+	ValidateNotificationChannelConfig(w http.ResponseWriter, r *http.Request)
+	TestNotificationChannelConfig(w http.ResponseWriter, r *http.Request)
 	GetWebhookVariables(w http.ResponseWriter, r *http.Request)
 	FindAllNotificationConfig(w http.ResponseWriter, r *http.Request)
 	GetAllNotificationSettings(w http.ResponseWriter, r *http.Request)
@@ -86,6 +90,7 @@ type NotificationRestHandlerImpl struct {
 	environmentService   cluster.EnvironmentService
 	pipelineBuilder      pipeline.PipelineBuilder
 	enforcerUtil         rbac.EnforcerUtil
+	channelService       notifier.NotificationChannelService
 }
 
 type ChannelDto struct {
@@ -98,7 +103,7 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 	validator *validator.Validate, notificationService notifier.NotificationConfigService,
 	slackService notifier.SlackNotificationService, webhookService notifier.WebhookNotificationService, sesService notifier.SESNotificationService, smtpService notifier.SMTPNotificationService,
 	enforcer casbin.Enforcer, teamService team.TeamService, environmentService cluster.EnvironmentService, pipelineBuilder pipeline.PipelineBuilder,
-	enforcerUtil rbac.EnforcerUtil) *NotificationRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, channelService notifier.NotificationChannelService) *NotificationRestHandlerImpl {
 	return &NotificationRestHandlerImpl{
 		dockerRegistryConfig: dockerRegistryConfig,
 		logger:               logger,
@@ -116,6 +121,7 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 		environmentService:   environmentService,
 		pipelineBuilder:      pipelineBuilder,
 		enforcerUtil:         enforcerUtil,
+		channelService:       channelService,
 	}
 }
 
@@ -587,6 +593,42 @@ func (impl NotificationRestHandlerImpl) SaveNotificationChannelConfig(w http.Res
 		}
 		w.Header().Set("Content-Type", "application/json")
 		common.WriteJsonResp(w, nil, res, http.StatusOK)
+	} else if impl.channelService.IsSupportedChannel(channelReq.Channel) {
+		var configReq *notifier.NotificationChannelConfigRequest
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&configReq)
+		if err != nil {
+			impl.logger.Errorw("request err, SaveNotificationChannelConfig", "err", err, "configReq", configReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		err = impl.validator.Struct(configReq)
+		if err != nil {
+			impl.logger.Errorw("validation err, SaveNotificationChannelConfig", "err", err, "configReq", configReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		// RBAC enforcer applying
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+		//RBAC enforcer Ends
+
+		for _, config := range configReq.Configs {
+			config.Channel = configReq.Channel
+		}
+		res, cErr := impl.channelService.SaveOrEditNotificationConfig(configReq.Configs, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, SaveNotificationChannelConfig", "err", cErr, "channel", configReq.Channel)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		common.WriteJsonResp(w, nil, res, http.StatusOK)
+	} else {
+		common.WriteJsonResp(w, fmt.Errorf(" The channel you requested is not supported"), nil, http.StatusBadRequest)
 	}
 }
 
@@ -595,6 +637,8 @@ type ChannelResponseDTO struct {
 	WebhookConfigs []*notifier.WebhookConfigDto `json:"webhookConfigs"`
 	SESConfigs     []*notifier.SESConfigDto     `json:"sesConfigs"`
 	SMTPConfigs    []*notifier.SMTPConfigDto    `json:"smtpConfigs"`
+	//configs of provider based channels, each entry carries its channel
+	ChannelConfigs []*notifier.NotificationChannelConfigDto `json:"channelConfigs"`
 }
 
 func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.ResponseWriter, r *http.Request) {
@@ -681,6 +725,16 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.Respons
 	if pass {
 		channelsResponse.SMTPConfigs = smtpConfigs
 	}
+
+	channelConfigs, err := impl.channelService.FetchAllNotificationConfig()
+	if err != nil {
+		impl.logger.Errorw("service err, FindAllNotificationConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if pass {
+		channelsResponse.ChannelConfigs = channelConfigs
+	}
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, channelsResponse, http.StatusOK)
 }
@@ -790,6 +844,99 @@ func (impl NotificationRestHandlerImpl) FindWebhookConfig(w http.ResponseWriter,
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, webhookConfig, http.StatusOK)
 }
+func (impl NotificationRestHandlerImpl) FindNotificationChannelConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	channel := util.Channel(vars["channel"])
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil || !impl.channelService.IsSupportedChannel(channel) {
+		impl.logger.Errorw("request err, FindNotificationChannelConfig", "err", err, "channel", channel)
+		common.WriteJsonResp(w, fmt.Errorf("invalid channel or id"), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+
+	channelConfig, err := impl.channelService.FetchNotificationConfigById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, FindNotificationChannelConfig", "err", err, "id", id)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if channelConfig.Channel != channel {
+		common.WriteJsonResp(w, fmt.Errorf("config not found for channel %s", channel), nil, http.StatusNotFound)
+		return
+	}
+	common.WriteJsonResp(w, nil, channelConfig, http.StatusOK)
+}
+
+func (impl NotificationRestHandlerImpl) ValidateNotificationChannelConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var configReq notifier.NotificationChannelConfigDto
+	err = json.NewDecoder(r.Body).Decode(&configReq)
+	if err != nil {
+		impl.logger.Errorw("request err, ValidateNotificationChannelConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	err = impl.channelService.ValidateNotificationConfig(&configReq)
+	if err != nil {
+		impl.logger.Errorw("service err, ValidateNotificationChannelConfig", "err", err, "channel", configReq.Channel)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, "config is valid", http.StatusOK)
+}
+
+// TestNotificationChannelConfig sends a sample notification either to the config in the body or, when only
+// an id is given, to the saved config
+func (impl NotificationRestHandlerImpl) TestNotificationChannelConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var configReq notifier.NotificationChannelConfigDto
+	err = json.NewDecoder(r.Body).Decode(&configReq)
+	if err != nil {
+		impl.logger.Errorw("request err, TestNotificationChannelConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	err = impl.channelService.SendTestNotification(&configReq)
+	if err != nil {
+		impl.logger.Errorw("service err, TestNotificationChannelConfig", "err", err, "channel", configReq.Channel)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "test notification sent", http.StatusOK)
+}
+
 func (impl NotificationRestHandlerImpl) GetWebhookVariables(w http.ResponseWriter, r *http.Request) {
 	userId, err := impl.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -906,6 +1053,13 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfigAutocomplete(w
 			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 			return
 		}
+	} else if impl.channelService.IsSupportedChannel(util.Channel(cType)) {
+		channelsResponse, err = impl.channelService.FetchAllNotificationConfigAutocomplete(util.Channel(cType))
+		if err != nil {
+			impl.logger.Errorw("service err, FindAllNotificationConfigAutocomplete", "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
 	}
 	if channelsResponse == nil {
 		channelsResponse = make([]*notifier.NotificationChannelAutoResponse, 0)
@@ -1123,6 +1277,30 @@ func (impl NotificationRestHandlerImpl) DeleteNotificationChannelConfig(w http.R
 			return
 		}
 		common.WriteJsonResp(w, nil, SMTP_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
+	} else if impl.channelService.IsSupportedChannel(channelReq.Channel) {
+		var deleteReq *notifier.NotificationChannelConfigDto
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&deleteReq)
+		if err != nil || deleteReq.Id == 0 {
+			impl.logger.Errorw("request err, DeleteNotificationChannelConfig", "err", err, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, fmt.Errorf("invalid delete request, id is required"), nil, http.StatusBadRequest)
+			return
+		}
+
+		// RBAC enforcer applying
+		token := r.Header.Get("token")
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+		//RBAC enforcer Ends
+
+		cErr := impl.channelService.DeleteNotificationConfig(deleteReq, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, DeleteNotificationChannelConfig", "err", cErr, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		common.WriteJsonResp(w, nil, CHANNEL_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
 	} else {
 		common.WriteJsonResp(w, fmt.Errorf(" The channel you requested is not supported"), nil, http.StatusBadRequest)
 	}
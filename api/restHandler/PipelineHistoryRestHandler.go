@@ -3,6 +3,7 @@ package restHandler
 import (
 	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/pipeline/drift"
 	history2 "github.com/devtron-labs/devtron/pkg/pipeline/history"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
@@ -19,6 +20,9 @@ type PipelineHistoryRestHandler interface {
 	FetchDeployedHistoryComponentDetail(w http.ResponseWriter, r *http.Request)
 	GetAllDeployedConfigurationHistoryForLatestWfrIdForPipeline(w http.ResponseWriter, r *http.Request)
 	GetAllDeployedConfigurationHistoryForSpecificWfrIdForPipeline(w http.ResponseWriter, r *http.Request)
+	GetEnvironmentConfigDrift(w http.ResponseWriter, r *http.Request)
+	GetReleaseConfigDrift(w http.ResponseWriter, r *http.Request)
+	GetLiveConfigDrift(w http.ResponseWriter, r *http.Request)
 }
 
 type PipelineHistoryRestHandlerImpl struct {
@@ -32,6 +36,7 @@ type PipelineHistoryRestHandlerImpl struct {
 	prePostCdScriptHistoryService       history2.PrePostCdScriptHistoryService
 	enforcerUtil                        rbac.EnforcerUtil
 	deployedConfigurationHistoryService history2.DeployedConfigurationHistoryService
+	configDriftService                  drift.ConfigDriftService
 }
 
 func NewPipelineHistoryRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
@@ -41,7 +46,8 @@ func NewPipelineHistoryRestHandlerImpl(logger *zap.SugaredLogger, userAuthServic
 	prePostCiScriptHistoryService history2.PrePostCiScriptHistoryService,
 	prePostCdScriptHistoryService history2.PrePostCdScriptHistoryService,
 	enforcerUtil rbac.EnforcerUtil,
-	deployedConfigurationHistoryService history2.DeployedConfigurationHistoryService) *PipelineHistoryRestHandlerImpl {
+	deployedConfigurationHistoryService history2.DeployedConfigurationHistoryService,
+	configDriftService drift.ConfigDriftService) *PipelineHistoryRestHandlerImpl {
 	return &PipelineHistoryRestHandlerImpl{
 		logger:                              logger,
 		userAuthService:                     userAuthService,
@@ -53,6 +59,7 @@ func NewPipelineHistoryRestHandlerImpl(logger *zap.SugaredLogger, userAuthServic
 		prePostCiScriptHistoryService:       prePostCiScriptHistoryService,
 		enforcerUtil:                        enforcerUtil,
 		deployedConfigurationHistoryService: deployedConfigurationHistoryService,
+		configDriftService:                  configDriftService,
 	}
 }
 
@@ -304,3 +311,139 @@ func (handler *PipelineHistoryRestHandlerImpl) GetAllDeployedConfigurationHistor
 	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
+
+func (handler *PipelineHistoryRestHandlerImpl) GetEnvironmentConfigDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetEnvironmentConfigDrift", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	baseEnvId, err := strconv.Atoi(r.URL.Query().Get("baseEnvId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetEnvironmentConfigDrift", "err", err, "baseEnvId", baseEnvId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	compareEnvId, err := strconv.Atoi(r.URL.Query().Get("compareEnvId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetEnvironmentConfigDrift", "err", err, "compareEnvId", compareEnvId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC START
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC END
+	res, err := handler.configDriftService.GetEnvironmentDrift(appId, baseEnvId, compareEnvId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEnvironmentDrift", "err", err, "appId", appId, "baseEnvId", baseEnvId, "compareEnvId", compareEnvId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (handler *PipelineHistoryRestHandlerImpl) GetReleaseConfigDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetReleaseConfigDrift", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	pipelineId, err := strconv.Atoi(vars["pipelineId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetReleaseConfigDrift", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	baseWfrId, err := strconv.Atoi(r.URL.Query().Get("baseWfrId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetReleaseConfigDrift", "err", err, "baseWfrId", baseWfrId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	compareWfrId, err := strconv.Atoi(r.URL.Query().Get("compareWfrId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetReleaseConfigDrift", "err", err, "compareWfrId", compareWfrId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//deployments of another pipeline of the same app can be compared, like a release promoted to the next environment
+	comparePipelineId := pipelineId
+	if comparePipelineIdParam := r.URL.Query().Get("comparePipelineId"); len(comparePipelineIdParam) > 0 {
+		comparePipelineId, err = strconv.Atoi(comparePipelineIdParam)
+		if err != nil {
+			handler.logger.Errorw("request err, GetReleaseConfigDrift", "err", err, "comparePipelineId", comparePipelineIdParam)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	//RBAC START
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC END
+	res, err := handler.configDriftService.GetReleaseDrift(appId, pipelineId, baseWfrId, comparePipelineId, compareWfrId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetReleaseDrift", "err", err, "pipelineId", pipelineId, "baseWfrId", baseWfrId, "comparePipelineId", comparePipelineId, "compareWfrId", compareWfrId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (handler *PipelineHistoryRestHandlerImpl) GetLiveConfigDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetLiveConfigDrift", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	pipelineId, err := strconv.Atoi(vars["pipelineId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetLiveConfigDrift", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC START
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC END
+	res, err := handler.configDriftService.GetLiveDrift(r.Context(), appId, pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetLiveDrift", "err", err, "appId", appId, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
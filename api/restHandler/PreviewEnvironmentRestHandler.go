@@ -0,0 +1,264 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PreviewEnvironmentRestHandler interface {
+	SavePreviewEnvironmentConfig(w http.ResponseWriter, r *http.Request)
+	GetPreviewEnvironmentConfig(w http.ResponseWriter, r *http.Request)
+	DeletePreviewEnvironmentConfig(w http.ResponseWriter, r *http.Request)
+	GetPreviewEnvironments(w http.ResponseWriter, r *http.Request)
+	DeletePreviewEnvironment(w http.ResponseWriter, r *http.Request)
+}
+
+type PreviewEnvironmentRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	userAuthService           user.UserService
+	validator                 *validator.Validate
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+	pipelineRepository        pipelineConfig.PipelineRepository
+	previewEnvironmentService pipeline.PreviewEnvironmentService
+	clusterService            cluster.ClusterService
+}
+
+func NewPreviewEnvironmentRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	previewEnvironmentService pipeline.PreviewEnvironmentService, clusterService cluster.ClusterService) *PreviewEnvironmentRestHandlerImpl {
+	return &PreviewEnvironmentRestHandlerImpl{
+		logger:                    logger,
+		userAuthService:           userAuthService,
+		validator:                 validator,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+		pipelineRepository:        pipelineRepository,
+		previewEnvironmentService: previewEnvironmentService,
+		clusterService:            clusterService,
+	}
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) SavePreviewEnvironmentConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipelineBean.PreviewEnvironmentConfigDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePreviewEnvironmentConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	handler.logger.Infow("request payload, SavePreviewEnvironmentConfig", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePreviewEnvironmentConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) ||
+		!handler.checkClusterRbac(w, token, userId, request.ClusterId) {
+		return
+	}
+	res, err := handler.previewEnvironmentService.SaveConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePreviewEnvironmentConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) GetPreviewEnvironmentConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.previewEnvironmentService.GetConfig(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPreviewEnvironmentConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) DeletePreviewEnvironmentConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) {
+		return
+	}
+	err = handler.previewEnvironmentService.DeleteConfig(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePreviewEnvironmentConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) GetPreviewEnvironments(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, ok := handler.getPipeline(w, pipelineId)
+	if !ok || !handler.checkAppRbac(w, r.Header.Get("token"), cdPipeline.AppId, casbin.ActionGet) {
+		return
+	}
+	res, err := handler.previewEnvironmentService.GetPreviewEnvironments(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPreviewEnvironments", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) DeletePreviewEnvironment(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	previewEnvironment, err := handler.previewEnvironmentService.GetPreviewEnvironmentById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePreviewEnvironment", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// access to the template pipeline governs its previews
+	cdPipeline, ok := handler.getPipeline(w, previewEnvironment.TemplatePipelineId)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppRbac(w, token, cdPipeline.AppId, casbin.ActionUpdate) || !handler.checkEnvRbac(w, token, cdPipeline, casbin.ActionUpdate) {
+		return
+	}
+	res, err := handler.previewEnvironmentService.DeletePreviewEnvironment(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePreviewEnvironment", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) getPipeline(w http.ResponseWriter, pipelineId int) (*pipelineConfig.Pipeline, bool) {
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching cd pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	return cdPipeline, true
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) checkAppRbac(w http.ResponseWriter, token string, appId int, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *PreviewEnvironmentRestHandlerImpl) checkEnvRbac(w http.ResponseWriter, token string, cdPipeline *pipelineConfig.Pipeline, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, cdPipeline.Id)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// checkClusterRbac allows super admins and users who can create in the cluster, as preview environments create
+// environments and namespaces in it
+func (handler *PreviewEnvironmentRestHandlerImpl) checkClusterRbac(w http.ResponseWriter, token string, userId int32, clusterId int) bool {
+	isSuperAdmin, err := handler.userAuthService.IsSuperAdmin(int(userId))
+	if err != nil {
+		handler.logger.Errorw("error in checking super admin", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if isSuperAdmin {
+		return true
+	}
+	clusterBean, err := handler.clusterService.FindByIdWithoutConfig(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching cluster", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceCluster, casbin.ActionCreate, strings.ToLower(clusterBean.ClusterName)); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/devtron-labs/devtron/client/lens"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/doraMetrics"
 	"github.com/devtron-labs/devtron/pkg/team"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
@@ -31,12 +32,17 @@ import (
 	"github.com/gorilla/schema"
 	"go.uber.org/zap"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ReleaseMetricsRestHandler interface {
 	ResetDataForAppEnvironment(w http.ResponseWriter, r *http.Request)
 	ResetDataForAllAppEnvironment(w http.ResponseWriter, r *http.Request)
 	GetDeploymentMetrics(w http.ResponseWriter, r *http.Request)
+	GetDoraMetrics(w http.ResponseWriter, r *http.Request)
+	ExportDoraMetricsCsv(w http.ResponseWriter, r *http.Request)
 }
 
 type ReleaseMetricsRestHandlerImpl struct {
@@ -47,6 +53,7 @@ type ReleaseMetricsRestHandlerImpl struct {
 	teamService        team.TeamService
 	pipelineRepository pipelineConfig.PipelineRepository
 	enforcerUtil       rbac.EnforcerUtil
+	doraMetricsService doraMetrics.DoraMetricsService
 }
 
 func NewReleaseMetricsRestHandlerImpl(
@@ -55,7 +62,8 @@ func NewReleaseMetricsRestHandlerImpl(
 	ReleaseDataService app.ReleaseDataService,
 	userAuthService user.UserService,
 	teamService team.TeamService,
-	pipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil) *ReleaseMetricsRestHandlerImpl {
+	pipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil,
+	doraMetricsService doraMetrics.DoraMetricsService) *ReleaseMetricsRestHandlerImpl {
 	return &ReleaseMetricsRestHandlerImpl{
 		logger:             logger,
 		enforcer:           enforcer,
@@ -64,6 +72,7 @@ func NewReleaseMetricsRestHandlerImpl(
 		teamService:        teamService,
 		pipelineRepository: pipelineRepository,
 		enforcerUtil:       enforcerUtil,
+		doraMetricsService: doraMetricsService,
 	}
 }
 
@@ -183,3 +192,110 @@ func (impl *ReleaseMetricsRestHandlerImpl) GetDeploymentMetrics(w http.ResponseW
 		impl.logger.Errorw("service err, GetDeploymentMetrics", "err", err, "resCode", resCode)
 	}
 }
+
+func (impl *ReleaseMetricsRestHandlerImpl) GetDoraMetrics(w http.ResponseWriter, r *http.Request) {
+	response, ok := impl.getDoraMetrics(w, r)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (impl *ReleaseMetricsRestHandlerImpl) ExportDoraMetricsCsv(w http.ResponseWriter, r *http.Request) {
+	response, ok := impl.getDoraMetrics(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=dora-metrics-%s.csv", time.Now().UTC().Format("20060102150405")))
+	w.WriteHeader(http.StatusOK)
+	err := doraMetrics.WriteDoraMetricsCsv(response, w)
+	if err != nil {
+		impl.logger.Errorw("error in writing dora metrics csv", "err", err)
+	}
+}
+
+// getDoraMetrics computes the metrics over the apps the user can view, writing the error response itself on failure.
+func (impl *ReleaseMetricsRestHandlerImpl) getDoraMetrics(w http.ResponseWriter, r *http.Request) (*doraMetrics.DoraMetricsResponse, bool) {
+	request, err := getDoraMetricsRequest(r)
+	if err != nil {
+		impl.logger.Errorw("request err, GetDoraMetrics", "err", err, "query", r.URL.RawQuery)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	emailId, err := impl.userAuthService.GetEmailFromToken(token)
+	if err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	request.CheckAuthBatch = func(appIds []int) map[int]bool {
+		appObjects := impl.enforcerUtil.GetRbacObjectsByAppIds(appIds)
+		var objects []string
+		for _, object := range appObjects {
+			objects = append(objects, object)
+		}
+		results := impl.enforcer.EnforceByEmailInBatch(emailId, casbin.ResourceApplications, casbin.ActionGet, objects)
+		authorizedApps := make(map[int]bool)
+		for appId, object := range appObjects {
+			authorizedApps[appId] = results[object]
+		}
+		return authorizedApps
+	}
+	response, err := impl.doraMetricsService.GetDoraMetrics(request)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDoraMetrics", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	return response, true
+}
+
+func getDoraMetricsRequest(r *http.Request) (*doraMetrics.DoraMetricsRequest, error) {
+	v := r.URL.Query()
+	request := &doraMetrics.DoraMetricsRequest{
+		GroupBy: doraMetrics.GroupBy(strings.ToUpper(v.Get("groupBy"))),
+		Bucket:  doraMetrics.Bucket(strings.ToUpper(v.Get("bucket"))),
+	}
+	var err error
+	if from := v.Get("from"); len(from) > 0 {
+		if request.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, fmt.Errorf("invalid from %s, expected RFC3339 time", from)
+		}
+	}
+	if to := v.Get("to"); len(to) > 0 {
+		if request.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, fmt.Errorf("invalid to %s, expected RFC3339 time", to)
+		}
+	}
+	if request.AppIds, err = getIntListQueryParam(v.Get("appIds")); err != nil {
+		return nil, fmt.Errorf("please provide valid appIds")
+	}
+	if request.EnvIds, err = getIntListQueryParam(v.Get("envIds")); err != nil {
+		return nil, fmt.Errorf("please provide valid envIds")
+	}
+	if request.TeamIds, err = getIntListQueryParam(v.Get("teamIds")); err != nil {
+		return nil, fmt.Errorf("please provide valid teamIds")
+	}
+	if appGroupId := v.Get("appGroupId"); len(appGroupId) > 0 {
+		if request.AppGroupId, err = strconv.Atoi(appGroupId); err != nil {
+			return nil, fmt.Errorf("please provide valid appGroupId")
+		}
+	}
+	return request, nil
+}
+
+func getIntListQueryParam(value string) ([]int, error) {
+	var ids []int
+	if len(value) == 0 {
+		return ids, nil
+	}
+	for _, idString := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(idString))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
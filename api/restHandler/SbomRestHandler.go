@@ -0,0 +1,233 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SbomRestHandler interface {
+	UploadSboms(w http.ResponseWriter, r *http.Request)
+	GetArtifactSboms(w http.ResponseWriter, r *http.Request)
+	DownloadSbom(w http.ResponseWriter, r *http.Request)
+	GetPackageExposure(w http.ResponseWriter, r *http.Request)
+}
+
+type SbomRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	userService          user.UserService
+	validator            *validator.Validate
+	enforcer             casbin.Enforcer
+	enforcerUtil         rbac.EnforcerUtil
+	ciPipelineRepository pipelineConfig.CiPipelineRepository
+	ciArtifactRepository repository.CiArtifactRepository
+	sbomService          pipeline.SbomService
+}
+
+func NewSbomRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil, ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciArtifactRepository repository.CiArtifactRepository, sbomService pipeline.SbomService) *SbomRestHandlerImpl {
+	return &SbomRestHandlerImpl{
+		logger:               logger,
+		userService:          userService,
+		validator:            validator,
+		enforcer:             enforcer,
+		enforcerUtil:         enforcerUtil,
+		ciPipelineRepository: ciPipelineRepository,
+		ciArtifactRepository: ciArtifactRepository,
+		sbomService:          sbomService,
+	}
+}
+
+// UploadSboms is called by the image scanner with the sboms generated during scan
+func (handler *SbomRestHandlerImpl) UploadSboms(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r)
+	if !ok {
+		return
+	}
+	var request pipelineBean.SbomUploadRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, UploadSboms", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, UploadSboms", "imageDigest", request.ImageDigest, "ciArtifactId", request.CiArtifactId)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, UploadSboms", "err", err, "imageDigest", request.ImageDigest)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.sbomService.UploadSboms(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, UploadSboms", "err", err, "imageDigest", request.ImageDigest)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *SbomRestHandlerImpl) GetArtifactSboms(w http.ResponseWriter, r *http.Request) {
+	artifactId, ok := handler.checkArtifactRbac(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.sbomService.GetSbomsByArtifactId(artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetArtifactSboms", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *SbomRestHandlerImpl) DownloadSbom(w http.ResponseWriter, r *http.Request) {
+	format := security.SbomFormat(r.URL.Query().Get("format"))
+	if len(format) == 0 {
+		format = security.SBOM_FORMAT_SPDX_JSON
+	}
+	if format != security.SBOM_FORMAT_SPDX_JSON && format != security.SBOM_FORMAT_CYCLONEDX_JSON {
+		common.WriteJsonResp(w, fmt.Errorf("unsupported sbom format %s", format), nil, http.StatusBadRequest)
+		return
+	}
+	artifactId, ok := handler.checkArtifactRbac(w, r)
+	if !ok {
+		return
+	}
+	document, err := handler.sbomService.GetSbomDocument(artifactId, format)
+	if err != nil {
+		handler.logger.Errorw("service err, DownloadSbom", "err", err, "artifactId", artifactId, "format", format)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=sbom-%d-%s.json", artifactId, strings.TrimSuffix(string(format), "-json")))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte(document))
+	if err != nil {
+		handler.logger.Errorw("error in writing sbom document", "err", err, "artifactId", artifactId)
+	}
+}
+
+func (handler *SbomRestHandlerImpl) GetPackageExposure(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request pipelineBean.SbomPackageExposureRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, GetPackageExposure", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, GetPackageExposure", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	results, err := handler.sbomService.FindDeployedPackageExposure(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPackageExposure", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	//RBAC, exposures in apps and environments the user can not view are left out
+	token := r.Header.Get("token")
+	appAccess := make(map[int]bool)
+	envAccess := make(map[string]bool)
+	exposures := make([]*pipelineBean.SbomPackageExposureDto, 0)
+	for _, item := range results {
+		allowed, found := appAccess[item.AppId]
+		if !found {
+			object := handler.enforcerUtil.GetAppRBACNameByAppId(item.AppId)
+			allowed = handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object)
+			appAccess[item.AppId] = allowed
+		}
+		if !allowed {
+			continue
+		}
+		key := fmt.Sprintf("%d-%d", item.AppId, item.EnvId)
+		allowed, found = envAccess[key]
+		if !found {
+			object := handler.enforcerUtil.GetEnvRBACNameByAppId(item.AppId, item.EnvId)
+			allowed = handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, object)
+			envAccess[key] = allowed
+		}
+		if allowed {
+			exposures = append(exposures, item)
+		}
+	}
+	//RBAC
+	common.WriteJsonResp(w, nil, exposures, http.StatusOK)
+}
+
+// checkArtifactRbac allows access to the sboms of an artifact as per the app of its ci pipeline, sboms of external ci
+// artifacts are accessible to super admins only
+func (handler *SbomRestHandlerImpl) checkArtifactRbac(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	artifactId, err := strconv.Atoi(mux.Vars(r)["artifactId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, false
+	}
+	artifact, err := handler.ciArtifactRepository.Get(artifactId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching artifact", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return 0, false
+	}
+	if artifact.PipelineId == 0 {
+		_, ok := handler.checkSuperAdmin(w, r)
+		return artifactId, ok
+	}
+	ciPipeline, err := handler.ciPipelineRepository.FindById(artifact.PipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", artifact.PipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return 0, false
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(ciPipeline.AppId)
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, false
+	}
+	return artifactId, true
+}
+
+func (handler *SbomRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	isSuperAdmin, err := handler.userService.IsSuperAdmin(int(userId))
+	if err != nil || !isSuperAdmin {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
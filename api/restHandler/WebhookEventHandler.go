@@ -36,21 +36,24 @@ type WebhookEventHandler interface {
 }
 
 type WebhookEventHandlerImpl struct {
-	logger                 *zap.SugaredLogger
-	gitHostConfig          pipeline.GitHostConfig
-	eventClient            client.EventClient
-	webhookSecretValidator git.WebhookSecretValidator
-	webhookEventDataConfig pipeline.WebhookEventDataConfig
+	logger                    *zap.SugaredLogger
+	gitHostConfig             pipeline.GitHostConfig
+	eventClient               client.EventClient
+	webhookSecretValidator    git.WebhookSecretValidator
+	webhookEventDataConfig    pipeline.WebhookEventDataConfig
+	previewEnvironmentService pipeline.PreviewEnvironmentService
 }
 
 func NewWebhookEventHandlerImpl(logger *zap.SugaredLogger, gitHostConfig pipeline.GitHostConfig, eventClient client.EventClient,
-	webhookSecretValidator git.WebhookSecretValidator, webhookEventDataConfig pipeline.WebhookEventDataConfig) *WebhookEventHandlerImpl {
+	webhookSecretValidator git.WebhookSecretValidator, webhookEventDataConfig pipeline.WebhookEventDataConfig,
+	previewEnvironmentService pipeline.PreviewEnvironmentService) *WebhookEventHandlerImpl {
 	return &WebhookEventHandlerImpl{
-		logger:                 logger,
-		gitHostConfig:          gitHostConfig,
-		eventClient:            eventClient,
-		webhookSecretValidator: webhookSecretValidator,
-		webhookEventDataConfig: webhookEventDataConfig,
+		logger:                    logger,
+		gitHostConfig:             gitHostConfig,
+		eventClient:               eventClient,
+		webhookSecretValidator:    webhookSecretValidator,
+		webhookEventDataConfig:    webhookEventDataConfig,
+		previewEnvironmentService: previewEnvironmentService,
 	}
 }
 
@@ -120,6 +123,9 @@ func (impl WebhookEventHandlerImpl) OnWebhookEvent(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// closed pull requests never reach ci, their preview environments are removed here
+	go impl.previewEnvironmentService.HandlePullRequestEvent(eventType, requestBodyBytes)
+
 	// write event
 	err = impl.eventClient.WriteNatsEvent(pubsub.WEBHOOK_EVENT_TOPIC, webhookEvent)
 	if err != nil {
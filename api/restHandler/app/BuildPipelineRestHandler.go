@@ -14,6 +14,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
 	appGroup2 "github.com/devtron-labs/devtron/pkg/appGroup"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	bean1 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
@@ -123,6 +124,11 @@ func (handler PipelineConfigRestHandlerImpl) CreateCiConfig(w http.ResponseWrite
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(createRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.pipelineBuilder.CreateCiPipeline(&createRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, create", "err", err, "create request", createRequest)
@@ -165,6 +171,11 @@ func (handler PipelineConfigRestHandlerImpl) UpdateCiTemplate(w http.ResponseWri
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(configRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.pipelineBuilder.UpdateCiTemplate(&configRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, UpdateCiTemplate", "err", err, "UpdateCiTemplate", configRequest)
@@ -267,6 +278,11 @@ func (handler PipelineConfigRestHandlerImpl) authorizeCiSourceChangeRequest(w ht
 		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
 		return err
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(patchRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return err
+	}
 	err = handler.validator.Struct(patchRequest)
 	if err != nil {
 		handler.Logger.Errorw("validation err", "err", err)
@@ -350,6 +366,11 @@ func (handler PipelineConfigRestHandlerImpl) PatchCiPipelines(w http.ResponseWri
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(patchRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	ciConf, err := handler.pipelineBuilder.GetCiPipeline(patchRequest.AppId)
 
@@ -378,12 +399,23 @@ func (handler PipelineConfigRestHandlerImpl) PatchCiPipelines(w http.ResponseWri
 	if app.AppType == helper.Job {
 		patchRequest.IsJob = true
 	}
+	// current pipeline is kept for the audit log diff
+	var currentPipeline *bean.CiPipeline
+	if patchRequest.Action == bean.UPDATE_SOURCE && patchRequest.CiPipeline != nil && patchRequest.CiPipeline.Id > 0 {
+		currentPipeline, err = handler.pipelineBuilder.GetCiPipelineById(patchRequest.CiPipeline.Id)
+		if err != nil {
+			handler.Logger.Errorw("error in fetching current ci pipeline, PatchCiPipelines", "err", err, "pipelineId", patchRequest.CiPipeline.Id)
+		}
+	}
 	createResp, err := handler.pipelineBuilder.PatchCiPipeline(&patchRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, PatchCiPipelines", "err", err, "PatchCiPipelines", patchRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	if currentPipeline != nil {
+		auditLog.RecordChange(r, "ciPipeline", currentPipeline, patchRequest.CiPipeline)
+	}
 	if createResp != nil && app != nil {
 		createResp.AppName = app.AppName
 	}
@@ -1102,6 +1134,11 @@ func (handler PipelineConfigRestHandlerImpl) CreateMaterial(w http.ResponseWrite
 			return
 		}
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(createMaterialDto.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	for _, gitMaterial := range createMaterialDto.Material {
 		validationResult, err := handler.ValidateGitMaterialUrl(gitMaterial.GitProviderId, gitMaterial.Url)
 		if err != nil {
@@ -1182,6 +1219,11 @@ func (handler PipelineConfigRestHandlerImpl) UpdateMaterial(w http.ResponseWrite
 			return
 		}
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(updateMaterialDto.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	createResp, err := handler.pipelineBuilder.UpdateMaterialsForApp(&updateMaterialDto)
 	if err != nil {
@@ -1237,6 +1279,11 @@ func (handler PipelineConfigRestHandlerImpl) DeleteMaterial(w http.ResponseWrite
 			return
 		}
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(deleteMaterial.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	//rbac ends
 	err = handler.pipelineBuilder.DeleteMaterial(&deleteMaterial)
 	if err != nil {
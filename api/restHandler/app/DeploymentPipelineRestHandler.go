@@ -12,6 +12,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/internal/util"
 	appGroup2 "github.com/devtron-labs/devtron/pkg/appGroup"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
@@ -132,6 +133,11 @@ func (handler PipelineConfigRestHandlerImpl) ConfigureDeploymentTemplateForApp(w
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(templateRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	ctx, cancel := context.WithCancel(r.Context())
 	if cn, ok := w.(http.CloseNotifier); ok {
@@ -202,6 +208,11 @@ func (handler PipelineConfigRestHandlerImpl) CreateCdPipeline(w http.ResponseWri
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(cdPipeline.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	for _, deploymentPipeline := range cdPipeline.Pipelines {
 		object := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(app.AppName, deploymentPipeline.EnvironmentId)
 		handler.Logger.Debugw("Triggered Request By:", "object", object)
@@ -298,6 +309,11 @@ func (handler PipelineConfigRestHandlerImpl) PatchCdPipeline(w http.ResponseWrit
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(cdPipeline.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, cdPipeline.Pipeline.Id)
 	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
@@ -311,12 +327,23 @@ func (handler PipelineConfigRestHandlerImpl) PatchCdPipeline(w http.ResponseWrit
 		return
 	}
 	ctx := context.WithValue(r.Context(), "token", acdToken)
+	// current pipeline is kept for the audit log diff
+	var currentPipeline *bean.CDPipelineConfigObject
+	if cdPipeline.Action == bean.CD_UPDATE {
+		currentPipeline, err = handler.pipelineBuilder.GetCdPipelineById(cdPipeline.Pipeline.Id)
+		if err != nil {
+			handler.Logger.Errorw("error in fetching current cd pipeline, PatchCdPipeline", "err", err, "pipelineId", cdPipeline.Pipeline.Id)
+		}
+	}
 	createResp, err := handler.pipelineBuilder.PatchCdPipelines(&cdPipeline, ctx)
 	if err != nil {
 		handler.Logger.Errorw("service err, PatchCdPipeline", "err", err, "payload", cdPipeline)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	if currentPipeline != nil {
+		auditLog.RecordChange(r, "cdPipeline", currentPipeline, cdPipeline.Pipeline)
+	}
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 }
 
@@ -568,6 +595,11 @@ func (handler PipelineConfigRestHandlerImpl) ChangeChartRef(w http.ResponseWrite
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(request.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	validate, err2 := handler.chartService.DeploymentTemplateValidate(r.Context(), envConfigProperties.EnvOverrideValues, envConfigProperties.ChartRefId)
 	if !validate {
@@ -678,6 +710,11 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideCreate(w http.Resp
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	object := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, environmentId)
 	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
@@ -741,6 +778,7 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideCreate(w http.Resp
 			return
 		}
 	}
+	auditLog.RecordChange(r, fmt.Sprintf("environment.%d.deploymentTemplate", environmentId), nil, envConfigProperties.EnvOverrideValues)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 }
 
@@ -781,6 +819,11 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideUpdate(w http.Resp
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	object := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
 	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
@@ -794,12 +837,15 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideUpdate(w http.Resp
 		return
 	}
 
+	// values fetched for rbac are the current ones, kept for the audit log diff
+	currentEnvOverrideValues := json.RawMessage(envConfigOverride.EnvOverrideValues)
 	createResp, err := handler.propertiesConfigService.UpdateEnvironmentProperties(appId, &envConfigProperties, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideUpdate", "err", err, "payload", envConfigProperties)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	auditLog.RecordChange(r, fmt.Sprintf("environment.%d.deploymentTemplate", envId), currentEnvOverrideValues, envConfigProperties.EnvOverrideValues)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 }
 
@@ -1266,6 +1312,11 @@ func (handler PipelineConfigRestHandlerImpl) UpdateAppOverride(w http.ResponseWr
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(templateRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	chartRefId := templateRequest.ChartRefId
 	_, span = otel.Tracer("orchestrator").Start(ctx, "chartService.DeploymentTemplateValidate")
 	validate, err2 := handler.chartService.DeploymentTemplateValidate(ctx, templateRequest.ValuesOverride, chartRefId)
@@ -1276,6 +1327,15 @@ func (handler PipelineConfigRestHandlerImpl) UpdateAppOverride(w http.ResponseWr
 		return
 	}
 
+	// current values are kept for the audit log diff
+	var currentValuesOverride json.RawMessage
+	currentTemplate, err := handler.chartService.GetByAppIdAndChartRefId(templateRequest.AppId, chartRefId)
+	if err != nil {
+		handler.Logger.Errorw("error in fetching current deployment template, UpdateAppOverride", "err", err, "appId", templateRequest.AppId, "chartRefId", chartRefId)
+	} else {
+		currentValuesOverride = currentTemplate.ValuesOverride
+	}
+
 	_, span = otel.Tracer("orchestrator").Start(ctx, "chartService.UpdateAppOverride")
 	createResp, err := handler.chartService.UpdateAppOverride(ctx, &templateRequest)
 	span.End()
@@ -1284,6 +1344,7 @@ func (handler PipelineConfigRestHandlerImpl) UpdateAppOverride(w http.ResponseWr
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	auditLog.RecordChange(r, "deploymentTemplate", currentValuesOverride, templateRequest.ValuesOverride)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 
 }
@@ -1417,6 +1478,11 @@ func (handler PipelineConfigRestHandlerImpl) CreateMigrationConfig(w http.Respon
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(deploymentPipeline.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.dbMigrationService.Save(&dbMigrationConfigBean)
 	if err != nil {
 		handler.Logger.Errorw("service err, CreateMigrationConfig", "err", err, "payload", dbMigrationConfigBean)
@@ -1463,6 +1529,11 @@ func (handler PipelineConfigRestHandlerImpl) UpdateMigrationConfig(w http.Respon
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(deploymentPipeline.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.dbMigrationService.Update(&dbMigrationConfigBean)
 	if err != nil {
 		handler.Logger.Errorw("service err, UpdateMigrationConfig", "err", err, "payload", dbMigrationConfigBean)
@@ -1513,6 +1584,11 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideReset(w http.Respo
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	isSuccess, err := handler.propertiesConfigService.ResetEnvironmentProperties(id)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideReset", "err", err, "appId", appId, "environmentId", environmentId)
@@ -1556,6 +1632,11 @@ func (handler PipelineConfigRestHandlerImpl) AppMetricsEnableDisable(w http.Resp
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.chartService.AppMetricsEnableDisable(appMetricEnableDisableRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, AppMetricsEnableDisable", "err", err, "appId", appId, "payload", appMetricEnableDisableRequest)
@@ -1610,6 +1691,11 @@ func (handler PipelineConfigRestHandlerImpl) EnvMetricsEnableDisable(w http.Resp
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.propertiesConfigService.EnvMetricsEnableDisable(&appMetricEnableDisableRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvMetricsEnableDisable", "err", err, "appId", appId, "environmentId", environmentId, "payload", appMetricEnableDisableRequest)
@@ -2148,6 +2234,11 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideCreateNamespace(w
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	err = handler.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	createResp, err := handler.propertiesConfigService.CreateEnvironmentPropertiesWithNamespace(appId, &envConfigProperties)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideCreateNamespace", "err", err, "appId", appId, "environmentId", environmentId, "payload", envConfigProperties)
@@ -29,6 +29,24 @@ func (impl *GlobalPluginRouterImpl) initGlobalPluginRouter(globalPluginRouter *m
 	globalPluginRouter.Path("/global/list").
 		HandlerFunc(impl.globalPluginRestHandler.ListAllPlugins).Methods("GET")
 
+	globalPluginRouter.Path("/global").
+		HandlerFunc(impl.globalPluginRestHandler.CreatePlugin).Methods("POST")
+
+	globalPluginRouter.Path("/global/import").
+		HandlerFunc(impl.globalPluginRestHandler.ImportPlugin).Methods("POST")
+
 	globalPluginRouter.Path("/global/{pluginId}").
 		HandlerFunc(impl.globalPluginRestHandler.GetPluginDetailById).Methods("GET")
+
+	globalPluginRouter.Path("/global/{pluginId}").
+		HandlerFunc(impl.globalPluginRestHandler.UpdatePluginMetadata).Methods("PUT")
+
+	globalPluginRouter.Path("/global/{pluginId}").
+		HandlerFunc(impl.globalPluginRestHandler.DeletePlugin).Methods("DELETE")
+
+	globalPluginRouter.Path("/global/{pluginId}/versions").
+		HandlerFunc(impl.globalPluginRestHandler.GetPluginVersions).Methods("GET")
+
+	globalPluginRouter.Path("/global/{pluginId}/export").
+		HandlerFunc(impl.globalPluginRestHandler.ExportPlugin).Methods("GET")
 }
@@ -27,10 +27,12 @@ type ImageScanRouter interface {
 }
 type ImageScanRouterImpl struct {
 	imageScanRestHandler restHandler.ImageScanRestHandler
+	sbomRestHandler      restHandler.SbomRestHandler
 }
 
-func NewImageScanRouterImpl(imageScanRestHandler restHandler.ImageScanRestHandler) *ImageScanRouterImpl {
-	return &ImageScanRouterImpl{imageScanRestHandler: imageScanRestHandler}
+func NewImageScanRouterImpl(imageScanRestHandler restHandler.ImageScanRestHandler,
+	sbomRestHandler restHandler.SbomRestHandler) *ImageScanRouterImpl {
+	return &ImageScanRouterImpl{imageScanRestHandler: imageScanRestHandler, sbomRestHandler: sbomRestHandler}
 }
 
 func (impl ImageScanRouterImpl) InitImageScanRouter(configRouter *mux.Router) {
@@ -42,4 +44,10 @@ func (impl ImageScanRouterImpl) InitImageScanRouter(configRouter *mux.Router) {
 
 	configRouter.Path("/cve/exposure").HandlerFunc(impl.imageScanRestHandler.VulnerabilityExposure).Methods("POST")
 
+	configRouter.Path("/sbom").HandlerFunc(impl.sbomRestHandler.UploadSboms).Methods("POST")
+	configRouter.Path("/sbom/artifact/{artifactId}").HandlerFunc(impl.sbomRestHandler.GetArtifactSboms).Methods("GET")
+	//format=spdx-json|cyclonedx-json
+	configRouter.Path("/sbom/artifact/{artifactId}/download").HandlerFunc(impl.sbomRestHandler.DownloadSbom).Methods("GET")
+	configRouter.Path("/sbom/package/exposure").HandlerFunc(impl.sbomRestHandler.GetPackageExposure).Methods("POST")
+
 }
@@ -10,14 +10,17 @@ type JobRouter interface {
 	InitJobRouter(router *mux.Router)
 }
 type JobRouterImpl struct {
-	pipelineConfigRestHandler app.PipelineConfigRestHandler
-	appListingRestHandler     restHandler.AppListingRestHandler
+	pipelineConfigRestHandler     app.PipelineConfigRestHandler
+	appListingRestHandler         restHandler.AppListingRestHandler
+	ciPipelineScheduleRestHandler restHandler.CiPipelineScheduleRestHandler
 }
 
-func NewJobRouterImpl(pipelineConfigRestHandler app.PipelineConfigRestHandler, appListingRestHandler restHandler.AppListingRestHandler) *JobRouterImpl {
+func NewJobRouterImpl(pipelineConfigRestHandler app.PipelineConfigRestHandler, appListingRestHandler restHandler.AppListingRestHandler,
+	ciPipelineScheduleRestHandler restHandler.CiPipelineScheduleRestHandler) *JobRouterImpl {
 	return &JobRouterImpl{
-		appListingRestHandler:     appListingRestHandler,
-		pipelineConfigRestHandler: pipelineConfigRestHandler,
+		appListingRestHandler:         appListingRestHandler,
+		pipelineConfigRestHandler:     pipelineConfigRestHandler,
+		ciPipelineScheduleRestHandler: ciPipelineScheduleRestHandler,
 	}
 	//return router
 }
@@ -25,4 +28,10 @@ func (router JobRouterImpl) InitJobRouter(jobRouter *mux.Router) {
 	jobRouter.Path("").HandlerFunc(router.pipelineConfigRestHandler.CreateApp).Methods("POST")
 	jobRouter.Path("/list").HandlerFunc(router.appListingRestHandler.FetchJobs).Methods("POST")
 	jobRouter.Path("/ci-pipeline/list/{jobId}").HandlerFunc(router.appListingRestHandler.FetchJobOverviewCiPipelines).Methods("GET")
+	jobRouter.Path("/ci-pipeline/{pipelineId}/schedule").HandlerFunc(router.ciPipelineScheduleRestHandler.GetSchedule).Methods("GET")
+	jobRouter.Path("/ci-pipeline/{pipelineId}/schedule").HandlerFunc(router.ciPipelineScheduleRestHandler.SaveSchedule).Methods("POST")
+	jobRouter.Path("/ci-pipeline/{pipelineId}/schedule").HandlerFunc(router.ciPipelineScheduleRestHandler.DeleteSchedule).Methods("DELETE")
+	jobRouter.Path("/ci-pipeline/{pipelineId}/schedule/pause").HandlerFunc(router.ciPipelineScheduleRestHandler.PauseSchedule).Methods("PUT")
+	jobRouter.Path("/ci-pipeline/{pipelineId}/schedule/resume").HandlerFunc(router.ciPipelineScheduleRestHandler.ResumeSchedule).Methods("PUT")
+	jobRouter.Path("/ci-pipeline/{pipelineId}/schedule/runs").HandlerFunc(router.ciPipelineScheduleRestHandler.GetScheduleRuns).Methods("GET")
 }
@@ -66,6 +66,12 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 	configRouter.Path("/channel/webhook/{id}").
 		HandlerFunc(impl.notificationRestHandler.FindWebhookConfig).
 		Methods("GET")
+	configRouter.Path("/channel/validate").
+		HandlerFunc(impl.notificationRestHandler.ValidateNotificationChannelConfig).
+		Methods("POST")
+	configRouter.Path("/channel/test").
+		HandlerFunc(impl.notificationRestHandler.TestNotificationChannelConfig).
+		Methods("POST")
 	configRouter.Path("/variables").
 		HandlerFunc(impl.notificationRestHandler.GetWebhookVariables).
 		Methods("GET")
@@ -81,6 +87,9 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 	configRouter.Path("/channel/autocomplete/{type}").
 		HandlerFunc(impl.notificationRestHandler.FindAllNotificationConfigAutocomplete).
 		Methods("GET")
+	configRouter.Path("/channel/{channel}/{id}").
+		HandlerFunc(impl.notificationRestHandler.FindNotificationChannelConfig).
+		Methods("GET")
 	configRouter.Path("/search").
 		HandlerFunc(impl.notificationRestHandler.GetOptionsForNotificationSettings).
 		Methods("POST")
@@ -32,19 +32,34 @@ type PipelineConfigRouterImpl struct {
 	webhookDataRestHandler            restHandler.WebhookDataRestHandler
 	pipelineHistoryRestHandler        restHandler.PipelineHistoryRestHandler
 	pipelineStatusTimelineRestHandler restHandler.PipelineStatusTimelineRestHandler
+	ciPipelineScheduleRestHandler     restHandler.CiPipelineScheduleRestHandler
+	deploymentApprovalRestHandler     restHandler.DeploymentApprovalRestHandler
+	canaryAnalysisRestHandler         restHandler.CanaryAnalysisRestHandler
+	previewEnvironmentRestHandler     restHandler.PreviewEnvironmentRestHandler
+	ciBuildCacheRestHandler           restHandler.CiBuildCacheRestHandler
 }
 
 func NewPipelineRouterImpl(restHandler app.PipelineConfigRestHandler,
 	appWorkflowRestHandler restHandler.AppWorkflowRestHandler,
 	webhookDataRestHandler restHandler.WebhookDataRestHandler,
 	pipelineHistoryRestHandler restHandler.PipelineHistoryRestHandler,
-	pipelineStatusTimelineRestHandler restHandler.PipelineStatusTimelineRestHandler) *PipelineConfigRouterImpl {
+	pipelineStatusTimelineRestHandler restHandler.PipelineStatusTimelineRestHandler,
+	ciPipelineScheduleRestHandler restHandler.CiPipelineScheduleRestHandler,
+	deploymentApprovalRestHandler restHandler.DeploymentApprovalRestHandler,
+	canaryAnalysisRestHandler restHandler.CanaryAnalysisRestHandler,
+	previewEnvironmentRestHandler restHandler.PreviewEnvironmentRestHandler,
+	ciBuildCacheRestHandler restHandler.CiBuildCacheRestHandler) *PipelineConfigRouterImpl {
 	return &PipelineConfigRouterImpl{
 		restHandler:                       restHandler,
 		appWorkflowRestHandler:            appWorkflowRestHandler,
 		webhookDataRestHandler:            webhookDataRestHandler,
 		pipelineHistoryRestHandler:        pipelineHistoryRestHandler,
 		pipelineStatusTimelineRestHandler: pipelineStatusTimelineRestHandler,
+		ciPipelineScheduleRestHandler:     ciPipelineScheduleRestHandler,
+		deploymentApprovalRestHandler:     deploymentApprovalRestHandler,
+		canaryAnalysisRestHandler:         canaryAnalysisRestHandler,
+		previewEnvironmentRestHandler:     previewEnvironmentRestHandler,
+		ciBuildCacheRestHandler:           ciBuildCacheRestHandler,
 	}
 
 }
@@ -72,6 +87,25 @@ func (router PipelineConfigRouterImpl) initPipelineConfigRouter(configRouter *mu
 	configRouter.Path("/cd-pipeline/patch/deployment").HandlerFunc(router.restHandler.HandleChangeDeploymentRequest).Methods("POST")
 	configRouter.Path("/cd-pipeline/patch/deployment/type").HandlerFunc(router.restHandler.HandleChangeDeploymentTypeRequest).Methods("POST")
 	configRouter.Path("/cd-pipeline/patch/deployment/trigger").HandlerFunc(router.restHandler.HandleTriggerDeploymentAfterTypeChange).Methods("POST")
+
+	configRouter.Path("/cd-pipeline/{pipelineId}/approval/config").HandlerFunc(router.deploymentApprovalRestHandler.GetApprovalConfig).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/approval/config").HandlerFunc(router.deploymentApprovalRestHandler.SaveApprovalConfig).Methods("POST")
+	configRouter.Path("/cd-pipeline/{pipelineId}/approval/config").HandlerFunc(router.deploymentApprovalRestHandler.DeleteApprovalConfig).Methods("DELETE")
+	configRouter.Path("/cd-pipeline/{pipelineId}/approval/request").HandlerFunc(router.deploymentApprovalRestHandler.GetApprovalRequests).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/approval/request").HandlerFunc(router.deploymentApprovalRestHandler.RaiseApprovalRequest).Methods("POST")
+	configRouter.Path("/cd-pipeline/approval/request/action").HandlerFunc(router.deploymentApprovalRestHandler.ActOnApprovalRequest).Methods("PUT")
+	configRouter.Path("/cd-pipeline/approval/request/{approvalRequestId}").HandlerFunc(router.deploymentApprovalRestHandler.CancelApprovalRequest).Methods("DELETE")
+	configRouter.Path("/cd-pipeline/{pipelineId}/canary-analysis/config").HandlerFunc(router.canaryAnalysisRestHandler.GetCanaryAnalysisConfig).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/canary-analysis/config").HandlerFunc(router.canaryAnalysisRestHandler.SaveCanaryAnalysisConfig).Methods("POST")
+	configRouter.Path("/cd-pipeline/{pipelineId}/canary-analysis/config").HandlerFunc(router.canaryAnalysisRestHandler.DeleteCanaryAnalysisConfig).Methods("DELETE")
+	configRouter.Path("/cd-pipeline/{pipelineId}/canary-analysis/run").HandlerFunc(router.canaryAnalysisRestHandler.GetCanaryAnalysisRuns).Methods("GET")
+	configRouter.Path("/cd-pipeline/canary-analysis/run/{runId}").HandlerFunc(router.canaryAnalysisRestHandler.GetCanaryAnalysisRun).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/preview-environment/config").HandlerFunc(router.previewEnvironmentRestHandler.GetPreviewEnvironmentConfig).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/preview-environment/config").HandlerFunc(router.previewEnvironmentRestHandler.SavePreviewEnvironmentConfig).Methods("POST")
+	configRouter.Path("/cd-pipeline/{pipelineId}/preview-environment/config").HandlerFunc(router.previewEnvironmentRestHandler.DeletePreviewEnvironmentConfig).Methods("DELETE")
+	configRouter.Path("/cd-pipeline/{pipelineId}/preview-environment").HandlerFunc(router.previewEnvironmentRestHandler.GetPreviewEnvironments).Methods("GET")
+	configRouter.Path("/cd-pipeline/preview-environment/{id}").HandlerFunc(router.previewEnvironmentRestHandler.DeletePreviewEnvironment).Methods("DELETE")
+
 	configRouter.Path("/cd-pipeline/{appId}").HandlerFunc(router.restHandler.GetCdPipelines).Methods("GET")
 	configRouter.Path("/cd-pipeline/{appId}/env/{envId}").HandlerFunc(router.restHandler.GetCdPipelinesForAppAndEnv).Methods("GET")
 	//save environment specific override
@@ -101,6 +135,18 @@ func (router PipelineConfigRouterImpl) initPipelineConfigRouter(configRouter *mu
 
 	configRouter.Path("/ci-pipeline/trigger").HandlerFunc(router.restHandler.TriggerCiPipeline).Methods("POST")
 
+	configRouter.Path("/ci-pipeline/{pipelineId}/schedule").HandlerFunc(router.ciPipelineScheduleRestHandler.GetSchedule).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/schedule").HandlerFunc(router.ciPipelineScheduleRestHandler.SaveSchedule).Methods("POST")
+	configRouter.Path("/ci-pipeline/{pipelineId}/schedule").HandlerFunc(router.ciPipelineScheduleRestHandler.DeleteSchedule).Methods("DELETE")
+	configRouter.Path("/ci-pipeline/{pipelineId}/schedule/pause").HandlerFunc(router.ciPipelineScheduleRestHandler.PauseSchedule).Methods("PUT")
+	configRouter.Path("/ci-pipeline/{pipelineId}/schedule/resume").HandlerFunc(router.ciPipelineScheduleRestHandler.ResumeSchedule).Methods("PUT")
+	configRouter.Path("/ci-pipeline/{pipelineId}/schedule/runs").HandlerFunc(router.ciPipelineScheduleRestHandler.GetScheduleRuns).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/config").HandlerFunc(router.ciBuildCacheRestHandler.GetBuildCacheConfig).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/config").HandlerFunc(router.ciBuildCacheRestHandler.SaveBuildCacheConfig).Methods("POST")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/config").HandlerFunc(router.ciBuildCacheRestHandler.DeleteBuildCacheConfig).Methods("DELETE")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/invalidate").HandlerFunc(router.ciBuildCacheRestHandler.InvalidateBuildCache).Methods("POST")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/entries").HandlerFunc(router.ciBuildCacheRestHandler.GetBuildCacheEntries).Methods("GET")
+
 	configRouter.Path("/{appId}/ci-pipeline/min").HandlerFunc(router.restHandler.GetCiPipelineMin).Methods("GET")
 	configRouter.Path("/ci-pipeline/{pipelineId}/material").HandlerFunc(router.restHandler.FetchMaterials).Methods("GET")
 	configRouter.Path("/ci-pipeline/{pipelineId}/material/{gitMaterialId}").HandlerFunc(router.restHandler.FetchMaterialsByMaterialId).Methods("GET")
@@ -191,6 +237,20 @@ func (router PipelineConfigRouterImpl) initPipelineConfigRouter(configRouter *mu
 		HandlerFunc(router.pipelineHistoryRestHandler.GetAllDeployedConfigurationHistoryForSpecificWfrIdForPipeline).
 		Methods("GET")
 
+	configRouter.Path("/history/drift/environment/{appId}").
+		HandlerFunc(router.pipelineHistoryRestHandler.GetEnvironmentConfigDrift).
+		Queries("baseEnvId", "{baseEnvId}", "compareEnvId", "{compareEnvId}").
+		Methods("GET")
+
+	configRouter.Path("/history/drift/release/{appId}/{pipelineId}").
+		HandlerFunc(router.pipelineHistoryRestHandler.GetReleaseConfigDrift).
+		Queries("baseWfrId", "{baseWfrId}", "compareWfrId", "{compareWfrId}").
+		Methods("GET")
+
+	configRouter.Path("/history/drift/live/{appId}/{pipelineId}").
+		HandlerFunc(router.pipelineHistoryRestHandler.GetLiveConfigDrift).
+		Methods("GET")
+
 	configRouter.Path("/commit-info/{ciPipelineMaterialId}/{gitHash}").HandlerFunc(router.restHandler.GetCommitMetadataForPipelineMaterial).Methods("GET")
 
 	configRouter.Path("/deployment-status/timeline/{appId}/{envId}").HandlerFunc(router.pipelineStatusTimelineRestHandler.FetchTimelines).Methods("GET")
@@ -26,12 +26,15 @@ type PolicyRouter interface {
 	InitPolicyRouter(configRouter *mux.Router)
 }
 type PolicyRouterImpl struct {
-	policyRestHandler restHandler.PolicyRestHandler
+	policyRestHandler       restHandler.PolicyRestHandler
+	imageSigningRestHandler restHandler.ImageSigningRestHandler
 }
 
-func NewPolicyRouterImpl(policyRestHandler restHandler.PolicyRestHandler) *PolicyRouterImpl {
+func NewPolicyRouterImpl(policyRestHandler restHandler.PolicyRestHandler,
+	imageSigningRestHandler restHandler.ImageSigningRestHandler) *PolicyRouterImpl {
 	return &PolicyRouterImpl{
-		policyRestHandler: policyRestHandler,
+		policyRestHandler:       policyRestHandler,
+		imageSigningRestHandler: imageSigningRestHandler,
 	}
 }
 func (impl PolicyRouterImpl) InitPolicyRouter(configRouter *mux.Router) {
@@ -39,4 +42,14 @@ func (impl PolicyRouterImpl) InitPolicyRouter(configRouter *mux.Router) {
 	configRouter.Path("/update").HandlerFunc(impl.policyRestHandler.UpdatePolicy).Methods("POST")
 	configRouter.Path("/list").HandlerFunc(impl.policyRestHandler.GetPolicy).Methods("GET")
 	configRouter.Path("/verify/webhook").HandlerFunc(impl.policyRestHandler.VerifyImage).Methods("POST")
+
+	configRouter.Path("/signing/key").HandlerFunc(impl.imageSigningRestHandler.SaveSigningKey).Methods("POST")
+	configRouter.Path("/signing/key").HandlerFunc(impl.imageSigningRestHandler.GetSigningKeys).Methods("GET")
+	configRouter.Path("/signing/key/{id}").HandlerFunc(impl.imageSigningRestHandler.DeleteSigningKey).Methods("DELETE")
+	configRouter.Path("/signing/ci-pipeline/{ciPipelineId}").HandlerFunc(impl.imageSigningRestHandler.SaveCiSigningConfig).Methods("POST")
+	configRouter.Path("/signing/ci-pipeline/{ciPipelineId}").HandlerFunc(impl.imageSigningRestHandler.GetCiSigningConfig).Methods("GET")
+	configRouter.Path("/signing/ci-pipeline/{ciPipelineId}").HandlerFunc(impl.imageSigningRestHandler.DeleteCiSigningConfig).Methods("DELETE")
+	configRouter.Path("/signing/policy").HandlerFunc(impl.imageSigningRestHandler.SavePolicy).Methods("POST")
+	configRouter.Path("/signing/policy").HandlerFunc(impl.imageSigningRestHandler.GetPolicy).Methods("GET")
+	configRouter.Path("/signing/artifact/{artifactId}").HandlerFunc(impl.imageSigningRestHandler.GetImageSignatures).Methods("GET")
 }
@@ -50,4 +50,10 @@ func (impl ReleaseMetricsRouterImpl) initReleaseMetricsRouter(router *mux.Router
 	router.Path("/").
 		HandlerFunc(impl.releaseMetricsRestHandler.GetDeploymentMetrics).
 		Methods("GET")
+	router.Path("/dora").
+		HandlerFunc(impl.releaseMetricsRestHandler.GetDoraMetrics).
+		Methods("GET")
+	router.Path("/dora/csv").
+		HandlerFunc(impl.releaseMetricsRestHandler.ExportDoraMetricsCsv).
+		Methods("GET")
 }
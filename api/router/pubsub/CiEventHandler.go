@@ -25,6 +25,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/util"
 	"go.uber.org/zap"
 )
@@ -46,34 +47,41 @@ type CiEventHandler interface {
 }
 
 type CiEventHandlerImpl struct {
-	logger         *zap.SugaredLogger
-	pubsubClient   *pubsub.PubSubClientServiceImpl
-	webhookService pipeline.WebhookService
-	ciEventConfig  *CiEventConfig
+	logger                    *zap.SugaredLogger
+	pubsubClient              *pubsub.PubSubClientServiceImpl
+	webhookService            pipeline.WebhookService
+	ciEventConfig             *CiEventConfig
+	previewEnvironmentService pipeline.PreviewEnvironmentService
 }
 
 type CiCompleteEvent struct {
-	CiProjectDetails   []pipeline.CiProjectDetails `json:"ciProjectDetails"`
-	DockerImage        string                      `json:"dockerImage" validate:"required,image-validator"`
-	Digest             string                      `json:"digest"`
-	PipelineId         int                         `json:"pipelineId"`
-	WorkflowId         *int                        `json:"workflowId"`
-	TriggeredBy        int32                       `json:"triggeredBy"`
-	PipelineName       string                      `json:"pipelineName"`
-	DataSource         string                      `json:"dataSource"`
-	MaterialType       string                      `json:"materialType"`
-	Metrics            util.CIMetrics              `json:"metrics"`
-	AppName            string                      `json:"appName"`
-	IsArtifactUploaded bool                        `json:"isArtifactUploaded"`
-	FailureReason      string                      `json:"failureReason"`
+	CiProjectDetails   []pipeline.CiProjectDetails      `json:"ciProjectDetails"`
+	DockerImage        string                           `json:"dockerImage" validate:"required,image-validator"`
+	Digest             string                           `json:"digest"`
+	PipelineId         int                              `json:"pipelineId"`
+	WorkflowId         *int                             `json:"workflowId"`
+	TriggeredBy        int32                            `json:"triggeredBy"`
+	PipelineName       string                           `json:"pipelineName"`
+	DataSource         string                           `json:"dataSource"`
+	MaterialType       string                           `json:"materialType"`
+	Metrics            util.CIMetrics                   `json:"metrics"`
+	AppName            string                           `json:"appName"`
+	IsArtifactUploaded bool                             `json:"isArtifactUploaded"`
+	FailureReason      string                           `json:"failureReason"`
+	BuildCacheMetrics  *bean.BuildCacheMetrics          `json:"buildCacheMetrics,omitempty"`
+	ManifestListDigest string                           `json:"manifestListDigest,omitempty"`
+	PlatformDigests    []repository.ImagePlatformDigest `json:"platformDigests,omitempty"`
+	SbomRefs           []*bean.SbomRefDto               `json:"sbomRefs,omitempty"`
 }
 
-func NewCiEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClientServiceImpl, webhookService pipeline.WebhookService, ciEventConfig *CiEventConfig) *CiEventHandlerImpl {
+func NewCiEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClientServiceImpl, webhookService pipeline.WebhookService, ciEventConfig *CiEventConfig,
+	previewEnvironmentService pipeline.PreviewEnvironmentService) *CiEventHandlerImpl {
 	ciEventHandlerImpl := &CiEventHandlerImpl{
-		logger:         logger,
-		pubsubClient:   pubsubClient,
-		webhookService: webhookService,
-		ciEventConfig:  ciEventConfig,
+		logger:                    logger,
+		pubsubClient:              pubsubClient,
+		webhookService:            webhookService,
+		ciEventConfig:             ciEventConfig,
+		previewEnvironmentService: previewEnvironmentService,
 	}
 	err := ciEventHandlerImpl.Subscribe()
 	if err != nil {
@@ -117,6 +125,10 @@ func (impl *CiEventHandlerImpl) Subscribe() error {
 				return
 			}
 			impl.logger.Debug(resp)
+			err = impl.previewEnvironmentService.HandleCiArtifact(resp)
+			if err != nil {
+				impl.logger.Errorw("error in handling preview environments of ci artifact", "ciPipelineId", ciCompleteEvent.PipelineId, "artifactId", resp, "err", err)
+			}
 		}
 	}
 	err := impl.pubsubClient.Subscribe(pubsub.CI_COMPLETE_TOPIC, callback)
@@ -190,6 +202,10 @@ func (impl *CiEventHandlerImpl) BuildCiArtifactRequest(event CiCompleteEvent) (*
 		UserId:             event.TriggeredBy,
 		WorkflowId:         event.WorkflowId,
 		IsArtifactUploaded: event.IsArtifactUploaded,
+		BuildCacheMetrics:  event.BuildCacheMetrics,
+		ManifestListDigest: event.ManifestListDigest,
+		PlatformDigests:    event.PlatformDigests,
+		SbomRefs:           event.SbomRefs,
 	}
 	return request, nil
 }
@@ -260,6 +276,9 @@ func (impl *CiEventHandlerImpl) BuildCiArtifactRequestForWebhook(event CiComplet
 		UserId:             event.TriggeredBy,
 		WorkflowId:         event.WorkflowId,
 		IsArtifactUploaded: event.IsArtifactUploaded,
+		ManifestListDigest: event.ManifestListDigest,
+		PlatformDigests:    event.PlatformDigests,
+		SbomRefs:           event.SbomRefs,
 	}
 	return request, nil
 }
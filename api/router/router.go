@@ -21,17 +21,22 @@ import (
 	"encoding/json"
 	pubsub2 "github.com/devtron-labs/common-lib/pubsub-lib"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appBundle"
 	"github.com/devtron-labs/devtron/api/appStore"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
+	"github.com/devtron-labs/devtron/api/appSync"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	client "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/api/k8s/application"
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/releaseTrain"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/pubsub"
 	"github.com/devtron-labs/devtron/api/server"
@@ -120,6 +125,21 @@ type MuxRouter struct {
 	ciStatusUpdateCron                 cron.CiStatusUpdateCron
 	appGroupingRouter                  AppGroupingRouter
 	rbacRoleRouter                     user.RbacRoleRouter
+	ciPipelineScheduleCron             cron.CiPipelineScheduleCron
+	deploymentWindowRouter             deploymentWindow.DeploymentWindowRouter
+	scheduledDeploymentCron            cron.ScheduledDeploymentCron
+	canaryAnalysisCron                 cron.CanaryAnalysisCron
+	configDriftScanCron                cron.ConfigDriftScanCron
+	releaseTrainRouter                 releaseTrain.ReleaseTrainRouter
+	appSyncCron                        cron.AppSyncCron
+	appSyncRouter                      appSync.AppSyncRouter
+	appBundleRouter                    appBundle.AppBundleRouter
+	previewEnvironmentCron             cron.PreviewEnvironmentCron
+	buildCacheRetentionCron            cron.BuildCacheRetentionCron
+	userAccessGrantCron                cron.UserAccessGrantCron
+	apiTokenExpiryNotificationCron     cron.ApiTokenExpiryNotificationCron
+	scimRouter                         user.ScimRouter
+	auditLogRouter                     auditLog.AuditLogRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter PipelineTriggerRouter, PipelineConfigRouter PipelineConfigRouter,
@@ -149,7 +169,14 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter PipelineTriggerRouter, P
 	webhookHelmRouter webhookHelm.WebhookHelmRouter, globalCMCSRouter GlobalCMCSRouter,
 	userTerminalAccessRouter terminal2.UserTerminalAccessRouter,
 	jobRouter JobRouter, ciStatusUpdateCron cron.CiStatusUpdateCron, appGroupingRouter AppGroupingRouter,
-	rbacRoleRouter user.RbacRoleRouter) *MuxRouter {
+	rbacRoleRouter user.RbacRoleRouter, ciPipelineScheduleCron cron.CiPipelineScheduleCron,
+	deploymentWindowRouter deploymentWindow.DeploymentWindowRouter, scheduledDeploymentCron cron.ScheduledDeploymentCron,
+	canaryAnalysisCron cron.CanaryAnalysisCron, configDriftScanCron cron.ConfigDriftScanCron,
+	releaseTrainRouter releaseTrain.ReleaseTrainRouter, appSyncCron cron.AppSyncCron, appSyncRouter appSync.AppSyncRouter,
+	appBundleRouter appBundle.AppBundleRouter, previewEnvironmentCron cron.PreviewEnvironmentCron,
+	buildCacheRetentionCron cron.BuildCacheRetentionCron, userAccessGrantCron cron.UserAccessGrantCron,
+	apiTokenExpiryNotificationCron cron.ApiTokenExpiryNotificationCron, scimRouter user.ScimRouter,
+	auditLogRouter auditLog.AuditLogRouter) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
 		HelmRouter:                         HelmRouter,
@@ -219,6 +246,21 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter PipelineTriggerRouter, P
 		JobRouter:                          jobRouter,
 		appGroupingRouter:                  appGroupingRouter,
 		rbacRoleRouter:                     rbacRoleRouter,
+		ciPipelineScheduleCron:             ciPipelineScheduleCron,
+		deploymentWindowRouter:             deploymentWindowRouter,
+		scheduledDeploymentCron:            scheduledDeploymentCron,
+		canaryAnalysisCron:                 canaryAnalysisCron,
+		configDriftScanCron:                configDriftScanCron,
+		releaseTrainRouter:                 releaseTrainRouter,
+		appSyncCron:                        appSyncCron,
+		appSyncRouter:                      appSyncRouter,
+		appBundleRouter:                    appBundleRouter,
+		previewEnvironmentCron:             previewEnvironmentCron,
+		buildCacheRetentionCron:            buildCacheRetentionCron,
+		userAccessGrantCron:                userAccessGrantCron,
+		apiTokenExpiryNotificationCron:     apiTokenExpiryNotificationCron,
+		scimRouter:                         scimRouter,
+		auditLogRouter:                     auditLogRouter,
 	}
 	return r
 }
@@ -308,6 +350,9 @@ func (r MuxRouter) Init() {
 	userRouter := r.Router.PathPrefix("/orchestrator/user").Subrouter()
 	r.UserRouter.InitUserRouter(userRouter)
 
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
 	chartRefRouter := r.Router.PathPrefix("/orchestrator/chartref").Subrouter()
 	r.ChartRefRouter.initChartRefRouter(chartRefRouter)
 
@@ -402,6 +447,18 @@ func (r MuxRouter) Init() {
 	externalLinkRouter := r.Router.PathPrefix("/orchestrator/external-links").Subrouter()
 	r.externalLinkRouter.InitExternalLinkRouter(externalLinkRouter)
 
+	deploymentWindowRouter := r.Router.PathPrefix("/orchestrator/deployment-window").Subrouter()
+	r.deploymentWindowRouter.InitDeploymentWindowRouter(deploymentWindowRouter)
+
+	releaseTrainRouter := r.Router.PathPrefix("/orchestrator/release-train").Subrouter()
+	r.releaseTrainRouter.InitReleaseTrainRouter(releaseTrainRouter)
+
+	appSyncRouter := r.Router.PathPrefix("/orchestrator/app-sync").Subrouter()
+	r.appSyncRouter.InitAppSyncRouter(appSyncRouter)
+
+	appBundleRouter := r.Router.PathPrefix("/orchestrator/app-bundle").Subrouter()
+	r.appBundleRouter.InitAppBundleRouter(appBundleRouter)
+
 	// module router
 	moduleRouter := r.Router.PathPrefix("/orchestrator/module").Subrouter()
 	r.moduleRouter.Init(moduleRouter)
@@ -413,6 +470,12 @@ func (r MuxRouter) Init() {
 	// api-token router
 	apiTokenRouter := r.Router.PathPrefix("/orchestrator/api-token").Subrouter()
 	r.apiTokenRouter.InitApiTokenRouter(apiTokenRouter)
+	// audit log middleware is registered first so that calls rejected by the api-token middleware are audited too
+	r.auditLogRouter.InitAuditLogMiddleware(r.Router)
+	r.apiTokenRouter.InitApiTokenMiddleware(r.Router)
+
+	auditLogRouter := r.Router.PathPrefix("/orchestrator/audit-log").Subrouter()
+	r.auditLogRouter.InitAuditLogRouter(auditLogRouter)
 
 	k8sCapacityApp := r.Router.PathPrefix("/orchestrator/k8s/capacity").Subrouter()
 	r.k8sCapacityRouter.InitK8sCapacityRouter(k8sCapacityApp)
@@ -0,0 +1,154 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type RbacExplainRestHandler interface {
+	Explain(w http.ResponseWriter, r *http.Request)
+	GetEffectivePermissions(w http.ResponseWriter, r *http.Request)
+	GetUsersWithAccess(w http.ResponseWriter, r *http.Request)
+}
+
+type RbacExplainRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	validator          *validator.Validate
+	userService        user.UserService
+	rbacExplainService user.RbacExplainService
+}
+
+func NewRbacExplainRestHandlerImpl(logger *zap.SugaredLogger, validator *validator.Validate, userService user.UserService,
+	rbacExplainService user.RbacExplainService) *RbacExplainRestHandlerImpl {
+	return &RbacExplainRestHandlerImpl{
+		logger:             logger,
+		validator:          validator,
+		userService:        userService,
+		rbacExplainService: rbacExplainService,
+	}
+}
+
+// Explain is open to any user for own checks, super admins can explain checks of other users and api tokens
+func (handler RbacExplainRestHandlerImpl) Explain(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.RbacExplainRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, Explain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, Explain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.UserId == 0 && len(request.EmailId) == 0 {
+		request.UserId = userId
+	}
+	if !handler.checkSelfOrSuperAdmin(w, r, userId, request.UserId, request.EmailId) {
+		return
+	}
+	res, err := handler.rbacExplainService.Explain(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, Explain", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler RbacExplainRestHandlerImpl) GetEffectivePermissions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	v := r.URL.Query()
+	emailId := v.Get("emailId")
+	permissionUserId := 0
+	if userIdParam := v.Get("userId"); len(userIdParam) > 0 {
+		permissionUserId, err = strconv.Atoi(userIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	} else if len(emailId) == 0 {
+		permissionUserId = int(userId)
+	}
+	if !handler.checkSelfOrSuperAdmin(w, r, userId, int32(permissionUserId), emailId) {
+		return
+	}
+	res, err := handler.rbacExplainService.GetEffectivePermissions(int32(permissionUserId), emailId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEffectivePermissions", "err", err, "userId", permissionUserId, "emailId", emailId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetUsersWithAccess is the reverse lookup for access reviews, like who can trigger on an app and environment
+func (handler RbacExplainRestHandlerImpl) GetUsersWithAccess(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	isSuperAdmin, err := handler.userService.IsSuperAdmin(int(userId))
+	if err != nil || !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request bean.RbacAccessReviewRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, GetUsersWithAccess", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, GetUsersWithAccess", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.rbacExplainService.GetUsersWithAccess(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, GetUsersWithAccess", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler RbacExplainRestHandlerImpl) checkSelfOrSuperAdmin(w http.ResponseWriter, r *http.Request, userId int32, targetUserId int32, targetEmailId string) bool {
+	if targetUserId == userId {
+		return true
+	}
+	if targetUserId == 0 && len(targetEmailId) > 0 {
+		emailId, err := handler.userService.GetEmailFromToken(r.Header.Get("token"))
+		if err == nil && strings.EqualFold(emailId, targetEmailId) {
+			return true
+		}
+	}
+	isSuperAdmin, err := handler.userService.IsSuperAdmin(int(userId))
+	if err != nil || !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,327 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/pkg/user/scim"
+	util2 "github.com/devtron-labs/devtron/util"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ScimRestHandler serves the SCIM 2.0 api for identity providers. Responses are plain SCIM documents instead of the
+// usual devtron envelope, and the caller authenticates with a bearer token of a super admin, usually an api token
+type ScimRestHandler interface {
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+	GetResourceTypes(w http.ResponseWriter, r *http.Request)
+
+	GetUser(w http.ResponseWriter, r *http.Request)
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	ListGroups(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+}
+
+type ScimRestHandlerImpl struct {
+	logger      *zap.SugaredLogger
+	userService user.UserService
+	scimService scim.ScimService
+	enforcer    casbin.Enforcer
+}
+
+func NewScimRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService, scimService scim.ScimService,
+	enforcer casbin.Enforcer) *ScimRestHandlerImpl {
+	return &ScimRestHandlerImpl{
+		logger:      logger,
+		userService: userService,
+		scimService: scimService,
+		enforcer:    enforcer,
+	}
+}
+
+func (handler ScimRestHandlerImpl) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	writeScimResp(w, map[string]interface{}{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": scim.DefaultPageSize},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "api token of a super admin",
+		}},
+	}, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) GetResourceTypes(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	resourceTypes := []interface{}{
+		map[string]interface{}{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeUser,
+			"name":     scim.ResourceTypeUser,
+			"endpoint": "/Users",
+			"schema":   scim.SchemaUser,
+		},
+		map[string]interface{}{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeGroup,
+			"name":     scim.ResourceTypeGroup,
+			"endpoint": "/Groups",
+			"schema":   scim.SchemaGroup,
+		},
+	}
+	writeScimResp(w, &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	}, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) GetUser(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	res, err := handler.scimService.GetUser(mux.Vars(r)["id"])
+	handler.writeResp(w, "GetUser", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	startIndex, count, err := getPagination(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	res, err := handler.scimService.ListUsers(r.URL.Query().Get("filter"), startIndex, count)
+	handler.writeResp(w, "ListUsers", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var scimUser scim.User
+	if !decodeScimRequest(w, r, &scimUser) {
+		return
+	}
+	res, err := handler.scimService.CreateUser(&scimUser, token, userId)
+	handler.writeResp(w, "CreateUser", res, err, http.StatusCreated)
+}
+
+func (handler ScimRestHandlerImpl) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var scimUser scim.User
+	if !decodeScimRequest(w, r, &scimUser) {
+		return
+	}
+	res, err := handler.scimService.ReplaceUser(mux.Vars(r)["id"], &scimUser, token, userId)
+	handler.writeResp(w, "ReplaceUser", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) PatchUser(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var patch scim.PatchRequest
+	if !decodeScimRequest(w, r, &patch) {
+		return
+	}
+	res, err := handler.scimService.PatchUser(mux.Vars(r)["id"], &patch, token, userId)
+	handler.writeResp(w, "PatchUser", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	err := handler.scimService.DeleteUser(mux.Vars(r)["id"], userId)
+	handler.writeResp(w, "DeleteUser", nil, err, http.StatusNoContent)
+}
+
+func (handler ScimRestHandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	res, err := handler.scimService.GetGroup(mux.Vars(r)["id"])
+	handler.writeResp(w, "GetGroup", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	startIndex, count, err := getPagination(r)
+	if err != nil {
+		writeScimError(w, err)
+		return
+	}
+	// identity providers skip members when looking groups up, groups can be large
+	excludeMembers := strings.Contains(strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members")
+	res, err := handler.scimService.ListGroups(r.URL.Query().Get("filter"), startIndex, count, excludeMembers)
+	handler.writeResp(w, "ListGroups", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var scimGroup scim.Group
+	if !decodeScimRequest(w, r, &scimGroup) {
+		return
+	}
+	res, err := handler.scimService.CreateGroup(&scimGroup, userId)
+	handler.writeResp(w, "CreateGroup", res, err, http.StatusCreated)
+}
+
+func (handler ScimRestHandlerImpl) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var scimGroup scim.Group
+	if !decodeScimRequest(w, r, &scimGroup) {
+		return
+	}
+	res, err := handler.scimService.ReplaceGroup(mux.Vars(r)["id"], &scimGroup, userId)
+	handler.writeResp(w, "ReplaceGroup", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var patch scim.PatchRequest
+	if !decodeScimRequest(w, r, &patch) {
+		return
+	}
+	res, err := handler.scimService.PatchGroup(mux.Vars(r)["id"], &patch, userId)
+	handler.writeResp(w, "PatchGroup", res, err, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	err := handler.scimService.DeleteGroup(mux.Vars(r)["id"], userId)
+	handler.writeResp(w, "DeleteGroup", nil, err, http.StatusNoContent)
+}
+
+// authenticate reads the bearer token identity providers send, the SCIM paths are whitelisted in the authorizer as
+// it only looks at the devtron token headers
+func (handler ScimRestHandlerImpl) authenticate(w http.ResponseWriter, r *http.Request) (int32, string, bool) {
+	token := util2.GetBearerToken(r)
+	if len(token) == 0 {
+		token = r.Header.Get("token")
+	}
+	if len(token) == 0 {
+		writeScimError(w, scim.NewError(http.StatusUnauthorized, "", "bearer token is required"))
+		return 0, "", false
+	}
+	userId, _, err := handler.userService.GetUserByToken(r.Context(), token)
+	if err != nil || userId == 0 {
+		writeScimError(w, scim.NewError(http.StatusUnauthorized, "", "invalid token"))
+		return 0, "", false
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		writeScimError(w, scim.NewError(http.StatusForbidden, "", "super admin access is required for provisioning"))
+		return 0, "", false
+	}
+	return userId, token, true
+}
+
+func (handler ScimRestHandlerImpl) writeResp(w http.ResponseWriter, operation string, res interface{}, err error, status int) {
+	if err != nil {
+		handler.logger.Errorw("service err, "+operation, "err", err)
+		writeScimError(w, err)
+		return
+	}
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	writeScimResp(w, res, status)
+}
+
+func getPagination(r *http.Request) (int, int, error) {
+	startIndex, count := 1, scim.DefaultPageSize
+	var err error
+	v := r.URL.Query()
+	if param := v.Get("startIndex"); len(param) > 0 {
+		startIndex, err = strconv.Atoi(param)
+		if err != nil {
+			return 0, 0, scim.NewError(http.StatusBadRequest, scim.ScimTypeInvalidValue, "invalid startIndex")
+		}
+	}
+	if param := v.Get("count"); len(param) > 0 {
+		count, err = strconv.Atoi(param)
+		if err != nil {
+			return 0, 0, scim.NewError(http.StatusBadRequest, scim.ScimTypeInvalidValue, "invalid count")
+		}
+	}
+	return startIndex, count, nil
+}
+
+func decodeScimRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeScimError(w, scim.NewError(http.StatusBadRequest, scim.ScimTypeInvalidValue, "invalid request body"))
+		return false
+	}
+	return true
+}
+
+func writeScimError(w http.ResponseWriter, err error) {
+	var scimErr *scim.Error
+	var apiErr *util.ApiError
+	switch {
+	case errors.As(err, &scimErr):
+	case errors.As(err, &apiErr) && apiErr.HttpStatusCode > 0:
+		scimErr = scim.NewError(apiErr.HttpStatusCode, "", fmt.Sprint(apiErr.UserMessage))
+	default:
+		scimErr = scim.NewError(http.StatusInternalServerError, "", err.Error())
+	}
+	writeScimResp(w, scimErr, scimErr.HttpStatusCode())
+}
+
+func writeScimResp(w http.ResponseWriter, res interface{}, status int) {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ScimRouter interface {
+	InitScimRouter(scimRouter *mux.Router)
+}
+
+type ScimRouterImpl struct {
+	scimRestHandler ScimRestHandler
+}
+
+func NewScimRouterImpl(scimRestHandler ScimRestHandler) *ScimRouterImpl {
+	return &ScimRouterImpl{scimRestHandler: scimRestHandler}
+}
+
+func (router ScimRouterImpl) InitScimRouter(scimRouter *mux.Router) {
+	scimRouter.Path("/ServiceProviderConfig").
+		HandlerFunc(router.scimRestHandler.GetServiceProviderConfig).Methods("GET")
+	scimRouter.Path("/ResourceTypes").
+		HandlerFunc(router.scimRestHandler.GetResourceTypes).Methods("GET")
+
+	scimRouter.Path("/Users").
+		HandlerFunc(router.scimRestHandler.ListUsers).Methods("GET")
+	scimRouter.Path("/Users").
+		HandlerFunc(router.scimRestHandler.CreateUser).Methods("POST")
+	scimRouter.Path("/Users/{id}").
+		HandlerFunc(router.scimRestHandler.GetUser).Methods("GET")
+	scimRouter.Path("/Users/{id}").
+		HandlerFunc(router.scimRestHandler.ReplaceUser).Methods("PUT")
+	scimRouter.Path("/Users/{id}").
+		HandlerFunc(router.scimRestHandler.PatchUser).Methods("PATCH")
+	scimRouter.Path("/Users/{id}").
+		HandlerFunc(router.scimRestHandler.DeleteUser).Methods("DELETE")
+
+	scimRouter.Path("/Groups").
+		HandlerFunc(router.scimRestHandler.ListGroups).Methods("GET")
+	scimRouter.Path("/Groups").
+		HandlerFunc(router.scimRestHandler.CreateGroup).Methods("POST")
+	scimRouter.Path("/Groups/{id}").
+		HandlerFunc(router.scimRestHandler.GetGroup).Methods("GET")
+	scimRouter.Path("/Groups/{id}").
+		HandlerFunc(router.scimRestHandler.ReplaceGroup).Methods("PUT")
+	scimRouter.Path("/Groups/{id}").
+		HandlerFunc(router.scimRestHandler.PatchGroup).Methods("PATCH")
+	scimRouter.Path("/Groups/{id}").
+		HandlerFunc(router.scimRestHandler.DeleteGroup).Methods("DELETE")
+}
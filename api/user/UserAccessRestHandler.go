@@ -0,0 +1,318 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type UserAccessRestHandler interface {
+	GrantAccess(w http.ResponseWriter, r *http.Request)
+	GetGrants(w http.ResponseWriter, r *http.Request)
+	RevokeGrant(w http.ResponseWriter, r *http.Request)
+	CreateAccessRequest(w http.ResponseWriter, r *http.Request)
+	GetAccessRequests(w http.ResponseWriter, r *http.Request)
+	CancelAccessRequest(w http.ResponseWriter, r *http.Request)
+	GetAccessRequestsForReview(w http.ResponseWriter, r *http.Request)
+	ReviewAccessRequest(w http.ResponseWriter, r *http.Request)
+	GetAuditTrail(w http.ResponseWriter, r *http.Request)
+}
+
+type UserAccessRestHandlerImpl struct {
+	logger                 *zap.SugaredLogger
+	validator              *validator.Validate
+	userService            user.UserService
+	userCommonService      user.UserCommonService
+	enforcer               casbin.Enforcer
+	userAccessGrantService user.UserAccessGrantService
+}
+
+func NewUserAccessRestHandlerImpl(logger *zap.SugaredLogger, validator *validator.Validate, userService user.UserService,
+	userCommonService user.UserCommonService, enforcer casbin.Enforcer, userAccessGrantService user.UserAccessGrantService) *UserAccessRestHandlerImpl {
+	return &UserAccessRestHandlerImpl{
+		logger:                 logger,
+		validator:              validator,
+		userService:            userService,
+		userCommonService:      userCommonService,
+		enforcer:               enforcer,
+		userAccessGrantService: userAccessGrantService,
+	}
+}
+
+func (handler UserAccessRestHandlerImpl) GrantAccess(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.AccessGrantRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, GrantAccess", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.GrantedBy = userId
+	handler.logger.Infow("request payload, GrantAccess", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, GrantAccess", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC is applied in service, per role filter and group
+	res, err := handler.userAccessGrantService.GrantAccess(&request, r.Header.Get("token"), handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, GrantAccess", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetGrants lists the access grants of a user, role filters of teams the caller can not view are left out unless the
+// grants are the caller's own
+func (handler UserAccessRestHandlerImpl) GetGrants(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	grantUserId := userId
+	if v := r.URL.Query().Get("userId"); len(v) > 0 {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		grantUserId = int32(id)
+	}
+	res, err := handler.userAccessGrantService.GetGrantsByUserId(grantUserId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetGrants", "err", err, "userId", grantUserId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if grantUserId == userId {
+		common.WriteJsonResp(w, nil, res, http.StatusOK)
+		return
+	}
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	grants := make([]*bean.AccessGrantDto, 0)
+	for _, grant := range res {
+		authPass := true
+		if filter := grant.RoleFilter; filter != nil {
+			if len(filter.Team) > 0 {
+				if ok := handler.enforcer.Enforce(token, casbin.ResourceUser, casbin.ActionGet, strings.ToLower(filter.Team)); !ok {
+					authPass = false
+				}
+			}
+			if filter.Entity == bean.CLUSTER_ENTITIY {
+				if ok := handler.userCommonService.CheckRbacForClusterEntity(filter.Cluster, filter.Namespace, filter.Group, filter.Kind, filter.Resource, token, handler.checkManagerAuth); !ok {
+					authPass = false
+				}
+			}
+		}
+		if authPass {
+			grants = append(grants, grant)
+		}
+	}
+	//RBAC enforcer Ends
+	common.WriteJsonResp(w, nil, grants, http.StatusOK)
+}
+
+func (handler UserAccessRestHandlerImpl) RevokeGrant(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC is applied in service as per the role or group of the grant
+	err = handler.userAccessGrantService.RevokeGrant(id, userId, r.Header.Get("token"), handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, RevokeGrant", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+// CreateAccessRequest is used by a user to ask access for self, any user can raise a request
+func (handler UserAccessRestHandlerImpl) CreateAccessRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.AccessRequestDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateAccessRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, CreateAccessRequest", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateAccessRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.userAccessGrantService.CreateAccessRequest(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateAccessRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserAccessRestHandlerImpl) GetAccessRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	res, err := handler.userAccessGrantService.GetAccessRequestsByUserId(userId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessRequests", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserAccessRestHandlerImpl) CancelAccessRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.userAccessGrantService.CancelAccessRequest(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelAccessRequest", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler UserAccessRestHandlerImpl) GetAccessRequestsForReview(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC is applied in service, only the requests the user can approve are listed
+	res, err := handler.userAccessGrantService.GetAccessRequestsForReview(userId, r.Header.Get("token"), handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessRequestsForReview", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserAccessRestHandlerImpl) ReviewAccessRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var review bean.AccessRequestReviewDto
+	err = json.NewDecoder(r.Body).Decode(&review)
+	if err != nil {
+		handler.logger.Errorw("request err, ReviewAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	review.ReviewedBy = userId
+	handler.logger.Infow("request payload, ReviewAccessRequest", "payload", review)
+	err = handler.validator.Struct(review)
+	if err != nil {
+		handler.logger.Errorw("validation err, ReviewAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC is applied in service, the reviewer needs the access required to grant the request
+	res, err := handler.userAccessGrantService.ReviewAccessRequest(&review, r.Header.Get("token"), handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, ReviewAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetAuditTrail lists who granted, requested and revoked what. Super admins can see the trail of any user, or of all
+// users when userId is not given, other users only their own
+func (handler UserAccessRestHandlerImpl) GetAuditTrail(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	v := r.URL.Query()
+	auditUserId, offset, size := 0, 0, 20
+	if userIdParam := v.Get("userId"); len(userIdParam) > 0 {
+		auditUserId, err = strconv.Atoi(userIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if offsetParam := v.Get("offset"); len(offsetParam) > 0 {
+		offset, err = strconv.Atoi(offsetParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if sizeParam := v.Get("size"); len(sizeParam) > 0 {
+		size, err = strconv.Atoi(sizeParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if int32(auditUserId) != userId {
+		isSuperAdmin, err := handler.userService.IsSuperAdmin(int(userId))
+		if err != nil || !isSuperAdmin {
+			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+			return
+		}
+	}
+	res, err := handler.userAccessGrantService.GetAuditTrail(int32(auditUserId), offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAuditTrail", "err", err, "userId", auditUserId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserAccessRestHandlerImpl) checkManagerAuth(resource, token string, object string) bool {
+	return handler.enforcer.Enforce(token, resource, casbin.ActionUpdate, strings.ToLower(object))
+}
@@ -26,12 +26,17 @@ type UserRouter interface {
 }
 
 type UserRouterImpl struct {
-	userRestHandler UserRestHandler
+	userRestHandler        UserRestHandler
+	userAccessRestHandler  UserAccessRestHandler
+	rbacExplainRestHandler RbacExplainRestHandler
 }
 
-func NewUserRouterImpl(userRestHandler UserRestHandler) *UserRouterImpl {
+func NewUserRouterImpl(userRestHandler UserRestHandler, userAccessRestHandler UserAccessRestHandler,
+	rbacExplainRestHandler RbacExplainRestHandler) *UserRouterImpl {
 	router := &UserRouterImpl{
-		userRestHandler: userRestHandler,
+		userRestHandler:        userRestHandler,
+		userAccessRestHandler:  userAccessRestHandler,
+		rbacExplainRestHandler: rbacExplainRestHandler,
 	}
 	return router
 }
@@ -49,6 +54,34 @@ func (router UserRouterImpl) InitUserRouter(userAuthRouter *mux.Router) {
 	userAuthRouter.Path("/{id}").
 		HandlerFunc(router.userRestHandler.DeleteUser).Methods("DELETE")
 
+	//time bound access, granted by managers or on approval of access requests
+	userAuthRouter.Path("/access/grant").
+		HandlerFunc(router.userAccessRestHandler.GrantAccess).Methods("POST")
+	userAuthRouter.Path("/access/grant").
+		HandlerFunc(router.userAccessRestHandler.GetGrants).Methods("GET")
+	userAuthRouter.Path("/access/grant/{id}").
+		HandlerFunc(router.userAccessRestHandler.RevokeGrant).Methods("DELETE")
+	userAuthRouter.Path("/access/request").
+		HandlerFunc(router.userAccessRestHandler.CreateAccessRequest).Methods("POST")
+	userAuthRouter.Path("/access/request").
+		HandlerFunc(router.userAccessRestHandler.GetAccessRequests).Methods("GET")
+	userAuthRouter.Path("/access/request/review").
+		HandlerFunc(router.userAccessRestHandler.GetAccessRequestsForReview).Methods("GET")
+	userAuthRouter.Path("/access/request/review").
+		HandlerFunc(router.userAccessRestHandler.ReviewAccessRequest).Methods("PUT")
+	userAuthRouter.Path("/access/request/{id}").
+		HandlerFunc(router.userAccessRestHandler.CancelAccessRequest).Methods("DELETE")
+	userAuthRouter.Path("/access/audit").
+		HandlerFunc(router.userAccessRestHandler.GetAuditTrail).Methods("GET")
+
+	//explanation of rbac decisions, effective permissions and reverse lookups for access reviews
+	userAuthRouter.Path("/rbac/explain").
+		HandlerFunc(router.rbacExplainRestHandler.Explain).Methods("POST")
+	userAuthRouter.Path("/rbac/permissions").
+		HandlerFunc(router.rbacExplainRestHandler.GetEffectivePermissions).Methods("GET")
+	userAuthRouter.Path("/rbac/access-review").
+		HandlerFunc(router.rbacExplainRestHandler.GetUsersWithAccess).Methods("POST")
+
 	userAuthRouter.Path("/detail/get").
 		HandlerFunc(router.userRestHandler.GetAllDetailedUsers).Methods("GET")
 
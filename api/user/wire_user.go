@@ -5,6 +5,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/devtron-labs/devtron/pkg/user/scim"
 	"github.com/google/wire"
 )
 
@@ -46,6 +47,27 @@ var UserWireSet = wire.NewSet(
 	user.NewUserCommonServiceImpl,
 	wire.Bind(new(user.UserCommonService), new(*user.UserCommonServiceImpl)),
 
+	NewUserAccessRestHandlerImpl,
+	wire.Bind(new(UserAccessRestHandler), new(*UserAccessRestHandlerImpl)),
+	user.NewUserAccessGrantServiceImpl,
+	wire.Bind(new(user.UserAccessGrantService), new(*user.UserAccessGrantServiceImpl)),
+	repository.NewUserAccessGrantRepositoryImpl,
+	wire.Bind(new(repository.UserAccessGrantRepository), new(*repository.UserAccessGrantRepositoryImpl)),
+
+	NewRbacExplainRestHandlerImpl,
+	wire.Bind(new(RbacExplainRestHandler), new(*RbacExplainRestHandlerImpl)),
+	user.NewRbacExplainServiceImpl,
+	wire.Bind(new(user.RbacExplainService), new(*user.RbacExplainServiceImpl)),
+
+	NewScimRouterImpl,
+	wire.Bind(new(ScimRouter), new(*ScimRouterImpl)),
+	NewScimRestHandlerImpl,
+	wire.Bind(new(ScimRestHandler), new(*ScimRestHandlerImpl)),
+	scim.NewScimServiceImpl,
+	wire.Bind(new(scim.ScimService), new(*scim.ScimServiceImpl)),
+	repository.NewScimResourceRepositoryImpl,
+	wire.Bind(new(repository.ScimResourceRepository), new(*repository.ScimResourceRepositoryImpl)),
+
 	auth.NewUserAuthOidcHelperImpl,
 	wire.Bind(new(auth.UserAuthOidcHelper), new(*auth.UserAuthOidcHelperImpl)),
 
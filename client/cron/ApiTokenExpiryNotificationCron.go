@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type ApiTokenExpiryNotificationCron interface {
+	NotifyExpiringApiTokens()
+}
+
+type ApiTokenExpiryNotificationCronImpl struct {
+	logger                            *zap.SugaredLogger
+	cron                              *cron.Cron
+	apiTokenExpiryNotificationService apiToken.ApiTokenExpiryNotificationService
+}
+
+func NewApiTokenExpiryNotificationCronImpl(logger *zap.SugaredLogger, apiTokenExpiryNotificationCronConfig *ApiTokenExpiryNotificationCronConfig,
+	apiTokenExpiryNotificationService apiToken.ApiTokenExpiryNotificationService) *ApiTokenExpiryNotificationCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &ApiTokenExpiryNotificationCronImpl{
+		logger:                            logger,
+		cron:                              cron,
+		apiTokenExpiryNotificationService: apiTokenExpiryNotificationService,
+	}
+
+	// execute periodically, notify the channels configured on api tokens nearing expiry
+	_, err := cron.AddFunc(apiTokenExpiryNotificationCronConfig.ApiTokenExpiryNotificationCron, impl.NotifyExpiringApiTokens)
+	if err != nil {
+		logger.Errorw("error while configure cron job for api token expiry notification", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type ApiTokenExpiryNotificationCronConfig struct {
+	ApiTokenExpiryNotificationCron string `env:"API_TOKEN_EXPIRY_NOTIFICATION_CRON" envDefault:"0 * * * *"`
+}
+
+func GetApiTokenExpiryNotificationCronConfig() (*ApiTokenExpiryNotificationCronConfig, error) {
+	cfg := &ApiTokenExpiryNotificationCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse api token expiry notification cron config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// NotifyExpiringApiTokens this function will execute periodically
+func (impl *ApiTokenExpiryNotificationCronImpl) NotifyExpiringApiTokens() {
+	impl.apiTokenExpiryNotificationService.NotifyExpiringApiTokens()
+}
@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/appSync"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type AppSyncCron interface {
+	SyncApps()
+}
+
+type AppSyncCronImpl struct {
+	logger         *zap.SugaredLogger
+	cron           *cron.Cron
+	appSyncService appSync.AppSyncService
+}
+
+func NewAppSyncCronImpl(logger *zap.SugaredLogger, appSyncConfig *AppSyncConfig,
+	appSyncService appSync.AppSyncService) *AppSyncCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &AppSyncCronImpl{
+		logger:         logger,
+		cron:           cron,
+		appSyncService: appSyncService,
+	}
+	if !appSyncConfig.AppSyncEnabled {
+		return impl
+	}
+	// execute periodically, reconcile apps with the git repositories of app sync sources
+	_, err := cron.AddFunc(appSyncConfig.AppSyncCron, impl.SyncApps)
+	if err != nil {
+		logger.Errorw("error while configure cron job for app sync", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type AppSyncConfig struct {
+	AppSyncEnabled bool   `env:"APP_SYNC_ENABLED" envDefault:"false"`
+	AppSyncCron    string `env:"APP_SYNC_CRON" envDefault:"*/5 * * * *"`
+}
+
+func GetAppSyncConfig() (*AppSyncConfig, error) {
+	cfg := &AppSyncConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse app sync config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SyncApps this function will execute periodically
+func (impl *AppSyncCronImpl) SyncApps() {
+	impl.appSyncService.SyncAllSources()
+}
@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type BuildCacheRetentionCron interface {
+	ApplyBuildCacheRetention()
+}
+
+type BuildCacheRetentionCronImpl struct {
+	logger              *zap.SugaredLogger
+	cron                *cron.Cron
+	ciBuildCacheService pipeline.CiBuildCacheService
+}
+
+func NewBuildCacheRetentionCronImpl(logger *zap.SugaredLogger, buildCacheRetentionConfig *BuildCacheRetentionConfig,
+	ciBuildCacheService pipeline.CiBuildCacheService) *BuildCacheRetentionCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &BuildCacheRetentionCronImpl{
+		logger:              logger,
+		cron:                cron,
+		ciBuildCacheService: ciBuildCacheService,
+	}
+
+	// execute periodically, expire build caches as per the retention policy
+	_, err := cron.AddFunc(buildCacheRetentionConfig.BuildCacheRetentionCron, impl.ApplyBuildCacheRetention)
+	if err != nil {
+		logger.Errorw("error while configure cron job for build cache retention", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type BuildCacheRetentionConfig struct {
+	BuildCacheRetentionCron string `env:"BUILD_CACHE_RETENTION_CRON" envDefault:"0 * * * *"`
+}
+
+func GetBuildCacheRetentionConfig() (*BuildCacheRetentionConfig, error) {
+	cfg := &BuildCacheRetentionConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse build cache retention config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ApplyBuildCacheRetention this function will execute periodically
+func (impl *BuildCacheRetentionCronImpl) ApplyBuildCacheRetention() {
+	impl.ciBuildCacheService.ApplyRetentionPolicy()
+}
@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type CanaryAnalysisCron interface {
+	EvaluateCanaryAnalysis()
+}
+
+type CanaryAnalysisCronImpl struct {
+	logger              *zap.SugaredLogger
+	cron                *cron.Cron
+	workflowDagExecutor pipeline.WorkflowDagExecutor
+}
+
+func NewCanaryAnalysisCronImpl(logger *zap.SugaredLogger, canaryAnalysisConfig *CanaryAnalysisConfig,
+	workflowDagExecutor pipeline.WorkflowDagExecutor) *CanaryAnalysisCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &CanaryAnalysisCronImpl{
+		logger:              logger,
+		cron:                cron,
+		workflowDagExecutor: workflowDagExecutor,
+	}
+
+	// execute periodically, evaluate due canary analysis runs and roll back failed ones
+	_, err := cron.AddFunc(canaryAnalysisConfig.CanaryAnalysisPollCron, impl.EvaluateCanaryAnalysis)
+	if err != nil {
+		logger.Errorw("error while configure cron job for canary analysis", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type CanaryAnalysisConfig struct {
+	CanaryAnalysisPollCron string `env:"CANARY_ANALYSIS_POLL_CRON" envDefault:"* * * * *"`
+}
+
+func GetCanaryAnalysisConfig() (*CanaryAnalysisConfig, error) {
+	cfg := &CanaryAnalysisConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse canary analysis config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// EvaluateCanaryAnalysis this function will execute periodically
+func (impl *CanaryAnalysisCronImpl) EvaluateCanaryAnalysis() {
+	impl.workflowDagExecutor.ProcessCanaryAnalysis()
+}
@@ -0,0 +1,62 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CiPipelineScheduleCron interface {
+	TriggerDueSchedules()
+}
+
+type CiPipelineScheduleCronImpl struct {
+	logger                    *zap.SugaredLogger
+	cron                      *cron.Cron
+	ciPipelineScheduleConfig  *CiPipelineScheduleConfig
+	ciPipelineScheduleService pipeline.CiPipelineScheduleService
+}
+
+func NewCiPipelineScheduleCronImpl(logger *zap.SugaredLogger, ciPipelineScheduleConfig *CiPipelineScheduleConfig,
+	ciPipelineScheduleService pipeline.CiPipelineScheduleService) *CiPipelineScheduleCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &CiPipelineScheduleCronImpl{
+		logger:                    logger,
+		cron:                      cron,
+		ciPipelineScheduleConfig:  ciPipelineScheduleConfig,
+		ciPipelineScheduleService: ciPipelineScheduleService,
+	}
+
+	// execute periodically, trigger ci pipelines whose schedule is due
+	_, err := cron.AddFunc(ciPipelineScheduleConfig.CiPipelineSchedulePollCron, impl.TriggerDueSchedules)
+	if err != nil {
+		logger.Errorw("error while configure cron job for ci pipeline schedules", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type CiPipelineScheduleConfig struct {
+	CiPipelineSchedulePollCron string `env:"CI_PIPELINE_SCHEDULE_POLL_CRON" envDefault:"* * * * *"`
+	MissedRunGrace             int    `env:"CI_PIPELINE_SCHEDULE_MISSED_RUN_GRACE" envDefault:"5"` //in minutes
+}
+
+func GetCiPipelineScheduleConfig() (*CiPipelineScheduleConfig, error) {
+	cfg := &CiPipelineScheduleConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse ci pipeline schedule config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// TriggerDueSchedules this function will execute periodically
+func (impl *CiPipelineScheduleCronImpl) TriggerDueSchedules() {
+	impl.ciPipelineScheduleService.ProcessDueSchedules(time.Duration(impl.ciPipelineScheduleConfig.MissedRunGrace) * time.Minute)
+}
@@ -0,0 +1,80 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/drift"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// the scan runs only on the replica holding this lease so that a drift is notified once, replicas fire the cron
+	// at the same time and the ones not getting the lease skip that run
+	CONFIG_DRIFT_SCAN_LEASE     = "config-drift-scan"
+	CONFIG_DRIFT_SCAN_LEASE_TTL = 10 * time.Minute
+)
+
+type ConfigDriftScanCron interface {
+	ScanLiveDrift()
+}
+
+type ConfigDriftScanCronImpl struct {
+	logger             *zap.SugaredLogger
+	cron               *cron.Cron
+	configDriftService drift.ConfigDriftService
+	leaseRepository    repository.SchedulerLeaseRepository
+}
+
+func NewConfigDriftScanCronImpl(logger *zap.SugaredLogger, configDriftScanConfig *ConfigDriftScanConfig,
+	configDriftService drift.ConfigDriftService, leaseRepository repository.SchedulerLeaseRepository) *ConfigDriftScanCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &ConfigDriftScanCronImpl{
+		logger:             logger,
+		cron:               cron,
+		configDriftService: configDriftService,
+		leaseRepository:    leaseRepository,
+	}
+	if !configDriftScanConfig.ConfigDriftScanEnabled {
+		return impl
+	}
+	// execute periodically, compare deployed manifests with live resources and notify drift
+	_, err := cron.AddFunc(configDriftScanConfig.ConfigDriftScanCron, impl.ScanLiveDrift)
+	if err != nil {
+		logger.Errorw("error while configure cron job for config drift scan", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type ConfigDriftScanConfig struct {
+	ConfigDriftScanEnabled bool   `env:"CONFIG_DRIFT_SCAN_ENABLED" envDefault:"false"`
+	ConfigDriftScanCron    string `env:"CONFIG_DRIFT_SCAN_CRON" envDefault:"*/30 * * * *"`
+}
+
+func GetConfigDriftScanConfig() (*ConfigDriftScanConfig, error) {
+	cfg := &ConfigDriftScanConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse config drift scan config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ScanLiveDrift this function will execute periodically
+func (impl *ConfigDriftScanCronImpl) ScanLiveDrift() {
+	acquired, err := impl.leaseRepository.TryAcquire(CONFIG_DRIFT_SCAN_LEASE, CONFIG_DRIFT_SCAN_LEASE_TTL)
+	if err != nil {
+		impl.logger.Errorw("error in acquiring config drift scan lease", "err", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	impl.configDriftService.ScanLiveDrift()
+}
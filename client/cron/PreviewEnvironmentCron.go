@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type PreviewEnvironmentCron interface {
+	CleanupPreviewEnvironments()
+}
+
+type PreviewEnvironmentCronImpl struct {
+	logger                    *zap.SugaredLogger
+	cron                      *cron.Cron
+	previewEnvironmentService pipeline.PreviewEnvironmentService
+}
+
+func NewPreviewEnvironmentCronImpl(logger *zap.SugaredLogger, previewEnvironmentCronConfig *PreviewEnvironmentCronConfig,
+	previewEnvironmentService pipeline.PreviewEnvironmentService) *PreviewEnvironmentCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &PreviewEnvironmentCronImpl{
+		logger:                    logger,
+		cron:                      cron,
+		previewEnvironmentService: previewEnvironmentService,
+	}
+
+	// execute periodically, tear down preview environments past their ttl
+	_, err := cron.AddFunc(previewEnvironmentCronConfig.PreviewEnvironmentCleanupCron, impl.CleanupPreviewEnvironments)
+	if err != nil {
+		logger.Errorw("error while configure cron job for preview environment cleanup", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type PreviewEnvironmentCronConfig struct {
+	PreviewEnvironmentCleanupCron string `env:"PREVIEW_ENVIRONMENT_CLEANUP_CRON" envDefault:"*/5 * * * *"`
+}
+
+func GetPreviewEnvironmentCronConfig() (*PreviewEnvironmentCronConfig, error) {
+	cfg := &PreviewEnvironmentCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse preview environment cron config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// CleanupPreviewEnvironments this function will execute periodically
+func (impl *PreviewEnvironmentCronImpl) CleanupPreviewEnvironments() {
+	impl.previewEnvironmentService.DeleteExpiredPreviewEnvironments()
+}
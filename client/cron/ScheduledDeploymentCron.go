@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type ScheduledDeploymentCron interface {
+	TriggerDueDeployments()
+}
+
+type ScheduledDeploymentCronImpl struct {
+	logger              *zap.SugaredLogger
+	cron                *cron.Cron
+	workflowDagExecutor pipeline.WorkflowDagExecutor
+}
+
+func NewScheduledDeploymentCronImpl(logger *zap.SugaredLogger, scheduledDeploymentConfig *ScheduledDeploymentConfig,
+	workflowDagExecutor pipeline.WorkflowDagExecutor) *ScheduledDeploymentCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &ScheduledDeploymentCronImpl{
+		logger:              logger,
+		cron:                cron,
+		workflowDagExecutor: workflowDagExecutor,
+	}
+
+	// execute periodically, trigger scheduled and queued deployments which are due
+	_, err := cron.AddFunc(scheduledDeploymentConfig.ScheduledDeploymentPollCron, impl.TriggerDueDeployments)
+	if err != nil {
+		logger.Errorw("error while configure cron job for scheduled deployments", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type ScheduledDeploymentConfig struct {
+	ScheduledDeploymentPollCron string `env:"SCHEDULED_DEPLOYMENT_POLL_CRON" envDefault:"* * * * *"`
+}
+
+func GetScheduledDeploymentConfig() (*ScheduledDeploymentConfig, error) {
+	cfg := &ScheduledDeploymentConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse scheduled deployment config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// TriggerDueDeployments this function will execute periodically
+func (impl *ScheduledDeploymentCronImpl) TriggerDueDeployments() {
+	impl.workflowDagExecutor.TriggerDueScheduledDeployments()
+}
@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type UserAccessGrantCron interface {
+	ProcessDueGrants()
+}
+
+type UserAccessGrantCronImpl struct {
+	logger                 *zap.SugaredLogger
+	cron                   *cron.Cron
+	userAccessGrantService user.UserAccessGrantService
+}
+
+func NewUserAccessGrantCronImpl(logger *zap.SugaredLogger, userAccessGrantCronConfig *UserAccessGrantCronConfig,
+	userAccessGrantService user.UserAccessGrantService) *UserAccessGrantCronImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &UserAccessGrantCronImpl{
+		logger:                 logger,
+		cron:                   cron,
+		userAccessGrantService: userAccessGrantService,
+	}
+
+	// execute periodically, start scheduled access grants and revoke the expired ones
+	_, err := cron.AddFunc(userAccessGrantCronConfig.UserAccessGrantCron, impl.ProcessDueGrants)
+	if err != nil {
+		logger.Errorw("error while configure cron job for user access grants", "err", err)
+		return impl
+	}
+	return impl
+}
+
+type UserAccessGrantCronConfig struct {
+	UserAccessGrantCron string `env:"USER_ACCESS_GRANT_CRON" envDefault:"* * * * *"`
+}
+
+func GetUserAccessGrantCronConfig() (*UserAccessGrantCronConfig, error) {
+	cfg := &UserAccessGrantCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse user access grant cron config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ProcessDueGrants this function will execute periodically
+func (impl *UserAccessGrantCronImpl) ProcessDueGrants() {
+	impl.userAccessGrantService.ProcessDueGrants()
+}
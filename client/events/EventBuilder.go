@@ -38,6 +38,7 @@ type EventFactory interface {
 	Build(eventType util.EventType, sourceId *int, appId int, envId *int, pipelineType util.PipelineType) Event
 	BuildExtraCDData(event Event, wfr *pipelineConfig.CdWorkflowRunner, pipelineOverrideId int, stage bean2.WorkflowType) Event
 	BuildExtraCIData(event Event, material *MaterialTriggerInfo, dockerImage string) Event
+	BuildExtraApprovalData(event Event, ciArtifactId int, requestedBy int32, comment string) Event
 	//BuildFinalData(event Event) *Payload
 }
 
@@ -197,6 +198,30 @@ func (impl *EventSimpleFactoryImpl) BuildExtraCIData(event Event, material *Mate
 	return event
 }
 
+func (impl *EventSimpleFactoryImpl) BuildExtraApprovalData(event Event, ciArtifactId int, requestedBy int32, comment string) Event {
+	payload := event.Payload
+	if payload == nil {
+		payload = &Payload{}
+	}
+	event.CiArtifactId = ciArtifactId
+	event.UserId = int(requestedBy)
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("found error on payload build for approval, skipping this error ", "ciArtifactId", ciArtifactId, "err", err)
+	} else {
+		payload.DockerImageUrl = artifact.Image
+	}
+	user, err := impl.userRepository.GetById(requestedBy)
+	if err != nil {
+		impl.logger.Errorw("found error on payload build for approval, skipping this error ", "userId", requestedBy, "err", err)
+	} else {
+		payload.TriggeredBy = user.EmailId
+	}
+	payload.ApprovalComment = comment
+	event.Payload = payload
+	return event
+}
+
 func (impl *EventSimpleFactoryImpl) getCiMaterialInfo(ciPipelineId int, ciArtifactId int) (*MaterialTriggerInfo, error) {
 	materialTriggerInfo := &MaterialTriggerInfo{}
 	if ciPipelineId > 0 {
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/pkg/module"
+	"github.com/devtron-labs/devtron/pkg/notifier"
 	"net/http"
 	"time"
 
@@ -90,6 +91,9 @@ type Payload struct {
 	BuildHistoryLink      string               `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *MaterialTriggerInfo `json:"material"`
 	FailureReason         string               `json:"failureReason"`
+	ImageApprovalLink     string               `json:"imageApprovalLink,omitempty"`
+	ApprovalComment       string               `json:"approvalComment,omitempty"`
+	DriftSummary          string               `json:"driftSummary,omitempty"`
 }
 
 type CiPipelineMaterialResponse struct {
@@ -123,14 +127,18 @@ type EventRESTClientImpl struct {
 	pipelineRepository   pipelineConfig.PipelineRepository
 	attributesRepository repository.AttributesRepository
 	moduleService        module.ModuleService
+	//delivers to channels (teams, discord, pagerduty...) which are not handled by the notifier service
+	notificationChannelService notifier.NotificationChannelService
 }
 
 func NewEventRESTClientImpl(logger *zap.SugaredLogger, client *http.Client, config *EventClientConfig, pubsubClient *pubsub.PubSubClientServiceImpl,
 	ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository,
-	attributesRepository repository.AttributesRepository, moduleService module.ModuleService) *EventRESTClientImpl {
+	attributesRepository repository.AttributesRepository, moduleService module.ModuleService,
+	notificationChannelService notifier.NotificationChannelService) *EventRESTClientImpl {
 	return &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
 		ciPipelineRepository: ciPipelineRepository, pipelineRepository: pipelineRepository,
-		attributesRepository: attributesRepository, moduleService: moduleService}
+		attributesRepository: attributesRepository, moduleService: moduleService,
+		notificationChannelService: notificationChannelService}
 }
 
 func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipelineConfig.Pipeline, ciPipeline *pipelineConfig.CiPipeline) *Payload {
@@ -147,6 +155,9 @@ func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipe
 		payload.Stage = string(event.CdWorkflowType)
 		payload.DeploymentHistoryLink = fmt.Sprintf("/dashboard/app/%d/cd-details/%d/%d/%d/source-code", event.AppId, event.EnvId, event.PipelineId, event.CdWorkflowRunnerId)
 		payload.AppDetailLink = fmt.Sprintf("/dashboard/app/%d/details/%d/pod", event.AppId, event.EnvId)
+		if event.EventTypeId == int(util.Approval) {
+			payload.ImageApprovalLink = fmt.Sprintf("/dashboard/app/%d/trigger?approval-node=%d", event.AppId, event.PipelineId)
+		}
 		if event.CdWorkflowType != bean.CD_WORKFLOW_TYPE_DEPLOY {
 			payload.DownloadLink = fmt.Sprintf("/orchestrator/app/cd-pipeline/workflow/download/%d/%d/%d/%d", event.AppId, event.EnvId, event.PipelineId, event.CdWorkflowRunnerId)
 		}
@@ -161,15 +172,16 @@ func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipe
 }
 
 func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, error) {
-	// if notification integration is not installed then do not send the notification
+	// if notification integration is not installed then only the channels delivered from here (teams, discord,
+	// pagerduty...) get the notification, the notifier service is skipped
 	moduleInfo, err := impl.moduleService.GetModuleInfo(module.ModuleNameNotification)
 	if err != nil {
 		impl.logger.Errorw("error while getting notification module status", "err", err)
 		return false, err
 	}
-	if moduleInfo.Status != module.ModuleStatusInstalled {
-		impl.logger.Warnw("Notification module is not installed, hence skipping sending notification", "currentModuleStatus", moduleInfo.Status)
-		return false, nil
+	notifierInstalled := moduleInfo.Status == module.ModuleStatusInstalled
+	if !notifierInstalled {
+		impl.logger.Warnw("Notification module is not installed, hence sending notification only to notification channels", "currentModuleStatus", moduleInfo.Status)
 	}
 
 	var cdPipeline *pipelineConfig.Pipeline
@@ -217,12 +229,12 @@ func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, erro
 		event.BaseUrl = attribute.Value
 	}
 	if event.CdWorkflowType == "" {
-		_, err = impl.sendEvent(event)
+		_, err = impl.sendEvent(event, notifierInstalled)
 	} else if event.CdWorkflowType == bean.CD_WORKFLOW_TYPE_PRE {
 		if event.EventTypeId == int(util.Success) {
 			impl.logger.Debug("skip - will send from deployment or post stage")
 		} else {
-			_, err = impl.sendEvent(event)
+			_, err = impl.sendEvent(event, notifierInstalled)
 		}
 	} else if event.CdWorkflowType == bean.CD_WORKFLOW_TYPE_DEPLOY {
 		if isPreStageExist && event.EventTypeId == int(util.Trigger) {
@@ -230,21 +242,25 @@ func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, erro
 		} else if isPostStageExist && event.EventTypeId == int(util.Success) {
 			impl.logger.Debug("skip - will send from post stage")
 		} else {
-			_, err = impl.sendEvent(event)
+			_, err = impl.sendEvent(event, notifierInstalled)
 		}
 	} else if event.CdWorkflowType == bean.CD_WORKFLOW_TYPE_POST {
 		if event.EventTypeId == int(util.Trigger) {
 			impl.logger.Debug("skip - already sent from pre or deployment stage")
 		} else {
-			_, err = impl.sendEvent(event)
+			_, err = impl.sendEvent(event, notifierInstalled)
 		}
 	}
-	return true, err
+	return notifierInstalled, err
 }
 
-// do not call this method if notification module is not installed
-func (impl *EventRESTClientImpl) sendEvent(event Event) (bool, error) {
+// sendEvent posts to the notifier service only if notification module is installed
+func (impl *EventRESTClientImpl) sendEvent(event Event, notifierInstalled bool) (bool, error) {
 	impl.logger.Debugw("event before send", "event", event)
+	impl.sendToNotificationChannels(event)
+	if !notifierInstalled {
+		return false, nil
+	}
 	body, err := json.Marshal(event)
 	if err != nil {
 		impl.logger.Errorw("error while marshaling event request ", "err", err)
@@ -266,6 +282,59 @@ func (impl *EventRESTClientImpl) sendEvent(event Event) (bool, error) {
 	return true, err
 }
 
+// sendToNotificationChannels is best effort and asynchronous, a failing channel must not block the notifier service
+func (impl *EventRESTClientImpl) sendToNotificationChannels(event Event) {
+	if impl.notificationChannelService == nil {
+		return
+	}
+	channelEvent := &notifier.NotificationChannelEvent{
+		EventTypeId:  event.EventTypeId,
+		PipelineType: util.PipelineType(event.PipelineType),
+		PipelineId:   event.PipelineId,
+		TeamId:       event.TeamId,
+		AppId:        event.AppId,
+		EnvId:        event.EnvId,
+		Message:      buildNotificationChannelMessage(event),
+	}
+	// providers are slow external endpoints, delivery happens in background so that pipeline status updates do not wait
+	go func() {
+		err := impl.notificationChannelService.SendNotification(channelEvent)
+		if err != nil {
+			impl.logger.Errorw("error in sending event to notification channels", "err", err, "pipelineId", event.PipelineId, "eventTypeId", event.EventTypeId)
+		}
+	}()
+}
+
+func buildNotificationChannelMessage(event Event) *notifier.NotificationChannelMessage {
+	message := &notifier.NotificationChannelMessage{
+		EventType:    util.EventType(event.EventTypeId),
+		PipelineType: util.PipelineType(event.PipelineType),
+		PipelineId:   event.PipelineId,
+	}
+	if event.Payload == nil {
+		return message
+	}
+	message.AppName = event.Payload.AppName
+	message.EnvName = event.Payload.EnvName
+	message.PipelineName = event.Payload.PipelineName
+	message.Stage = event.Payload.Stage
+	message.TriggeredBy = event.Payload.TriggeredBy
+	message.FailureReason = event.Payload.FailureReason
+	message.DriftSummary = event.Payload.DriftSummary
+	link := event.Payload.DeploymentHistoryLink
+	if len(event.Payload.ImageApprovalLink) > 0 {
+		link = event.Payload.ImageApprovalLink
+	} else if event.EventTypeId == int(util.ConfigDrift) {
+		link = event.Payload.AppDetailLink
+	} else if event.PipelineType == string(util.CI) {
+		link = event.Payload.BuildHistoryLink
+	}
+	if len(link) > 0 {
+		message.Link = event.BaseUrl + link
+	}
+	return message
+}
+
 func (impl *EventRESTClientImpl) WriteNatsEvent(topic string, payload interface{}) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
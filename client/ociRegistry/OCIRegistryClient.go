@@ -0,0 +1,431 @@
+package ociRegistry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	"go.uber.org/zap"
+)
+
+const (
+	OCIManifestMediaType        = "application/vnd.oci.image.manifest.v1+json"
+	HelmChartConfigMediaType    = "application/vnd.cncf.helm.config.v1+json"
+	HelmChartContentMediaType   = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	legacyChartContentMediaType = "application/tar+gzip"
+	OCIImageIndexMediaType      = "application/vnd.oci.image.index.v1+json"
+	DockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	DockerManifestMediaType     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+type OCIRegistryClientConfig struct {
+	Timeout int `env:"OCI_REGISTRY_REQUEST_TIMEOUT" envDefault:"120"` // in seconds
+}
+
+func GetOCIRegistryClientConfig() (*OCIRegistryClientConfig, error) {
+	cfg := &OCIRegistryClientConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+// RegistryCredential identifies a registry, RegistryUrl may carry a scheme (http for insecure registries, https by default)
+// and a path prefix which is prepended to every repository
+type RegistryCredential struct {
+	RegistryUrl string
+	Username    string
+	Password    string
+}
+
+// OCIRegistryClient pushes and pulls helm charts using the OCI distribution api
+type OCIRegistryClient interface {
+	// PushHelmChart uploads the chart archive with its config and tags the manifest, returns the manifest digest
+	PushHelmChart(credential *RegistryCredential, repository string, tag string, chartConfig []byte, chartArchive []byte) (string, error)
+	// PullHelmChart returns the gzipped chart archive tagged with tag
+	PullHelmChart(credential *RegistryCredential, repository string, tag string) ([]byte, error)
+	// GetImagePlatforms returns the os/arch platforms of an image, read from the manifest list of multi-arch images
+	// and from the image config of single-arch images. reference is a tag or a digest
+	GetImagePlatforms(credential *RegistryCredential, repository string, reference string) ([]string, error)
+}
+
+type OCIRegistryClientImpl struct {
+	logger     *zap.SugaredLogger
+	httpClient *http.Client
+}
+
+func NewOCIRegistryClientImpl(logger *zap.SugaredLogger, config *OCIRegistryClientConfig) *OCIRegistryClientImpl {
+	return &OCIRegistryClientImpl{
+		logger:     logger,
+		httpClient: &http.Client{Timeout: time.Duration(config.Timeout) * time.Second},
+	}
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int    `json:"size"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+type ociPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ociImageManifest is an image manifest or, when Manifests is set, an index of the image manifests per platform
+type ociImageManifest struct {
+	MediaType string        `json:"mediaType,omitempty"`
+	Config    ociDescriptor `json:"config"`
+	Manifests []struct {
+		Digest   string       `json:"digest"`
+		Platform *ociPlatform `json:"platform,omitempty"`
+	} `json:"manifests"`
+}
+
+func (platform *ociPlatform) String() string {
+	if len(platform.Variant) > 0 {
+		return fmt.Sprintf("%s/%s/%s", platform.OS, platform.Architecture, platform.Variant)
+	}
+	return fmt.Sprintf("%s/%s", platform.OS, platform.Architecture)
+}
+
+// registrySession holds the endpoint of a repository and the authorization negotiated with the registry
+type registrySession struct {
+	client        *http.Client
+	credential    *RegistryCredential
+	baseUrl       string
+	repository    string
+	scope         string
+	authorization string
+}
+
+func (impl *OCIRegistryClientImpl) newSession(credential *RegistryCredential, repository string, actions string) (*registrySession, error) {
+	registryUrl := credential.RegistryUrl
+	if !strings.Contains(registryUrl, "://") {
+		registryUrl = "https://" + registryUrl
+	}
+	parsedUrl, err := url.Parse(registryUrl)
+	if err != nil || len(parsedUrl.Host) == 0 {
+		return nil, fmt.Errorf("invalid registry url %q", credential.RegistryUrl)
+	}
+	repository = strings.Trim(path.Join(strings.Trim(parsedUrl.Path, "/"), strings.Trim(repository, "/")), "/")
+	if len(repository) == 0 {
+		return nil, fmt.Errorf("repository is required")
+	}
+	return &registrySession{
+		client:     impl.httpClient,
+		credential: credential,
+		baseUrl:    fmt.Sprintf("%s://%s", parsedUrl.Scheme, parsedUrl.Host),
+		repository: repository,
+		scope:      fmt.Sprintf("repository:%s:%s", repository, actions),
+	}, nil
+}
+
+func ociDigest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+func (impl *OCIRegistryClientImpl) PushHelmChart(credential *RegistryCredential, repository string, tag string, chartConfig []byte, chartArchive []byte) (string, error) {
+	session, err := impl.newSession(credential, repository, "pull,push")
+	if err != nil {
+		return "", err
+	}
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     OCIManifestMediaType,
+		Config:        ociDescriptor{MediaType: HelmChartConfigMediaType, Digest: ociDigest(chartConfig), Size: len(chartConfig)},
+		Layers:        []ociDescriptor{{MediaType: HelmChartContentMediaType, Digest: ociDigest(chartArchive), Size: len(chartArchive)}},
+	}
+	for _, blob := range [][]byte{chartConfig, chartArchive} {
+		err = session.pushBlob(blob)
+		if err != nil {
+			impl.logger.Errorw("error in pushing blob to oci registry", "err", err, "repository", session.repository)
+			return "", err
+		}
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	resp, body, err := session.do(http.MethodPut, session.url("/manifests/"+tag), manifestBytes, map[string]string{"Content-Type": OCIManifestMediaType})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", registryError("pushing manifest", resp, body)
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); len(digest) > 0 {
+		return digest, nil
+	}
+	return ociDigest(manifestBytes), nil
+}
+
+func (impl *OCIRegistryClientImpl) PullHelmChart(credential *RegistryCredential, repository string, tag string) ([]byte, error) {
+	session, err := impl.newSession(credential, repository, "pull")
+	if err != nil {
+		return nil, err
+	}
+	resp, body, err := session.do(http.MethodGet, session.url("/manifests/"+tag), nil, map[string]string{"Accept": OCIManifestMediaType})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, registryError("fetching manifest", resp, body)
+	}
+	manifest := &ociManifest{}
+	err = json.Unmarshal(body, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest for %s:%s, %v", session.repository, tag, err)
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != HelmChartContentMediaType && layer.MediaType != legacyChartContentMediaType {
+			continue
+		}
+		resp, body, err = session.do(http.MethodGet, session.url("/blobs/"+layer.Digest), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, registryError("fetching chart", resp, body)
+		}
+		if ociDigest(body) != layer.Digest {
+			return nil, fmt.Errorf("digest mismatch for chart %s:%s", session.repository, tag)
+		}
+		return body, nil
+	}
+	return nil, fmt.Errorf("%s:%s is not a helm chart", session.repository, tag)
+}
+
+func (impl *OCIRegistryClientImpl) GetImagePlatforms(credential *RegistryCredential, repository string, reference string) ([]string, error) {
+	session, err := impl.newSession(credential, repository, "pull")
+	if err != nil {
+		return nil, err
+	}
+	accept := strings.Join([]string{OCIImageIndexMediaType, DockerManifestListMediaType, OCIManifestMediaType, DockerManifestMediaType}, ", ")
+	resp, body, err := session.do(http.MethodGet, session.url("/manifests/"+reference), nil, map[string]string{"Accept": accept})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, registryError("fetching manifest", resp, body)
+	}
+	manifest := &ociImageManifest{}
+	err = json.Unmarshal(body, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest for %s@%s, %v", session.repository, reference, err)
+	}
+	if len(manifest.Manifests) > 0 {
+		var platforms []string
+		for _, entry := range manifest.Manifests {
+			// attestation manifests pushed by buildx are listed with an unknown platform
+			if entry.Platform == nil || entry.Platform.OS == "unknown" {
+				continue
+			}
+			platforms = append(platforms, entry.Platform.String())
+		}
+		return platforms, nil
+	}
+	if len(manifest.Config.Digest) == 0 {
+		return nil, fmt.Errorf("%s@%s is not an image", session.repository, reference)
+	}
+	resp, body, err = session.do(http.MethodGet, session.url("/blobs/"+manifest.Config.Digest), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, registryError("fetching image config", resp, body)
+	}
+	imageConfig := &ociPlatform{}
+	err = json.Unmarshal(body, imageConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image config for %s@%s, %v", session.repository, reference, err)
+	}
+	if len(imageConfig.OS) == 0 || len(imageConfig.Architecture) == 0 {
+		return nil, nil
+	}
+	return []string{imageConfig.String()}, nil
+}
+
+func (session *registrySession) url(suffix string) string {
+	return fmt.Sprintf("%s/v2/%s%s", session.baseUrl, session.repository, suffix)
+}
+
+// pushBlob uploads content in a single request unless the registry already has it
+func (session *registrySession) pushBlob(content []byte) error {
+	digest := ociDigest(content)
+	resp, _, err := session.do(http.MethodHead, session.url("/blobs/"+digest), nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	resp, body, err := session.do(http.MethodPost, session.url("/blobs/uploads/"), nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		return registryError("starting blob upload", resp, body)
+	}
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || len(resp.Header.Get("Location")) == 0 {
+		return fmt.Errorf("registry returned invalid upload location %q", resp.Header.Get("Location"))
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+	resp, body, err = session.do(http.MethodPut, location.String(), content, map[string]string{"Content-Type": "application/octet-stream"})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return registryError("uploading blob", resp, body)
+	}
+	return nil
+}
+
+// do sends the request and on an auth challenge negotiates basic or bearer token auth and retries once
+func (session *registrySession) do(method string, requestUrl string, body []byte, headers map[string]string) (*http.Response, []byte, error) {
+	resp, respBody, err := session.send(method, requestUrl, body, headers)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || len(session.authorization) > 0 {
+		return resp, respBody, err
+	}
+	err = session.authorize(resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return session.send(method, requestUrl, body, headers)
+}
+
+func (session *registrySession) send(method string, requestUrl string, body []byte, headers map[string]string) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(method, requestUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if len(session.authorization) > 0 {
+		req.Header.Set("Authorization", session.authorization)
+	}
+	resp, err := session.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	return resp, respBody, err
+}
+
+func (session *registrySession) authorize(challenge string) error {
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		req, _ := http.NewRequest(http.MethodGet, session.baseUrl, nil)
+		req.SetBasicAuth(session.credential.Username, session.credential.Password)
+		session.authorization = req.Header.Get("Authorization")
+		return nil
+	case "bearer":
+		tokenUrl, err := url.Parse(params["realm"])
+		if err != nil || len(params["realm"]) == 0 {
+			return fmt.Errorf("registry returned invalid token realm %q", params["realm"])
+		}
+		query := tokenUrl.Query()
+		if len(params["service"]) > 0 {
+			query.Set("service", params["service"])
+		}
+		query.Set("scope", session.scope)
+		tokenUrl.RawQuery = query.Encode()
+		req, err := http.NewRequest(http.MethodGet, tokenUrl.String(), nil)
+		if err != nil {
+			return err
+		}
+		if len(session.credential.Username) > 0 {
+			req.SetBasicAuth(session.credential.Username, session.credential.Password)
+		}
+		resp, err := session.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return registryError("fetching token", resp, respBody)
+		}
+		tokenResponse := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		err = json.Unmarshal(respBody, &tokenResponse)
+		if err != nil {
+			return err
+		}
+		token := tokenResponse.Token
+		if len(token) == 0 {
+			token = tokenResponse.AccessToken
+		}
+		if len(token) == 0 {
+			return fmt.Errorf("registry token response has no token")
+		}
+		session.authorization = "Bearer " + token
+		return nil
+	default:
+		return fmt.Errorf("unauthorized, unsupported registry auth challenge %q", challenge)
+	}
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header like `Bearer realm="https://auth",service="registry"`
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	challenge = strings.TrimSpace(challenge)
+	scheme := challenge
+	if index := strings.Index(challenge, " "); index > 0 {
+		scheme = challenge[:index]
+		rest := challenge[index+1:]
+		for len(rest) > 0 {
+			eq := strings.Index(rest, "=")
+			if eq < 0 {
+				break
+			}
+			key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+			rest = strings.TrimSpace(rest[eq+1:])
+			var value string
+			if strings.HasPrefix(rest, `"`) {
+				end := strings.Index(rest[1:], `"`)
+				if end < 0 {
+					value, rest = rest[1:], ""
+				} else {
+					value, rest = rest[1:end+1], rest[end+2:]
+				}
+			} else if comma := strings.Index(rest, ","); comma >= 0 {
+				value, rest = rest[:comma], rest[comma:]
+			} else {
+				value, rest = rest, ""
+			}
+			params[key] = value
+			rest = strings.TrimLeft(rest, ", ")
+		}
+	}
+	return scheme, params
+}
+
+func registryError(action string, resp *http.Response, body []byte) error {
+	return fmt.Errorf("error in %s, registry responded with status %d: %s", action, resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,159 @@
+package ociRegistry
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// testRegistry is a minimal in memory stand-in for a registry:2 style OCI registry protected by bearer tokens
+type testRegistry struct {
+	lock      sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+	server    *httptest.Server
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	registry := &testRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+	registry.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registry.lock.Lock()
+		defer registry.lock.Unlock()
+		if r.URL.Path == "/token" {
+			username, password, _ := r.BasicAuth()
+			if username != "admin" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.True(t, strings.HasPrefix(r.URL.Query().Get("scope"), "repository:charts/payments:pull"))
+			_, _ = w.Write([]byte(`{"token":"test-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test-registry"`, registry.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		const prefix = "/v2/charts/payments"
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == prefix+"/blobs/uploads/":
+			registry.uploads++
+			w.Header().Set("Location", fmt.Sprintf("%s/blobs/uploads/%d?state=abc", prefix, registry.uploads))
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, prefix+"/blobs/uploads/"):
+			digest := r.URL.Query().Get("digest")
+			assert.Equal(t, "abc", r.URL.Query().Get("state"))
+			if ociDigest(body) != digest {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			registry.blobs[digest] = body
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasPrefix(r.URL.Path, prefix+"/blobs/"):
+			blob, ok := registry.blobs[strings.TrimPrefix(r.URL.Path, prefix+"/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(blob)
+			}
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, prefix+"/manifests/"):
+			assert.Equal(t, OCIManifestMediaType, r.Header.Get("Content-Type"))
+			registry.manifests[strings.TrimPrefix(r.URL.Path, prefix+"/manifests/")] = body
+			w.Header().Set("Docker-Content-Digest", ociDigest(body))
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, prefix+"/manifests/"):
+			manifest, ok := registry.manifests[strings.TrimPrefix(r.URL.Path, prefix+"/manifests/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", OCIManifestMediaType)
+			_, _ = w.Write(manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return registry
+}
+
+func TestOCIRegistryClient_PushAndPullHelmChart(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.server.Close()
+	client := NewOCIRegistryClientImpl(zap.NewNop().Sugar(), &OCIRegistryClientConfig{Timeout: 10})
+	credential := &RegistryCredential{RegistryUrl: registry.server.URL + "/charts", Username: "admin", Password: "secret"}
+	chartConfig := []byte(`{"name":"payments","version":"1.0.7"}`)
+	chartArchive := []byte("chart archive content")
+
+	digest, err := client.PushHelmChart(credential, "payments", "1.0.7", chartConfig, chartArchive)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(digest, "sha256:"))
+	assert.Equal(t, 2, len(registry.blobs))
+	assert.Contains(t, string(registry.manifests["1.0.7"]), HelmChartContentMediaType)
+
+	//blobs already present are not uploaded again
+	_, err = client.PushHelmChart(credential, "payments", "1.0.8", chartConfig, chartArchive)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, registry.uploads)
+
+	pulled, err := client.PullHelmChart(credential, "payments", "1.0.7")
+	assert.Nil(t, err)
+	assert.Equal(t, chartArchive, pulled)
+
+	_, err = client.PullHelmChart(credential, "payments", "9.9.9")
+	assert.NotNil(t, err)
+}
+
+func TestOCIRegistryClient_GetImagePlatforms(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.server.Close()
+	client := NewOCIRegistryClientImpl(zap.NewNop().Sugar(), &OCIRegistryClientConfig{Timeout: 10})
+	credential := &RegistryCredential{RegistryUrl: registry.server.URL + "/charts", Username: "admin", Password: "secret"}
+
+	imageConfig := []byte(`{"architecture":"arm64","os":"linux","variant":"v8"}`)
+	registry.blobs[ociDigest(imageConfig)] = imageConfig
+	registry.manifests["single"] = []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","config":{"digest":"%s"}}`, DockerManifestMediaType, ociDigest(imageConfig)))
+	platforms, err := client.GetImagePlatforms(credential, "payments", "single")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"linux/arm64/v8"}, platforms)
+
+	registry.manifests["multi"] = []byte(`{"schemaVersion":2,"manifests":[{"digest":"sha256:a","platform":{"os":"linux","architecture":"amd64"}},` +
+		`{"digest":"sha256:b","platform":{"os":"linux","architecture":"arm64"}},{"digest":"sha256:c","platform":{"os":"unknown","architecture":"unknown"}}]}`)
+	platforms, err = client.GetImagePlatforms(credential, "payments", "multi")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"linux/amd64", "linux/arm64"}, platforms)
+
+	_, err = client.GetImagePlatforms(credential, "payments", "missing")
+	assert.NotNil(t, err)
+}
+
+func TestOCIRegistryClient_InvalidCredentials(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.server.Close()
+	client := NewOCIRegistryClientImpl(zap.NewNop().Sugar(), &OCIRegistryClientConfig{Timeout: 10})
+	credential := &RegistryCredential{RegistryUrl: registry.server.URL + "/charts", Username: "admin", Password: "wrong"}
+	_, err := client.PushHelmChart(credential, "payments", "1.0.0", []byte("{}"), []byte("chart"))
+	assert.NotNil(t, err)
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, "https://auth.docker.io/token", params["realm"])
+	assert.Equal(t, "registry.docker.io", params["service"])
+	assert.Equal(t, "repository:library/nginx:pull", params["scope"])
+
+	scheme, params = parseAuthChallenge(`Basic realm="Registry Realm"`)
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, "Registry Realm", params["realm"])
+}
@@ -5,6 +5,7 @@ import (
 	authMiddleware "github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/devtron/client/telemetry"
 	"github.com/devtron-labs/devtron/internal/middleware"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -13,13 +14,14 @@ import (
 )
 
 type App struct {
-	db             *pg.DB
-	sessionManager *authMiddleware.SessionManager
-	MuxRouter      *MuxRouter
-	Logger         *zap.SugaredLogger
-	server         *http.Server
-	telemetry      telemetry.TelemetryEventClient
-	posthogClient  *telemetry.PosthogClient
+	db              *pg.DB
+	sessionManager  *authMiddleware.SessionManager
+	MuxRouter       *MuxRouter
+	Logger          *zap.SugaredLogger
+	server          *http.Server
+	telemetry       telemetry.TelemetryEventClient
+	posthogClient   *telemetry.PosthogClient
+	auditLogService auditLog.AuditLogService
 }
 
 func NewApp(db *pg.DB,
@@ -27,14 +29,16 @@ func NewApp(db *pg.DB,
 	MuxRouter *MuxRouter,
 	telemetry telemetry.TelemetryEventClient,
 	posthogClient *telemetry.PosthogClient,
-	Logger *zap.SugaredLogger) *App {
+	Logger *zap.SugaredLogger,
+	auditLogService auditLog.AuditLogService) *App {
 	return &App{
-		db:             db,
-		sessionManager: sessionManager,
-		MuxRouter:      MuxRouter,
-		Logger:         Logger,
-		telemetry:      telemetry,
-		posthogClient:  posthogClient,
+		db:              db,
+		sessionManager:  sessionManager,
+		MuxRouter:       MuxRouter,
+		Logger:          Logger,
+		telemetry:       telemetry,
+		posthogClient:   posthogClient,
+		auditLogService: auditLogService,
 	}
 }
 func (app *App) Start() {
@@ -69,4 +73,5 @@ func (app *App) Stop() {
 		app.Logger.Info("flushing messages of posthog")
 		posthogCl.Close()
 	}
+	app.auditLogService.Stop()
 }
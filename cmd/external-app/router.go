@@ -6,6 +6,7 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
@@ -59,6 +60,8 @@ type MuxRouter struct {
 	attributesRouter         router.AttributesRouter
 	appRouter                router.AppRouter
 	rbacRoleRouter           user.RbacRoleRouter
+	scimRouter               user.ScimRouter
+	auditLogRouter           auditLog.AuditLogRouter
 }
 
 func NewMuxRouter(
@@ -89,6 +92,8 @@ func NewMuxRouter(
 	attributesRouter router.AttributesRouter,
 	appRouter router.AppRouter,
 	rbacRoleRouter user.RbacRoleRouter,
+	scimRouter user.ScimRouter,
+	auditLogRouter auditLog.AuditLogRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                   mux.NewRouter(),
@@ -120,6 +125,8 @@ func NewMuxRouter(
 		attributesRouter:         attributesRouter,
 		appRouter:                appRouter,
 		rbacRoleRouter:           rbacRoleRouter,
+		scimRouter:               scimRouter,
+		auditLogRouter:           auditLogRouter,
 	}
 	return r
 }
@@ -161,6 +168,8 @@ func (r *MuxRouter) Init() {
 	r.UserAuthRouter.InitUserAuthRouter(rootRouter)
 	userRouter := baseRouter.PathPrefix("/user").Subrouter()
 	r.userRouter.InitUserRouter(userRouter)
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
 	rbacRoleRouter := baseRouter.PathPrefix("/rbac/role").Subrouter()
 	r.rbacRoleRouter.InitRbacRoleRouter(rbacRoleRouter)
 	clusterRouter := baseRouter.PathPrefix("/cluster").Subrouter()
@@ -227,6 +236,11 @@ func (r *MuxRouter) Init() {
 	// api-token router
 	apiTokenRouter := r.Router.PathPrefix("/orchestrator/api-token").Subrouter()
 	r.apiTokenRouter.InitApiTokenRouter(apiTokenRouter)
+	// audit log middleware is registered first so that calls rejected by the api-token middleware are audited too
+	r.auditLogRouter.InitAuditLogMiddleware(r.Router)
+	r.apiTokenRouter.InitApiTokenMiddleware(r.Router)
+	auditLogRouter := r.Router.PathPrefix("/orchestrator/audit-log").Subrouter()
+	r.auditLogRouter.InitAuditLogRouter(auditLogRouter)
 
 	// webhook helm app router
 	webhookHelmRouter := r.Router.PathPrefix("/orchestrator/webhook/helm").Subrouter()
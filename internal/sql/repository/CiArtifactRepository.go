@@ -31,26 +31,43 @@ import (
 )
 
 type CiArtifact struct {
-	tableName            struct{}  `sql:"ci_artifact" pg:",discard_unknown_columns"`
-	Id                   int       `sql:"id,pk"`
-	PipelineId           int       `sql:"pipeline_id"` //id of the ci pipeline from which this webhook was triggered
-	Image                string    `sql:"image,notnull"`
-	ImageDigest          string    `sql:"image_digest,notnull"`
-	MaterialInfo         string    `sql:"material_info"` //git material metadata json array string
-	DataSource           string    `sql:"data_source,notnull"`
-	WorkflowId           *int      `sql:"ci_workflow_id"`
-	ParentCiArtifact     int       `sql:"parent_ci_artifact"`
-	ScanEnabled          bool      `sql:"scan_enabled,notnull"`
-	Scanned              bool      `sql:"scanned,notnull"`
-	ExternalCiPipelineId int       `sql:"external_ci_pipeline_id"`
-	IsArtifactUploaded   bool      `sql:"is_artifact_uploaded"`
-	DeployedTime         time.Time `sql:"-"`
-	Deployed             bool      `sql:"-"`
-	Latest               bool      `sql:"-"`
-	RunningOnParent      bool      `sql:"-"`
+	tableName            struct{}              `sql:"ci_artifact" pg:",discard_unknown_columns"`
+	Id                   int                   `sql:"id,pk"`
+	PipelineId           int                   `sql:"pipeline_id"` //id of the ci pipeline from which this webhook was triggered
+	Image                string                `sql:"image,notnull"`
+	ImageDigest          string                `sql:"image_digest,notnull"`
+	MaterialInfo         string                `sql:"material_info"` //git material metadata json array string
+	DataSource           string                `sql:"data_source,notnull"`
+	WorkflowId           *int                  `sql:"ci_workflow_id"`
+	ParentCiArtifact     int                   `sql:"parent_ci_artifact"`
+	ScanEnabled          bool                  `sql:"scan_enabled,notnull"`
+	Scanned              bool                  `sql:"scanned,notnull"`
+	ExternalCiPipelineId int                   `sql:"external_ci_pipeline_id"`
+	IsArtifactUploaded   bool                  `sql:"is_artifact_uploaded"`
+	ManifestListDigest   string                `sql:"manifest_list_digest"` //digest of the manifest list of a multi-arch image
+	PlatformDigests      []ImagePlatformDigest `sql:"platform_digests"`
+	DeployedTime         time.Time             `sql:"-"`
+	Deployed             bool                  `sql:"-"`
+	Latest               bool                  `sql:"-"`
+	RunningOnParent      bool                  `sql:"-"`
 	sql.AuditLog
 }
 
+// ImagePlatformDigest is the digest of the image built for one platform of a multi-arch image
+type ImagePlatformDigest struct {
+	Platform string `json:"platform"` //os/arch[/variant]
+	Digest   string `json:"digest"`
+}
+
+// GetPlatforms returns the platforms of a multi-arch image, empty for single platform builds
+func (artifact *CiArtifact) GetPlatforms() []string {
+	var platforms []string
+	for _, platformDigest := range artifact.PlatformDigests {
+		platforms = append(platforms, platformDigest.Platform)
+	}
+	return platforms
+}
+
 type CiArtifactRepository interface {
 	Save(artifact *CiArtifact) error
 	Delete(artifact *CiArtifact) error
@@ -68,6 +85,8 @@ type CiArtifactRepository interface {
 	GetByImageDigest(imageDigest string) (artifact *CiArtifact, err error)
 	GetByIds(ids []int) ([]*CiArtifact, error)
 	GetArtifactByCdWorkflowId(cdWorkflowId int) (artifact *CiArtifact, err error)
+	// FindAppIdById returns the app of the ci or external ci pipeline which created the artifact
+	FindAppIdById(id int) (int, error)
 }
 
 type CiArtifactRepositoryImpl struct {
@@ -535,3 +554,13 @@ func (impl CiArtifactRepositoryImpl) GetArtifactByCdWorkflowId(cdWorkflowId int)
 		Select()
 	return artifact, err
 }
+
+func (impl CiArtifactRepositoryImpl) FindAppIdById(id int) (int, error) {
+	var appId int
+	query := "SELECT COALESCE(cp.app_id, ecp.app_id) FROM ci_artifact cia" +
+		" LEFT JOIN ci_pipeline cp ON cp.id = cia.pipeline_id" +
+		" LEFT JOIN external_ci_pipeline ecp ON ecp.id = cia.external_ci_pipeline_id" +
+		" WHERE cia.id = ?;"
+	_, err := impl.dbConnection.QueryOne(pg.Scan(&appId), query, id)
+	return appId, err
+}
@@ -53,6 +53,7 @@ type GitOpsConfig struct {
 	Active               bool     `sql:"active,notnull"`
 	BitBucketWorkspaceId string   `sql:"bitbucket_workspace_id"`
 	BitBucketProjectKey  string   `sql:"bitbucket_project_key"`
+	GiteaOrgId           string   `sql:"gitea_org_id"`
 	EmailId              string   `sql:"email_id"`
 	sql.AuditLog
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type NotificationChannelConfigRepository interface {
+	FindOne(id int) (*NotificationChannelConfig, error)
+	FindAll() ([]*NotificationChannelConfig, error)
+	FindAllByChannelType(channelType string) ([]*NotificationChannelConfig, error)
+	FindByIds(ids []int) ([]*NotificationChannelConfig, error)
+	SaveConfig(config *NotificationChannelConfig) (*NotificationChannelConfig, error)
+	UpdateConfig(config *NotificationChannelConfig) (*NotificationChannelConfig, error)
+	MarkConfigDeleted(config *NotificationChannelConfig) error
+}
+
+type NotificationChannelConfigRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewNotificationChannelConfigRepositoryImpl(dbConnection *pg.DB) *NotificationChannelConfigRepositoryImpl {
+	return &NotificationChannelConfigRepositoryImpl{dbConnection: dbConnection}
+}
+
+// NotificationChannelConfig holds the destination config of every channel served by a
+// notifier.NotificationChannelProvider, the shape of Config is owned by the provider
+type NotificationChannelConfig struct {
+	tableName   struct{}          `sql:"notification_channel_config" pg:",discard_unknown_columns"`
+	Id          int               `sql:"id,pk"`
+	ChannelType string            `sql:"channel_type"`
+	ConfigName  string            `sql:"config_name"`
+	Description string            `sql:"description"`
+	Config      map[string]string `sql:"config"`
+	OwnerId     int32             `sql:"owner_id"`
+	Deleted     bool              `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) FindOne(id int) (*NotificationChannelConfig, error) {
+	config := &NotificationChannelConfig{}
+	err := impl.dbConnection.Model(config).Where("id = ?", id).
+		Where("deleted = ?", false).Select()
+	return config, err
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) FindAll() ([]*NotificationChannelConfig, error) {
+	var configs []*NotificationChannelConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("deleted = ?", false).Order("id").Select()
+	return configs, err
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) FindAllByChannelType(channelType string) ([]*NotificationChannelConfig, error) {
+	var configs []*NotificationChannelConfig
+	err := impl.dbConnection.Model(&configs).Where("channel_type = ?", channelType).
+		Where("deleted = ?", false).Order("id").Select()
+	return configs, err
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) FindByIds(ids []int) ([]*NotificationChannelConfig, error) {
+	var configs []*NotificationChannelConfig
+	if len(ids) == 0 {
+		return configs, nil
+	}
+	err := impl.dbConnection.Model(&configs).Where("id in (?)", pg.In(ids)).
+		Where("deleted = ?", false).Select()
+	return configs, err
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) SaveConfig(config *NotificationChannelConfig) (*NotificationChannelConfig, error) {
+	return config, impl.dbConnection.Insert(config)
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) UpdateConfig(config *NotificationChannelConfig) (*NotificationChannelConfig, error) {
+	return config, impl.dbConnection.Update(config)
+}
+
+func (impl *NotificationChannelConfigRepositoryImpl) MarkConfigDeleted(config *NotificationChannelConfig) error {
+	config.Deleted = true
+	return impl.dbConnection.Update(config)
+}
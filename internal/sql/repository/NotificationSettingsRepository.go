@@ -20,6 +20,7 @@ package repository
 import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
 	"strconv"
 )
 
@@ -41,6 +42,8 @@ type NotificationSettingsRepository interface {
 	FindNotificationSettingBuildOptions(settingRequest *SearchRequest) ([]*SettingOptionDTO, error)
 	FetchNotificationSettingGroupBy(viewId int) ([]NotificationSettings, error)
 	FindNotificationSettingsByConfigIdAndConfigType(configId int, configType string) ([]*NotificationSettings, error)
+	FindNotificationSettingsForEvent(eventTypeId int, pipelineType string, pipelineId int, teamId int, appId int, envId int) ([]*NotificationSettings, error)
+	FindNotificationSettingsByPipelineIds(pipelineType string, pipelineIds []int) ([]*NotificationSettings, error)
 }
 
 type NotificationSettingsRepositoryImpl struct {
@@ -317,3 +320,37 @@ func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsByConfig
 	}
 	return notificationSettings, nil
 }
+
+// FindNotificationSettingsForEvent returns the rules an event falls under, a rule either targets the pipeline
+// directly or matches on team/app/env where an empty column matches everything
+func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsForEvent(eventTypeId int, pipelineType string, pipelineId int, teamId int, appId int, envId int) ([]*NotificationSettings, error) {
+	var notificationSettings []*NotificationSettings
+	err := impl.dbConnection.Model(&notificationSettings).
+		Where("event_type_id = ?", eventTypeId).
+		Where("pipeline_type = ?", pipelineType).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("pipeline_id = ?", pipelineId).
+				WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+					q = q.Where("pipeline_id IS NULL").
+						Where("(team_id IS NULL OR team_id = ?)", teamId).
+						Where("(app_id IS NULL OR app_id = ?)", appId).
+						Where("(env_id IS NULL OR env_id = ?)", envId).
+						Where("(team_id IS NOT NULL OR app_id IS NOT NULL OR env_id IS NOT NULL)")
+					return q, nil
+				})
+			return q, nil
+		}).Select()
+	return notificationSettings, err
+}
+
+func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsByPipelineIds(pipelineType string, pipelineIds []int) ([]*NotificationSettings, error) {
+	var notificationSettings []*NotificationSettings
+	if len(pipelineIds) == 0 {
+		return notificationSettings, nil
+	}
+	err := impl.dbConnection.Model(&notificationSettings).
+		Where("pipeline_type = ?", pipelineType).
+		Where("pipeline_id in (?)", pg.In(pipelineIds)).
+		Order("id").Select()
+	return notificationSettings, err
+}
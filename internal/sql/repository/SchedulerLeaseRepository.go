@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/go-pg/pg"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"os"
+	"time"
+)
+
+// SchedulerLease marks the orchestrator replica which runs a background job that must not run on every replica,
+// a lease not renewed before it expires can be taken over by any other replica
+type SchedulerLease struct {
+	tableName struct{}  `sql:"scheduler_lease" pg:",discard_unknown_columns"`
+	Name      string    `sql:"name,pk"`
+	Holder    string    `sql:"holder,notnull"`
+	ExpiresOn time.Time `sql:"expires_on,notnull"`
+}
+
+type SchedulerLeaseRepository interface {
+	// TryAcquire takes or renews the lease for this replica, false is returned while another replica holds it
+	TryAcquire(name string, ttl time.Duration) (bool, error)
+}
+
+type SchedulerLeaseRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	holder       string
+}
+
+func NewSchedulerLeaseRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *SchedulerLeaseRepositoryImpl {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.Warnw("error in getting hostname for scheduler lease holder", "err", err)
+	}
+	return &SchedulerLeaseRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+		holder:       fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+	}
+}
+
+func (impl *SchedulerLeaseRepositoryImpl) TryAcquire(name string, ttl time.Duration) (bool, error) {
+	// expiry is compared with the db clock so that clock skew between replicas does not matter
+	query := `INSERT INTO scheduler_lease (name, holder, expires_on) VALUES (?, ?, now() + ? * interval '1 second')
+		ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_on = EXCLUDED.expires_on
+		WHERE scheduler_lease.holder = EXCLUDED.holder OR scheduler_lease.expires_on < now()
+		RETURNING name`
+	var acquiredName string
+	_, err := impl.dbConnection.QueryOne(pg.Scan(&acquiredName), query, name, impl.holder, int(ttl.Seconds()))
+	if err == pg.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in acquiring scheduler lease", "name", name, "err", err)
+		return false, err
+	}
+	return true, nil
+}
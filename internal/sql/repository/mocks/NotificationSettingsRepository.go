@@ -216,6 +216,58 @@ func (_m *NotificationSettingsRepository) FindNotificationSettingsByConfigIdAndC
 	return r0, r1
 }
 
+// FindNotificationSettingsForEvent provides a mock function with given fields: eventTypeId, pipelineType, pipelineId, teamId, appId, envId
+func (_m *NotificationSettingsRepository) FindNotificationSettingsForEvent(eventTypeId int, pipelineType string, pipelineId int, teamId int, appId int, envId int) ([]*repository.NotificationSettings, error) {
+	ret := _m.Called(eventTypeId, pipelineType, pipelineId, teamId, appId, envId)
+
+	var r0 []*repository.NotificationSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, int, int, int, int) ([]*repository.NotificationSettings, error)); ok {
+		return rf(eventTypeId, pipelineType, pipelineId, teamId, appId, envId)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, int, int, int, int) []*repository.NotificationSettings); ok {
+		r0 = rf(eventTypeId, pipelineType, pipelineId, teamId, appId, envId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.NotificationSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, int, int, int, int) error); ok {
+		r1 = rf(eventTypeId, pipelineType, pipelineId, teamId, appId, envId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindNotificationSettingsByPipelineIds provides a mock function with given fields: pipelineType, pipelineIds
+func (_m *NotificationSettingsRepository) FindNotificationSettingsByPipelineIds(pipelineType string, pipelineIds []int) ([]*repository.NotificationSettings, error) {
+	ret := _m.Called(pipelineType, pipelineIds)
+
+	var r0 []*repository.NotificationSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []int) ([]*repository.NotificationSettings, error)); ok {
+		return rf(pipelineType, pipelineIds)
+	}
+	if rf, ok := ret.Get(0).(func(string, []int) []*repository.NotificationSettings); ok {
+		r0 = rf(pipelineType, pipelineIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.NotificationSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []int) error); ok {
+		r1 = rf(pipelineType, pipelineIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindNotificationSettingsByViewId provides a mock function with given fields: viewId
 func (_m *NotificationSettingsRepository) FindNotificationSettingsByViewId(viewId int) ([]repository.NotificationSettings, error) {
 	ret := _m.Called(viewId)
@@ -0,0 +1,284 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CanaryAnalysisRunStatus string
+
+const (
+	CANARY_ANALYSIS_RUN_STATUS_RUNNING   CanaryAnalysisRunStatus = "RUNNING"
+	CANARY_ANALYSIS_RUN_STATUS_SUCCEEDED CanaryAnalysisRunStatus = "SUCCEEDED"
+	CANARY_ANALYSIS_RUN_STATUS_FAILED    CanaryAnalysisRunStatus = "FAILED"
+	// CANARY_ANALYSIS_RUN_STATUS_ABORTED analysis stopped because a newer deployment superseded the analysed one
+	CANARY_ANALYSIS_RUN_STATUS_ABORTED CanaryAnalysisRunStatus = "ABORTED"
+)
+
+type CanaryAnalysisOperator string
+
+const (
+	CANARY_ANALYSIS_OPERATOR_LT  CanaryAnalysisOperator = "<"
+	CANARY_ANALYSIS_OPERATOR_LTE CanaryAnalysisOperator = "<="
+	CANARY_ANALYSIS_OPERATOR_GT  CanaryAnalysisOperator = ">"
+	CANARY_ANALYSIS_OPERATOR_GTE CanaryAnalysisOperator = ">="
+)
+
+type CanaryAnalysisConfig struct {
+	tableName             struct{} `sql:"canary_analysis_config" pg:",discard_unknown_columns"`
+	Id                    int      `sql:"id,pk"`
+	PipelineId            int      `sql:"pipeline_id,notnull"`
+	InitialDelayInSeconds int      `sql:"initial_delay_in_seconds,notnull"`
+	IntervalInSeconds     int      `sql:"interval_in_seconds,notnull"`
+	EvaluationCount       int      `sql:"evaluation_count,notnull"`
+	FailureLimit          int      `sql:"failure_limit,notnull"`
+	AutoRollback          bool     `sql:"auto_rollback,notnull"`
+	Active                bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisMetric struct {
+	tableName struct{}               `sql:"canary_analysis_metric" pg:",discard_unknown_columns"`
+	Id        int                    `sql:"id,pk"`
+	ConfigId  int                    `sql:"config_id,notnull"`
+	Name      string                 `sql:"name,notnull"`
+	Query     string                 `sql:"query,notnull"`
+	Operator  CanaryAnalysisOperator `sql:"operator,notnull"`
+	Threshold float64                `sql:"threshold,notnull"`
+	Deleted   bool                   `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisRun struct {
+	tableName                  struct{}                `sql:"canary_analysis_run" json:"-" pg:",discard_unknown_columns"`
+	Id                         int                     `sql:"id,pk" json:"id"`
+	PipelineId                 int                     `sql:"pipeline_id,notnull" json:"pipelineId"`
+	ConfigId                   int                     `sql:"config_id,notnull" json:"configId"`
+	CdWorkflowRunnerId         int                     `sql:"cd_workflow_runner_id,notnull" json:"cdWorkflowRunnerId"`
+	PipelineOverrideId         int                     `sql:"pipeline_override_id,notnull" json:"pipelineOverrideId"`
+	RollbackCdWorkflowRunnerId int                     `sql:"rollback_cd_workflow_runner_id" json:"rollbackCdWorkflowRunnerId,omitempty"`
+	RollbackPipelineOverrideId int                     `sql:"rollback_pipeline_override_id" json:"rollbackPipelineOverrideId,omitempty"`
+	RollbackRunnerId           int                     `sql:"rollback_runner_id" json:"rollbackRunnerId,omitempty"` //deploy runner of the rollback, saved before the rollback is deployed
+	Status                     CanaryAnalysisRunStatus `sql:"status,notnull" json:"status"`
+	EvaluationsDone            int                     `sql:"evaluations_done,notnull" json:"evaluationsDone"`
+	FailedEvaluations          int                     `sql:"failed_evaluations,notnull" json:"failedEvaluations"`
+	NextEvaluationAt           time.Time               `sql:"next_evaluation_at" json:"nextEvaluationAt"`
+	FinishedOn                 time.Time               `sql:"finished_on" json:"finishedOn,omitempty"`
+	Message                    string                  `sql:"message" json:"message"`
+	Results                    []*CanaryAnalysisResult `sql:"-" json:"results,omitempty"`
+	sql.AuditLog               `json:"-"`
+}
+
+type CanaryAnalysisResult struct {
+	tableName   struct{}  `sql:"canary_analysis_result" json:"-" pg:",discard_unknown_columns"`
+	Id          int       `sql:"id,pk" json:"id"`
+	RunId       int       `sql:"run_id,notnull" json:"runId"`
+	MetricId    int       `sql:"metric_id,notnull" json:"metricId"`
+	MetricName  string    `sql:"metric_name,notnull" json:"metricName"`
+	Evaluation  int       `sql:"evaluation,notnull" json:"evaluation"`
+	Value       float64   `sql:"value" json:"value"`
+	Passed      bool      `sql:"passed,notnull" json:"passed"`
+	Error       string    `sql:"error" json:"error,omitempty"`
+	EvaluatedOn time.Time `sql:"evaluated_on,notnull" json:"evaluatedOn"`
+}
+
+type CanaryAnalysisRepository interface {
+	SaveConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error
+	UpdateConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error
+	FindActiveConfigByPipelineId(pipelineId int) (*CanaryAnalysisConfig, error)
+	FindConfigById(id int) (*CanaryAnalysisConfig, error)
+	SaveMetrics(metrics []*CanaryAnalysisMetric, tx *pg.Tx) error
+	MarkMetricsDeletedByConfigId(configId int, userId int32, tx *pg.Tx) error
+	FindMetricsByConfigId(configId int) ([]*CanaryAnalysisMetric, error)
+
+	SaveRun(run *CanaryAnalysisRun) error
+	UpdateRun(run *CanaryAnalysisRun) error
+	FindRunById(id int) (*CanaryAnalysisRun, error)
+	FindRunsByPipelineId(pipelineId int, limit int) ([]*CanaryAnalysisRun, error)
+	FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*CanaryAnalysisRun, error)
+	FindDueRuns(now time.Time) ([]*CanaryAnalysisRun, error)
+	// ClaimRun pushes the next evaluation of a due run forward, returns false when another instance already claimed it
+	ClaimRun(id int, dueAt time.Time, nextEvaluationAt time.Time) (bool, error)
+	AbortRunningRunsByPipelineId(pipelineId int, message string, userId int32) error
+	SetRollbackRunnerId(id int, rollbackRunnerId int) error
+	ExistsByRollbackRunnerId(rollbackRunnerId int) (bool, error)
+
+	SaveResults(results []*CanaryAnalysisResult) error
+	FindResultsByRunId(runId int) ([]*CanaryAnalysisResult, error)
+	GetConnection() *pg.DB
+}
+
+type CanaryAnalysisRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCanaryAnalysisRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CanaryAnalysisRepositoryImpl {
+	return &CanaryAnalysisRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error {
+	return tx.Insert(config)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) UpdateConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error {
+	return tx.Update(config)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindActiveConfigByPipelineId(pipelineId int) (*CanaryAnalysisConfig, error) {
+	config := &CanaryAnalysisConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Select()
+	return config, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindConfigById(id int) (*CanaryAnalysisConfig, error) {
+	config := &CanaryAnalysisConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("id = ?", id).
+		Select()
+	return config, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveMetrics(metrics []*CanaryAnalysisMetric, tx *pg.Tx) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	return tx.Insert(&metrics)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) MarkMetricsDeletedByConfigId(configId int, userId int32, tx *pg.Tx) error {
+	_, err := tx.Model((*CanaryAnalysisMetric)(nil)).
+		Set("deleted = ?", true).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("config_id = ?", configId).
+		Where("deleted = ?", false).
+		Update()
+	return err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindMetricsByConfigId(configId int) ([]*CanaryAnalysisMetric, error) {
+	var metrics []*CanaryAnalysisMetric
+	err := impl.dbConnection.Model(&metrics).
+		Where("config_id = ?", configId).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return metrics, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveRun(run *CanaryAnalysisRun) error {
+	return impl.dbConnection.Insert(run)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) UpdateRun(run *CanaryAnalysisRun) error {
+	return impl.dbConnection.Update(run)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindRunById(id int) (*CanaryAnalysisRun, error) {
+	run := &CanaryAnalysisRun{}
+	err := impl.dbConnection.Model(run).
+		Where("id = ?", id).
+		Select()
+	return run, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindRunsByPipelineId(pipelineId int, limit int) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := impl.dbConnection.Model(&runs).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	return runs, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*CanaryAnalysisRun, error) {
+	run := &CanaryAnalysisRun{}
+	err := impl.dbConnection.Model(run).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return run, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindDueRuns(now time.Time) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := impl.dbConnection.Model(&runs).
+		Where("status = ?", CANARY_ANALYSIS_RUN_STATUS_RUNNING).
+		Where("next_evaluation_at <= ?", now).
+		Order("next_evaluation_at ASC").
+		Select()
+	return runs, err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) ClaimRun(id int, dueAt time.Time, nextEvaluationAt time.Time) (bool, error) {
+	res, err := impl.dbConnection.Model((*CanaryAnalysisRun)(nil)).
+		Set("next_evaluation_at = ?", nextEvaluationAt).
+		Where("id = ?", id).
+		Where("status = ?", CANARY_ANALYSIS_RUN_STATUS_RUNNING).
+		Where("next_evaluation_at = ?", dueAt).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) AbortRunningRunsByPipelineId(pipelineId int, message string, userId int32) error {
+	now := time.Now()
+	_, err := impl.dbConnection.Model((*CanaryAnalysisRun)(nil)).
+		Set("status = ?", CANARY_ANALYSIS_RUN_STATUS_ABORTED).
+		Set("message = ?", message).
+		Set("finished_on = ?", now).
+		Set("updated_on = ?", now).
+		Set("updated_by = ?", userId).
+		Where("pipeline_id = ?", pipelineId).
+		Where("status = ?", CANARY_ANALYSIS_RUN_STATUS_RUNNING).
+		Update()
+	return err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SetRollbackRunnerId(id int, rollbackRunnerId int) error {
+	_, err := impl.dbConnection.Model((*CanaryAnalysisRun)(nil)).
+		Set("rollback_runner_id = ?", rollbackRunnerId).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) ExistsByRollbackRunnerId(rollbackRunnerId int) (bool, error) {
+	return impl.dbConnection.Model((*CanaryAnalysisRun)(nil)).
+		Where("rollback_runner_id = ?", rollbackRunnerId).
+		Exists()
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveResults(results []*CanaryAnalysisResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	return impl.dbConnection.Insert(&results)
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindResultsByRunId(runId int) ([]*CanaryAnalysisResult, error) {
+	var results []*CanaryAnalysisResult
+	err := impl.dbConnection.Model(&results).
+		Where("run_id = ?", runId).
+		Order("id ASC").
+		Select()
+	return results, err
+}
@@ -47,6 +47,9 @@ type CdWorkflowRepository interface {
 	UpdateWorkFlowRunners(wfr []*CdWorkflowRunner) error
 	FindWorkflowRunnerByCdWorkflowId(wfIds []int) ([]*CdWorkflowRunner, error)
 	FindPreviousCdWfRunnerByStatus(pipelineId int, currentWFRunnerId int, status []string) ([]*CdWorkflowRunner, error)
+	FindLastSucceededDeployRunnerBefore(pipelineId int, currentWFRunnerId int) (*CdWorkflowRunner, error)
+	// FindLatestRunnerByPipelineIdAndArtifactId returns the latest runner of given type for the artifact on the pipeline, filtered on statuses when provided
+	FindLatestRunnerByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int, runnerType bean.WorkflowType, statuses []string) (*CdWorkflowRunner, error)
 	FindConfigByPipelineId(pipelineId int) (*CdWorkflowConfig, error)
 	FindWorkflowRunnerById(wfrId int) (*CdWorkflowRunner, error)
 	FindLatestWfrByAppIdAndEnvironmentId(appId int, environmentId int) (*CdWorkflowRunner, error)
@@ -206,6 +209,7 @@ type CdWorkflowWithArtifact struct {
 	CiMaterials        []CiPipelineMaterialResponse `json:"ciMaterials"`
 	ImageReleaseTags   []*repository2.ImageTag      `json:"imageReleaseTags"`
 	ImageComment       *repository2.ImageComment    `json:"imageComment"`
+	ApprovalRequest    *DeploymentApprovalRequest   `json:"approvalRequest,omitempty"`
 }
 
 type TriggerWorkflowStatus struct {
@@ -260,6 +264,38 @@ func (impl *CdWorkflowRepositoryImpl) FindPreviousCdWfRunnerByStatus(pipelineId
 	return runner, err
 }
 
+func (impl *CdWorkflowRepositoryImpl) FindLastSucceededDeployRunnerBefore(pipelineId int, currentWFRunnerId int) (*CdWorkflowRunner, error) {
+	runner := &CdWorkflowRunner{}
+	err := impl.dbConnection.
+		Model(runner).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow_runner.id < ?", currentWFRunnerId).
+		Where("workflow_type = ? ", bean.CD_WORKFLOW_TYPE_DEPLOY).
+		Where("cd_workflow_runner.status in (?) ", pg.In([]string{WorkflowSucceeded, string(health.HealthStatusHealthy)})).
+		Order("cd_workflow_runner.id DESC").
+		Limit(1).
+		Select()
+	return runner, err
+}
+
+func (impl *CdWorkflowRepositoryImpl) FindLatestRunnerByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int, runnerType bean.WorkflowType, statuses []string) (*CdWorkflowRunner, error) {
+	runner := &CdWorkflowRunner{}
+	query := impl.dbConnection.
+		Model(runner).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow.ci_artifact_id = ?", ciArtifactId).
+		Where("cd_workflow_runner.workflow_type = ?", runnerType)
+	if len(statuses) > 0 {
+		query = query.Where("cd_workflow_runner.status in (?)", pg.In(statuses))
+	}
+	err := query.Order("cd_workflow_runner.id DESC").
+		Limit(1).
+		Select()
+	return runner, err
+}
+
 func (impl *CdWorkflowRepositoryImpl) SaveWorkFlow(ctx context.Context, wf *CdWorkflow) error {
 	_, span := otel.Tracer("orchestrator").Start(ctx, "cdWorkflowRepository.SaveWorkFlow")
 	defer span.End()
@@ -0,0 +1,207 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type BuildCacheMode string
+
+const (
+	// BUILD_CACHE_MODE_REGISTRY layers are exported to and imported from cache images in a container registry
+	BUILD_CACHE_MODE_REGISTRY BuildCacheMode = "registry"
+	// BUILD_CACHE_MODE_DISABLED every build starts without any docker layer cache
+	BUILD_CACHE_MODE_DISABLED BuildCacheMode = "disabled"
+)
+
+type BuildCacheEntryStatus string
+
+const (
+	BUILD_CACHE_ENTRY_STATUS_LIVE BuildCacheEntryStatus = "LIVE"
+	// BUILD_CACHE_ENTRY_STATUS_EXPIRED the cache image is no longer imported, it is handed to the next build of the pipeline for removal
+	BUILD_CACHE_ENTRY_STATUS_EXPIRED BuildCacheEntryStatus = "EXPIRED"
+	// BUILD_CACHE_ENTRY_STATUS_PRUNED the build the entry was handed to reported the cache image removed
+	BUILD_CACHE_ENTRY_STATUS_PRUNED BuildCacheEntryStatus = "PRUNED"
+)
+
+type CiBuildCacheConfig struct {
+	tableName       struct{}       `sql:"ci_build_cache_config" pg:",discard_unknown_columns"`
+	Id              int            `sql:"id,pk"`
+	CiPipelineId    int            `sql:"ci_pipeline_id,notnull"`
+	Mode            BuildCacheMode `sql:"mode,notnull"`
+	CacheRepository string         `sql:"cache_repository"`
+	ExportMode      string         `sql:"export_mode"`
+	KeyByBranch     bool           `sql:"key_by_branch,notnull"`
+	KeyFiles        string         `sql:"key_files"` //comma separated paths, relative to the checkout path
+	// Generation is part of every cache key, bumping it on invalidation makes the next build miss all older caches
+	Generation    int       `sql:"generation,notnull"`
+	InvalidatedOn time.Time `sql:"invalidated_on"`
+	Active        bool      `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CiBuildCacheEntry struct {
+	tableName    struct{}              `sql:"ci_build_cache_entry" pg:",discard_unknown_columns"`
+	Id           int                   `sql:"id,pk"`
+	CiPipelineId int                   `sql:"ci_pipeline_id,notnull"`
+	CacheKey     string                `sql:"cache_key,notnull"`
+	CacheRef     string                `sql:"cache_ref,notnull"`
+	Status       BuildCacheEntryStatus `sql:"status,notnull"`
+	LastUsedOn   time.Time             `sql:"last_used_on,notnull"`
+	// PruneCiWorkflowId is the build an expired entry was handed to for removal
+	PruneCiWorkflowId int `sql:"prune_ci_workflow_id"`
+	sql.AuditLog
+}
+
+type CiBuildCacheRepository interface {
+	SaveConfig(config *CiBuildCacheConfig) error
+	UpdateConfig(config *CiBuildCacheConfig) error
+	FindActiveConfigByCiPipelineId(ciPipelineId int) (*CiBuildCacheConfig, error)
+
+	SaveEntry(entry *CiBuildCacheEntry) error
+	UpdateEntry(entry *CiBuildCacheEntry) error
+	FindLiveEntryByCacheRef(ciPipelineId int, cacheRef string) (*CiBuildCacheEntry, error)
+	// FindLiveEntriesByCiPipelineId returns live entries, most recently used first
+	FindLiveEntriesByCiPipelineId(ciPipelineId int) ([]*CiBuildCacheEntry, error)
+	// ClaimExpiredEntriesForPrune hands the expired entries not handed to a build yet, or to one which finished
+	// without removing them, to the build given
+	ClaimExpiredEntriesForPrune(ciPipelineId int, ciWorkflowId int, finishedStatuses []string, userId int32) ([]*CiBuildCacheEntry, error)
+	// MarkEntriesPruned marks the entries handed to the build given pruned, for the cache refs it removed
+	MarkEntriesPruned(ciPipelineId int, ciWorkflowId int, cacheRefs []string, userId int32) error
+	FindCiPipelineIdsWithLiveEntries() ([]int, error)
+	UpdateEntriesStatus(ids []int, status BuildCacheEntryStatus, userId int32) error
+	ExpireLiveEntriesByCiPipelineId(ciPipelineId int, userId int32) error
+	ExpireLiveEntriesNotUsedSince(lastUsedBefore time.Time, userId int32) (int, error)
+}
+
+type CiBuildCacheRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCiBuildCacheRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CiBuildCacheRepositoryImpl {
+	return &CiBuildCacheRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CiBuildCacheRepositoryImpl) SaveConfig(config *CiBuildCacheConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *CiBuildCacheRepositoryImpl) UpdateConfig(config *CiBuildCacheConfig) error {
+	return impl.dbConnection.Update(config)
+}
+
+func (impl *CiBuildCacheRepositoryImpl) FindActiveConfigByCiPipelineId(ciPipelineId int) (*CiBuildCacheConfig, error) {
+	config := &CiBuildCacheConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("active = ?", true).
+		Select()
+	return config, err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) SaveEntry(entry *CiBuildCacheEntry) error {
+	return impl.dbConnection.Insert(entry)
+}
+
+func (impl *CiBuildCacheRepositoryImpl) UpdateEntry(entry *CiBuildCacheEntry) error {
+	return impl.dbConnection.Update(entry)
+}
+
+func (impl *CiBuildCacheRepositoryImpl) FindLiveEntryByCacheRef(ciPipelineId int, cacheRef string) (*CiBuildCacheEntry, error) {
+	entry := &CiBuildCacheEntry{}
+	err := impl.dbConnection.Model(entry).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("cache_ref = ?", cacheRef).
+		Where("status = ?", BUILD_CACHE_ENTRY_STATUS_LIVE).
+		Limit(1).
+		Select()
+	return entry, err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) FindLiveEntriesByCiPipelineId(ciPipelineId int) ([]*CiBuildCacheEntry, error) {
+	var entries []*CiBuildCacheEntry
+	err := impl.dbConnection.Model(&entries).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("status = ?", BUILD_CACHE_ENTRY_STATUS_LIVE).
+		Order("last_used_on DESC").
+		Select()
+	return entries, err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) ClaimExpiredEntriesForPrune(ciPipelineId int, ciWorkflowId int, finishedStatuses []string, userId int32) ([]*CiBuildCacheEntry, error) {
+	var entries []*CiBuildCacheEntry
+	query := `UPDATE ci_build_cache_entry SET prune_ci_workflow_id = ?, updated_on = ?, updated_by = ?
+		WHERE ci_pipeline_id = ? AND status = ?
+		AND (prune_ci_workflow_id IS NULL OR prune_ci_workflow_id IN (SELECT id FROM ci_workflow WHERE status IN (?)))
+		RETURNING *;`
+	_, err := impl.dbConnection.Query(&entries, query, ciWorkflowId, time.Now(), userId, ciPipelineId,
+		BUILD_CACHE_ENTRY_STATUS_EXPIRED, pg.In(finishedStatuses))
+	return entries, err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) MarkEntriesPruned(ciPipelineId int, ciWorkflowId int, cacheRefs []string, userId int32) error {
+	if len(cacheRefs) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model((*CiBuildCacheEntry)(nil)).
+		Set("status = ?", BUILD_CACHE_ENTRY_STATUS_PRUNED).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("prune_ci_workflow_id = ?", ciWorkflowId).
+		Where("status = ?", BUILD_CACHE_ENTRY_STATUS_EXPIRED).
+		Where("cache_ref in (?)", pg.In(cacheRefs)).
+		Update()
+	return err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) FindCiPipelineIdsWithLiveEntries() ([]int, error) {
+	var ciPipelineIds []int
+	query := "SELECT DISTINCT ci_pipeline_id FROM ci_build_cache_entry WHERE status = ?;"
+	_, err := impl.dbConnection.Query(&ciPipelineIds, query, BUILD_CACHE_ENTRY_STATUS_LIVE)
+	return ciPipelineIds, err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) UpdateEntriesStatus(ids []int, status BuildCacheEntryStatus, userId int32) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model((*CiBuildCacheEntry)(nil)).
+		Set("status = ?", status).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("id in (?)", pg.In(ids)).
+		Update()
+	return err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) ExpireLiveEntriesByCiPipelineId(ciPipelineId int, userId int32) error {
+	_, err := impl.dbConnection.Model((*CiBuildCacheEntry)(nil)).
+		Set("status = ?", BUILD_CACHE_ENTRY_STATUS_EXPIRED).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("status = ?", BUILD_CACHE_ENTRY_STATUS_LIVE).
+		Update()
+	return err
+}
+
+func (impl *CiBuildCacheRepositoryImpl) ExpireLiveEntriesNotUsedSince(lastUsedBefore time.Time, userId int32) (int, error) {
+	res, err := impl.dbConnection.Model((*CiBuildCacheEntry)(nil)).
+		Set("status = ?", BUILD_CACHE_ENTRY_STATUS_EXPIRED).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("status = ?", BUILD_CACHE_ENTRY_STATUS_LIVE).
+		Where("last_used_on < ?", lastUsedBefore).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
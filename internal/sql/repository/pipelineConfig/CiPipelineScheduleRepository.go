@@ -0,0 +1,141 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ScheduleCommitPolicy string
+
+const (
+	SCHEDULE_COMMIT_POLICY_LATEST     ScheduleCommitPolicy = "LATEST_COMMIT"
+	SCHEDULE_COMMIT_POLICY_LAST_BUILT ScheduleCommitPolicy = "LAST_BUILT_COMMIT"
+)
+
+type ScheduleCatchUpPolicy string
+
+const (
+	SCHEDULE_CATCH_UP_POLICY_SKIP     ScheduleCatchUpPolicy = "SKIP"
+	SCHEDULE_CATCH_UP_POLICY_RUN_ONCE ScheduleCatchUpPolicy = "RUN_ONCE"
+)
+
+type ScheduleRunStatus string
+
+const (
+	SCHEDULE_RUN_STATUS_TRIGGERED ScheduleRunStatus = "TRIGGERED"
+	SCHEDULE_RUN_STATUS_SKIPPED   ScheduleRunStatus = "SKIPPED"
+	SCHEDULE_RUN_STATUS_FAILED    ScheduleRunStatus = "FAILED"
+)
+
+type CiPipelineSchedule struct {
+	tableName      struct{}              `sql:"ci_pipeline_schedule" pg:",discard_unknown_columns"`
+	Id             int                   `sql:"id,pk"`
+	CiPipelineId   int                   `sql:"ci_pipeline_id"`
+	EnvironmentId  int                   `sql:"environment_id"`
+	CronExpression string                `sql:"cron_expression"`
+	Timezone       string                `sql:"timezone"`
+	CommitPolicy   ScheduleCommitPolicy  `sql:"commit_policy"`
+	CatchUpPolicy  ScheduleCatchUpPolicy `sql:"catch_up_policy"`
+	Paused         bool                  `sql:"paused,notnull"`
+	NextRunAt      time.Time             `sql:"next_run_at"`
+	LastRunAt      time.Time             `sql:"last_run_at"`
+	Active         bool                  `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CiPipelineScheduleRun struct {
+	tableName            struct{}          `sql:"ci_pipeline_schedule_run" pg:",discard_unknown_columns"`
+	Id                   int               `sql:"id,pk"`
+	CiPipelineScheduleId int               `sql:"ci_pipeline_schedule_id"`
+	CiWorkflowId         int               `sql:"ci_workflow_id"`
+	ScheduledAt          time.Time         `sql:"scheduled_at"`
+	TriggeredAt          time.Time         `sql:"triggered_at"`
+	TriggeredBy          int32             `sql:"triggered_by"`
+	Status               ScheduleRunStatus `sql:"status"`
+	Message              string            `sql:"message"`
+}
+
+type CiPipelineScheduleRepository interface {
+	Save(schedule *CiPipelineSchedule) error
+	Update(schedule *CiPipelineSchedule) error
+	FindActiveByCiPipelineId(ciPipelineId int) (*CiPipelineSchedule, error)
+	FindDueSchedules(now time.Time) ([]*CiPipelineSchedule, error)
+	// ClaimRun moves next_run_at forward only if no other replica has done it already,
+	// returns true when the caller owns the run
+	ClaimRun(scheduleId int, expectedNextRunAt time.Time, nextRunAt time.Time, lastRunAt time.Time) (bool, error)
+	SaveRun(run *CiPipelineScheduleRun) error
+	FindRunsByScheduleId(scheduleId int, limit int) ([]*CiPipelineScheduleRun, error)
+}
+
+type CiPipelineScheduleRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCiPipelineScheduleRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CiPipelineScheduleRepositoryImpl {
+	return &CiPipelineScheduleRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) Save(schedule *CiPipelineSchedule) error {
+	return impl.dbConnection.Insert(schedule)
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) Update(schedule *CiPipelineSchedule) error {
+	return impl.dbConnection.Update(schedule)
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) FindActiveByCiPipelineId(ciPipelineId int) (*CiPipelineSchedule, error) {
+	schedule := &CiPipelineSchedule{}
+	err := impl.dbConnection.Model(schedule).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return schedule, err
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) FindDueSchedules(now time.Time) ([]*CiPipelineSchedule, error) {
+	var schedules []*CiPipelineSchedule
+	err := impl.dbConnection.Model(&schedules).
+		Where("active = ?", true).
+		Where("paused = ?", false).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Select()
+	return schedules, err
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) ClaimRun(scheduleId int, expectedNextRunAt time.Time, nextRunAt time.Time, lastRunAt time.Time) (bool, error) {
+	res, err := impl.dbConnection.Model(&CiPipelineSchedule{}).
+		Set("next_run_at = ?", nextRunAt).
+		Set("last_run_at = ?", lastRunAt).
+		Where("id = ?", scheduleId).
+		Where("active = ?", true).
+		Where("paused = ?", false).
+		Where("next_run_at = ?", expectedNextRunAt).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in claiming schedule run", "err", err, "scheduleId", scheduleId)
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) SaveRun(run *CiPipelineScheduleRun) error {
+	return impl.dbConnection.Insert(run)
+}
+
+func (impl CiPipelineScheduleRepositoryImpl) FindRunsByScheduleId(scheduleId int, limit int) ([]*CiPipelineScheduleRun, error) {
+	var runs []*CiPipelineScheduleRun
+	err := impl.dbConnection.Model(&runs).
+		Where("ci_pipeline_schedule_id = ?", scheduleId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	return runs, err
+}
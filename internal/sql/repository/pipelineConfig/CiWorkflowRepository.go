@@ -51,24 +51,28 @@ type CiWorkflowRepositoryImpl struct {
 }
 
 type CiWorkflow struct {
-	tableName          struct{}          `sql:"ci_workflow" pg:",discard_unknown_columns"`
-	Id                 int               `sql:"id,pk"`
-	Name               string            `sql:"name"`
-	Status             string            `sql:"status"`
-	PodStatus          string            `sql:"pod_status"`
-	Message            string            `sql:"message"`
-	StartedOn          time.Time         `sql:"started_on"`
-	FinishedOn         time.Time         `sql:"finished_on"`
-	CiPipelineId       int               `sql:"ci_pipeline_id"`
-	Namespace          string            `sql:"namespace"`
-	BlobStorageEnabled bool              `sql:"blob_storage_enabled,notnull"`
-	LogLocation        string            `sql:"log_file_path"`
-	GitTriggers        map[int]GitCommit `sql:"git_triggers"`
-	TriggeredBy        int32             `sql:"triggered_by"`
-	CiArtifactLocation string            `sql:"ci_artifact_location"`
-	PodName            string            `sql:"pod_name"`
-	CiBuildType        string            `sql:"ci_build_type"`
-	EnvironmentId      int               `sql:"environment_id"`
+	tableName          struct{}             `sql:"ci_workflow" pg:",discard_unknown_columns"`
+	Id                 int                  `sql:"id,pk"`
+	Name               string               `sql:"name"`
+	Status             string               `sql:"status"`
+	PodStatus          string               `sql:"pod_status"`
+	Message            string               `sql:"message"`
+	StartedOn          time.Time            `sql:"started_on"`
+	FinishedOn         time.Time            `sql:"finished_on"`
+	CiPipelineId       int                  `sql:"ci_pipeline_id"`
+	Namespace          string               `sql:"namespace"`
+	BlobStorageEnabled bool                 `sql:"blob_storage_enabled,notnull"`
+	LogLocation        string               `sql:"log_file_path"`
+	GitTriggers        map[int]GitCommit    `sql:"git_triggers"`
+	TriggeredBy        int32                `sql:"triggered_by"`
+	CiArtifactLocation string               `sql:"ci_artifact_location"`
+	PodName            string               `sql:"pod_name"`
+	CiBuildType        string               `sql:"ci_build_type"`
+	EnvironmentId      int                  `sql:"environment_id"`
+	ExecutorType       WorkflowExecutorType `sql:"executor_type"` //awf, system
+	BuildCacheKey      string               `sql:"build_cache_key"`
+	BuildCacheHits     int                  `sql:"build_cache_hits"`   // layers served from build cache
+	BuildCacheMisses   int                  `sql:"build_cache_misses"` // layers built
 	CiPipeline         *CiPipeline
 }
 
@@ -0,0 +1,282 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type DeploymentApprovalStatus string
+
+const (
+	DEPLOYMENT_APPROVAL_STATUS_REQUESTED DeploymentApprovalStatus = "REQUESTED"
+	DEPLOYMENT_APPROVAL_STATUS_APPROVED  DeploymentApprovalStatus = "APPROVED"
+	DEPLOYMENT_APPROVAL_STATUS_REJECTED  DeploymentApprovalStatus = "REJECTED"
+	DEPLOYMENT_APPROVAL_STATUS_EXPIRED   DeploymentApprovalStatus = "EXPIRED"
+	DEPLOYMENT_APPROVAL_STATUS_CANCELLED DeploymentApprovalStatus = "CANCELLED"
+	// DEPLOYMENT_APPROVAL_STATUS_CONSUMED approval has been used by a deployment, an approval is valid for a single deployment
+	DEPLOYMENT_APPROVAL_STATUS_CONSUMED DeploymentApprovalStatus = "CONSUMED"
+)
+
+type DeploymentApprovalAction string
+
+const (
+	DEPLOYMENT_APPROVAL_ACTION_APPROVE DeploymentApprovalAction = "APPROVE"
+	DEPLOYMENT_APPROVAL_ACTION_REJECT  DeploymentApprovalAction = "REJECT"
+)
+
+type DeploymentApprovalConfig struct {
+	tableName         struct{} `sql:"deployment_approval_config" pg:",discard_unknown_columns"`
+	Id                int      `sql:"id,pk"`
+	PipelineId        int      `sql:"pipeline_id,notnull"`
+	RequiredApprovals int      `sql:"required_approvals,notnull"`
+	ApproverGroupIds  []int32  `sql:"approver_group_ids" pg:",array"`
+	ExpiryInHours     int      `sql:"expiry_in_hours,notnull"`
+	Active            bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentApprovalRequest struct {
+	tableName          struct{}                      `sql:"deployment_approval_request" json:"-" pg:",discard_unknown_columns"`
+	Id                 int                           `sql:"id,pk" json:"id"`
+	PipelineId         int                           `sql:"pipeline_id,notnull" json:"pipelineId"`
+	CiArtifactId       int                           `sql:"ci_artifact_id,notnull" json:"ciArtifactId"`
+	CdWorkflowRunnerId int                           `sql:"cd_workflow_runner_id" json:"cdWorkflowRunnerId,omitempty"`
+	Status             DeploymentApprovalStatus      `sql:"status,notnull" json:"status"`
+	RequiredApprovals  int                           `sql:"required_approvals,notnull" json:"requiredApprovals"`
+	Comment            string                        `sql:"comment" json:"comment"`
+	ExpiresAt          time.Time                     `sql:"expires_at,notnull" json:"expiresAt"`
+	RequestedBy        int32                         `sql:"requested_by,notnull" json:"requestedBy"`
+	RequestedByEmail   string                        `sql:"-" json:"requestedByEmail"`
+	ApprovalActions    []*DeploymentApprovalUserData `sql:"-" json:"approvalActions"`
+	sql.AuditLog       `json:"-"`
+}
+
+type DeploymentApprovalUserData struct {
+	tableName         struct{}                 `sql:"deployment_approval_user_data" json:"-" pg:",discard_unknown_columns"`
+	Id                int                      `sql:"id,pk" json:"id"`
+	ApprovalRequestId int                      `sql:"approval_request_id,notnull" json:"approvalRequestId"`
+	UserId            int32                    `sql:"user_id,notnull" json:"userId"`
+	UserEmail         string                   `sql:"-" json:"userEmail"`
+	Action            DeploymentApprovalAction `sql:"action,notnull" json:"action"`
+	Comment           string                   `sql:"comment" json:"comment"`
+	ActedOn           time.Time                `sql:"acted_on,notnull" json:"actedOn"`
+}
+
+type DeploymentApprovalRepository interface {
+	SaveConfig(config *DeploymentApprovalConfig) error
+	UpdateConfig(config *DeploymentApprovalConfig) error
+	FindActiveConfigByPipelineId(pipelineId int) (*DeploymentApprovalConfig, error)
+	FindActiveConfigsByPipelineIds(pipelineIds []int) ([]*DeploymentApprovalConfig, error)
+
+	GetConnection() *pg.DB
+	SaveRequest(request *DeploymentApprovalRequest) error
+	FindRequestById(id int) (*DeploymentApprovalRequest, error)
+	// FindRequestByIdForUpdate locks the request for the tx, actions on a request are serialised by this lock
+	FindRequestByIdForUpdate(id int, tx *pg.Tx) (*DeploymentApprovalRequest, error)
+	// UpdateRequestStatus moves the request to the status given only when it is in one of fromStatuses, false is returned
+	// when the request has moved on
+	UpdateRequestStatus(id int, fromStatuses []DeploymentApprovalStatus, status DeploymentApprovalStatus, updatedBy int32, now time.Time, tx *pg.Tx) (bool, error)
+	FindRequestsByPipelineId(pipelineId int, limit int) ([]*DeploymentApprovalRequest, error)
+	FindOpenRequestByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int) (*DeploymentApprovalRequest, error)
+	FindApprovedArtifactIds(pipelineId int, ciArtifactIds []int, now time.Time) ([]int, error)
+	FindRequestsByCdWorkflowRunnerIds(cdWorkflowRunnerIds []int) ([]*DeploymentApprovalRequest, error)
+	// MarkExpired moves requested and approved requests past their expiry to expired
+	MarkExpired(now time.Time) error
+	// Consume moves an approved request of the artifact to consumed, returns 0 when there is no valid approval
+	Consume(pipelineId int, ciArtifactId int, now time.Time) (int, error)
+	// Release moves a consumed request back to approved when the deployment using it did not go through
+	Release(id int, now time.Time) error
+	SetCdWorkflowRunnerId(id int, cdWorkflowRunnerId int) error
+
+	SaveUserData(userData *DeploymentApprovalUserData, tx *pg.Tx) error
+	FindUserDataByRequestIds(requestIds []int) ([]*DeploymentApprovalUserData, error)
+}
+
+type DeploymentApprovalRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDeploymentApprovalRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DeploymentApprovalRepositoryImpl {
+	return &DeploymentApprovalRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) SaveConfig(config *DeploymentApprovalConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) UpdateConfig(config *DeploymentApprovalConfig) error {
+	return impl.dbConnection.Update(config)
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindActiveConfigByPipelineId(pipelineId int) (*DeploymentApprovalConfig, error) {
+	config := &DeploymentApprovalConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Select()
+	return config, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindActiveConfigsByPipelineIds(pipelineIds []int) ([]*DeploymentApprovalConfig, error) {
+	var configs []*DeploymentApprovalConfig
+	if len(pipelineIds) == 0 {
+		return configs, nil
+	}
+	err := impl.dbConnection.Model(&configs).
+		Where("pipeline_id in (?)", pg.In(pipelineIds)).
+		Where("active = ?", true).
+		Select()
+	return configs, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) SaveRequest(request *DeploymentApprovalRequest) error {
+	return impl.dbConnection.Insert(request)
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindRequestById(id int) (*DeploymentApprovalRequest, error) {
+	request := &DeploymentApprovalRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("id = ?", id).
+		Select()
+	return request, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindRequestByIdForUpdate(id int, tx *pg.Tx) (*DeploymentApprovalRequest, error) {
+	request := &DeploymentApprovalRequest{}
+	err := tx.Model(request).
+		Where("id = ?", id).
+		For("UPDATE").
+		Select()
+	return request, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) UpdateRequestStatus(id int, fromStatuses []DeploymentApprovalStatus, status DeploymentApprovalStatus, updatedBy int32, now time.Time, tx *pg.Tx) (bool, error) {
+	res, err := tx.Model((*DeploymentApprovalRequest)(nil)).
+		Set("status = ?", status).
+		Set("updated_on = ?", now).
+		Set("updated_by = ?", updatedBy).
+		Where("id = ?", id).
+		Where("status in (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindRequestsByPipelineId(pipelineId int, limit int) ([]*DeploymentApprovalRequest, error) {
+	var requests []*DeploymentApprovalRequest
+	err := impl.dbConnection.Model(&requests).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	return requests, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindOpenRequestByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int) (*DeploymentApprovalRequest, error) {
+	request := &DeploymentApprovalRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("status in (?)", pg.In([]DeploymentApprovalStatus{DEPLOYMENT_APPROVAL_STATUS_REQUESTED, DEPLOYMENT_APPROVAL_STATUS_APPROVED})).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return request, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindApprovedArtifactIds(pipelineId int, ciArtifactIds []int, now time.Time) ([]int, error) {
+	var artifactIds []int
+	if len(ciArtifactIds) == 0 {
+		return artifactIds, nil
+	}
+	err := impl.dbConnection.Model((*DeploymentApprovalRequest)(nil)).
+		Column("ci_artifact_id").
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id in (?)", pg.In(ciArtifactIds)).
+		Where("status = ?", DEPLOYMENT_APPROVAL_STATUS_APPROVED).
+		Where("expires_at > ?", now).
+		Select(&artifactIds)
+	return artifactIds, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindRequestsByCdWorkflowRunnerIds(cdWorkflowRunnerIds []int) ([]*DeploymentApprovalRequest, error) {
+	var requests []*DeploymentApprovalRequest
+	if len(cdWorkflowRunnerIds) == 0 {
+		return requests, nil
+	}
+	err := impl.dbConnection.Model(&requests).
+		Where("cd_workflow_runner_id in (?)", pg.In(cdWorkflowRunnerIds)).
+		Select()
+	return requests, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) MarkExpired(now time.Time) error {
+	_, err := impl.dbConnection.Model((*DeploymentApprovalRequest)(nil)).
+		Set("status = ?", DEPLOYMENT_APPROVAL_STATUS_EXPIRED).
+		Set("updated_on = ?", now).
+		Where("status in (?)", pg.In([]DeploymentApprovalStatus{DEPLOYMENT_APPROVAL_STATUS_REQUESTED, DEPLOYMENT_APPROVAL_STATUS_APPROVED})).
+		Where("expires_at <= ?", now).
+		Update()
+	return err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) Consume(pipelineId int, ciArtifactId int, now time.Time) (int, error) {
+	var approvalRequestId int
+	// the status check in the update makes sure two concurrent deployments cannot use the same approval
+	_, err := impl.dbConnection.Query(pg.Scan(&approvalRequestId),
+		`UPDATE deployment_approval_request SET status = ?, updated_on = ?
+		WHERE id = (SELECT id FROM deployment_approval_request WHERE pipeline_id = ? AND ci_artifact_id = ? AND status = ? AND expires_at > ? ORDER BY id DESC LIMIT 1)
+		AND status = ? RETURNING id;`,
+		DEPLOYMENT_APPROVAL_STATUS_CONSUMED, now, pipelineId, ciArtifactId, DEPLOYMENT_APPROVAL_STATUS_APPROVED, now, DEPLOYMENT_APPROVAL_STATUS_APPROVED)
+	if err == pg.ErrNoRows {
+		return 0, nil
+	}
+	return approvalRequestId, err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) Release(id int, now time.Time) error {
+	_, err := impl.dbConnection.Model((*DeploymentApprovalRequest)(nil)).
+		Set("status = ?", DEPLOYMENT_APPROVAL_STATUS_APPROVED).
+		Set("cd_workflow_runner_id = NULL").
+		Set("updated_on = ?", now).
+		Where("id = ?", id).
+		Where("status = ?", DEPLOYMENT_APPROVAL_STATUS_CONSUMED).
+		Update()
+	return err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) SetCdWorkflowRunnerId(id int, cdWorkflowRunnerId int) error {
+	_, err := impl.dbConnection.Model((*DeploymentApprovalRequest)(nil)).
+		Set("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) SaveUserData(userData *DeploymentApprovalUserData, tx *pg.Tx) error {
+	return tx.Insert(userData)
+}
+
+func (impl *DeploymentApprovalRepositoryImpl) FindUserDataByRequestIds(requestIds []int) ([]*DeploymentApprovalUserData, error) {
+	var userData []*DeploymentApprovalUserData
+	if len(requestIds) == 0 {
+		return userData, nil
+	}
+	err := impl.dbConnection.Model(&userData).
+		Where("approval_request_id in (?)", pg.In(requestIds)).
+		Order("id ASC").
+		Select()
+	return userData, err
+}
@@ -59,6 +59,7 @@ type Pipeline struct {
 	DeploymentAppType             string      `sql:"deployment_app_type,notnull"` //helm, acd
 	DeploymentAppName             string      `sql:"deployment_app_name"`
 	DeploymentAppDeleteRequest    bool        `sql:"deployment_app_delete_request,notnull"`
+	ValidateImagePlatforms        bool        `sql:"validate_image_platforms,notnull"`
 	Environment                   repository.Environment
 	sql.AuditLog
 }
@@ -110,6 +111,7 @@ type PipelineRepository interface {
 	FindActiveByAppIds(appIds []int) (pipelines []*Pipeline, err error)
 	FindAppAndEnvironmentAndProjectByPipelineIds(pipelineIds []int) (pipelines []*Pipeline, err error)
 	FilterDeploymentDeleteRequestedPipelineIds(cdPipelineIds []int) (map[int]bool, error)
+	FindActiveWithDeploymentAppCreated() (pipelines []*Pipeline, err error)
 }
 
 type CiArtifactDTO struct {
@@ -649,6 +651,17 @@ func (impl PipelineRepositoryImpl) FindActiveByAppIds(appIds []int) (pipelines [
 	return pipelines, err
 }
 
+func (impl PipelineRepositoryImpl) FindActiveWithDeploymentAppCreated() (pipelines []*Pipeline, err error) {
+	err = impl.dbConnection.Model(&pipelines).
+		Column("pipeline.*", "App", "Environment").
+		Join("inner join app a on pipeline.app_id = a.id").
+		Where("a.active = ?", true).
+		Where("pipeline.deleted = ?", false).
+		Where("pipeline.deployment_app_created = ?", true).
+		Select()
+	return pipelines, err
+}
+
 func (impl PipelineRepositoryImpl) FindAppAndEnvironmentAndProjectByPipelineIds(pipelineIds []int) (pipelines []*Pipeline, err error) {
 	err = impl.dbConnection.Model(&pipelines).Column("pipeline.*", "App", "Environment", "App.Team").
 		Where("pipeline.id in(?)", pg.In(pipelineIds)).
@@ -12,23 +12,34 @@ type TimelineStatus = string
 var TimelineStatusDescription string
 
 const (
-	TIMELINE_STATUS_DEPLOYMENT_INITIATED   TimelineStatus = "DEPLOYMENT_INITIATED"
-	TIMELINE_STATUS_GIT_COMMIT             TimelineStatus = "GIT_COMMIT"
-	TIMELINE_STATUS_GIT_COMMIT_FAILED      TimelineStatus = "GIT_COMMIT_FAILED"
-	TIMELINE_STATUS_KUBECTL_APPLY_STARTED  TimelineStatus = "KUBECTL_APPLY_STARTED"
-	TIMELINE_STATUS_KUBECTL_APPLY_SYNCED   TimelineStatus = "KUBECTL_APPLY_SYNCED"
-	TIMELINE_STATUS_APP_HEALTHY            TimelineStatus = "HEALTHY"
-	TIMELINE_STATUS_DEPLOYMENT_FAILED      TimelineStatus = "FAILED"
-	TIMELINE_STATUS_FETCH_TIMED_OUT        TimelineStatus = "TIMED_OUT"
-	TIMELINE_STATUS_UNABLE_TO_FETCH_STATUS TimelineStatus = "UNABLE_TO_FETCH_STATUS"
-	TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED  TimelineStatus = "DEPLOYMENT_SUPERSEDED"
-	TIMELINE_STATUS_MANIFEST_GENERATED     TimelineStatus = "MANIFEST_GENERATED"
+	TIMELINE_STATUS_DEPLOYMENT_INITIATED      TimelineStatus = "DEPLOYMENT_INITIATED"
+	TIMELINE_STATUS_GIT_COMMIT                TimelineStatus = "GIT_COMMIT"
+	TIMELINE_STATUS_GIT_COMMIT_FAILED         TimelineStatus = "GIT_COMMIT_FAILED"
+	TIMELINE_STATUS_OCI_CHART_PUSHED          TimelineStatus = "OCI_CHART_PUSHED"
+	TIMELINE_STATUS_OCI_CHART_PUSH_FAILED     TimelineStatus = "OCI_CHART_PUSH_FAILED"
+	TIMELINE_STATUS_KUBECTL_APPLY_STARTED     TimelineStatus = "KUBECTL_APPLY_STARTED"
+	TIMELINE_STATUS_KUBECTL_APPLY_SYNCED      TimelineStatus = "KUBECTL_APPLY_SYNCED"
+	TIMELINE_STATUS_APP_HEALTHY               TimelineStatus = "HEALTHY"
+	TIMELINE_STATUS_DEPLOYMENT_FAILED         TimelineStatus = "FAILED"
+	TIMELINE_STATUS_FETCH_TIMED_OUT           TimelineStatus = "TIMED_OUT"
+	TIMELINE_STATUS_UNABLE_TO_FETCH_STATUS    TimelineStatus = "UNABLE_TO_FETCH_STATUS"
+	TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED     TimelineStatus = "DEPLOYMENT_SUPERSEDED"
+	TIMELINE_STATUS_MANIFEST_GENERATED        TimelineStatus = "MANIFEST_GENERATED"
+	TIMELINE_STATUS_CANARY_ANALYSIS_STARTED   TimelineStatus = "CANARY_ANALYSIS_STARTED"
+	TIMELINE_STATUS_CANARY_ANALYSIS_SUCCEEDED TimelineStatus = "CANARY_ANALYSIS_SUCCEEDED"
+	TIMELINE_STATUS_CANARY_ANALYSIS_FAILED    TimelineStatus = "CANARY_ANALYSIS_FAILED"
+	TIMELINE_STATUS_CANARY_ANALYSIS_ABORTED   TimelineStatus = "CANARY_ANALYSIS_ABORTED"
+	TIMELINE_STATUS_ROLLBACK_TRIGGERED        TimelineStatus = "ROLLBACK_TRIGGERED"
+	TIMELINE_STATUS_ROLLBACK_FAILED           TimelineStatus = "ROLLBACK_FAILED"
 )
 
 const (
-	TIMELINE_DESCRIPTION_DEPLOYMENT_INITIATED string = "Deployment initiated successfully."
-	TIMELINE_DESCRIPTION_VULNERABLE_IMAGE     string = "Deployment failed: Vulnerability policy violated."
-	TIMELINE_DESCRIPTION_MANIFEST_GENERATED   string = "HELM_PACKAGE_GENERATED"
+	TIMELINE_DESCRIPTION_DEPLOYMENT_INITIATED       string = "Deployment initiated successfully."
+	TIMELINE_DESCRIPTION_VULNERABLE_IMAGE           string = "Deployment failed: Vulnerability policy violated."
+	TIMELINE_DESCRIPTION_MANIFEST_GENERATED         string = "HELM_PACKAGE_GENERATED"
+	TIMELINE_DESCRIPTION_CANARY_ANALYSIS_STARTED    string = "Canary analysis started."
+	TIMELINE_DESCRIPTION_UNVERIFIED_IMAGE           string = "Deployment failed: Image signature verification policy violated."
+	TIMELINE_DESCRIPTION_UNSUPPORTED_IMAGE_PLATFORM string = "Deployment failed: Image is not built for the node platforms of the cluster."
 )
 
 type PipelineStatusTimelineRepository interface {
@@ -200,7 +211,7 @@ func (impl *PipelineStatusTimelineRepositoryImpl) FetchLatestTimelineByWfrId(wfr
 }
 
 func (impl *PipelineStatusTimelineRepositoryImpl) CheckIfTerminalStatusTimelinePresentByWfrId(wfrId int) (bool, error) {
-	terminalStatus := []string{string(TIMELINE_STATUS_APP_HEALTHY), string(TIMELINE_STATUS_DEPLOYMENT_FAILED), string(TIMELINE_STATUS_GIT_COMMIT_FAILED), string(TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED)}
+	terminalStatus := []string{string(TIMELINE_STATUS_APP_HEALTHY), string(TIMELINE_STATUS_DEPLOYMENT_FAILED), string(TIMELINE_STATUS_GIT_COMMIT_FAILED), string(TIMELINE_STATUS_OCI_CHART_PUSH_FAILED), string(TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED)}
 	timeline := &PipelineStatusTimeline{}
 	exists, err := impl.dbConnection.Model(timeline).
 		Where("cd_workflow_runner_id = ?", wfrId).
@@ -213,7 +224,7 @@ func (impl *PipelineStatusTimelineRepositoryImpl) CheckIfTerminalStatusTimelineP
 }
 
 func (impl *PipelineStatusTimelineRepositoryImpl) CheckIfTerminalStatusTimelinePresentByInstalledAppVersionHistoryId(installedAppVersionHistoryId int) (bool, error) {
-	terminalStatus := []string{string(TIMELINE_STATUS_APP_HEALTHY), string(TIMELINE_STATUS_DEPLOYMENT_FAILED), string(TIMELINE_STATUS_GIT_COMMIT_FAILED), string(TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED)}
+	terminalStatus := []string{string(TIMELINE_STATUS_APP_HEALTHY), string(TIMELINE_STATUS_DEPLOYMENT_FAILED), string(TIMELINE_STATUS_GIT_COMMIT_FAILED), string(TIMELINE_STATUS_OCI_CHART_PUSH_FAILED), string(TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED)}
 	timeline := &PipelineStatusTimeline{}
 	exists, err := impl.dbConnection.Model(timeline).
 		Where("installed_app_version_history_id = ?", installedAppVersionHistoryId).
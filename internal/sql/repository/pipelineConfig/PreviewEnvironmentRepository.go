@@ -0,0 +1,173 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type PreviewEnvironmentStatus string
+
+const (
+	PREVIEW_ENVIRONMENT_STATUS_CREATING PreviewEnvironmentStatus = "CREATING"
+	PREVIEW_ENVIRONMENT_STATUS_DEPLOYED PreviewEnvironmentStatus = "DEPLOYED"
+	PREVIEW_ENVIRONMENT_STATUS_FAILED   PreviewEnvironmentStatus = "FAILED"
+	// PREVIEW_ENVIRONMENT_STATUS_DELETE_FAILED teardown did not complete, it is retried on the next expiry check
+	PREVIEW_ENVIRONMENT_STATUS_DELETE_FAILED PreviewEnvironmentStatus = "DELETE_FAILED"
+	PREVIEW_ENVIRONMENT_STATUS_DELETED       PreviewEnvironmentStatus = "DELETED"
+)
+
+// PreviewEnvironmentConfig enables preview mode on a cd pipeline, the pipeline then acts as the template of the
+// environments created for pull request builds of its ci pipeline
+type PreviewEnvironmentConfig struct {
+	tableName            struct{} `sql:"preview_environment_config" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	PipelineId           int      `sql:"pipeline_id,notnull"`
+	ClusterId            int      `sql:"cluster_id,notnull"`
+	NamespacePrefix      string   `sql:"namespace_prefix,notnull"`
+	ValuesOverride       string   `sql:"values_override"`
+	UrlTemplate          string   `sql:"url_template"`
+	TtlInHours           int      `sql:"ttl_in_hours,notnull"`
+	CommentOnPullRequest bool     `sql:"comment_on_pull_request,notnull"`
+	Active               bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type PreviewEnvironment struct {
+	tableName          struct{}                 `sql:"preview_environment" json:"-" pg:",discard_unknown_columns"`
+	Id                 int                      `sql:"id,pk" json:"id"`
+	ConfigId           int                      `sql:"config_id,notnull" json:"configId"`
+	TemplatePipelineId int                      `sql:"template_pipeline_id,notnull" json:"templatePipelineId"`
+	PullRequestUrl     string                   `sql:"pull_request_url,notnull" json:"pullRequestUrl"`
+	PullRequestNumber  int                      `sql:"pull_request_number,notnull" json:"pullRequestNumber"`
+	PullRequestTitle   string                   `sql:"pull_request_title" json:"pullRequestTitle"`
+	SourceBranch       string                   `sql:"source_branch" json:"sourceBranch"`
+	EnvironmentId      int                      `sql:"environment_id" json:"environmentId,omitempty"`
+	PipelineId         int                      `sql:"pipeline_id" json:"pipelineId,omitempty"` //cd pipeline created for the preview
+	Namespace          string                   `sql:"namespace,notnull" json:"namespace"`
+	NamespaceCreated   bool                     `sql:"namespace_created,notnull" json:"-"` //namespace was created for the preview and is deleted with it
+	Url                string                   `sql:"url" json:"url,omitempty"`
+	CiArtifactId       int                      `sql:"ci_artifact_id" json:"ciArtifactId,omitempty"`
+	Status             PreviewEnvironmentStatus `sql:"status,notnull" json:"status"`
+	Message            string                   `sql:"message" json:"message,omitempty"`
+	Commented          bool                     `sql:"commented,notnull" json:"commented"`
+	ExpiresOn          time.Time                `sql:"expires_on,notnull" json:"expiresOn"`
+	sql.AuditLog       `json:"-"`
+}
+
+type PreviewEnvironmentRepository interface {
+	SaveConfig(config *PreviewEnvironmentConfig) error
+	UpdateConfig(config *PreviewEnvironmentConfig) error
+	FindActiveConfigByPipelineId(pipelineId int) (*PreviewEnvironmentConfig, error)
+	FindActiveConfigsByPipelineIds(pipelineIds []int) ([]*PreviewEnvironmentConfig, error)
+
+	Save(previewEnvironment *PreviewEnvironment) error
+	Update(previewEnvironment *PreviewEnvironment) error
+	FindById(id int) (*PreviewEnvironment, error)
+	FindLiveByTemplatePipelineId(templatePipelineId int) ([]*PreviewEnvironment, error)
+	FindLiveByTemplatePipelineIdAndPullRequestUrl(templatePipelineId int, pullRequestUrl string) (*PreviewEnvironment, error)
+	FindLiveByPullRequestUrl(pullRequestUrl string) ([]*PreviewEnvironment, error)
+	FindExpired(now time.Time) ([]*PreviewEnvironment, error)
+}
+
+type PreviewEnvironmentRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPreviewEnvironmentRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *PreviewEnvironmentRepositoryImpl {
+	return &PreviewEnvironmentRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) SaveConfig(config *PreviewEnvironmentConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) UpdateConfig(config *PreviewEnvironmentConfig) error {
+	return impl.dbConnection.Update(config)
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindActiveConfigByPipelineId(pipelineId int) (*PreviewEnvironmentConfig, error) {
+	config := &PreviewEnvironmentConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Select()
+	return config, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindActiveConfigsByPipelineIds(pipelineIds []int) ([]*PreviewEnvironmentConfig, error) {
+	var configs []*PreviewEnvironmentConfig
+	if len(pipelineIds) == 0 {
+		return configs, nil
+	}
+	err := impl.dbConnection.Model(&configs).
+		Where("pipeline_id in (?)", pg.In(pipelineIds)).
+		Where("active = ?", true).
+		Select()
+	return configs, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) Save(previewEnvironment *PreviewEnvironment) error {
+	return impl.dbConnection.Insert(previewEnvironment)
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) Update(previewEnvironment *PreviewEnvironment) error {
+	return impl.dbConnection.Update(previewEnvironment)
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindById(id int) (*PreviewEnvironment, error) {
+	previewEnvironment := &PreviewEnvironment{}
+	err := impl.dbConnection.Model(previewEnvironment).
+		Where("id = ?", id).
+		Select()
+	return previewEnvironment, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindLiveByTemplatePipelineId(templatePipelineId int) ([]*PreviewEnvironment, error) {
+	var previewEnvironments []*PreviewEnvironment
+	err := impl.dbConnection.Model(&previewEnvironments).
+		Where("template_pipeline_id = ?", templatePipelineId).
+		Where("status != ?", PREVIEW_ENVIRONMENT_STATUS_DELETED).
+		Order("id DESC").
+		Select()
+	return previewEnvironments, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindLiveByTemplatePipelineIdAndPullRequestUrl(templatePipelineId int, pullRequestUrl string) (*PreviewEnvironment, error) {
+	previewEnvironment := &PreviewEnvironment{}
+	err := impl.dbConnection.Model(previewEnvironment).
+		Where("template_pipeline_id = ?", templatePipelineId).
+		Where("pull_request_url = ?", pullRequestUrl).
+		Where("status != ?", PREVIEW_ENVIRONMENT_STATUS_DELETED).
+		Select()
+	return previewEnvironment, err
+}
+
+func (impl *PreviewEnvironmentRepositoryImpl) FindLiveByPullRequestUrl(pullRequestUrl string) ([]*PreviewEnvironment, error) {
+	var previewEnvironments []*PreviewEnvironment
+	err := impl.dbConnection.Model(&previewEnvironments).
+		Where("pull_request_url = ?", pullRequestUrl).
+		Where("status != ?", PREVIEW_ENVIRONMENT_STATUS_DELETED).
+		Select()
+	return previewEnvironments, err
+}
+
+// FindExpired returns live previews past their ttl and previews whose earlier teardown failed
+func (impl *PreviewEnvironmentRepositoryImpl) FindExpired(now time.Time) ([]*PreviewEnvironment, error) {
+	var previewEnvironments []*PreviewEnvironment
+	err := impl.dbConnection.Model(&previewEnvironments).
+		Where("status != ?", PREVIEW_ENVIRONMENT_STATUS_DELETED).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("expires_on <= ?", now).
+				WhereOr("status = ?", PREVIEW_ENVIRONMENT_STATUS_DELETE_FAILED)
+			return q, nil
+		}).
+		Select()
+	return previewEnvironments, err
+}
@@ -417,6 +417,52 @@ func (_m *CdWorkflowRepository) FindPreviousCdWfRunnerByStatus(pipelineId int, c
 	return r0, r1
 }
 
+// FindLastSucceededDeployRunnerBefore provides a mock function with given fields: pipelineId, currentWFRunnerId
+func (_m *CdWorkflowRepository) FindLastSucceededDeployRunnerBefore(pipelineId int, currentWFRunnerId int) (*pipelineConfig.CdWorkflowRunner, error) {
+	ret := _m.Called(pipelineId, currentWFRunnerId)
+
+	var r0 *pipelineConfig.CdWorkflowRunner
+	if rf, ok := ret.Get(0).(func(int, int) *pipelineConfig.CdWorkflowRunner); ok {
+		r0 = rf(pipelineId, currentWFRunnerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pipelineConfig.CdWorkflowRunner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(pipelineId, currentWFRunnerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatestRunnerByPipelineIdAndArtifactId provides a mock function with given fields: pipelineId, ciArtifactId, runnerType, statuses
+func (_m *CdWorkflowRepository) FindLatestRunnerByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int, runnerType bean.WorkflowType, statuses []string) (*pipelineConfig.CdWorkflowRunner, error) {
+	ret := _m.Called(pipelineId, ciArtifactId, runnerType, statuses)
+
+	var r0 *pipelineConfig.CdWorkflowRunner
+	if rf, ok := ret.Get(0).(func(int, int, bean.WorkflowType, []string) *pipelineConfig.CdWorkflowRunner); ok {
+		r0 = rf(pipelineId, ciArtifactId, runnerType, statuses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pipelineConfig.CdWorkflowRunner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, bean.WorkflowType, []string) error); ok {
+		r1 = rf(pipelineId, ciArtifactId, runnerType, statuses)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindWorkflowRunnerByCdWorkflowId provides a mock function with given fields: wfIds
 func (_m *CdWorkflowRepository) FindWorkflowRunnerByCdWorkflowId(wfIds []int) ([]*pipelineConfig.CdWorkflowRunner, error) {
 	ret := _m.Called(wfIds)
@@ -314,6 +314,32 @@ func (_m *PipelineRepository) FindActiveByNotFilter(envId int, appIdExcludes []i
 	return r0, r1
 }
 
+// FindActiveWithDeploymentAppCreated provides a mock function with given fields:
+func (_m *PipelineRepository) FindActiveWithDeploymentAppCreated() ([]*pipelineConfig.Pipeline, error) {
+	ret := _m.Called()
+
+	var r0 []*pipelineConfig.Pipeline
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*pipelineConfig.Pipeline, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*pipelineConfig.Pipeline); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*pipelineConfig.Pipeline)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAllPipelineInLast24Hour provides a mock function with given fields:
 func (_m *PipelineRepository) FindAllPipelineInLast24Hour() ([]*pipelineConfig.Pipeline, error) {
 	ret := _m.Called()
@@ -0,0 +1,225 @@
+package security
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+)
+
+type ImageSignatureType string
+
+const (
+	IMAGE_SIGNATURE_TYPE_SIGNATURE   ImageSignatureType = "SIGNATURE"
+	IMAGE_SIGNATURE_TYPE_ATTESTATION ImageSignatureType = "ATTESTATION"
+)
+
+// ImageSigningKey is a cosign key pair, only the public key is kept here. The private key and its password live in
+// a secret in the ci namespace which is mounted in the ci runner
+type ImageSigningKey struct {
+	tableName  struct{} `sql:"image_signing_key" pg:",discard_unknown_columns"`
+	Id         int      `sql:"id,pk"`
+	Name       string   `sql:"name,notnull"`
+	PublicKey  string   `sql:"public_key,notnull"`
+	SecretName string   `sql:"secret_name,notnull"`
+	Active     bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// ImageSigningConfig enables signing of images built by a ci pipeline as a post ci step
+type ImageSigningConfig struct {
+	tableName           struct{} `sql:"image_signing_config" pg:",discard_unknown_columns"`
+	Id                  int      `sql:"id,pk"`
+	CiPipelineId        int      `sql:"ci_pipeline_id,notnull"`
+	SigningKeyId        int      `sql:"signing_key_id,notnull"`
+	GenerateAttestation bool     `sql:"generate_attestation,notnull"`
+	Active              bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ImageSignature struct {
+	tableName     struct{}           `sql:"image_signature" pg:",discard_unknown_columns"`
+	Id            int                `sql:"id,pk"`
+	CiArtifactId  int                `sql:"ci_artifact_id,notnull"`
+	ImageDigest   string             `sql:"image_digest,notnull"`
+	SignatureType ImageSignatureType `sql:"signature_type,notnull"`
+	SigningKeyId  int                `sql:"signing_key_id"`
+	PredicateType string             `sql:"predicate_type"`
+	Payload       string             `sql:"payload,notnull"`
+	Signature     string             `sql:"signature,notnull"`
+	sql.AuditLog
+}
+
+// ImageSigningPolicy decides whether unsigned images can be deployed, levels are resolved like CvePolicy
+type ImageSigningPolicy struct {
+	tableName     struct{}     `sql:"image_signing_policy" pg:",discard_unknown_columns"`
+	Id            int          `sql:"id,pk"`
+	Global        bool         `sql:"global,notnull"`
+	ClusterId     int          `sql:"cluster_id"`
+	EnvironmentId int          `sql:"env_id"`
+	AppId         int          `sql:"app_id"`
+	Action        PolicyAction `sql:"action,notnull"`
+	// SigningKeyId is the key signatures must be verifiable with, any active key when 0
+	SigningKeyId int  `sql:"signing_key_id"`
+	Deleted      bool `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (policy *ImageSigningPolicy) PolicyLevel() PolicyLevel {
+	if policy.ClusterId != 0 {
+		return Cluster
+	} else if policy.AppId != 0 {
+		return Application
+	} else if policy.EnvironmentId != 0 {
+		return Environment
+	} else {
+		return Global
+	}
+}
+
+type ImageSigningRepository interface {
+	SaveKey(key *ImageSigningKey) error
+	UpdateKey(key *ImageSigningKey) error
+	FindKeyById(id int) (*ImageSigningKey, error)
+	FindActiveKeys() ([]*ImageSigningKey, error)
+
+	SaveConfig(config *ImageSigningConfig) error
+	UpdateConfig(config *ImageSigningConfig) error
+	FindActiveConfigByCiPipelineId(ciPipelineId int) (*ImageSigningConfig, error)
+
+	SaveSignatures(signatures []*ImageSignature) error
+	FindSignaturesByImageDigest(imageDigest string) ([]*ImageSignature, error)
+	FindSignaturesByCiArtifactId(ciArtifactId int) ([]*ImageSignature, error)
+
+	SavePolicy(policy *ImageSigningPolicy) error
+	UpdatePolicy(policy *ImageSigningPolicy) error
+	FindPolicyByScope(clusterId, envId, appId int) (*ImageSigningPolicy, error)
+	// FindPoliciesForScope returns the global policy and all policies of the enclosing cluster, environment and app
+	FindPoliciesForScope(clusterId, envId, appId int) ([]*ImageSigningPolicy, error)
+}
+
+type ImageSigningRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageSigningRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageSigningRepositoryImpl {
+	return &ImageSigningRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ImageSigningRepositoryImpl) SaveKey(key *ImageSigningKey) error {
+	return impl.dbConnection.Insert(key)
+}
+
+func (impl *ImageSigningRepositoryImpl) UpdateKey(key *ImageSigningKey) error {
+	return impl.dbConnection.Update(key)
+}
+
+func (impl *ImageSigningRepositoryImpl) FindKeyById(id int) (*ImageSigningKey, error) {
+	key := &ImageSigningKey{}
+	err := impl.dbConnection.Model(key).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return key, err
+}
+
+func (impl *ImageSigningRepositoryImpl) FindActiveKeys() ([]*ImageSigningKey, error) {
+	var keys []*ImageSigningKey
+	err := impl.dbConnection.Model(&keys).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return keys, err
+}
+
+func (impl *ImageSigningRepositoryImpl) SaveConfig(config *ImageSigningConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *ImageSigningRepositoryImpl) UpdateConfig(config *ImageSigningConfig) error {
+	return impl.dbConnection.Update(config)
+}
+
+func (impl *ImageSigningRepositoryImpl) FindActiveConfigByCiPipelineId(ciPipelineId int) (*ImageSigningConfig, error) {
+	config := &ImageSigningConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("active = ?", true).
+		Select()
+	return config, err
+}
+
+func (impl *ImageSigningRepositoryImpl) SaveSignatures(signatures []*ImageSignature) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+	return impl.dbConnection.Insert(&signatures)
+}
+
+func (impl *ImageSigningRepositoryImpl) FindSignaturesByImageDigest(imageDigest string) ([]*ImageSignature, error) {
+	var signatures []*ImageSignature
+	err := impl.dbConnection.Model(&signatures).
+		Where("image_digest = ?", imageDigest).
+		Order("id DESC").
+		Select()
+	return signatures, err
+}
+
+func (impl *ImageSigningRepositoryImpl) FindSignaturesByCiArtifactId(ciArtifactId int) ([]*ImageSignature, error) {
+	var signatures []*ImageSignature
+	err := impl.dbConnection.Model(&signatures).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Order("id ASC").
+		Select()
+	return signatures, err
+}
+
+func (impl *ImageSigningRepositoryImpl) SavePolicy(policy *ImageSigningPolicy) error {
+	return impl.dbConnection.Insert(policy)
+}
+
+func (impl *ImageSigningRepositoryImpl) UpdatePolicy(policy *ImageSigningPolicy) error {
+	return impl.dbConnection.Update(policy)
+}
+
+func (impl *ImageSigningRepositoryImpl) FindPolicyByScope(clusterId, envId, appId int) (*ImageSigningPolicy, error) {
+	policy := &ImageSigningPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("COALESCE(cluster_id, 0) = ?", clusterId).
+		Where("COALESCE(env_id, 0) = ?", envId).
+		Where("COALESCE(app_id, 0) = ?", appId).
+		Where("deleted = ?", false).
+		Select()
+	return policy, err
+}
+
+func (impl *ImageSigningRepositoryImpl) FindPoliciesForScope(clusterId, envId, appId int) ([]*ImageSigningPolicy, error) {
+	var policies []*ImageSigningPolicy
+	err := impl.dbConnection.Model(&policies).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("global = true")
+			if clusterId > 0 {
+				q = q.WhereOr("cluster_id = ?", clusterId)
+			}
+			if envId > 0 {
+				q = q.WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					sq = sq.Where("env_id = ?", envId).Where("app_id is null")
+					return sq, nil
+				})
+			}
+			if envId > 0 && appId > 0 {
+				q = q.WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					sq = sq.Where("env_id = ?", envId).Where("app_id = ?", appId)
+					return sq, nil
+				})
+			}
+			return q, nil
+		}).
+		Where("deleted = ?", false).
+		Select()
+	return policies, err
+}
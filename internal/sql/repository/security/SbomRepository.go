@@ -0,0 +1,198 @@
+package security
+
+import (
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strings"
+)
+
+type SbomFormat string
+
+const (
+	SBOM_FORMAT_SPDX_JSON      SbomFormat = "spdx-json"
+	SBOM_FORMAT_CYCLONEDX_JSON SbomFormat = "cyclonedx-json"
+)
+
+type SbomSource string
+
+const (
+	SBOM_SOURCE_CI   SbomSource = "CI"
+	SBOM_SOURCE_SCAN SbomSource = "SCAN"
+)
+
+// ImageSbom is the bill of materials of an image in one format, the document is kept as generated for download
+type ImageSbom struct {
+	tableName    struct{}   `sql:"image_sbom" pg:",discard_unknown_columns"`
+	Id           int        `sql:"id,pk"`
+	CiArtifactId int        `sql:"ci_artifact_id,notnull"`
+	ImageDigest  string     `sql:"image_digest,notnull"`
+	Format       SbomFormat `sql:"format,notnull"`
+	SpecVersion  string     `sql:"spec_version"`
+	Source       SbomSource `sql:"source,notnull"`
+	Document     string     `sql:"document,notnull"`
+	PackageCount int        `sql:"package_count,notnull"`
+	Active       bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// ImageSbomPackage is a package listed in an sbom, kept in a table of its own to be queried across images
+type ImageSbomPackage struct {
+	tableName   struct{} `sql:"image_sbom_package" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	ImageSbomId int      `sql:"image_sbom_id,notnull"`
+	ImageDigest string   `sql:"image_digest,notnull"`
+	Name        string   `sql:"name,notnull"`
+	Version     string   `sql:"version"`
+	Type        string   `sql:"type"`
+	Purl        string   `sql:"purl"`
+	License     string   `sql:"license"`
+}
+
+type SbomPackageFilter struct {
+	PackageName    string
+	PackageType    string
+	EnvironmentIds []int
+	ClusterIds     []int
+}
+
+// SbomPackageExposure is a package found in an image deployed in an environment
+type SbomPackageExposure struct {
+	AppId           int    `sql:"app_id"`
+	AppName         string `sql:"app_name"`
+	EnvId           int    `sql:"env_id"`
+	EnvironmentName string `sql:"environment_name"`
+	ClusterId       int    `sql:"cluster_id"`
+	ClusterName     string `sql:"cluster_name"`
+	CiArtifactId    int    `sql:"ci_artifact_id"`
+	Image           string `sql:"image"`
+	ImageDigest     string `sql:"image_digest"`
+	PackageName     string `sql:"package_name"`
+	PackageVersion  string `sql:"package_version"`
+	PackageType     string `sql:"package_type"`
+	Purl            string `sql:"purl"`
+}
+
+type SbomRepository interface {
+	GetConnection() *pg.DB
+	SaveSbom(sbom *ImageSbom, tx *pg.Tx) error
+	SavePackages(packages []*ImageSbomPackage, tx *pg.Tx) error
+	// DeactivateSbomsByImageDigestAndFormat replaces older sboms of the image, packages of those are removed
+	DeactivateSbomsByImageDigestAndFormat(imageDigest string, format SbomFormat, userId int32, tx *pg.Tx) error
+	FindActiveSbomsByImageDigest(imageDigest string) ([]*ImageSbom, error)
+	FindActiveSbomByImageDigestAndFormat(imageDigest string, format SbomFormat) (*ImageSbom, error)
+	// FindDeployedPackageExposure lists packages matching the filter in images deployed as per image_scan_deploy_info
+	FindDeployedPackageExposure(filter *SbomPackageFilter) ([]*SbomPackageExposure, error)
+}
+
+type SbomRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewSbomRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *SbomRepositoryImpl {
+	return &SbomRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *SbomRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *SbomRepositoryImpl) SaveSbom(sbom *ImageSbom, tx *pg.Tx) error {
+	return tx.Insert(sbom)
+}
+
+func (impl *SbomRepositoryImpl) SavePackages(packages []*ImageSbomPackage, tx *pg.Tx) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&packages).Insert()
+	return err
+}
+
+func (impl *SbomRepositoryImpl) DeactivateSbomsByImageDigestAndFormat(imageDigest string, format SbomFormat, userId int32, tx *pg.Tx) error {
+	var sbomIds []int
+	_, err := tx.Query(&sbomIds, "SELECT id FROM image_sbom WHERE image_digest = ? AND format = ? AND active = true", imageDigest, format)
+	if err != nil || len(sbomIds) == 0 {
+		return err
+	}
+	_, err = tx.Model((*ImageSbomPackage)(nil)).Where("image_sbom_id in (?)", pg.In(sbomIds)).Delete()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Model((*ImageSbom)(nil)).
+		Set("active = ?", false).Set("updated_on = now()").Set("updated_by = ?", userId).
+		Where("id in (?)", pg.In(sbomIds)).
+		Update()
+	return err
+}
+
+func (impl *SbomRepositoryImpl) FindActiveSbomsByImageDigest(imageDigest string) ([]*ImageSbom, error) {
+	var sboms []*ImageSbom
+	err := impl.dbConnection.Model(&sboms).
+		Column("id", "ci_artifact_id", "image_digest", "format", "spec_version", "source", "package_count", "active", "created_on", "created_by", "updated_on", "updated_by").
+		Where("image_digest = ?", imageDigest).
+		Where("active = ?", true).
+		Order("format").
+		Select()
+	return sboms, err
+}
+
+func (impl *SbomRepositoryImpl) FindActiveSbomByImageDigestAndFormat(imageDigest string, format SbomFormat) (*ImageSbom, error) {
+	sbom := &ImageSbom{}
+	err := impl.dbConnection.Model(sbom).
+		Where("image_digest = ?", imageDigest).
+		Where("format = ?", format).
+		Where("active = ?", true).
+		Order("id desc").Limit(1).
+		Select()
+	return sbom, err
+}
+
+func (impl *SbomRepositoryImpl) FindDeployedPackageExposure(filter *SbomPackageFilter) ([]*SbomPackageExposure, error) {
+	var exposures []*SbomPackageExposure
+	// the artifact of the latest successful deployment of each pipeline is the one running in its environment
+	query := "SELECT DISTINCT p.app_id, a.app_name, env.id as env_id, env.environment_name," +
+		" c.id as cluster_id, c.cluster_name, cia.id as ci_artifact_id, cia.image, cia.image_digest," +
+		" pkg.name as package_name, pkg.version as package_version, pkg.type as package_type, pkg.purl" +
+		" FROM pipeline p" +
+		" INNER JOIN LATERAL (SELECT wf.ci_artifact_id FROM cd_workflow_runner wfr" +
+		" INNER JOIN cd_workflow wf ON wf.id = wfr.cd_workflow_id" +
+		" WHERE wf.pipeline_id = p.id AND wfr.workflow_type = ? AND wfr.status IN (?)" +
+		" ORDER BY wfr.started_on DESC LIMIT 1) deployed ON true" +
+		" INNER JOIN ci_artifact cia ON cia.id = deployed.ci_artifact_id" +
+		" INNER JOIN image_sbom_package pkg ON pkg.image_digest = cia.image_digest" +
+		" INNER JOIN image_sbom sbom ON sbom.id = pkg.image_sbom_id AND sbom.active = true" +
+		" INNER JOIN app a ON a.id = p.app_id AND a.active = true" +
+		" INNER JOIN environment env ON env.id = p.environment_id" +
+		" INNER JOIN cluster c ON c.id = env.cluster_id" +
+		" WHERE p.deleted = false AND env.active = true AND lower(pkg.name) = ?"
+	params := []interface{}{bean.CD_WORKFLOW_TYPE_DEPLOY, pg.In([]string{pipelineConfig.WorkflowSucceeded, string(health.HealthStatusHealthy)}),
+		strings.ToLower(filter.PackageName)}
+	if len(filter.PackageType) > 0 {
+		query = query + " AND lower(pkg.type) = ?"
+		params = append(params, strings.ToLower(filter.PackageType))
+	}
+	if len(filter.EnvironmentIds) > 0 {
+		query = query + " AND env.id IN (?)"
+		params = append(params, pg.In(filter.EnvironmentIds))
+	}
+	if len(filter.ClusterIds) > 0 {
+		query = query + " AND c.id IN (?)"
+		params = append(params, pg.In(filter.ClusterIds))
+	}
+	query = query + " ORDER BY a.app_name, env.environment_name"
+	_, err := impl.dbConnection.Query(&exposures, query, params...)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed package exposure", "err", err, "filter", fmt.Sprintf("%+v", *filter))
+		return nil, err
+	}
+	return exposures, nil
+}
@@ -82,6 +82,7 @@ type ChartTemplateService interface {
 	UpdateGitRepoUrlInCharts(appId int, chartGitAttribute *ChartGitAttribute, userId int32) error
 	CreateAndPushToGitChartProxy(appStoreName, tmpChartLocation string, envName string, installAppVersionRequest *appStoreBean.InstallAppVersionDTO) (chartGitAttribute *ChartGitAttribute, err error)
 	LoadChartInBytes(ChartPath string, deleteChart bool) ([]byte, error)
+	PackageChartWithValues(chartMetaData *chart.Metadata, chartPath string, values string) ([]byte, error)
 }
 type ChartTemplateServiceImpl struct {
 	randSource             rand.Source
@@ -771,6 +772,35 @@ func (impl ChartTemplateServiceImpl) LoadChartInBytes(ChartPath string, deleteCh
 	return bs, err
 }
 
+// PackageChartWithValues replaces values.yaml of the built chart at chartPath and returns the gzipped chart archive
+func (impl ChartTemplateServiceImpl) PackageChartWithValues(chartMetaData *chart.Metadata, chartPath string, values string) ([]byte, error) {
+	chartMetaData.ApiVersion = "v1" // ensure always v1
+	// archives left by earlier packaging would otherwise be bundled as chart files
+	archives, err := filepath.Glob(filepath.Join(chartPath, "*.tgz"))
+	if err != nil {
+		return nil, err
+	}
+	for _, archive := range archives {
+		err = os.Remove(archive)
+		if err != nil {
+			impl.logger.Errorw("error in removing old chart archive", "archive", archive, "err", err)
+			return nil, err
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(chartPath, "values.yaml"), []byte(values), 0600)
+	if err != nil {
+		impl.logger.Errorw("err in writing values.yaml", "dir", chartPath, "err", err)
+		return nil, err
+	}
+	archivePath, _, err := impl.packageChart(chartPath, chartMetaData)
+	if err != nil {
+		impl.logger.Errorw("error in creating archive", "err", err)
+		return nil, err
+	}
+	defer os.Remove(*archivePath)
+	return ioutil.ReadFile(*archivePath)
+}
+
 func IsHelmApp(deploymentAppType string) bool {
 	return deploymentAppType == PIPELINE_DEPLOYMENT_TYPE_HELM
 }
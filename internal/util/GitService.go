@@ -37,18 +37,20 @@ import (
 )
 
 const (
-	GIT_WORKING_DIR       = "/tmp/gitops/"
-	GetRepoUrlStage       = "Get Repo Url"
-	CreateRepoStage       = "Create Repo"
-	CloneHttpStage        = "Clone Http"
-	CreateReadmeStage     = "Create Readme"
-	CloneSshStage         = "Clone Ssh"
-	GITLAB_PROVIDER       = "GITLAB"
-	GITHUB_PROVIDER       = "GITHUB"
-	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
-	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
-	GITHUB_API_V3         = "api/v3"
-	GITHUB_HOST           = "github.com"
+	GIT_WORKING_DIR           = "/tmp/gitops/"
+	GetRepoUrlStage           = "Get Repo Url"
+	CreateRepoStage           = "Create Repo"
+	CloneHttpStage            = "Clone Http"
+	CreateReadmeStage         = "Create Readme"
+	CloneSshStage             = "Clone Ssh"
+	GITLAB_PROVIDER           = "GITLAB"
+	GITHUB_PROVIDER           = "GITHUB"
+	AZURE_DEVOPS_PROVIDER     = "AZURE_DEVOPS"
+	BITBUCKET_PROVIDER        = "BITBUCKET_CLOUD"
+	GITEA_PROVIDER            = "GITEA"
+	BITBUCKET_SERVER_PROVIDER = "BITBUCKET_SERVER"
+	GITHUB_API_V3             = "api/v3"
+	GITHUB_HOST               = "github.com"
 )
 
 type GitClient interface {
@@ -140,6 +142,26 @@ func (factory *GitFactory) GetGitLabGroupPath(gitOpsConfig *bean2.GitOpsConfigDt
 	return group.FullPath, nil
 }
 
+// GetGiteaOwner returns the owner gitops repos are created under in gitea, the organisation when configured else the
+// login of the token owner
+func (factory *GitFactory) GetGiteaOwner(gitOpsConfig *bean2.GitOpsConfigDto) (string, error) {
+	start := time.Now()
+	var err error
+	defer func() {
+		util.TriggerGitOpsMetrics("GetGiteaOwner", "GitService", start, err)
+	}()
+	giteaClient, err := NewGitGiteaClient(gitOpsConfig.Host, gitOpsConfig.Token, gitOpsConfig.GiteaOrgId, factory.logger, nil)
+	if err != nil {
+		return "", err
+	}
+	owner, err := giteaClient.owner()
+	if err != nil {
+		factory.logger.Errorw("error in fetching gitea owner", "err", err, "host", gitOpsConfig.Host)
+		return "", err
+	}
+	return owner, nil
+}
+
 func (factory *GitFactory) NewClientForValidation(gitOpsConfig *bean2.GitOpsConfigDto) (GitClient, *GitServiceImpl, error) {
 	start := time.Now()
 	var err error
@@ -158,6 +180,7 @@ func (factory *GitFactory) NewClientForValidation(gitOpsConfig *bean2.GitOpsConf
 		AzureProject:         gitOpsConfig.AzureProjectName,
 		BitbucketWorkspaceId: gitOpsConfig.BitBucketWorkspaceId,
 		BitbucketProjectKey:  gitOpsConfig.BitBucketProjectKey,
+		GiteaOrgId:           gitOpsConfig.GiteaOrgId,
 	}
 	gitService := NewGitServiceImpl(cfg, logger, factory.gitCliUtil)
 	//factory.GitService = GitService
@@ -204,6 +227,7 @@ type GitConfig struct {
 	AzureProject         string
 	BitbucketWorkspaceId string
 	BitbucketProjectKey  string
+	GiteaOrgId           string
 }
 
 func GetGitConfig(gitOpsRepository repository.GitOpsConfigRepository) (*GitConfig, error) {
@@ -233,6 +257,7 @@ func GetGitConfig(gitOpsRepository repository.GitOpsConfigRepository) (*GitConfi
 		AzureProject:         gitOpsConfig.AzureProject,
 		BitbucketWorkspaceId: gitOpsConfig.BitBucketWorkspaceId,
 		BitbucketProjectKey:  gitOpsConfig.BitBucketProjectKey,
+		GiteaOrgId:           gitOpsConfig.GiteaOrgId,
 	}
 	return cfg, err
 }
@@ -250,6 +275,12 @@ func NewGitOpsClient(config *GitConfig, logger *zap.SugaredLogger, gitService Gi
 	} else if config.GitProvider == BITBUCKET_PROVIDER {
 		gitBitbucketClient := NewGitBitbucketClient(config.GitUserName, config.GitToken, config.GitHost, logger, gitService, gitOpsConfigRepository)
 		return gitBitbucketClient, nil
+	} else if config.GitProvider == GITEA_PROVIDER {
+		gitGiteaClient, err := NewGitGiteaClient(config.GitHost, config.GitToken, config.GiteaOrgId, logger, gitService)
+		return gitGiteaClient, err
+	} else if config.GitProvider == BITBUCKET_SERVER_PROVIDER {
+		gitBitbucketServerClient, err := NewGitBitbucketServerClient(config.GitHost, config.GitUserName, config.GitToken, config.BitbucketProjectKey, logger, gitService)
+		return gitBitbucketServerClient, err
 	} else {
 		logger.Errorw("no gitops config provided, gitops will not work ")
 		return nil, nil
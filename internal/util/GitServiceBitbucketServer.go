@@ -0,0 +1,371 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	http2 "net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"go.uber.org/zap"
+)
+
+const (
+	BITBUCKET_SERVER_API_V1         = "/rest/api/1.0"
+	BITBUCKET_SERVER_DEFAULT_BRANCH = "master"
+)
+
+// GitBitbucketServerClient talks to the bitbucket server / data center rest api, repositories live in the configured project
+type GitBitbucketServerClient struct {
+	httpClient *http2.Client
+	hostUrl    string
+	username   string
+	token      string
+	projectKey string
+	logger     *zap.SugaredLogger
+	gitService GitService
+}
+
+type bitbucketServerRepository struct {
+	Slug  string `json:"slug"`
+	Links struct {
+		Clone []struct {
+			Href string `json:"href"`
+			Name string `json:"name"`
+		} `json:"clone"`
+	} `json:"links"`
+}
+
+type bitbucketServerCommit struct {
+	Id     string `json:"id"`
+	Author struct {
+		Name string `json:"name"`
+	} `json:"author"`
+	CommitterTimestamp int64 `json:"committerTimestamp"`
+}
+
+type bitbucketServerCommitPage struct {
+	Values []bitbucketServerCommit `json:"values"`
+}
+
+type BitbucketServerApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *BitbucketServerApiError) Error() string {
+	return fmt.Sprintf("bitbucket server api returned %d: %s", e.StatusCode, e.Message)
+}
+
+func NewGitBitbucketServerClient(host string, username string, token string, projectKey string, logger *zap.SugaredLogger, gitService GitService) (GitBitbucketServerClient, error) {
+	hostUrl, err := url.ParseRequestURI(host)
+	if err != nil || len(hostUrl.Host) == 0 {
+		logger.Errorw("error in creating bitbucket server client", "host", host, "err", err)
+		return GitBitbucketServerClient{}, fmt.Errorf("invalid bitbucket server host %q", host)
+	}
+	return GitBitbucketServerClient{
+		httpClient: &http2.Client{Timeout: 60 * time.Second},
+		hostUrl:    strings.TrimSuffix(hostUrl.String(), "/"),
+		username:   username,
+		token:      token,
+		projectKey: strings.ToUpper(projectKey),
+		logger:     logger,
+		gitService: gitService,
+	}, nil
+}
+
+func (impl GitBitbucketServerClient) doRequest(method string, apiPath string, contentType string, body []byte, response interface{}) error {
+	req, err := http2.NewRequest(method, impl.hostUrl+BITBUCKET_SERVER_API_V1+apiPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(impl.username, impl.token)
+	req.Header.Set("Accept", "application/json")
+	// required by bitbucket server for multipart requests, harmless otherwise
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &BitbucketServerApiError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+		errBody := struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}{}
+		if json.Unmarshal(respBody, &errBody) == nil && len(errBody.Errors) > 0 {
+			apiErr.Message = errBody.Errors[0].Message
+		}
+		return apiErr
+	}
+	if response != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, response)
+	}
+	return nil
+}
+
+func (impl GitBitbucketServerClient) doJsonRequest(method string, apiPath string, body interface{}, response interface{}) error {
+	if body == nil {
+		return impl.doRequest(method, apiPath, "", nil, response)
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return impl.doRequest(method, apiPath, "application/json", reqBody, response)
+}
+
+func isBitbucketServerNotFound(err error) bool {
+	apiErr, ok := err.(*BitbucketServerApiError)
+	return ok && apiErr.StatusCode == http2.StatusNotFound
+}
+
+func (impl GitBitbucketServerClient) reposPath() string {
+	return fmt.Sprintf("/projects/%s/repos", url.PathEscape(impl.projectKey))
+}
+
+func (impl GitBitbucketServerClient) repoPath(repoName string) string {
+	return impl.reposPath() + "/" + url.PathEscape(strings.ToLower(repoName))
+}
+
+// cloneUrl picks the http clone link, falling back to the url layout used by bitbucket server
+func (impl GitBitbucketServerClient) cloneUrl(repo *bitbucketServerRepository) string {
+	for _, link := range repo.Links.Clone {
+		if link.Name == "http" || link.Name == "https" {
+			return link.Href
+		}
+	}
+	return fmt.Sprintf("%s/scm/%s/%s.git", impl.hostUrl, strings.ToLower(impl.projectKey), repo.Slug)
+}
+
+func (impl GitBitbucketServerClient) getRepository(repoName string) (*bitbucketServerRepository, error) {
+	repo := &bitbucketServerRepository{}
+	err := impl.doJsonRequest(http2.MethodGet, impl.repoPath(repoName), nil, repo)
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func (impl GitBitbucketServerClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, err error) {
+	repo, err := impl.getRepository(config.GitRepoName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching bitbucket server repo", "repoName", config.GitRepoName, "err", err)
+		return "", err
+	}
+	return impl.cloneUrl(repo), nil
+}
+
+func (impl GitBitbucketServerClient) CreateRepository(config *bean2.GitOpsConfigDto) (url string, isNew bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	repo, err := impl.getRepository(config.GitRepoName)
+	if err != nil && !isBitbucketServerNotFound(err) {
+		impl.logger.Errorw("error in communication with bitbucket server", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[GetRepoUrlStage] = err
+		return "", false, detailedErrorGitOpsConfigActions
+	}
+	if err == nil {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, GetRepoUrlStage)
+		return impl.cloneUrl(repo), false, detailedErrorGitOpsConfigActions
+	}
+	createRequest := map[string]interface{}{
+		"name":          config.GitRepoName,
+		"scmId":         "git",
+		"public":        false,
+		"defaultBranch": BITBUCKET_SERVER_DEFAULT_BRANCH,
+	}
+	if len(config.Description) > 0 {
+		createRequest["description"] = config.Description
+	}
+	repo = &bitbucketServerRepository{}
+	err = impl.doJsonRequest(http2.MethodPost, impl.reposPath(), createRequest, repo)
+	if err != nil {
+		impl.logger.Errorw("error in creating bitbucket server repo", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateRepoStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	repoUrl := impl.cloneUrl(repo)
+	impl.logger.Infow("bitbucket server repo created", "repoUrl", repoUrl)
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(config.GitRepoName)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability bitbucket server", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneHttpStage)
+
+	_, err = impl.CreateReadme(config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme bitbucket server", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateReadmeStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(config.GitRepoName, repoUrl)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability bitbucket server", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneSshStage)
+	return repoUrl, true, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitBitbucketServerClient) ensureProjectAvailabilityOnHttp(repoName string) (bool, error) {
+	for count := 0; count < 5; count++ {
+		_, err := impl.getRepository(repoName)
+		if err == nil {
+			impl.logger.Infow("repo validated successfully on https")
+			return true, nil
+		} else if !isBitbucketServerNotFound(err) {
+			impl.logger.Errorw("error in validating repo bitbucket server", "repoName", repoName, "err", err)
+			return false, err
+		}
+		impl.logger.Errorw("repo not available on http", "repoName", repoName)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitBitbucketServerClient) ensureProjectAvailabilityOnSsh(repoName string, repoUrl string) (bool, error) {
+	for count := 0; count < 5; count++ {
+		_, err := impl.gitService.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", repoName))
+		if err == nil {
+			impl.logger.Infow("ensureProjectAvailability clone passed bitbucket server", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		}
+		impl.logger.Errorw("ensureProjectAvailability clone failed bitbucket server", "try count", count, "err", err)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitBitbucketServerClient) DeleteRepository(config *bean2.GitOpsConfigDto) error {
+	err := impl.doJsonRequest(http2.MethodDelete, impl.repoPath(config.GitRepoName), nil, nil)
+	if err != nil {
+		impl.logger.Errorw("error in deleting repo bitbucket server", "repoName", config.GitRepoName, "err", err)
+	}
+	return err
+}
+
+func (impl GitBitbucketServerClient) CreateReadme(config *bean2.GitOpsConfigDto) (string, error) {
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "pushing readme",
+		ChartRepoName:  config.GitRepoName,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(cfg, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme bitbucket server", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+// lastCommitForFile returns the latest commit touching the file on the default branch, empty when the file is new
+func (impl GitBitbucketServerClient) lastCommitForFile(repoName string, fileName string) (string, error) {
+	page := &bitbucketServerCommitPage{}
+	query := url.Values{}
+	query.Set("path", fileName)
+	query.Set("until", BITBUCKET_SERVER_DEFAULT_BRANCH)
+	query.Set("limit", "1")
+	err := impl.doJsonRequest(http2.MethodGet, impl.repoPath(repoName)+"/commits?"+query.Encode(), nil, page)
+	if err != nil {
+		// empty repositories have no default branch yet
+		if isBitbucketServerNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(page.Values) == 0 {
+		return "", nil
+	}
+	return page.Values[0].Id, nil
+}
+
+// CommitValues uses the file edit api, sourceCommitId is mandatory when the file already exists
+func (impl GitBitbucketServerClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
+	fileName := filepath.Join(config.ChartLocation, config.FileName)
+	sourceCommitId, err := impl.lastCommitForFile(config.ChartRepoName, fileName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching last commit for file bitbucket server", "repo", config.ChartRepoName, "file", fileName, "err", err)
+		return "", time.Time{}, err
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fields := map[string]string{
+		"content": config.FileContent,
+		"message": config.ReleaseMessage,
+		"branch":  BITBUCKET_SERVER_DEFAULT_BRANCH,
+	}
+	if len(sourceCommitId) > 0 {
+		fields["sourceCommitId"] = sourceCommitId
+	}
+	for key, value := range fields {
+		if err = writer.WriteField(key, value); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return "", time.Time{}, err
+	}
+	commit := &bitbucketServerCommit{}
+	err = impl.doRequest(http2.MethodPut, impl.repoPath(config.ChartRepoName)+"/browse/"+fileName, writer.FormDataContentType(), body.Bytes(), commit)
+	if err != nil {
+		impl.logger.Errorw("error in committing file to bitbucket server", "repo", config.ChartRepoName, "file", fileName, "err", err)
+		return "", time.Time{}, err
+	}
+	commitTime = time.Now()
+	if commit.CommitterTimestamp > 0 {
+		commitTime = time.Unix(0, commit.CommitterTimestamp*int64(time.Millisecond))
+	}
+	return commit.Id, commitTime, nil
+}
+
+func (impl GitBitbucketServerClient) GetCommits(repoName, projectName string) ([]*GitCommitDto, error) {
+	page := &bitbucketServerCommitPage{}
+	err := impl.doJsonRequest(http2.MethodGet, impl.repoPath(repoName)+"/commits?until="+BITBUCKET_SERVER_DEFAULT_BRANCH, nil, page)
+	if err != nil {
+		impl.logger.Errorw("error in getting commits", "err", err, "repoName", repoName)
+		return nil, err
+	}
+	var gitCommitsDto []*GitCommitDto
+	for _, commit := range page.Values {
+		gitCommitsDto = append(gitCommitsDto, &GitCommitDto{
+			CommitHash: commit.Id,
+			AuthorName: commit.Author.Name,
+			CommitTime: time.Unix(0, commit.CommitterTimestamp*int64(time.Millisecond)),
+		})
+	}
+	return gitCommitsDto, nil
+}
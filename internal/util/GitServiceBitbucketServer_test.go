@@ -0,0 +1,65 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGitBitbucketServerClient_CommitValues(t *testing.T) {
+	var sourceCommitIds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "admin", username)
+		assert.Equal(t, "secret", password)
+		const repoPath = "/rest/api/1.0/projects/DEV/repos/payments"
+		switch {
+		case r.URL.Path == repoPath:
+			_, _ = w.Write([]byte(`{"slug":"payments","links":{"clone":[{"href":"ssh://git@bbs.local/dev/payments.git","name":"ssh"},{"href":"https://bbs.local/scm/dev/payments.git","name":"http"}]}}`))
+		case r.URL.Path == repoPath+"/commits" && r.URL.Query().Get("path") != "":
+			if len(sourceCommitIds) == 0 {
+				_, _ = w.Write([]byte(`{"values":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"values":[{"id":"first"}]}`))
+		case r.URL.Path == repoPath+"/browse/payments/values.yaml" && r.Method == http.MethodPut:
+			assert.Equal(t, "replicaCount: 1", r.FormValue("content"))
+			assert.Equal(t, BITBUCKET_SERVER_DEFAULT_BRANCH, r.FormValue("branch"))
+			sourceCommitIds = append(sourceCommitIds, r.FormValue("sourceCommitId"))
+			_, _ = w.Write([]byte(`{"id":"first","committerTimestamp":1672653600000}`))
+		case r.URL.Path == repoPath+"/commits":
+			_, _ = w.Write([]byte(`{"values":[{"id":"first","author":{"name":"admin"},"committerTimestamp":1672653600000}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Repository does not exist."}]}`))
+		}
+	}))
+	defer server.Close()
+	client, err := NewGitBitbucketServerClient(server.URL, "admin", "secret", "dev", zap.NewNop().Sugar(), nil)
+	assert.Nil(t, err)
+
+	repoUrl, err := client.GetRepoUrl(&bean.GitOpsConfigDto{GitRepoName: "Payments"})
+	assert.Nil(t, err)
+	assert.Equal(t, "https://bbs.local/scm/dev/payments.git", repoUrl)
+
+	config := &ChartConfig{ChartLocation: "payments", FileName: "values.yaml", FileContent: "replicaCount: 1", ChartRepoName: "payments"}
+	for i := 0; i < 2; i++ {
+		hash, commitTime, err := client.CommitValues(config, &bean.GitOpsConfigDto{})
+		assert.Nil(t, err)
+		assert.Equal(t, "first", hash)
+		assert.Equal(t, 2023, commitTime.UTC().Year())
+	}
+	assert.Equal(t, []string{"", "first"}, sourceCommitIds)
+
+	commits, err := client.GetCommits("payments", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(commits))
+
+	_, err = client.GetRepoUrl(&bean.GitOpsConfigDto{GitRepoName: "missing"})
+	assert.True(t, isBitbucketServerNotFound(err))
+	assert.Equal(t, "Repository does not exist.", err.(*BitbucketServerApiError).Message)
+}
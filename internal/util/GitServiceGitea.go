@@ -0,0 +1,359 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	http2 "net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"go.uber.org/zap"
+)
+
+const (
+	GITEA_API_V1         = "/api/v1"
+	GITEA_DEFAULT_BRANCH = "master"
+)
+
+// GitGiteaClient talks to the gitea api, repositories are created in the organisation when configured else under the token owner
+type GitGiteaClient struct {
+	httpClient *http2.Client
+	baseUrl    string
+	token      string
+	org        string
+	logger     *zap.SugaredLogger
+	gitService GitService
+}
+
+type giteaRepository struct {
+	Name          string `json:"name"`
+	CloneUrl      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type giteaCommit struct {
+	Sha    string `json:"sha"`
+	Commit struct {
+		Author struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+type giteaFileResponse struct {
+	Commit struct {
+		Sha       string `json:"sha"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+type GiteaApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *GiteaApiError) Error() string {
+	return fmt.Sprintf("gitea api returned %d: %s", e.StatusCode, e.Message)
+}
+
+func NewGitGiteaClient(host string, token string, org string, logger *zap.SugaredLogger, gitService GitService) (GitGiteaClient, error) {
+	hostUrl, err := url.ParseRequestURI(host)
+	if err != nil || len(hostUrl.Host) == 0 {
+		logger.Errorw("error in creating gitea client", "host", host, "err", err)
+		return GitGiteaClient{}, fmt.Errorf("invalid gitea host %q", host)
+	}
+	return GitGiteaClient{
+		httpClient: &http2.Client{Timeout: 60 * time.Second},
+		baseUrl:    strings.TrimSuffix(hostUrl.String(), "/") + GITEA_API_V1,
+		token:      token,
+		org:        org,
+		logger:     logger,
+		gitService: gitService,
+	}, nil
+}
+
+func (impl GitGiteaClient) doRequest(method string, apiPath string, body interface{}, response interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http2.NewRequest(method, impl.baseUrl+apiPath, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+impl.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &GiteaApiError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+		errBody := struct {
+			Message string `json:"message"`
+		}{}
+		if json.Unmarshal(respBody, &errBody) == nil && len(errBody.Message) > 0 {
+			apiErr.Message = errBody.Message
+		}
+		return apiErr
+	}
+	if response != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, response)
+	}
+	return nil
+}
+
+func isGiteaNotFound(err error) bool {
+	apiErr, ok := err.(*GiteaApiError)
+	return ok && apiErr.StatusCode == http2.StatusNotFound
+}
+
+// owner returns the organisation repositories live in, or the login of the token owner when no organisation is configured
+func (impl GitGiteaClient) owner() (string, error) {
+	if len(impl.org) > 0 {
+		return impl.org, nil
+	}
+	user := struct {
+		Login string `json:"login"`
+	}{}
+	err := impl.doRequest(http2.MethodGet, "/user", nil, &user)
+	return user.Login, err
+}
+
+func (impl GitGiteaClient) getRepository(repoName string) (*giteaRepository, error) {
+	owner, err := impl.owner()
+	if err != nil {
+		return nil, err
+	}
+	repo := &giteaRepository{}
+	err = impl.doRequest(http2.MethodGet, fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repoName)), nil, repo)
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func (impl GitGiteaClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, err error) {
+	repo, err := impl.getRepository(config.GitRepoName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching gitea repo", "repoName", config.GitRepoName, "err", err)
+		return "", err
+	}
+	return repo.CloneUrl, nil
+}
+
+func (impl GitGiteaClient) CreateRepository(config *bean2.GitOpsConfigDto) (url string, isNew bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	repo, err := impl.getRepository(config.GitRepoName)
+	if err != nil && !isGiteaNotFound(err) {
+		impl.logger.Errorw("error in communication with gitea", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[GetRepoUrlStage] = err
+		return "", false, detailedErrorGitOpsConfigActions
+	}
+	if err == nil {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, GetRepoUrlStage)
+		return repo.CloneUrl, false, detailedErrorGitOpsConfigActions
+	}
+	createRequest := map[string]interface{}{
+		"name":           config.GitRepoName,
+		"description":    config.Description,
+		"private":        true,
+		"auto_init":      true,
+		"readme":         "Default",
+		"default_branch": GITEA_DEFAULT_BRANCH,
+	}
+	createPath := "/user/repos"
+	if len(impl.org) > 0 {
+		createPath = fmt.Sprintf("/orgs/%s/repos", impl.org)
+	}
+	repo = &giteaRepository{}
+	err = impl.doRequest(http2.MethodPost, createPath, createRequest, repo)
+	if err != nil {
+		impl.logger.Errorw("error in creating gitea repo", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateRepoStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	impl.logger.Infow("gitea repo created", "repoUrl", repo.CloneUrl)
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(config.GitRepoName)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneHttpStage)
+
+	_, err = impl.CreateReadme(config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateReadmeStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(config.GitRepoName, repo.CloneUrl)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "repoName", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneSshStage)
+	return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnHttp(repoName string) (bool, error) {
+	for count := 0; count < 5; count++ {
+		_, err := impl.getRepository(repoName)
+		if err == nil {
+			impl.logger.Infow("repo validated successfully on https")
+			return true, nil
+		} else if !isGiteaNotFound(err) {
+			impl.logger.Errorw("error in validating repo gitea", "repoName", repoName, "err", err)
+			return false, err
+		}
+		impl.logger.Errorw("repo not available on http", "repoName", repoName)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnSsh(repoName string, repoUrl string) (bool, error) {
+	for count := 0; count < 5; count++ {
+		_, err := impl.gitService.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", repoName))
+		if err == nil {
+			impl.logger.Infow("ensureProjectAvailability clone passed gitea", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		}
+		impl.logger.Errorw("ensureProjectAvailability clone failed gitea", "try count", count, "err", err)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) DeleteRepository(config *bean2.GitOpsConfigDto) error {
+	owner, err := impl.owner()
+	if err != nil {
+		return err
+	}
+	err = impl.doRequest(http2.MethodDelete, fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(config.GitRepoName)), nil, nil)
+	if err != nil {
+		impl.logger.Errorw("error in deleting repo gitea", "repoName", config.GitRepoName, "err", err)
+	}
+	return err
+}
+
+func (impl GitGiteaClient) CreateReadme(config *bean2.GitOpsConfigDto) (string, error) {
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "pushing readme",
+		ChartRepoName:  config.GitRepoName,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(cfg, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+// CommitValues creates the file or updates it in place when it already exists on the default branch
+func (impl GitGiteaClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
+	owner, err := impl.owner()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	fileName := filepath.Join(config.ChartLocation, config.FileName)
+	contentPath := fmt.Sprintf("/repos/%s/%s/contents/%s", url.PathEscape(owner), url.PathEscape(config.ChartRepoName), fileName)
+	existingFile := struct {
+		Sha string `json:"sha"`
+	}{}
+	err = impl.doRequest(http2.MethodGet, contentPath+"?ref="+GITEA_DEFAULT_BRANCH, nil, &existingFile)
+	if err != nil && !isGiteaNotFound(err) {
+		impl.logger.Errorw("error in fetching file from gitea", "repo", config.ChartRepoName, "file", fileName, "err", err)
+		return "", time.Time{}, err
+	}
+	fileRequest := map[string]interface{}{
+		"content": base64.StdEncoding.EncodeToString([]byte(config.FileContent)),
+		"message": config.ReleaseMessage,
+		"branch":  GITEA_DEFAULT_BRANCH,
+		"author":  map[string]string{"name": config.UserName, "email": config.UserEmailId},
+	}
+	method := http2.MethodPost
+	if len(existingFile.Sha) > 0 {
+		method = http2.MethodPut
+		fileRequest["sha"] = existingFile.Sha
+	}
+	fileResponse := &giteaFileResponse{}
+	err = impl.doRequest(method, contentPath, fileRequest, fileResponse)
+	if err != nil {
+		impl.logger.Errorw("error in committing file to gitea", "repo", config.ChartRepoName, "file", fileName, "err", err)
+		return "", time.Time{}, err
+	}
+	commitTime = fileResponse.Commit.Committer.Date
+	if commitTime.IsZero() {
+		commitTime = time.Now()
+	}
+	return fileResponse.Commit.Sha, commitTime, nil
+}
+
+func (impl GitGiteaClient) GetCommits(repoName, projectName string) ([]*GitCommitDto, error) {
+	owner, err := impl.owner()
+	if err != nil {
+		return nil, err
+	}
+	var commits []giteaCommit
+	err = impl.doRequest(http2.MethodGet, fmt.Sprintf("/repos/%s/%s/commits?sha=%s", url.PathEscape(owner), url.PathEscape(repoName), GITEA_DEFAULT_BRANCH), nil, &commits)
+	if err != nil {
+		impl.logger.Errorw("error in getting commits", "err", err, "repoName", repoName)
+		return nil, err
+	}
+	var gitCommitsDto []*GitCommitDto
+	for _, commit := range commits {
+		gitCommitsDto = append(gitCommitsDto, &GitCommitDto{
+			CommitHash: commit.Sha,
+			AuthorName: commit.Commit.Author.Name,
+			CommitTime: commit.Commit.Committer.Date,
+		})
+	}
+	return gitCommitsDto, nil
+}
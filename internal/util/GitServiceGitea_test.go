@@ -0,0 +1,90 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGitGiteaClient_CommitValues(t *testing.T) {
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		const contentPath = "/api/v1/repos/devtron/payments/contents/payments/values.yaml"
+		switch {
+		case r.URL.Path == contentPath && r.Method == http.MethodGet:
+			if _, ok := files[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"sha":"file-sha"}`))
+		case r.URL.Path == contentPath && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+			request := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			assert.Equal(t, GITEA_DEFAULT_BRANCH, request["branch"])
+			if r.Method == http.MethodPut {
+				assert.Equal(t, "file-sha", request["sha"])
+			}
+			content, _ := base64.StdEncoding.DecodeString(request["content"].(string))
+			files[r.URL.Path] = string(content)
+			_, _ = w.Write([]byte(`{"commit":{"sha":"commit-` + r.Method + `","committer":{"date":"2023-01-02T10:00:00Z"}}}`))
+		case r.URL.Path == "/api/v1/repos/devtron/payments/commits":
+			_, _ = w.Write([]byte(`[{"sha":"abc","commit":{"author":{"name":"admin"},"committer":{"date":"2023-01-02T10:00:00Z"}}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, err := NewGitGiteaClient(server.URL, "secret", "devtron", zap.NewNop().Sugar(), nil)
+	assert.Nil(t, err)
+
+	config := &ChartConfig{ChartLocation: "payments", FileName: "values.yaml", FileContent: "replicaCount: 1", ChartRepoName: "payments"}
+	hash, commitTime, err := client.CommitValues(config, &bean.GitOpsConfigDto{})
+	assert.Nil(t, err)
+	assert.Equal(t, "commit-POST", hash)
+	assert.Equal(t, 2023, commitTime.Year())
+
+	config.FileContent = "replicaCount: 2"
+	hash, _, err = client.CommitValues(config, &bean.GitOpsConfigDto{})
+	assert.Nil(t, err)
+	assert.Equal(t, "commit-PUT", hash)
+	assert.Equal(t, "replicaCount: 2", files["/api/v1/repos/devtron/payments/contents/payments/values.yaml"])
+
+	commits, err := client.GetCommits("payments", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(commits))
+	assert.Equal(t, "admin", commits[0].AuthorName)
+
+	_, err = client.GetRepoUrl(&bean.GitOpsConfigDto{GitRepoName: "missing"})
+	assert.True(t, isGiteaNotFound(err))
+}
+
+func TestNewGitGiteaClient_InvalidHost(t *testing.T) {
+	_, err := NewGitGiteaClient("gitea.local", "secret", "", zap.NewNop().Sugar(), nil)
+	assert.NotNil(t, err)
+}
+
+func TestGitFactory_GetGiteaOwner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/user", r.URL.Path)
+		_, _ = w.Write([]byte(`{"login":"gitops-bot"}`))
+	}))
+	defer server.Close()
+	factory := &GitFactory{logger: zap.NewNop().Sugar()}
+
+	//the token owner is used when no organisation is configured, not the configured username
+	owner, err := factory.GetGiteaOwner(&bean.GitOpsConfigDto{Host: server.URL, Token: "secret", Username: "admin"})
+	assert.Nil(t, err)
+	assert.Equal(t, "gitops-bot", owner)
+
+	owner, err = factory.GetGiteaOwner(&bean.GitOpsConfigDto{Host: server.URL, Token: "secret", Username: "admin", GiteaOrgId: "devtron"})
+	assert.Nil(t, err)
+	assert.Equal(t, "devtron", owner)
+}
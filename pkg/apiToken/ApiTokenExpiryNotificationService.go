@@ -0,0 +1,81 @@
+package apiToken
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/pkg/notifier"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ApiTokenExpiryNotificationService warns the notification channels configured on api tokens ahead of their expiry, once
+// per expiry
+type ApiTokenExpiryNotificationService interface {
+	NotifyExpiringApiTokens()
+}
+
+type ApiTokenExpiryNotificationConfig struct {
+	NotifyBeforeDays int `env:"API_TOKEN_EXPIRY_NOTIFY_BEFORE_DAYS" envDefault:"7"`
+}
+
+type ApiTokenExpiryNotificationServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	apiTokenRepository         ApiTokenRepository
+	notificationChannelService notifier.NotificationChannelService
+	config                     *ApiTokenExpiryNotificationConfig
+}
+
+func NewApiTokenExpiryNotificationServiceImpl(logger *zap.SugaredLogger, apiTokenRepository ApiTokenRepository,
+	notificationChannelService notifier.NotificationChannelService) *ApiTokenExpiryNotificationServiceImpl {
+	config := &ApiTokenExpiryNotificationConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Fatal("error occurred while parsing api token expiry notification config", err)
+	}
+	return &ApiTokenExpiryNotificationServiceImpl{
+		logger:                     logger,
+		apiTokenRepository:         apiTokenRepository,
+		notificationChannelService: notificationChannelService,
+		config:                     config,
+	}
+}
+
+func (impl *ApiTokenExpiryNotificationServiceImpl) NotifyExpiringApiTokens() {
+	notifyBefore := time.Now().AddDate(0, 0, impl.config.NotifyBeforeDays)
+	apiTokens, err := impl.apiTokenRepository.FindActiveExpiringBefore(notifyBefore.UnixMilli())
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting expiring api tokens", "error", err)
+		return
+	}
+	for _, apiToken := range apiTokens {
+		expiresOn := time.UnixMilli(apiToken.ExpireAtInMs).UTC()
+		message := &notifier.NotificationChannelMessage{
+			EventType:    util.ApiTokenExpiry,
+			ApiTokenName: apiToken.Name,
+			ExpiresOn:    expiresOn.Format(time.RFC1123),
+			Summary:      fmt.Sprintf("API token %s expires in %s", apiToken.Name, getTimeLeft(time.Until(expiresOn))),
+		}
+		err = impl.notificationChannelService.SendNotificationToConfigs(apiToken.ExpiryNotificationConfigIds, message)
+		if err != nil {
+			// retried on the next run
+			impl.logger.Errorw("error while sending api token expiry notification", "apiTokenId", apiToken.Id, "error", err)
+			continue
+		}
+		err = impl.apiTokenRepository.UpdateExpiryNotifiedOn(apiToken.Id, time.Now())
+		if err != nil {
+			impl.logger.Errorw("error while marking api token expiry notified", "apiTokenId", apiToken.Id, "error", err)
+		}
+	}
+}
+
+func getTimeLeft(duration time.Duration) string {
+	if duration >= 24*time.Hour {
+		return fmt.Sprintf("%d days", int(duration.Hours()/24))
+	}
+	if duration >= time.Hour {
+		return fmt.Sprintf("%d hours", int(duration.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(duration.Minutes()))
+}
@@ -22,26 +22,48 @@ import (
 	"github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type ApiToken struct {
-	tableName    struct{} `sql:"api_token"`
-	Id           int      `sql:"id,pk"`
-	UserId       int32    `sql:"user_id, notnull"`
-	Name         string   `sql:"name, notnull"`
-	Description  string   `sql:"description, notnull"`
-	ExpireAtInMs int64    `sql:"expire_at_in_ms"`
-	Token        string   `sql:"token, notnull"`
-	User         *repository.UserModel
+	tableName                   struct{}         `sql:"api_token"`
+	Id                          int              `sql:"id,pk"`
+	UserId                      int32            `sql:"user_id, notnull"`
+	Name                        string           `sql:"name, notnull"`
+	Description                 string           `sql:"description, notnull"`
+	ExpireAtInMs                int64            `sql:"expire_at_in_ms"`
+	Token                       string           `sql:"token, notnull"`
+	Scopes                      []*ApiTokenScope `sql:"scopes"`
+	IpAllowlist                 []string         `sql:"ip_allowlist" pg:",array"`
+	ExpiryNotificationConfigIds []int            `sql:"expiry_notification_config_ids" pg:",array"`
+	PreviousToken               string           `sql:"previous_token"`
+	PreviousTokenExpireAtInMs   int64            `sql:"previous_token_expire_at_in_ms"`
+	LastUsedAt                  time.Time        `sql:"last_used_at"`
+	LastUsedByIp                string           `sql:"last_used_by_ip"`
+	ExpiryNotifiedOn            time.Time        `sql:"expiry_notified_on"`
+	User                        *repository.UserModel
 	sql.AuditLog
 }
 
+// ApiTokenScope is an rbac check the token is limited to, matched the way policies are. A token with scopes is
+// allowed a check only when both the roles of the token user and one of the scopes allow it
+type ApiTokenScope struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Object   string `json:"object"`
+}
+
 type ApiTokenRepository interface {
 	Save(apiToken *ApiToken) error
 	Update(apiToken *ApiToken) error
 	FindAllActive() ([]*ApiToken, error)
 	FindActiveById(id int) (*ApiToken, error)
 	FindByName(name string) (*ApiToken, error)
+	UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error
+	// FindActiveExpiringBefore lists the active tokens expiring by the time given, with expiry notifications configured
+	// and not yet sent
+	FindActiveExpiringBefore(expireAtInMs int64) ([]*ApiToken, error)
+	UpdateExpiryNotifiedOn(id int, notifiedOn time.Time) error
 }
 
 type ApiTokenRepositoryImpl struct {
@@ -91,3 +113,35 @@ func (impl ApiTokenRepositoryImpl) FindByName(name string) (*ApiToken, error) {
 		Select()
 	return apiToken, err
 }
+
+func (impl ApiTokenRepositoryImpl) UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error {
+	_, err := impl.dbConnection.Model((*ApiToken)(nil)).
+		Set("last_used_at = ?", lastUsedAt).
+		Set("last_used_by_ip = ?", lastUsedByIp).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (impl ApiTokenRepositoryImpl) FindActiveExpiringBefore(expireAtInMs int64) ([]*ApiToken, error) {
+	var apiTokens []*ApiToken
+	err := impl.dbConnection.Model(&apiTokens).
+		Column("api_token.*", "User").
+		Relation("User", func(q *orm.Query) (query *orm.Query, err error) {
+			return q.Where("active IS TRUE"), nil
+		}).
+		Where("api_token.expire_at_in_ms > ?", time.Now().UnixMilli()).
+		Where("api_token.expire_at_in_ms <= ?", expireAtInMs).
+		Where("api_token.expiry_notified_on IS NULL").
+		Where("cardinality(api_token.expiry_notification_config_ids) > 0").
+		Select()
+	return apiTokens, err
+}
+
+func (impl ApiTokenRepositoryImpl) UpdateExpiryNotifiedOn(id int, notifiedOn time.Time) error {
+	_, err := impl.dbConnection.Model((*ApiToken)(nil)).
+		Set("expiry_notified_on = ?", notifiedOn).
+		Where("id = ?", id).
+		Update()
+	return err
+}
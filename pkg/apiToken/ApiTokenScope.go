@@ -0,0 +1,63 @@
+package apiToken
+
+import (
+	"fmt"
+	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// getApiTokenScopes validates the scopes of a request and lower cases them, the way policies are stored
+func getApiTokenScopes(requestScopes []openapi.ApiTokenScope) ([]*ApiTokenScope, error) {
+	var scopes []*ApiTokenScope
+	for _, requestScope := range requestScopes {
+		scope := &ApiTokenScope{
+			Resource: strings.ToLower(strings.TrimSpace(requestScope.GetResource())),
+			Action:   strings.ToLower(strings.TrimSpace(requestScope.GetAction())),
+			Object:   strings.ToLower(strings.TrimSpace(requestScope.GetObject())),
+		}
+		if len(scope.Resource) == 0 || len(scope.Action) == 0 || len(scope.Object) == 0 {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "resource, action and object are required for every scope, use * to match any"}
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// isWithinScopes tells whether any of the scopes matches the check, no scopes means the token is not narrowed down
+func isWithinScopes(scopes []*ApiTokenScope, resource string, action string, object string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if casbin.MatchKeyByPart(resource, scope.Resource) && casbin.MatchKeyByPart(action, scope.Action) &&
+			casbin.MatchKeyByPart(object, scope.Object) {
+			return true
+		}
+	}
+	return false
+}
+
+// getIpAllowlist validates the ips and cidrs of a request, plain ips are kept as is
+func getIpAllowlist(requestIpAllowlist []string) ([]string, error) {
+	var ipAllowlist []string
+	for _, entry := range requestIpAllowlist {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, _, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("invalid cidr %s in ip allowlist", entry)}
+			}
+		} else if net.ParseIP(entry) == nil {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("invalid ip %s in ip allowlist", entry)}
+		}
+		ipAllowlist = append(ipAllowlist, entry)
+	}
+	return ipAllowlist, nil
+}
@@ -0,0 +1,51 @@
+package apiToken
+
+import (
+	"github.com/devtron-labs/devtron/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestIsWithinScopes(t *testing.T) {
+	scopes := []*ApiTokenScope{
+		{Resource: "applications", Action: "trigger", Object: "payments/*"},
+		{Resource: "applications", Action: "get", Object: "*"},
+	}
+	assert.True(t, isWithinScopes(nil, "global", "update", "*"))
+	assert.True(t, isWithinScopes(scopes, "applications", "trigger", "payments/checkout"))
+	assert.False(t, isWithinScopes(scopes, "applications", "trigger", "orders/checkout"))
+	assert.True(t, isWithinScopes(scopes, "applications", "get", "orders/checkout"))
+	assert.False(t, isWithinScopes(scopes, "environment", "trigger", "prod/checkout"))
+	//super admin is kept only by a scope covering everything
+	assert.False(t, isWithinScopes(scopes, "*", "*", "*"))
+	assert.True(t, isWithinScopes([]*ApiTokenScope{{Resource: "*", Action: "*", Object: "*"}}, "*", "*", "*"))
+}
+
+func TestIsIpAllowed(t *testing.T) {
+	ipAllowlist, err := getIpAllowlist([]string{"10.0.0.0/16", " 192.168.1.7 ", ""})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/16", "192.168.1.7"}, ipAllowlist)
+	_, err = getIpAllowlist([]string{"10.0.0.0/33"})
+	assert.NotNil(t, err)
+
+	assert.True(t, util.IsIpAllowed(nil, "172.16.0.1"))
+	assert.True(t, util.IsIpAllowed(ipAllowlist, "10.0.3.4"))
+	assert.True(t, util.IsIpAllowed(ipAllowlist, util.GetTrustedClientIp("192.168.1.7:53412", "", nil)))
+	assert.False(t, util.IsIpAllowed(ipAllowlist, util.GetTrustedClientIp("192.168.1.8:53412", "", nil)))
+	assert.False(t, util.IsIpAllowed(ipAllowlist, ""))
+}
+
+func TestIsCurrentOrInGracePeriod(t *testing.T) {
+	apiToken := &ApiToken{
+		Token:                     "current",
+		PreviousToken:             "previous",
+		PreviousTokenExpireAtInMs: time.Now().Add(time.Minute).UnixMilli(),
+	}
+	assert.True(t, isCurrentOrInGracePeriod(apiToken, "current"))
+	assert.True(t, isCurrentOrInGracePeriod(apiToken, "previous"))
+	assert.False(t, isCurrentOrInGracePeriod(apiToken, "other"))
+
+	apiToken.PreviousTokenExpireAtInMs = time.Now().Add(-time.Minute).UnixMilli()
+	assert.False(t, isCurrentOrInGracePeriod(apiToken, "previous"))
+}
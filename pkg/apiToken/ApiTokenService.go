@@ -20,17 +20,24 @@ package apiToken
 import (
 	"errors"
 	"fmt"
+	"github.com/caarlos0/env/v6"
 	"github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/devtron/api/bean"
 	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	util2 "github.com/devtron-labs/devtron/util"
 	"github.com/go-pg/pg"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -40,6 +47,22 @@ type ApiTokenService interface {
 	UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
 	DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
 	GetAllApiTokensForWebhook(projectName string, environmentName string, appName string, auth func(token string, projectObject string, envObject string) bool) ([]*openapi.ApiToken, error)
+	// RotateApiToken issues a new token, the current one keeps working for the grace period asked for
+	RotateApiToken(apiTokenId int, request *openapi.RotateApiTokenRequest, rotatedBy int32) (*openapi.RotateApiTokenResponse, error)
+	// VerifyApiTokenUsage checks an api-token presented on a request is the current token or a rotated one within its
+	// grace period and is used from an allowed ip, and records the usage. Other tokens are left to the authenticator
+	VerifyApiTokenUsage(token string, remoteAddr string, forwardedFor string) error
+	// IsWithinScope tells whether the check is within the scopes of the api-token user, true for other users
+	IsWithinScope(emailId string, resource string, action string, object string) bool
+}
+
+type ApiTokenConfig struct {
+	// CacheRefreshIntervalSecs bounds how long changes done on other instances take to apply to token verification
+	CacheRefreshIntervalSecs int `env:"API_TOKEN_CACHE_REFRESH_INTERVAL_SECS" envDefault:"30"`
+	// UsageUpdateIntervalSecs throttles last used updates of a token used from the same ip
+	UsageUpdateIntervalSecs       int `env:"API_TOKEN_USAGE_UPDATE_INTERVAL_SECS" envDefault:"60"`
+	DefaultRotationGracePeriodMin int `env:"API_TOKEN_DEFAULT_ROTATION_GRACE_PERIOD_MINS" envDefault:"60"`
+	MaxRotationGracePeriodMin     int `env:"API_TOKEN_MAX_ROTATION_GRACE_PERIOD_MINS" envDefault:"10080"`
 }
 
 type ApiTokenServiceImpl struct {
@@ -48,17 +71,44 @@ type ApiTokenServiceImpl struct {
 	userService           user.UserService
 	userAuditService      user.UserAuditService
 	apiTokenRepository    ApiTokenRepository
+	enforcer              casbin.Enforcer
+	config                *ApiTokenConfig
+	trustedProxies        []string
+	cache                 *apiTokenCache
+}
+
+// apiTokenCache holds the active api tokens by lower cased email of their users, it is consulted on every request and
+// enforce of an api-token user
+type apiTokenCache struct {
+	lock        *sync.RWMutex
+	byEmail     map[string]*ApiToken
+	refreshedOn time.Time
 }
 
 func NewApiTokenServiceImpl(logger *zap.SugaredLogger, apiTokenSecretService ApiTokenSecretService, userService user.UserService, userAuditService user.UserAuditService,
-	apiTokenRepository ApiTokenRepository) *ApiTokenServiceImpl {
-	return &ApiTokenServiceImpl{
+	apiTokenRepository ApiTokenRepository, enforcer casbin.Enforcer) *ApiTokenServiceImpl {
+	config := &ApiTokenConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Fatal("error occurred while parsing api token config", err)
+	}
+	trustedProxies, err := util2.GetTrustedProxies()
+	if err != nil {
+		logger.Fatal("error occurred while parsing trusted proxies", err)
+	}
+	impl := &ApiTokenServiceImpl{
 		logger:                logger,
 		apiTokenSecretService: apiTokenSecretService,
 		userService:           userService,
 		userAuditService:      userAuditService,
 		apiTokenRepository:    apiTokenRepository,
+		enforcer:              enforcer,
+		config:                config,
+		trustedProxies:        trustedProxies,
+		cache:                 &apiTokenCache{lock: &sync.RWMutex{}},
 	}
+	casbin.SetApiTokenScopeChecker(impl.IsWithinScope)
+	return impl
 }
 
 const API_TOKEN_USER_EMAIL_PREFIX = "API-TOKEN:"
@@ -81,7 +131,6 @@ func (impl ApiTokenServiceImpl) GetAllApiTokensForWebhook(projectName string, en
 	apiTokens := make([]*openapi.ApiToken, 0)
 	for _, apiTokenFromDb := range apiTokensFromDb {
 		authPassed := true
-		userId := apiTokenFromDb.User.Id
 		//checking permission on each of the roles associated with this API Token
 		environmentNames := strings.Split(environmentName, ",")
 		for _, environment := range environmentNames {
@@ -96,19 +145,7 @@ func (impl ApiTokenServiceImpl) GetAllApiTokensForWebhook(projectName string, en
 		}
 
 		if authPassed {
-			apiTokenIdI32 := int32(apiTokenFromDb.Id)
-			updatedAtStr := apiTokenFromDb.UpdatedOn.String()
-			apiToken := &openapi.ApiToken{
-				Id:             &apiTokenIdI32,
-				UserId:         &userId,
-				UserIdentifier: &apiTokenFromDb.User.EmailId,
-				Name:           &apiTokenFromDb.Name,
-				Description:    &apiTokenFromDb.Description,
-				ExpireAtInMs:   &apiTokenFromDb.ExpireAtInMs,
-				Token:          &apiTokenFromDb.Token,
-				UpdatedAt:      &updatedAtStr,
-			}
-			apiTokens = append(apiTokens, apiToken)
+			apiTokens = append(apiTokens, adaptApiToken(apiTokenFromDb))
 		}
 	}
 
@@ -125,25 +162,20 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 
 	var apiTokens []*openapi.ApiToken
 	for _, apiTokenFromDb := range apiTokensFromDb {
-		userId := apiTokenFromDb.User.Id
-		latestAuditLog, err := impl.userAuditService.GetLatestByUserId(userId)
+		apiToken := adaptApiToken(apiTokenFromDb)
+		if !apiTokenFromDb.LastUsedAt.IsZero() {
+			lastUsedAtStr := apiTokenFromDb.LastUsedAt.String()
+			apiToken.LastUsedAt = &lastUsedAtStr
+			apiToken.LastUsedByIp = &apiTokenFromDb.LastUsedByIp
+			apiTokens = append(apiTokens, apiToken)
+			continue
+		}
+		// tokens not used since usage is tracked on the token, fall back to the audit of the token user
+		latestAuditLog, err := impl.userAuditService.GetLatestByUserId(apiTokenFromDb.User.Id)
 		if err != nil {
 			impl.logger.Errorw("error while getting latest audit log", "error", err)
 			return nil, err
 		}
-
-		apiTokenIdI32 := int32(apiTokenFromDb.Id)
-		updatedAtStr := apiTokenFromDb.UpdatedOn.String()
-		apiToken := &openapi.ApiToken{
-			Id:             &apiTokenIdI32,
-			UserId:         &userId,
-			UserIdentifier: &apiTokenFromDb.User.EmailId,
-			Name:           &apiTokenFromDb.Name,
-			Description:    &apiTokenFromDb.Description,
-			ExpireAtInMs:   &apiTokenFromDb.ExpireAtInMs,
-			Token:          &apiTokenFromDb.Token,
-			UpdatedAt:      &updatedAtStr,
-		}
 		if latestAuditLog != nil {
 			lastUsedAtStr := latestAuditLog.CreatedOn.String()
 			apiToken.LastUsedAt = &lastUsedAtStr
@@ -164,6 +196,15 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		return nil, errors.New(fmt.Sprintf("name '%s' contains either white-space or comma, which is not allowed", name))
 	}
 
+	scopes, err := getApiTokenScopes(request.GetScopes())
+	if err != nil {
+		return nil, err
+	}
+	ipAllowlist, err := getIpAllowlist(request.GetIpAllowlist())
+	if err != nil {
+		return nil, err
+	}
+
 	// step-1 - check if the name exists, if exists with active user - throw error
 	apiToken, err := impl.apiTokenRepository.FindByName(name)
 	if err != nil && err != pg.ErrNoRows {
@@ -213,8 +254,11 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		Description:  *request.Description,
 		ExpireAtInMs: *request.ExpireAtInMs,
 		Token:        token,
+		Scopes:       scopes,
+		IpAllowlist:  ipAllowlist,
 		AuditLog:     sql.AuditLog{UpdatedOn: time.Now()},
 	}
+	apiTokenSaveRequest.ExpiryNotificationConfigIds = getExpiryNotificationConfigIds(request.GetExpiryNotificationConfigIds())
 	if apiTokenExists {
 		apiTokenSaveRequest.Id = apiToken.Id
 		apiTokenSaveRequest.CreatedBy = apiToken.CreatedBy
@@ -230,6 +274,7 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		impl.logger.Errorw("error while saving api-token into DB", "error", err)
 		return nil, err
 	}
+	impl.invalidateCache(email)
 
 	success := true
 	return &openapi.CreateApiTokenResponse{
@@ -252,20 +297,39 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 	if apiToken == nil || apiToken.Id == 0 {
 		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
 	}
+	scopes, err := getApiTokenScopes(request.GetScopes())
+	if err != nil {
+		return nil, err
+	}
+	ipAllowlist, err := getIpAllowlist(request.GetIpAllowlist())
+	if err != nil {
+		return nil, err
+	}
 
-	// step-2 - If expires_at is not same, then token needs to be generated again
+	// step-2 - If expires_at is not same, then token needs to be generated again, the current one is kept working for
+	// the default grace period as with a rotation
 	if *request.ExpireAtInMs != apiToken.ExpireAtInMs {
-		// regenerate token
 		token, err := impl.createApiJwtToken(apiToken.User.EmailId, *request.ExpireAtInMs)
 		if err != nil {
 			return nil, err
 		}
-		apiToken.Token = token
+		impl.replaceToken(apiToken, token, time.Duration(impl.config.DefaultRotationGracePeriodMin)*time.Minute)
+		apiToken.ExpiryNotifiedOn = time.Time{}
 	}
 
 	// step-3 - update in DB
 	apiToken.Description = *request.Description
 	apiToken.ExpireAtInMs = *request.ExpireAtInMs
+	// scopes, allowlist and notification configs are left as is when not sent, for clients unaware of them
+	if request.HasScopes() {
+		apiToken.Scopes = scopes
+	}
+	if request.HasIpAllowlist() {
+		apiToken.IpAllowlist = ipAllowlist
+	}
+	if request.HasExpiryNotificationConfigIds() {
+		apiToken.ExpiryNotificationConfigIds = getExpiryNotificationConfigIds(request.GetExpiryNotificationConfigIds())
+	}
 	apiToken.UpdatedBy = updatedBy
 	apiToken.UpdatedOn = time.Now()
 	err = impl.apiTokenRepository.Update(apiToken)
@@ -273,6 +337,9 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		impl.logger.Errorw("error while updating api-token", "apiTokenId", apiTokenId, "error", err)
 		return nil, err
 	}
+	impl.invalidateCache(apiToken.User.EmailId)
+	// enforce results cached for the token user are stale once the scopes change
+	impl.enforcer.InvalidateCache(strings.ToLower(apiToken.User.EmailId))
 
 	success := true
 	return &openapi.UpdateApiTokenResponse{
@@ -314,6 +381,7 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 	if !success {
 		return nil, errors.New(fmt.Sprintf("Couldn't in-activate user corresponds to apiTokenId '%d'", apiTokenId))
 	}
+	impl.invalidateCache(apiToken.User.EmailId)
 
 	return &openapi.ActionResponse{
 		Success: &success,
@@ -328,8 +396,11 @@ func (impl ApiTokenServiceImpl) createApiJwtToken(email string, expireAtInMs int
 		return "", err
 	}
 
+	// the id keeps tokens issued on rotation distinct from the ones they replace
 	registeredClaims := jwt.RegisteredClaims{
-		Issuer: middleware.ApiTokenClaimIssuer,
+		Issuer:   middleware.ApiTokenClaimIssuer,
+		ID:       uuid.NewString(),
+		IssuedAt: jwt.NewNumericDate(time.Now()),
 	}
 	if expireAtInMs > 0 {
 		registeredClaims.ExpiresAt = jwt.NewNumericDate(time.Unix(expireAtInMs/1000, 0))
@@ -347,3 +418,241 @@ func (impl ApiTokenServiceImpl) createApiJwtToken(email string, expireAtInMs int
 	}
 	return token, nil
 }
+
+func (impl ApiTokenServiceImpl) RotateApiToken(apiTokenId int, request *openapi.RotateApiTokenRequest, rotatedBy int32) (*openapi.RotateApiTokenResponse, error) {
+	impl.logger.Infow("Rotating API token", "request", request, "rotatedBy", rotatedBy, "apiTokenId", apiTokenId)
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId)}
+	}
+	if apiToken.ExpireAtInMs > 0 && apiToken.ExpireAtInMs <= time.Now().UnixMilli() {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "expired api-token cannot be rotated, update its expiry instead"}
+	}
+	gracePeriodMin := impl.config.DefaultRotationGracePeriodMin
+	if request.HasGracePeriodInMinutes() {
+		gracePeriodMin = int(request.GetGracePeriodInMinutes())
+	}
+	if gracePeriodMin < 0 || gracePeriodMin > impl.config.MaxRotationGracePeriodMin {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("grace period should be between 0 and %d minutes", impl.config.MaxRotationGracePeriodMin)}
+	}
+
+	token, err := impl.createApiJwtToken(apiToken.User.EmailId, apiToken.ExpireAtInMs)
+	if err != nil {
+		return nil, err
+	}
+	impl.replaceToken(apiToken, token, time.Duration(gracePeriodMin)*time.Minute)
+	apiToken.UpdatedBy = rotatedBy
+	apiToken.UpdatedOn = time.Now()
+	err = impl.apiTokenRepository.Update(apiToken)
+	if err != nil {
+		impl.logger.Errorw("error while updating api-token", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	impl.invalidateCache(apiToken.User.EmailId)
+
+	success := true
+	return &openapi.RotateApiTokenResponse{
+		Success:                   &success,
+		Token:                     &apiToken.Token,
+		PreviousTokenExpireAtInMs: &apiToken.PreviousTokenExpireAtInMs,
+	}, nil
+}
+
+// replaceToken sets the new token keeping the current one valid for the grace period, though not beyond its own expiry
+func (impl ApiTokenServiceImpl) replaceToken(apiToken *ApiToken, token string, gracePeriod time.Duration) {
+	previousTokenExpireAtInMs := time.Now().Add(gracePeriod).UnixMilli()
+	if apiToken.ExpireAtInMs > 0 && apiToken.ExpireAtInMs < previousTokenExpireAtInMs {
+		previousTokenExpireAtInMs = apiToken.ExpireAtInMs
+	}
+	apiToken.PreviousToken = apiToken.Token
+	apiToken.PreviousTokenExpireAtInMs = previousTokenExpireAtInMs
+	apiToken.Token = token
+}
+
+func (impl ApiTokenServiceImpl) VerifyApiTokenUsage(token string, remoteAddr string, forwardedFor string) error {
+	claims := &ApiTokenCustomClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil || claims.Issuer != middleware.ApiTokenClaimIssuer {
+		// not an api-token, verified by the authenticator
+		return nil
+	}
+	apiToken, err := impl.getApiToken(claims.Email)
+	if err != nil {
+		return err
+	}
+	if apiToken != nil && !isCurrentOrInGracePeriod(apiToken, token) {
+		// the token may have been rotated on another instance after the cache was refreshed
+		impl.invalidateCache(claims.Email)
+		apiToken, err = impl.getApiToken(claims.Email)
+		if err != nil {
+			return err
+		}
+	}
+	if apiToken == nil || !isCurrentOrInGracePeriod(apiToken, token) {
+		return &util.ApiError{HttpStatusCode: http.StatusUnauthorized, Code: "401", UserMessage: "api-token is revoked or has been rotated"}
+	}
+	ip := util2.GetTrustedClientIp(remoteAddr, forwardedFor, impl.trustedProxies)
+	if !util2.IsIpAllowed(apiToken.IpAllowlist, ip) {
+		impl.logger.Infow("api-token used from an ip not allowed", "name", apiToken.Name, "clientIp", ip, "remoteAddr", remoteAddr, "forwardedFor", forwardedFor)
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", UserMessage: fmt.Sprintf("api-token is not allowed to be used from %s", ip)}
+	}
+	impl.recordUsage(apiToken, ip)
+	return nil
+}
+
+func (impl ApiTokenServiceImpl) IsWithinScope(emailId string, resource string, action string, object string) bool {
+	if !strings.HasPrefix(strings.ToLower(emailId), strings.ToLower(API_TOKEN_USER_EMAIL_PREFIX)) {
+		return true
+	}
+	apiToken, err := impl.getApiToken(emailId)
+	if err != nil || apiToken == nil {
+		return false
+	}
+	return isWithinScopes(apiToken.Scopes, strings.ToLower(resource), strings.ToLower(action), strings.ToLower(object))
+}
+
+// recordUsage updates the last used time and ip of the token, when used from another ip or after the update interval
+func (impl ApiTokenServiceImpl) recordUsage(apiToken *ApiToken, ip string) {
+	now := time.Now()
+	impl.cache.lock.Lock()
+	if apiToken.LastUsedByIp == ip && now.Sub(apiToken.LastUsedAt) < time.Duration(impl.config.UsageUpdateIntervalSecs)*time.Second {
+		impl.cache.lock.Unlock()
+		return
+	}
+	apiToken.LastUsedAt = now
+	apiToken.LastUsedByIp = ip
+	impl.cache.lock.Unlock()
+	go func() {
+		err := impl.apiTokenRepository.UpdateLastUsed(apiToken.Id, now, ip)
+		if err != nil {
+			impl.logger.Errorw("error while updating last used of api-token", "apiTokenId", apiToken.Id, "error", err)
+		}
+	}()
+}
+
+// getApiToken returns the active token of the api-token user from the cache, nil when the user has no active token
+func (impl ApiTokenServiceImpl) getApiToken(emailId string) (*ApiToken, error) {
+	emailId = strings.ToLower(emailId)
+	impl.cache.lock.RLock()
+	byEmail := impl.cache.byEmail
+	stale := time.Since(impl.cache.refreshedOn) > time.Duration(impl.config.CacheRefreshIntervalSecs)*time.Second
+	apiToken, found := byEmail[emailId]
+	impl.cache.lock.RUnlock()
+	if byEmail != nil && !stale {
+		if found {
+			return apiToken, nil
+		}
+		return impl.loadApiToken(emailId)
+	}
+	err := impl.refreshCache()
+	if err != nil {
+		return nil, err
+	}
+	impl.cache.lock.RLock()
+	apiToken = impl.cache.byEmail[emailId]
+	impl.cache.lock.RUnlock()
+	return apiToken, nil
+}
+
+// loadApiToken fetches a token missing in the cache, like one created on another instance since the last refresh
+func (impl ApiTokenServiceImpl) loadApiToken(emailId string) (*ApiToken, error) {
+	if !strings.HasPrefix(emailId, strings.ToLower(API_TOKEN_USER_EMAIL_PREFIX)) {
+		return nil, nil
+	}
+	apiToken, err := impl.apiTokenRepository.FindByName(emailId[len(API_TOKEN_USER_EMAIL_PREFIX):])
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by name", "emailId", emailId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 || apiToken.User == nil || !apiToken.User.Active {
+		return nil, nil
+	}
+	impl.cache.lock.Lock()
+	defer impl.cache.lock.Unlock()
+	if impl.cache.byEmail != nil {
+		impl.cache.byEmail[emailId] = apiToken
+	}
+	return apiToken, nil
+}
+
+func (impl ApiTokenServiceImpl) refreshCache() error {
+	apiTokens, err := impl.apiTokenRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting all active api tokens from DB", "error", err)
+		return err
+	}
+	byEmail := make(map[string]*ApiToken, len(apiTokens))
+	for _, apiToken := range apiTokens {
+		if apiToken.User != nil {
+			byEmail[strings.ToLower(apiToken.User.EmailId)] = apiToken
+		}
+	}
+	impl.cache.lock.Lock()
+	defer impl.cache.lock.Unlock()
+	impl.cache.byEmail = byEmail
+	impl.cache.refreshedOn = time.Now()
+	return nil
+}
+
+func (impl ApiTokenServiceImpl) invalidateCache(emailId string) {
+	impl.cache.lock.Lock()
+	defer impl.cache.lock.Unlock()
+	delete(impl.cache.byEmail, strings.ToLower(emailId))
+}
+
+func isCurrentOrInGracePeriod(apiToken *ApiToken, token string) bool {
+	if token == apiToken.Token {
+		return true
+	}
+	return len(apiToken.PreviousToken) > 0 && token == apiToken.PreviousToken &&
+		time.Now().UnixMilli() < apiToken.PreviousTokenExpireAtInMs
+}
+
+func adaptApiToken(apiTokenFromDb *ApiToken) *openapi.ApiToken {
+	apiTokenIdI32 := int32(apiTokenFromDb.Id)
+	updatedAtStr := apiTokenFromDb.UpdatedOn.String()
+	scopes := make([]openapi.ApiTokenScope, 0, len(apiTokenFromDb.Scopes))
+	for _, scope := range apiTokenFromDb.Scopes {
+		apiTokenScope := openapi.ApiTokenScope{}
+		apiTokenScope.SetResource(scope.Resource)
+		apiTokenScope.SetAction(scope.Action)
+		apiTokenScope.SetObject(scope.Object)
+		scopes = append(scopes, apiTokenScope)
+	}
+	ipAllowlist := append([]string{}, apiTokenFromDb.IpAllowlist...)
+	configIds := make([]int32, 0, len(apiTokenFromDb.ExpiryNotificationConfigIds))
+	for _, configId := range apiTokenFromDb.ExpiryNotificationConfigIds {
+		configIds = append(configIds, int32(configId))
+	}
+	apiToken := &openapi.ApiToken{
+		Id:                          &apiTokenIdI32,
+		UserId:                      &apiTokenFromDb.User.Id,
+		UserIdentifier:              &apiTokenFromDb.User.EmailId,
+		Name:                        &apiTokenFromDb.Name,
+		Description:                 &apiTokenFromDb.Description,
+		ExpireAtInMs:                &apiTokenFromDb.ExpireAtInMs,
+		Token:                       &apiTokenFromDb.Token,
+		UpdatedAt:                   &updatedAtStr,
+		Scopes:                      &scopes,
+		IpAllowlist:                 &ipAllowlist,
+		ExpiryNotificationConfigIds: &configIds,
+	}
+	if apiTokenFromDb.PreviousTokenExpireAtInMs > time.Now().UnixMilli() {
+		apiToken.PreviousTokenExpireAtInMs = &apiTokenFromDb.PreviousTokenExpireAtInMs
+	}
+	return apiToken
+}
+
+func getExpiryNotificationConfigIds(configIds []int32) []int {
+	var ids []int
+	for _, configId := range configIds {
+		if configId > 0 {
+			ids = append(ids, int(configId))
+		}
+	}
+	return ids
+}
@@ -57,6 +57,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/appStatus"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/externalSecret"
 	history2 "github.com/devtron-labs/devtron/pkg/pipeline/history"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
@@ -170,6 +171,8 @@ type AppServiceImpl struct {
 	globalEnvVariables                     *util2.GlobalEnvVariables
 	manifestPushConfigRepository           repository5.ManifestPushConfigRepository
 	GitOpsManifestPushService              GitOpsPushService
+	ociManifestPushService                 OCIPushService
+	externalSecretService                  externalSecret.ExternalSecretService
 }
 
 type AppService interface {
@@ -247,7 +250,9 @@ func NewAppService(
 	installedAppVersionHistoryRepository repository4.InstalledAppVersionHistoryRepository,
 	globalEnvVariables *util2.GlobalEnvVariables, helmAppService client2.HelmAppService,
 	manifestPushConfigRepository repository5.ManifestPushConfigRepository,
-	GitOpsManifestPushService GitOpsPushService) *AppServiceImpl {
+	GitOpsManifestPushService GitOpsPushService,
+	ociManifestPushService OCIPushService,
+	externalSecretService externalSecret.ExternalSecretService) *AppServiceImpl {
 	appServiceImpl := &AppServiceImpl{
 		environmentConfigRepository:            environmentConfigRepository,
 		mergeUtil:                              mergeUtil,
@@ -308,6 +313,8 @@ func NewAppService(
 		helmAppService:                         helmAppService,
 		manifestPushConfigRepository:           manifestPushConfigRepository,
 		GitOpsManifestPushService:              GitOpsManifestPushService,
+		ociManifestPushService:                 ociManifestPushService,
+		externalSecretService:                  externalSecretService,
 	}
 	return appServiceImpl
 }
@@ -975,6 +982,8 @@ type ValuesOverrideResponse struct {
 	Artifact            *repository.CiArtifact
 	Pipeline            *pipelineConfig.Pipeline
 	AppMetrics          bool
+	// ResolvedExternalSecrets are the secrets rendered with values fetched from external stores
+	ResolvedExternalSecrets []string
 }
 
 type EnvironmentOverride struct {
@@ -1379,10 +1388,14 @@ func (impl *AppServiceImpl) GetValuesOverrideForTrigger(overrideRequest *bean.Va
 	}
 	chartVersion := envOverride.Chart.ChartVersion
 	_, span = otel.Tracer("orchestrator").Start(ctx, "getConfigMapAndSecretJsonV2")
-	configMapJson, err := impl.getConfigMapAndSecretJsonV2(overrideRequest.AppId, envOverride.TargetEnvironment, overrideRequest.PipelineId, chartVersion, overrideRequest.DeploymentWithConfig, overrideRequest.WfrIdForDeploymentWithSpecificTrigger)
+	resolveExternalSecrets := IsHelmApp(overrideRequest.DeploymentAppType)
+	configMapJson, resolvedExternalSecrets, err := impl.getConfigMapAndSecretJsonV2(overrideRequest.AppId, envOverride.TargetEnvironment, overrideRequest.PipelineId, chartVersion, overrideRequest.DeploymentWithConfig, overrideRequest.WfrIdForDeploymentWithSpecificTrigger, resolveExternalSecrets, overrideRequest.AppName, envOverride.Environment.Name)
 	span.End()
 	if err != nil {
 		impl.logger.Errorw("error in fetching config map n secret ", "err", err)
+		if _, ok := err.(*externalSecret.SecretResolutionError); ok {
+			return valuesOverrideResponse, err
+		}
 		configMapJson = nil
 	}
 	_, span = otel.Tracer("orchestrator").Start(ctx, "appCrudOperationService.GetLabelsByAppIdForDeployment")
@@ -1415,6 +1428,15 @@ func (impl *AppServiceImpl) GetValuesOverrideForTrigger(overrideRequest *bean.Va
 		return valuesOverrideResponse, err
 	}
 	pipelineOverride.PipelineMergedValues = string(mergedValues)
+	if len(resolvedExternalSecrets) > 0 {
+		//values fetched from external secret stores are never persisted
+		maskedMergedValues, err := impl.externalSecretService.MaskResolvedSecrets(mergedValues, resolvedExternalSecrets)
+		if err != nil {
+			impl.logger.Errorw("error in masking resolved external secrets", "err", err, "pipelineId", overrideRequest.PipelineId)
+			return valuesOverrideResponse, err
+		}
+		pipelineOverride.PipelineMergedValues = string(maskedMergedValues)
+	}
 	err = impl.pipelineOverrideRepository.Update(pipelineOverride)
 	if err != nil {
 		return valuesOverrideResponse, err
@@ -1428,6 +1450,7 @@ func (impl *AppServiceImpl) GetValuesOverrideForTrigger(overrideRequest *bean.Va
 	valuesOverrideResponse.ReleaseOverrideJSON = releaseOverrideJson
 	valuesOverrideResponse.Artifact = artifact
 	valuesOverrideResponse.Pipeline = pipeline
+	valuesOverrideResponse.ResolvedExternalSecrets = resolvedExternalSecrets
 	return valuesOverrideResponse, err
 }
 
@@ -1641,6 +1664,10 @@ func (impl *AppServiceImpl) TriggerPipeline(overrideRequest *bean.ValuesOverride
 	span.End()
 
 	if triggerEvent.PerformChartPush {
+		if len(valuesOverrideResponse.ResolvedExternalSecrets) > 0 {
+			impl.logger.Errorw("manifest push not allowed with resolved external secrets", "pipelineId", overrideRequest.PipelineId, "secrets", valuesOverrideResponse.ResolvedExternalSecrets)
+			return releaseNo, manifest, fmt.Errorf("external secrets resolved by orchestrator can not be pushed to %s storage", triggerEvent.ManifestStorageType)
+		}
 		manifestPushTemplate, err := impl.BuildManifestPushTemplate(overrideRequest, valuesOverrideResponse, builtChartPath, &manifest)
 		if err != nil {
 			impl.logger.Errorw("error in building manifest push template", "err", err)
@@ -1649,8 +1676,8 @@ func (impl *AppServiceImpl) TriggerPipeline(overrideRequest *bean.ValuesOverride
 		manifestPushService := impl.GetManifestPushService(triggerEvent)
 		manifestPushResponse := manifestPushService.PushChart(manifestPushTemplate, ctx)
 		if manifestPushResponse.Error != nil {
-			impl.logger.Errorw("Error in pushing manifest to git", "err", err, "git_repo_url", manifestPushTemplate.RepoUrl)
-			return releaseNo, manifest, err
+			impl.logger.Errorw("Error in pushing manifest", "err", manifestPushResponse.Error, "storageType", triggerEvent.ManifestStorageType, "git_repo_url", manifestPushTemplate.RepoUrl)
+			return releaseNo, manifest, manifestPushResponse.Error
 		}
 		pipelineOverrideUpdateRequest := &chartConfig.PipelineOverride{
 			Id:                     valuesOverrideResponse.PipelineOverride.Id,
@@ -1714,6 +1741,18 @@ func (impl *AppServiceImpl) GetTriggerEvent(deploymentAppType string, triggeredA
 
 func (impl *AppServiceImpl) TriggerRelease(overrideRequest *bean.ValuesOverrideRequest, ctx context.Context, triggeredAt time.Time, deployedBy int32) (releaseNo int, manifest []byte, err error) {
 	triggerEvent := impl.GetTriggerEvent(overrideRequest.DeploymentAppType, triggeredAt, deployedBy)
+	if triggerEvent.DeploymentAppType == bean2.Helm {
+		// helm apps push the packaged chart only when an OCI registry is configured as push target
+		manifestPushConfig, err := impl.manifestPushConfigRepository.GetManifestPushConfigByAppIdAndEnvId(overrideRequest.AppId, overrideRequest.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching manifest push config", "err", err, "appId", overrideRequest.AppId, "envId", overrideRequest.EnvId)
+			return 0, manifest, err
+		}
+		if manifestPushConfig != nil && bean2.IsOCIHelmRepoStorage(manifestPushConfig.StorageType) {
+			triggerEvent.PerformChartPush = true
+			triggerEvent.ManifestStorageType = bean2.ManifestStorageOCIHelmRepo
+		}
+	}
 	releaseNo, manifest, err = impl.TriggerPipeline(overrideRequest, triggerEvent, ctx)
 	if err != nil {
 		return 0, manifest, err
@@ -1725,6 +1764,8 @@ func (impl *AppServiceImpl) GetManifestPushService(triggerEvent bean.TriggerEven
 	var manifestPushService ManifestPushService
 	if triggerEvent.ManifestStorageType == bean2.ManifestStorageGit {
 		manifestPushService = impl.GitOpsManifestPushService
+	} else if triggerEvent.ManifestStorageType == bean2.ManifestStorageOCIHelmRepo {
+		manifestPushService = impl.ociManifestPushService
 	}
 	return manifestPushService
 }
@@ -1738,6 +1779,7 @@ func (impl *AppServiceImpl) BuildManifestPushTemplate(overrideRequest *bean.Valu
 		EnvironmentId:         valuesOverrideResponse.EnvOverride.Environment.Id,
 		UserId:                overrideRequest.UserId,
 		PipelineOverrideId:    valuesOverrideResponse.PipelineOverride.Id,
+		ReleaseNumber:         valuesOverrideResponse.PipelineOverride.PipelineReleaseCounter,
 		AppName:               overrideRequest.AppName,
 		TargetEnvironmentName: valuesOverrideResponse.EnvOverride.TargetEnvironment,
 		BuiltChartPath:        builtChartPath,
@@ -1746,7 +1788,7 @@ func (impl *AppServiceImpl) BuildManifestPushTemplate(overrideRequest *bean.Valu
 	}
 
 	manifestPushConfig, err := impl.manifestPushConfigRepository.GetManifestPushConfigByAppIdAndEnvId(overrideRequest.AppId, overrideRequest.EnvId)
-	if err != nil && err != pg.ErrNoRows {
+	if err != nil {
 		impl.logger.Errorw("error in fetching manifest push config from db", "err", err)
 		return manifestPushTemplate, err
 	}
@@ -1755,6 +1797,18 @@ func (impl *AppServiceImpl) BuildManifestPushTemplate(overrideRequest *bean.Valu
 		if manifestPushConfig.StorageType == bean2.ManifestStorageGit {
 			// need to implement for git repo push
 			// currently manifest push config doesn't have git push config. Gitops config is derived from charts, chart_env_config_override and chart_ref table
+		} else if bean2.IsOCIHelmRepoStorage(manifestPushConfig.StorageType) {
+			helmRepositoryConfig := &bean3.HelmRepositoryConfig{}
+			err = json.Unmarshal([]byte(manifestPushConfig.CredentialsConfig), helmRepositoryConfig)
+			if err != nil {
+				impl.logger.Errorw("error in unmarshalling helm repository config", "err", err, "manifestPushConfigId", manifestPushConfig.Id)
+				return manifestPushTemplate, err
+			}
+			manifestPushTemplate.ContainerRegistryName = helmRepositoryConfig.ContainerRegistryName
+			manifestPushTemplate.RepositoryName = helmRepositoryConfig.RepositoryName
+			manifestPushTemplate.ChartName = manifestPushConfig.ChartName
+			manifestPushTemplate.ChartBaseVersion = manifestPushConfig.ChartBaseVersion
+			manifestPushTemplate.DeployFromOciChart = manifestPushConfig.DeployFromOciChart
 		}
 	} else {
 		manifestPushTemplate.ChartReferenceTemplate = valuesOverrideResponse.EnvOverride.Chart.ReferenceTemplate
@@ -1766,6 +1820,27 @@ func (impl *AppServiceImpl) BuildManifestPushTemplate(overrideRequest *bean.Valu
 	return manifestPushTemplate, err
 }
 
+// getOCIChartForDeployment returns the chart pushed for this release when the pipeline deploys from its OCI chart, nil otherwise
+func (impl *AppServiceImpl) getOCIChartForDeployment(overrideRequest *bean.ValuesOverrideRequest, valuesOverrideResponse *ValuesOverrideResponse) ([]byte, error) {
+	manifestPushConfig, err := impl.manifestPushConfigRepository.GetManifestPushConfigByAppIdAndEnvId(overrideRequest.AppId, overrideRequest.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	if manifestPushConfig == nil || !bean2.IsOCIHelmRepoStorage(manifestPushConfig.StorageType) || !manifestPushConfig.DeployFromOciChart {
+		return nil, nil
+	}
+	helmRepositoryConfig := &bean3.HelmRepositoryConfig{}
+	err = json.Unmarshal([]byte(manifestPushConfig.CredentialsConfig), helmRepositoryConfig)
+	if err != nil {
+		return nil, err
+	}
+	chartVersion, err := GetOCIChartVersion(manifestPushConfig.ChartBaseVersion, valuesOverrideResponse.PipelineOverride.PipelineReleaseCounter)
+	if err != nil {
+		return nil, err
+	}
+	return impl.ociManifestPushService.PullChart(helmRepositoryConfig.ContainerRegistryName, helmRepositoryConfig.RepositoryName, manifestPushConfig.ChartName, chartVersion)
+}
+
 func (impl *AppServiceImpl) saveTimeline(overrideRequest *bean.ValuesOverrideRequest, status string, statusDetail string, ctx context.Context) {
 	// creating cd pipeline status timeline for git commit
 	timeline := &pipelineConfig.PipelineStatusTimeline{
@@ -2003,7 +2078,9 @@ func (impl *AppServiceImpl) GetConfigMapAndSecretJson(appId int, envId int, pipe
 	return merged, nil
 }
 
-func (impl *AppServiceImpl) getConfigMapAndSecretJsonV2(appId int, envId int, pipelineId int, chartVersion string, deploymentWithConfig bean.DeploymentConfigurationType, wfrIdForDeploymentWithSpecificTrigger int) ([]byte, error) {
+// getConfigMapAndSecretJsonV2 fetches values of external secrets when resolveExternalSecrets is set and resolution is
+// enabled, names of the resolved secrets are returned along with the merged json
+func (impl *AppServiceImpl) getConfigMapAndSecretJsonV2(appId int, envId int, pipelineId int, chartVersion string, deploymentWithConfig bean.DeploymentConfigurationType, wfrIdForDeploymentWithSpecificTrigger int, resolveExternalSecrets bool, appName string, envName string) ([]byte, []string, error) {
 
 	var configMapJson string
 	var secretDataJson string
@@ -2019,7 +2096,7 @@ func (impl *AppServiceImpl) getConfigMapAndSecretJsonV2(appId int, envId int, pi
 	if deploymentWithConfig == bean.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED {
 		configMapA, err := impl.configMapRepository.GetByAppIdAppLevel(appId)
 		if err != nil && pg.ErrNoRows != err {
-			return []byte("{}"), err
+			return []byte("{}"), nil, err
 		}
 		if configMapA != nil && configMapA.Id > 0 {
 			configMapJsonApp = configMapA.ConfigMapData
@@ -2027,7 +2104,7 @@ func (impl *AppServiceImpl) getConfigMapAndSecretJsonV2(appId int, envId int, pi
 		}
 		configMapE, err := impl.configMapRepository.GetByAppIdAndEnvIdEnvLevel(appId, envId)
 		if err != nil && pg.ErrNoRows != err {
-			return []byte("{}"), err
+			return []byte("{}"), nil, err
 		}
 		if configMapE != nil && configMapE.Id > 0 {
 			configMapJsonEnv = configMapE.ConfigMapData
@@ -2038,35 +2115,35 @@ func (impl *AppServiceImpl) getConfigMapAndSecretJsonV2(appId int, envId int, pi
 		configMapHistory, err := impl.configMapHistoryRepository.GetHistoryByPipelineIdAndWfrId(pipelineId, wfrIdForDeploymentWithSpecificTrigger, repository3.CONFIGMAP_TYPE)
 		if err != nil {
 			impl.logger.Errorw("error in getting config map history config by pipelineId and wfrId ", "err", err, "pipelineId", pipelineId, "wfrid", wfrIdForDeploymentWithSpecificTrigger)
-			return []byte("{}"), err
+			return []byte("{}"), nil, err
 		}
 		configMapJsonEnv = configMapHistory.Data
 		secretHistory, err := impl.configMapHistoryRepository.GetHistoryByPipelineIdAndWfrId(pipelineId, wfrIdForDeploymentWithSpecificTrigger, repository3.SECRET_TYPE)
 		if err != nil {
 			impl.logger.Errorw("error in getting config map history config by pipelineId and wfrId ", "err", err, "pipelineId", pipelineId, "wfrid", wfrIdForDeploymentWithSpecificTrigger)
-			return []byte("{}"), err
+			return []byte("{}"), nil, err
 		}
 		secretDataJsonEnv = secretHistory.Data
 	}
 	configMapJson, err = impl.mergeUtil.ConfigMapMerge(configMapJsonApp, configMapJsonEnv)
 	if err != nil {
-		return []byte("{}"), err
+		return []byte("{}"), nil, err
 	}
 	chartMajorVersion, chartMinorVersion, err := util2.ExtractChartVersion(chartVersion)
 	if err != nil {
 		impl.logger.Errorw("chart version parsing", "err", err)
-		return []byte("{}"), err
+		return []byte("{}"), nil, err
 	}
 	secretDataJson, err = impl.mergeUtil.ConfigSecretMerge(secretDataJsonApp, secretDataJsonEnv, chartMajorVersion, chartMinorVersion, false)
 	if err != nil {
-		return []byte("{}"), err
+		return []byte("{}"), nil, err
 	}
 	configResponseR := bean.ConfigMapRootJson{}
 	configResponse := bean.ConfigMapJson{}
 	if configMapJson != "" {
 		err = json.Unmarshal([]byte(configMapJson), &configResponse)
 		if err != nil {
-			return []byte("{}"), err
+			return []byte("{}"), nil, err
 		}
 	}
 	configResponseR.ConfigMapJson = configResponse
@@ -2075,25 +2152,33 @@ func (impl *AppServiceImpl) getConfigMapAndSecretJsonV2(appId int, envId int, pi
 	if configMapJson != "" {
 		err = json.Unmarshal([]byte(secretDataJson), &secretResponse)
 		if err != nil {
-			return []byte("{}"), err
+			return []byte("{}"), nil, err
+		}
+	}
+	var resolvedExternalSecrets []string
+	if resolveExternalSecrets && impl.externalSecretService.IsResolutionEnabled() {
+		resolvedExternalSecrets, err = impl.externalSecretService.ResolveSecrets(secretResponse.Secrets, appName, envName)
+		if err != nil {
+			impl.logger.Errorw("error in resolving external secrets", "err", err, "appId", appId, "envId", envId)
+			return []byte("{}"), nil, err
 		}
 	}
 	secretResponseR.ConfigSecretJson = secretResponse
 
 	configMapByte, err := json.Marshal(configResponseR)
 	if err != nil {
-		return []byte("{}"), err
+		return []byte("{}"), nil, err
 	}
 	secretDataByte, err := json.Marshal(secretResponseR)
 	if err != nil {
-		return []byte("{}"), err
+		return []byte("{}"), nil, err
 	}
 
 	merged, err = impl.mergeUtil.JsonPatch(configMapByte, secretDataByte)
 	if err != nil {
-		return []byte("{}"), err
+		return []byte("{}"), nil, err
 	}
-	return merged, nil
+	return merged, resolvedExternalSecrets, nil
 }
 
 func (impl *AppServiceImpl) synchCD(pipeline *pipelineConfig.Pipeline, ctx context.Context,
@@ -2895,6 +2980,14 @@ func (impl *AppServiceImpl) createHelmAppForCdPipeline(overrideRequest *bean.Val
 			}
 			referenceChartByte = refChartByte
 		}
+		ociChartByte, err := impl.getOCIChartForDeployment(overrideRequest, valuesOverrideResponse)
+		if err != nil {
+			impl.logger.Errorw("error in pulling chart from oci registry for deployment", "err", err, "pipelineId", pipeline.Id)
+			return false, err
+		}
+		if ociChartByte != nil {
+			referenceChartByte = ociChartByte
+		}
 
 		releaseName := pipeline.DeploymentAppName
 		bearerToken := envOverride.Environment.Cluster.Config[BearerToken]
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/devtron-labs/devtron/client/ociRegistry"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app/bean"
+	status2 "github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/go-pg/pg"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const DefaultOCIChartBaseVersion = "1.0.0"
+
+type OCIPushService interface {
+	ManifestPushService
+	// PullChart returns the chart pushed for a release as tar bytes, the format expected by helm app service
+	PullChart(containerRegistryName string, repositoryName string, chartName string, chartVersion string) ([]byte, error)
+}
+
+type OCIManifestPushServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	chartTemplateService          util.ChartTemplateService
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	ociRegistryConfigRepository   dockerRegistryRepository.OCIRegistryConfigRepository
+	ociRegistryClient             ociRegistry.OCIRegistryClient
+	pipelineStatusTimelineService status2.PipelineStatusTimelineService
+}
+
+func NewOCIManifestPushServiceImpl(
+	logger *zap.SugaredLogger,
+	chartTemplateService util.ChartTemplateService,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	ociRegistryConfigRepository dockerRegistryRepository.OCIRegistryConfigRepository,
+	ociRegistryClient ociRegistry.OCIRegistryClient,
+	pipelineStatusTimelineService status2.PipelineStatusTimelineService,
+) *OCIManifestPushServiceImpl {
+	return &OCIManifestPushServiceImpl{
+		logger:                        logger,
+		chartTemplateService:          chartTemplateService,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		ociRegistryConfigRepository:   ociRegistryConfigRepository,
+		ociRegistryClient:             ociRegistryClient,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
+	}
+}
+
+func (impl *OCIManifestPushServiceImpl) PushChart(manifestPushTemplate *bean.ManifestPushTemplate, ctx context.Context) bean.ManifestPushResponse {
+	manifestPushResponse := bean.ManifestPushResponse{}
+	chartVersion, digest, err := impl.pushChartToOCIRegistry(manifestPushTemplate, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in pushing chart to oci registry", "err", err, "registry", manifestPushTemplate.ContainerRegistryName, "repository", manifestPushTemplate.RepositoryName)
+		manifestPushResponse.Error = err
+		impl.saveTimelineForError(manifestPushTemplate, err)
+		return manifestPushResponse
+	}
+	manifestPushResponse.ChartVersion = chartVersion
+	manifestPushResponse.ChartDigest = digest
+
+	timeline := getTimelineObject(manifestPushTemplate, pipelineConfig.TIMELINE_STATUS_OCI_CHART_PUSHED,
+		fmt.Sprintf("Chart %s:%s pushed to %s.", path.Join(manifestPushTemplate.RepositoryName, manifestPushTemplate.ChartName), chartVersion, manifestPushTemplate.ContainerRegistryName))
+	timelineErr := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
+	if timelineErr != nil {
+		impl.logger.Errorw("error in saving oci chart push success timeline", "err", timelineErr, "timeline", timeline)
+	}
+	return manifestPushResponse
+}
+
+func (impl *OCIManifestPushServiceImpl) pushChartToOCIRegistry(manifestPushTemplate *bean.ManifestPushTemplate, ctx context.Context) (string, string, error) {
+	if len(manifestPushTemplate.ContainerRegistryName) == 0 || len(manifestPushTemplate.RepositoryName) == 0 || len(manifestPushTemplate.ChartName) == 0 {
+		return "", "", fmt.Errorf("container registry, repository and chart name are required to push chart")
+	}
+	chartVersion, err := GetOCIChartVersion(manifestPushTemplate.ChartBaseVersion, manifestPushTemplate.ReleaseNumber)
+	if err != nil {
+		return "", "", err
+	}
+	credential, err := impl.getRegistryCredential(manifestPushTemplate.ContainerRegistryName, dockerRegistryRepository.STORAGE_ACTION_TYPE_PUSH)
+	if err != nil {
+		return "", "", err
+	}
+	chartMetaData := &chart.Metadata{
+		Name:    manifestPushTemplate.ChartName,
+		Version: chartVersion,
+	}
+	_, span := otel.Tracer("orchestrator").Start(ctx, "chartTemplateService.PackageChartWithValues")
+	chartArchive, err := impl.chartTemplateService.PackageChartWithValues(chartMetaData, manifestPushTemplate.BuiltChartPath, manifestPushTemplate.MergedValues)
+	span.End()
+	if err != nil {
+		return "", "", err
+	}
+	chartConfig, err := json.Marshal(chartMetaData)
+	if err != nil {
+		return "", "", err
+	}
+	_, span = otel.Tracer("orchestrator").Start(ctx, "ociRegistryClient.PushHelmChart")
+	digest, err := impl.ociRegistryClient.PushHelmChart(credential, path.Join(manifestPushTemplate.RepositoryName, manifestPushTemplate.ChartName), chartVersion, chartConfig, chartArchive)
+	span.End()
+	if err != nil {
+		return "", "", err
+	}
+	return chartVersion, digest, nil
+}
+
+func (impl *OCIManifestPushServiceImpl) PullChart(containerRegistryName string, repositoryName string, chartName string, chartVersion string) ([]byte, error) {
+	credential, err := impl.getRegistryCredential(containerRegistryName, dockerRegistryRepository.STORAGE_ACTION_TYPE_PULL)
+	if err != nil {
+		return nil, err
+	}
+	chartArchive, err := impl.ociRegistryClient.PullHelmChart(credential, path.Join(repositoryName, chartName), chartVersion)
+	if err != nil {
+		impl.logger.Errorw("error in pulling chart from oci registry", "err", err, "registry", containerRegistryName, "repository", repositoryName, "chart", chartName, "version", chartVersion)
+		return nil, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(chartArchive))
+	if err != nil {
+		impl.logger.Errorw("error in reading chart archive", "err", err)
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// getRegistryCredential resolves the registry endpoint and credentials, the registry must allow charts for the given action
+func (impl *OCIManifestPushServiceImpl) getRegistryCredential(containerRegistryName string, action string) (*ociRegistry.RegistryCredential, error) {
+	store, err := impl.dockerArtifactStoreRepository.FindOne(containerRegistryName)
+	if err != nil {
+		impl.logger.Errorw("error in fetching container registry", "err", err, "registry", containerRegistryName)
+		return nil, err
+	}
+	if !store.IsOCICompliantRegistry {
+		return nil, fmt.Errorf("container registry %s is not OCI compliant", containerRegistryName)
+	}
+	ociConfig, err := impl.ociRegistryConfigRepository.FindOneByDockerRegistryIdAndRepositoryType(containerRegistryName, dockerRegistryRepository.OCI_REGISRTY_REPO_TYPE_CHART)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching oci registry config", "err", err, "registry", containerRegistryName)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || !IsRepositoryActionAllowed(ociConfig.RepositoryAction, action) {
+		return nil, fmt.Errorf("container registry %s does not allow %s of helm charts", containerRegistryName, action)
+	}
+	credential := &ociRegistry.RegistryCredential{
+		RegistryUrl: store.RegistryURL,
+		Username:    store.Username,
+		Password:    store.Password,
+	}
+	if store.RegistryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		credential.Username, credential.Password, err = dockerRegistry.CreateCredentialForEcr(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+		if err != nil {
+			impl.logger.Errorw("error in creating ecr credentials", "err", err, "registry", containerRegistryName)
+			return nil, err
+		}
+	}
+	return credential, nil
+}
+
+func (impl *OCIManifestPushServiceImpl) saveTimelineForError(manifestPushTemplate *bean.ManifestPushTemplate, pushErr error) {
+	timeline := getTimelineObject(manifestPushTemplate, pipelineConfig.TIMELINE_STATUS_OCI_CHART_PUSH_FAILED, fmt.Sprintf("Chart push to OCI registry failed - %v", pushErr))
+	timelineErr := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
+	if timelineErr != nil {
+		impl.logger.Errorw("error in creating timeline status for oci chart push", "err", timelineErr, "timeline", timeline)
+	}
+}
+
+// GetOCIChartVersion keeps major and minor of the base version and uses the release number as patch, so every
+// deployment pushes a new immutable chart version
+func GetOCIChartVersion(baseVersion string, releaseNumber int) (string, error) {
+	if len(baseVersion) == 0 {
+		baseVersion = DefaultOCIChartBaseVersion
+	}
+	version, err := semver.NewVersion(baseVersion)
+	if err != nil {
+		return "", fmt.Errorf("invalid chart base version %q, %v", baseVersion, err)
+	}
+	return fmt.Sprintf("%d.%d.%d", version.Major(), version.Minor(), releaseNumber), nil
+}
+
+func IsRepositoryActionAllowed(configuredAction string, action string) bool {
+	return configuredAction == action || configuredAction == dockerRegistryRepository.STORAGE_ACTION_TYPE_PULL_AND_PUSH
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"testing"
+
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOCIChartVersion(t *testing.T) {
+	version, err := GetOCIChartVersion("", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.7", version)
+
+	version, err = GetOCIChartVersion("2.3.9", 12)
+	assert.Nil(t, err)
+	assert.Equal(t, "2.3.12", version)
+
+	_, err = GetOCIChartVersion("latest", 1)
+	assert.NotNil(t, err)
+}
+
+func TestIsRepositoryActionAllowed(t *testing.T) {
+	assert.True(t, IsRepositoryActionAllowed(dockerRegistryRepository.STORAGE_ACTION_TYPE_PULL_AND_PUSH, dockerRegistryRepository.STORAGE_ACTION_TYPE_PUSH))
+	assert.True(t, IsRepositoryActionAllowed(dockerRegistryRepository.STORAGE_ACTION_TYPE_PUSH, dockerRegistryRepository.STORAGE_ACTION_TYPE_PUSH))
+	assert.False(t, IsRepositoryActionAllowed(dockerRegistryRepository.STORAGE_ACTION_TYPE_PULL, dockerRegistryRepository.STORAGE_ACTION_TYPE_PUSH))
+}
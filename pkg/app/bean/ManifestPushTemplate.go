@@ -9,6 +9,7 @@ type ManifestPushTemplate struct {
 	EnvironmentId          int
 	UserId                 int32
 	PipelineOverrideId     int
+	ReleaseNumber          int
 	AppName                string
 	TargetEnvironmentName  int
 	ChartReferenceTemplate string
@@ -19,17 +20,24 @@ type ManifestPushTemplate struct {
 	BuiltChartPath         string
 	BuiltChartBytes        *[]byte
 	MergedValues           string
+	ContainerRegistryName  string
+	RepositoryName         string
+	ChartBaseVersion       string
+	DeployFromOciChart     bool
 }
 
 type ManifestPushResponse struct {
-	CommitHash string
-	CommitTime time.Time
-	Error      error
+	CommitHash   string
+	CommitTime   time.Time
+	ChartVersion string
+	ChartDigest  string
+	Error        error
 }
 
+// HelmRepositoryConfig is stored as credentials_config of manifest_push_config for the helm_repo storage type
 type HelmRepositoryConfig struct {
-	repositoryName        string
-	containerRegistryName string
+	RepositoryName        string `json:"repositoryName"`
+	ContainerRegistryName string `json:"containerRegistryName"`
 }
 
 type GitRepositoryConfig struct {
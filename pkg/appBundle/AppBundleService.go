@@ -0,0 +1,592 @@
+package appBundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appDefinition"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/notifier"
+	"github.com/devtron-labs/devtron/pkg/team"
+	"github.com/devtron-labs/devtron/util/argo"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type AppBundleService interface {
+	Export(ctx context.Context, request *ExportRequest) (*AppBundle, error)
+	// ValidateImport maps the references of the bundle onto this instance and reports what can not be mapped
+	ValidateImport(request *ImportRequest) (*ImportReport, error)
+	// Import creates the app of the bundle when its validation has no errors, otherwise only the report is returned
+	Import(ctx context.Context, request *ImportRequest) (*ImportReport, error)
+}
+
+type AppBundleServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	appDefinitionService                appDefinition.AppDefinitionService
+	appRepository                       app.AppRepository
+	teamRepository                      team.TeamRepository
+	gitProviderRepository               repository.GitProviderRepository
+	dockerArtifactStoreRepository       dockerRegistryRepository.DockerArtifactStoreRepository
+	chartRefRepository                  chartRepoRepository.ChartRefRepository
+	environmentRepository               repository2.EnvironmentRepository
+	clusterRepository                   repository2.ClusterRepository
+	ciPipelineRepository                pipelineConfig.CiPipelineRepository
+	pipelineRepository                  pipelineConfig.PipelineRepository
+	notificationSettingsRepository      repository.NotificationSettingsRepository
+	slackRepository                     repository.SlackNotificationRepository
+	webhookRepository                   repository.WebhookNotificationRepository
+	sesRepository                       repository.SESNotificationRepository
+	smtpRepository                      repository.SMTPNotificationRepository
+	notificationChannelConfigRepository repository.NotificationChannelConfigRepository
+	notificationConfigService           notifier.NotificationConfigService
+	argoUserService                     argo.ArgoUserService
+}
+
+func NewAppBundleServiceImpl(logger *zap.SugaredLogger, appDefinitionService appDefinition.AppDefinitionService,
+	appRepository app.AppRepository, teamRepository team.TeamRepository,
+	gitProviderRepository repository.GitProviderRepository, dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	chartRefRepository chartRepoRepository.ChartRefRepository, environmentRepository repository2.EnvironmentRepository,
+	clusterRepository repository2.ClusterRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, notificationSettingsRepository repository.NotificationSettingsRepository,
+	slackRepository repository.SlackNotificationRepository, webhookRepository repository.WebhookNotificationRepository,
+	sesRepository repository.SESNotificationRepository, smtpRepository repository.SMTPNotificationRepository,
+	notificationChannelConfigRepository repository.NotificationChannelConfigRepository,
+	notificationConfigService notifier.NotificationConfigService, argoUserService argo.ArgoUserService) *AppBundleServiceImpl {
+	return &AppBundleServiceImpl{
+		logger:                              logger,
+		appDefinitionService:                appDefinitionService,
+		appRepository:                       appRepository,
+		teamRepository:                      teamRepository,
+		gitProviderRepository:               gitProviderRepository,
+		dockerArtifactStoreRepository:       dockerArtifactStoreRepository,
+		chartRefRepository:                  chartRefRepository,
+		environmentRepository:               environmentRepository,
+		clusterRepository:                   clusterRepository,
+		ciPipelineRepository:                ciPipelineRepository,
+		pipelineRepository:                  pipelineRepository,
+		notificationSettingsRepository:      notificationSettingsRepository,
+		slackRepository:                     slackRepository,
+		webhookRepository:                   webhookRepository,
+		sesRepository:                       sesRepository,
+		smtpRepository:                      smtpRepository,
+		notificationChannelConfigRepository: notificationChannelConfigRepository,
+		notificationConfigService:           notificationConfigService,
+		argoUserService:                     argoUserService,
+	}
+}
+
+// resolution holds the names and ids of the target instance keyed by the ones of the bundle
+type resolution struct {
+	gitProviderUrls  map[string]string
+	dockerRegistries map[string]string
+	chartRefIds      map[int]int
+	environments     map[string]string
+	environmentIds   map[string]int
+}
+
+func (impl AppBundleServiceImpl) Export(ctx context.Context, request *ExportRequest) (*AppBundle, error) {
+	if request.SecretMode == SECRET_MODE_ENCRYPT && len(request.EncryptionKey) == 0 {
+		return nil, newBadRequestError("encryption key is required to encrypt secrets")
+	}
+	appDetail, err, statusCode := impl.appDefinitionService.BuildAppDetail(ctx, request.AppId, request.Enforce)
+	if err != nil {
+		impl.logger.Errorw("error in getting app definition", "err", err, "appId", request.AppId)
+		if statusCode == http.StatusForbidden {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: err.Error(), UserMessage: "unauthorized user for one or more environments of the app"}
+		}
+		return nil, err
+	}
+	references, err := impl.buildReferences(appDetail)
+	if err != nil {
+		return nil, err
+	}
+	notifications, err := impl.exportNotifications(request.AppId)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &AppBundle{
+		ApiVersion:    BUNDLE_API_VERSION,
+		Kind:          BUNDLE_KIND,
+		Version:       BUNDLE_VERSION,
+		ExportedOn:    time.Now(),
+		App:           appDetail,
+		References:    references,
+		Notifications: notifications,
+		SecretMode:    request.SecretMode,
+	}
+	if request.SecretMode == SECRET_MODE_ENCRYPT {
+		encryption, aead, err := newSecretEncryption(request.EncryptionKey)
+		if err != nil {
+			impl.logger.Errorw("error in deriving secret encryption key", "err", err, "appId", request.AppId)
+			return nil, err
+		}
+		err = sealSecrets(appDetail, aead)
+		if err != nil {
+			impl.logger.Errorw("error in encrypting secrets", "err", err, "appId", request.AppId)
+			return nil, err
+		}
+		bundle.SecretEncryption = encryption
+	} else {
+		redactSecrets(appDetail)
+	}
+	return bundle, nil
+}
+
+// buildReferences records every object of the instance the app refers to by id or url
+func (impl AppBundleServiceImpl) buildReferences(appDetail *appBean.AppDetail) (*BundleReferences, error) {
+	references := &BundleReferences{}
+	gitProviderUrls := make(map[string]bool)
+	for _, material := range appDetail.GitMaterials {
+		if gitProviderUrls[material.GitProviderUrl] {
+			continue
+		}
+		gitProviderUrls[material.GitProviderUrl] = true
+		gitProvider, err := impl.gitProviderRepository.FindByUrl(material.GitProviderUrl)
+		if err != nil {
+			impl.logger.Errorw("error in getting git provider", "err", err, "url", material.GitProviderUrl)
+			return nil, err
+		}
+		references.GitProviders = append(references.GitProviders, &GitProviderReference{Name: gitProvider.Name, Url: gitProvider.Url})
+	}
+	if appDetail.DockerConfig != nil && len(appDetail.DockerConfig.DockerRegistry) > 0 {
+		registry, err := impl.dockerArtifactStoreRepository.FindOne(appDetail.DockerConfig.DockerRegistry)
+		if err != nil {
+			impl.logger.Errorw("error in getting docker registry", "err", err, "registry", appDetail.DockerConfig.DockerRegistry)
+			return nil, err
+		}
+		references.DockerRegistries = append(references.DockerRegistries, &DockerRegistryReference{Name: registry.Id, RegistryUrl: registry.RegistryURL})
+	}
+	for _, chartRefId := range getChartRefIds(appDetail) {
+		chartRef, err := impl.chartRefRepository.FindById(chartRefId)
+		if err != nil {
+			impl.logger.Errorw("error in getting chart ref", "err", err, "chartRefId", chartRefId)
+			return nil, err
+		}
+		references.Charts = append(references.Charts, &ChartReference{Id: chartRef.Id, Name: chartRef.Name, Version: chartRef.Version, Location: chartRef.Location})
+	}
+	for _, envName := range getEnvironmentNames(appDetail) {
+		env, err := impl.environmentRepository.FindByName(envName)
+		if err != nil {
+			impl.logger.Errorw("error in getting environment", "err", err, "envName", envName)
+			return nil, err
+		}
+		cluster, err := impl.clusterRepository.FindById(env.ClusterId)
+		if err != nil {
+			impl.logger.Errorw("error in getting cluster", "err", err, "clusterId", env.ClusterId)
+			return nil, err
+		}
+		references.Environments = append(references.Environments, &EnvironmentReference{Name: env.Name, ClusterName: cluster.ClusterName, Namespace: env.Namespace})
+	}
+	return references, nil
+}
+
+func (impl AppBundleServiceImpl) ValidateImport(request *ImportRequest) (*ImportReport, error) {
+	report, _, _, err := impl.validateImport(request)
+	return report, err
+}
+
+func (impl AppBundleServiceImpl) Import(ctx context.Context, request *ImportRequest) (*ImportReport, error) {
+	report, appDetail, notifications, err := impl.validateImport(request)
+	if err != nil || !report.Valid {
+		return report, err
+	}
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "token", acdToken)
+	appId, err, _ := impl.appDefinitionService.CreateApp(ctx, appDetail, request.UserId, nil)
+	if err != nil {
+		impl.logger.Errorw("error in creating app from bundle", "err", err, "appName", report.AppName)
+		return nil, err
+	}
+	report.AppId, report.Applied = appId, true
+	// the app is in place at this point, notifications which fail are reported instead of failing the import
+	impl.importNotifications(appId, notifications, request.UserId, report)
+	return report, nil
+}
+
+// validateImport returns the report along with the app definition and notification requests mapped onto this instance
+func (impl AppBundleServiceImpl) validateImport(request *ImportRequest) (*ImportReport, *appBean.AppDetail, []*pendingNotification, error) {
+	bundle := request.Bundle
+	if bundle == nil || bundle.App == nil || bundle.App.Metadata == nil {
+		return nil, nil, nil, newBadRequestError("bundle does not contain an app")
+	}
+	if bundle.ApiVersion != BUNDLE_API_VERSION || bundle.Kind != BUNDLE_KIND {
+		return nil, nil, nil, newBadRequestError(fmt.Sprintf("unsupported bundle %s/%s", bundle.ApiVersion, bundle.Kind))
+	}
+	if bundle.Version > BUNDLE_VERSION {
+		return nil, nil, nil, newBadRequestError(fmt.Sprintf("bundle version %d is newer than the supported version %d", bundle.Version, BUNDLE_VERSION))
+	}
+	// the request is left as sent, mappings are applied on a copy
+	appDetail, err := copyAppDetail(bundle.App)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if bundle.References == nil {
+		bundle.References = &BundleReferences{}
+	}
+	mapping := request.Mapping
+	if mapping == nil {
+		mapping = &ReferenceMapping{}
+	}
+	if len(request.AppName) > 0 {
+		appDetail.Metadata.AppName = request.AppName
+	}
+	if len(request.ProjectName) > 0 {
+		appDetail.Metadata.ProjectName = request.ProjectName
+	}
+	report := &ImportReport{AppName: appDetail.Metadata.AppName, ProjectName: appDetail.Metadata.ProjectName}
+
+	err = impl.validateAppAndProject(appDetail.Metadata, report)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	res := &resolution{}
+	if res.gitProviderUrls, err = impl.resolveGitProviders(bundle.References, appDetail, mapping, report); err != nil {
+		return nil, nil, nil, err
+	}
+	if res.dockerRegistries, err = impl.resolveDockerRegistries(appDetail, mapping, report); err != nil {
+		return nil, nil, nil, err
+	}
+	if res.chartRefIds, err = impl.resolveCharts(bundle.References, appDetail, report); err != nil {
+		return nil, nil, nil, err
+	}
+	if res.environments, res.environmentIds, err = impl.resolveEnvironments(bundle.References, appDetail, mapping, report); err != nil {
+		return nil, nil, nil, err
+	}
+	applyResolution(appDetail, res)
+	for _, envName := range getEnvironmentNames(appDetail) {
+		report.Environments = append(report.Environments, &TargetEnvironment{Id: res.environmentIds[envName], Name: envName})
+	}
+	validateCiPipelines(appDetail, report)
+	validateSecrets(bundle, appDetail, request.EncryptionKey, report)
+	notifications, err := impl.resolveNotifications(bundle.Notifications, appDetail, report)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	report.Valid = !report.hasErrors()
+	return report, appDetail, notifications, nil
+}
+
+func (impl AppBundleServiceImpl) validateAppAndProject(metadata *appBean.AppMetadata, report *ImportReport) error {
+	existingApp, err := impl.appRepository.FindActiveByName(metadata.AppName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting app by name", "err", err, "appName", metadata.AppName)
+		return err
+	}
+	if existingApp != nil && existingApp.Id > 0 {
+		report.addError(REFERENCE_KIND_APP, metadata.AppName, "app already exists, import with another app name")
+	}
+	project, err := impl.teamRepository.FindByTeamName(metadata.ProjectName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting project by name", "err", err, "projectName", metadata.ProjectName)
+		return err
+	}
+	if project.Id == 0 {
+		report.addError(REFERENCE_KIND_PROJECT, metadata.ProjectName, "project not found")
+	}
+	return nil
+}
+
+func (impl AppBundleServiceImpl) resolveGitProviders(references *BundleReferences, appDetail *appBean.AppDetail, mapping *ReferenceMapping, report *ImportReport) (map[string]string, error) {
+	gitProviderUrls := make(map[string]string)
+	if len(appDetail.GitMaterials) == 0 {
+		return gitProviderUrls, nil
+	}
+	gitProviders, err := impl.gitProviderRepository.FindAllActiveForAutocomplete()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting git providers", "err", err)
+		return nil, err
+	}
+	targetUrls := make(map[string]string)
+	for _, gitProvider := range gitProviders {
+		targetUrls[gitProvider.Name] = gitProvider.Url
+	}
+	sourceNames := make(map[string]string)
+	for _, reference := range references.GitProviders {
+		sourceNames[reference.Url] = reference.Name
+	}
+	for _, material := range appDetail.GitMaterials {
+		if _, ok := gitProviderUrls[material.GitProviderUrl]; ok {
+			continue
+		}
+		sourceName, ok := sourceNames[material.GitProviderUrl]
+		if !ok {
+			report.addError(REFERENCE_KIND_GIT_PROVIDER, material.GitProviderUrl, "git provider is missing in bundle references")
+			continue
+		}
+		targetName := mappedName(mapping.GitProviders, sourceName)
+		targetUrl, ok := targetUrls[targetName]
+		if !ok {
+			report.addError(REFERENCE_KIND_GIT_PROVIDER, sourceName, fmt.Sprintf("git provider %s not found", targetName))
+			continue
+		}
+		gitProviderUrls[material.GitProviderUrl] = targetUrl
+		report.resolve(REFERENCE_KIND_GIT_PROVIDER, sourceName, targetName)
+	}
+	return gitProviderUrls, nil
+}
+
+func (impl AppBundleServiceImpl) resolveDockerRegistries(appDetail *appBean.AppDetail, mapping *ReferenceMapping, report *ImportReport) (map[string]string, error) {
+	dockerRegistries := make(map[string]string)
+	if appDetail.DockerConfig == nil || len(appDetail.DockerConfig.DockerRegistry) == 0 {
+		return dockerRegistries, nil
+	}
+	sourceName := appDetail.DockerConfig.DockerRegistry
+	targetName := mappedName(mapping.DockerRegistries, sourceName)
+	registry, err := impl.dockerArtifactStoreRepository.FindOne(targetName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting docker registry", "err", err, "registry", targetName)
+		return nil, err
+	}
+	if registry == nil || len(registry.Id) == 0 {
+		report.addError(REFERENCE_KIND_DOCKER_REGISTRY, sourceName, fmt.Sprintf("container registry %s not found", targetName))
+		return dockerRegistries, nil
+	}
+	dockerRegistries[sourceName] = registry.Id
+	report.resolve(REFERENCE_KIND_DOCKER_REGISTRY, sourceName, registry.Id)
+	return dockerRegistries, nil
+}
+
+// resolveCharts maps charts by name and version, charts shipped with devtron have no name and are mapped by location
+func (impl AppBundleServiceImpl) resolveCharts(references *BundleReferences, appDetail *appBean.AppDetail, report *ImportReport) (map[int]int, error) {
+	chartRefIds := make(map[int]int)
+	chartRefs, err := impl.chartRefRepository.GetAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting chart refs", "err", err)
+		return nil, err
+	}
+	sourceCharts := make(map[int]*ChartReference)
+	for _, reference := range references.Charts {
+		sourceCharts[reference.Id] = reference
+	}
+	for _, chartRefId := range getChartRefIds(appDetail) {
+		sourceChart, ok := sourceCharts[chartRefId]
+		if !ok {
+			report.addError(REFERENCE_KIND_CHART, fmt.Sprintf("%d", chartRefId), "chart is missing in bundle references")
+			continue
+		}
+		chartName := sourceChart.Name
+		if len(chartName) == 0 {
+			chartName = sourceChart.Location
+		}
+		for _, chartRef := range chartRefs {
+			if chartRef.Version == sourceChart.Version &&
+				((len(sourceChart.Name) > 0 && chartRef.Name == sourceChart.Name) || (len(sourceChart.Name) == 0 && chartRef.Location == sourceChart.Location)) {
+				chartRefIds[chartRefId] = chartRef.Id
+				break
+			}
+		}
+		if _, ok := chartRefIds[chartRefId]; !ok {
+			report.addError(REFERENCE_KIND_CHART, chartName, fmt.Sprintf("chart %s version %s not found", chartName, sourceChart.Version))
+			continue
+		}
+		report.resolve(REFERENCE_KIND_CHART, fmt.Sprintf("%s %s", chartName, sourceChart.Version), fmt.Sprintf("%s %s", chartName, sourceChart.Version))
+	}
+	return chartRefIds, nil
+}
+
+// resolveEnvironments maps an environment by its mapping, then by its namespace in the mapped cluster and last by its own name
+func (impl AppBundleServiceImpl) resolveEnvironments(references *BundleReferences, appDetail *appBean.AppDetail, mapping *ReferenceMapping, report *ImportReport) (map[string]string, map[string]int, error) {
+	environments := make(map[string]string)
+	environmentIds := make(map[string]int)
+	sourceEnvironments := make(map[string]*EnvironmentReference)
+	for _, reference := range references.Environments {
+		sourceEnvironments[reference.Name] = reference
+	}
+	for _, envName := range getEnvironmentNames(appDetail) {
+		var env *repository2.Environment
+		var err error
+		sourceEnv := sourceEnvironments[envName]
+		targetCluster, clusterMapped := "", false
+		if sourceEnv != nil && mapping.Clusters != nil {
+			targetCluster, clusterMapped = mapping.Clusters[sourceEnv.ClusterName]
+		}
+		if targetName, ok := mapping.Environments[envName]; ok {
+			env, err = impl.environmentRepository.FindByName(targetName)
+		} else if clusterMapped {
+			env, err = impl.environmentRepository.FindByNamespaceAndClusterName(sourceEnv.Namespace, targetCluster)
+		} else {
+			env, err = impl.environmentRepository.FindByName(envName)
+		}
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting environment", "err", err, "envName", envName)
+			return nil, nil, err
+		}
+		if env == nil || env.Id == 0 {
+			message := fmt.Sprintf("environment %s not found", mappedName(mapping.Environments, envName))
+			if clusterMapped {
+				message = fmt.Sprintf("no environment for namespace %s in cluster %s", sourceEnv.Namespace, targetCluster)
+			}
+			report.addError(REFERENCE_KIND_ENVIRONMENT, envName, message)
+			continue
+		}
+		environments[envName] = env.Name
+		environmentIds[env.Name] = env.Id
+		report.resolve(REFERENCE_KIND_ENVIRONMENT, envName, env.Name)
+	}
+	return environments, environmentIds, nil
+}
+
+// applyResolution points the app definition at the objects of this instance
+func applyResolution(appDetail *appBean.AppDetail, res *resolution) {
+	for _, material := range appDetail.GitMaterials {
+		if url, ok := res.gitProviderUrls[material.GitProviderUrl]; ok {
+			material.GitProviderUrl = url
+		}
+	}
+	if appDetail.DockerConfig != nil {
+		if registry, ok := res.dockerRegistries[appDetail.DockerConfig.DockerRegistry]; ok {
+			appDetail.DockerConfig.DockerRegistry = registry
+		}
+	}
+	if template := appDetail.GlobalDeploymentTemplate; template != nil {
+		if chartRefId, ok := res.chartRefIds[template.ChartRefId]; ok {
+			template.ChartRefId = chartRefId
+		}
+	}
+	for _, workflow := range appDetail.AppWorkflows {
+		for _, cdPipeline := range workflow.CdPipelines {
+			if envName, ok := res.environments[cdPipeline.EnvironmentName]; ok {
+				cdPipeline.EnvironmentName = envName
+			}
+		}
+	}
+	environmentOverrides := make(map[string]*appBean.EnvironmentOverride)
+	for envName, override := range appDetail.EnvironmentOverrides {
+		if override != nil && override.DeploymentTemplate != nil {
+			if chartRefId, ok := res.chartRefIds[override.DeploymentTemplate.ChartRefId]; ok {
+				override.DeploymentTemplate.ChartRefId = chartRefId
+			}
+		}
+		if targetName, ok := res.environments[envName]; ok {
+			envName = targetName
+		}
+		environmentOverrides[envName] = override
+	}
+	appDetail.EnvironmentOverrides = environmentOverrides
+}
+
+// validateCiPipelines reports pipelines which can not be created from a definition, linked pipelines refer to
+// pipelines of other apps by id
+func validateCiPipelines(appDetail *appBean.AppDetail, report *ImportReport) {
+	for _, workflow := range appDetail.AppWorkflows {
+		ciPipeline := workflow.CiPipeline
+		if ciPipeline == nil {
+			continue
+		}
+		if ciPipeline.ParentCiPipeline != 0 || ciPipeline.ParentAppId != 0 {
+			report.addError(REFERENCE_KIND_CI_PIPELINE, ciPipeline.Name, "linked ci pipelines can not be imported, remove the workflow from the bundle")
+		} else if ciPipeline.IsExternal {
+			report.addError(REFERENCE_KIND_CI_PIPELINE, ciPipeline.Name, "external ci pipelines can not be imported, remove the workflow from the bundle")
+		}
+	}
+}
+
+// validateSecrets decrypts secrets of an encrypted bundle in place and warns about secrets which are redacted
+func validateSecrets(bundle *AppBundle, appDetail *appBean.AppDetail, encryptionKey string, report *ImportReport) {
+	if bundle.SecretMode != SECRET_MODE_ENCRYPT {
+		_ = forEachSecret(appDetail, func(secret *appBean.Secret) error {
+			if len(secret.Data) > 0 {
+				report.addWarning(REFERENCE_KIND_SECRET, secret.Name, "secret values are redacted in the bundle, update them after import")
+			}
+			return nil
+		})
+		return
+	}
+	if bundle.SecretEncryption == nil {
+		report.addError(REFERENCE_KIND_SECRET, "", "bundle is missing its secret encryption")
+		return
+	}
+	aead, err := openSecretEncryption(encryptionKey, bundle.SecretEncryption)
+	if err != nil {
+		report.addError(REFERENCE_KIND_SECRET, "", err.Error())
+		return
+	}
+	err = openSecrets(appDetail, aead)
+	if err != nil {
+		report.addError(REFERENCE_KIND_SECRET, "", err.Error())
+	}
+}
+
+func getChartRefIds(appDetail *appBean.AppDetail) []int {
+	var chartRefIds []int
+	seen := make(map[int]bool)
+	addChartRefId := func(template *appBean.DeploymentTemplate) {
+		if template == nil || template.ChartRefId == 0 || seen[template.ChartRefId] {
+			return
+		}
+		seen[template.ChartRefId] = true
+		chartRefIds = append(chartRefIds, template.ChartRefId)
+	}
+	addChartRefId(appDetail.GlobalDeploymentTemplate)
+	for _, envName := range sortedOverrideNames(appDetail.EnvironmentOverrides) {
+		if override := appDetail.EnvironmentOverrides[envName]; override != nil {
+			addChartRefId(override.DeploymentTemplate)
+		}
+	}
+	return chartRefIds
+}
+
+// getEnvironmentNames returns the environments of cd pipelines followed by the ones only overridden
+func getEnvironmentNames(appDetail *appBean.AppDetail) []string {
+	var envNames []string
+	seen := make(map[string]bool)
+	for _, workflow := range appDetail.AppWorkflows {
+		for _, cdPipeline := range workflow.CdPipelines {
+			if !seen[cdPipeline.EnvironmentName] {
+				seen[cdPipeline.EnvironmentName] = true
+				envNames = append(envNames, cdPipeline.EnvironmentName)
+			}
+		}
+	}
+	for _, envName := range sortedOverrideNames(appDetail.EnvironmentOverrides) {
+		if !seen[envName] {
+			seen[envName] = true
+			envNames = append(envNames, envName)
+		}
+	}
+	return envNames
+}
+
+func sortedOverrideNames(environmentOverrides map[string]*appBean.EnvironmentOverride) []string {
+	envNames := make([]string, 0, len(environmentOverrides))
+	for envName := range environmentOverrides {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	return envNames
+}
+
+func mappedName(mapping map[string]string, name string) string {
+	if mappedName, ok := mapping[name]; ok && len(mappedName) > 0 {
+		return mappedName
+	}
+	return name
+}
+
+func copyAppDetail(appDetail *appBean.AppDetail) (*appBean.AppDetail, error) {
+	data, err := json.Marshal(appDetail)
+	if err != nil {
+		return nil, err
+	}
+	appDetailCopy := &appBean.AppDetail{}
+	err = json.Unmarshal(data, appDetailCopy)
+	return appDetailCopy, err
+}
+
+func newBadRequestError(message string) error {
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: message, UserMessage: message}
+}
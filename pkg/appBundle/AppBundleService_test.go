@@ -0,0 +1,101 @@
+package appBundle
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestAppDetail() *appBean.AppDetail {
+	return &appBean.AppDetail{
+		Metadata: &appBean.AppMetadata{AppName: "payments", ProjectName: "finance"},
+		GitMaterials: []*appBean.GitMaterial{
+			{GitProviderUrl: "https://github.com", GitRepoUrl: "https://github.com/org/payments.git", CheckoutPath: "./"},
+		},
+		DockerConfig:             &appBean.DockerConfig{DockerRegistry: "source-registry", DockerRepository: "payments"},
+		GlobalDeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 10},
+		GlobalSecrets: []*appBean.Secret{
+			{Name: "app-secret", Data: map[string]interface{}{"PASSWORD": "cGFzc3dvcmQ="}},
+		},
+		AppWorkflows: []*appBean.AppWorkflow{{
+			Name:        "build-deploy",
+			CiPipeline:  &appBean.CiPipelineDetails{Name: "payments-ci"},
+			CdPipelines: []*appBean.CdPipelineDetails{{Name: "payments-qa", EnvironmentName: "qa"}},
+		}},
+		EnvironmentOverrides: map[string]*appBean.EnvironmentOverride{
+			"qa": {
+				DeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 12},
+				Secrets:            []*appBean.Secret{{Name: "qa-secret", Data: map[string]interface{}{"TOKEN": "dG9rZW4="}}},
+			},
+			"prod": {},
+		},
+	}
+}
+
+func TestSecretEncryption(t *testing.T) {
+	appDetail := newTestAppDetail()
+	encryption, aead, err := newSecretEncryption("bundle-key")
+	assert.Nil(t, err)
+	assert.Nil(t, sealSecrets(appDetail, aead))
+	assert.NotEqual(t, "cGFzc3dvcmQ=", appDetail.GlobalSecrets[0].Data["PASSWORD"])
+
+	_, err = openSecretEncryption("other-key", encryption)
+	assert.NotNil(t, err)
+	_, err = openSecretEncryption("", encryption)
+	assert.NotNil(t, err)
+
+	aead, err = openSecretEncryption("bundle-key", encryption)
+	assert.Nil(t, err)
+	assert.Nil(t, openSecrets(appDetail, aead))
+	assert.Equal(t, "cGFzc3dvcmQ=", appDetail.GlobalSecrets[0].Data["PASSWORD"])
+	assert.Equal(t, "dG9rZW4=", appDetail.EnvironmentOverrides["qa"].Secrets[0].Data["TOKEN"])
+}
+
+func TestRedactSecrets(t *testing.T) {
+	appDetail := newTestAppDetail()
+	redactSecrets(appDetail)
+	assert.Equal(t, "", appDetail.GlobalSecrets[0].Data["PASSWORD"])
+	assert.Equal(t, "", appDetail.EnvironmentOverrides["qa"].Secrets[0].Data["TOKEN"])
+
+	report := &ImportReport{}
+	validateSecrets(&AppBundle{SecretMode: SECRET_MODE_REDACT}, appDetail, "", report)
+	assert.Len(t, report.Issues, 2)
+	assert.False(t, report.hasErrors())
+
+	report = &ImportReport{}
+	validateSecrets(&AppBundle{SecretMode: SECRET_MODE_ENCRYPT}, appDetail, "bundle-key", report)
+	assert.True(t, report.hasErrors())
+}
+
+func TestApplyResolution(t *testing.T) {
+	appDetail := newTestAppDetail()
+	assert.Equal(t, []int{10, 12}, getChartRefIds(appDetail))
+	assert.Equal(t, []string{"qa", "prod"}, getEnvironmentNames(appDetail))
+
+	applyResolution(appDetail, &resolution{
+		gitProviderUrls:  map[string]string{"https://github.com": "https://github.example.com"},
+		dockerRegistries: map[string]string{"source-registry": "target-registry"},
+		chartRefIds:      map[int]int{10: 20, 12: 22},
+		environments:     map[string]string{"qa": "staging"},
+	})
+	assert.Equal(t, "https://github.example.com", appDetail.GitMaterials[0].GitProviderUrl)
+	assert.Equal(t, "target-registry", appDetail.DockerConfig.DockerRegistry)
+	assert.Equal(t, 20, appDetail.GlobalDeploymentTemplate.ChartRefId)
+	assert.Equal(t, "staging", appDetail.AppWorkflows[0].CdPipelines[0].EnvironmentName)
+	assert.Contains(t, appDetail.EnvironmentOverrides, "staging")
+	assert.Contains(t, appDetail.EnvironmentOverrides, "prod")
+	assert.NotContains(t, appDetail.EnvironmentOverrides, "qa")
+	assert.Equal(t, 22, appDetail.EnvironmentOverrides["staging"].DeploymentTemplate.ChartRefId)
+}
+
+func TestValidateCiPipelines(t *testing.T) {
+	appDetail := newTestAppDetail()
+	report := &ImportReport{}
+	validateCiPipelines(appDetail, report)
+	assert.False(t, report.hasErrors())
+
+	appDetail.AppWorkflows[0].CiPipeline.ParentCiPipeline = 5
+	validateCiPipelines(appDetail, report)
+	assert.True(t, report.hasErrors())
+	assert.Equal(t, REFERENCE_KIND_CI_PIPELINE, report.Issues[0].Kind)
+}
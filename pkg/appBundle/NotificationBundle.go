@@ -0,0 +1,281 @@
+package appBundle
+
+import (
+	"encoding/json"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/notifier"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"sort"
+)
+
+// pendingNotification is a notification request waiting for its pipeline to be created by the import
+type pendingNotification struct {
+	pipelineType util2.PipelineType
+	pipelineName string
+	request      *notifier.NotificationRequest
+}
+
+// exportNotifications collects the rules on the pipelines of the app, rows of a rule are stored per event type
+func (impl AppBundleServiceImpl) exportNotifications(appId int) ([]*NotificationSetting, error) {
+	ciPipelines, err := impl.ciPipelineRepository.FindByAppId(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting ci pipelines", "err", err, "appId", appId)
+		return nil, err
+	}
+	cdPipelines, err := impl.pipelineRepository.FindActiveByAppId(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting cd pipelines", "err", err, "appId", appId)
+		return nil, err
+	}
+	pipelineNames := map[util2.PipelineType]map[int]string{util2.CI: {}, util2.CD: {}}
+	for _, ciPipeline := range ciPipelines {
+		pipelineNames[util2.CI][ciPipeline.Id] = ciPipeline.Name
+	}
+	for _, cdPipeline := range cdPipelines {
+		pipelineNames[util2.CD][cdPipeline.Id] = cdPipeline.Name
+	}
+
+	var notifications []*NotificationSetting
+	for _, pipelineType := range []util2.PipelineType{util2.CI, util2.CD} {
+		var pipelineIds []int
+		for pipelineId := range pipelineNames[pipelineType] {
+			pipelineIds = append(pipelineIds, pipelineId)
+		}
+		sort.Ints(pipelineIds)
+		settings, err := impl.notificationSettingsRepository.FindNotificationSettingsByPipelineIds(string(pipelineType), pipelineIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting notification settings", "err", err, "appId", appId)
+			return nil, err
+		}
+		rules := make(map[string]*NotificationSetting)
+		for _, setting := range settings {
+			key := fmt.Sprintf("%d/%d", setting.ViewId, *setting.PipelineId)
+			if rule, ok := rules[key]; ok {
+				rule.EventTypeIds = append(rule.EventTypeIds, setting.EventTypeId)
+				continue
+			}
+			providers, err := impl.exportProviders(setting.Config)
+			if err != nil {
+				return nil, err
+			}
+			rule := &NotificationSetting{
+				PipelineType: pipelineType,
+				PipelineName: pipelineNames[pipelineType][*setting.PipelineId],
+				EventTypeIds: []int{setting.EventTypeId},
+				Providers:    providers,
+			}
+			rules[key] = rule
+			notifications = append(notifications, rule)
+		}
+	}
+	return notifications, nil
+}
+
+func (impl AppBundleServiceImpl) exportProviders(config string) ([]*NotificationProvider, error) {
+	var providers []*notifier.Provider
+	err := json.Unmarshal([]byte(config), &providers)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling notification providers", "err", err)
+		return nil, err
+	}
+	var notificationProviders []*NotificationProvider
+	for _, provider := range providers {
+		configName, err := impl.getProviderConfigName(provider.Destination, provider.ConfigId)
+		if err != nil {
+			impl.logger.Errorw("error in getting notification config", "err", err, "dest", provider.Destination, "configId", provider.ConfigId)
+			return nil, err
+		}
+		notificationProviders = append(notificationProviders, &NotificationProvider{
+			Destination: provider.Destination,
+			Rule:        provider.Rule,
+			ConfigName:  configName,
+			Recipient:   provider.Recipient,
+		})
+	}
+	return notificationProviders, nil
+}
+
+// resolveNotifications maps destinations by config name, a destination which is not found is dropped with a warning
+func (impl AppBundleServiceImpl) resolveNotifications(notifications []*NotificationSetting, appDetail *appBean.AppDetail, report *ImportReport) ([]*pendingNotification, error) {
+	pipelineNames := map[util2.PipelineType]map[string]bool{util2.CI: {}, util2.CD: {}}
+	for _, workflow := range appDetail.AppWorkflows {
+		if workflow.CiPipeline != nil {
+			pipelineNames[util2.CI][workflow.CiPipeline.Name] = true
+		}
+		for _, cdPipeline := range workflow.CdPipelines {
+			pipelineNames[util2.CD][cdPipeline.Name] = true
+		}
+	}
+	var pendingNotifications []*pendingNotification
+	for _, notification := range notifications {
+		if !pipelineNames[notification.PipelineType][notification.PipelineName] {
+			report.addWarning(REFERENCE_KIND_NOTIFICATION, notification.PipelineName, "pipeline of notification is not part of the bundle, notification is skipped")
+			continue
+		}
+		var providers []*notifier.Provider
+		for _, provider := range notification.Providers {
+			configId := 0
+			if len(provider.ConfigName) > 0 {
+				var err error
+				configId, err = impl.getProviderConfigId(provider.Destination, provider.ConfigName)
+				if err != nil {
+					impl.logger.Errorw("error in getting notification config", "err", err, "dest", provider.Destination, "configName", provider.ConfigName)
+					return nil, err
+				}
+				if configId == 0 {
+					report.addWarning(REFERENCE_KIND_NOTIFICATION, notification.PipelineName, fmt.Sprintf("%s destination %s not found, destination is skipped", provider.Destination, provider.ConfigName))
+					continue
+				}
+			}
+			providers = append(providers, &notifier.Provider{
+				Destination: provider.Destination,
+				Rule:        provider.Rule,
+				ConfigId:    configId,
+				Recipient:   provider.Recipient,
+			})
+		}
+		if len(providers) == 0 {
+			report.addWarning(REFERENCE_KIND_NOTIFICATION, notification.PipelineName, "notification has no destination left, notification is skipped")
+			continue
+		}
+		pendingNotifications = append(pendingNotifications, &pendingNotification{
+			pipelineType: notification.PipelineType,
+			pipelineName: notification.PipelineName,
+			request: &notifier.NotificationRequest{
+				Providers: providers,
+				NotificationConfigRequest: []*notifier.NotificationConfigRequest{{
+					PipelineType: notification.PipelineType,
+					EventTypeIds: notification.EventTypeIds,
+				}},
+			},
+		})
+	}
+	return pendingNotifications, nil
+}
+
+func (impl AppBundleServiceImpl) importNotifications(appId int, pendingNotifications []*pendingNotification, userId int32, report *ImportReport) {
+	if len(pendingNotifications) == 0 {
+		return
+	}
+	pipelineIds := map[util2.PipelineType]map[string]int{util2.CI: {}, util2.CD: {}}
+	ciPipelines, err := impl.ciPipelineRepository.FindByAppId(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting ci pipelines", "err", err, "appId", appId)
+	}
+	for _, ciPipeline := range ciPipelines {
+		pipelineIds[util2.CI][ciPipeline.Name] = ciPipeline.Id
+	}
+	cdPipelines, err := impl.pipelineRepository.FindActiveByAppId(appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting cd pipelines", "err", err, "appId", appId)
+	}
+	for _, cdPipeline := range cdPipelines {
+		pipelineIds[util2.CD][cdPipeline.Name] = cdPipeline.Id
+	}
+	for _, notification := range pendingNotifications {
+		pipelineId, ok := pipelineIds[notification.pipelineType][notification.pipelineName]
+		if !ok {
+			report.addWarning(REFERENCE_KIND_NOTIFICATION, notification.pipelineName, "pipeline of notification was not created, notification is skipped")
+			continue
+		}
+		notification.request.NotificationConfigRequest[0].PipelineId = &pipelineId
+		_, err = impl.notificationConfigService.CreateOrUpdateNotificationSettings(notification.request, userId)
+		if err != nil {
+			impl.logger.Errorw("error in creating notification settings", "err", err, "appId", appId, "pipelineName", notification.pipelineName)
+			report.addWarning(REFERENCE_KIND_NOTIFICATION, notification.pipelineName, "notification could not be created: "+err.Error())
+		}
+	}
+}
+
+func (impl AppBundleServiceImpl) getProviderConfigName(destination util2.Channel, configId int) (string, error) {
+	if configId == 0 {
+		return "", nil
+	}
+	switch destination {
+	case util2.Slack:
+		config, err := impl.slackRepository.FindOne(configId)
+		if err != nil {
+			return "", err
+		}
+		return config.ConfigName, nil
+	case util2.Webhook:
+		config, err := impl.webhookRepository.FindOne(configId)
+		if err != nil {
+			return "", err
+		}
+		return config.ConfigName, nil
+	case util2.SES:
+		config, err := impl.sesRepository.FindOne(configId)
+		if err != nil {
+			return "", err
+		}
+		return config.ConfigName, nil
+	case util2.SMTP:
+		config, err := impl.smtpRepository.FindOne(configId)
+		if err != nil {
+			return "", err
+		}
+		return config.ConfigName, nil
+	default:
+		config, err := impl.notificationChannelConfigRepository.FindOne(configId)
+		if err != nil {
+			return "", err
+		}
+		return config.ConfigName, nil
+	}
+}
+
+// getProviderConfigId returns 0 when no config of the destination has the name
+func (impl AppBundleServiceImpl) getProviderConfigId(destination util2.Channel, configName string) (int, error) {
+	switch destination {
+	case util2.Slack:
+		configs, err := impl.slackRepository.FindByName(configName)
+		if err != nil && !util.IsErrNoRows(err) {
+			return 0, err
+		}
+		for _, config := range configs {
+			return config.Id, nil
+		}
+	case util2.Webhook:
+		configs, err := impl.webhookRepository.FindByName(configName)
+		if err != nil && !util.IsErrNoRows(err) {
+			return 0, err
+		}
+		for _, config := range configs {
+			return config.Id, nil
+		}
+	case util2.SES:
+		configs, err := impl.sesRepository.FindAll()
+		if err != nil && !util.IsErrNoRows(err) {
+			return 0, err
+		}
+		for _, config := range configs {
+			if config.ConfigName == configName {
+				return config.Id, nil
+			}
+		}
+	case util2.SMTP:
+		configs, err := impl.smtpRepository.FindAll()
+		if err != nil && !util.IsErrNoRows(err) {
+			return 0, err
+		}
+		for _, config := range configs {
+			if config.ConfigName == configName {
+				return config.Id, nil
+			}
+		}
+	default:
+		configs, err := impl.notificationChannelConfigRepository.FindAllByChannelType(string(destination))
+		if err != nil && !util.IsErrNoRows(err) {
+			return 0, err
+		}
+		for _, config := range configs {
+			if config.ConfigName == configName {
+				return config.Id, nil
+			}
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,163 @@
+package appBundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"golang.org/x/crypto/pbkdf2"
+	"io"
+)
+
+const (
+	secretCipherAlgorithm     = "AES-256-GCM"
+	secretCipherKeyDerivation = "PBKDF2-SHA256"
+	secretCipherIterations    = 100000
+	secretCipherKeyCheck      = "devtron-app-bundle"
+)
+
+// newSecretEncryption derives a key from the user key with a fresh salt
+func newSecretEncryption(encryptionKey string) (*SecretEncryption, cipher.AEAD, error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, nil, err
+	}
+	encryption := &SecretEncryption{
+		Algorithm:     secretCipherAlgorithm,
+		KeyDerivation: secretCipherKeyDerivation,
+		Iterations:    secretCipherIterations,
+		Salt:          base64.StdEncoding.EncodeToString(salt),
+	}
+	aead, err := newSecretCipher(encryptionKey, encryption)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryption.KeyCheck, err = seal(aead, []byte(secretCipherKeyCheck))
+	if err != nil {
+		return nil, nil, err
+	}
+	return encryption, aead, nil
+}
+
+// openSecretEncryption derives the key of an encrypted bundle and verifies it against the key check
+func openSecretEncryption(encryptionKey string, encryption *SecretEncryption) (cipher.AEAD, error) {
+	if encryption.Algorithm != secretCipherAlgorithm || encryption.KeyDerivation != secretCipherKeyDerivation {
+		return nil, fmt.Errorf("unsupported secret encryption %s with %s", encryption.Algorithm, encryption.KeyDerivation)
+	}
+	aead, err := newSecretCipher(encryptionKey, encryption)
+	if err != nil {
+		return nil, err
+	}
+	keyCheck, err := open(aead, encryption.KeyCheck)
+	if err != nil || string(keyCheck) != secretCipherKeyCheck {
+		return nil, fmt.Errorf("encryption key does not match the key the bundle was exported with")
+	}
+	return aead, nil
+}
+
+func newSecretCipher(encryptionKey string, encryption *SecretEncryption) (cipher.AEAD, error) {
+	if len(encryptionKey) == 0 {
+		return nil, fmt.Errorf("encryption key is required")
+	}
+	salt, err := base64.StdEncoding.DecodeString(encryption.Salt)
+	if err != nil {
+		return nil, err
+	}
+	key := pbkdf2.Key([]byte(encryptionKey), salt, encryption.Iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func open(aead cipher.AEAD, sealed string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed value is too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+// forEachSecret visits the global secrets and the secrets of every environment override
+func forEachSecret(appDetail *appBean.AppDetail, visit func(secret *appBean.Secret) error) error {
+	for _, secret := range appDetail.GlobalSecrets {
+		if err := visit(secret); err != nil {
+			return err
+		}
+	}
+	for _, override := range appDetail.EnvironmentOverrides {
+		if override == nil {
+			continue
+		}
+		for _, secret := range override.Secrets {
+			if err := visit(secret); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// redactSecrets keeps the keys of every secret and drops the values, external secrets only hold references
+func redactSecrets(appDetail *appBean.AppDetail) {
+	_ = forEachSecret(appDetail, func(secret *appBean.Secret) error {
+		for key := range secret.Data {
+			secret.Data[key] = ""
+		}
+		return nil
+	})
+}
+
+func sealSecrets(appDetail *appBean.AppDetail, aead cipher.AEAD) error {
+	return forEachSecret(appDetail, func(secret *appBean.Secret) error {
+		for key, value := range secret.Data {
+			plaintext, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			secret.Data[key], err = seal(aead, plaintext)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func openSecrets(appDetail *appBean.AppDetail, aead cipher.AEAD) error {
+	return forEachSecret(appDetail, func(secret *appBean.Secret) error {
+		for key, value := range secret.Data {
+			sealed, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("value of key %s in secret %s is not encrypted", key, secret.Name)
+			}
+			plaintext, err := open(aead, sealed)
+			if err != nil {
+				return fmt.Errorf("value of key %s in secret %s could not be decrypted", key, secret.Name)
+			}
+			var data interface{}
+			err = json.Unmarshal(plaintext, &data)
+			if err != nil {
+				return err
+			}
+			secret.Data[key] = data
+		}
+		return nil
+	})
+}
@@ -0,0 +1,191 @@
+package appBundle
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appDefinition"
+	util "github.com/devtron-labs/devtron/util/event"
+	"time"
+)
+
+const (
+	BUNDLE_API_VERSION = "devtron.ai/v1beta1"
+	BUNDLE_KIND        = "AppBundle"
+	// BUNDLE_VERSION is bumped on every change of the bundle format which older importers can not read
+	BUNDLE_VERSION = 1
+)
+
+type SecretMode string
+
+const (
+	SECRET_MODE_REDACT  SecretMode = "REDACT"
+	SECRET_MODE_ENCRYPT SecretMode = "ENCRYPT"
+)
+
+type ReferenceKind string
+
+const (
+	REFERENCE_KIND_APP             ReferenceKind = "APP"
+	REFERENCE_KIND_PROJECT         ReferenceKind = "PROJECT"
+	REFERENCE_KIND_GIT_PROVIDER    ReferenceKind = "GIT_PROVIDER"
+	REFERENCE_KIND_DOCKER_REGISTRY ReferenceKind = "DOCKER_REGISTRY"
+	REFERENCE_KIND_ENVIRONMENT     ReferenceKind = "ENVIRONMENT"
+	REFERENCE_KIND_CHART           ReferenceKind = "CHART"
+	REFERENCE_KIND_CI_PIPELINE     ReferenceKind = "CI_PIPELINE"
+	REFERENCE_KIND_SECRET          ReferenceKind = "SECRET"
+	REFERENCE_KIND_NOTIFICATION    ReferenceKind = "NOTIFICATION"
+)
+
+type IssueSeverity string
+
+const (
+	ISSUE_SEVERITY_ERROR   IssueSeverity = "ERROR"
+	ISSUE_SEVERITY_WARNING IssueSeverity = "WARNING"
+)
+
+// AppBundle is the portable form of an app, everything the app refers to outside itself is kept by name in
+// References so that import can map it onto the objects of another devtron instance
+type AppBundle struct {
+	ApiVersion       string                 `json:"apiVersion"`
+	Kind             string                 `json:"kind"`
+	Version          int                    `json:"version"`
+	ExportedOn       time.Time              `json:"exportedOn"`
+	App              *appBean.AppDetail     `json:"app"`
+	References       *BundleReferences      `json:"references"`
+	Notifications    []*NotificationSetting `json:"notifications"`
+	SecretMode       SecretMode             `json:"secretMode"`
+	SecretEncryption *SecretEncryption      `json:"secretEncryption,omitempty"`
+}
+
+type BundleReferences struct {
+	GitProviders     []*GitProviderReference    `json:"gitProviders"`
+	DockerRegistries []*DockerRegistryReference `json:"dockerRegistries"`
+	Environments     []*EnvironmentReference    `json:"environments"`
+	Charts           []*ChartReference          `json:"charts"`
+}
+
+type GitProviderReference struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type DockerRegistryReference struct {
+	Name        string `json:"name"`
+	RegistryUrl string `json:"registryUrl"`
+}
+
+type EnvironmentReference struct {
+	Name        string `json:"name"`
+	ClusterName string `json:"clusterName"`
+	Namespace   string `json:"namespace"`
+}
+
+type ChartReference struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Location string `json:"location"`
+}
+
+// SecretEncryption describes how secret values of an encrypted bundle were sealed, KeyCheck lets import verify
+// the key before any secret is decrypted
+type SecretEncryption struct {
+	Algorithm     string `json:"algorithm"`
+	KeyDerivation string `json:"keyDerivation"`
+	Iterations    int    `json:"iterations"`
+	Salt          string `json:"salt"`
+	KeyCheck      string `json:"keyCheck"`
+}
+
+// NotificationSetting is a notification rule on one of the pipelines of the bundled app, rules matching on
+// project, app or environment filters are not part of a bundle as they span other apps
+type NotificationSetting struct {
+	PipelineType util.PipelineType       `json:"pipelineType"`
+	PipelineName string                  `json:"pipelineName"`
+	EventTypeIds []int                   `json:"eventTypeIds"`
+	Providers    []*NotificationProvider `json:"providers"`
+}
+
+type NotificationProvider struct {
+	Destination util.Channel `json:"dest"`
+	Rule        string       `json:"rule,omitempty"`
+	ConfigName  string       `json:"configName,omitempty"`
+	Recipient   string       `json:"recipient,omitempty"`
+}
+
+type ExportRequest struct {
+	AppId         int        `json:"appId" validate:"required"`
+	SecretMode    SecretMode `json:"secretMode" validate:"oneof=REDACT ENCRYPT"`
+	EncryptionKey string     `json:"encryptionKey,omitempty"`
+	UserId        int32      `json:"-"`
+	// Enforce checks rbac of the caller, every environment of the app must be accessible for the export
+	Enforce appDefinition.EnforceFunc `json:"-"`
+}
+
+// ReferenceMapping maps names of the source instance to names of the target instance, names without a mapping are
+// looked up as is. An environment without a mapping is also looked up by namespace in its mapped cluster.
+type ReferenceMapping struct {
+	GitProviders     map[string]string `json:"gitProviders"`
+	DockerRegistries map[string]string `json:"dockerRegistries"`
+	Clusters         map[string]string `json:"clusters"`
+	Environments     map[string]string `json:"environments"`
+}
+
+type ImportRequest struct {
+	Bundle        *AppBundle        `json:"bundle" validate:"required"`
+	AppName       string            `json:"appName,omitempty"`
+	ProjectName   string            `json:"projectName,omitempty"`
+	Mapping       *ReferenceMapping `json:"mapping"`
+	EncryptionKey string            `json:"encryptionKey,omitempty"`
+	UserId        int32             `json:"-"`
+}
+
+type ImportIssue struct {
+	Kind     ReferenceKind `json:"kind"`
+	Name     string        `json:"name"`
+	Severity IssueSeverity `json:"severity"`
+	Message  string        `json:"message"`
+}
+
+type ResolvedReference struct {
+	Kind   ReferenceKind `json:"kind"`
+	Source string        `json:"source"`
+	Target string        `json:"target"`
+}
+
+// ImportReport is returned by validation and by import, an import is applied only when the report has no errors
+type ImportReport struct {
+	Valid        bool                 `json:"valid"`
+	Applied      bool                 `json:"applied"`
+	AppId        int                  `json:"appId,omitempty"`
+	AppName      string               `json:"appName"`
+	ProjectName  string               `json:"projectName"`
+	Resolved     []*ResolvedReference `json:"resolved"`
+	Issues       []*ImportIssue       `json:"issues"`
+	Environments []*TargetEnvironment `json:"environments"`
+}
+
+type TargetEnvironment struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (report *ImportReport) addError(kind ReferenceKind, name string, message string) {
+	report.Issues = append(report.Issues, &ImportIssue{Kind: kind, Name: name, Severity: ISSUE_SEVERITY_ERROR, Message: message})
+}
+
+func (report *ImportReport) addWarning(kind ReferenceKind, name string, message string) {
+	report.Issues = append(report.Issues, &ImportIssue{Kind: kind, Name: name, Severity: ISSUE_SEVERITY_WARNING, Message: message})
+}
+
+func (report *ImportReport) resolve(kind ReferenceKind, source string, target string) {
+	report.Resolved = append(report.Resolved, &ResolvedReference{Kind: kind, Source: source, Target: target})
+}
+
+func (report *ImportReport) hasErrors() bool {
+	for _, issue := range report.Issues {
+		if issue.Severity == ISSUE_SEVERITY_ERROR {
+			return true
+		}
+	}
+	return false
+}
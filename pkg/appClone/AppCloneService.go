@@ -936,6 +936,7 @@ func (impl *AppCloneServiceImpl) CreateCdPipeline(req *cloneCdPipelineRequest, c
 			PostStageConfigMapSecretNames: refCdPipeline.PostStageConfigMapSecretNames,
 			RunPostStageInEnv:             refCdPipeline.RunPostStageInEnv,
 			RunPreStageInEnv:              refCdPipeline.RunPreStageInEnv,
+			ValidateImagePlatforms:        refCdPipeline.ValidateImagePlatforms,
 			DeploymentAppType:             refCdPipeline.DeploymentAppType,
 			ParentPipelineId:              0,
 			ParentPipelineType:            refCdPipeline.ParentPipelineType,
@@ -964,6 +965,7 @@ func (impl *AppCloneServiceImpl) CreateCdPipeline(req *cloneCdPipelineRequest, c
 		PostStageConfigMapSecretNames: refCdPipeline.PostStageConfigMapSecretNames,
 		RunPostStageInEnv:             refCdPipeline.RunPostStageInEnv,
 		RunPreStageInEnv:              refCdPipeline.RunPreStageInEnv,
+		ValidateImagePlatforms:        refCdPipeline.ValidateImagePlatforms,
 		DeploymentAppType:             deploymentAppType,
 		PreDeployStage:                refCdPipeline.PreDeployStage,
 		PostDeployStage:               refCdPipeline.PostDeployStage,
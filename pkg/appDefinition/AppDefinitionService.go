@@ -0,0 +1,2003 @@
+package appDefinition
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	util2 "github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/appWorkflow"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/chart"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/team"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/go-pg/pg"
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	APP_DELETE_FAILED_RESP = "App deletion failed, please try deleting from Devtron UI"
+	SSH_URL_PREFIX         = "git@"
+	HTTPS_URL_PREFIX       = "https://"
+)
+
+// EnforceFunc checks rbac of the caller on an object. Callers which are authorised already, like the sync of a super
+// admin managed source, pass nil to skip rbac.
+type EnforceFunc func(resource string, action string, object string) bool
+
+// AppDefinitionService reads and writes apps in the shape of their v1beta1 definition, it backs the v1beta1 application
+// api, app sync and app bundles so that an app is the same whichever way it was created
+type AppDefinitionService interface {
+	// BuildAppDetail builds the full definition of an app, it fails if the caller can not update any of its environments
+	BuildAppDetail(ctx context.Context, appId int, enforce EnforceFunc) (*appBean.AppDetail, error, int)
+	BuildAppWorkflows(request *appWorkflow.WorkflowCloneRequest) ([]*appBean.AppWorkflow, error, int)
+	BuildEnvironmentOverrides(ctx context.Context, appId int, enforce EnforceFunc) (map[string]*appBean.EnvironmentOverride, error, int)
+	BuildEnvironmentOverride(ctx context.Context, appId int, environmentId int, enforce EnforceFunc) (map[string]*appBean.EnvironmentOverride, error, int)
+	// CreateApp creates the app with all of its components, the app is deleted if any of them fails
+	CreateApp(ctx context.Context, createAppRequest *appBean.AppDetail, userId int32, enforce EnforceFunc) (int, error, int)
+	CreateWorkflows(ctx context.Context, appId int, userId int32, workflows []*appBean.AppWorkflow, enforce EnforceFunc, appName string) (error, int)
+	CreateEnvOverrides(ctx context.Context, appId int, userId int32, environmentOverrides map[string]*appBean.EnvironmentOverride, enforce EnforceFunc) (error, int)
+	DeleteApp(ctx context.Context, appId int, userId int32) error
+	CreateGitMaterials(appId int, gitMaterials []*appBean.GitMaterial, userId int32) (error, int)
+	// UpdateDeploymentTemplate updates values of the latest chart of the app, the chart itself is not changed
+	UpdateDeploymentTemplate(ctx context.Context, appId int, deploymentTemplate *appBean.DeploymentTemplate, userId int32) error
+	CreateGlobalConfigMaps(appId int, userId int32, configMaps []*appBean.ConfigMap) (error, int)
+	CreateGlobalSecrets(appId int, userId int32, secrets []*appBean.Secret) (error, int)
+	DeleteGlobalConfigMap(appId int, name string, userId int32) error
+	DeleteGlobalSecret(appId int, name string, userId int32) error
+}
+
+type AppDefinitionServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	validator               *validator.Validate
+	enforcerUtil            rbac.EnforcerUtil
+	appCrudOperationService app.AppCrudOperationService
+	pipelineBuilder         pipeline.PipelineBuilder
+	gitRegistryService      pipeline.GitRegistryConfig
+	chartService            chart.ChartService
+	configMapService        pipeline.ConfigMapService
+	appListingService       app.AppListingService
+	propertiesConfigService pipeline.PropertiesConfigService
+	appWorkflowService      appWorkflow.AppWorkflowService
+	materialRepository      pipelineConfig.MaterialRepository
+	gitProviderRepo         repository.GitProviderRepository
+	appWorkflowRepository   appWorkflow2.AppWorkflowRepository
+	environmentRepository   repository2.EnvironmentRepository
+	configMapRepository     chartConfig.ConfigMapRepository
+	envConfigRepo           chartConfig.EnvConfigOverrideRepository
+	chartRepo               chartRepoRepository.ChartRepository
+	teamService             team.TeamService
+	pipelineStageService    pipeline.PipelineStageService
+}
+
+func NewAppDefinitionServiceImpl(logger *zap.SugaredLogger, validator *validator.Validate, enforcerUtil rbac.EnforcerUtil,
+	appCrudOperationService app.AppCrudOperationService, pipelineBuilder pipeline.PipelineBuilder, gitRegistryService pipeline.GitRegistryConfig,
+	chartService chart.ChartService, configMapService pipeline.ConfigMapService, appListingService app.AppListingService,
+	propertiesConfigService pipeline.PropertiesConfigService, appWorkflowService appWorkflow.AppWorkflowService,
+	materialRepository pipelineConfig.MaterialRepository, gitProviderRepo repository.GitProviderRepository,
+	appWorkflowRepository appWorkflow2.AppWorkflowRepository, environmentRepository repository2.EnvironmentRepository, configMapRepository chartConfig.ConfigMapRepository,
+	envConfigRepo chartConfig.EnvConfigOverrideRepository, chartRepo chartRepoRepository.ChartRepository, teamService team.TeamService,
+	pipelineStageService pipeline.PipelineStageService) *AppDefinitionServiceImpl {
+	return &AppDefinitionServiceImpl{
+		logger:                  logger,
+		validator:               validator,
+		enforcerUtil:            enforcerUtil,
+		appCrudOperationService: appCrudOperationService,
+		pipelineBuilder:         pipelineBuilder,
+		gitRegistryService:      gitRegistryService,
+		chartService:            chartService,
+		configMapService:        configMapService,
+		appListingService:       appListingService,
+		propertiesConfigService: propertiesConfigService,
+		appWorkflowService:      appWorkflowService,
+		materialRepository:      materialRepository,
+		gitProviderRepo:         gitProviderRepo,
+		appWorkflowRepository:   appWorkflowRepository,
+		environmentRepository:   environmentRepository,
+		configMapRepository:     configMapRepository,
+		envConfigRepo:           envConfigRepo,
+		chartRepo:               chartRepo,
+		teamService:             teamService,
+		pipelineStageService:    pipelineStageService,
+	}
+}
+
+// enforce checks rbac through the given func, rbac is skipped when it is nil
+func (impl *AppDefinitionServiceImpl) enforce(enforce EnforceFunc, resource string, action string, object string) bool {
+	if enforce == nil {
+		return true
+	}
+	return enforce(resource, action, object)
+}
+
+//GetApp related methods starts
+
+// get/build the full app detail
+func (impl *AppDefinitionServiceImpl) BuildAppDetail(ctx context.Context, appId int, enforce EnforceFunc) (*appBean.AppDetail, error, int) {
+	//get/build app metadata starts
+	appMetadataResp, err, statusCode := impl.buildAppMetadata(appId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build app metadata ends
+
+	//get/build git materials starts
+	gitMaterialsResp, err, statusCode := impl.buildAppGitMaterials(appId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build git materials ends
+
+	//get/build docker config starts
+	dockerConfig, err, statusCode := impl.buildDockerConfig(appId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build docker config ends
+
+	//get/build global deployment template starts
+	globalDeploymentTemplateResp, err, statusCode := impl.buildAppDeploymentTemplate(appId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build global deployment template ends
+
+	//get/build app workflows starts
+	//using empty workflow name because it is optional, if not provided then workflows will be fetched on the basis of app
+	wfCloneRequest := &appWorkflow.WorkflowCloneRequest{AppId: appId}
+	appWorkflows, err, statusCode := impl.BuildAppWorkflows(wfCloneRequest)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build app workflows ends
+
+	//get/build global config maps starts
+	globalConfigMapsResp, err, statusCode := impl.buildAppGlobalConfigMaps(appId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build global config maps ends
+
+	//get/build global secrets starts
+	globalSecretsResp, err, statusCode := impl.buildAppGlobalSecrets(appId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build global secrets ends
+
+	//get/build environment override starts
+	environmentOverrides, err, statusCode := impl.BuildEnvironmentOverrides(ctx, appId, enforce)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build environment override ends
+
+	//build full object for response
+	appDetail := &appBean.AppDetail{
+		Metadata:                 appMetadataResp,
+		GitMaterials:             gitMaterialsResp,
+		DockerConfig:             dockerConfig,
+		GlobalDeploymentTemplate: globalDeploymentTemplateResp,
+		AppWorkflows:             appWorkflows,
+		GlobalConfigMaps:         globalConfigMapsResp,
+		GlobalSecrets:            globalSecretsResp,
+		EnvironmentOverrides:     environmentOverrides,
+	}
+	//end
+	return appDetail, nil, http.StatusOK
+}
+
+// get/build app metadata
+func (impl *AppDefinitionServiceImpl) buildAppMetadata(appId int) (*appBean.AppMetadata, error, int) {
+	impl.logger.Debugw("Getting app detail - meta data", "appId", appId)
+
+	appMetaInfo, err := impl.appCrudOperationService.GetAppMetaInfo(appId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetAppMetaInfo in GetAppAllDetail", "err", err, "appId", appId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	if appMetaInfo == nil {
+		err = errors.New("invalid appId - appMetaInfo is null")
+		impl.logger.Errorw("Validation error ", "err", err, "appId", appId)
+		return nil, err, http.StatusBadRequest
+	}
+
+	var appLabelsRes []*appBean.AppLabel
+	if len(appMetaInfo.Labels) > 0 {
+		for _, label := range appMetaInfo.Labels {
+			appLabelsRes = append(appLabelsRes, &appBean.AppLabel{
+				Key:       label.Key,
+				Value:     label.Value,
+				Propagate: label.Propagate,
+			})
+		}
+	}
+	appMetadataResp := &appBean.AppMetadata{
+		AppName:     appMetaInfo.AppName,
+		ProjectName: appMetaInfo.ProjectName,
+		Labels:      appLabelsRes,
+	}
+
+	return appMetadataResp, nil, http.StatusOK
+}
+
+// get/build git materials
+func (impl *AppDefinitionServiceImpl) buildAppGitMaterials(appId int) ([]*appBean.GitMaterial, error, int) {
+	impl.logger.Debugw("Getting app detail - git materials", "appId", appId)
+
+	gitMaterials := impl.pipelineBuilder.GetMaterialsForAppId(appId)
+	var gitMaterialsResp []*appBean.GitMaterial
+	if len(gitMaterials) > 0 {
+		for _, gitMaterial := range gitMaterials {
+			gitRegistry, err := impl.gitRegistryService.FetchOneGitProvider(strconv.Itoa(gitMaterial.GitProviderId))
+			if err != nil {
+				impl.logger.Errorw("service err, getGitProvider in GetAppAllDetail", "err", err, "appId", appId)
+				return nil, err, http.StatusInternalServerError
+			}
+
+			gitMaterialsResp = append(gitMaterialsResp, &appBean.GitMaterial{
+				GitRepoUrl:      gitMaterial.Url,
+				CheckoutPath:    gitMaterial.CheckoutPath,
+				FetchSubmodules: gitMaterial.FetchSubmodules,
+				GitProviderUrl:  gitRegistry.Url,
+			})
+		}
+	}
+	return gitMaterialsResp, nil, http.StatusOK
+}
+
+// get/build docker build config
+func (impl *AppDefinitionServiceImpl) buildDockerConfig(appId int) (*appBean.DockerConfig, error, int) {
+	impl.logger.Debugw("Getting app detail - docker build", "appId", appId)
+
+	ciConfig, err := impl.pipelineBuilder.GetCiPipeline(appId)
+	if errResponse, ok := err.(*util2.ApiError); ok && errResponse.UserMessage == "no ci pipeline exists" {
+		impl.logger.Warnw("docker config not available for app, GetCiPipeline in GetAppAllDetail", "err", err, "appId", appId)
+		return nil, nil, http.StatusOK
+	}
+
+	if err != nil {
+		impl.logger.Errorw("service err, GetCiPipeline in GetAppAllDetail", "err", err, "appId", appId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	//getting gitMaterialUrl by id
+	gitMaterial, err := impl.materialRepository.FindById(ciConfig.CiBuildConfig.GitMaterialId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching materialUrl by ID in GetAppAllDetail", "err", err, "gitMaterialId", ciConfig.CiBuildConfig.GitMaterialId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	dockerConfig := &appBean.DockerConfig{
+		DockerRegistry:   ciConfig.DockerRegistry,
+		DockerRepository: ciConfig.DockerRepository,
+		CiBuildConfig:    ciConfig.CiBuildConfig,
+		CheckoutPath:     gitMaterial.CheckoutPath,
+	}
+
+	return dockerConfig, nil, http.StatusOK
+}
+
+// get/build global deployment template
+func (impl *AppDefinitionServiceImpl) buildAppDeploymentTemplate(appId int) (*appBean.DeploymentTemplate, error, int) {
+	impl.logger.Debugw("Getting app detail - deployment template", "appId", appId)
+
+	//for global template, to bypass env overrides using envId = 0
+	return impl.buildAppEnvironmentDeploymentTemplate(appId, 0)
+}
+
+// get/build environment deployment template
+// using this method for global as well, for global pass envId = 0
+func (impl *AppDefinitionServiceImpl) buildAppEnvironmentDeploymentTemplate(appId int, envId int) (*appBean.DeploymentTemplate, error, int) {
+	impl.logger.Debugw("Getting app detail - environment deployment template", "appId", appId, "envId", envId)
+
+	chartRefData, err := impl.chartService.ChartRefAutocompleteForAppOrEnv(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("service err, ChartRefAutocompleteForAppOrEnv in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	if chartRefData == nil {
+		err = errors.New("invalid appId/envId - chartRefData is null")
+		impl.logger.Errorw("Validation error ", "err", err, "appId", appId, "envId", envId)
+		return nil, err, http.StatusBadRequest
+	}
+
+	appDeploymentTemplate, err := impl.chartService.FindLatestChartForAppByAppId(appId)
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("service err, GetDeploymentTemplate in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+			return nil, err, http.StatusInternalServerError
+		} else {
+			impl.logger.Warnw("no charts configured for app, GetDeploymentTemplate in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+			return nil, nil, http.StatusOK
+		}
+	}
+
+	if appDeploymentTemplate == nil {
+		err = errors.New("invalid appId - deploymentTemplate is null")
+		impl.logger.Errorw("Validation error ", "err", err, "appId", appId, "envId", envId)
+		return nil, err, http.StatusBadRequest
+	}
+
+	//set deployment template & showAppMetrics && isOverride
+	var showAppMetrics bool
+	var deploymentTemplateRaw json.RawMessage
+	var chartRefId int
+	var isOverride bool
+	var isBasicViewLocked bool
+	var currentViewEditor models.ChartsViewEditorType
+	if envId > 0 {
+		//on env level
+		env, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, envId, chartRefData.LatestEnvChartRef)
+		if err != nil {
+			impl.logger.Errorw("service err, GetEnvironmentProperties in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+			return nil, err, http.StatusInternalServerError
+		}
+		chartRefId = chartRefData.LatestEnvChartRef
+		if env.EnvironmentConfig.IsOverride {
+			deploymentTemplateRaw = env.EnvironmentConfig.EnvOverrideValues
+			showAppMetrics = *env.AppMetrics
+			isOverride = true
+			isBasicViewLocked = env.EnvironmentConfig.IsBasicViewLocked
+			currentViewEditor = env.EnvironmentConfig.CurrentViewEditor
+		} else {
+			showAppMetrics = appDeploymentTemplate.IsAppMetricsEnabled
+			deploymentTemplateRaw = appDeploymentTemplate.DefaultAppOverride
+			isBasicViewLocked = appDeploymentTemplate.IsBasicViewLocked
+			currentViewEditor = appDeploymentTemplate.CurrentViewEditor
+		}
+	} else {
+		//on app level
+		showAppMetrics = appDeploymentTemplate.IsAppMetricsEnabled
+		deploymentTemplateRaw = appDeploymentTemplate.DefaultAppOverride
+		chartRefId = chartRefData.LatestAppChartRef
+		isBasicViewLocked = appDeploymentTemplate.IsBasicViewLocked
+		currentViewEditor = appDeploymentTemplate.CurrentViewEditor
+	}
+
+	var deploymentTemplateObj map[string]interface{}
+	if deploymentTemplateRaw != nil {
+		err = json.Unmarshal([]byte(deploymentTemplateRaw), &deploymentTemplateObj)
+		if err != nil {
+			impl.logger.Errorw("service err, un-marshaling fail in deploymentTemplate", "err", err, "appId", appId)
+			return nil, err, http.StatusInternalServerError
+		}
+	}
+
+	deploymentTemplateResp := &appBean.DeploymentTemplate{
+		ChartRefId:        chartRefId,
+		Template:          deploymentTemplateObj,
+		ShowAppMetrics:    showAppMetrics,
+		IsOverride:        isOverride,
+		IsBasicViewLocked: isBasicViewLocked,
+		CurrentViewEditor: currentViewEditor,
+	}
+
+	return deploymentTemplateResp, nil, http.StatusOK
+}
+
+// validate and build workflows
+func (impl *AppDefinitionServiceImpl) BuildAppWorkflows(request *appWorkflow.WorkflowCloneRequest) ([]*appBean.AppWorkflow, error, int) {
+	impl.logger.Debugw("Getting app detail - workflows", "appId", request.AppId)
+	var workflowsList []appWorkflow.AppWorkflowDto
+	var err error
+	if len(request.WorkflowName) != 0 {
+		workflow, err := impl.appWorkflowService.FindAppWorkflowByName(request.WorkflowName, request.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching workflow by name", "err", err, "workflowName", request.WorkflowName, "appId", request.AppId)
+			return nil, err, http.StatusInternalServerError
+		}
+		workflowsList = []appWorkflow.AppWorkflowDto{workflow}
+	} else if request.WorkflowId > 0 {
+		workflow, err := impl.appWorkflowService.FindAppWorkflowById(request.WorkflowId, request.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching workflow by id", "err", err, "workflowName", request.WorkflowName, "appId", request.AppId)
+			return nil, err, http.StatusInternalServerError
+		}
+		workflowsList = []appWorkflow.AppWorkflowDto{workflow}
+	} else {
+		workflowsList, err = impl.appWorkflowService.FindAppWorkflows(request.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching workflows for app in GetAppAllDetail", "err", err)
+			return nil, err, http.StatusInternalServerError
+		}
+	}
+
+	var appWorkflowsResp []*appBean.AppWorkflow
+	for _, workflow := range workflowsList {
+
+		workflowResp := &appBean.AppWorkflow{
+			Name: workflow.Name,
+		}
+
+		var cdPipelinesResp []*appBean.CdPipelineDetails
+		for _, workflowMapping := range workflow.AppWorkflowMappingDto {
+			if workflowMapping.Type == appWorkflow2.CIPIPELINE {
+				ciPipeline, err := impl.pipelineBuilder.GetCiPipelineById(workflowMapping.ComponentId)
+				if err != nil {
+					impl.logger.Errorw("service err, GetCiPipelineById in GetAppAllDetail", "err", err, "appId", request.AppId)
+					return nil, err, http.StatusInternalServerError
+				}
+
+				ciPipelineResp, err := impl.buildCiPipelineResp(request.AppId, ciPipeline)
+				if err != nil {
+					impl.logger.Errorw("service err, buildCiPipelineResp in GetAppAllDetail", "err", err, "appId", request.AppId)
+					return nil, err, http.StatusInternalServerError
+				}
+				workflowResp.CiPipeline = ciPipelineResp
+			}
+
+			if workflowMapping.Type == appWorkflow2.CDPIPELINE {
+				cdPipeline, err := impl.pipelineBuilder.GetCdPipelineById(workflowMapping.ComponentId)
+				if err != nil {
+					impl.logger.Errorw("service err, GetCdPipelineById in GetAppAllDetail", "err", err, "appId", request.AppId)
+					return nil, err, http.StatusInternalServerError
+				}
+				if request.EnvironmentId > 0 && request.EnvironmentId != cdPipeline.EnvironmentId {
+					// if environment id present in request it should match cd pipeline, else skip
+					continue
+				}
+				cdPipelineResp, err := impl.buildCdPipelineResp(request.AppId, cdPipeline)
+				if err != nil {
+					impl.logger.Errorw("service err, buildCdPipelineResp in GetAppAllDetail", "err", err, "appId", request.AppId)
+					return nil, err, http.StatusInternalServerError
+				}
+				cdPipelinesResp = append(cdPipelinesResp, cdPipelineResp)
+			}
+		}
+
+		workflowResp.CdPipelines = cdPipelinesResp
+		appWorkflowsResp = append(appWorkflowsResp, workflowResp)
+
+	}
+
+	return appWorkflowsResp, nil, http.StatusOK
+}
+
+// build ci pipeline resp
+func (impl *AppDefinitionServiceImpl) buildCiPipelineResp(appId int, ciPipeline *bean.CiPipeline) (*appBean.CiPipelineDetails, error) {
+	impl.logger.Debugw("Getting app detail - build ci pipeline resp", "appId", appId)
+
+	if ciPipeline == nil {
+		return nil, nil
+	}
+
+	ciPipelineResp := &appBean.CiPipelineDetails{
+		Name:                     ciPipeline.Name,
+		IsManual:                 ciPipeline.IsManual,
+		DockerBuildArgs:          ciPipeline.DockerArgs,
+		VulnerabilityScanEnabled: ciPipeline.ScanEnabled,
+		IsExternal:               ciPipeline.IsExternal,
+		ParentCiPipeline:         ciPipeline.ParentCiPipeline,
+		ParentAppId:              ciPipeline.ParentAppId,
+		LinkedCount:              ciPipeline.LinkedCount,
+	}
+
+	//build ciPipelineMaterial resp
+	var ciPipelineMaterialsConfig []*appBean.CiPipelineMaterialConfig
+	for _, ciMaterial := range ciPipeline.CiMaterial {
+		gitMaterial, err := impl.materialRepository.FindById(ciMaterial.GitMaterialId)
+		if err != nil {
+			impl.logger.Errorw("service err, GitMaterialById in GetAppAllDetail", "err", err, "appId", appId)
+			return nil, err
+		}
+		ciPipelineMaterialConfig := &appBean.CiPipelineMaterialConfig{
+			Type:          ciMaterial.Source.Type,
+			Value:         ciMaterial.Source.Value,
+			CheckoutPath:  gitMaterial.CheckoutPath,
+			GitMaterialId: gitMaterial.Id,
+		}
+		ciPipelineMaterialsConfig = append(ciPipelineMaterialsConfig, ciPipelineMaterialConfig)
+	}
+
+	ciPipelineResp.CiPipelineMaterialsConfig = ciPipelineMaterialsConfig
+
+	//build docker pre-build script
+	var beforeDockerBuildScriptsResp []*appBean.BuildScript
+	for _, beforeDockerBuildScript := range ciPipeline.BeforeDockerBuildScripts {
+		beforeDockerBuildScriptResp := &appBean.BuildScript{
+			Name:                beforeDockerBuildScript.Name,
+			Index:               beforeDockerBuildScript.Index,
+			Script:              beforeDockerBuildScript.Script,
+			ReportDirectoryPath: beforeDockerBuildScript.OutputLocation,
+		}
+		beforeDockerBuildScriptsResp = append(beforeDockerBuildScriptsResp, beforeDockerBuildScriptResp)
+	}
+	ciPipelineResp.BeforeDockerBuildScripts = beforeDockerBuildScriptsResp
+
+	//build docker post build script
+	var afterDockerBuildScriptsResp []*appBean.BuildScript
+	for _, afterDockerBuildScript := range ciPipeline.AfterDockerBuildScripts {
+		afterDockerBuildScriptResp := &appBean.BuildScript{
+			Name:                afterDockerBuildScript.Name,
+			Index:               afterDockerBuildScript.Index,
+			Script:              afterDockerBuildScript.Script,
+			ReportDirectoryPath: afterDockerBuildScript.OutputLocation,
+		}
+		afterDockerBuildScriptsResp = append(afterDockerBuildScriptsResp, afterDockerBuildScriptResp)
+	}
+	ciPipelineResp.AfterDockerBuildScripts = afterDockerBuildScriptsResp
+
+	//getting pre stage and post stage details
+	preStageDetail, postStageDetail, err := impl.pipelineStageService.GetCiPipelineStageDataDeepCopy(ciPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting pre & post stage detail by ciPipelineId", "err", err, "ciPipelineId", ciPipeline.Id)
+		return nil, err
+	}
+	ciPipelineResp.PreBuildStage = preStageDetail
+	ciPipelineResp.PostBuildStage = postStageDetail
+	return ciPipelineResp, nil
+}
+
+// build cd pipeline resp
+func (impl *AppDefinitionServiceImpl) buildCdPipelineResp(appId int, cdPipeline *bean.CDPipelineConfigObject) (*appBean.CdPipelineDetails, error) {
+	impl.logger.Debugw("Getting app detail - build cd pipeline resp", "appId", appId)
+
+	if cdPipeline == nil {
+		return nil, nil
+	}
+
+	cdPipelineResp := &appBean.CdPipelineDetails{
+		Name:                   cdPipeline.Name,
+		EnvironmentName:        cdPipeline.EnvironmentName,
+		TriggerType:            cdPipeline.TriggerType,
+		DeploymentStrategyType: cdPipeline.DeploymentTemplate,
+		RunPreStageInEnv:       cdPipeline.RunPreStageInEnv,
+		RunPostStageInEnv:      cdPipeline.RunPostStageInEnv,
+		IsClusterCdActive:      cdPipeline.CdArgoSetup,
+	}
+
+	//build DeploymentStrategies resp
+	var deploymentTemplateStrategiesResp []*appBean.DeploymentStrategy
+	for _, strategy := range cdPipeline.Strategies {
+		deploymentTemplateStrategyResp := &appBean.DeploymentStrategy{
+			DeploymentStrategyType: strategy.DeploymentTemplate,
+			IsDefault:              strategy.Default,
+		}
+		var configObj map[string]interface{}
+		if strategy.Config != nil {
+			err := json.Unmarshal([]byte(strategy.Config), &configObj)
+			if err != nil {
+				impl.logger.Errorw("service err, un-marshaling fail in config object in cd", "err", err, "appId", appId)
+				return nil, err
+			}
+		}
+		deploymentTemplateStrategyResp.Config = configObj
+		deploymentTemplateStrategiesResp = append(deploymentTemplateStrategiesResp, deploymentTemplateStrategyResp)
+	}
+	cdPipelineResp.DeploymentStrategies = deploymentTemplateStrategiesResp
+
+	//set pre-deploy and post-deploy stage steps for multi step execution
+	cdPipelineMigrated, err := pipeline.ConvertStageYamlScriptsToPipelineStageSteps(cdPipeline)
+	if err != nil {
+		impl.logger.Errorw("service err, InitiateMigrationOfStageScriptsToPipelineStageSteps", "err", err, "appId", appId, "pipelineId", cdPipeline.Id)
+		return nil, err
+	}
+	cdPipelineResp.PreDeployStage = cdPipelineMigrated.PreDeployStage
+	cdPipelineResp.PostDeployStage = cdPipelineMigrated.PostDeployStage
+	//set pre stage config maps secret names
+	preStageConfigMapSecretNames := cdPipeline.PreStageConfigMapSecretNames
+	cdPipelineResp.PreStageConfigMapSecretNames = &appBean.CdStageConfigMapSecretNames{
+		ConfigMaps: preStageConfigMapSecretNames.ConfigMaps,
+		Secrets:    preStageConfigMapSecretNames.Secrets,
+	}
+
+	//set post stage config maps secret names
+	postStageConfigMapSecretNames := cdPipeline.PostStageConfigMapSecretNames
+	cdPipelineResp.PostStageConfigMapSecretNames = &appBean.CdStageConfigMapSecretNames{
+		ConfigMaps: postStageConfigMapSecretNames.ConfigMaps,
+		Secrets:    postStageConfigMapSecretNames.Secrets,
+	}
+
+	return cdPipelineResp, nil
+}
+
+// get/build global config maps
+func (impl *AppDefinitionServiceImpl) buildAppGlobalConfigMaps(appId int) ([]*appBean.ConfigMap, error, int) {
+	impl.logger.Debugw("Getting app detail - global config maps", "appId", appId)
+
+	configMapData, err := impl.configMapService.CMGlobalFetch(appId)
+	if err != nil {
+		impl.logger.Errorw("service err, CMGlobalFetch in GetAppAllDetail", "err", err, "appId", appId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return impl.buildAppConfigMaps(appId, 0, configMapData)
+}
+
+// get/build environment config maps
+func (impl *AppDefinitionServiceImpl) buildAppEnvironmentConfigMaps(appId int, envId int) ([]*appBean.ConfigMap, error, int) {
+	impl.logger.Debugw("Getting app detail - environment config maps", "appId", appId, "envId", envId)
+
+	configMapData, err := impl.configMapService.CMEnvironmentFetch(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("service err, CMEnvironmentFetch in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return impl.buildAppConfigMaps(appId, envId, configMapData)
+}
+
+// get/build config maps
+func (impl *AppDefinitionServiceImpl) buildAppConfigMaps(appId int, envId int, configMapData *pipeline.ConfigDataRequest) ([]*appBean.ConfigMap, error, int) {
+	impl.logger.Debugw("Getting app detail - config maps", "appId", appId, "envId", envId)
+
+	var configMapsResp []*appBean.ConfigMap
+	if configMapData != nil && len(configMapData.ConfigData) > 0 {
+		for _, configMap := range configMapData.ConfigData {
+
+			//initialise
+			configMapRes := &appBean.ConfigMap{
+				Name:       configMap.Name,
+				IsExternal: configMap.External,
+				UsageType:  configMap.Type,
+			}
+
+			//set data
+			data := configMap.Data
+			var dataObj map[string]interface{}
+			if data != nil {
+				err := json.Unmarshal([]byte(data), &dataObj)
+				if err != nil {
+					impl.logger.Errorw("service err, un-marshaling of data fail in config map", "err", err, "appId", appId)
+					return nil, err, http.StatusInternalServerError
+				}
+			}
+			configMapRes.Data = dataObj
+
+			//set data volume usage type
+			if configMap.Type == util.ConfigMapSecretUsageTypeVolume {
+				dataVolumeUsageConfig := &appBean.ConfigMapSecretDataVolumeUsageConfig{
+					FilePermission: configMap.FilePermission,
+					SubPath:        configMap.SubPath,
+				}
+				considerGlobalDefaultData := envId > 0 && configMap.Data == nil
+				if considerGlobalDefaultData {
+					dataVolumeUsageConfig.MountPath = configMap.DefaultMountPath
+				} else {
+					dataVolumeUsageConfig.MountPath = configMap.MountPath
+				}
+
+				configMapRes.DataVolumeUsageConfig = dataVolumeUsageConfig
+			}
+
+			configMapsResp = append(configMapsResp, configMapRes)
+		}
+	}
+	return configMapsResp, nil, http.StatusOK
+}
+
+// get/build global secrets
+func (impl *AppDefinitionServiceImpl) buildAppGlobalSecrets(appId int) ([]*appBean.Secret, error, int) {
+	impl.logger.Debugw("Getting app detail - global secret", "appId", appId)
+
+	secretData, err := impl.configMapService.CSGlobalFetch(appId)
+	if err != nil {
+		impl.logger.Errorw("service err, CSGlobalFetch in GetAppAllDetail", "err", err, "appId", appId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	var secretsResp []*appBean.Secret
+	if secretData != nil && len(secretData.ConfigData) > 0 {
+
+		for _, secretConfig := range secretData.ConfigData {
+			secretDataWithData, err := impl.configMapService.CSGlobalFetchForEdit(secretConfig.Name, secretData.Id)
+			if err != nil {
+				impl.logger.Errorw("service err, CSGlobalFetch-CSGlobalFetchForEdit in GetAppAllDetail", "err", err, "appId", appId)
+				return nil, err, http.StatusInternalServerError
+			}
+
+			secretRes, err, statusCode := impl.buildAppSecrets(appId, 0, secretDataWithData)
+			if err != nil {
+				impl.logger.Errorw("service err, CSGlobalFetch-buildAppSecrets in GetAppAllDetail", "err", err, "appId", appId)
+				return nil, err, statusCode
+			}
+
+			for _, secret := range secretRes {
+				secretsResp = append(secretsResp, secret)
+			}
+		}
+	}
+
+	return secretsResp, nil, http.StatusOK
+}
+
+// get/build environment secrets
+func (impl *AppDefinitionServiceImpl) buildAppEnvironmentSecrets(appId int, envId int) ([]*appBean.Secret, error, int) {
+	impl.logger.Debugw("Getting app detail - env secrets", "appId", appId, "envId", envId)
+
+	secretData, err := impl.configMapService.CSEnvironmentFetch(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("service err, CSEnvironmentFetch in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	var secretsResp []*appBean.Secret
+	if secretData != nil && len(secretData.ConfigData) > 0 {
+
+		for _, secretConfig := range secretData.ConfigData {
+			secretDataWithData, err := impl.configMapService.CSEnvironmentFetchForEdit(secretConfig.Name, secretData.Id, appId, envId)
+			if err != nil {
+				impl.logger.Errorw("service err, CSEnvironmentFetchForEdit in GetAppAllDetail", "err", err, "appId", appId, "envId", envId)
+				return nil, err, http.StatusInternalServerError
+			}
+			if secretConfig.Data == nil {
+				secretDataWithData.ConfigData[0].Data = secretConfig.Data
+			}
+			secretDataWithData.ConfigData[0].DefaultData = secretConfig.DefaultData
+
+			secretRes, err, statusCode := impl.buildAppSecrets(appId, envId, secretDataWithData)
+			if err != nil {
+				impl.logger.Errorw("service err, CSGlobalFetch-buildAppSecrets in GetAppAllDetail", "err", err, "appId", appId)
+				return nil, err, statusCode
+			}
+
+			for _, secret := range secretRes {
+				secretsResp = append(secretsResp, secret)
+			}
+		}
+	}
+
+	return secretsResp, nil, http.StatusOK
+}
+
+// get/build secrets
+func (impl *AppDefinitionServiceImpl) buildAppSecrets(appId int, envId int, secretData *pipeline.ConfigDataRequest) ([]*appBean.Secret, error, int) {
+	impl.logger.Debugw("Getting app detail - secrets", "appId", appId, "envId", envId)
+
+	var secretsResp []*appBean.Secret
+	if secretData != nil && len(secretData.ConfigData) > 0 {
+		for _, secret := range secretData.ConfigData {
+
+			//initialise
+			globalSecret := &appBean.Secret{
+				Name:         secret.Name,
+				RoleArn:      secret.RoleARN,
+				IsExternal:   secret.External,
+				UsageType:    secret.Type,
+				ExternalType: secret.ExternalSecretType,
+			}
+
+			//set data
+			data := secret.Data
+			var dataObj map[string]interface{}
+			if data != nil {
+				err := json.Unmarshal([]byte(data), &dataObj)
+				if err != nil {
+					impl.logger.Errorw("service err, un-marshaling of data fail in secret", "err", err, "appId", appId)
+					return nil, err, http.StatusInternalServerError
+				}
+			}
+			globalSecret.Data = dataObj
+
+			//set external data
+			externalSecrets := secret.ExternalSecret
+			var externalSecretsResp []*appBean.ExternalSecret
+			if len(externalSecrets) > 0 {
+				for _, externalSecret := range externalSecrets {
+					externalSecretsResp = append(externalSecretsResp, &appBean.ExternalSecret{
+						Name:     externalSecret.Name,
+						Key:      externalSecret.Key,
+						Property: externalSecret.Property,
+						IsBinary: externalSecret.IsBinary,
+					})
+				}
+			}
+			globalSecret.ExternalSecretData = externalSecretsResp
+
+			//set data volume usage type
+			if secret.Type == util.ConfigMapSecretUsageTypeVolume {
+				globalSecret.DataVolumeUsageConfig = &appBean.ConfigMapSecretDataVolumeUsageConfig{
+					SubPath:        secret.SubPath,
+					FilePermission: secret.FilePermission,
+				}
+				considerGlobalDefaultData := envId > 0 && secret.Data == nil
+				if considerGlobalDefaultData {
+					globalSecret.DataVolumeUsageConfig.MountPath = secret.DefaultMountPath
+				} else {
+					globalSecret.DataVolumeUsageConfig.MountPath = secret.MountPath
+				}
+			}
+
+			secretsResp = append(secretsResp, globalSecret)
+		}
+	}
+	return secretsResp, nil, http.StatusOK
+}
+
+// get/build environment overrides
+func (impl *AppDefinitionServiceImpl) BuildEnvironmentOverrides(ctx context.Context, appId int, enforce EnforceFunc) (map[string]*appBean.EnvironmentOverride, error, int) {
+	impl.logger.Debugw("Getting app detail - env override", "appId", appId)
+
+	appEnvironments, err := impl.appListingService.FetchOtherEnvironment(ctx, appId)
+	if err != nil {
+		impl.logger.Errorw("service err, Fetch app environments in GetAppAllDetail", "err", err, "appId", appId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	environmentOverrides := make(map[string]*appBean.EnvironmentOverride)
+	if len(appEnvironments) > 0 {
+		for _, appEnvironment := range appEnvironments {
+			environmentOverride, err, _ := impl.BuildEnvironmentOverride(ctx, appId, appEnvironment.EnvironmentId, enforce)
+			if err != nil {
+				impl.logger.Errorw("service err", "err", err)
+				return nil, err, http.StatusInternalServerError
+			}
+			override := environmentOverride[appEnvironment.EnvironmentName]
+			environmentOverrides[appEnvironment.EnvironmentName] = &appBean.EnvironmentOverride{
+				Secrets:            override.Secrets,
+				ConfigMaps:         override.ConfigMaps,
+				DeploymentTemplate: override.DeploymentTemplate,
+			}
+		}
+	}
+	return environmentOverrides, nil, http.StatusOK
+}
+
+// get/build environment overrides
+func (impl *AppDefinitionServiceImpl) BuildEnvironmentOverride(ctx context.Context, appId int, environmentId int, enforce EnforceFunc) (map[string]*appBean.EnvironmentOverride, error, int) {
+	impl.logger.Debugw("Getting app detail - env override", "appId", appId)
+	environmentOverrides := make(map[string]*appBean.EnvironmentOverride)
+	//check RBAC for environment
+	object := impl.enforcerUtil.GetEnvRBACNameByAppId(appId, environmentId)
+	if ok := impl.enforce(enforce, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+		impl.logger.Errorw("Unauthorized User for env update action", "appId", appId, "envId", environmentId)
+		return nil, fmt.Errorf("unauthorized user"), http.StatusForbidden
+	}
+	//RBAC end
+
+	environment, err := impl.environmentRepository.FindById(environmentId)
+	if err != nil {
+		impl.logger.Errorw("service err, for fetching environment model", "err", err, "appId", appId)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	envDeploymentTemplateResp, err, statusCode := impl.buildAppEnvironmentDeploymentTemplate(appId, environmentId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	envSecretsResp, err, statusCode := impl.buildAppEnvironmentSecrets(appId, environmentId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+
+	envConfigMapsResp, err, statusCode := impl.buildAppEnvironmentConfigMaps(appId, environmentId)
+	if err != nil {
+		return nil, err, statusCode
+	}
+
+	environmentOverrides[environment.Name] = &appBean.EnvironmentOverride{
+		Secrets:            envSecretsResp,
+		ConfigMaps:         envConfigMapsResp,
+		DeploymentTemplate: envDeploymentTemplateResp,
+	}
+
+	return environmentOverrides, nil, http.StatusOK
+}
+
+//GetApp related methods ends
+
+//Create App related methods starts
+
+// create app with all of its components, the app is deleted if any of them fails
+func (impl *AppDefinitionServiceImpl) CreateApp(ctx context.Context, createAppRequest *appBean.AppDetail, userId int32, enforce EnforceFunc) (int, error, int) {
+	err := impl.validator.Struct(createAppRequest)
+	if err != nil {
+		impl.logger.Errorw("validation err, CreateApp", "err", err)
+		return 0, err, http.StatusBadRequest
+	}
+	//creating blank app starts
+	createBlankAppResp, err, statusCode := impl.createBlankApp(createAppRequest.Metadata, userId)
+	if err != nil {
+		return 0, err, statusCode
+	}
+	//creating blank app ends
+
+	//declaring appId for creating other components of app
+	appId := createBlankAppResp.Id
+
+	rollback := func(err error, statusCode int) (int, error, int) {
+		var errResp *multierror.Error
+		errResp = multierror.Append(errResp, err)
+		errInAppDelete := impl.DeleteApp(ctx, appId, userId)
+		if errInAppDelete != nil {
+			errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+		}
+		return 0, errResp, statusCode
+	}
+
+	//creating git material starts
+	if createAppRequest.GitMaterials != nil {
+		err, statusCode = impl.CreateGitMaterials(appId, createAppRequest.GitMaterials, userId)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating git material ends
+
+	//creating docker config
+	if createAppRequest.DockerConfig != nil {
+		err, statusCode = impl.createDockerConfig(appId, createAppRequest.DockerConfig, userId)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating docker config ends
+
+	//creating deployment template starts
+	if createAppRequest.GlobalDeploymentTemplate != nil {
+		err, statusCode = impl.createDeploymentTemplate(ctx, appId, createAppRequest.GlobalDeploymentTemplate, userId)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating deployment template ends
+
+	//creating global configMaps starts
+	if createAppRequest.GlobalConfigMaps != nil {
+		err, statusCode = impl.CreateGlobalConfigMaps(appId, userId, createAppRequest.GlobalConfigMaps)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating global configMaps ends
+
+	//creating global secrets starts
+	if createAppRequest.GlobalSecrets != nil {
+		err, statusCode = impl.CreateGlobalSecrets(appId, userId, createAppRequest.GlobalSecrets)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating global secrets ends
+
+	//creating workflow starts
+	if createAppRequest.AppWorkflows != nil {
+		err, statusCode = impl.CreateWorkflows(ctx, appId, userId, createAppRequest.AppWorkflows, enforce, createAppRequest.Metadata.AppName)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating workflow ends
+
+	//creating environment override starts
+	if createAppRequest.EnvironmentOverrides != nil {
+		err, statusCode = impl.CreateEnvOverrides(ctx, appId, userId, createAppRequest.EnvironmentOverrides, enforce)
+		if err != nil {
+			return rollback(err, statusCode)
+		}
+	}
+	//creating environment override ends
+	return appId, nil, http.StatusOK
+}
+
+// create a blank app with metadata
+func (impl *AppDefinitionServiceImpl) createBlankApp(appMetadata *appBean.AppMetadata, userId int32) (*bean.CreateAppDTO, error, int) {
+	impl.logger.Infow("Create App - creating blank app", "appMetadata", appMetadata)
+
+	//validating app metadata
+	err := impl.validator.Struct(appMetadata)
+	if err != nil {
+		impl.logger.Errorw("validation err, AppMetadata in create app by API", "err", err, "AppMetadata", appMetadata)
+		return nil, err, http.StatusBadRequest
+	}
+
+	team, err := impl.teamService.FindByTeamName(appMetadata.ProjectName)
+	if err != nil {
+		impl.logger.Infow("no project found by name in CreateApp request by API")
+		return nil, err, http.StatusBadRequest
+	}
+
+	impl.logger.Infow("Create App - creating blank app with metadata", "appMetadata", appMetadata)
+
+	createAppRequest := &bean.CreateAppDTO{
+		AppName: appMetadata.AppName,
+		TeamId:  team.Id,
+		UserId:  userId,
+	}
+
+	var appLabels []*bean.Label
+	for _, requestLabel := range appMetadata.Labels {
+		appLabel := &bean.Label{
+			Key:       requestLabel.Key,
+			Value:     requestLabel.Value,
+			Propagate: requestLabel.Propagate,
+		}
+		appLabels = append(appLabels, appLabel)
+	}
+	createAppRequest.AppLabels = appLabels
+
+	createAppResp, err := impl.pipelineBuilder.CreateApp(createAppRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateApp in CreateBlankApp", "err", err, "CreateApp", createAppRequest)
+		return nil, err, http.StatusInternalServerError
+	}
+
+	return createAppResp, nil, http.StatusOK
+}
+
+// delete app
+func (impl *AppDefinitionServiceImpl) DeleteApp(ctx context.Context, appId int, userId int32) error {
+	impl.logger.Infow("Delete app", "appid", appId)
+
+	//finding all workflows for app
+	workflowsList, err := impl.appWorkflowService.FindAppWorkflows(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching workflows for app in DeleteApp", "err", err)
+		return err
+	}
+
+	//deleting all ci, cd pipelines & workflows before deleting app
+	if len(workflowsList) > 0 {
+
+		// delete all CD pipelines for app starts
+		cdPipelines, err := impl.pipelineBuilder.GetCdPipelinesForApp(appId)
+		if err != nil {
+			impl.logger.Errorw("service err, GetCdPipelines in DeleteApp", "err", err, "appId", appId)
+			return err
+		}
+
+		for _, cdPipeline := range cdPipelines.Pipelines {
+			cdPipelineDeleteRequest := &bean.CDPatchRequest{
+				AppId:            appId,
+				UserId:           userId,
+				Action:           bean.CD_DELETE,
+				ForceDelete:      true,
+				NonCascadeDelete: false,
+				Pipeline:         cdPipeline,
+			}
+			_, err = impl.pipelineBuilder.PatchCdPipelines(cdPipelineDeleteRequest, ctx)
+			if err != nil {
+				impl.logger.Errorw("err in deleting cd pipeline in DeleteApp", "err", err, "payload", cdPipelineDeleteRequest)
+				return err
+			}
+		}
+		// delete all CD pipelines for app ends
+
+		// delete all CI pipelines for app starts
+		ciPipelines, err := impl.pipelineBuilder.GetCiPipeline(appId)
+		if err != nil {
+			impl.logger.Errorw("service err, GetCiPipelines in DeleteApp", "err", err, "appId", appId)
+			return err
+		}
+		for _, ciPipeline := range ciPipelines.CiPipelines {
+			ciPipelineDeleteRequest := &bean.CiPatchRequest{
+				AppId:      appId,
+				UserId:     userId,
+				Action:     bean.DELETE,
+				CiPipeline: ciPipeline,
+			}
+			_, err := impl.pipelineBuilder.PatchCiPipeline(ciPipelineDeleteRequest)
+			if err != nil {
+				impl.logger.Errorw("err in deleting ci pipeline in DeleteApp", "err", err, "payload", ciPipelineDeleteRequest)
+				return err
+			}
+		}
+		// delete all CI pipelines for app ends
+
+		// delete all workflows for app starts
+		for _, workflow := range workflowsList {
+			err = impl.appWorkflowService.DeleteAppWorkflow(workflow.Id, userId)
+			if err != nil {
+				impl.logger.Errorw("service err, DeleteAppWorkflow ")
+				return err
+			}
+		}
+		// delete all workflows for app ends
+	}
+
+	// delete app
+	err = impl.pipelineBuilder.DeleteApp(appId, userId)
+	if err != nil {
+		impl.logger.Errorw("service error, DeleteApp", "err", err, "appId", appId)
+		return err
+	}
+	return nil
+}
+
+// create git materials
+func (impl *AppDefinitionServiceImpl) CreateGitMaterials(appId int, gitMaterials []*appBean.GitMaterial, userId int32) (error, int) {
+	impl.logger.Infow("Create App - creating git materials", "appId", appId, "GitMaterials", gitMaterials)
+
+	createMaterialRequest := &bean.CreateMaterialDTO{
+		AppId:  appId,
+		UserId: userId,
+	}
+
+	for _, material := range gitMaterials {
+		err := impl.validator.Struct(material)
+		if err != nil {
+			impl.logger.Errorw("validation err, gitMaterial in CreateGitMaterials", "err", err, "GitMaterial", material)
+			return err, http.StatusBadRequest
+		}
+
+		//finding gitProvider to update gitMaterial
+		gitProvider, err := impl.gitProviderRepo.FindByUrl(material.GitProviderUrl)
+		if err != nil {
+			impl.logger.Errorw("service err, FindByUrl in CreateGitMaterials", "err", err, "gitProviderUrl", material.GitProviderUrl)
+			return err, http.StatusInternalServerError
+		}
+
+		//validating git material by git provider auth mode
+		var hasPrefixResult bool
+		var expectedUrlPrefix string
+		if gitProvider.AuthMode == repository.AUTH_MODE_SSH {
+			hasPrefixResult = strings.HasPrefix(material.GitRepoUrl, SSH_URL_PREFIX)
+			expectedUrlPrefix = SSH_URL_PREFIX
+		} else {
+			hasPrefixResult = strings.HasPrefix(material.GitRepoUrl, HTTPS_URL_PREFIX)
+			expectedUrlPrefix = HTTPS_URL_PREFIX
+		}
+		if !hasPrefixResult {
+			impl.logger.Errorw("validation err, CreateGitMaterials : invalid git material url", "err", err, "gitMaterialUrl", material.GitRepoUrl)
+			return fmt.Errorf("validation for url failed, expected url prefix : %s", expectedUrlPrefix), http.StatusBadRequest
+		}
+
+		gitMaterialRequest := &bean.GitMaterial{
+			Url:             material.GitRepoUrl,
+			GitProviderId:   gitProvider.Id,
+			CheckoutPath:    material.CheckoutPath,
+			FetchSubmodules: material.FetchSubmodules,
+		}
+
+		createMaterialRequest.Material = append(createMaterialRequest.Material, gitMaterialRequest)
+	}
+
+	_, err := impl.pipelineBuilder.CreateMaterialsForApp(createMaterialRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateMaterialsForApp in CreateGitMaterials", "err", err, "CreateMaterial", createMaterialRequest)
+		return err, http.StatusInternalServerError
+	}
+
+	return nil, http.StatusOK
+}
+
+// create docker config
+func (impl *AppDefinitionServiceImpl) createDockerConfig(appId int, dockerConfig *appBean.DockerConfig, userId int32) (error, int) {
+	impl.logger.Infow("Create App - creating docker config", "appId", appId, "DockerConfig", dockerConfig)
+	dockerBuildConfig := dockerConfig.DockerBuildConfig
+	if dockerBuildConfig != nil {
+		dockerConfig.CheckoutPath = dockerBuildConfig.GitCheckoutPath
+		dockerConfig.CiBuildConfig = &bean2.CiBuildConfigBean{
+			CiBuildType: bean2.SELF_DOCKERFILE_BUILD_TYPE,
+			DockerBuildConfig: &bean2.DockerBuildConfig{
+				DockerfilePath:     dockerBuildConfig.DockerfileRelativePath,
+				DockerBuildOptions: dockerBuildConfig.DockerBuildOptions,
+				Args:               dockerBuildConfig.Args,
+				TargetPlatform:     dockerBuildConfig.TargetPlatform,
+				BuildContext:       dockerBuildConfig.BuildContext,
+			},
+		}
+	}
+	createDockerConfigRequest := &bean.CiConfigRequest{
+		AppId:            appId,
+		UserId:           userId,
+		DockerRegistry:   dockerConfig.DockerRegistry,
+		DockerRepository: dockerConfig.DockerRepository,
+	}
+
+	//finding gitMaterial by appId and checkoutPath
+	gitMaterial, err := impl.materialRepository.FindByAppIdAndCheckoutPath(appId, dockerConfig.CheckoutPath)
+	if err != nil {
+		impl.logger.Errorw("service err, FindByAppIdAndCheckoutPath in CreateDockerConfig", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+
+	ciBuildConfig := dockerConfig.CiBuildConfig
+	ciBuildConfig.GitMaterialId = gitMaterial.Id
+	createDockerConfigRequest.CiBuildConfig = ciBuildConfig
+
+	_, err = impl.pipelineBuilder.CreateCiPipeline(createDockerConfigRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateCiPipeline in CreateDockerConfig", "err", err, "createRequest", createDockerConfigRequest)
+		return err, http.StatusInternalServerError
+	}
+
+	return nil, http.StatusOK
+}
+
+// create global template
+func (impl *AppDefinitionServiceImpl) createDeploymentTemplate(ctx context.Context, appId int, deploymentTemplate *appBean.DeploymentTemplate, userId int32) (error, int) {
+	impl.logger.Infow("Create App - creating deployment template", "appId", appId, "DeploymentStrategy", deploymentTemplate)
+
+	createDeploymentTemplateRequest := chart.TemplateRequest{
+		AppId:               appId,
+		ChartRefId:          deploymentTemplate.ChartRefId,
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+		UserId:              userId,
+		IsBasicViewLocked:   deploymentTemplate.IsBasicViewLocked,
+		CurrentViewEditor:   deploymentTemplate.CurrentViewEditor,
+	}
+
+	//marshalling template
+	template, err := json.Marshal(deploymentTemplate.Template)
+	if err != nil {
+		impl.logger.Errorw("service err, could not json marshal template in CreateDeploymentTemplate", "err", err, "appId", appId, "template", deploymentTemplate.Template)
+		return err, http.StatusInternalServerError
+	}
+	templateRequest := json.RawMessage(template)
+	createDeploymentTemplateRequest.ValuesOverride = templateRequest
+
+	//creating deployment template
+	_, err = impl.chartService.Create(createDeploymentTemplateRequest, ctx)
+	if err != nil {
+		impl.logger.Errorw("service err, Create in CreateDeploymentTemplate", "err", err, "createRequest", createDeploymentTemplateRequest)
+		return err, http.StatusInternalServerError
+	}
+
+	//updating app metrics
+	appMetricsRequest := chart.AppMetricEnableDisableRequest{
+		AppId:               appId,
+		UserId:              userId,
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+	}
+	_, err = impl.chartService.AppMetricsEnableDisable(appMetricsRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, AppMetricsEnableDisable in createDeploymentTemplate", "err", err, "appId", appId, "payload", appMetricsRequest)
+		return err, http.StatusInternalServerError
+	}
+
+	return nil, http.StatusOK
+}
+
+// create global CMs
+func (impl *AppDefinitionServiceImpl) CreateGlobalConfigMaps(appId int, userId int32, configMaps []*appBean.ConfigMap) (error, int) {
+	impl.logger.Infow("Create App - creating global configMap", "appId", appId)
+
+	var appLevelId int
+	for _, configMap := range configMaps {
+
+		//getting app level by app id
+		if appLevelId == 0 {
+			appLevel, err := impl.configMapRepository.GetByAppIdAppLevel(appId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in getting app level by app id in createGlobalConfigMaps", "appId", appId)
+				return err, http.StatusInternalServerError
+			}
+
+			if appLevel != nil {
+				appLevelId = appLevel.Id
+			}
+		}
+
+		//marshalling configMap data, i.e. key-value pairs
+		configMapKeyValueData, err := json.Marshal(configMap.Data)
+		if err != nil {
+			impl.logger.Errorw("service err, could not json marshal configMap data in CreateGlobalConfigMap", "err", err, "appId", appId, "configMapData", configMap.Data)
+			return err, http.StatusInternalServerError
+		}
+
+		// build
+		configMapData := &pipeline.ConfigData{
+			Name:     configMap.Name,
+			External: configMap.IsExternal,
+			Data:     json.RawMessage(configMapKeyValueData),
+			Type:     configMap.UsageType,
+		}
+		dataVolumeUsageConfig := configMap.DataVolumeUsageConfig
+		if dataVolumeUsageConfig != nil {
+			configMapData.MountPath = dataVolumeUsageConfig.MountPath
+			configMapData.SubPath = dataVolumeUsageConfig.SubPath
+			configMapData.FilePermission = dataVolumeUsageConfig.FilePermission
+		}
+
+		// service call
+		var configMapDataRequest []*pipeline.ConfigData
+		configMapDataRequest = append(configMapDataRequest, configMapData)
+		configMapRequest := &pipeline.ConfigDataRequest{
+			AppId:      appId,
+			UserId:     userId,
+			Id:         appLevelId,
+			ConfigData: configMapDataRequest,
+		}
+		//using same var for every request, since appId and userID are same
+		_, err = impl.configMapService.CMGlobalAddUpdate(configMapRequest)
+		if err != nil {
+			impl.logger.Errorw("service err, CMGlobalAddUpdate in CreateGlobalConfigMap", "err", err, "appId", appId, "configMapRequest", configMapRequest)
+			return err, http.StatusInternalServerError
+		}
+	}
+
+	return nil, http.StatusOK
+
+}
+
+// create global secrets
+func (impl *AppDefinitionServiceImpl) CreateGlobalSecrets(appId int, userId int32, secrets []*appBean.Secret) (error, int) {
+	impl.logger.Infow("Create App - creating global secrets", "appId", appId)
+
+	var appLevelId int
+	for _, secret := range secrets {
+		//getting app level by app id
+		if appLevelId == 0 {
+			appLevel, err := impl.configMapRepository.GetByAppIdAppLevel(appId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in getting app level by app id in createGlobalSecrets", "appId", appId)
+				return err, http.StatusInternalServerError
+			}
+
+			if appLevel != nil {
+				appLevelId = appLevel.Id
+			}
+		}
+
+		// build
+		secretData := &pipeline.ConfigData{
+			Name:               secret.Name,
+			External:           secret.IsExternal,
+			Type:               secret.UsageType,
+			ExternalSecretType: secret.ExternalType,
+			RoleARN:            secret.RoleArn,
+		}
+
+		dataVolumeUsageConfig := secret.DataVolumeUsageConfig
+		if dataVolumeUsageConfig != nil {
+			secretData.MountPath = dataVolumeUsageConfig.MountPath
+			secretData.SubPath = dataVolumeUsageConfig.SubPath
+			secretData.FilePermission = dataVolumeUsageConfig.FilePermission
+		}
+
+		if secret.IsExternal {
+			var externalDataRequests []pipeline.ExternalSecret
+			for _, externalData := range secret.ExternalSecretData {
+				externalDataRequest := pipeline.ExternalSecret{
+					Name:     externalData.Name,
+					IsBinary: externalData.IsBinary,
+					Key:      externalData.Key,
+					Property: externalData.Property,
+				}
+				externalDataRequests = append(externalDataRequests, externalDataRequest)
+			}
+			secretData.ExternalSecret = externalDataRequests
+		} else {
+			secretKeyValueData, err := json.Marshal(secret.Data)
+			if err != nil {
+				impl.logger.Errorw("service err, could not json marshal secret data in CreateGlobalSecret", "err", err, "appId", appId)
+				return err, http.StatusInternalServerError
+			}
+			secretData.Data = secretKeyValueData
+		}
+
+		// service call
+		var secretDataRequest []*pipeline.ConfigData
+		secretDataRequest = append(secretDataRequest, secretData)
+		secretRequest := &pipeline.ConfigDataRequest{
+			AppId:      appId,
+			UserId:     userId,
+			Id:         appLevelId,
+			ConfigData: secretDataRequest,
+		}
+		//using same var for every request, since appId and userID are same
+		_, err := impl.configMapService.CSGlobalAddUpdate(secretRequest)
+		if err != nil {
+			impl.logger.Errorw("service err, CSGlobalAddUpdate in CreateGlobalSecret", "err", err, "appId", appId)
+			return err, http.StatusInternalServerError
+		}
+	}
+
+	return nil, http.StatusOK
+}
+
+// create app workflows
+func (impl *AppDefinitionServiceImpl) CreateWorkflows(ctx context.Context, appId int, userId int32, workflows []*appBean.AppWorkflow, enforce EnforceFunc, appName string) (error, int) {
+	impl.logger.Infow("Create App - creating workflows", "appId", appId, "workflows size", len(workflows))
+	for _, workflow := range workflows {
+		//Create workflow starts (we need to create workflow with given name)
+		workflowId, err := impl.createWorkflowInDb(workflow.Name, appId, userId)
+		if err != nil {
+			impl.logger.Errorw("err in saving new workflow", err, "appId", appId)
+			return err, http.StatusInternalServerError
+		}
+		//Creating workflow ends
+
+		//Creating CI pipeline starts
+		ciPipelineId, err := impl.createCiPipeline(appId, userId, workflowId, workflow.CiPipeline)
+		if err != nil {
+			impl.logger.Errorw("err in saving ci pipelines", err, "appId", appId)
+			return err, http.StatusInternalServerError
+		}
+		//Creating CI pipeline ends
+
+		//Creating CD pipeline starts
+		err = impl.createCdPipelines(ctx, appId, userId, workflowId, ciPipelineId, workflow.CdPipelines, enforce, appName)
+		if err != nil {
+			impl.logger.Errorw("err in saving cd pipelines", err, "appId", appId)
+			return err, http.StatusInternalServerError
+		}
+		//Creating CD pipeline ends
+	}
+	return nil, http.StatusOK
+}
+
+func (impl *AppDefinitionServiceImpl) createWorkflowInDb(workflowName string, appId int, userId int32) (int, error) {
+	wf := &appWorkflow2.AppWorkflow{
+		Name:   workflowName,
+		AppId:  appId,
+		Active: true,
+		AuditLog: sql.AuditLog{
+			CreatedOn: time.Now(),
+			UpdatedOn: time.Now(),
+			CreatedBy: userId,
+			UpdatedBy: userId,
+		},
+	}
+	savedAppWf, err := impl.appWorkflowRepository.SaveAppWorkflow(wf)
+	if err != nil {
+		impl.logger.Errorw("err in saving new workflow", err, "appId", appId)
+		return 0, err
+	}
+
+	return savedAppWf.Id, nil
+}
+
+func (impl *AppDefinitionServiceImpl) createCiPipeline(appId int, userId int32, workflowId int, ciPipelineData *appBean.CiPipelineDetails) (int, error) {
+
+	// if ci pipeline is of external type, then throw error as we are not supporting it as of now
+	if ciPipelineData.ParentCiPipeline == 0 && ciPipelineData.ParentAppId == 0 && ciPipelineData.IsExternal {
+		err := errors.New("external ci pipeline creation is not supported yet")
+		impl.logger.Error("external ci pipeline creation is not supported yet")
+		return 0, err
+	}
+
+	// build ci pipeline materials starts
+	var ciMaterialsRequest []*bean.CiMaterial
+	for _, ciMaterial := range ciPipelineData.CiPipelineMaterialsConfig {
+		var gitMaterial *pipelineConfig.GitMaterial
+		var err error
+		if ciPipelineData.ParentCiPipeline == 0 && ciPipelineData.ParentAppId == 0 {
+			//finding gitMaterial by appId and checkoutPath
+			gitMaterial, err = impl.materialRepository.FindByAppIdAndCheckoutPath(appId, ciMaterial.CheckoutPath)
+		} else {
+			//if linkedci find git material by it's id
+			gitMaterial, err = impl.materialRepository.FindById(ciMaterial.GitMaterialId)
+		}
+		if err != nil {
+			impl.logger.Errorw("service err, FindByAppIdAndCheckoutPath in CreateWorkflows", "err", err, "appId", appId)
+			return 0, err
+		}
+
+		if gitMaterial == nil {
+			err = errors.New("gitMaterial is nil")
+			impl.logger.Errorw("gitMaterial is nil", "checkoutPath", ciMaterial.CheckoutPath)
+			return 0, err
+		}
+
+		ciMaterialRequest := &bean.CiMaterial{
+			GitMaterialId:   gitMaterial.Id,
+			GitMaterialName: gitMaterial.Name,
+			Source: &bean.SourceTypeConfig{
+				Type:  ciMaterial.Type,
+				Value: ciMaterial.Value,
+			},
+			CheckoutPath: gitMaterial.CheckoutPath,
+		}
+		ciMaterialsRequest = append(ciMaterialsRequest, ciMaterialRequest)
+	}
+	// build ci pipeline materials ends
+
+	// build model
+	ciPipelineRequest := &bean.CiPatchRequest{
+		AppId:         appId,
+		UserId:        userId,
+		AppWorkflowId: workflowId,
+		Action:        bean.CREATE,
+		CiPipeline: &bean.CiPipeline{
+			Name:                     ciPipelineData.Name,
+			IsManual:                 ciPipelineData.IsManual,
+			IsExternal:               ciPipelineData.IsExternal,
+			Active:                   true,
+			BeforeDockerBuildScripts: convertCiBuildScripts(ciPipelineData.BeforeDockerBuildScripts),
+			AfterDockerBuildScripts:  convertCiBuildScripts(ciPipelineData.AfterDockerBuildScripts),
+			DockerArgs:               ciPipelineData.DockerBuildArgs,
+			ScanEnabled:              ciPipelineData.VulnerabilityScanEnabled,
+			CiMaterial:               ciMaterialsRequest,
+			PreBuildStage:            ciPipelineData.PreBuildStage,
+			PostBuildStage:           ciPipelineData.PostBuildStage,
+			ParentCiPipeline:         ciPipelineData.ParentCiPipeline,
+			ParentAppId:              ciPipelineData.ParentAppId,
+			LinkedCount:              ciPipelineData.LinkedCount,
+		},
+	}
+
+	// service call
+	res, err := impl.pipelineBuilder.PatchCiPipeline(ciPipelineRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, PatchCiPipelines", "err", err, "appId", appId)
+		return 0, err
+	}
+
+	return res.CiPipelines[0].Id, nil
+}
+
+func (impl *AppDefinitionServiceImpl) createCdPipelines(ctx context.Context, appId int, userId int32, workflowId int, ciPipelineId int, cdPipelines []*appBean.CdPipelineDetails, enforce EnforceFunc, appName string) error {
+
+	var cdPipelineRequestConfigs []*bean.CDPipelineConfigObject
+	for _, cdPipeline := range cdPipelines {
+		//getting environment ID by name
+		envName := cdPipeline.EnvironmentName
+		envModel, err := impl.environmentRepository.FindByName(envName)
+		if err != nil {
+			impl.logger.Errorw("err in fetching environment details by name", "appId", appId, "envName", envName)
+			return err
+		}
+
+		if envModel == nil {
+			err = errors.New("environment not found for name " + envName)
+			impl.logger.Errorw("environment not found for name", "envName", envName)
+			return err
+		}
+
+		// RBAC starts
+		object := impl.enforcerUtil.GetAppRBACByAppNameAndEnvId(appName, envModel.Id)
+		if ok := impl.enforce(enforce, casbin.ResourceEnvironment, casbin.ActionCreate, object); !ok {
+			return errors.New("unauthorized User")
+		}
+		// RBAC ends
+
+		// build model
+		cdPipelineRequestConfig := &bean.CDPipelineConfigObject{
+			Name:                          cdPipeline.Name,
+			EnvironmentId:                 envModel.Id,
+			Namespace:                     envModel.Namespace,
+			AppWorkflowId:                 workflowId,
+			CiPipelineId:                  ciPipelineId,
+			DeploymentAppType:             cdPipeline.DeploymentAppType,
+			PreStage:                      convertCdStages(cdPipeline.PreStage),
+			PostStage:                     convertCdStages(cdPipeline.PostStage),
+			DeploymentTemplate:            cdPipeline.DeploymentStrategyType,
+			TriggerType:                   cdPipeline.TriggerType,
+			CdArgoSetup:                   cdPipeline.IsClusterCdActive,
+			RunPreStageInEnv:              cdPipeline.RunPreStageInEnv,
+			RunPostStageInEnv:             cdPipeline.RunPostStageInEnv,
+			PreDeployStage:                cdPipeline.PreDeployStage,
+			PostDeployStage:               cdPipeline.PostDeployStage,
+			PreStageConfigMapSecretNames:  convertCdPreStageCMorCSNames(cdPipeline.PreStageConfigMapSecretNames),
+			PostStageConfigMapSecretNames: convertCdPostStageCMorCSNames(cdPipeline.PostStageConfigMapSecretNames),
+		}
+		convertedDeploymentStrategies, err := convertCdDeploymentStrategies(cdPipeline.DeploymentStrategies)
+		if err != nil {
+			impl.logger.Errorw("err in converting deployment strategies for creating cd pipeline", "appId", appId, "Strategies", cdPipeline.DeploymentStrategies)
+			return err
+		}
+		cdPipelineRequestConfig.Strategies = convertedDeploymentStrategies
+
+		cdPipelineRequestConfigs = append(cdPipelineRequestConfigs, cdPipelineRequestConfig)
+	}
+
+	// service call
+	cdPipelinesRequest := &bean.CdPipelines{
+		AppId:     appId,
+		UserId:    userId,
+		Pipelines: cdPipelineRequestConfigs,
+	}
+	_, err := impl.pipelineBuilder.CreateCdPipelines(cdPipelinesRequest, ctx)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateCdPipeline", "err", err, "payload", cdPipelinesRequest)
+		return err
+	}
+	return nil
+}
+
+// create environment overrides
+func (impl *AppDefinitionServiceImpl) CreateEnvOverrides(ctx context.Context, appId int, userId int32, environmentOverrides map[string]*appBean.EnvironmentOverride, enforce EnforceFunc) (error, int) {
+	impl.logger.Infow("Create App - creating env overrides", "appId", appId)
+
+	for envName, envOverrideValues := range environmentOverrides {
+		envModel, err := impl.environmentRepository.FindByName(envName)
+
+		if err != nil {
+			impl.logger.Errorw("err in fetching environment details by name in CreateEnvOverrides", "appId", appId, "envName", envName)
+			return err, http.StatusInternalServerError
+		}
+
+		if envModel == nil {
+			err = errors.New("environment not found for name " + envName)
+			impl.logger.Errorw("environment not found for name", "envName", envName)
+			return err, http.StatusInternalServerError
+		}
+
+		// RBAC starts
+		object := impl.enforcerUtil.GetEnvRBACNameByAppId(appId, envModel.Id)
+		if ok := impl.enforce(enforce, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+			return fmt.Errorf("unauthorized user"), http.StatusForbidden
+		}
+		// RBAC ends
+
+		envId := envModel.Id
+
+		//creating deployment template override
+		envDeploymentTemplate := envOverrideValues.DeploymentTemplate
+		if envDeploymentTemplate != nil && envDeploymentTemplate.IsOverride {
+			err := impl.createEnvDeploymentTemplate(appId, userId, envModel.Id, envOverrideValues.DeploymentTemplate)
+			if err != nil {
+				impl.logger.Errorw("err in creating deployment template for env override", "appId", appId, "envName", envName)
+				return err, http.StatusInternalServerError
+			}
+		}
+
+		//creating configMap override
+		err = impl.createEnvCM(appId, userId, envId, envOverrideValues.ConfigMaps)
+		if err != nil {
+			impl.logger.Errorw("err in creating config map for env override", "appId", appId, "envName", envName)
+			return err, http.StatusInternalServerError
+		}
+
+		//creating secrets override
+		err = impl.createEnvSecret(appId, userId, envModel.Id, envOverrideValues.Secrets)
+		if err != nil {
+			impl.logger.Errorw("err in creating secret for env override", "appId", appId, "envName", envName)
+			return err, http.StatusInternalServerError
+		}
+
+	}
+	return nil, http.StatusOK
+}
+
+// create template overrides
+func (impl *AppDefinitionServiceImpl) createEnvDeploymentTemplate(appId int, userId int32, envId int, deploymentTemplateOverride *appBean.DeploymentTemplate) error {
+	impl.logger.Infow("Create App - creating template override", "appId", appId)
+
+	// build object
+	template, err := json.Marshal(deploymentTemplateOverride.Template)
+	if err != nil {
+		impl.logger.Errorw("json marshaling error env override template in createEnvDeploymentTemplate", "appId", appId, "envId", envId)
+		return err
+	}
+	chartRefId := deploymentTemplateOverride.ChartRefId
+	envConfigProperties := &pipeline.EnvironmentProperties{
+		IsOverride:        true,
+		Active:            true,
+		ManualReviewed:    true,
+		Status:            models.CHARTSTATUS_NEW,
+		EnvOverrideValues: template,
+		IsBasicViewLocked: deploymentTemplateOverride.IsBasicViewLocked,
+		CurrentViewEditor: deploymentTemplateOverride.CurrentViewEditor,
+		ChartRefId:        chartRefId,
+		EnvironmentId:     envId,
+		UserId:            userId,
+	}
+
+	// if chart not found for chart_ref then create
+	chartEntry, err := impl.chartRepo.FindChartByAppIdAndRefId(appId, chartRefId)
+	if err != nil {
+		if pg.ErrNoRows == err {
+			templateRequest := chart.TemplateRequest{
+				AppId:               appId,
+				ChartRefId:          chartRefId,
+				ValuesOverride:      []byte("{}"),
+				UserId:              userId,
+				IsAppMetricsEnabled: deploymentTemplateOverride.ShowAppMetrics,
+			}
+			newChartEntry, err := impl.chartService.CreateChartFromEnvOverride(templateRequest, context.Background())
+			if err != nil {
+				impl.logger.Errorw("service err, CreateChartFromEnvOverride", "err", err, "appId", appId, "envId", envId, "chartRefId", chartRefId)
+				return err
+			}
+			chartEntry.Id = newChartEntry.Id
+			chartEntry.AppId = newChartEntry.AppId
+		} else {
+			impl.logger.Errorw("service err, FindChartByAppIdAndRefId", "err", err, "appId", appId, "envId", envId, "chartRefId", chartRefId)
+			return err
+		}
+	}
+
+	// create if required
+	appMetrics := false
+	if envConfigProperties.AppMetrics != nil {
+		appMetrics = *envConfigProperties.AppMetrics
+	}
+	chartEntry.GlobalOverride = string(envConfigProperties.EnvOverrideValues)
+	_, err = impl.propertiesConfigService.CreateIfRequired(chartEntry, envId, userId, envConfigProperties.ManualReviewed, models.CHARTSTATUS_SUCCESS,
+		true, appMetrics, envConfigProperties.Namespace, envConfigProperties.IsBasicViewLocked, envConfigProperties.CurrentViewEditor, nil)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateIfRequired", "err", err, "appId", appId, "envId", envId, "chartRefId", chartRefId)
+		return err
+	}
+
+	//getting environment properties for db table id(this properties get created when cd pipeline is created)
+	env, err := impl.propertiesConfigService.GetEnvironmentProperties(appId, envId, deploymentTemplateOverride.ChartRefId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetEnvConfOverride", "err", err, "appId", appId, "envId", envId, "chartRefId", deploymentTemplateOverride.ChartRefId)
+		return err
+	}
+
+	//updating env template override
+	envConfigProperties.Id = env.EnvironmentConfig.Id
+	envConfigProperties.Namespace = env.Namespace
+	_, err = impl.propertiesConfigService.UpdateEnvironmentProperties(appId, envConfigProperties, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, EnvConfigOverrideUpdate", "err", err, "appId", appId, "envId", envId)
+		return err
+	}
+
+	//updating app metrics
+	appMetricsRequest := &chart.AppMetricEnableDisableRequest{
+		AppId:               appId,
+		UserId:              userId,
+		EnvironmentId:       envId,
+		IsAppMetricsEnabled: deploymentTemplateOverride.ShowAppMetrics,
+	}
+	_, err = impl.propertiesConfigService.EnvMetricsEnableDisable(appMetricsRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, EnvMetricsEnableDisable", "err", err, "appId", appId, "envId", envId)
+		return err
+	}
+
+	return nil
+}
+
+// create CM overrides
+func (impl *AppDefinitionServiceImpl) createEnvCM(appId int, userId int32, envId int, CmOverrides []*appBean.ConfigMap) error {
+	impl.logger.Infow("Create App - creating CM override", "appId", appId, "envId", envId)
+
+	var envLevelId int
+
+	for _, cmOverride := range CmOverrides {
+		//getting env level by app id and envId
+		if envLevelId == 0 {
+			envLevel, err := impl.configMapRepository.GetByAppIdAndEnvIdEnvLevel(appId, envId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in getting app level by app id in createEnvCM", "appId", appId, "envId", envId)
+				return err
+			}
+			if envLevel != nil {
+				envLevelId = envLevel.Id
+			}
+		}
+
+		cmOverrideData, err := json.Marshal(cmOverride.Data)
+		if err != nil {
+			impl.logger.Errorw("service err, could not json marshal template in CreateEnvCM", "err", err, "appId", appId, "envId", envId)
+			return err
+		}
+
+		// build
+		configData := &pipeline.ConfigData{
+			Name:     cmOverride.Name,
+			External: cmOverride.IsExternal,
+			Type:     cmOverride.UsageType,
+			Data:     json.RawMessage(cmOverrideData),
+		}
+		cmOverrideDataVolumeUsageConfig := cmOverride.DataVolumeUsageConfig
+		if cmOverrideDataVolumeUsageConfig != nil {
+			configData.MountPath = cmOverrideDataVolumeUsageConfig.MountPath
+			configData.SubPath = cmOverrideDataVolumeUsageConfig.SubPath
+			configData.FilePermission = cmOverrideDataVolumeUsageConfig.FilePermission
+		}
+
+		var configDataRequest []*pipeline.ConfigData
+		configDataRequest = append(configDataRequest, configData)
+
+		// service call
+		cmEnvRequest := &pipeline.ConfigDataRequest{
+			AppId:         appId,
+			UserId:        userId,
+			EnvironmentId: envId,
+			Id:            envLevelId,
+			ConfigData:    configDataRequest,
+		}
+
+		_, err = impl.configMapService.CMEnvironmentAddUpdate(cmEnvRequest)
+		if err != nil {
+			impl.logger.Errorw("service err, CMEnvironmentAddUpdate in CreateEnvCM", "err", err, "payload", cmEnvRequest)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// create secret overrides
+func (impl *AppDefinitionServiceImpl) createEnvSecret(appId int, userId int32, envId int, secretOverrides []*appBean.Secret) error {
+	impl.logger.Infow("Create App - creating secret overrides", "appId", appId)
+
+	var envLevelId int
+	for _, secretOverride := range secretOverrides {
+		//getting env level by app id
+		if envLevelId == 0 {
+			envLevel, err := impl.configMapRepository.GetByAppIdAndEnvIdEnvLevel(appId, envId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in getting app level by app id in createEnvSecret", "appId", appId, "envId", envId)
+				return err
+			}
+			if envLevel != nil {
+				envLevelId = envLevel.Id
+			}
+		}
+
+		// build
+		secretOverrideData, err := json.Marshal(secretOverride.Data)
+		if err != nil {
+			impl.logger.Errorw("service err, could not json marshal secret data in CreateEnvSecret", "err", err, "appId", appId, "envId", envId)
+			return err
+		}
+
+		secretData := &pipeline.ConfigData{
+			Name:               secretOverride.Name,
+			External:           secretOverride.IsExternal,
+			ExternalSecretType: secretOverride.ExternalType,
+			Type:               secretOverride.UsageType,
+			Data:               secretOverrideData,
+			RoleARN:            secretOverride.RoleArn,
+			ExternalSecret:     convertCSExternalSecretData(secretOverride.ExternalSecretData),
+		}
+		secretOverrideDataVolumeUsageConfig := secretOverride.DataVolumeUsageConfig
+		if secretOverrideDataVolumeUsageConfig != nil {
+			secretData.MountPath = secretOverrideDataVolumeUsageConfig.MountPath
+			secretData.SubPath = secretOverrideDataVolumeUsageConfig.SubPath
+			secretData.FilePermission = secretOverrideDataVolumeUsageConfig.FilePermission
+		}
+		var secretDataRequest []*pipeline.ConfigData
+		secretDataRequest = append(secretDataRequest, secretData)
+
+		// service call
+		secretEnvRequest := &pipeline.ConfigDataRequest{
+			AppId:         appId,
+			UserId:        userId,
+			EnvironmentId: envId,
+			Id:            envLevelId,
+			ConfigData:    secretDataRequest,
+		}
+		_, err = impl.configMapService.CSEnvironmentAddUpdate(secretEnvRequest)
+		if err != nil {
+			impl.logger.Errorw("service err, CSEnvironmentAddUpdate", "err", err, "appId", appId, "envId", envId)
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Create App related methods ends
+
+//private methods for data conversion below
+
+func convertCSExternalSecretData(externalSecretsData []*appBean.ExternalSecret) []pipeline.ExternalSecret {
+	var convertedExternalSecretsData []pipeline.ExternalSecret
+	for _, externalSecretData := range externalSecretsData {
+		convertedExternalSecret := pipeline.ExternalSecret{
+			Key:      externalSecretData.Key,
+			Name:     externalSecretData.Name,
+			Property: externalSecretData.Property,
+			IsBinary: externalSecretData.IsBinary,
+		}
+		convertedExternalSecretsData = append(convertedExternalSecretsData, convertedExternalSecret)
+	}
+	return convertedExternalSecretsData
+}
+
+func convertCiBuildScripts(buildScripts []*appBean.BuildScript) []*bean.CiScript {
+	var convertedBuildScripts []*bean.CiScript
+	for _, buildScript := range buildScripts {
+		convertedBuildScript := &bean.CiScript{
+			Index:          buildScript.Index,
+			Name:           buildScript.Name,
+			Script:         buildScript.Script,
+			OutputLocation: buildScript.ReportDirectoryPath,
+		}
+		convertedBuildScripts = append(convertedBuildScripts, convertedBuildScript)
+	}
+	return convertedBuildScripts
+}
+
+func convertCdStages(cdStage *appBean.CdStage) bean.CdStage {
+
+	convertedCdStage := bean.CdStage{}
+
+	if cdStage != nil {
+		convertedCdStage.TriggerType = cdStage.TriggerType
+		convertedCdStage.Name = cdStage.Name
+		convertedCdStage.Config = cdStage.Config
+	}
+
+	return convertedCdStage
+}
+
+func convertCdPreStageCMorCSNames(preStageNames *appBean.CdStageConfigMapSecretNames) bean.PreStageConfigMapSecretNames {
+
+	convertPreStageNames := bean.PreStageConfigMapSecretNames{}
+	if preStageNames != nil {
+		convertPreStageNames.ConfigMaps = preStageNames.ConfigMaps
+		convertPreStageNames.Secrets = preStageNames.Secrets
+	}
+
+	return convertPreStageNames
+}
+
+func convertCdPostStageCMorCSNames(postStageNames *appBean.CdStageConfigMapSecretNames) bean.PostStageConfigMapSecretNames {
+	convertPostStageNames := bean.PostStageConfigMapSecretNames{}
+	if postStageNames != nil {
+		convertPostStageNames.ConfigMaps = postStageNames.ConfigMaps
+		convertPostStageNames.Secrets = postStageNames.Secrets
+	}
+
+	return convertPostStageNames
+}
+
+func convertCdDeploymentStrategies(deploymentStrategies []*appBean.DeploymentStrategy) ([]bean.Strategy, error) {
+	var convertedStrategies []bean.Strategy
+	for _, deploymentStrategy := range deploymentStrategies {
+		convertedStrategy := bean.Strategy{
+			DeploymentTemplate: deploymentStrategy.DeploymentStrategyType,
+			Default:            deploymentStrategy.IsDefault,
+		}
+		strategyConfig, err := json.Marshal(deploymentStrategy.Config)
+		if err != nil {
+			return nil, err
+		}
+		convertedStrategy.Config = strategyConfig
+		convertedStrategies = append(convertedStrategies, convertedStrategy)
+	}
+	return convertedStrategies, nil
+}
+
+func (impl *AppDefinitionServiceImpl) UpdateDeploymentTemplate(ctx context.Context, appId int, deploymentTemplate *appBean.DeploymentTemplate, userId int32) error {
+	latestChart, err := impl.chartService.FindLatestChartForAppByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("service err, FindLatestChartForAppByAppId in UpdateDeploymentTemplate", "err", err, "appId", appId)
+		return err
+	}
+	template, err := json.Marshal(deploymentTemplate.Template)
+	if err != nil {
+		impl.logger.Errorw("service err, could not json marshal template in UpdateDeploymentTemplate", "err", err, "appId", appId)
+		return err
+	}
+	updateRequest := &chart.TemplateRequest{
+		Id:                  latestChart.Id,
+		AppId:               appId,
+		ChartRefId:          latestChart.ChartRefId,
+		ValuesOverride:      template,
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+		IsBasicViewLocked:   deploymentTemplate.IsBasicViewLocked,
+		CurrentViewEditor:   deploymentTemplate.CurrentViewEditor,
+		UserId:              userId,
+	}
+	_, err = impl.chartService.UpdateAppOverride(ctx, updateRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateAppOverride in UpdateDeploymentTemplate", "err", err, "appId", appId)
+		return err
+	}
+	appMetricsRequest := chart.AppMetricEnableDisableRequest{
+		AppId:               appId,
+		UserId:              userId,
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+	}
+	_, err = impl.chartService.AppMetricsEnableDisable(appMetricsRequest)
+	if err != nil {
+		impl.logger.Errorw("service err, AppMetricsEnableDisable in UpdateDeploymentTemplate", "err", err, "appId", appId)
+		return err
+	}
+	return nil
+}
+
+func (impl *AppDefinitionServiceImpl) DeleteGlobalConfigMap(appId int, name string, userId int32) error {
+	_, err := impl.configMapService.CMGlobalDeleteByAppId(name, appId, userId)
+	return err
+}
+
+func (impl *AppDefinitionServiceImpl) DeleteGlobalSecret(appId int, name string, userId int32) error {
+	_, err := impl.configMapService.CSGlobalDeleteByAppId(name, appId, userId)
+	return err
+}
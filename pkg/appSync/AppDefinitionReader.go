@@ -0,0 +1,92 @@
+package appSync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// readAppDefinitions reads every yaml or json file under dir, one app definition per file. Files which cannot be
+// parsed are returned with their parse error so that they are reported instead of failing the whole sync
+func readAppDefinitions(dir string) ([]*appDefinitionFile, error) {
+	var files []*appDefinitionFile
+	appFiles := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		extension := strings.ToLower(filepath.Ext(path))
+		if extension != ".yaml" && extension != ".yml" && extension != ".json" {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file := parseAppDefinition(relativePath, content)
+		if file.parseError == nil {
+			appName := file.definition.Metadata.AppName
+			if existingPath, exists := appFiles[appName]; exists {
+				file.parseError = fmt.Errorf("app %s is already defined in %s", appName, existingPath)
+			} else {
+				appFiles[appName] = relativePath
+			}
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].filePath < files[j].filePath
+	})
+	return files, nil
+}
+
+func parseAppDefinition(filePath string, content []byte) *appDefinitionFile {
+	file := &appDefinitionFile{filePath: filePath}
+	definitionJson, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		file.parseError = err
+		return file
+	}
+	definition := &AppDefinition{}
+	if err = json.Unmarshal(definitionJson, definition); err != nil {
+		file.parseError = err
+		return file
+	}
+	if definition.ApiVersion != APP_DEFINITION_API_VERSION || definition.Kind != APP_DEFINITION_KIND {
+		file.parseError = fmt.Errorf("expected apiVersion %s and kind %s", APP_DEFINITION_API_VERSION, APP_DEFINITION_KIND)
+		return file
+	}
+	if definition.Spec == nil || definition.Spec.Metadata == nil || len(definition.Spec.Metadata.AppName) == 0 {
+		file.parseError = fmt.Errorf("spec.metadata.appName is required")
+		return file
+	}
+	rawDefinition := struct {
+		Spec map[string]interface{} `json:"spec"`
+	}{}
+	if err = json.Unmarshal(definitionJson, &rawDefinition); err != nil {
+		file.parseError = err
+		return file
+	}
+	file.definition = definition.Spec
+	file.rawSpec = rawDefinition.Spec
+	return file
+}
@@ -0,0 +1,195 @@
+package appSync
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+// AppSyncSource is a git repository of app definitions which devtron reconciles its apps with, files are read
+// from Path on Branch using the credentials of the git provider
+type AppSyncSource struct {
+	tableName        struct{}  `sql:"app_sync_source" pg:",discard_unknown_columns"`
+	Id               int       `sql:"id,pk"`
+	Name             string    `sql:"name,notnull"`
+	GitRepoUrl       string    `sql:"git_repo_url,notnull"`
+	Branch           string    `sql:"branch,notnull"`
+	Path             string    `sql:"path"`
+	GitProviderId    int       `sql:"git_provider_id,notnull"`
+	AutoSync         bool      `sql:"auto_sync,notnull"`
+	Prune            bool      `sql:"prune,notnull"`
+	Active           bool      `sql:"active,notnull"`
+	LastSyncedCommit string    `sql:"last_synced_commit"`
+	LastSyncedOn     time.Time `sql:"last_synced_on"`
+	LastSyncStatus   string    `sql:"last_sync_status"`
+	LastSyncMessage  string    `sql:"last_sync_message"`
+	sql.AuditLog
+}
+
+// AppSyncManagedApp marks an app as managed from git, such apps can only be changed through their sync source
+type AppSyncManagedApp struct {
+	tableName    struct{} `sql:"app_sync_managed_app" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	SyncSourceId int      `sql:"sync_source_id,notnull"`
+	AppId        int      `sql:"app_id,notnull"`
+	FilePath     string   `sql:"file_path,notnull"`
+	Active       bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// AppSyncResourceStatus is the outcome of the last sync, or dry run, of a resource of a sync source
+type AppSyncResourceStatus struct {
+	tableName    struct{} `sql:"app_sync_resource_status" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	SyncSourceId int      `sql:"sync_source_id,notnull"`
+	AppName      string   `sql:"app_name,notnull"`
+	AppId        int      `sql:"app_id"`
+	FilePath     string   `sql:"file_path"`
+	Resource     string   `sql:"resource,notnull"`
+	ResourceName string   `sql:"resource_name"`
+	Action       string   `sql:"action,notnull"`
+	Status       string   `sql:"status,notnull"`
+	Message      string   `sql:"message"`
+	Diff         string   `sql:"diff"`
+	CommitHash   string   `sql:"commit_hash"`
+	sql.AuditLog
+}
+
+type AppSyncRepository interface {
+	GetConnection() *pg.DB
+	SaveSource(source *AppSyncSource) error
+	UpdateSource(source *AppSyncSource) error
+	FindActiveSourceById(id int) (*AppSyncSource, error)
+	FindActiveSourceByName(name string) (*AppSyncSource, error)
+	FindAllActiveSources() ([]*AppSyncSource, error)
+	// ClaimSourceForSync marks the source as being synced, false is returned while a sync of it started within
+	// timeout runs, a claim older than that is taken over so that a sync lost with its replica does not block the source
+	ClaimSourceForSync(id int, timeout time.Duration) (bool, error)
+	ReleaseSourceForSync(id int) error
+
+	SaveManagedApp(managedApp *AppSyncManagedApp, tx *pg.Tx) error
+	UpdateManagedApp(managedApp *AppSyncManagedApp, tx *pg.Tx) error
+	FindActiveManagedAppByAppId(appId int) (*AppSyncManagedApp, error)
+	FindActiveManagedAppsBySourceId(syncSourceId int) ([]*AppSyncManagedApp, error)
+
+	// ReplaceResourceStatuses replaces the statuses of the previous sync of the source with the given ones
+	ReplaceResourceStatuses(syncSourceId int, statuses []*AppSyncResourceStatus, tx *pg.Tx) error
+	FindResourceStatusesBySourceId(syncSourceId int) ([]*AppSyncResourceStatus, error)
+}
+
+type AppSyncRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewAppSyncRepositoryImpl(dbConnection *pg.DB) *AppSyncRepositoryImpl {
+	return &AppSyncRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl AppSyncRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl AppSyncRepositoryImpl) SaveSource(source *AppSyncSource) error {
+	return impl.dbConnection.Insert(source)
+}
+
+func (impl AppSyncRepositoryImpl) UpdateSource(source *AppSyncSource) error {
+	return impl.dbConnection.Update(source)
+}
+
+func (impl AppSyncRepositoryImpl) FindActiveSourceById(id int) (*AppSyncSource, error) {
+	source := &AppSyncSource{}
+	err := impl.dbConnection.Model(source).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl AppSyncRepositoryImpl) FindActiveSourceByName(name string) (*AppSyncSource, error) {
+	source := &AppSyncSource{}
+	err := impl.dbConnection.Model(source).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl AppSyncRepositoryImpl) FindAllActiveSources() ([]*AppSyncSource, error) {
+	var sources []*AppSyncSource
+	err := impl.dbConnection.Model(&sources).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	return sources, err
+}
+
+func (impl AppSyncRepositoryImpl) ClaimSourceForSync(id int, timeout time.Duration) (bool, error) {
+	// db clock is used so that clock skew between replicas does not matter
+	res, err := impl.dbConnection.Model((*AppSyncSource)(nil)).
+		Set("sync_started_on = now()").
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Where("sync_started_on IS NULL OR sync_started_on < now() - ? * interval '1 second'", int(timeout.Seconds())).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (impl AppSyncRepositoryImpl) ReleaseSourceForSync(id int) error {
+	_, err := impl.dbConnection.Model((*AppSyncSource)(nil)).
+		Set("sync_started_on = NULL").
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (impl AppSyncRepositoryImpl) SaveManagedApp(managedApp *AppSyncManagedApp, tx *pg.Tx) error {
+	return tx.Insert(managedApp)
+}
+
+func (impl AppSyncRepositoryImpl) UpdateManagedApp(managedApp *AppSyncManagedApp, tx *pg.Tx) error {
+	return tx.Update(managedApp)
+}
+
+func (impl AppSyncRepositoryImpl) FindActiveManagedAppByAppId(appId int) (*AppSyncManagedApp, error) {
+	managedApp := &AppSyncManagedApp{}
+	err := impl.dbConnection.Model(managedApp).
+		Where("app_id = ?", appId).
+		Where("active = ?", true).
+		Select()
+	return managedApp, err
+}
+
+func (impl AppSyncRepositoryImpl) FindActiveManagedAppsBySourceId(syncSourceId int) ([]*AppSyncManagedApp, error) {
+	var managedApps []*AppSyncManagedApp
+	err := impl.dbConnection.Model(&managedApps).
+		Where("sync_source_id = ?", syncSourceId).
+		Where("active = ?", true).
+		Select()
+	return managedApps, err
+}
+
+func (impl AppSyncRepositoryImpl) ReplaceResourceStatuses(syncSourceId int, statuses []*AppSyncResourceStatus, tx *pg.Tx) error {
+	_, err := tx.Model((*AppSyncResourceStatus)(nil)).
+		Where("sync_source_id = ?", syncSourceId).
+		Delete()
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+	return tx.Insert(&statuses)
+}
+
+func (impl AppSyncRepositoryImpl) FindResourceStatusesBySourceId(syncSourceId int) ([]*AppSyncResourceStatus, error) {
+	var statuses []*AppSyncResourceStatus
+	err := impl.dbConnection.Model(&statuses).
+		Where("sync_source_id = ?", syncSourceId).
+		Order("id").
+		Select()
+	return statuses, err
+}
@@ -0,0 +1,676 @@
+package appSync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appDefinition"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"gopkg.in/src-d/go-git.v4"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	APP_SYNC_WORKING_DIR = "/tmp/app-sync/"
+	// a sync running longer than this is taken as lost with its replica and the source can be synced again
+	APP_SYNC_CLAIM_TIMEOUT = 30 * time.Minute
+)
+
+type AppSyncService interface {
+	CreateSource(request *AppSyncSourceDto) (*AppSyncSourceDto, error)
+	UpdateSource(request *AppSyncSourceDto) (*AppSyncSourceDto, error)
+	DeleteSource(id int, userId int32) error
+	GetSource(id int) (*AppSyncSourceDto, error)
+	GetAllSources() ([]*AppSyncSourceDto, error)
+
+	// Sync reconciles the apps of the source with the head of its branch, on dry run only the plan is computed.
+	// The outcome of dry runs is recorded as well so that drift of a source shows up without applying it
+	Sync(ctx context.Context, request *SyncRequest) (*SyncResponse, error)
+	GetSyncStatus(syncSourceId int) (*SyncStatusResponse, error)
+	// SyncAllSources applies sources with auto sync and dry runs the others to refresh their drift
+	SyncAllSources()
+}
+
+type AppSyncServiceImpl struct {
+	logger                *zap.SugaredLogger
+	appSyncRepository     AppSyncRepository
+	gitProviderRepository repository.GitProviderRepository
+	appRepository         app.AppRepository
+	gitCliUtil            *util.GitCliUtil
+	appDefinitionService  appDefinition.AppDefinitionService
+	argoUserService       argo.ArgoUserService
+}
+
+func NewAppSyncServiceImpl(logger *zap.SugaredLogger, appSyncRepository AppSyncRepository,
+	gitProviderRepository repository.GitProviderRepository, appRepository app.AppRepository, gitCliUtil *util.GitCliUtil,
+	appDefinitionService appDefinition.AppDefinitionService, argoUserService argo.ArgoUserService) *AppSyncServiceImpl {
+	return &AppSyncServiceImpl{
+		logger:                logger,
+		appSyncRepository:     appSyncRepository,
+		gitProviderRepository: gitProviderRepository,
+		appRepository:         appRepository,
+		gitCliUtil:            gitCliUtil,
+		appDefinitionService:  appDefinitionService,
+		argoUserService:       argoUserService,
+	}
+}
+
+func (impl AppSyncServiceImpl) CreateSource(request *AppSyncSourceDto) (*AppSyncSourceDto, error) {
+	err := impl.validateSource(request)
+	if err != nil {
+		return nil, err
+	}
+	source := &AppSyncSource{
+		Name:          request.Name,
+		GitRepoUrl:    request.GitRepoUrl,
+		Branch:        request.Branch,
+		Path:          request.Path,
+		GitProviderId: request.GitProviderId,
+		AutoSync:      request.AutoSync,
+		Prune:         request.Prune,
+		Active:        true,
+		AuditLog:      sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+	}
+	err = impl.appSyncRepository.SaveSource(source)
+	if err != nil {
+		impl.logger.Errorw("error in saving app sync source", "name", request.Name, "err", err)
+		return nil, err
+	}
+	return toSourceDto(source), nil
+}
+
+func (impl AppSyncServiceImpl) UpdateSource(request *AppSyncSourceDto) (*AppSyncSourceDto, error) {
+	source, err := impl.findSource(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validateSource(request)
+	if err != nil {
+		return nil, err
+	}
+	source.Name = request.Name
+	source.GitRepoUrl = request.GitRepoUrl
+	source.Branch = request.Branch
+	source.Path = request.Path
+	source.GitProviderId = request.GitProviderId
+	source.AutoSync = request.AutoSync
+	source.Prune = request.Prune
+	source.UpdatedOn = time.Now()
+	source.UpdatedBy = request.UserId
+	err = impl.appSyncRepository.UpdateSource(source)
+	if err != nil {
+		impl.logger.Errorw("error in updating app sync source", "id", request.Id, "err", err)
+		return nil, err
+	}
+	return toSourceDto(source), nil
+}
+
+// DeleteSource deletes the source and releases its apps, the apps themselves are kept and can be edited again
+func (impl AppSyncServiceImpl) DeleteSource(id int, userId int32) error {
+	source, err := impl.findSource(id)
+	if err != nil {
+		return err
+	}
+	managedApps, err := impl.appSyncRepository.FindActiveManagedAppsBySourceId(id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching managed apps of app sync source", "id", id, "err", err)
+		return err
+	}
+	tx, err := impl.appSyncRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, managedApp := range managedApps {
+		managedApp.Active = false
+		managedApp.UpdatedOn = time.Now()
+		managedApp.UpdatedBy = userId
+		err = impl.appSyncRepository.UpdateManagedApp(managedApp, tx)
+		if err != nil {
+			impl.logger.Errorw("error in releasing managed app of app sync source", "id", id, "appId", managedApp.AppId, "err", err)
+			return err
+		}
+	}
+	source.Active = false
+	source.UpdatedOn = time.Now()
+	source.UpdatedBy = userId
+	_, err = tx.Model(source).WherePK().Update()
+	if err != nil {
+		impl.logger.Errorw("error in deleting app sync source", "id", id, "err", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl AppSyncServiceImpl) GetSource(id int) (*AppSyncSourceDto, error) {
+	source, err := impl.findSource(id)
+	if err != nil {
+		return nil, err
+	}
+	return toSourceDto(source), nil
+}
+
+func (impl AppSyncServiceImpl) GetAllSources() ([]*AppSyncSourceDto, error) {
+	sources, err := impl.appSyncRepository.FindAllActiveSources()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app sync sources", "err", err)
+		return nil, err
+	}
+	sourceDtos := make([]*AppSyncSourceDto, 0, len(sources))
+	for _, source := range sources {
+		sourceDtos = append(sourceDtos, toSourceDto(source))
+	}
+	return sourceDtos, nil
+}
+
+func (impl AppSyncServiceImpl) Sync(ctx context.Context, request *SyncRequest) (*SyncResponse, error) {
+	source, err := impl.findSource(request.SyncSourceId)
+	if err != nil {
+		return nil, err
+	}
+	// a source is reconciled by one sync at a time across replicas
+	claimed, err := impl.appSyncRepository.ClaimSourceForSync(source.Id, APP_SYNC_CLAIM_TIMEOUT)
+	if err != nil {
+		impl.logger.Errorw("error in claiming app sync source for sync", "id", source.Id, "err", err)
+		return nil, err
+	}
+	if !claimed {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, Code: "409",
+			InternalMessage: "a sync of the source is already running", UserMessage: "a sync of the source is already running"}
+	}
+	defer func() {
+		if err := impl.appSyncRepository.ReleaseSourceForSync(source.Id); err != nil {
+			impl.logger.Errorw("error in releasing app sync source after sync", "id", source.Id, "err", err)
+		}
+	}()
+	commitHash, files, err := impl.fetchDefinitions(source)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app definitions of app sync source", "id", source.Id, "err", err)
+		source.LastSyncStatus = string(SYNC_STATUS_FAILED)
+		source.LastSyncMessage = err.Error()
+		if errInUpdate := impl.appSyncRepository.UpdateSource(source); errInUpdate != nil {
+			impl.logger.Errorw("error in updating status of app sync source", "id", source.Id, "err", errInUpdate)
+		}
+		return nil, err
+	}
+	managedApps, err := impl.appSyncRepository.FindActiveManagedAppsBySourceId(source.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching managed apps of app sync source", "id", source.Id, "err", err)
+		return nil, err
+	}
+	items, err := impl.computePlan(ctx, source, files, managedApps)
+	if err != nil {
+		return nil, err
+	}
+	if !request.DryRun {
+		impl.applyPlan(ctx, items, request.UserId)
+	}
+	response := &SyncResponse{
+		SyncSourceId: source.Id,
+		CommitHash:   commitHash,
+		DryRun:       request.DryRun,
+		Status:       getSyncStatus(items),
+		Items:        items,
+	}
+	err = impl.recordSync(source, response, managedApps, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (impl AppSyncServiceImpl) GetSyncStatus(syncSourceId int) (*SyncStatusResponse, error) {
+	source, err := impl.findSource(syncSourceId)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := impl.appSyncRepository.FindResourceStatusesBySourceId(syncSourceId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching resource statuses of app sync source", "id", syncSourceId, "err", err)
+		return nil, err
+	}
+	items := make([]*PlanItem, 0, len(statuses))
+	for _, status := range statuses {
+		item := &PlanItem{
+			AppName:      status.AppName,
+			AppId:        status.AppId,
+			FilePath:     status.FilePath,
+			Resource:     SyncResource(status.Resource),
+			ResourceName: status.ResourceName,
+			Action:       PlanAction(status.Action),
+			Status:       SyncStatus(status.Status),
+			Message:      status.Message,
+		}
+		if len(status.Diff) > 0 {
+			if err = json.Unmarshal([]byte(status.Diff), &item.Diff); err != nil {
+				impl.logger.Errorw("error in unmarshalling diff of resource status", "id", status.Id, "err", err)
+			}
+		}
+		items = append(items, item)
+	}
+	return &SyncStatusResponse{Source: toSourceDto(source), Items: items}, nil
+}
+
+func (impl AppSyncServiceImpl) SyncAllSources() {
+	sources, err := impl.appSyncRepository.FindAllActiveSources()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app sync sources", "err", err)
+		return
+	}
+	for _, source := range sources {
+		// system triggered sync
+		request := &SyncRequest{SyncSourceId: source.Id, DryRun: !source.AutoSync, UserId: 1}
+		response, err := impl.Sync(context.Background(), request)
+		if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusConflict {
+			impl.logger.Infow("app sync source is being synced by another sync, skipping", "id", source.Id)
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in syncing app sync source", "id", source.Id, "err", err)
+			continue
+		}
+		impl.logger.Infow("app sync source synced", "id", source.Id, "dryRun", request.DryRun, "commit", response.CommitHash, "status", response.Status)
+	}
+}
+
+func (impl AppSyncServiceImpl) findSource(id int) (*AppSyncSource, error) {
+	source, err := impl.appSyncRepository.FindActiveSourceById(id)
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "app sync source not found", UserMessage: "app sync source not found"}
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching app sync source", "id", id, "err", err)
+		return nil, err
+	}
+	return source, nil
+}
+
+func (impl AppSyncServiceImpl) validateSource(request *AppSyncSourceDto) error {
+	existingSource, err := impl.appSyncRepository.FindActiveSourceByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app sync source by name", "name", request.Name, "err", err)
+		return err
+	}
+	if err == nil && existingSource.Id != request.Id {
+		return newBadRequestError(fmt.Sprintf("app sync source %s already exists", request.Name))
+	}
+	cleanPath := filepath.Clean(request.Path)
+	if filepath.IsAbs(cleanPath) || cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return newBadRequestError("path must be relative to the root of the repository")
+	}
+	gitProvider, err := impl.gitProviderRepository.FindOne(strconv.Itoa(request.GitProviderId))
+	if err == pg.ErrNoRows {
+		return newBadRequestError("git provider not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching git provider", "gitProviderId", request.GitProviderId, "err", err)
+		return err
+	}
+	if gitProvider.AuthMode == repository.AUTH_MODE_SSH {
+		return newBadRequestError("git providers with ssh auth are not supported for app sync")
+	}
+	return nil
+}
+
+// fetchDefinitions checks out the head of the branch of the source and reads the app definitions under its path
+func (impl AppSyncServiceImpl) fetchDefinitions(source *AppSyncSource) (string, []*appDefinitionFile, error) {
+	gitProvider, err := impl.gitProviderRepository.FindOne(strconv.Itoa(source.GitProviderId))
+	if err != nil {
+		return "", nil, err
+	}
+	userName, password, err := getGitCredentials(gitProvider)
+	if err != nil {
+		return "", nil, err
+	}
+	rootDir := filepath.Join(APP_SYNC_WORKING_DIR, strconv.Itoa(source.Id))
+	defer os.RemoveAll(rootDir)
+	err = impl.gitCliUtil.Init(rootDir, source.GitRepoUrl, false)
+	if err != nil {
+		return "", nil, err
+	}
+	_, errMsg, err := impl.gitCliUtil.Fetch(rootDir, userName, password)
+	if err != nil {
+		return "", nil, fmt.Errorf("error in fetching %s: %s", source.GitRepoUrl, errMsg)
+	}
+	_, errMsg, err = impl.gitCliUtil.Checkout(rootDir, "origin/"+source.Branch)
+	if err != nil {
+		return "", nil, fmt.Errorf("error in checking out branch %s: %s", source.Branch, errMsg)
+	}
+	repo, err := git.PlainOpen(rootDir)
+	if err != nil {
+		return "", nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", nil, err
+	}
+	files, err := readAppDefinitions(filepath.Join(rootDir, filepath.Clean(source.Path)))
+	if err != nil {
+		return "", nil, err
+	}
+	return head.Hash().String(), files, nil
+}
+
+func (impl AppSyncServiceImpl) computePlan(ctx context.Context, source *AppSyncSource, files []*appDefinitionFile, managedApps []*AppSyncManagedApp) ([]*PlanItem, error) {
+	var items []*PlanItem
+	definedAppIds := make(map[int]bool)
+	for _, file := range files {
+		if file.parseError != nil {
+			items = append(items, &PlanItem{FilePath: file.filePath, Resource: SYNC_RESOURCE_APP, Action: PLAN_ACTION_MANUAL,
+				Status: SYNC_STATUS_FAILED, Message: file.parseError.Error()})
+			continue
+		}
+		appName := file.definition.Metadata.AppName
+		existingApp, err := impl.appRepository.FindActiveByName(appName)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching app by name", "appName", appName, "err", err)
+			return nil, err
+		}
+		if err == pg.ErrNoRows {
+			appItems, _ := computeAppPlan(file, 0, nil, source.Prune)
+			items = append(items, appItems...)
+			continue
+		}
+		definedAppIds[existingApp.Id] = true
+		managedApp, err := impl.appSyncRepository.FindActiveManagedAppByAppId(existingApp.Id)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching git managed app", "appId", existingApp.Id, "err", err)
+			return nil, err
+		}
+		if err == nil && managedApp.SyncSourceId != source.Id {
+			items = append(items, &PlanItem{AppName: appName, AppId: existingApp.Id, FilePath: file.filePath, Resource: SYNC_RESOURCE_APP,
+				Action: PLAN_ACTION_MANUAL, Status: SYNC_STATUS_FAILED, Message: "app is managed by another sync source"})
+			continue
+		}
+		// sync sources are managed by super admins, so rbac of the caller is not checked
+		current, err, _ := impl.appDefinitionService.BuildAppDetail(ctx, existingApp.Id, nil)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app definition", "appId", existingApp.Id, "err", err)
+			items = append(items, &PlanItem{AppName: appName, AppId: existingApp.Id, FilePath: file.filePath, Resource: SYNC_RESOURCE_APP,
+				Action: PLAN_ACTION_MANUAL, Status: SYNC_STATUS_FAILED, Message: err.Error()})
+			continue
+		}
+		appItems, err := computeAppPlan(file, existingApp.Id, current, source.Prune)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, appItems...)
+	}
+	for _, managedApp := range managedApps {
+		if definedAppIds[managedApp.AppId] {
+			continue
+		}
+		existingApp, err := impl.appRepository.FindById(managedApp.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching git managed app", "appId", managedApp.AppId, "err", err)
+			return nil, err
+		}
+		if !existingApp.Active {
+			// deleted outside of sync, released when the sync is recorded
+			continue
+		}
+		items = append(items, computeRemovedAppPlan(existingApp.AppName, existingApp.Id, managedApp.FilePath, source.Prune))
+	}
+	return items, nil
+}
+
+// applyPlan applies the items in plan order, a failed item is recorded on the item and does not stop the others
+func (impl AppSyncServiceImpl) applyPlan(ctx context.Context, items []*PlanItem, userId int32) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		for _, item := range items {
+			if item.Status == SYNC_STATUS_OUT_OF_SYNC && item.Action != PLAN_ACTION_MANUAL {
+				item.Status, item.Message = SYNC_STATUS_FAILED, err.Error()
+			}
+		}
+		return
+	}
+	ctx = context.WithValue(ctx, "token", acdToken)
+	for _, item := range items {
+		if item.Action == PLAN_ACTION_NONE || item.Action == PLAN_ACTION_MANUAL || item.Status == SYNC_STATUS_FAILED {
+			continue
+		}
+		err := impl.applyItem(ctx, item, userId)
+		if err != nil {
+			impl.logger.Errorw("error in applying app sync item", "appName", item.AppName, "resource", item.Resource,
+				"resourceName", item.ResourceName, "action", item.Action, "err", err)
+			item.Status, item.Message = SYNC_STATUS_FAILED, err.Error()
+			continue
+		}
+		item.Status, item.Message = SYNC_STATUS_SYNCED, ""
+	}
+}
+
+func (impl AppSyncServiceImpl) applyItem(ctx context.Context, item *PlanItem, userId int32) error {
+	definition := item.definition
+	switch item.Resource {
+	case SYNC_RESOURCE_APP:
+		if item.Action == PLAN_ACTION_DELETE {
+			return impl.appDefinitionService.DeleteApp(ctx, item.AppId, userId)
+		}
+		appId, err, _ := impl.appDefinitionService.CreateApp(ctx, definition, userId, nil)
+		item.AppId = appId
+		return err
+	case SYNC_RESOURCE_GIT_MATERIAL:
+		for _, gitMaterial := range definition.GitMaterials {
+			if gitMaterial.CheckoutPath == item.ResourceName {
+				err, _ := impl.appDefinitionService.CreateGitMaterials(item.AppId, []*appBean.GitMaterial{gitMaterial}, userId)
+				return err
+			}
+		}
+	case SYNC_RESOURCE_DEPLOYMENT_TEMPLATE:
+		return impl.appDefinitionService.UpdateDeploymentTemplate(ctx, item.AppId, definition.GlobalDeploymentTemplate, userId)
+	case SYNC_RESOURCE_CONFIG_MAP:
+		if item.Action == PLAN_ACTION_DELETE {
+			return impl.appDefinitionService.DeleteGlobalConfigMap(item.AppId, item.ResourceName, userId)
+		}
+		for _, configMap := range definition.GlobalConfigMaps {
+			if configMap.Name == item.ResourceName {
+				err, _ := impl.appDefinitionService.CreateGlobalConfigMaps(item.AppId, userId, []*appBean.ConfigMap{configMap})
+				return err
+			}
+		}
+	case SYNC_RESOURCE_SECRET:
+		if item.Action == PLAN_ACTION_DELETE {
+			return impl.appDefinitionService.DeleteGlobalSecret(item.AppId, item.ResourceName, userId)
+		}
+		for _, secret := range definition.GlobalSecrets {
+			if secret.Name == item.ResourceName {
+				err, _ := impl.appDefinitionService.CreateGlobalSecrets(item.AppId, userId, []*appBean.Secret{secret})
+				return err
+			}
+		}
+	case SYNC_RESOURCE_WORKFLOW:
+		for _, workflow := range definition.AppWorkflows {
+			if workflow.Name == item.ResourceName {
+				err, _ := impl.appDefinitionService.CreateWorkflows(ctx, item.AppId, userId, []*appBean.AppWorkflow{workflow}, nil, item.AppName)
+				return err
+			}
+		}
+	case SYNC_RESOURCE_ENV_OVERRIDE:
+		environmentOverride := map[string]*appBean.EnvironmentOverride{item.ResourceName: definition.EnvironmentOverrides[item.ResourceName]}
+		err, _ := impl.appDefinitionService.CreateEnvOverrides(ctx, item.AppId, userId, environmentOverride, nil)
+		return err
+	}
+	return errors.New("nothing to apply for resource")
+}
+
+// recordSync saves the statuses of the sync and, when applied, the apps managed by the source
+func (impl AppSyncServiceImpl) recordSync(source *AppSyncSource, response *SyncResponse, managedApps []*AppSyncManagedApp, userId int32) error {
+	statuses := make([]*AppSyncResourceStatus, 0, len(response.Items))
+	for _, item := range response.Items {
+		status := &AppSyncResourceStatus{
+			SyncSourceId: source.Id,
+			AppName:      item.AppName,
+			AppId:        item.AppId,
+			FilePath:     item.FilePath,
+			Resource:     string(item.Resource),
+			ResourceName: item.ResourceName,
+			Action:       string(item.Action),
+			Status:       string(item.Status),
+			Message:      item.Message,
+			CommitHash:   response.CommitHash,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		if len(item.Diff) > 0 {
+			diff, err := json.Marshal(item.Diff)
+			if err != nil {
+				return err
+			}
+			status.Diff = string(diff)
+		}
+		statuses = append(statuses, status)
+	}
+	tx, err := impl.appSyncRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if !response.DryRun {
+		err = impl.recordManagedApps(source, response.Items, managedApps, userId, tx)
+		if err != nil {
+			return err
+		}
+		source.LastSyncedCommit = response.CommitHash
+		source.LastSyncedOn = time.Now()
+	}
+	err = impl.appSyncRepository.ReplaceResourceStatuses(source.Id, statuses, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving resource statuses of app sync source", "id", source.Id, "err", err)
+		return err
+	}
+	source.LastSyncStatus = string(response.Status)
+	source.LastSyncMessage = ""
+	_, err = tx.Model(source).WherePK().Update()
+	if err != nil {
+		impl.logger.Errorw("error in updating app sync source", "id", source.Id, "err", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl AppSyncServiceImpl) recordManagedApps(source *AppSyncSource, items []*PlanItem, managedApps []*AppSyncManagedApp, userId int32, tx *pg.Tx) error {
+	managedAppsById := make(map[int]*AppSyncManagedApp)
+	for _, managedApp := range managedApps {
+		managedAppsById[managedApp.AppId] = managedApp
+	}
+	definedAppIds := make(map[int]bool)
+	for _, item := range items {
+		if item.Resource != SYNC_RESOURCE_APP || item.AppId == 0 {
+			continue
+		}
+		if item.Action == PLAN_ACTION_DELETE && item.Status == SYNC_STATUS_SYNCED {
+			// deleted by sync, released below
+			continue
+		}
+		definedAppIds[item.AppId] = true
+		if item.definition == nil {
+			// removed from git but kept, or its definition could not be compared
+			continue
+		}
+		managedApp, isManaged := managedAppsById[item.AppId]
+		if isManaged && managedApp.FilePath == item.FilePath {
+			continue
+		}
+		if isManaged {
+			managedApp.FilePath = item.FilePath
+			managedApp.UpdatedOn = time.Now()
+			managedApp.UpdatedBy = userId
+			if err := impl.appSyncRepository.UpdateManagedApp(managedApp, tx); err != nil {
+				impl.logger.Errorw("error in updating git managed app", "appId", item.AppId, "err", err)
+				return err
+			}
+			continue
+		}
+		managedApp = &AppSyncManagedApp{
+			SyncSourceId: source.Id,
+			AppId:        item.AppId,
+			FilePath:     item.FilePath,
+			Active:       true,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		if err := impl.appSyncRepository.SaveManagedApp(managedApp, tx); err != nil {
+			impl.logger.Errorw("error in saving git managed app", "appId", item.AppId, "err", err)
+			return err
+		}
+	}
+	// release apps which were deleted, by sync or outside of it
+	for _, managedApp := range managedApps {
+		if definedAppIds[managedApp.AppId] {
+			continue
+		}
+		managedApp.Active = false
+		managedApp.UpdatedOn = time.Now()
+		managedApp.UpdatedBy = userId
+		if err := impl.appSyncRepository.UpdateManagedApp(managedApp, tx); err != nil {
+			impl.logger.Errorw("error in releasing git managed app", "appId", managedApp.AppId, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func getGitCredentials(gitProvider repository.GitProvider) (string, string, error) {
+	switch gitProvider.AuthMode {
+	case repository.AUTH_MODE_USERNAME_PASSWORD:
+		return gitProvider.UserName, gitProvider.Password, nil
+	case repository.AUTH_MODE_ACCESS_TOKEN:
+		userName := gitProvider.UserName
+		if userName == "" {
+			userName = "devtron-boat"
+		}
+		return userName, gitProvider.AccessToken, nil
+	case repository.AUTH_MODE_SSH:
+		return "", "", errors.New("git providers with ssh auth are not supported for app sync")
+	}
+	return "", "", nil
+}
+
+func getSyncStatus(items []*PlanItem) SyncStatus {
+	status := SYNC_STATUS_SYNCED
+	for _, item := range items {
+		if item.Status == SYNC_STATUS_FAILED {
+			return SYNC_STATUS_FAILED
+		}
+		if item.Status == SYNC_STATUS_OUT_OF_SYNC {
+			status = SYNC_STATUS_OUT_OF_SYNC
+		}
+	}
+	return status
+}
+
+func toSourceDto(source *AppSyncSource) *AppSyncSourceDto {
+	sourceDto := &AppSyncSourceDto{
+		Id:               source.Id,
+		Name:             source.Name,
+		GitRepoUrl:       source.GitRepoUrl,
+		Branch:           source.Branch,
+		Path:             source.Path,
+		GitProviderId:    source.GitProviderId,
+		AutoSync:         source.AutoSync,
+		Prune:            source.Prune,
+		LastSyncedCommit: source.LastSyncedCommit,
+		LastSyncStatus:   SyncStatus(source.LastSyncStatus),
+		LastSyncMessage:  source.LastSyncMessage,
+	}
+	if !source.LastSyncedOn.IsZero() {
+		lastSyncedOn := source.LastSyncedOn
+		sourceDto.LastSyncedOn = &lastSyncedOn
+	}
+	return sourceDto
+}
+
+func newBadRequestError(message string) error {
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: message, UserMessage: message}
+}
@@ -0,0 +1,45 @@
+package appSync
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// GitManagedAppService guards apps managed from git against changes made outside of their sync source
+type GitManagedAppService interface {
+	// CheckAppEditable returns a conflict error when the app is managed from git
+	CheckAppEditable(appId int) error
+}
+
+type GitManagedAppServiceImpl struct {
+	logger            *zap.SugaredLogger
+	appSyncRepository AppSyncRepository
+}
+
+func NewGitManagedAppServiceImpl(logger *zap.SugaredLogger, appSyncRepository AppSyncRepository) *GitManagedAppServiceImpl {
+	return &GitManagedAppServiceImpl{
+		logger:            logger,
+		appSyncRepository: appSyncRepository,
+	}
+}
+
+func (impl GitManagedAppServiceImpl) CheckAppEditable(appId int) error {
+	managedApp, err := impl.appSyncRepository.FindActiveManagedAppByAppId(appId)
+	if err == pg.ErrNoRows {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching git managed app", "appId", appId, "err", err)
+		return err
+	}
+	source, err := impl.appSyncRepository.FindActiveSourceById(managedApp.SyncSourceId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching sync source of git managed app", "appId", appId, "syncSourceId", managedApp.SyncSourceId, "err", err)
+		return err
+	}
+	message := fmt.Sprintf("app is managed from git by sync source %s (%s), change %s on branch %s instead",
+		source.Name, source.GitRepoUrl, managedApp.FilePath, source.Branch)
+	return &util.ApiError{HttpStatusCode: http.StatusConflict, Code: "409", InternalMessage: message, UserMessage: message}
+}
@@ -0,0 +1,168 @@
+package appSync
+
+import (
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/drift"
+	"reflect"
+	"sort"
+)
+
+// computeAppPlan compares the definition of an app in git with the app in devtron, current is nil when the app does
+// not exist yet. Only the sections present in git are compared; a list section present in git owns the whole list,
+// so entries missing from it are deleted on prune when sync knows how to delete them.
+func computeAppPlan(file *appDefinitionFile, appId int, current *appBean.AppDetail, prune bool) ([]*PlanItem, error) {
+	appName := file.definition.Metadata.AppName
+	newItem := func(resource SyncResource, resourceName string, action PlanAction, message string) *PlanItem {
+		return &PlanItem{AppName: appName, AppId: appId, FilePath: file.filePath, Resource: resource, ResourceName: resourceName,
+			Action: action, Status: getPlannedStatus(action), Message: message, definition: file.definition}
+	}
+	if current == nil {
+		return []*PlanItem{newItem(SYNC_RESOURCE_APP, "", PLAN_ACTION_CREATE, "app will be created")}, nil
+	}
+	currentSpec, err := toRawSpec(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredSpec := file.rawSpec
+	items := []*PlanItem{newItem(SYNC_RESOURCE_APP, "", PLAN_ACTION_NONE, "")}
+
+	compare := func(resource SyncResource, resourceName string, desired, current interface{}, driftAction PlanAction, message string) {
+		diff := getDiff(desired, current, resource == SYNC_RESOURCE_SECRET)
+		if len(diff) == 0 {
+			items = append(items, newItem(resource, resourceName, PLAN_ACTION_NONE, ""))
+			return
+		}
+		item := newItem(resource, resourceName, driftAction, message)
+		item.Diff = diff
+		items = append(items, item)
+	}
+	compareList := func(resource SyncResource, section string, key string, missingAction PlanAction, driftAction PlanAction, driftMessage string, deletable bool) {
+		desiredList, ok := desiredSpec[section].([]interface{})
+		if !ok {
+			return
+		}
+		currentEntries, currentKeys := indexByKey(currentSpec[section], key)
+		desiredEntries, desiredKeys := indexByKey(desiredList, key)
+		for _, name := range desiredKeys {
+			currentEntry, exists := currentEntries[name]
+			if !exists {
+				items = append(items, newItem(resource, name, missingAction, getMissingMessage(missingAction)))
+				continue
+			}
+			compare(resource, name, desiredEntries[name], currentEntry, driftAction, driftMessage)
+		}
+		for _, name := range currentKeys {
+			if _, exists := desiredEntries[name]; exists {
+				continue
+			}
+			if deletable && prune {
+				items = append(items, newItem(resource, name, PLAN_ACTION_DELETE, "not present in git, will be deleted"))
+			} else {
+				items = append(items, newItem(resource, name, PLAN_ACTION_MANUAL, "present in devtron but not in git"))
+			}
+		}
+	}
+
+	if desired, ok := desiredSpec["metadata"]; ok {
+		compare(SYNC_RESOURCE_METADATA, "", desired, currentSpec["metadata"], PLAN_ACTION_MANUAL, "metadata of an existing app is not updated by sync")
+	}
+	compareList(SYNC_RESOURCE_GIT_MATERIAL, "gitMaterials", "checkoutPath", PLAN_ACTION_CREATE, PLAN_ACTION_MANUAL,
+		"existing git materials are not updated by sync", false)
+	if desired, ok := desiredSpec["dockerConfig"]; ok && desired != nil {
+		compare(SYNC_RESOURCE_DOCKER_CONFIG, "", desired, currentSpec["dockerConfig"], PLAN_ACTION_MANUAL, "build configuration is not updated by sync")
+	}
+	if desired, ok := desiredSpec["globalDeploymentTemplate"]; ok && desired != nil {
+		action, message := PLAN_ACTION_UPDATE, "deployment template will be updated"
+		if current.GlobalDeploymentTemplate == nil || file.definition.GlobalDeploymentTemplate.ChartRefId != current.GlobalDeploymentTemplate.ChartRefId {
+			action, message = PLAN_ACTION_MANUAL, "changing the chart of the deployment template is not supported by sync"
+		}
+		compare(SYNC_RESOURCE_DEPLOYMENT_TEMPLATE, "", desired, currentSpec["globalDeploymentTemplate"], action, message)
+	}
+	compareList(SYNC_RESOURCE_CONFIG_MAP, "globalConfigMaps", "name", PLAN_ACTION_CREATE, PLAN_ACTION_UPDATE, "config map will be updated", true)
+	compareList(SYNC_RESOURCE_SECRET, "globalSecrets", "name", PLAN_ACTION_CREATE, PLAN_ACTION_UPDATE, "secret will be updated", true)
+	compareList(SYNC_RESOURCE_WORKFLOW, "workflows", "name", PLAN_ACTION_CREATE, PLAN_ACTION_MANUAL,
+		"existing workflows and their pipelines are not updated by sync", false)
+
+	if desiredOverrides, ok := desiredSpec["environmentOverride"].(map[string]interface{}); ok {
+		currentOverrides, _ := currentSpec["environmentOverride"].(map[string]interface{})
+		for _, envName := range sortedKeys(desiredOverrides) {
+			currentOverride, exists := currentOverrides[envName]
+			if !exists {
+				items = append(items, newItem(SYNC_RESOURCE_ENV_OVERRIDE, envName, PLAN_ACTION_UPDATE, "environment override will be created"))
+				continue
+			}
+			compare(SYNC_RESOURCE_ENV_OVERRIDE, envName, desiredOverrides[envName], currentOverride, PLAN_ACTION_UPDATE, "environment override will be updated")
+		}
+	}
+	return items, nil
+}
+
+// computeRemovedAppPlan plans a git managed app whose definition is no longer in the repository
+func computeRemovedAppPlan(appName string, appId int, filePath string, prune bool) *PlanItem {
+	action, message := PLAN_ACTION_MANUAL, "app definition removed from git, enable prune to delete the app"
+	if prune {
+		action, message = PLAN_ACTION_DELETE, "app definition removed from git, app will be deleted"
+	}
+	return &PlanItem{AppName: appName, AppId: appId, FilePath: filePath, Resource: SYNC_RESOURCE_APP, Action: action,
+		Status: getPlannedStatus(action), Message: message}
+}
+
+func getPlannedStatus(action PlanAction) SyncStatus {
+	if action == PLAN_ACTION_NONE {
+		return SYNC_STATUS_SYNCED
+	}
+	return SYNC_STATUS_OUT_OF_SYNC
+}
+
+func getMissingMessage(action PlanAction) string {
+	if action == PLAN_ACTION_CREATE {
+		return "not present in devtron, will be created"
+	}
+	return "not present in devtron"
+}
+
+// getDiff returns the patch from current to desired over the fields set in desired, values of secrets are dropped
+// from the patch so that secret data never leaves devtron through sync status
+func getDiff(desired interface{}, current interface{}, maskValues bool) []*drift.JsonPatchOperation {
+	projected := drift.ProjectLiveState(desired, current)
+	if reflect.DeepEqual(desired, projected) {
+		return nil
+	}
+	diff := drift.CreateJsonPatch(projected, desired)
+	if maskValues {
+		for _, operation := range diff {
+			operation.Value = nil
+		}
+	}
+	return diff
+}
+
+// indexByKey indexes a list of json objects by the string value of key, preserving the order of the list
+func indexByKey(list interface{}, key string) (map[string]interface{}, []string) {
+	entries := make(map[string]interface{})
+	var keys []string
+	values, _ := list.([]interface{})
+	for _, value := range values {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := fmt.Sprint(entry[key])
+		if _, exists := entries[name]; exists {
+			continue
+		}
+		entries[name] = entry
+		keys = append(keys, name)
+	}
+	return entries, keys
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+package appSync
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testAppDefinition = `
+apiVersion: devtron.ai/v1beta1
+kind: Application
+spec:
+  metadata:
+    appName: payments
+    projectName: finance
+  globalDeploymentTemplate:
+    chartRefId: 10
+    template:
+      replicaCount: 2
+  globalConfigMaps:
+  - name: app-config
+    data:
+      LOG_LEVEL: debug
+  globalSecrets:
+  - name: app-secret
+    data:
+      PASSWORD: bmV3
+  workflows:
+  - name: build-deploy
+`
+
+func TestParseAppDefinition(t *testing.T) {
+	file := parseAppDefinition("apps/payments.yaml", []byte(testAppDefinition))
+	assert.Nil(t, file.parseError)
+	assert.Equal(t, "payments", file.definition.Metadata.AppName)
+	assert.Equal(t, 10, file.definition.GlobalDeploymentTemplate.ChartRefId)
+	assert.Contains(t, file.rawSpec, "globalConfigMaps")
+	assert.NotContains(t, file.rawSpec, "gitMaterials")
+
+	file = parseAppDefinition("apps/other.yaml", []byte("apiVersion: v1\nkind: ConfigMap\n"))
+	assert.NotNil(t, file.parseError)
+	file = parseAppDefinition("apps/invalid.yaml", []byte("apiVersion: devtron.ai/v1beta1\nkind: Application\nspec: {}\n"))
+	assert.NotNil(t, file.parseError)
+}
+
+func TestReadAppDefinitions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "app-sync")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "team", ".git"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "team", "payments.yaml"), []byte(testAppDefinition), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "payments-copy.yml"), []byte(testAppDefinition), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "team", ".git", "config.yaml"), []byte(testAppDefinition), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# apps"), 0644))
+
+	files, err := readAppDefinitions(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "payments-copy.yml", files[0].filePath)
+	assert.Nil(t, files[0].parseError)
+	// the same app defined twice is reported on the second file read
+	assert.Equal(t, filepath.Join("team", "payments.yaml"), files[1].filePath)
+	assert.NotNil(t, files[1].parseError)
+}
+
+func TestComputeAppPlan(t *testing.T) {
+	file := parseAppDefinition("payments.yaml", []byte(testAppDefinition))
+	assert.Nil(t, file.parseError)
+
+	items, err := computeAppPlan(file, 0, nil, false)
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, PLAN_ACTION_CREATE, items[0].Action)
+	assert.Equal(t, SYNC_STATUS_OUT_OF_SYNC, items[0].Status)
+
+	current := &appBean.AppDetail{
+		Metadata: &appBean.AppMetadata{AppName: "payments", ProjectName: "finance"},
+		GitMaterials: []*appBean.GitMaterial{
+			{GitProviderUrl: "github.com", GitRepoUrl: "https://github.com/org/payments.git", CheckoutPath: "./"},
+		},
+		GlobalDeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 10,
+			Template: map[string]interface{}{"replicaCount": 1, "image": map[string]interface{}{"pullPolicy": "Always"}}},
+		GlobalConfigMaps: []*appBean.ConfigMap{
+			{Name: "app-config", UsageType: "environment", Data: map[string]interface{}{"LOG_LEVEL": "debug"}},
+			{Name: "legacy-config", Data: map[string]interface{}{}},
+		},
+		GlobalSecrets: []*appBean.Secret{
+			{Name: "app-secret", Data: map[string]interface{}{"PASSWORD": "b2xk"}},
+		},
+		AppWorkflows: []*appBean.AppWorkflow{{Name: "nightly"}},
+	}
+	items, err = computeAppPlan(file, 7, current, true)
+	assert.Nil(t, err)
+	actions := make(map[string]*PlanItem)
+	for _, item := range items {
+		assert.Equal(t, 7, item.AppId)
+		actions[string(item.Resource)+"/"+item.ResourceName] = item
+	}
+	assert.Len(t, items, 8)
+	assert.Equal(t, PLAN_ACTION_NONE, actions["APP/"].Action)
+	assert.Equal(t, PLAN_ACTION_NONE, actions["METADATA/"].Action)
+	// git materials are not part of the definition, so they are not compared
+	assert.NotContains(t, actions, "GIT_MATERIAL/./")
+
+	template := actions["DEPLOYMENT_TEMPLATE/"]
+	assert.Equal(t, PLAN_ACTION_UPDATE, template.Action)
+	assert.Len(t, template.Diff, 1)
+	assert.Equal(t, "/template/replicaCount", template.Diff[0].Path)
+
+	assert.Equal(t, PLAN_ACTION_NONE, actions["CONFIG_MAP/app-config"].Action)
+	assert.Equal(t, PLAN_ACTION_DELETE, actions["CONFIG_MAP/legacy-config"].Action)
+
+	secret := actions["SECRET/app-secret"]
+	assert.Equal(t, PLAN_ACTION_UPDATE, secret.Action)
+	assert.Len(t, secret.Diff, 1)
+	assert.Nil(t, secret.Diff[0].Value)
+
+	assert.Equal(t, PLAN_ACTION_CREATE, actions["WORKFLOW/build-deploy"].Action)
+	// workflows are never deleted by sync
+	assert.Equal(t, PLAN_ACTION_MANUAL, actions["WORKFLOW/nightly"].Action)
+
+	items, err = computeAppPlan(file, 7, current, false)
+	assert.Nil(t, err)
+	for _, item := range items {
+		if item.ResourceName == "legacy-config" {
+			assert.Equal(t, PLAN_ACTION_MANUAL, item.Action)
+		}
+	}
+
+	current.GlobalDeploymentTemplate.ChartRefId = 11
+	items, err = computeAppPlan(file, 7, current, true)
+	assert.Nil(t, err)
+	for _, item := range items {
+		if item.Resource == SYNC_RESOURCE_DEPLOYMENT_TEMPLATE {
+			assert.Equal(t, PLAN_ACTION_MANUAL, item.Action)
+		}
+	}
+}
+
+func TestComputeRemovedAppPlan(t *testing.T) {
+	item := computeRemovedAppPlan("payments", 7, "payments.yaml", false)
+	assert.Equal(t, PLAN_ACTION_MANUAL, item.Action)
+	item = computeRemovedAppPlan("payments", 7, "payments.yaml", true)
+	assert.Equal(t, PLAN_ACTION_DELETE, item.Action)
+	assert.Equal(t, SYNC_STATUS_OUT_OF_SYNC, getSyncStatus([]*PlanItem{{Status: SYNC_STATUS_SYNCED}, item}))
+}
@@ -0,0 +1,123 @@
+package appSync
+
+import (
+	"encoding/json"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/drift"
+	"time"
+)
+
+const (
+	APP_DEFINITION_API_VERSION = "devtron.ai/v1beta1"
+	APP_DEFINITION_KIND        = "Application"
+)
+
+// AppDefinition is the document stored in a sync repository, Spec has the same shape as the
+// v1beta1/application api request
+type AppDefinition struct {
+	ApiVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Spec       *appBean.AppDetail `json:"spec"`
+}
+
+// appDefinitionFile is a parsed definition along with its raw spec, only the fields present in the raw spec
+// are compared with devtron so that omitted fields never show up as drift
+type appDefinitionFile struct {
+	filePath   string
+	definition *appBean.AppDetail
+	rawSpec    map[string]interface{}
+	parseError error
+}
+
+type SyncResource string
+
+const (
+	SYNC_RESOURCE_APP                 SyncResource = "APP"
+	SYNC_RESOURCE_METADATA            SyncResource = "METADATA"
+	SYNC_RESOURCE_GIT_MATERIAL        SyncResource = "GIT_MATERIAL"
+	SYNC_RESOURCE_DOCKER_CONFIG       SyncResource = "DOCKER_CONFIG"
+	SYNC_RESOURCE_DEPLOYMENT_TEMPLATE SyncResource = "DEPLOYMENT_TEMPLATE"
+	SYNC_RESOURCE_CONFIG_MAP          SyncResource = "CONFIG_MAP"
+	SYNC_RESOURCE_SECRET              SyncResource = "SECRET"
+	SYNC_RESOURCE_WORKFLOW            SyncResource = "WORKFLOW"
+	SYNC_RESOURCE_ENV_OVERRIDE        SyncResource = "ENVIRONMENT_OVERRIDE"
+)
+
+type PlanAction string
+
+const (
+	PLAN_ACTION_NONE   PlanAction = "NONE"
+	PLAN_ACTION_CREATE PlanAction = "CREATE"
+	PLAN_ACTION_UPDATE PlanAction = "UPDATE"
+	PLAN_ACTION_DELETE PlanAction = "DELETE"
+	// PLAN_ACTION_MANUAL is drift which sync cannot reconcile, it has to be fixed in git or applied by hand
+	PLAN_ACTION_MANUAL PlanAction = "MANUAL"
+)
+
+type SyncStatus string
+
+const (
+	SYNC_STATUS_SYNCED      SyncStatus = "SYNCED"
+	SYNC_STATUS_OUT_OF_SYNC SyncStatus = "OUT_OF_SYNC"
+	SYNC_STATUS_FAILED      SyncStatus = "FAILED"
+)
+
+type AppSyncSourceDto struct {
+	Id               int        `json:"id"`
+	Name             string     `json:"name" validate:"required,max=50"`
+	GitRepoUrl       string     `json:"gitRepoUrl" validate:"required"`
+	Branch           string     `json:"branch" validate:"required"`
+	Path             string     `json:"path"`
+	GitProviderId    int        `json:"gitProviderId" validate:"required"`
+	AutoSync         bool       `json:"autoSync"`
+	Prune            bool       `json:"prune"`
+	LastSyncedCommit string     `json:"lastSyncedCommit,omitempty"`
+	LastSyncedOn     *time.Time `json:"lastSyncedOn,omitempty"`
+	LastSyncStatus   SyncStatus `json:"lastSyncStatus,omitempty"`
+	LastSyncMessage  string     `json:"lastSyncMessage,omitempty"`
+	UserId           int32      `json:"-"`
+}
+
+type SyncRequest struct {
+	SyncSourceId int   `json:"syncSourceId" validate:"required"`
+	DryRun       bool  `json:"dryRun"`
+	UserId       int32 `json:"-"`
+}
+
+// PlanItem is a single change, or drift, of a resource of an app
+type PlanItem struct {
+	AppName      string       `json:"appName"`
+	AppId        int          `json:"appId,omitempty"`
+	FilePath     string       `json:"filePath,omitempty"`
+	Resource     SyncResource `json:"resource"`
+	ResourceName string       `json:"resourceName,omitempty"`
+	Action       PlanAction   `json:"action"`
+	Status       SyncStatus   `json:"status"`
+	Message      string       `json:"message,omitempty"`
+	// Diff holds the operations which turn the resource in devtron into the one in git
+	Diff       []*drift.JsonPatchOperation `json:"diff,omitempty"`
+	definition *appBean.AppDetail
+}
+
+type SyncResponse struct {
+	SyncSourceId int         `json:"syncSourceId"`
+	CommitHash   string      `json:"commitHash"`
+	DryRun       bool        `json:"dryRun"`
+	Status       SyncStatus  `json:"status"`
+	Items        []*PlanItem `json:"items"`
+}
+
+type SyncStatusResponse struct {
+	Source *AppSyncSourceDto `json:"source"`
+	Items  []*PlanItem       `json:"items"`
+}
+
+func toRawSpec(spec interface{}) (map[string]interface{}, error) {
+	specJson, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	rawSpec := make(map[string]interface{})
+	err = json.Unmarshal(specJson, &rawSpec)
+	return rawSpec, err
+}
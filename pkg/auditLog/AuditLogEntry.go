@@ -0,0 +1,101 @@
+package auditLog
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	util2 "github.com/devtron-labs/devtron/util"
+	"github.com/gorilla/mux"
+)
+
+type auditLogContextKey struct{}
+
+// AuditLogEntry is an audit log being collected while a request is served, the token is kept so that the user is
+// resolved off the request path
+type AuditLogEntry struct {
+	AuditLog *AuditLog
+	Token    string
+}
+
+// NewAuditLogEntry collects the request details, it is to be called once routing is done so that the route template
+// and path variables are available. X-Forwarded-For is only trusted when the request came through the trusted proxies
+func NewAuditLogEntry(r *http.Request, trustedProxies []string) *AuditLogEntry {
+	auditLog := &AuditLog{
+		ActionTime:     time.Now(),
+		ClientIp:       util2.GetRequestClientIp(r, trustedProxies),
+		Method:         r.Method,
+		Path:           r.URL.Path,
+		Resource:       r.URL.Path,
+		ResourceParams: mux.Vars(r),
+		Action:         GetAction(r.Method),
+	}
+	if route := mux.CurrentRoute(r); route != nil {
+		if pathTemplate, err := route.GetPathTemplate(); err == nil {
+			auditLog.Resource = pathTemplate
+		}
+	}
+	return &AuditLogEntry{AuditLog: auditLog, Token: getToken(r)}
+}
+
+func getToken(r *http.Request) string {
+	token := r.Header.Get("api-token")
+	if len(token) == 0 {
+		token = r.Header.Get("token")
+	}
+	if len(token) == 0 {
+		token = util2.GetBearerToken(r)
+	}
+	return token
+}
+
+// GetAction maps a mutating http method to the audit action, empty for read only methods
+func GetAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return ACTION_CREATE
+	case http.MethodPut, http.MethodPatch:
+		return ACTION_UPDATE
+	case http.MethodDelete:
+		return ACTION_DELETE
+	}
+	return ""
+}
+
+func IsMutatingMethod(method string) bool {
+	return len(GetAction(method)) > 0
+}
+
+func WithAuditLogEntry(r *http.Request, entry *AuditLogEntry) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), auditLogContextKey{}, entry))
+}
+
+func GetAuditLogEntry(r *http.Request) *AuditLogEntry {
+	entry, _ := r.Context().Value(auditLogContextKey{}).(*AuditLogEntry)
+	return entry
+}
+
+// RecordChange adds the diff of a config changed by the request to its audit log, it is a no-op for requests which
+// are not audited. Failures are not surfaced to the caller as the change itself has been made already
+func RecordChange(r *http.Request, name string, before interface{}, after interface{}) {
+	RecordMaskedChange(r, name, before, after)
+}
+
+// RecordMaskedChange is RecordChange for configs holding secrets, the values of changes under any of the masked fields
+// are replaced so that the audit log only tells that they changed
+func RecordMaskedChange(r *http.Request, name string, before interface{}, after interface{}, maskedFields ...string) {
+	entry := GetAuditLogEntry(r)
+	if entry == nil {
+		return
+	}
+	changes, err := GetConfigChanges(name, before, after)
+	if err != nil {
+		changes = []*ConfigChange{{Path: name, Before: before, After: after}}
+		if len(maskedFields) > 0 {
+			// the fields can not be told apart, the whole config is masked
+			maskedFields = []string{""}
+		}
+	}
+	MaskConfigChanges(name, changes, maskedFields)
+	entry.AuditLog.ConfigChanges = append(entry.AuditLog.ConfigChanges, changes...)
+}
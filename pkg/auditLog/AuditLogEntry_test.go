@@ -0,0 +1,42 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditLogEntry(t *testing.T) {
+	var entry *AuditLogEntry
+	router := mux.NewRouter()
+	router.Path("/orchestrator/app/{appId}/template").Methods("PUT").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry = NewAuditLogEntry(r, []string{"192.0.2.1"})
+		r = WithAuditLogEntry(r, entry)
+		RecordChange(r, "deploymentTemplate", json.RawMessage(`{"replicaCount": 1}`), json.RawMessage(`{"replicaCount": 2}`))
+	})
+	req := httptest.NewRequest(http.MethodPut, "/orchestrator/app/12/template", nil)
+	req.Header.Set("Authorization", "Bearer some-token")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NotNil(t, entry)
+	assert.Equal(t, "some-token", entry.Token)
+	assert.Equal(t, "10.0.0.1", entry.AuditLog.ClientIp)
+	// the header is ignored when not sent by a trusted proxy
+	assert.Equal(t, "192.0.2.1", NewAuditLogEntry(req, nil).AuditLog.ClientIp)
+	assert.Equal(t, "/orchestrator/app/{appId}/template", entry.AuditLog.Resource)
+	assert.Equal(t, map[string]string{"appId": "12"}, entry.AuditLog.ResourceParams)
+	assert.Equal(t, ACTION_UPDATE, entry.AuditLog.Action)
+	assert.Equal(t, []*ConfigChange{{Path: "deploymentTemplate.replicaCount", Before: float64(1), After: float64(2)}}, entry.AuditLog.ConfigChanges)
+}
+
+func TestRecordChangeWithoutEntry(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orchestrator/app/12", nil)
+	RecordChange(req, "deploymentTemplate", nil, json.RawMessage(`{}`))
+	assert.Nil(t, GetAuditLogEntry(req))
+	assert.False(t, IsMutatingMethod(http.MethodGet))
+}
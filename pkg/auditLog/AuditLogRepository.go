@@ -0,0 +1,98 @@
+package auditLog
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// AuditLog is an entry of the audit log, written once per mutating api call and never updated
+type AuditLog struct {
+	TableName      struct{}          `sql:"audit_log" pg:",discard_unknown_columns"`
+	Id             int               `sql:"id,pk"`
+	ActionTime     time.Time         `sql:"action_time,notnull"`
+	UserId         int32             `sql:"user_id"`
+	EmailId        string            `sql:"email_id"`
+	UserType       string            `sql:"user_type"`
+	ApiTokenName   string            `sql:"api_token_name"`
+	ClientIp       string            `sql:"client_ip"`
+	Method         string            `sql:"method,notnull"`
+	Path           string            `sql:"path,notnull"`
+	Resource       string            `sql:"resource,notnull"`
+	ResourceParams map[string]string `sql:"resource_params"`
+	Action         string            `sql:"action,notnull"`
+	StatusCode     int               `sql:"status_code,notnull"`
+	DurationInMs   int64             `sql:"duration_in_ms"`
+	ConfigChanges  []*ConfigChange   `sql:"config_changes"`
+}
+
+type AuditLogRepository interface {
+	Save(auditLog *AuditLog) error
+	FindByFilter(filter *AuditLogFilter) ([]*AuditLog, int, error)
+	// DeleteBefore deletes the entries older than the time given, used for retention only
+	DeleteBefore(actionTime time.Time) (int, error)
+}
+
+type AuditLogRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAuditLogRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AuditLogRepositoryImpl {
+	return &AuditLogRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl AuditLogRepositoryImpl) Save(auditLog *AuditLog) error {
+	return impl.dbConnection.Insert(auditLog)
+}
+
+func (impl AuditLogRepositoryImpl) FindByFilter(filter *AuditLogFilter) ([]*AuditLog, int, error) {
+	var auditLogs []*AuditLog
+	query := impl.dbConnection.Model(&auditLogs)
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if len(filter.EmailId) > 0 {
+		query = query.Where("email_id ILIKE ?", "%"+filter.EmailId+"%")
+	}
+	if len(filter.UserType) > 0 {
+		query = query.Where("user_type = ?", filter.UserType)
+	}
+	if len(filter.ApiTokenName) > 0 {
+		query = query.Where("api_token_name = ?", filter.ApiTokenName)
+	}
+	if len(filter.Method) > 0 {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if len(filter.Resource) > 0 {
+		query = query.Where("resource ILIKE ?", "%"+filter.Resource+"%")
+	}
+	if len(filter.Action) > 0 {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.StatusCode > 0 {
+		query = query.Where("status_code = ?", filter.StatusCode)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("action_time >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("action_time <= ?", filter.To)
+	}
+	totalCount, err := query.Order("action_time desc", "id desc").
+		Offset(filter.Offset).
+		Limit(filter.Size).
+		SelectAndCount()
+	return auditLogs, totalCount, err
+}
+
+func (impl AuditLogRepositoryImpl) DeleteBefore(actionTime time.Time) (int, error) {
+	res, err := impl.dbConnection.Model(&AuditLog{}).Where("action_time < ?", actionTime).Delete()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
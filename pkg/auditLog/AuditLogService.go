@@ -0,0 +1,302 @@
+package auditLog
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type AuditLogConfig struct {
+	Enabled bool `env:"AUDIT_LOG_ENABLED" envDefault:"true"`
+	// RetentionDays is the age after which entries are deleted, 0 keeps them forever
+	RetentionDays int    `env:"AUDIT_LOG_RETENTION_DAYS" envDefault:"90"`
+	RetentionCron string `env:"AUDIT_LOG_RETENTION_CRON" envDefault:"0 2 * * *"`
+	BufferSize    int    `env:"AUDIT_LOG_BUFFER_SIZE" envDefault:"1000"`
+	// ExcludedPaths are path fragments of mutating calls not worth auditing like terminal streams and event ingestion
+	ExcludedPaths       []string `env:"AUDIT_LOG_EXCLUDED_PATHS" envSeparator:"," envDefault:"/pod/exec/sockjs/ws,/orchestrator/webhook/msg/nats,/orchestrator/telemetry,/orchestrator/dashboard-event"`
+	SinkType            string   `env:"AUDIT_LOG_SINK_TYPE" envDefault:""`
+	SyslogNetwork       string   `env:"AUDIT_LOG_SYSLOG_NETWORK" envDefault:""`
+	SyslogAddress       string   `env:"AUDIT_LOG_SYSLOG_ADDRESS" envDefault:""`
+	SyslogTag           string   `env:"AUDIT_LOG_SYSLOG_TAG" envDefault:"devtron-audit"`
+	HttpSinkUrl         string   `env:"AUDIT_LOG_HTTP_SINK_URL" envDefault:""`
+	HttpSinkAuthHeader  string   `env:"AUDIT_LOG_HTTP_SINK_AUTH_HEADER" envDefault:""`
+	HttpSinkTimeoutSecs int      `env:"AUDIT_LOG_HTTP_SINK_TIMEOUT_SECS" envDefault:"5"`
+}
+
+func GetAuditLogConfig() (*AuditLogConfig, error) {
+	cfg := &AuditLogConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type AuditLogService interface {
+	IsEnabled() bool
+	IsExcluded(path string) bool
+	// GetTrustedProxies returns the proxies whose X-Forwarded-For entries are trusted for the client ip
+	GetTrustedProxies() []string
+	// Record queues the entry to be saved and exported, user resolution and persistence happen off the request path
+	Record(entry *AuditLogEntry)
+	GetAuditLogs(filter *AuditLogFilter) (*AuditLogListResponse, error)
+	ApplyRetention()
+	// Stop saves and exports the entries still buffered, it is to be called on shutdown once requests are served
+	Stop()
+}
+
+type AuditLogServiceImpl struct {
+	logger             *zap.SugaredLogger
+	auditLogRepository AuditLogRepository
+	userService        user.UserService
+	userRepository     repository.UserRepository
+	cfg                *AuditLogConfig
+	trustedProxies     []string
+	sink               AuditLogSink
+	entries            chan *AuditLogEntry
+	exports            chan *AuditLogDto
+	entriesDone        chan struct{}
+	exportsDone        chan struct{}
+	// lock guards stopped against entries being queued while the channels are closed
+	lock          sync.RWMutex
+	stopped       bool
+	retentionCron *cron.Cron
+}
+
+func NewAuditLogServiceImpl(logger *zap.SugaredLogger, auditLogRepository AuditLogRepository,
+	userService user.UserService, userRepository repository.UserRepository) (*AuditLogServiceImpl, error) {
+	cfg, err := GetAuditLogConfig()
+	if err != nil {
+		logger.Errorw("error in parsing audit log config", "err", err)
+		return nil, err
+	}
+	trustedProxies, err := util.GetTrustedProxies()
+	if err != nil {
+		logger.Errorw("error in parsing trusted proxies for audit log", "err", err)
+		return nil, err
+	}
+	impl := &AuditLogServiceImpl{
+		logger:             logger,
+		auditLogRepository: auditLogRepository,
+		userService:        userService,
+		userRepository:     userRepository,
+		cfg:                cfg,
+		trustedProxies:     trustedProxies,
+	}
+	if !cfg.Enabled {
+		return impl, nil
+	}
+	impl.sink, err = NewAuditLogSink(cfg)
+	if err != nil {
+		logger.Errorw("error in initialising audit log sink", "sinkType", cfg.SinkType, "err", err)
+		return nil, err
+	}
+	impl.entries = make(chan *AuditLogEntry, cfg.BufferSize)
+	impl.entriesDone = make(chan struct{})
+	go impl.processEntries()
+	if impl.sink != nil {
+		impl.exports = make(chan *AuditLogDto, cfg.BufferSize)
+		impl.exportsDone = make(chan struct{})
+		go impl.exportEntries()
+	}
+	if cfg.RetentionDays > 0 {
+		impl.retentionCron = cron.New(cron.WithChain())
+		_, err = impl.retentionCron.AddFunc(cfg.RetentionCron, impl.ApplyRetention)
+		if err != nil {
+			logger.Errorw("error in adding audit log retention cron", "cron", cfg.RetentionCron, "err", err)
+			return nil, err
+		}
+		impl.retentionCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *AuditLogServiceImpl) IsEnabled() bool {
+	return impl.cfg.Enabled
+}
+
+func (impl *AuditLogServiceImpl) IsExcluded(path string) bool {
+	for _, excludedPath := range impl.cfg.ExcludedPaths {
+		excludedPath = strings.TrimSpace(excludedPath)
+		if len(excludedPath) > 0 && strings.Contains(path, excludedPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (impl *AuditLogServiceImpl) GetTrustedProxies() []string {
+	return impl.trustedProxies
+}
+
+func (impl *AuditLogServiceImpl) Record(entry *AuditLogEntry) {
+	if !impl.cfg.Enabled {
+		return
+	}
+	impl.lock.RLock()
+	defer impl.lock.RUnlock()
+	if impl.stopped {
+		// request finished after shutdown began, it is saved but not exported anymore
+		impl.save(entry)
+		return
+	}
+	select {
+	case impl.entries <- entry:
+	default:
+		// buffer is full, the audit log is not to be dropped so the caller pays for it
+		impl.logger.Warnw("audit log buffer full, saving synchronously", "path", entry.AuditLog.Path)
+		impl.process(entry)
+	}
+}
+
+func (impl *AuditLogServiceImpl) Stop() {
+	if !impl.cfg.Enabled {
+		return
+	}
+	impl.lock.Lock()
+	if impl.stopped {
+		impl.lock.Unlock()
+		return
+	}
+	impl.stopped = true
+	close(impl.entries)
+	impl.lock.Unlock()
+	impl.logger.Infow("saving buffered audit logs before shutdown", "count", len(impl.entries))
+	<-impl.entriesDone
+	if impl.sink != nil {
+		close(impl.exports)
+		<-impl.exportsDone
+	}
+	if impl.retentionCron != nil {
+		impl.retentionCron.Stop()
+	}
+}
+
+func (impl *AuditLogServiceImpl) processEntries() {
+	defer close(impl.entriesDone)
+	for entry := range impl.entries {
+		impl.process(entry)
+	}
+}
+
+func (impl *AuditLogServiceImpl) process(entry *AuditLogEntry) {
+	if !impl.save(entry) || impl.sink == nil {
+		return
+	}
+	auditLog := entry.AuditLog
+	dto := toAuditLogDto(auditLog)
+	select {
+	case impl.exports <- dto:
+	default:
+		impl.logger.Errorw("audit log export buffer full, entry not exported", "id", auditLog.Id)
+	}
+}
+
+func (impl *AuditLogServiceImpl) save(entry *AuditLogEntry) bool {
+	auditLog := entry.AuditLog
+	impl.resolveUser(entry.Token, auditLog)
+	err := impl.auditLogRepository.Save(auditLog)
+	if err != nil {
+		impl.logger.Errorw("error in saving audit log", "auditLog", auditLog, "err", err)
+		return false
+	}
+	return true
+}
+
+func (impl *AuditLogServiceImpl) resolveUser(token string, auditLog *AuditLog) {
+	if len(token) == 0 {
+		return
+	}
+	emailId, err := impl.userService.GetEmailFromToken(token)
+	if err != nil || len(emailId) == 0 {
+		// unauthenticated calls are audited too, without the user
+		return
+	}
+	auditLog.EmailId = emailId
+	auditLog.UserType = USER_TYPE_USER
+	if strings.HasPrefix(emailId, apiToken.API_TOKEN_USER_EMAIL_PREFIX) {
+		auditLog.UserType = bean.USER_TYPE_API_TOKEN
+		auditLog.ApiTokenName = strings.TrimPrefix(emailId, apiToken.API_TOKEN_USER_EMAIL_PREFIX)
+	}
+	userInfo, err := impl.userRepository.FetchActiveUserByEmail(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user for audit log", "emailId", emailId, "err", err)
+		return
+	}
+	auditLog.UserId = userInfo.Id
+}
+
+func (impl *AuditLogServiceImpl) exportEntries() {
+	defer close(impl.exportsDone)
+	for dto := range impl.exports {
+		err := impl.sink.Export(dto)
+		if err != nil {
+			impl.logger.Errorw("error in exporting audit log", "id", dto.Id, "sinkType", impl.cfg.SinkType, "err", err)
+		}
+	}
+}
+
+func (impl *AuditLogServiceImpl) GetAuditLogs(filter *AuditLogFilter) (*AuditLogListResponse, error) {
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	if filter.Size <= 0 {
+		filter.Size = DefaultPageSize
+	} else if filter.Size > MaxPageSize {
+		filter.Size = MaxPageSize
+	}
+	auditLogs, totalCount, err := impl.auditLogRepository.FindByFilter(filter)
+	if err != nil {
+		impl.logger.Errorw("error in fetching audit logs", "filter", filter, "err", err)
+		return nil, err
+	}
+	dtos := make([]*AuditLogDto, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		dtos = append(dtos, toAuditLogDto(auditLog))
+	}
+	return &AuditLogListResponse{
+		AuditLogs:  dtos,
+		TotalCount: totalCount,
+		Offset:     filter.Offset,
+		Size:       filter.Size,
+	}, nil
+}
+
+func (impl *AuditLogServiceImpl) ApplyRetention() {
+	if impl.cfg.RetentionDays <= 0 {
+		return
+	}
+	retainFrom := time.Now().AddDate(0, 0, -impl.cfg.RetentionDays)
+	deleted, err := impl.auditLogRepository.DeleteBefore(retainFrom)
+	if err != nil {
+		impl.logger.Errorw("error in deleting audit logs past retention", "retainFrom", retainFrom, "err", err)
+		return
+	}
+	impl.logger.Infow("deleted audit logs past retention", "retainFrom", retainFrom, "count", deleted)
+}
+
+func toAuditLogDto(auditLog *AuditLog) *AuditLogDto {
+	return &AuditLogDto{
+		Id:             auditLog.Id,
+		ActionTime:     auditLog.ActionTime,
+		UserId:         auditLog.UserId,
+		EmailId:        auditLog.EmailId,
+		UserType:       auditLog.UserType,
+		ApiTokenName:   auditLog.ApiTokenName,
+		ClientIp:       auditLog.ClientIp,
+		Method:         auditLog.Method,
+		Path:           auditLog.Path,
+		Resource:       auditLog.Resource,
+		ResourceParams: auditLog.ResourceParams,
+		Action:         auditLog.Action,
+		StatusCode:     auditLog.StatusCode,
+		DurationInMs:   auditLog.DurationInMs,
+		ConfigChanges:  auditLog.ConfigChanges,
+	}
+}
@@ -0,0 +1,54 @@
+package auditLog
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type auditLogRepositoryStub struct {
+	lock  sync.Mutex
+	saved []*AuditLog
+}
+
+func (repo *auditLogRepositoryStub) Save(auditLog *AuditLog) error {
+	// slow enough for entries to be still buffered when stopping
+	time.Sleep(time.Millisecond)
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.saved = append(repo.saved, auditLog)
+	return nil
+}
+
+func (repo *auditLogRepositoryStub) FindByFilter(filter *AuditLogFilter) ([]*AuditLog, int, error) {
+	return nil, 0, nil
+}
+
+func (repo *auditLogRepositoryStub) DeleteBefore(actionTime time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestStopSavesBufferedEntries(t *testing.T) {
+	repo := &auditLogRepositoryStub{}
+	impl := &AuditLogServiceImpl{
+		logger:             zap.NewNop().Sugar(),
+		auditLogRepository: repo,
+		cfg:                &AuditLogConfig{Enabled: true},
+		entries:            make(chan *AuditLogEntry, 100),
+		entriesDone:        make(chan struct{}),
+	}
+	go impl.processEntries()
+	for i := 0; i < 50; i++ {
+		impl.Record(&AuditLogEntry{AuditLog: &AuditLog{Path: "/orchestrator/app"}})
+	}
+	impl.Stop()
+	assert.Len(t, repo.saved, 50)
+
+	// entries of requests finishing after the stop are saved synchronously
+	impl.Record(&AuditLogEntry{AuditLog: &AuditLog{Path: "/orchestrator/app"}})
+	assert.Len(t, repo.saved, 51)
+	impl.Stop()
+}
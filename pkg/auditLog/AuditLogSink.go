@@ -0,0 +1,86 @@
+package auditLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/http"
+	"time"
+)
+
+const (
+	SINK_TYPE_SYSLOG = "SYSLOG"
+	SINK_TYPE_HTTP   = "HTTP"
+)
+
+// AuditLogSink streams the saved audit logs to an external system
+type AuditLogSink interface {
+	Export(auditLog *AuditLogDto) error
+}
+
+// NewAuditLogSink returns the sink configured, nil when export is not enabled
+func NewAuditLogSink(cfg *AuditLogConfig) (AuditLogSink, error) {
+	switch cfg.SinkType {
+	case "":
+		return nil, nil
+	case SINK_TYPE_SYSLOG:
+		writer, err := syslog.Dial(cfg.SyslogNetwork, cfg.SyslogAddress, syslog.LOG_INFO|syslog.LOG_AUTH, cfg.SyslogTag)
+		if err != nil {
+			return nil, err
+		}
+		return &SyslogSink{writer: writer}, nil
+	case SINK_TYPE_HTTP:
+		if len(cfg.HttpSinkUrl) == 0 {
+			return nil, fmt.Errorf("url is required for audit log sink of type %s", SINK_TYPE_HTTP)
+		}
+		return &HttpSink{
+			url:        cfg.HttpSinkUrl,
+			authHeader: cfg.HttpSinkAuthHeader,
+			client:     &http.Client{Timeout: time.Duration(cfg.HttpSinkTimeoutSecs) * time.Second},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported audit log sink type %s", cfg.SinkType)
+}
+
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+func (impl *SyslogSink) Export(auditLog *AuditLogDto) error {
+	data, err := json.Marshal(auditLog)
+	if err != nil {
+		return err
+	}
+	return impl.writer.Info(string(data))
+}
+
+type HttpSink struct {
+	url        string
+	authHeader string
+	client     *http.Client
+}
+
+func (impl *HttpSink) Export(auditLog *AuditLogDto) error {
+	data, err := json.Marshal(auditLog)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, impl.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(impl.authHeader) > 0 {
+		req.Header.Set("Authorization", impl.authHeader)
+	}
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("audit log sink responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
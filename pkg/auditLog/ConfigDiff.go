@@ -0,0 +1,185 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxConfigChanges caps the changes kept for a single config, bulk rewrites of large configs are not worth listing
+const maxConfigChanges = 500
+
+// MASKED_CONFIG_VALUE replaces values of changes to secrets
+const MASKED_CONFIG_VALUE = "********"
+
+// GetConfigChanges compares two versions of a config by their json form down to the leaf values. Configs given as
+// json bytes are compared by their content, anything else is marshalled first
+func GetConfigChanges(name string, before interface{}, after interface{}) ([]*ConfigChange, error) {
+	beforeValue, err := toJsonValue(before)
+	if err != nil {
+		return nil, err
+	}
+	afterValue, err := toJsonValue(after)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*ConfigChange
+	diffValues(name, beforeValue, afterValue, &changes)
+	return changes, nil
+}
+
+func toJsonValue(config interface{}) (interface{}, error) {
+	var data []byte
+	var err error
+	switch v := config.(type) {
+	case nil:
+		return nil, nil
+	case json.RawMessage:
+		data = v
+	case []byte:
+		data = v
+	default:
+		data, err = json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+func diffValues(path string, before interface{}, after interface{}, changes *[]*ConfigChange) {
+	if len(*changes) >= maxConfigChanges {
+		return
+	}
+	beforeMap, isBeforeMap := before.(map[string]interface{})
+	afterMap, isAfterMap := after.(map[string]interface{})
+	if isBeforeMap && isAfterMap {
+		keys := make(map[string]bool)
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+		for _, key := range sortedKeys {
+			diffValues(joinPath(path, key), beforeMap[key], afterMap[key], changes)
+		}
+		return
+	}
+	beforeList, isBeforeList := before.([]interface{})
+	afterList, isAfterList := after.([]interface{})
+	if isBeforeList && isAfterList {
+		length := len(beforeList)
+		if len(afterList) > length {
+			length = len(afterList)
+		}
+		for i := 0; i < length; i++ {
+			var beforeItem, afterItem interface{}
+			if i < len(beforeList) {
+				beforeItem = beforeList[i]
+			}
+			if i < len(afterList) {
+				afterItem = afterList[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), beforeItem, afterItem, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, &ConfigChange{Path: path, Before: before, After: after})
+	}
+}
+
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// MaskConfigChanges masks the values of changes under any of the fields, fields are relative to the config name and
+// match at any depth so that a field of each item of a list is masked. Values of changes above the fields, like a
+// config added as a whole, keep their other fields. An empty field masks the whole config
+func MaskConfigChanges(name string, changes []*ConfigChange, maskedFields []string) {
+	if len(maskedFields) == 0 {
+		return
+	}
+	for _, change := range changes {
+		masked := false
+		for _, field := range maskedFields {
+			if isUnderField(name, change.Path, field) {
+				masked = true
+				break
+			}
+		}
+		if masked {
+			change.Before = maskConfigValue(change.Before)
+			change.After = maskConfigValue(change.After)
+		} else {
+			change.Before = maskNestedFields(change.Before, maskedFields)
+			change.After = maskNestedFields(change.After, maskedFields)
+		}
+	}
+}
+
+func maskNestedFields(value interface{}, maskedFields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			masked[key] = maskNestedFields(item, maskedFields)
+			for _, field := range maskedFields {
+				if key == field {
+					masked[key] = maskConfigValue(item)
+					break
+				}
+			}
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = maskNestedFields(item, maskedFields)
+		}
+		return masked
+	}
+	return value
+}
+
+func isUnderField(name string, path string, field string) bool {
+	if len(field) == 0 {
+		return true
+	}
+	if !strings.HasPrefix(path, name) {
+		return false
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(path, name), ".") {
+		if index := strings.Index(part, "["); index >= 0 {
+			part = part[:index]
+		}
+		if part == field {
+			return true
+		}
+	}
+	return false
+}
+
+// maskConfigValue keeps nil as is, so that secrets being added or removed still show
+func maskConfigValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return MASKED_CONFIG_VALUE
+}
@@ -0,0 +1,57 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetConfigChanges(t *testing.T) {
+	before := json.RawMessage(`{"replicaCount": 1, "resources": {"limits": {"cpu": "1"}}, "args": ["a", "b"], "unchanged": true}`)
+	after := map[string]interface{}{
+		"replicaCount": 2,
+		"resources":    map[string]interface{}{"limits": map[string]interface{}{"cpu": "1", "memory": "1Gi"}},
+		"args":         []string{"a"},
+		"unchanged":    true,
+	}
+	changes, err := GetConfigChanges("deploymentTemplate", before, after)
+	assert.Nil(t, err)
+	assert.Equal(t, []*ConfigChange{
+		{Path: "deploymentTemplate.args[1]", Before: "b"},
+		{Path: "deploymentTemplate.replicaCount", Before: float64(1), After: float64(2)},
+		{Path: "deploymentTemplate.resources.limits.memory", After: "1Gi"},
+	}, changes)
+
+	changes, err = GetConfigChanges("deploymentTemplate", nil, json.RawMessage(`{"replicaCount": 1}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []*ConfigChange{{Path: "deploymentTemplate", After: map[string]interface{}{"replicaCount": float64(1)}}}, changes)
+
+	_, err = GetConfigChanges("deploymentTemplate", json.RawMessage(`{`), nil)
+	assert.NotNil(t, err)
+}
+
+func TestMaskConfigChanges(t *testing.T) {
+	before := json.RawMessage(`{"name": "db", "type": "environment", "data": {"password": "old", "user": "app"}}`)
+	after := json.RawMessage(`{"name": "db", "type": "volume", "data": {"password": "new", "user": "app", "host": "db.local"}}`)
+	changes, err := GetConfigChanges("secret.db", before, after)
+	assert.Nil(t, err)
+	MaskConfigChanges("secret.db", changes, []string{"data"})
+	assert.Equal(t, []*ConfigChange{
+		{Path: "secret.db.data.host", After: MASKED_CONFIG_VALUE},
+		{Path: "secret.db.data.password", Before: MASKED_CONFIG_VALUE, After: MASKED_CONFIG_VALUE},
+		{Path: "secret.db.type", Before: "environment", After: "volume"},
+	}, changes)
+
+	// secret added as a whole keeps its other fields
+	changes, err = GetConfigChanges("secret.db", nil, after)
+	assert.Nil(t, err)
+	MaskConfigChanges("secret.db", changes, []string{"data"})
+	assert.Equal(t, []*ConfigChange{{Path: "secret.db", After: map[string]interface{}{
+		"name": "db", "type": "volume", "data": MASKED_CONFIG_VALUE,
+	}}}, changes)
+
+	// empty field masks the whole config
+	changes = []*ConfigChange{{Path: "secret.db", Before: "old", After: "new"}}
+	MaskConfigChanges("secret.db", changes, []string{""})
+	assert.Equal(t, []*ConfigChange{{Path: "secret.db", Before: MASKED_CONFIG_VALUE, After: MASKED_CONFIG_VALUE}}, changes)
+}
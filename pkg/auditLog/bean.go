@@ -0,0 +1,62 @@
+package auditLog
+
+import "time"
+
+const (
+	ACTION_CREATE = "CREATE"
+	ACTION_UPDATE = "UPDATE"
+	ACTION_DELETE = "DELETE"
+
+	USER_TYPE_USER = "user"
+
+	DefaultPageSize = 20
+	MaxPageSize     = 500
+)
+
+// ConfigChange is a changed value of a config, Path is the dotted path to the value like
+// deploymentTemplate.resources.limits.cpu, Before is nil for added and After is nil for removed values
+type ConfigChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+type AuditLogFilter struct {
+	UserId       int32     `json:"userId"`
+	EmailId      string    `json:"emailId"`
+	UserType     string    `json:"userType"`
+	ApiTokenName string    `json:"apiTokenName"`
+	Method       string    `json:"method"`
+	Resource     string    `json:"resource"`
+	Action       string    `json:"action"`
+	StatusCode   int       `json:"statusCode"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Offset       int       `json:"offset"`
+	Size         int       `json:"size"`
+}
+
+type AuditLogDto struct {
+	Id             int               `json:"id"`
+	ActionTime     time.Time         `json:"actionTime"`
+	UserId         int32             `json:"userId,omitempty"`
+	EmailId        string            `json:"emailId,omitempty"`
+	UserType       string            `json:"userType,omitempty"`
+	ApiTokenName   string            `json:"apiTokenName,omitempty"`
+	ClientIp       string            `json:"clientIp,omitempty"`
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	Resource       string            `json:"resource"`
+	ResourceParams map[string]string `json:"resourceParams,omitempty"`
+	Action         string            `json:"action"`
+	StatusCode     int               `json:"statusCode"`
+	DurationInMs   int64             `json:"durationInMs"`
+	ConfigChanges  []*ConfigChange   `json:"configChanges,omitempty"`
+}
+
+type AuditLogListResponse struct {
+	AuditLogs  []*AuditLogDto `json:"auditLogs"`
+	TotalCount int            `json:"totalCount"`
+	Offset     int            `json:"offset"`
+	Size       int            `json:"size"`
+}
@@ -20,6 +20,7 @@ package bean
 import (
 	"encoding/json"
 	bean2 "github.com/devtron-labs/devtron/api/bean"
+	repository3 "github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
 	repository2 "github.com/devtron-labs/devtron/internal/sql/repository/imageTagging"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
@@ -517,6 +518,7 @@ type CDPipelineConfigObject struct {
 	AppName                       string                                 `json:"appName"`
 	DeploymentAppDeleteRequest    bool                                   `json:"deploymentAppDeleteRequest"`
 	DeploymentAppCreated          bool                                   `json:"deploymentAppCreated"`
+	ValidateImagePlatforms        bool                                   `json:"validateImagePlatforms"` //block deploy of multi-arch images not covering the node platforms of the cluster
 	AppId                         int                                    `json:"appId"`
 	TeamId                        int                                    `json:"-"`
 	EnvironmentIdentifier         string                                 `json:"-" `
@@ -525,6 +527,8 @@ type CDPipelineConfigObject struct {
 	ChartName                     string                                 `json:"chartName"`
 	ChartBaseVersion              string                                 `json:"chartBaseVersion"`
 	ContainerRegistryId           int                                    `json:"containerRegistryId"`
+	ContainerRegistryName         string                                 `json:"containerRegistryName"`
+	DeployFromOciChart            bool                                   `json:"deployFromOciChart"`
 	RepoUrl                       string                                 `json:"repoUrl"`
 	ManifestStorageType           string                                 `json:"manifestStorageType"`
 	PreDeployStage                *bean.PipelineStageDto                 `json:"preDeployStage,omitempty"`
@@ -673,25 +677,27 @@ type Rollback struct {
 }
 
 type CiArtifactBean struct {
-	Id                            int                       `json:"id"`
-	Image                         string                    `json:"image,notnull"`
-	ImageDigest                   string                    `json:"image_digest,notnull"`
-	MaterialInfo                  json.RawMessage           `json:"material_info"` //git material metadata json array string
-	DataSource                    string                    `json:"data_source,notnull"`
-	DeployedTime                  string                    `json:"deployed_time"`
-	Deployed                      bool                      `json:"deployed,notnull"`
-	Latest                        bool                      `json:"latest,notnull"`
-	LastSuccessfulTriggerOnParent bool                      `json:"lastSuccessfulTriggerOnParent,notnull"`
-	RunningOnParentCd             bool                      `json:"runningOnParentCd,omitempty"`
-	IsVulnerable                  bool                      `json:"vulnerable,notnull"`
-	ScanEnabled                   bool                      `json:"scanEnabled,notnull"`
-	Scanned                       bool                      `json:"scanned,notnull"`
-	WfrId                         int                       `json:"wfrId"`
-	DeployedBy                    string                    `json:"deployedBy"`
-	CiConfigureSourceType         pipelineConfig.SourceType `json:"ciConfigureSourceType"`
-	CiConfigureSourceValue        string                    `json:"ciConfigureSourceValue"`
-	ImageReleaseTags              []*repository2.ImageTag   `json:"imageReleaseTags"`
-	ImageComment                  *repository2.ImageComment `json:"imageComment"`
+	Id                            int                               `json:"id"`
+	Image                         string                            `json:"image,notnull"`
+	ImageDigest                   string                            `json:"image_digest,notnull"`
+	MaterialInfo                  json.RawMessage                   `json:"material_info"` //git material metadata json array string
+	DataSource                    string                            `json:"data_source,notnull"`
+	DeployedTime                  string                            `json:"deployed_time"`
+	Deployed                      bool                              `json:"deployed,notnull"`
+	Latest                        bool                              `json:"latest,notnull"`
+	LastSuccessfulTriggerOnParent bool                              `json:"lastSuccessfulTriggerOnParent,notnull"`
+	RunningOnParentCd             bool                              `json:"runningOnParentCd,omitempty"`
+	IsVulnerable                  bool                              `json:"vulnerable,notnull"`
+	ScanEnabled                   bool                              `json:"scanEnabled,notnull"`
+	Scanned                       bool                              `json:"scanned,notnull"`
+	WfrId                         int                               `json:"wfrId"`
+	DeployedBy                    string                            `json:"deployedBy"`
+	CiConfigureSourceType         pipelineConfig.SourceType         `json:"ciConfigureSourceType"`
+	CiConfigureSourceValue        string                            `json:"ciConfigureSourceValue"`
+	ImageReleaseTags              []*repository2.ImageTag           `json:"imageReleaseTags"`
+	ImageComment                  *repository2.ImageComment         `json:"imageComment"`
+	ManifestListDigest            string                            `json:"manifestListDigest,omitempty"`
+	PlatformDigests               []repository3.ImagePlatformDigest `json:"platformDigests,omitempty"`
 }
 
 type CiArtifactResponse struct {
@@ -812,13 +818,18 @@ type ExampleValueDto struct {
 type ManifestStorage = string
 
 const (
-	ManifestStorageGit ManifestStorage = "git"
+	ManifestStorageGit         ManifestStorage = "git"
+	ManifestStorageOCIHelmRepo ManifestStorage = "helm_repo"
 )
 
 func IsGitStorage(storageType string) bool {
 	return storageType == ManifestStorageGit
 }
 
+func IsOCIHelmRepoStorage(storageType string) bool {
+	return storageType == ManifestStorageOCIHelmRepo
+}
+
 const CustomAutoScalingEnabledPathKey = "CUSTOM_AUTOSCALING_ENABLED_PATH"
 const CustomAutoscalingReplicaCountPathKey = "CUSTOM_AUTOSCALING_REPLICA_COUNT_PATH"
 const CustomAutoscalingMinPathKey = "CUSTOM_AUTOSCALING_MIN_PATH"
@@ -18,6 +18,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appSync"
 	appWorkflow2 "github.com/devtron-labs/devtron/pkg/appWorkflow"
 	bean2 "github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/chart"
@@ -89,6 +90,8 @@ type BulkUpdateServiceImpl struct {
 	appWorkflowService               appWorkflow2.AppWorkflowService
 	pubsubClient                     *pubsub.PubSubClientServiceImpl
 	argoUserService                  argo.ArgoUserService
+	deploymentApprovalService        pipeline.DeploymentApprovalService
+	gitManagedAppService             appSync.GitManagedAppService
 }
 
 func NewBulkUpdateServiceImpl(bulkUpdateRepository bulkUpdate.BulkUpdateRepository,
@@ -118,7 +121,9 @@ func NewBulkUpdateServiceImpl(bulkUpdateRepository bulkUpdate.BulkUpdateReposito
 	appWorkflowRepository appWorkflow.AppWorkflowRepository,
 	appWorkflowService appWorkflow2.AppWorkflowService,
 	pubsubClient *pubsub.PubSubClientServiceImpl,
-	argoUserService argo.ArgoUserService) (*BulkUpdateServiceImpl, error) {
+	argoUserService argo.ArgoUserService,
+	deploymentApprovalService pipeline.DeploymentApprovalService,
+	gitManagedAppService appSync.GitManagedAppService) (*BulkUpdateServiceImpl, error) {
 	impl := &BulkUpdateServiceImpl{
 		bulkUpdateRepository:             bulkUpdateRepository,
 		chartRepository:                  chartRepository,
@@ -152,6 +157,8 @@ func NewBulkUpdateServiceImpl(bulkUpdateRepository bulkUpdate.BulkUpdateReposito
 		appWorkflowService:               appWorkflowService,
 		pubsubClient:                     pubsubClient,
 		argoUserService:                  argoUserService,
+		deploymentApprovalService:        deploymentApprovalService,
+		gitManagedAppService:             gitManagedAppService,
 	}
 
 	err := impl.SubscribeToCdBulkTriggerTopic()
@@ -413,6 +420,14 @@ func (impl BulkUpdateServiceImpl) BulkUpdateDeploymentTemplate(bulkUpdatePayload
 			} else {
 				for _, chart := range charts {
 					appDetailsByChart, _ := impl.bulkUpdateRepository.FindAppByChartId(chart.Id)
+					if message := impl.getNotEditableMessage(appDetailsByChart.Id); len(message) > 0 {
+						deploymentTemplateBulkUpdateResponse.Failure = append(deploymentTemplateBulkUpdateResponse.Failure, &DeploymentTemplateBulkUpdateResponseForOneApp{
+							AppId:   appDetailsByChart.Id,
+							AppName: appDetailsByChart.AppName,
+							Message: message,
+						})
+						continue
+					}
 					modified, err := impl.ApplyJsonPatch(deploymentTemplatePatch, chart.Values)
 					if err != nil {
 						impl.logger.Errorw("error in applying JSON patch", "err", err)
@@ -472,6 +487,15 @@ func (impl BulkUpdateServiceImpl) BulkUpdateDeploymentTemplate(bulkUpdatePayload
 			} else {
 				for _, chartEnv := range chartsEnv {
 					appDetailsByChart, _ := impl.bulkUpdateRepository.FindAppByChartEnvId(chartEnv.Id)
+					if message := impl.getNotEditableMessage(appDetailsByChart.Id); len(message) > 0 {
+						deploymentTemplateBulkUpdateResponse.Failure = append(deploymentTemplateBulkUpdateResponse.Failure, &DeploymentTemplateBulkUpdateResponseForOneApp{
+							AppId:   appDetailsByChart.Id,
+							AppName: appDetailsByChart.AppName,
+							EnvId:   envId,
+							Message: message,
+						})
+						continue
+					}
 					modified, err := impl.ApplyJsonPatch(deploymentTemplatePatch, chartEnv.EnvOverrideValues)
 					if err != nil {
 						impl.logger.Errorw("error in applying JSON patch", "err", err)
@@ -534,6 +558,15 @@ func (impl BulkUpdateServiceImpl) BulkUpdateDeploymentTemplate(bulkUpdatePayload
 	return deploymentTemplateBulkUpdateResponse
 }
 
+// getNotEditableMessage gives why the app is left out of bulk changes, apps managed from git are changed from git only
+func (impl BulkUpdateServiceImpl) getNotEditableMessage(appId int) string {
+	err := impl.gitManagedAppService.CheckAppEditable(appId)
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
 func (impl BulkUpdateServiceImpl) BulkUpdateConfigMap(bulkUpdatePayload *BulkUpdatePayload) *CmAndSecretBulkUpdateResponse {
 	configMapBulkUpdateResponse := &CmAndSecretBulkUpdateResponse{}
 	var appNameIncludes []string
@@ -562,6 +595,11 @@ func (impl BulkUpdateServiceImpl) BulkUpdateConfigMap(bulkUpdatePayload *BulkUpd
 				configMapBulkUpdateResponse.Message = append(configMapBulkUpdateResponse.Message, "No matching apps to update globally")
 			} else {
 				for _, configMapAppModel := range configMapAppModels {
+					if message := impl.getNotEditableMessage(configMapAppModel.AppId); len(message) > 0 {
+						appDetailsById, _ := impl.appRepository.FindById(configMapAppModel.AppId)
+						configMapBulkUpdateResponse.Failure = append(configMapBulkUpdateResponse.Failure, &CmAndSecretBulkUpdateResponseForOneApp{AppId: appDetailsById.Id, AppName: appDetailsById.AppName, Message: message})
+						continue
+					}
 					configMapNames := gjson.Get(configMapAppModel.ConfigMapData, "maps.#.name")
 					messageCmNamesMap := make(map[string][]string)
 					for i, configMapName := range configMapNames.Array() {
@@ -654,6 +692,11 @@ func (impl BulkUpdateServiceImpl) BulkUpdateConfigMap(bulkUpdatePayload *BulkUpd
 				configMapBulkUpdateResponse.Message = append(configMapBulkUpdateResponse.Message, fmt.Sprintf("No matching apps to update for envId : %d", envId))
 			} else {
 				for _, configMapEnvModel := range configMapEnvModels {
+					if message := impl.getNotEditableMessage(configMapEnvModel.AppId); len(message) > 0 {
+						appDetailsById, _ := impl.appRepository.FindById(configMapEnvModel.AppId)
+						configMapBulkUpdateResponse.Failure = append(configMapBulkUpdateResponse.Failure, &CmAndSecretBulkUpdateResponseForOneApp{AppId: appDetailsById.Id, AppName: appDetailsById.AppName, Message: message})
+						continue
+					}
 					configMapNames := gjson.Get(configMapEnvModel.ConfigMapData, "maps.#.name")
 					messageCmNamesMap := make(map[string][]string)
 					for i, configMapName := range configMapNames.Array() {
@@ -767,6 +810,11 @@ func (impl BulkUpdateServiceImpl) BulkUpdateSecret(bulkUpdatePayload *BulkUpdate
 				secretBulkUpdateResponse.Message = append(secretBulkUpdateResponse.Message, "No matching apps to update globally")
 			} else {
 				for _, secretAppModel := range secretAppModels {
+					if message := impl.getNotEditableMessage(secretAppModel.AppId); len(message) > 0 {
+						appDetailsById, _ := impl.appRepository.FindById(secretAppModel.AppId)
+						secretBulkUpdateResponse.Failure = append(secretBulkUpdateResponse.Failure, &CmAndSecretBulkUpdateResponseForOneApp{AppId: appDetailsById.Id, AppName: appDetailsById.AppName, Message: message})
+						continue
+					}
 					secretNames := gjson.Get(secretAppModel.SecretData, "secrets.#.name")
 					messageSecretNamesMap := make(map[string][]string)
 					for i, secretName := range secretNames.Array() {
@@ -865,6 +913,11 @@ func (impl BulkUpdateServiceImpl) BulkUpdateSecret(bulkUpdatePayload *BulkUpdate
 				secretBulkUpdateResponse.Message = append(secretBulkUpdateResponse.Message, fmt.Sprintf("No matching apps to update for envId : %d", envId))
 			} else {
 				for _, secretEnvModel := range secretEnvModels {
+					if message := impl.getNotEditableMessage(secretEnvModel.AppId); len(message) > 0 {
+						appDetailsById, _ := impl.appRepository.FindById(secretEnvModel.AppId)
+						secretBulkUpdateResponse.Failure = append(secretBulkUpdateResponse.Failure, &CmAndSecretBulkUpdateResponseForOneApp{AppId: appDetailsById.Id, AppName: appDetailsById.AppName, Message: message})
+						continue
+					}
 					secretNames := gjson.Get(secretEnvModel.SecretData, "secrets.#.name")
 					messageSecretNamesMap := make(map[string][]string)
 					for i, secretName := range secretNames.Array() {
@@ -1258,7 +1311,14 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(request *BulkApplicationForEnvironm
 			response[appKey] = pipelineResponse
 			continue
 		}
-		artifact := artifacts[0]
+		artifact, err := impl.getLatestDeployableArtifact(pipeline.Id, artifacts)
+		if err != nil || artifact == nil {
+			//no approved artifact found for this pipeline, skip cd trigger
+			pipelineResponse := response[appKey]
+			pipelineResponse[pipelineKey] = false
+			response[appKey] = pipelineResponse
+			continue
+		}
 		overrideRequest := &bean.ValuesOverrideRequest{
 			PipelineId:     pipeline.Id,
 			AppId:          pipeline.AppId,
@@ -1306,6 +1366,25 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(request *BulkApplicationForEnvironm
 	return bulkOperationResponse, nil
 }
 
+// getLatestDeployableArtifact returns the latest artifact, or the latest approved one if the pipeline needs deployment approval
+func (impl BulkUpdateServiceImpl) getLatestDeployableArtifact(pipelineId int, artifacts []bean2.CiArtifactBean) (*bean2.CiArtifactBean, error) {
+	var artifactIds []int
+	for _, artifact := range artifacts {
+		artifactIds = append(artifactIds, artifact.Id)
+	}
+	approvalRequired, approvedArtifactIds, err := impl.deploymentApprovalService.GetApprovedArtifactIds(pipelineId, artifactIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approved artifacts", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	for i := range artifacts {
+		if !approvalRequired || approvedArtifactIds[artifacts[i].Id] {
+			return &artifacts[i], nil
+		}
+	}
+	return nil, nil
+}
+
 func (impl BulkUpdateServiceImpl) SubscribeToCdBulkTriggerTopic() error {
 
 	callback := func(msg *pubsub.PubSubMsg) {
@@ -1589,7 +1668,9 @@ func (impl BulkUpdateServiceImpl) PerformBulkDeleteActionOnCdPipelines(impactedP
 			AppName:         pipeline.App.AppName,
 			EnvironmentName: pipeline.Environment.Name,
 		}
-		if !dryRun {
+		if message := impl.getNotEditableMessage(pipeline.AppId); len(message) > 0 {
+			respDto.DeletionResult = fmt.Sprintf("Not able to delete pipeline, %s", message)
+		} else if !dryRun {
 			// Delete Cd pipeline
 			deleteResponse, err := impl.pipelineBuilder.DeleteCdPipeline(pipeline, ctx, deleteAction, true, userId)
 			if err != nil {
@@ -1614,7 +1695,9 @@ func (impl BulkUpdateServiceImpl) PerformBulkDeleteActionOnCdPipelines(impactedP
 			respDto := &CiBulkActionResponseDto{
 				PipelineName: ciPipeline.Name,
 			}
-			if !dryRun {
+			if message := impl.getNotEditableMessage(ciPipeline.AppId); len(message) > 0 {
+				respDto.DeletionResult = fmt.Sprintf("Not able to delete pipeline, %s", message)
+			} else if !dryRun {
 				deleteReq := &bean2.CiPatchRequest{
 					Action:     2, //delete
 					CiPipeline: ciPipeline,
@@ -1635,7 +1718,14 @@ func (impl BulkUpdateServiceImpl) PerformBulkDeleteActionOnCdPipelines(impactedP
 			respDto := &WfBulkActionResponseDto{
 				WorkflowId: impactedAppWfId,
 			}
-			if !dryRun {
+			appWf, err := impl.appWorkflowRepository.FindById(impactedAppWfId)
+			if err != nil {
+				impl.logger.Errorw("error in getting appWf by id", "err", err, "id", impactedAppWfId)
+				return nil, err
+			}
+			if message := impl.getNotEditableMessage(appWf.AppId); len(message) > 0 {
+				respDto.DeletionResult = fmt.Sprintf("Not able to delete workflow, %s", message)
+			} else if !dryRun {
 				err := impl.appWorkflowService.DeleteAppWorkflow(impactedAppWfId, userId)
 				if err != nil {
 					impl.logger.Errorw("error in deleting appWf", "err", err, "appWfId", impactedAppWfId)
@@ -41,6 +41,7 @@ type Cluster struct {
 	ErrorInConnecting      string            `sql:"error_in_connecting"`
 	IsVirtualCluster       bool              `sql:"is_virtual_cluster"`
 	InsecureSkipTlsVerify  bool              `sql:"insecure_skip_tls_verify"`
+	WorkflowExecutorType   string            `sql:"workflow_executor_type"`
 	sql.AuditLog
 }
 
@@ -595,10 +595,12 @@ func (impl *DeploymentGroupServiceImpl) GetArtifactsByCiPipeline(ciPipelineId in
 		}
 
 		ciArtifacts = append(ciArtifacts, bean.CiArtifactBean{
-			Id:           artifact.Id,
-			Image:        artifact.Image,
-			MaterialInfo: mInfo,
-			Latest:       artifact.Latest,
+			Id:                 artifact.Id,
+			Image:              artifact.Image,
+			MaterialInfo:       mInfo,
+			Latest:             artifact.Latest,
+			ManifestListDigest: artifact.ManifestListDigest,
+			PlatformDigests:    artifact.PlatformDigests,
 		})
 	}
 
@@ -0,0 +1,92 @@
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type WindowType string
+
+const (
+	WINDOW_TYPE_ALLOWED  WindowType = "ALLOWED"
+	WINDOW_TYPE_BLACKOUT WindowType = "BLACKOUT"
+)
+
+// DeploymentWindow is either a recurring time range (weekdays + start/end time of day in a timezone),
+// an absolute range (start_at/end_at) or both, in which case the recurring range applies only within the absolute one
+type DeploymentWindow struct {
+	tableName     struct{}   `sql:"deployment_window" pg:",discard_unknown_columns"`
+	Id            int        `sql:"id,pk"`
+	EnvironmentId int        `sql:"environment_id,notnull"`
+	Name          string     `sql:"name,notnull"`
+	WindowType    WindowType `sql:"window_type,notnull"`
+	Weekdays      []int      `sql:"weekdays" pg:",array"`
+	StartTime     string     `sql:"start_time"`
+	EndTime       string     `sql:"end_time"`
+	Timezone      string     `sql:"timezone"`
+	StartAt       time.Time  `sql:"start_at"`
+	EndAt         time.Time  `sql:"end_at"`
+	Description   string     `sql:"description"`
+	Active        bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentWindowOverrideAudit struct {
+	tableName     struct{}  `sql:"deployment_window_override_audit" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	PipelineId    int       `sql:"pipeline_id,notnull"`
+	EnvironmentId int       `sql:"environment_id,notnull"`
+	CiArtifactId  int       `sql:"ci_artifact_id"`
+	BlockedReason string    `sql:"blocked_reason"`
+	Reason        string    `sql:"reason"`
+	OverriddenBy  int32     `sql:"overridden_by,notnull"`
+	OverriddenOn  time.Time `sql:"overridden_on,notnull"`
+}
+
+type DeploymentWindowRepository interface {
+	Save(window *DeploymentWindow) error
+	Update(window *DeploymentWindow) error
+	FindById(id int) (*DeploymentWindow, error)
+	FindActiveByEnvironmentId(envId int) ([]*DeploymentWindow, error)
+	SaveOverrideAudit(audit *DeploymentWindowOverrideAudit) error
+}
+
+type DeploymentWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentWindowRepositoryImpl(dbConnection *pg.DB) *DeploymentWindowRepositoryImpl {
+	return &DeploymentWindowRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentWindowRepositoryImpl) Save(window *DeploymentWindow) error {
+	return impl.dbConnection.Insert(window)
+}
+
+func (impl DeploymentWindowRepositoryImpl) Update(window *DeploymentWindow) error {
+	return impl.dbConnection.Update(window)
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindById(id int) (*DeploymentWindow, error) {
+	window := &DeploymentWindow{}
+	err := impl.dbConnection.Model(window).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return window, err
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindActiveByEnvironmentId(envId int) ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := impl.dbConnection.Model(&windows).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return windows, err
+}
+
+func (impl DeploymentWindowRepositoryImpl) SaveOverrideAudit(audit *DeploymentWindowOverrideAudit) error {
+	return impl.dbConnection.Insert(audit)
+}
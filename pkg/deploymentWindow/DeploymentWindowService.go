@@ -0,0 +1,541 @@
+package deploymentWindow
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	DEFAULT_WINDOW_TIMEZONE = "UTC"
+	TIME_OF_DAY_LAYOUT      = "15:04"
+	// how far ahead recurring windows are expanded while looking for the next open slot
+	NEXT_OPEN_LOOKAHEAD_DAYS = 8
+	MAX_LOOKAHEAD_DAYS       = 400
+
+	QUEUED_DEPLOYMENT_SUPERSEDED_MESSAGE = "superseded by a newer artifact"
+)
+
+type DeploymentWindowDto struct {
+	Id            int        `json:"id"`
+	EnvironmentId int        `json:"environmentId" validate:"required"`
+	Name          string     `json:"name" validate:"required"`
+	WindowType    WindowType `json:"windowType" validate:"oneof=ALLOWED BLACKOUT"`
+	Weekdays      []int      `json:"weekdays"`  //0 is Sunday, empty means every day
+	StartTime     string     `json:"startTime"` //HH:MM
+	EndTime       string     `json:"endTime"`   //HH:MM, lower than startTime if window spans midnight
+	Timezone      string     `json:"timezone"`
+	StartAt       *time.Time `json:"startAt,omitempty"`
+	EndAt         *time.Time `json:"endAt,omitempty"`
+	Description   string     `json:"description"`
+	UserId        int32      `json:"-"`
+}
+
+type DeploymentWindowState struct {
+	EnvironmentId int        `json:"environmentId"`
+	Allowed       bool       `json:"allowed"`
+	Reason        string     `json:"reason,omitempty"`
+	NextOpenAt    *time.Time `json:"nextOpenAt,omitempty"`
+}
+
+type ScheduledDeploymentDto struct {
+	Id             int                       `json:"id"`
+	AppId          int                       `json:"appId" validate:"required"`
+	PipelineId     int                       `json:"pipelineId" validate:"required"`
+	CiArtifactId   int                       `json:"ciArtifactId" validate:"required"`
+	ScheduledAt    time.Time                 `json:"scheduledAt" validate:"required"`
+	DeploymentType ScheduledDeploymentType   `json:"deploymentType"`
+	Status         ScheduledDeploymentStatus `json:"status"`
+	Message        string                    `json:"message"`
+	TriggeredBy    int32                     `json:"triggeredBy"`
+	UserId         int32                     `json:"-"`
+}
+
+type DeploymentWindowService interface {
+	CreateWindow(request *DeploymentWindowDto) (*DeploymentWindowDto, error)
+	UpdateWindow(request *DeploymentWindowDto) (*DeploymentWindowDto, error)
+	DeleteWindow(id int, userId int32) error
+	GetWindows(envId int) ([]*DeploymentWindowDto, error)
+	GetDeploymentWindowState(envId int, at time.Time) (*DeploymentWindowState, error)
+	SaveOverrideAudit(pipelineId int, envId int, ciArtifactId int, blockedReason string, reason string, userId int32) error
+
+	ScheduleDeployment(request *ScheduledDeploymentDto) (*ScheduledDeploymentDto, error)
+	// QueueDeployment holds back an automatic deployment till scheduledAt, only the latest queued artifact of a pipeline is kept
+	QueueDeployment(pipelineId int, ciArtifactId int, cdWorkflowId int, scheduledAt time.Time, triggeredBy int32) error
+	GetPendingScheduledDeployments(pipelineId int) ([]*ScheduledDeploymentDto, error)
+	GetScheduledDeploymentById(id int) (*ScheduledDeployment, error)
+	CancelScheduledDeployment(id int, userId int32) error
+	// ClaimDueScheduledDeployments atomically moves due deployments to in progress, only the replica which claimed a
+	// deployment gets it back
+	ClaimDueScheduledDeployments(now time.Time) ([]*ScheduledDeployment, error)
+	// UpdateScheduledDeploymentStatus saves the outcome of a claimed deployment
+	UpdateScheduledDeploymentStatus(scheduledDeployment *ScheduledDeployment, status ScheduledDeploymentStatus, message string) error
+	// RescheduleDeployment puts a claimed deployment back in the queue for scheduledAt
+	RescheduleDeployment(scheduledDeployment *ScheduledDeployment, scheduledAt time.Time, message string) error
+}
+
+type DeploymentWindowServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	deploymentWindowRepository    DeploymentWindowRepository
+	scheduledDeploymentRepository ScheduledDeploymentRepository
+}
+
+func NewDeploymentWindowServiceImpl(logger *zap.SugaredLogger, deploymentWindowRepository DeploymentWindowRepository,
+	scheduledDeploymentRepository ScheduledDeploymentRepository) *DeploymentWindowServiceImpl {
+	return &DeploymentWindowServiceImpl{
+		logger:                        logger,
+		deploymentWindowRepository:    deploymentWindowRepository,
+		scheduledDeploymentRepository: scheduledDeploymentRepository,
+	}
+}
+
+func (impl *DeploymentWindowServiceImpl) CreateWindow(request *DeploymentWindowDto) (*DeploymentWindowDto, error) {
+	err := validateWindow(request)
+	if err != nil {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	now := time.Now()
+	window := &DeploymentWindow{
+		Active:   true,
+		AuditLog: sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	updateWindowFromDto(window, request)
+	err = impl.deploymentWindowRepository.Save(window)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window", "err", err, "window", window)
+		return nil, err
+	}
+	return adaptDeploymentWindow(window), nil
+}
+
+func (impl *DeploymentWindowServiceImpl) UpdateWindow(request *DeploymentWindowDto) (*DeploymentWindowDto, error) {
+	err := validateWindow(request)
+	if err != nil {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	window, err := impl.deploymentWindowRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", request.Id)
+		return nil, err
+	}
+	updateWindowFromDto(window, request)
+	window.UpdatedOn = time.Now()
+	window.UpdatedBy = request.UserId
+	err = impl.deploymentWindowRepository.Update(window)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment window", "err", err, "window", window)
+		return nil, err
+	}
+	return adaptDeploymentWindow(window), nil
+}
+
+func (impl *DeploymentWindowServiceImpl) DeleteWindow(id int, userId int32) error {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", id)
+		return err
+	}
+	window.Active = false
+	window.UpdatedOn = time.Now()
+	window.UpdatedBy = userId
+	err = impl.deploymentWindowRepository.Update(window)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment window", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetWindows(envId int) ([]*DeploymentWindowDto, error) {
+	windows, err := impl.deploymentWindowRepository.FindActiveByEnvironmentId(envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err, "envId", envId)
+		return nil, err
+	}
+	dtos := make([]*DeploymentWindowDto, 0, len(windows))
+	for _, window := range windows {
+		dtos = append(dtos, adaptDeploymentWindow(window))
+	}
+	return dtos, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetDeploymentWindowState(envId int, at time.Time) (*DeploymentWindowState, error) {
+	windows, err := impl.deploymentWindowRepository.FindActiveByEnvironmentId(envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err, "envId", envId)
+		return nil, err
+	}
+	state := evaluateDeploymentWindows(windows, at)
+	state.EnvironmentId = envId
+	return state, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) SaveOverrideAudit(pipelineId int, envId int, ciArtifactId int, blockedReason string, reason string, userId int32) error {
+	audit := &DeploymentWindowOverrideAudit{
+		PipelineId:    pipelineId,
+		EnvironmentId: envId,
+		CiArtifactId:  ciArtifactId,
+		BlockedReason: blockedReason,
+		Reason:        reason,
+		OverriddenBy:  userId,
+		OverriddenOn:  time.Now(),
+	}
+	err := impl.deploymentWindowRepository.SaveOverrideAudit(audit)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window override audit", "err", err, "audit", audit)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) ScheduleDeployment(request *ScheduledDeploymentDto) (*ScheduledDeploymentDto, error) {
+	now := time.Now()
+	if !request.ScheduledAt.After(now) {
+		errMsg := "scheduled time must be in future"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	scheduledDeployment := &ScheduledDeployment{
+		PipelineId:     request.PipelineId,
+		CiArtifactId:   request.CiArtifactId,
+		DeploymentType: SCHEDULED_DEPLOYMENT_TYPE_SCHEDULED,
+		ScheduledAt:    request.ScheduledAt,
+		Status:         SCHEDULED_DEPLOYMENT_STATUS_PENDING,
+		TriggeredBy:    request.UserId,
+		AuditLog:       sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	err := impl.scheduledDeploymentRepository.Save(scheduledDeployment)
+	if err != nil {
+		impl.logger.Errorw("error in saving scheduled deployment", "err", err, "scheduledDeployment", scheduledDeployment)
+		return nil, err
+	}
+	return adaptScheduledDeployment(scheduledDeployment), nil
+}
+
+func (impl *DeploymentWindowServiceImpl) QueueDeployment(pipelineId int, ciArtifactId int, cdWorkflowId int, scheduledAt time.Time, triggeredBy int32) error {
+	now := time.Now()
+	scheduledDeployment := &ScheduledDeployment{
+		PipelineId:     pipelineId,
+		CiArtifactId:   ciArtifactId,
+		CdWorkflowId:   cdWorkflowId,
+		DeploymentType: SCHEDULED_DEPLOYMENT_TYPE_QUEUED,
+		ScheduledAt:    scheduledAt,
+		Status:         SCHEDULED_DEPLOYMENT_STATUS_PENDING,
+		TriggeredBy:    triggeredBy,
+		AuditLog:       sql.AuditLog{CreatedOn: now, CreatedBy: triggeredBy, UpdatedOn: now, UpdatedBy: triggeredBy},
+	}
+	err := impl.scheduledDeploymentRepository.SaveQueued(scheduledDeployment, QUEUED_DEPLOYMENT_SUPERSEDED_MESSAGE)
+	if err != nil {
+		impl.logger.Errorw("error in queueing deployment", "err", err, "scheduledDeployment", scheduledDeployment)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetPendingScheduledDeployments(pipelineId int) ([]*ScheduledDeploymentDto, error) {
+	scheduledDeployments, err := impl.scheduledDeploymentRepository.FindPendingByPipelineId(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching scheduled deployments", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	dtos := make([]*ScheduledDeploymentDto, 0, len(scheduledDeployments))
+	for _, scheduledDeployment := range scheduledDeployments {
+		dtos = append(dtos, adaptScheduledDeployment(scheduledDeployment))
+	}
+	return dtos, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetScheduledDeploymentById(id int) (*ScheduledDeployment, error) {
+	return impl.scheduledDeploymentRepository.FindById(id)
+}
+
+func (impl *DeploymentWindowServiceImpl) CancelScheduledDeployment(id int, userId int32) error {
+	scheduledDeployment, err := impl.scheduledDeploymentRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching scheduled deployment", "err", err, "id", id)
+		return err
+	}
+	if scheduledDeployment.Status != SCHEDULED_DEPLOYMENT_STATUS_PENDING {
+		errMsg := fmt.Sprintf("deployment cannot be cancelled in %s state", scheduledDeployment.Status)
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	scheduledDeployment.Message = "cancelled by user"
+	scheduledDeployment.UpdatedOn = time.Now()
+	scheduledDeployment.UpdatedBy = userId
+	cancelled, err := impl.scheduledDeploymentRepository.CancelPending(scheduledDeployment)
+	if err != nil {
+		impl.logger.Errorw("error in cancelling scheduled deployment", "err", err, "id", id)
+		return err
+	}
+	if !cancelled {
+		errMsg := "deployment cannot be cancelled as it is already being triggered"
+		return &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	scheduledDeployment.Status = SCHEDULED_DEPLOYMENT_STATUS_CANCELLED
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) ClaimDueScheduledDeployments(now time.Time) ([]*ScheduledDeployment, error) {
+	scheduledDeployments, err := impl.scheduledDeploymentRepository.ClaimDue(now)
+	if err != nil {
+		impl.logger.Errorw("error in claiming due scheduled deployments", "err", err)
+		return nil, err
+	}
+	return scheduledDeployments, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) UpdateScheduledDeploymentStatus(scheduledDeployment *ScheduledDeployment, status ScheduledDeploymentStatus, message string) error {
+	scheduledDeployment.Status = status
+	scheduledDeployment.Message = message
+	scheduledDeployment.UpdatedOn = time.Now()
+	updated, err := impl.scheduledDeploymentRepository.UpdateInProgress(scheduledDeployment)
+	if err != nil {
+		impl.logger.Errorw("error in updating scheduled deployment", "err", err, "id", scheduledDeployment.Id, "status", status)
+		return err
+	}
+	if !updated {
+		impl.logger.Warnw("scheduled deployment not in progress anymore, status not updated", "id", scheduledDeployment.Id, "status", status)
+	}
+	return nil
+}
+
+func (impl *DeploymentWindowServiceImpl) RescheduleDeployment(scheduledDeployment *ScheduledDeployment, scheduledAt time.Time, message string) error {
+	scheduledDeployment.ScheduledAt = scheduledAt
+	scheduledDeployment.Message = message
+	requeued, err := impl.scheduledDeploymentRepository.RequeueInProgress(scheduledDeployment, QUEUED_DEPLOYMENT_SUPERSEDED_MESSAGE)
+	if err != nil {
+		impl.logger.Errorw("error in rescheduling deployment", "err", err, "id", scheduledDeployment.Id)
+		return err
+	}
+	if !requeued {
+		impl.logger.Infow("scheduled deployment not requeued", "id", scheduledDeployment.Id, "status", scheduledDeployment.Status)
+	}
+	return nil
+}
+
+// evaluateDeploymentWindows checks if deployment is allowed at the given time; any active blackout blocks the deployment
+// and when allowed windows are configured the time must fall in at least one of them
+func evaluateDeploymentWindows(windows []*DeploymentWindow, at time.Time) *DeploymentWindowState {
+	reason, blocked := getBlockedReason(windows, at)
+	if !blocked {
+		return &DeploymentWindowState{Allowed: true}
+	}
+	state := &DeploymentWindowState{Allowed: false, Reason: reason}
+	for _, candidate := range getWindowBoundaries(windows, at) {
+		if _, blocked := getBlockedReason(windows, candidate); !blocked {
+			nextOpenAt := candidate
+			state.NextOpenAt = &nextOpenAt
+			break
+		}
+	}
+	return state
+}
+
+func getBlockedReason(windows []*DeploymentWindow, at time.Time) (string, bool) {
+	hasAllowedWindow := false
+	inAllowedWindow := false
+	for _, window := range windows {
+		covered := isTimeInWindow(window, at)
+		if window.WindowType == WINDOW_TYPE_BLACKOUT && covered {
+			return fmt.Sprintf("blackout period '%s' is active", window.Name), true
+		}
+		if window.WindowType == WINDOW_TYPE_ALLOWED {
+			hasAllowedWindow = true
+			inAllowedWindow = inAllowedWindow || covered
+		}
+	}
+	if hasAllowedWindow && !inAllowedWindow {
+		return "outside of allowed deployment windows", true
+	}
+	return "", false
+}
+
+func isTimeInWindow(window *DeploymentWindow, at time.Time) bool {
+	if !window.StartAt.IsZero() && at.Before(window.StartAt) {
+		return false
+	}
+	if !window.EndAt.IsZero() && !at.Before(window.EndAt) {
+		return false
+	}
+	if len(window.StartTime) == 0 {
+		return true
+	}
+	loc, err := time.LoadLocation(getTimezone(window.Timezone))
+	if err != nil {
+		return false
+	}
+	startMinute, _ := parseTimeOfDay(window.StartTime)
+	endMinute, _ := parseTimeOfDay(window.EndTime)
+	local := at.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if startMinute == endMinute {
+		return isWeekdayIncluded(window.Weekdays, local.Weekday())
+	}
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute && isWeekdayIncluded(window.Weekdays, local.Weekday())
+	}
+	// window spans midnight, the part after midnight belongs to the previous day's window
+	if minute >= startMinute {
+		return isWeekdayIncluded(window.Weekdays, local.Weekday())
+	}
+	if minute < endMinute {
+		return isWeekdayIncluded(window.Weekdays, local.AddDate(0, 0, -1).Weekday())
+	}
+	return false
+}
+
+// getWindowBoundaries returns sorted points in time after the given time at which the result of the evaluation can change
+func getWindowBoundaries(windows []*DeploymentWindow, at time.Time) []time.Time {
+	var boundaries []time.Time
+	lookaheadEnd := at.AddDate(0, 0, NEXT_OPEN_LOOKAHEAD_DAYS)
+	for _, window := range windows {
+		for _, boundary := range []time.Time{window.StartAt, window.EndAt} {
+			if !boundary.IsZero() && boundary.After(at) {
+				boundaries = append(boundaries, boundary)
+				if boundary.AddDate(0, 0, NEXT_OPEN_LOOKAHEAD_DAYS).After(lookaheadEnd) {
+					lookaheadEnd = boundary.AddDate(0, 0, NEXT_OPEN_LOOKAHEAD_DAYS)
+				}
+			}
+		}
+	}
+	if maxEnd := at.AddDate(0, 0, MAX_LOOKAHEAD_DAYS); lookaheadEnd.After(maxEnd) {
+		lookaheadEnd = maxEnd
+	}
+	for _, window := range windows {
+		if len(window.StartTime) == 0 {
+			continue
+		}
+		loc, err := time.LoadLocation(getTimezone(window.Timezone))
+		if err != nil {
+			continue
+		}
+		startMinute, _ := parseTimeOfDay(window.StartTime)
+		endMinute, _ := parseTimeOfDay(window.EndTime)
+		local := at.In(loc)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		for ; !day.After(lookaheadEnd); day = day.AddDate(0, 0, 1) {
+			for _, minute := range []int{startMinute, endMinute} {
+				boundary := day.Add(time.Duration(minute) * time.Minute)
+				if boundary.After(at) {
+					boundaries = append(boundaries, boundary)
+				}
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+	return boundaries
+}
+
+func isWeekdayIncluded(weekdays []int, weekday time.Weekday) bool {
+	if len(weekdays) == 0 {
+		return true
+	}
+	for _, day := range weekdays {
+		if day == int(weekday) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse(TIME_OF_DAY_LAYOUT, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func getTimezone(timezone string) string {
+	if len(timezone) == 0 {
+		return DEFAULT_WINDOW_TIMEZONE
+	}
+	return timezone
+}
+
+func validateWindow(request *DeploymentWindowDto) error {
+	isRecurring := len(request.StartTime) > 0 || len(request.EndTime) > 0
+	if !isRecurring && request.StartAt == nil && request.EndAt == nil {
+		return fmt.Errorf("either time of day range or start/end time is required")
+	}
+	if isRecurring {
+		if _, err := parseTimeOfDay(request.StartTime); err != nil {
+			return fmt.Errorf("invalid start time %s, expected HH:MM", request.StartTime)
+		}
+		if _, err := parseTimeOfDay(request.EndTime); err != nil {
+			return fmt.Errorf("invalid end time %s, expected HH:MM", request.EndTime)
+		}
+	}
+	if request.StartAt != nil && request.EndAt != nil && !request.EndAt.After(*request.StartAt) {
+		return fmt.Errorf("end time must be after start time")
+	}
+	for _, day := range request.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("invalid weekday %d, expected 0 (Sunday) to 6 (Saturday)", day)
+		}
+	}
+	if _, err := time.LoadLocation(getTimezone(request.Timezone)); err != nil {
+		return fmt.Errorf("invalid timezone %s", request.Timezone)
+	}
+	return nil
+}
+
+func updateWindowFromDto(window *DeploymentWindow, request *DeploymentWindowDto) {
+	window.EnvironmentId = request.EnvironmentId
+	window.Name = request.Name
+	window.WindowType = request.WindowType
+	window.Weekdays = request.Weekdays
+	window.StartTime = request.StartTime
+	window.EndTime = request.EndTime
+	window.Timezone = getTimezone(request.Timezone)
+	window.StartAt = time.Time{}
+	if request.StartAt != nil {
+		window.StartAt = *request.StartAt
+	}
+	window.EndAt = time.Time{}
+	if request.EndAt != nil {
+		window.EndAt = *request.EndAt
+	}
+	window.Description = request.Description
+}
+
+func adaptDeploymentWindow(window *DeploymentWindow) *DeploymentWindowDto {
+	dto := &DeploymentWindowDto{
+		Id:            window.Id,
+		EnvironmentId: window.EnvironmentId,
+		Name:          window.Name,
+		WindowType:    window.WindowType,
+		Weekdays:      window.Weekdays,
+		StartTime:     window.StartTime,
+		EndTime:       window.EndTime,
+		Timezone:      window.Timezone,
+		Description:   window.Description,
+	}
+	if !window.StartAt.IsZero() {
+		startAt := window.StartAt
+		dto.StartAt = &startAt
+	}
+	if !window.EndAt.IsZero() {
+		endAt := window.EndAt
+		dto.EndAt = &endAt
+	}
+	return dto
+}
+
+func adaptScheduledDeployment(scheduledDeployment *ScheduledDeployment) *ScheduledDeploymentDto {
+	return &ScheduledDeploymentDto{
+		Id:             scheduledDeployment.Id,
+		PipelineId:     scheduledDeployment.PipelineId,
+		CiArtifactId:   scheduledDeployment.CiArtifactId,
+		ScheduledAt:    scheduledDeployment.ScheduledAt,
+		DeploymentType: scheduledDeployment.DeploymentType,
+		Status:         scheduledDeployment.Status,
+		Message:        scheduledDeployment.Message,
+		TriggeredBy:    scheduledDeployment.TriggeredBy,
+	}
+}
@@ -0,0 +1,106 @@
+package deploymentWindow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEvaluateDeploymentWindows(t *testing.T) {
+	// office hours on weekdays in IST, 09:30 IST is 04:00 UTC and 18:30 IST is 13:00 UTC
+	officeHours := &DeploymentWindow{
+		Name:       "office-hours",
+		WindowType: WINDOW_TYPE_ALLOWED,
+		Weekdays:   []int{1, 2, 3, 4, 5},
+		StartTime:  "09:30",
+		EndTime:    "18:30",
+		Timezone:   "Asia/Kolkata",
+	}
+	releaseFreeze := &DeploymentWindow{
+		Name:       "release-freeze",
+		WindowType: WINDOW_TYPE_BLACKOUT,
+		StartAt:    time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC),
+		EndAt:      time.Date(2023, 6, 8, 6, 0, 0, 0, time.UTC),
+	}
+	nightlyMaintenance := &DeploymentWindow{
+		Name:       "nightly",
+		WindowType: WINDOW_TYPE_ALLOWED,
+		StartTime:  "22:00",
+		EndTime:    "02:00",
+		Timezone:   "UTC",
+		Weekdays:   []int{5},
+	}
+	tests := []struct {
+		name           string
+		windows        []*DeploymentWindow
+		at             time.Time
+		wantAllowed    bool
+		wantNextOpenAt *time.Time
+	}{
+		{
+			name:        "no windows configured",
+			at:          time.Date(2023, 6, 5, 20, 0, 0, 0, time.UTC),
+			wantAllowed: true,
+		},
+		{
+			name:        "inside allowed window",
+			windows:     []*DeploymentWindow{officeHours},
+			at:          time.Date(2023, 6, 5, 5, 0, 0, 0, time.UTC), //Monday
+			wantAllowed: true,
+		},
+		{
+			name:           "outside allowed window on friday evening",
+			windows:        []*DeploymentWindow{officeHours},
+			at:             time.Date(2023, 6, 9, 14, 0, 0, 0, time.UTC), //Friday
+			wantAllowed:    false,
+			wantNextOpenAt: timePtr(time.Date(2023, 6, 12, 4, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:           "blackout over allowed window",
+			windows:        []*DeploymentWindow{officeHours, releaseFreeze},
+			at:             time.Date(2023, 6, 7, 5, 0, 0, 0, time.UTC), //Wednesday
+			wantAllowed:    false,
+			wantNextOpenAt: timePtr(time.Date(2023, 6, 8, 6, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:        "window spanning midnight belongs to previous day",
+			windows:     []*DeploymentWindow{nightlyMaintenance},
+			at:          time.Date(2023, 6, 10, 1, 0, 0, 0, time.UTC), //Saturday
+			wantAllowed: true,
+		},
+		{
+			name:           "window spanning midnight does not start on other days",
+			windows:        []*DeploymentWindow{nightlyMaintenance},
+			at:             time.Date(2023, 6, 11, 1, 0, 0, 0, time.UTC), //Sunday
+			wantAllowed:    false,
+			wantNextOpenAt: timePtr(time.Date(2023, 6, 16, 22, 0, 0, 0, time.UTC)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := evaluateDeploymentWindows(tt.windows, tt.at)
+			assert.Equal(t, tt.wantAllowed, state.Allowed)
+			if tt.wantNextOpenAt == nil {
+				assert.Nil(t, state.NextOpenAt)
+			} else {
+				assert.NotNil(t, state.NextOpenAt)
+				assert.True(t, tt.wantNextOpenAt.Equal(*state.NextOpenAt), "got %s", state.NextOpenAt)
+			}
+		})
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	startAt := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)
+	endAt := startAt.Add(-time.Hour)
+	assert.NotNil(t, validateWindow(&DeploymentWindowDto{Name: "empty", WindowType: WINDOW_TYPE_ALLOWED}))
+	assert.NotNil(t, validateWindow(&DeploymentWindowDto{Name: "bad-time", StartTime: "25:00", EndTime: "10:00"}))
+	assert.NotNil(t, validateWindow(&DeploymentWindowDto{Name: "bad-range", StartAt: &startAt, EndAt: &endAt}))
+	assert.NotNil(t, validateWindow(&DeploymentWindowDto{Name: "bad-day", StartTime: "09:00", EndTime: "10:00", Weekdays: []int{7}}))
+	assert.NotNil(t, validateWindow(&DeploymentWindowDto{Name: "bad-tz", StartTime: "09:00", EndTime: "10:00", Timezone: "Mars/Olympus"}))
+	assert.Nil(t, validateWindow(&DeploymentWindowDto{Name: "ok", StartTime: "09:00", EndTime: "10:00", Timezone: "Europe/Berlin"}))
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
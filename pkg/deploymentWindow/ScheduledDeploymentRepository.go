@@ -0,0 +1,221 @@
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"sort"
+	"time"
+)
+
+type ScheduledDeploymentType string
+
+const (
+	// SCHEDULED_DEPLOYMENT_TYPE_SCHEDULED is a user requested deployment of an artifact at a given time
+	SCHEDULED_DEPLOYMENT_TYPE_SCHEDULED ScheduledDeploymentType = "SCHEDULED"
+	// SCHEDULED_DEPLOYMENT_TYPE_QUEUED is an automatic deployment held back until the deployment window opens
+	SCHEDULED_DEPLOYMENT_TYPE_QUEUED ScheduledDeploymentType = "QUEUED"
+)
+
+type ScheduledDeploymentStatus string
+
+const (
+	SCHEDULED_DEPLOYMENT_STATUS_PENDING     ScheduledDeploymentStatus = "PENDING"
+	SCHEDULED_DEPLOYMENT_STATUS_IN_PROGRESS ScheduledDeploymentStatus = "IN_PROGRESS"
+	SCHEDULED_DEPLOYMENT_STATUS_TRIGGERED   ScheduledDeploymentStatus = "TRIGGERED"
+	SCHEDULED_DEPLOYMENT_STATUS_FAILED      ScheduledDeploymentStatus = "FAILED"
+	SCHEDULED_DEPLOYMENT_STATUS_CANCELLED   ScheduledDeploymentStatus = "CANCELLED"
+)
+
+type ScheduledDeployment struct {
+	tableName      struct{}                  `sql:"scheduled_deployment" pg:",discard_unknown_columns"`
+	Id             int                       `sql:"id,pk"`
+	PipelineId     int                       `sql:"pipeline_id,notnull"`
+	CiArtifactId   int                       `sql:"ci_artifact_id,notnull"`
+	CdWorkflowId   int                       `sql:"cd_workflow_id"`
+	DeploymentType ScheduledDeploymentType   `sql:"deployment_type,notnull"`
+	ScheduledAt    time.Time                 `sql:"scheduled_at,notnull"`
+	Status         ScheduledDeploymentStatus `sql:"status,notnull"`
+	Message        string                    `sql:"message"`
+	TriggeredBy    int32                     `sql:"triggered_by,notnull"`
+	sql.AuditLog
+}
+
+type ScheduledDeploymentRepository interface {
+	Save(scheduledDeployment *ScheduledDeployment) error
+	Update(scheduledDeployment *ScheduledDeployment) error
+	FindById(id int) (*ScheduledDeployment, error)
+	FindPendingByPipelineId(pipelineId int) ([]*ScheduledDeployment, error)
+	// ClaimDue moves the pending deployments whose time has come to in progress in a single statement and returns them,
+	// rows being claimed by another replica are skipped so that every deployment is triggered once
+	ClaimDue(now time.Time) ([]*ScheduledDeployment, error)
+	// UpdateInProgress saves the status of a claimed deployment, returns false if it is no longer in progress
+	UpdateInProgress(scheduledDeployment *ScheduledDeployment) (bool, error)
+	// CancelPending cancels a deployment which is not claimed yet, returns false if it is no longer pending
+	CancelPending(scheduledDeployment *ScheduledDeployment) (bool, error)
+	// SaveQueued replaces the pending queued deployment of the pipeline with the one given
+	SaveQueued(scheduledDeployment *ScheduledDeployment, supersededMessage string) error
+	// RequeueInProgress puts a claimed deployment back as pending, a queued deployment is cancelled instead if a newer
+	// one got queued for the pipeline meanwhile. Returns false if the deployment was not requeued.
+	RequeueInProgress(scheduledDeployment *ScheduledDeployment, supersededMessage string) (bool, error)
+}
+
+type ScheduledDeploymentRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewScheduledDeploymentRepositoryImpl(dbConnection *pg.DB) *ScheduledDeploymentRepositoryImpl {
+	return &ScheduledDeploymentRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) Save(scheduledDeployment *ScheduledDeployment) error {
+	return impl.dbConnection.Insert(scheduledDeployment)
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) Update(scheduledDeployment *ScheduledDeployment) error {
+	return impl.dbConnection.Update(scheduledDeployment)
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) FindById(id int) (*ScheduledDeployment, error) {
+	scheduledDeployment := &ScheduledDeployment{}
+	err := impl.dbConnection.Model(scheduledDeployment).
+		Where("id = ?", id).
+		Select()
+	return scheduledDeployment, err
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) FindPendingByPipelineId(pipelineId int) ([]*ScheduledDeployment, error) {
+	var scheduledDeployments []*ScheduledDeployment
+	err := impl.dbConnection.Model(&scheduledDeployments).
+		Where("pipeline_id = ?", pipelineId).
+		Where("status = ?", SCHEDULED_DEPLOYMENT_STATUS_PENDING).
+		Order("scheduled_at ASC").
+		Select()
+	return scheduledDeployments, err
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) ClaimDue(now time.Time) ([]*ScheduledDeployment, error) {
+	var scheduledDeployments []*ScheduledDeployment
+	query := "UPDATE scheduled_deployment SET status = ?, updated_on = ? WHERE id IN (" +
+		" SELECT id FROM scheduled_deployment WHERE status = ? AND scheduled_at <= ? FOR UPDATE SKIP LOCKED)" +
+		" RETURNING *;"
+	_, err := impl.dbConnection.Query(&scheduledDeployments, query, SCHEDULED_DEPLOYMENT_STATUS_IN_PROGRESS, time.Now(), SCHEDULED_DEPLOYMENT_STATUS_PENDING, now)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(scheduledDeployments, func(i, j int) bool {
+		return scheduledDeployments[i].ScheduledAt.Before(scheduledDeployments[j].ScheduledAt)
+	})
+	return scheduledDeployments, nil
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) UpdateInProgress(scheduledDeployment *ScheduledDeployment) (bool, error) {
+	res, err := impl.dbConnection.Model(&ScheduledDeployment{}).
+		Set("status = ?", scheduledDeployment.Status).
+		Set("scheduled_at = ?", scheduledDeployment.ScheduledAt).
+		Set("message = ?", scheduledDeployment.Message).
+		Set("updated_on = ?", scheduledDeployment.UpdatedOn).
+		Where("id = ?", scheduledDeployment.Id).
+		Where("status = ?", SCHEDULED_DEPLOYMENT_STATUS_IN_PROGRESS).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) CancelPending(scheduledDeployment *ScheduledDeployment) (bool, error) {
+	res, err := impl.dbConnection.Model(&ScheduledDeployment{}).
+		Set("status = ?", SCHEDULED_DEPLOYMENT_STATUS_CANCELLED).
+		Set("message = ?", scheduledDeployment.Message).
+		Set("updated_on = ?", scheduledDeployment.UpdatedOn).
+		Set("updated_by = ?", scheduledDeployment.UpdatedBy).
+		Where("id = ?", scheduledDeployment.Id).
+		Where("status = ?", SCHEDULED_DEPLOYMENT_STATUS_PENDING).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) SaveQueued(scheduledDeployment *ScheduledDeployment, supersededMessage string) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockPipelineQueue(tx, scheduledDeployment.PipelineId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Model(&ScheduledDeployment{}).
+		Set("status = ?", SCHEDULED_DEPLOYMENT_STATUS_CANCELLED).
+		Set("message = ?", supersededMessage).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", scheduledDeployment.TriggeredBy).
+		Where("pipeline_id = ?", scheduledDeployment.PipelineId).
+		Where("deployment_type = ?", SCHEDULED_DEPLOYMENT_TYPE_QUEUED).
+		Where("status = ?", SCHEDULED_DEPLOYMENT_STATUS_PENDING).
+		Update()
+	if err != nil {
+		return err
+	}
+	err = tx.Insert(scheduledDeployment)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl ScheduledDeploymentRepositoryImpl) RequeueInProgress(scheduledDeployment *ScheduledDeployment, supersededMessage string) (bool, error) {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	err = lockPipelineQueue(tx, scheduledDeployment.PipelineId)
+	if err != nil {
+		return false, err
+	}
+	status := SCHEDULED_DEPLOYMENT_STATUS_PENDING
+	message := scheduledDeployment.Message
+	if scheduledDeployment.DeploymentType == SCHEDULED_DEPLOYMENT_TYPE_QUEUED {
+		newerQueued, err := tx.Model(&ScheduledDeployment{}).
+			Where("pipeline_id = ?", scheduledDeployment.PipelineId).
+			Where("deployment_type = ?", SCHEDULED_DEPLOYMENT_TYPE_QUEUED).
+			Where("status = ?", SCHEDULED_DEPLOYMENT_STATUS_PENDING).
+			Where("id <> ?", scheduledDeployment.Id).
+			Exists()
+		if err != nil {
+			return false, err
+		}
+		if newerQueued {
+			status = SCHEDULED_DEPLOYMENT_STATUS_CANCELLED
+			message = supersededMessage
+		}
+	}
+	res, err := tx.Model(&ScheduledDeployment{}).
+		Set("status = ?", status).
+		Set("scheduled_at = ?", scheduledDeployment.ScheduledAt).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", scheduledDeployment.Id).
+		Where("status = ?", SCHEDULED_DEPLOYMENT_STATUS_IN_PROGRESS).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+	scheduledDeployment.Status = status
+	scheduledDeployment.Message = message
+	return res.RowsAffected() == 1 && status == SCHEDULED_DEPLOYMENT_STATUS_PENDING, nil
+}
+
+// lockPipelineQueue serialises queue changes of a pipeline by locking its row till the transaction ends
+func lockPipelineQueue(tx *pg.Tx, pipelineId int) error {
+	_, err := tx.Exec("SELECT id FROM pipeline WHERE id = ? FOR UPDATE;", pipelineId)
+	return err
+}
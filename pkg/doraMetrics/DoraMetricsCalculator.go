@@ -0,0 +1,367 @@
+package doraMetrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type deploymentOutcome int
+
+const (
+	OUTCOME_PENDING deploymentOutcome = iota
+	OUTCOME_SUCCEEDED
+	OUTCOME_FAILED
+)
+
+// deploymentEvent is a deployment record with everything derived from its position in the pipeline's history.
+type deploymentEvent struct {
+	record     *DeploymentRecord
+	outcome    deploymentOutcome
+	deployedOn time.Time
+	leadTime   *time.Duration
+	// restoreTime is set on the successful deployment which restored an incident opened by an earlier failure
+	restoreTime *time.Duration
+	// prior events happened before the requested range, they only carry the state the pipeline enters it with
+	prior bool
+}
+
+// deploymentHistory is what happened on the pipelines of the requested range before it started
+type deploymentHistory struct {
+	// records of every pipeline since its last successful deployment, that one included
+	records []*DeploymentRecord
+	// pipeline and artifact pairs deployed successfully before the range
+	deployedArtifacts map[[2]int]bool
+}
+
+type groupKey struct {
+	id   int
+	name string
+}
+
+func getSucceededStatuses() []string {
+	return []string{pipelineConfig.WorkflowSucceeded, string(health.HealthStatusHealthy)}
+}
+
+func getDeploymentOutcome(record *DeploymentRecord) deploymentOutcome {
+	if record.RolledBack {
+		return OUTCOME_FAILED
+	}
+	switch record.Status {
+	case pipelineConfig.WorkflowSucceeded, string(health.HealthStatusHealthy):
+		return OUTCOME_SUCCEEDED
+	case pipelineConfig.WorkflowFailed, string(health.HealthStatusDegraded):
+		return OUTCOME_FAILED
+	}
+	return OUTCOME_PENDING
+}
+
+// getCommitTime returns the oldest commit time of the changed materials of an artifact, all materials are
+// considered when the artifact does not mark any of them as changed.
+func getCommitTime(materialInfo string) (time.Time, bool) {
+	var ciMaterials []repository.CiMaterialInfo
+	if len(materialInfo) == 0 || json.Unmarshal([]byte(materialInfo), &ciMaterials) != nil {
+		return time.Time{}, false
+	}
+	var commitTime time.Time
+	found := false
+	for _, onlyChanged := range []bool{true, false} {
+		for _, ciMaterial := range ciMaterials {
+			if onlyChanged && !ciMaterial.Changed {
+				continue
+			}
+			for _, modification := range ciMaterial.Modifications {
+				modifiedTime, err := time.Parse(time.RFC3339, modification.ModifiedTime)
+				if err != nil {
+					continue
+				}
+				if !found || modifiedTime.Before(commitTime) {
+					commitTime = modifiedTime
+					found = true
+				}
+			}
+		}
+		if found {
+			break
+		}
+	}
+	return commitTime, found
+}
+
+// analyseDeployments walks every pipeline's deployments in order, starting from the state the pipeline enters the
+// range with. Lead time is measured only for the first successful deployment of an artifact on a pipeline, and an
+// incident opened by a failed deployment is restored by the next successful deployment on the same pipeline. A
+// successful deployment of an older artifact deployed before on the pipeline is a rollback, the deployment it
+// replaced is taken as failed.
+func analyseDeployments(records []*DeploymentRecord, history *deploymentHistory) []*deploymentEvent {
+	events := make([]*deploymentEvent, 0, len(records))
+	newEvent := func(record *DeploymentRecord, prior bool) *deploymentEvent {
+		deployedOn := record.FinishedOn
+		if deployedOn.IsZero() {
+			deployedOn = record.StartedOn
+		}
+		return &deploymentEvent{record: record, outcome: getDeploymentOutcome(record), deployedOn: deployedOn, prior: prior}
+	}
+	deployedArtifacts := make(map[[2]int]bool)
+	if history != nil {
+		for _, record := range history.records {
+			events = append(events, newEvent(record, true))
+		}
+		for artifactKey := range history.deployedArtifacts {
+			deployedArtifacts[artifactKey] = true
+		}
+	}
+	for _, record := range records {
+		events = append(events, newEvent(record, false))
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].record.PipelineId != events[j].record.PipelineId {
+			return events[i].record.PipelineId < events[j].record.PipelineId
+		}
+		return events[i].record.StartedOn.Before(events[j].record.StartedOn)
+	})
+	openIncidents := make(map[int]*deploymentEvent)
+	currentDeployments := make(map[int]*deploymentEvent)
+	for _, event := range events {
+		pipelineId := event.record.PipelineId
+		switch event.outcome {
+		case OUTCOME_FAILED:
+			if openIncidents[pipelineId] == nil {
+				openIncidents[pipelineId] = event
+			}
+		case OUTCOME_SUCCEEDED:
+			artifactKey := [2]int{pipelineId, event.record.CiArtifactId}
+			if current := currentDeployments[pipelineId]; current != nil && isRollback(current, event, deployedArtifacts[artifactKey]) {
+				current.outcome = OUTCOME_FAILED
+				if incident := openIncidents[pipelineId]; incident == nil || current.deployedOn.Before(incident.deployedOn) {
+					openIncidents[pipelineId] = current
+				}
+			}
+			currentDeployments[pipelineId] = event
+			if incident := openIncidents[pipelineId]; incident != nil {
+				restoreTime := event.deployedOn.Sub(incident.deployedOn)
+				event.restoreTime = &restoreTime
+				delete(openIncidents, pipelineId)
+			}
+			if deployedArtifacts[artifactKey] {
+				continue
+			}
+			deployedArtifacts[artifactKey] = true
+			if commitTime, ok := getCommitTime(event.record.MaterialInfo); ok && !commitTime.After(event.deployedOn) {
+				leadTime := event.deployedOn.Sub(commitTime)
+				event.leadTime = &leadTime
+			}
+		}
+	}
+	return events
+}
+
+// isRollback tells if the deployment brought back an artifact, deployed before on the pipeline, older than the
+// current one
+func isRollback(current *deploymentEvent, event *deploymentEvent, deployedBefore bool) bool {
+	return deployedBefore && current.record.CiArtifactId != event.record.CiArtifactId &&
+		event.record.ArtifactCreatedOn.Before(current.record.ArtifactCreatedOn)
+}
+
+func getBucketStart(t time.Time, bucket Bucket) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case BUCKET_WEEK:
+		// weeks start on monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BUCKET_MONTH:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func getNextBucketStart(start time.Time, bucket Bucket) time.Time {
+	switch bucket {
+	case BUCKET_WEEK:
+		return start.AddDate(0, 0, 7)
+	case BUCKET_MONTH:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// getBuckets splits [from, to) into buckets, the first and last buckets are clipped to the requested range.
+func getBuckets(from, to time.Time, bucket Bucket) []*DoraMetricsBucket {
+	var buckets []*DoraMetricsBucket
+	for start := getBucketStart(from, bucket); start.Before(to); start = getNextBucketStart(start, bucket) {
+		bucketFrom, bucketTo := start, getNextBucketStart(start, bucket)
+		if bucketFrom.Before(from) {
+			bucketFrom = from
+		}
+		if bucketTo.After(to) {
+			bucketTo = to
+		}
+		buckets = append(buckets, &DoraMetricsBucket{Start: bucketFrom, End: bucketTo})
+	}
+	return buckets
+}
+
+type metricsAccumulator struct {
+	successes    int
+	failures     int
+	pending      int
+	leadTimes    []time.Duration
+	restoreTimes []time.Duration
+}
+
+func (acc *metricsAccumulator) add(event *deploymentEvent) {
+	switch event.outcome {
+	case OUTCOME_SUCCEEDED:
+		acc.successes++
+	case OUTCOME_FAILED:
+		acc.failures++
+	default:
+		acc.pending++
+	}
+	if event.leadTime != nil {
+		acc.leadTimes = append(acc.leadTimes, *event.leadTime)
+	}
+	if event.restoreTime != nil {
+		acc.restoreTimes = append(acc.restoreTimes, *event.restoreTime)
+	}
+}
+
+func (acc *metricsAccumulator) toMetrics(from, to time.Time) *DoraMetrics {
+	metrics := &DoraMetrics{
+		Deployments:           acc.successes + acc.failures + acc.pending,
+		SuccessfulDeployments: acc.successes,
+		FailedDeployments:     acc.failures,
+		Restores:              len(acc.restoreTimes),
+	}
+	if days := to.Sub(from).Hours() / 24; days > 0 {
+		metrics.DeploymentFrequency = float64(acc.successes) / days
+	}
+	if completed := acc.successes + acc.failures; completed > 0 {
+		metrics.ChangeFailureRate = float64(acc.failures) * 100 / float64(completed)
+	}
+	if len(acc.leadTimes) > 0 {
+		sort.Slice(acc.leadTimes, func(i, j int) bool { return acc.leadTimes[i] < acc.leadTimes[j] })
+		mid := len(acc.leadTimes) / 2
+		median := acc.leadTimes[mid]
+		if len(acc.leadTimes)%2 == 0 {
+			median = (acc.leadTimes[mid-1] + acc.leadTimes[mid]) / 2
+		}
+		leadTime := median.Minutes()
+		metrics.LeadTimeForChangesMinutes = &leadTime
+	}
+	if len(acc.restoreTimes) > 0 {
+		var total time.Duration
+		for _, restoreTime := range acc.restoreTimes {
+			total += restoreTime
+		}
+		meanTimeToRestore := (total / time.Duration(len(acc.restoreTimes))).Minutes()
+		metrics.MeanTimeToRestoreMinutes = &meanTimeToRestore
+	}
+	return metrics
+}
+
+// computeDoraMetrics aggregates the deployments of the request range into the groups returned by groupsOf.
+// A deployment is counted in the bucket in which it was started, a restore in the bucket of the deployment
+// which restored it, and lead time is reported as the median.
+func computeDoraMetrics(request *DoraMetricsRequest, records []*DeploymentRecord, history *deploymentHistory, groupsOf func(record *DeploymentRecord) []groupKey) *DoraMetricsResponse {
+	buckets := getBuckets(request.From, request.To, request.Bucket)
+	summary := &metricsAccumulator{}
+	groupAccumulators := make(map[groupKey][]*metricsAccumulator)
+	groupSummaries := make(map[groupKey]*metricsAccumulator)
+	var groupKeys []groupKey
+	for _, event := range analyseDeployments(records, history) {
+		if event.prior {
+			continue
+		}
+		bucketIndex := sort.Search(len(buckets), func(i int) bool { return buckets[i].End.After(event.record.StartedOn) })
+		if bucketIndex == len(buckets) {
+			continue
+		}
+		summary.add(event)
+		for _, key := range groupsOf(event.record) {
+			if _, ok := groupSummaries[key]; !ok {
+				groupKeys = append(groupKeys, key)
+				groupSummaries[key] = &metricsAccumulator{}
+				accumulators := make([]*metricsAccumulator, len(buckets))
+				for i := range accumulators {
+					accumulators[i] = &metricsAccumulator{}
+				}
+				groupAccumulators[key] = accumulators
+			}
+			groupSummaries[key].add(event)
+			groupAccumulators[key][bucketIndex].add(event)
+		}
+	}
+	sort.Slice(groupKeys, func(i, j int) bool {
+		if groupKeys[i].name != groupKeys[j].name {
+			return groupKeys[i].name < groupKeys[j].name
+		}
+		return groupKeys[i].id < groupKeys[j].id
+	})
+	response := &DoraMetricsResponse{
+		From:    request.From,
+		To:      request.To,
+		GroupBy: request.GroupBy,
+		Bucket:  request.Bucket,
+		Summary: summary.toMetrics(request.From, request.To),
+		Groups:  make([]*DoraMetricsGroup, 0, len(groupKeys)),
+	}
+	for _, key := range groupKeys {
+		group := &DoraMetricsGroup{
+			Id:      key.id,
+			Name:    key.name,
+			Summary: groupSummaries[key].toMetrics(request.From, request.To),
+		}
+		for i, bucket := range buckets {
+			group.Buckets = append(group.Buckets, &DoraMetricsBucket{
+				Start:       bucket.Start,
+				End:         bucket.End,
+				DoraMetrics: groupAccumulators[key][i].toMetrics(bucket.Start, bucket.End),
+			})
+		}
+		response.Groups = append(response.Groups, group)
+	}
+	return response
+}
+
+// WriteDoraMetricsCsv writes one row per group and bucket followed by a TOTAL row for every group.
+func WriteDoraMetricsCsv(response *DoraMetricsResponse, writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.Write([]string{"groupBy", "groupId", "groupName", "period", "from", "to", "deployments", "successfulDeployments",
+		"failedDeployments", "deploymentFrequencyPerDay", "leadTimeForChangesMinutes", "changeFailureRatePercent", "meanTimeToRestoreMinutes", "restores"})
+	if err != nil {
+		return err
+	}
+	row := func(group *DoraMetricsGroup, period string, from, to time.Time, metrics *DoraMetrics) []string {
+		return []string{string(response.GroupBy), strconv.Itoa(group.Id), group.Name, period, from.Format(time.RFC3339), to.Format(time.RFC3339),
+			strconv.Itoa(metrics.Deployments), strconv.Itoa(metrics.SuccessfulDeployments), strconv.Itoa(metrics.FailedDeployments),
+			formatFloat(&metrics.DeploymentFrequency), formatFloat(metrics.LeadTimeForChangesMinutes), formatFloat(&metrics.ChangeFailureRate),
+			formatFloat(metrics.MeanTimeToRestoreMinutes), strconv.Itoa(metrics.Restores)}
+	}
+	for _, group := range response.Groups {
+		for _, bucket := range group.Buckets {
+			if err = csvWriter.Write(row(group, string(response.Bucket), bucket.Start, bucket.End, bucket.DoraMetrics)); err != nil {
+				return err
+			}
+		}
+		if err = csvWriter.Write(row(group, "TOTAL", response.From, response.To, group.Summary)); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 2, 64)
+}
@@ -0,0 +1,150 @@
+package doraMetrics
+
+import (
+	"bytes"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCommitTime(t *testing.T) {
+	materialInfo := `[{"changed":false,"modifications":[{"modified-time":"2023-06-01T08:00:00Z"}]},` +
+		`{"changed":true,"modifications":[{"modified-time":"2023-06-02T10:00:00+05:30"},{"modified-time":"invalid"}]}]`
+	commitTime, ok := getCommitTime(materialInfo)
+	assert.True(t, ok)
+	assert.True(t, commitTime.Equal(time.Date(2023, 6, 2, 4, 30, 0, 0, time.UTC)))
+
+	commitTime, ok = getCommitTime(`[{"changed":false,"modifications":[{"modified-time":"2023-06-01T08:00:00Z"}]}]`)
+	assert.True(t, ok)
+	assert.True(t, commitTime.Equal(time.Date(2023, 6, 1, 8, 0, 0, 0, time.UTC)))
+
+	_, ok = getCommitTime("")
+	assert.False(t, ok)
+	_, ok = getCommitTime("not json")
+	assert.False(t, ok)
+}
+
+func TestGetBuckets(t *testing.T) {
+	// 2023-06-07 is a wednesday
+	from := time.Date(2023, 6, 7, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 20, 0, 0, 0, 0, time.UTC)
+	buckets := getBuckets(from, to, BUCKET_WEEK)
+	assert.Len(t, buckets, 3)
+	assert.Equal(t, from, buckets[0].Start)
+	assert.Equal(t, time.Date(2023, 6, 12, 0, 0, 0, 0, time.UTC), buckets[0].End)
+	assert.Equal(t, time.Date(2023, 6, 19, 0, 0, 0, 0, time.UTC), buckets[1].End)
+	assert.Equal(t, to, buckets[2].End)
+
+	assert.Len(t, getBuckets(from, to, BUCKET_DAY), 13)
+	months := getBuckets(from, time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC), BUCKET_MONTH)
+	assert.Len(t, months, 2)
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), months[1].Start)
+}
+
+func TestComputeDoraMetrics(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2023, 6, d, hour, 0, 0, 0, time.UTC)
+	}
+	record := func(id, pipelineId, appId, artifactId int, status string, finishedOn time.Time, materialInfo string) *DeploymentRecord {
+		return &DeploymentRecord{CdWorkflowRunnerId: id, PipelineId: pipelineId, AppId: appId, AppName: map[int]string{1: "app-a", 2: "app-b"}[appId],
+			CiArtifactId: artifactId, Status: status, StartedOn: finishedOn.Add(-10 * time.Minute), FinishedOn: finishedOn, MaterialInfo: materialInfo}
+	}
+	commit := `[{"changed":true,"modifications":[{"modified-time":"2023-06-01T00:00:00Z"}]}]`
+	records := []*DeploymentRecord{
+		record(1, 1, 1, 10, pipelineConfig.WorkflowSucceeded, day(1, 2), commit),
+		record(2, 1, 1, 11, pipelineConfig.WorkflowFailed, day(2, 0), commit),
+		record(3, 1, 1, 11, "Healthy", day(2, 3), commit),
+		// redeploy of an already deployed artifact does not count towards lead time
+		record(4, 1, 1, 10, pipelineConfig.WorkflowSucceeded, day(3, 0), commit),
+		record(5, 2, 2, 20, pipelineConfig.WorkflowSucceeded, day(8, 0), commit),
+		record(6, 2, 2, 21, pipelineConfig.WorkflowInProgress, day(9, 0), ""),
+	}
+	rolledBack := record(7, 2, 2, 22, pipelineConfig.WorkflowSucceeded, day(10, 0), "")
+	rolledBack.RolledBack = true
+	records = append(records, rolledBack)
+
+	request := &DoraMetricsRequest{From: day(1, 0), To: day(15, 0), Bucket: BUCKET_WEEK, GroupBy: GROUP_BY_APP}
+	response := computeDoraMetrics(request, records, nil, getGroupsOf(request, nil))
+
+	summary := response.Summary
+	assert.Equal(t, 7, summary.Deployments)
+	assert.Equal(t, 4, summary.SuccessfulDeployments)
+	assert.Equal(t, 2, summary.FailedDeployments)
+	assert.InDelta(t, 4.0/14, summary.DeploymentFrequency, 0.0001)
+	assert.InDelta(t, 100.0/3, summary.ChangeFailureRate, 0.0001)
+	// lead times are 2h, 27h and 168h
+	assert.InDelta(t, 27*60, *summary.LeadTimeForChangesMinutes, 0.0001)
+	// failure on day 2 restored three hours later, the rollback on day 10 is never restored
+	assert.Equal(t, 1, summary.Restores)
+	assert.InDelta(t, 180, *summary.MeanTimeToRestoreMinutes, 0.0001)
+
+	assert.Len(t, response.Groups, 2)
+	appA := response.Groups[0]
+	assert.Equal(t, "app-a", appA.Name)
+	assert.Len(t, appA.Buckets, 3)
+	assert.Equal(t, 4, appA.Buckets[0].Deployments)
+	assert.Equal(t, 0, appA.Buckets[1].Deployments)
+	assert.Nil(t, appA.Buckets[1].LeadTimeForChangesMinutes)
+	appB := response.Groups[1]
+	assert.Equal(t, 3, appB.Summary.Deployments)
+	assert.Equal(t, 50.0, appB.Summary.ChangeFailureRate)
+	assert.Nil(t, appB.Summary.MeanTimeToRestoreMinutes)
+
+	var csvOutput bytes.Buffer
+	assert.Nil(t, WriteDoraMetricsCsv(response, &csvOutput))
+	lines := strings.Split(strings.TrimSpace(csvOutput.String()), "\n")
+	// header, three buckets and a total per app
+	assert.Len(t, lines, 9)
+	assert.True(t, strings.HasPrefix(lines[4], "APP,1,app-a,TOTAL,"))
+}
+
+func TestComputeDoraMetricsWithHistory(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2023, 6, d, hour, 0, 0, 0, time.UTC)
+	}
+	record := func(id, artifactId int, status string, finishedOn time.Time) *DeploymentRecord {
+		return &DeploymentRecord{CdWorkflowRunnerId: id, PipelineId: 1, AppId: 1, AppName: "app-a", CiArtifactId: artifactId, Status: status,
+			StartedOn: finishedOn.Add(-10 * time.Minute), FinishedOn: finishedOn, ArtifactCreatedOn: day(1, 0).AddDate(0, 0, artifactId-10),
+			MaterialInfo: `[{"changed":true,"modifications":[{"modified-time":"2023-06-01T00:00:00Z"}]}]`}
+	}
+	history := &deploymentHistory{
+		records: []*DeploymentRecord{
+			record(1, 10, pipelineConfig.WorkflowSucceeded, day(1, 0)),
+			record(2, 11, pipelineConfig.WorkflowFailed, day(2, 0)),
+		},
+		deployedArtifacts: map[[2]int]bool{{1, 10}: true},
+	}
+	records := []*DeploymentRecord{
+		// restores the failure from before the range
+		record(3, 11, pipelineConfig.WorkflowSucceeded, day(5, 0)),
+		record(4, 12, pipelineConfig.WorkflowSucceeded, day(6, 0)),
+		// manual rollback to an artifact deployed before the range fails the deployment it replaced
+		record(5, 10, pipelineConfig.WorkflowSucceeded, day(6, 2)),
+	}
+	request := &DoraMetricsRequest{From: day(4, 0), To: day(8, 0), Bucket: BUCKET_WEEK}
+	summary := computeDoraMetrics(request, records, history, getGroupsOf(request, nil)).Summary
+	assert.Equal(t, 3, summary.Deployments)
+	assert.Equal(t, 2, summary.SuccessfulDeployments)
+	assert.Equal(t, 1, summary.FailedDeployments)
+	// lead time only for artifacts 11 and 12, artifact 10 was deployed before the range
+	assert.InDelta(t, (4*24+5*24)*60/2, *summary.LeadTimeForChangesMinutes, 0.0001)
+	// restores after 3 days and 2 hours
+	assert.Equal(t, 2, summary.Restores)
+	assert.InDelta(t, (3*24*60+120)/2, *summary.MeanTimeToRestoreMinutes, 0.0001)
+}
+
+func TestValidateDoraMetricsRequest(t *testing.T) {
+	now := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	request := &DoraMetricsRequest{}
+	assert.Nil(t, validateDoraMetricsRequest(request, now))
+	assert.Equal(t, now, request.To)
+	assert.Equal(t, now.Add(-DEFAULT_METRICS_RANGE), request.From)
+	assert.Equal(t, BUCKET_WEEK, request.Bucket)
+
+	assert.NotNil(t, validateDoraMetricsRequest(&DoraMetricsRequest{From: now, To: now.Add(-time.Hour)}, now))
+	assert.NotNil(t, validateDoraMetricsRequest(&DoraMetricsRequest{From: now.AddDate(-2, 0, 0), To: now}, now))
+	assert.NotNil(t, validateDoraMetricsRequest(&DoraMetricsRequest{Bucket: "YEAR"}, now))
+	assert.NotNil(t, validateDoraMetricsRequest(&DoraMetricsRequest{GroupBy: "CLUSTER"}, now))
+}
@@ -0,0 +1,167 @@
+package doraMetrics
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// DeploymentRecord is a single deploy runner of a cd pipeline along with the app, environment, team and
+// artifact details required for computing DORA metrics.
+type DeploymentRecord struct {
+	CdWorkflowRunnerId int       `sql:"cd_workflow_runner_id"`
+	Status             string    `sql:"status"`
+	StartedOn          time.Time `sql:"started_on"`
+	FinishedOn         time.Time `sql:"finished_on"`
+	PipelineId         int       `sql:"pipeline_id"`
+	AppId              int       `sql:"app_id"`
+	AppName            string    `sql:"app_name"`
+	EnvironmentId      int       `sql:"environment_id"`
+	EnvironmentName    string    `sql:"environment_name"`
+	TeamId             int       `sql:"team_id"`
+	TeamName           string    `sql:"team_name"`
+	CiArtifactId       int       `sql:"ci_artifact_id"`
+	MaterialInfo       string    `sql:"material_info"`
+	ArtifactCreatedOn  time.Time `sql:"artifact_created_on"`
+	RolledBack         bool      `sql:"rolled_back"`
+}
+
+// AppGroupMember is an app of an active app group, app groups are always scoped to a single environment.
+type AppGroupMember struct {
+	AppGroupId    int    `sql:"app_group_id"`
+	AppGroupName  string `sql:"app_group_name"`
+	EnvironmentId int    `sql:"environment_id"`
+	AppId         int    `sql:"app_id"`
+}
+
+// PipelineArtifact is an artifact deployed successfully on a pipeline
+type PipelineArtifact struct {
+	PipelineId   int `sql:"pipeline_id"`
+	CiArtifactId int `sql:"ci_artifact_id"`
+}
+
+type DeploymentRecordFilter struct {
+	From    time.Time
+	To      time.Time
+	AppIds  []int
+	EnvIds  []int
+	TeamIds []int
+}
+
+type DoraMetricsRepository interface {
+	FindDeploymentRecords(filter *DeploymentRecordFilter) ([]*DeploymentRecord, error)
+	// FindPriorDeploymentRecords returns the deploy runners of the pipelines started before the given time since
+	// their last successful one, that one included, ordered by start time
+	FindPriorDeploymentRecords(pipelineIds []int, before time.Time) ([]*DeploymentRecord, error)
+	// FindPriorDeployedArtifacts returns which of the artifacts were deployed successfully on the pipelines before
+	// the given time
+	FindPriorDeployedArtifacts(pipelineIds []int, artifactIds []int, before time.Time) ([]*PipelineArtifact, error)
+	FindActiveAppGroupMembers() ([]*AppGroupMember, error)
+}
+
+type DoraMetricsRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewDoraMetricsRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *DoraMetricsRepositoryImpl {
+	return &DoraMetricsRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+const deploymentRecordQuery = "SELECT wfr.id AS cd_workflow_runner_id, wfr.status, wfr.started_on, wfr.finished_on," +
+	" wf.pipeline_id, p.app_id, a.app_name, p.environment_id, e.environment_name, a.team_id, t.name AS team_name," +
+	" wf.ci_artifact_id, cia.material_info, cia.created_on AS artifact_created_on," +
+	" EXISTS (SELECT 1 FROM pipeline_status_timeline pst WHERE pst.cd_workflow_runner_id = wfr.id AND pst.status = ?) AS rolled_back" +
+	" FROM cd_workflow_runner wfr" +
+	" INNER JOIN cd_workflow wf ON wf.id = wfr.cd_workflow_id" +
+	" INNER JOIN pipeline p ON p.id = wf.pipeline_id AND p.deleted = false" +
+	" INNER JOIN app a ON a.id = p.app_id AND a.active = true AND a.app_type = ?" +
+	" INNER JOIN environment e ON e.id = p.environment_id" +
+	" INNER JOIN team t ON t.id = a.team_id" +
+	" INNER JOIN ci_artifact cia ON cia.id = wf.ci_artifact_id"
+
+// FindDeploymentRecords returns deploy runners started in [From, To) ordered by start time. A runner is marked
+// rolled back when its timeline has a rollback triggered by a failed canary analysis.
+func (impl DoraMetricsRepositoryImpl) FindDeploymentRecords(filter *DeploymentRecordFilter) ([]*DeploymentRecord, error) {
+	var records []*DeploymentRecord
+	query := deploymentRecordQuery + " WHERE wfr.workflow_type = ? AND wfr.started_on >= ? AND wfr.started_on < ?"
+	params := []interface{}{pipelineConfig.TIMELINE_STATUS_ROLLBACK_TRIGGERED, helper.CustomApp, bean.CD_WORKFLOW_TYPE_DEPLOY, filter.From, filter.To}
+	if len(filter.AppIds) > 0 {
+		query += " AND p.app_id IN (?)"
+		params = append(params, pg.In(filter.AppIds))
+	}
+	if len(filter.EnvIds) > 0 {
+		query += " AND p.environment_id IN (?)"
+		params = append(params, pg.In(filter.EnvIds))
+	}
+	if len(filter.TeamIds) > 0 {
+		query += " AND a.team_id IN (?)"
+		params = append(params, pg.In(filter.TeamIds))
+	}
+	query += " ORDER BY wfr.started_on, wfr.id"
+	_, err := impl.dbConnection.Query(&records, query, params...)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment records", "filter", filter, "err", err)
+		return nil, err
+	}
+	return records, nil
+}
+
+func (impl DoraMetricsRepositoryImpl) FindPriorDeploymentRecords(pipelineIds []int, before time.Time) ([]*DeploymentRecord, error) {
+	var records []*DeploymentRecord
+	if len(pipelineIds) == 0 {
+		return records, nil
+	}
+	query := deploymentRecordQuery +
+		" LEFT JOIN (SELECT wf2.pipeline_id, MAX(wfr2.started_on) AS started_on FROM cd_workflow_runner wfr2" +
+		" INNER JOIN cd_workflow wf2 ON wf2.id = wfr2.cd_workflow_id" +
+		" WHERE wf2.pipeline_id IN (?) AND wfr2.workflow_type = ? AND wfr2.started_on < ? AND wfr2.status IN (?)" +
+		" GROUP BY wf2.pipeline_id) ls ON ls.pipeline_id = wf.pipeline_id" +
+		" WHERE wf.pipeline_id IN (?) AND wfr.workflow_type = ? AND wfr.started_on < ?" +
+		" AND (ls.started_on IS NULL OR wfr.started_on >= ls.started_on)" +
+		" ORDER BY wfr.started_on, wfr.id"
+	params := []interface{}{pipelineConfig.TIMELINE_STATUS_ROLLBACK_TRIGGERED, helper.CustomApp,
+		pg.In(pipelineIds), bean.CD_WORKFLOW_TYPE_DEPLOY, before, pg.In(getSucceededStatuses()),
+		pg.In(pipelineIds), bean.CD_WORKFLOW_TYPE_DEPLOY, before}
+	_, err := impl.dbConnection.Query(&records, query, params...)
+	if err != nil {
+		impl.logger.Errorw("error in fetching prior deployment records", "pipelineIds", pipelineIds, "before", before, "err", err)
+		return nil, err
+	}
+	return records, nil
+}
+
+func (impl DoraMetricsRepositoryImpl) FindPriorDeployedArtifacts(pipelineIds []int, artifactIds []int, before time.Time) ([]*PipelineArtifact, error) {
+	var artifacts []*PipelineArtifact
+	if len(pipelineIds) == 0 || len(artifactIds) == 0 {
+		return artifacts, nil
+	}
+	query := "SELECT DISTINCT wf.pipeline_id, wf.ci_artifact_id FROM cd_workflow_runner wfr" +
+		" INNER JOIN cd_workflow wf ON wf.id = wfr.cd_workflow_id" +
+		" WHERE wf.pipeline_id IN (?) AND wf.ci_artifact_id IN (?) AND wfr.workflow_type = ? AND wfr.started_on < ? AND wfr.status IN (?)"
+	_, err := impl.dbConnection.Query(&artifacts, query, pg.In(pipelineIds), pg.In(artifactIds), bean.CD_WORKFLOW_TYPE_DEPLOY, before, pg.In(getSucceededStatuses()))
+	if err != nil {
+		impl.logger.Errorw("error in fetching prior deployed artifacts", "pipelineIds", pipelineIds, "before", before, "err", err)
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+func (impl DoraMetricsRepositoryImpl) FindActiveAppGroupMembers() ([]*AppGroupMember, error) {
+	var members []*AppGroupMember
+	query := "SELECT ag.id AS app_group_id, ag.name AS app_group_name, ag.environment_id, agm.app_id" +
+		" FROM app_group ag INNER JOIN app_group_mapping agm ON agm.app_group_id = ag.id" +
+		" WHERE ag.active = true ORDER BY ag.id"
+	_, err := impl.dbConnection.Query(&members, query)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app group members", "err", err)
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,205 @@
+package doraMetrics
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type DoraMetricsService interface {
+	GetDoraMetrics(request *DoraMetricsRequest) (*DoraMetricsResponse, error)
+}
+
+type DoraMetricsServiceImpl struct {
+	logger                *zap.SugaredLogger
+	doraMetricsRepository DoraMetricsRepository
+}
+
+func NewDoraMetricsServiceImpl(logger *zap.SugaredLogger, doraMetricsRepository DoraMetricsRepository) *DoraMetricsServiceImpl {
+	return &DoraMetricsServiceImpl{
+		logger:                logger,
+		doraMetricsRepository: doraMetricsRepository,
+	}
+}
+
+func (impl *DoraMetricsServiceImpl) GetDoraMetrics(request *DoraMetricsRequest) (*DoraMetricsResponse, error) {
+	err := validateDoraMetricsRequest(request, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var appGroupMembers []*AppGroupMember
+	if request.GroupBy == GROUP_BY_APP_GROUP || request.AppGroupId > 0 {
+		appGroupMembers, err = impl.doraMetricsRepository.FindActiveAppGroupMembers()
+		if err != nil {
+			impl.logger.Errorw("error in fetching app group members", "err", err)
+			return nil, err
+		}
+	}
+	filter := &DeploymentRecordFilter{
+		From:    request.From,
+		To:      request.To,
+		AppIds:  request.AppIds,
+		EnvIds:  request.EnvIds,
+		TeamIds: request.TeamIds,
+	}
+	var appGroupFilter map[[2]int]bool
+	if request.AppGroupId > 0 {
+		appGroupFilter = make(map[[2]int]bool)
+		for _, member := range appGroupMembers {
+			if member.AppGroupId == request.AppGroupId {
+				appGroupFilter[[2]int{member.AppId, member.EnvironmentId}] = true
+				if len(request.AppIds) == 0 {
+					filter.AppIds = append(filter.AppIds, member.AppId)
+				}
+			}
+		}
+		if len(appGroupFilter) == 0 {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, Code: "404", UserMessage: "app group not found", InternalMessage: "app group not found"}
+		}
+	}
+	records, err := impl.doraMetricsRepository.FindDeploymentRecords(filter)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment records", "request", request, "err", err)
+		return nil, err
+	}
+	if appGroupFilter != nil {
+		var appGroupRecords []*DeploymentRecord
+		for _, record := range records {
+			if appGroupFilter[[2]int{record.AppId, record.EnvironmentId}] {
+				appGroupRecords = append(appGroupRecords, record)
+			}
+		}
+		records = appGroupRecords
+	}
+	records = impl.filterAuthorizedRecords(request, records)
+	history, err := impl.getDeploymentHistory(records, request.From)
+	if err != nil {
+		return nil, err
+	}
+	return computeDoraMetrics(request, records, history, getGroupsOf(request, appGroupMembers)), nil
+}
+
+// getDeploymentHistory fetches what happened on the pipelines of the records before from, so that incidents open
+// at the start of the range, artifacts deployed before it and rollbacks to them are accounted for
+func (impl *DoraMetricsServiceImpl) getDeploymentHistory(records []*DeploymentRecord, from time.Time) (*deploymentHistory, error) {
+	pipelineIdSet, artifactIdSet := make(map[int]bool), make(map[int]bool)
+	var pipelineIds, artifactIds []int
+	for _, record := range records {
+		if !pipelineIdSet[record.PipelineId] {
+			pipelineIdSet[record.PipelineId] = true
+			pipelineIds = append(pipelineIds, record.PipelineId)
+		}
+		if !artifactIdSet[record.CiArtifactId] {
+			artifactIdSet[record.CiArtifactId] = true
+			artifactIds = append(artifactIds, record.CiArtifactId)
+		}
+	}
+	priorRecords, err := impl.doraMetricsRepository.FindPriorDeploymentRecords(pipelineIds, from)
+	if err != nil {
+		impl.logger.Errorw("error in fetching prior deployment records", "pipelineIds", pipelineIds, "err", err)
+		return nil, err
+	}
+	priorArtifacts, err := impl.doraMetricsRepository.FindPriorDeployedArtifacts(pipelineIds, artifactIds, from)
+	if err != nil {
+		impl.logger.Errorw("error in fetching prior deployed artifacts", "pipelineIds", pipelineIds, "err", err)
+		return nil, err
+	}
+	history := &deploymentHistory{records: priorRecords, deployedArtifacts: make(map[[2]int]bool)}
+	for _, artifact := range priorArtifacts {
+		history.deployedArtifacts[[2]int{artifact.PipelineId, artifact.CiArtifactId}] = true
+	}
+	return history, nil
+}
+
+func (impl *DoraMetricsServiceImpl) filterAuthorizedRecords(request *DoraMetricsRequest, records []*DeploymentRecord) []*DeploymentRecord {
+	if request.CheckAuthBatch == nil || len(records) == 0 {
+		return records
+	}
+	appIdSet := make(map[int]bool)
+	var appIds []int
+	for _, record := range records {
+		if !appIdSet[record.AppId] {
+			appIdSet[record.AppId] = true
+			appIds = append(appIds, record.AppId)
+		}
+	}
+	authorizedApps := request.CheckAuthBatch(appIds)
+	var authorizedRecords []*DeploymentRecord
+	for _, record := range records {
+		if authorizedApps[record.AppId] {
+			authorizedRecords = append(authorizedRecords, record)
+		}
+	}
+	return authorizedRecords
+}
+
+func getGroupsOf(request *DoraMetricsRequest, appGroupMembers []*AppGroupMember) func(record *DeploymentRecord) []groupKey {
+	switch request.GroupBy {
+	case GROUP_BY_APP:
+		return func(record *DeploymentRecord) []groupKey {
+			return []groupKey{{id: record.AppId, name: record.AppName}}
+		}
+	case GROUP_BY_ENVIRONMENT:
+		return func(record *DeploymentRecord) []groupKey {
+			return []groupKey{{id: record.EnvironmentId, name: record.EnvironmentName}}
+		}
+	case GROUP_BY_TEAM:
+		return func(record *DeploymentRecord) []groupKey {
+			return []groupKey{{id: record.TeamId, name: record.TeamName}}
+		}
+	case GROUP_BY_APP_GROUP:
+		// an app can be part of several app groups of an environment, its deployments count towards each of them
+		appGroupsByAppEnv := make(map[[2]int][]groupKey)
+		for _, member := range appGroupMembers {
+			if request.AppGroupId > 0 && member.AppGroupId != request.AppGroupId {
+				continue
+			}
+			appEnv := [2]int{member.AppId, member.EnvironmentId}
+			appGroupsByAppEnv[appEnv] = append(appGroupsByAppEnv[appEnv], groupKey{id: member.AppGroupId, name: member.AppGroupName})
+		}
+		return func(record *DeploymentRecord) []groupKey {
+			return appGroupsByAppEnv[[2]int{record.AppId, record.EnvironmentId}]
+		}
+	}
+	return func(record *DeploymentRecord) []groupKey {
+		return []groupKey{{}}
+	}
+}
+
+// validateDoraMetricsRequest defaults the range to the last 30 days bucketed by week and rejects unknown
+// groupings, buckets and ranges longer than a year.
+func validateDoraMetricsRequest(request *DoraMetricsRequest, now time.Time) error {
+	if request.To.IsZero() {
+		request.To = now
+	}
+	if request.From.IsZero() {
+		request.From = request.To.Add(-DEFAULT_METRICS_RANGE)
+	}
+	request.From, request.To = request.From.UTC(), request.To.UTC()
+	if len(request.Bucket) == 0 {
+		request.Bucket = BUCKET_WEEK
+	}
+	if !request.From.Before(request.To) {
+		return newBadRequestError("from must be before to")
+	}
+	if request.To.Sub(request.From) > MAX_METRICS_RANGE {
+		return newBadRequestError("time range cannot be longer than 366 days")
+	}
+	switch request.Bucket {
+	case BUCKET_DAY, BUCKET_WEEK, BUCKET_MONTH:
+	default:
+		return newBadRequestError(fmt.Sprintf("unsupported bucket %s", request.Bucket))
+	}
+	switch request.GroupBy {
+	case GROUP_BY_NONE, GROUP_BY_APP, GROUP_BY_ENVIRONMENT, GROUP_BY_TEAM, GROUP_BY_APP_GROUP:
+	default:
+		return newBadRequestError(fmt.Sprintf("unsupported groupBy %s", request.GroupBy))
+	}
+	return nil
+}
+
+func newBadRequestError(message string) error {
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", UserMessage: message, InternalMessage: message}
+}
@@ -0,0 +1,74 @@
+package doraMetrics
+
+import "time"
+
+type GroupBy string
+
+const (
+	GROUP_BY_NONE        GroupBy = ""
+	GROUP_BY_APP         GroupBy = "APP"
+	GROUP_BY_ENVIRONMENT GroupBy = "ENVIRONMENT"
+	GROUP_BY_TEAM        GroupBy = "TEAM"
+	GROUP_BY_APP_GROUP   GroupBy = "APP_GROUP"
+)
+
+type Bucket string
+
+const (
+	BUCKET_DAY   Bucket = "DAY"
+	BUCKET_WEEK  Bucket = "WEEK"
+	BUCKET_MONTH Bucket = "MONTH"
+)
+
+const (
+	DEFAULT_METRICS_RANGE = 30 * 24 * time.Hour
+	MAX_METRICS_RANGE     = 366 * 24 * time.Hour
+)
+
+type DoraMetricsRequest struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	GroupBy    GroupBy   `json:"groupBy"`
+	Bucket     Bucket    `json:"bucket"`
+	AppIds     []int     `json:"appIds"`
+	EnvIds     []int     `json:"envIds"`
+	TeamIds    []int     `json:"teamIds"`
+	AppGroupId int       `json:"appGroupId"`
+	// CheckAuthBatch returns the apps, out of the given ones, the user is allowed to view metrics for
+	CheckAuthBatch func(appIds []int) map[int]bool `json:"-"`
+}
+
+// DoraMetrics holds the four key metrics for a period. Durations are in minutes and are nil when
+// there is nothing to measure in the period.
+type DoraMetrics struct {
+	Deployments               int      `json:"deployments"`
+	SuccessfulDeployments     int      `json:"successfulDeployments"`
+	FailedDeployments         int      `json:"failedDeployments"`
+	DeploymentFrequency       float64  `json:"deploymentFrequency"` // successful deployments per day
+	LeadTimeForChangesMinutes *float64 `json:"leadTimeForChangesMinutes"`
+	ChangeFailureRate         float64  `json:"changeFailureRate"` // percentage of deployments which failed
+	MeanTimeToRestoreMinutes  *float64 `json:"meanTimeToRestoreMinutes"`
+	Restores                  int      `json:"restores"`
+}
+
+type DoraMetricsBucket struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	*DoraMetrics
+}
+
+type DoraMetricsGroup struct {
+	Id      int                  `json:"id,omitempty"`
+	Name    string               `json:"name,omitempty"`
+	Summary *DoraMetrics         `json:"summary"`
+	Buckets []*DoraMetricsBucket `json:"buckets"`
+}
+
+type DoraMetricsResponse struct {
+	From    time.Time           `json:"from"`
+	To      time.Time           `json:"to"`
+	GroupBy GroupBy             `json:"groupBy"`
+	Bucket  Bucket              `json:"bucket"`
+	Summary *DoraMetrics        `json:"summary"`
+	Groups  []*DoraMetricsGroup `json:"groups"`
+}
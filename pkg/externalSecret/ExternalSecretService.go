@@ -0,0 +1,353 @@
+package externalSecret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaskedSecretValue replaces values of resolved secrets wherever deployment values are stored
+const MaskedSecretValue = "*****"
+
+type ExternalSecretConfig struct {
+	// ResolveInOrchestrator renders supported external secrets as kubernetes secrets with values fetched at deploy
+	// time, so that target clusters do not need the external secrets operator. Applies to helm deployments only as
+	// gitops and manifest push would store the values outside the cluster
+	ResolveInOrchestrator bool `env:"EXTERNAL_SECRET_RESOLVE_IN_ORCHESTRATOR" envDefault:"false"`
+	CacheTtlSeconds       int  `env:"EXTERNAL_SECRET_CACHE_TTL_SECONDS" envDefault:"30"`
+	// PathPrefix is the prefix every key read for an app has to start with, {appName} and {envName} are replaced by
+	// the app and environment being deployed so that apps can not read secrets of each other
+	PathPrefix string `env:"EXTERNAL_SECRET_PATH_PREFIX" envDefault:"{appName}/{envName}/"`
+	// VaultAddress and VaultKvMountPath are the only vault the token is sent to, secret stores pointing elsewhere are
+	// rejected
+	VaultAddress     string `env:"EXTERNAL_SECRET_VAULT_ADDRESS"`
+	VaultToken       string `env:"EXTERNAL_SECRET_VAULT_TOKEN"`
+	VaultKvMountPath string `env:"EXTERNAL_SECRET_VAULT_KV_MOUNT_PATH" envDefault:"secret"`
+	// AwsRegion is the only region secrets are read from with the configured credentials
+	AwsRegion          string `env:"EXTERNAL_SECRET_AWS_REGION"`
+	AwsAccessKeyId     string `env:"EXTERNAL_SECRET_AWS_ACCESS_KEY_ID"`
+	AwsSecretAccessKey string `env:"EXTERNAL_SECRET_AWS_SECRET_ACCESS_KEY"`
+	AwsEndpoint        string `env:"EXTERNAL_SECRET_AWS_ENDPOINT"`
+}
+
+func GetExternalSecretConfig() (*ExternalSecretConfig, error) {
+	cfg := &ExternalSecretConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse external secret config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SecretResolutionError is returned when a secret which should be resolved could not be fetched, deployments must not
+// proceed without it
+type SecretResolutionError struct {
+	SecretName string
+	Err        error
+}
+
+func (e *SecretResolutionError) Error() string {
+	return fmt.Sprintf("error in resolving external secret %s, %v", e.SecretName, e.Err)
+}
+
+type ExternalSecretService interface {
+	IsResolutionEnabled() bool
+	// ResolveSecrets replaces references of supported external secrets with the fetched values so that they are
+	// rendered as kubernetes secrets, names of the resolved secrets are returned. Only keys under the path prefix of
+	// the app and environment are read
+	ResolveSecrets(secrets []*bean.ConfigSecretMap, appName string, envName string) ([]string, error)
+	// MaskResolvedSecrets hides the values of resolved secrets in merged deployment values before they are stored
+	MaskResolvedSecrets(mergedValues []byte, secretNames []string) ([]byte, error)
+}
+
+type ExternalSecretServiceImpl struct {
+	logger    *zap.SugaredLogger
+	config    *ExternalSecretConfig
+	lock      *sync.Mutex
+	cache     map[string]*cachedSecretValue
+	providers map[string]SecretProvider
+}
+
+type cachedSecretValue struct {
+	value     []byte
+	expiresOn time.Time
+}
+
+func NewExternalSecretServiceImpl(logger *zap.SugaredLogger, config *ExternalSecretConfig) *ExternalSecretServiceImpl {
+	return &ExternalSecretServiceImpl{
+		logger:    logger,
+		config:    config,
+		lock:      &sync.Mutex{},
+		cache:     make(map[string]*cachedSecretValue),
+		providers: make(map[string]SecretProvider),
+	}
+}
+
+// secretReference is a single key of the kubernetes secret and where its value is read from
+type secretReference struct {
+	secretKey string
+	key       string
+	property  string
+	isBinary  bool
+}
+
+type externalSecretData struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Property string `json:"property,omitempty"`
+	IsBinary bool   `json:"isBinary"`
+}
+
+type esoSecretData struct {
+	SecretStore json.RawMessage `json:"secretStore,omitempty"`
+	EsoData     []struct {
+		SecretKey string `json:"secretKey"`
+		Key       string `json:"key"`
+		Property  string `json:"property,omitempty"`
+	} `json:"esoData,omitempty"`
+}
+
+// esoSecretStore holds the parts of an external secrets operator store spec used to pick the provider endpoint,
+// credentials are always taken from orchestrator config
+type esoSecretStore struct {
+	Vault *struct {
+		Server  string `json:"server"`
+		Path    string `json:"path"`
+		Version string `json:"version"`
+	} `json:"vault,omitempty"`
+	Aws *struct {
+		Service string `json:"service"`
+		Region  string `json:"region"`
+	} `json:"aws,omitempty"`
+}
+
+func (impl *ExternalSecretServiceImpl) IsResolutionEnabled() bool {
+	return impl.config.ResolveInOrchestrator
+}
+
+func (impl *ExternalSecretServiceImpl) ResolveSecrets(secrets []*bean.ConfigSecretMap, appName string, envName string) ([]string, error) {
+	var resolvedSecrets []string
+	if !impl.IsResolutionEnabled() {
+		return resolvedSecrets, nil
+	}
+	if len(appName) == 0 || len(envName) == 0 {
+		return nil, fmt.Errorf("app and environment are required to resolve external secrets")
+	}
+	pathPrefix := strings.NewReplacer("{appName}", appName, "{envName}", envName).Replace(impl.config.PathPrefix)
+	for _, secret := range secrets {
+		if !secret.External {
+			continue
+		}
+		resolved, err := impl.resolveSecret(secret, pathPrefix)
+		if err != nil {
+			impl.logger.Errorw("error in resolving external secret", "err", err, "name", secret.Name, "externalType", secret.ExternalType)
+			return nil, &SecretResolutionError{SecretName: secret.Name, Err: err}
+		}
+		if resolved {
+			resolvedSecrets = append(resolvedSecrets, secret.Name)
+		}
+	}
+	return resolvedSecrets, nil
+}
+
+func (impl *ExternalSecretServiceImpl) resolveSecret(secret *bean.ConfigSecretMap, pathPrefix string) (bool, error) {
+	var references []secretReference
+	var provider SecretProvider
+	var err error
+	isVault := secret.ExternalType == util.HashiCorpVault || secret.ExternalType == util.ESOHashiCorpVault
+	switch secret.ExternalType {
+	case util.HashiCorpVault, util.AWSSecretsManager:
+		if len(secret.SecretData) == 0 {
+			return false, nil
+		}
+		var data []externalSecretData
+		if err = json.Unmarshal(secret.SecretData, &data); err != nil {
+			return false, err
+		}
+		for _, item := range data {
+			references = append(references, secretReference{secretKey: item.Name, key: item.Key, property: item.Property, isBinary: item.IsBinary})
+		}
+		if secret.ExternalType == util.HashiCorpVault {
+			provider, err = impl.getVaultProvider(impl.config.VaultAddress, impl.config.VaultKvMountPath)
+		} else {
+			provider, err = impl.getAwsSecretsManagerProvider(impl.config.AwsRegion)
+		}
+	case util.ESOHashiCorpVault, util.ESOAWSSecretsManager:
+		data := &esoSecretData{}
+		if len(secret.ESOSecretData) == 0 {
+			return false, nil
+		}
+		if err = json.Unmarshal(secret.ESOSecretData, data); err != nil {
+			return false, err
+		}
+		for _, item := range data.EsoData {
+			references = append(references, secretReference{secretKey: item.SecretKey, key: item.Key, property: item.Property})
+		}
+		store := &esoSecretStore{}
+		if len(data.SecretStore) > 0 {
+			if err = json.Unmarshal(data.SecretStore, store); err != nil {
+				return false, err
+			}
+		}
+		if secret.ExternalType == util.ESOHashiCorpVault {
+			if store.Vault != nil {
+				if len(store.Vault.Version) > 0 && store.Vault.Version != "v2" {
+					return false, fmt.Errorf("vault kv %s is not supported, only kv v2 secrets can be resolved", store.Vault.Version)
+				}
+				//the token is only ever sent to the configured vault
+				if len(store.Vault.Server) > 0 && strings.TrimSuffix(store.Vault.Server, "/") != strings.TrimSuffix(impl.config.VaultAddress, "/") {
+					return false, fmt.Errorf("vault server %q is not the configured vault", store.Vault.Server)
+				}
+				if len(store.Vault.Path) > 0 && strings.Trim(store.Vault.Path, "/") != strings.Trim(impl.config.VaultKvMountPath, "/") {
+					return false, fmt.Errorf("vault path %q is not the configured kv mount path", store.Vault.Path)
+				}
+			}
+			provider, err = impl.getVaultProvider(impl.config.VaultAddress, impl.config.VaultKvMountPath)
+		} else {
+			if store.Aws != nil {
+				if len(store.Aws.Service) > 0 && store.Aws.Service != "SecretsManager" {
+					//parameter store is left to the external secrets operator
+					return false, nil
+				}
+				if len(store.Aws.Region) > 0 && store.Aws.Region != impl.config.AwsRegion {
+					return false, fmt.Errorf("aws region %q is not the configured region", store.Aws.Region)
+				}
+			}
+			provider, err = impl.getAwsSecretsManagerProvider(impl.config.AwsRegion)
+		}
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(references) == 0 {
+		return false, nil
+	}
+	for _, reference := range references {
+		key := reference.key
+		if isVault {
+			key = vaultRelativeKey(impl.config.VaultKvMountPath, key)
+		}
+		if !isPathAllowed(key, pathPrefix) {
+			return false, fmt.Errorf("key %q is not under %q, the path allowed for the app and environment", reference.key, pathPrefix)
+		}
+	}
+	secretData := make(map[string]string, len(references))
+	for _, reference := range references {
+		value, err := impl.getSecretValue(secret.ExternalType, provider, reference)
+		if err != nil {
+			return false, err
+		}
+		if reference.isBinary {
+			//binary values are already base64 encoded by the store
+			secretData[reference.secretKey] = string(value)
+		} else {
+			secretData[reference.secretKey] = base64.StdEncoding.EncodeToString(value)
+		}
+	}
+	secret.Data, err = json.Marshal(secretData)
+	if err != nil {
+		return false, err
+	}
+	secret.External = false
+	secret.ExternalType = util.KubernetesSecret
+	secret.SecretData = nil
+	secret.ESOSecretData = nil
+	return true, nil
+}
+
+// isPathAllowed tells whether the key is under the prefix, keys stepping out of it through .. are never allowed
+func isPathAllowed(key string, pathPrefix string) bool {
+	key = strings.TrimPrefix(key, "/")
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return strings.HasPrefix(key, strings.TrimPrefix(pathPrefix, "/"))
+}
+
+func (impl *ExternalSecretServiceImpl) getSecretValue(externalType string, provider SecretProvider, reference secretReference) ([]byte, error) {
+	cacheKey := fmt.Sprintf("%s/%p/%s/%s", externalType, provider, reference.key, reference.property)
+	impl.lock.Lock()
+	cached, ok := impl.cache[cacheKey]
+	impl.lock.Unlock()
+	if ok && time.Now().Before(cached.expiresOn) {
+		return cached.value, nil
+	}
+	value, err := provider.GetSecretValue(reference.key, reference.property)
+	if err != nil {
+		return nil, err
+	}
+	if impl.config.CacheTtlSeconds > 0 {
+		impl.lock.Lock()
+		impl.cache[cacheKey] = &cachedSecretValue{value: value, expiresOn: time.Now().Add(time.Duration(impl.config.CacheTtlSeconds) * time.Second)}
+		impl.lock.Unlock()
+	}
+	return value, nil
+}
+
+func (impl *ExternalSecretServiceImpl) getVaultProvider(address string, mountPath string) (SecretProvider, error) {
+	return impl.getProvider(fmt.Sprintf("vault/%s/%s", address, mountPath), func() (SecretProvider, error) {
+		return NewVaultKVv2Provider(address, impl.config.VaultToken, mountPath)
+	})
+}
+
+func (impl *ExternalSecretServiceImpl) getAwsSecretsManagerProvider(region string) (SecretProvider, error) {
+	return impl.getProvider(fmt.Sprintf("aws/%s", region), func() (SecretProvider, error) {
+		return NewAWSSecretsManagerProvider(region, impl.config.AwsAccessKeyId, impl.config.AwsSecretAccessKey, impl.config.AwsEndpoint)
+	})
+}
+
+func (impl *ExternalSecretServiceImpl) getProvider(providerKey string, newProvider func() (SecretProvider, error)) (SecretProvider, error) {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+	if provider, ok := impl.providers[providerKey]; ok {
+		return provider, nil
+	}
+	provider, err := newProvider()
+	if err != nil {
+		return nil, err
+	}
+	impl.providers[providerKey] = provider
+	return provider, nil
+}
+
+func (impl *ExternalSecretServiceImpl) MaskResolvedSecrets(mergedValues []byte, secretNames []string) ([]byte, error) {
+	if len(secretNames) == 0 {
+		return mergedValues, nil
+	}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(mergedValues, &values); err != nil {
+		return nil, err
+	}
+	configSecrets, ok := values["ConfigSecrets"].(map[string]interface{})
+	if !ok {
+		return mergedValues, nil
+	}
+	secrets, _ := configSecrets["secrets"].([]interface{})
+	resolved := make(map[string]bool, len(secretNames))
+	for _, name := range secretNames {
+		resolved[name] = true
+	}
+	for _, item := range secrets {
+		secret, ok := item.(map[string]interface{})
+		if !ok || !resolved[fmt.Sprint(secret["name"])] {
+			continue
+		}
+		data, _ := secret["data"].(map[string]interface{})
+		for key := range data {
+			data[key] = MaskedSecretValue
+		}
+	}
+	return json.Marshal(values)
+}
@@ -0,0 +1,169 @@
+package externalSecret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// newVaultDevServer stands in for a vault dev server with a kv v2 engine mounted at secret/
+func newVaultDevServer(t *testing.T, secrets map[string]map[string]string, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		data, ok := secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[]}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": data}})
+	}))
+}
+
+func decodeSecretData(t *testing.T, secret *bean.ConfigSecretMap) map[string]string {
+	data := make(map[string]string)
+	assert.Nil(t, json.Unmarshal(secret.Data, &data))
+	for key, value := range data {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		assert.Nil(t, err)
+		data[key] = string(decoded)
+	}
+	return data
+}
+
+func TestExternalSecretService_ResolveVaultSecrets(t *testing.T) {
+	requests := 0
+	server := newVaultDevServer(t, map[string]map[string]string{"payments/prod/db": {"username": "app", "password": "s3cr3t"}}, &requests)
+	defer server.Close()
+	impl := NewExternalSecretServiceImpl(zap.NewNop().Sugar(), &ExternalSecretConfig{
+		ResolveInOrchestrator: true, CacheTtlSeconds: 30, PathPrefix: "{appName}/{envName}/", VaultAddress: server.URL, VaultToken: "root", VaultKvMountPath: "secret",
+	})
+	secrets := []*bean.ConfigSecretMap{
+		{Name: "db", External: true, ExternalType: util.HashiCorpVault,
+			SecretData: json.RawMessage(`[{"key":"secret/data/payments/prod/db","name":"DB_PASSWORD","property":"password"}]`)},
+		{Name: "db-eso", External: true, ExternalType: util.ESOHashiCorpVault,
+			ESOSecretData: json.RawMessage(fmt.Sprintf(`{"secretStore":{"vault":{"server":%q,"path":"secret","version":"v2"}},"esoData":[{"secretKey":"DB_USER","key":"payments/prod/db","property":"username"}]}`, server.URL))},
+		{Name: "plain", Data: json.RawMessage(`{"KEY":"dmFsdWU="}`)},
+	}
+
+	resolved, err := impl.ResolveSecrets(secrets, "payments", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"db", "db-eso"}, resolved)
+	assert.False(t, secrets[0].External)
+	assert.Equal(t, util.KubernetesSecret, secrets[0].ExternalType)
+	assert.Nil(t, secrets[0].SecretData)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "s3cr3t"}, decodeSecretData(t, secrets[0]))
+	assert.Nil(t, secrets[1].ESOSecretData)
+	assert.Equal(t, map[string]string{"DB_USER": "app"}, decodeSecretData(t, secrets[1]))
+	assert.Equal(t, `{"KEY":"dmFsdWU="}`, string(secrets[2].Data))
+
+	//values are served from cache till the ttl expires
+	_, err = impl.ResolveSecrets([]*bean.ConfigSecretMap{{Name: "db", External: true, ExternalType: util.HashiCorpVault,
+		SecretData: json.RawMessage(`[{"key":"secret/data/payments/prod/db","name":"DB_PASSWORD","property":"password"}]`)}}, "payments", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+
+	_, err = impl.ResolveSecrets([]*bean.ConfigSecretMap{{Name: "missing", External: true, ExternalType: util.HashiCorpVault,
+		SecretData: json.RawMessage(`[{"key":"payments/prod/missing","name":"KEY"}]`)}}, "payments", "prod")
+	resolutionErr, ok := err.(*SecretResolutionError)
+	assert.True(t, ok)
+	assert.Equal(t, "missing", resolutionErr.SecretName)
+}
+
+func TestExternalSecretService_ResolveAwsSecretsManagerSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		request := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": request["SecretId"], "SecretString": `{"apiKey":"abc123"}`})
+	}))
+	defer server.Close()
+	impl := NewExternalSecretServiceImpl(zap.NewNop().Sugar(), &ExternalSecretConfig{
+		ResolveInOrchestrator: true, PathPrefix: "{appName}/{envName}/", AwsRegion: "us-east-1", AwsAccessKeyId: "key", AwsSecretAccessKey: "secret", AwsEndpoint: server.URL,
+	})
+	secrets := []*bean.ConfigSecretMap{
+		{Name: "api", External: true, ExternalType: util.AWSSecretsManager,
+			SecretData: json.RawMessage(`[{"key":"payments/prod/api","name":"API_KEY","property":"apiKey"}]`)},
+		{Name: "ssm", External: true, ExternalType: util.ESOAWSSecretsManager,
+			ESOSecretData: json.RawMessage(`{"secretStore":{"aws":{"service":"ParameterStore","region":"us-east-1"}},"esoData":[{"secretKey":"KEY","key":"/payments/prod/key"}]}`)},
+	}
+	resolved, err := impl.ResolveSecrets(secrets, "payments", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"api"}, resolved)
+	assert.Equal(t, map[string]string{"API_KEY": "abc123"}, decodeSecretData(t, secrets[0]))
+	//parameter store secrets are left to the external secrets operator
+	assert.True(t, secrets[1].External)
+}
+
+func TestExternalSecretService_ResolveSecretsOutsideConfig(t *testing.T) {
+	requests := 0
+	server := newVaultDevServer(t, map[string]map[string]string{"orders/prod/db": {"password": "s3cr3t"}}, &requests)
+	defer server.Close()
+	impl := NewExternalSecretServiceImpl(zap.NewNop().Sugar(), &ExternalSecretConfig{
+		ResolveInOrchestrator: true, PathPrefix: "{appName}/{envName}/", VaultAddress: server.URL, VaultToken: "root",
+		VaultKvMountPath: "secret", AwsRegion: "us-east-1",
+	})
+	resolve := func(secret *bean.ConfigSecretMap) error {
+		_, err := impl.ResolveSecrets([]*bean.ConfigSecretMap{secret}, "payments", "prod")
+		return err
+	}
+	//keys of other apps, also when reached through .., are not read
+	assert.NotNil(t, resolve(&bean.ConfigSecretMap{Name: "other", External: true, ExternalType: util.HashiCorpVault,
+		SecretData: json.RawMessage(`[{"key":"secret/data/orders/prod/db","name":"KEY"}]`)}))
+	assert.NotNil(t, resolve(&bean.ConfigSecretMap{Name: "traversal", External: true, ExternalType: util.HashiCorpVault,
+		SecretData: json.RawMessage(`[{"key":"payments/prod/../../orders/prod/db","name":"KEY"}]`)}))
+	//the token is not sent to a vault or mount other than the configured one
+	attacker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request sent to a vault other than the configured one with token %q", r.Header.Get("X-Vault-Token"))
+	}))
+	defer attacker.Close()
+	assert.NotNil(t, resolve(&bean.ConfigSecretMap{Name: "server", External: true, ExternalType: util.ESOHashiCorpVault,
+		ESOSecretData: json.RawMessage(fmt.Sprintf(`{"secretStore":{"vault":{"server":%q}},"esoData":[{"secretKey":"KEY","key":"payments/prod/db"}]}`, attacker.URL))}))
+	assert.NotNil(t, resolve(&bean.ConfigSecretMap{Name: "mount", External: true, ExternalType: util.ESOHashiCorpVault,
+		ESOSecretData: json.RawMessage(`{"secretStore":{"vault":{"path":"other-kv"}},"esoData":[{"secretKey":"KEY","key":"payments/prod/db"}]}`)}))
+	assert.NotNil(t, resolve(&bean.ConfigSecretMap{Name: "region", External: true, ExternalType: util.ESOAWSSecretsManager,
+		ESOSecretData: json.RawMessage(`{"secretStore":{"aws":{"service":"SecretsManager","region":"eu-west-1"}},"esoData":[{"secretKey":"KEY","key":"payments/prod/api"}]}`)}))
+	_, err := impl.ResolveSecrets([]*bean.ConfigSecretMap{{Name: "db", External: true, ExternalType: util.HashiCorpVault,
+		SecretData: json.RawMessage(`[{"key":"payments/prod/db","name":"KEY"}]`)}}, "", "")
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, requests)
+}
+
+func TestExternalSecretService_ResolutionDisabled(t *testing.T) {
+	impl := NewExternalSecretServiceImpl(zap.NewNop().Sugar(), &ExternalSecretConfig{})
+	secret := &bean.ConfigSecretMap{Name: "db", External: true, ExternalType: util.HashiCorpVault,
+		SecretData: json.RawMessage(`[{"key":"payments/db","name":"DB_PASSWORD"}]`)}
+	resolved, err := impl.ResolveSecrets([]*bean.ConfigSecretMap{secret}, "payments", "prod")
+	assert.Nil(t, err)
+	assert.Empty(t, resolved)
+	assert.True(t, secret.External)
+}
+
+func TestExternalSecretService_MaskResolvedSecrets(t *testing.T) {
+	impl := NewExternalSecretServiceImpl(zap.NewNop().Sugar(), &ExternalSecretConfig{})
+	values := `{"replicaCount":1,"ConfigSecrets":{"enabled":true,"secrets":[{"name":"db","external":false,"data":{"DB_PASSWORD":"czNjcjN0"}},{"name":"plain","external":false,"data":{"KEY":"dmFsdWU="}}]}}`
+	masked, err := impl.MaskResolvedSecrets([]byte(values), []string{"db"})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(masked), "czNjcjN0")
+	assert.Contains(t, string(masked), `"DB_PASSWORD":"*****"`)
+	assert.Contains(t, string(masked), `"KEY":"dmFsdWU="`)
+
+	unchanged, err := impl.MaskResolvedSecrets([]byte(values), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, values, string(unchanged))
+}
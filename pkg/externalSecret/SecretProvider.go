@@ -0,0 +1,159 @@
+package externalSecret
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SecretProvider fetches secret values from an external secret store
+type SecretProvider interface {
+	// GetSecretValue returns the value stored at key, when property is set only that field of the secret is returned
+	GetSecretValue(key string, property string) ([]byte, error)
+}
+
+const vaultRequestTimeout = 10 * time.Second
+
+// VaultKVv2Provider reads secrets from a hashicorp vault kv version 2 secret engine
+type VaultKVv2Provider struct {
+	address    string
+	token      string
+	mountPath  string
+	httpClient *http.Client
+}
+
+func NewVaultKVv2Provider(address string, token string, mountPath string) (*VaultKVv2Provider, error) {
+	if len(address) == 0 || len(token) == 0 {
+		return nil, fmt.Errorf("vault address and token are required to resolve vault secrets")
+	}
+	if _, err := url.ParseRequestURI(address); err != nil {
+		return nil, fmt.Errorf("invalid vault address %q, %v", address, err)
+	}
+	if len(mountPath) == 0 {
+		mountPath = "secret"
+	}
+	return &VaultKVv2Provider{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		mountPath:  strings.Trim(mountPath, "/"),
+		httpClient: &http.Client{Timeout: vaultRequestTimeout},
+	}, nil
+}
+
+type vaultKVv2Response struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (impl *VaultKVv2Provider) GetSecretValue(key string, property string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s", impl.address, impl.dataPath(key)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", impl.token)
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	vaultResponse := &vaultKVv2Response{}
+	if err = json.Unmarshal(body, vaultResponse); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("invalid response from vault, %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %d for %q, %s", resp.StatusCode, key, strings.Join(vaultResponse.Errors, ", "))
+	}
+	if len(property) == 0 {
+		return json.Marshal(vaultResponse.Data.Data)
+	}
+	value, ok := vaultResponse.Data.Data[property]
+	if !ok {
+		return nil, fmt.Errorf("property %q not found in vault secret %q", property, key)
+	}
+	return secretFieldValue(value)
+}
+
+// dataPath accepts keys relative to the mount path as well as full kv v2 api paths like secret/data/app
+func (impl *VaultKVv2Provider) dataPath(key string) string {
+	return fmt.Sprintf("%s/data/%s", impl.mountPath, vaultRelativeKey(impl.mountPath, key))
+}
+
+// vaultRelativeKey returns the key relative to the mount path of the kv v2 engine
+func vaultRelativeKey(mountPath string, key string) string {
+	mountPath = strings.Trim(mountPath, "/")
+	key = strings.Trim(key, "/")
+	if strings.HasPrefix(key, mountPath+"/data/") {
+		return strings.TrimPrefix(key, mountPath+"/data/")
+	}
+	return strings.TrimPrefix(key, mountPath+"/")
+}
+
+// AWSSecretsManagerProvider reads secrets from aws secrets manager, the default credential chain is used when no
+// access key is configured
+type AWSSecretsManagerProvider struct {
+	client *secretsmanager.SecretsManager
+}
+
+func NewAWSSecretsManagerProvider(region string, accessKeyId string, secretAccessKey string, endpoint string) (*AWSSecretsManagerProvider, error) {
+	if len(region) == 0 {
+		return nil, fmt.Errorf("aws region is required to resolve aws secrets manager secrets")
+	}
+	config := &aws.Config{Region: aws.String(region)}
+	if len(accessKeyId) > 0 && len(secretAccessKey) > 0 {
+		config.Credentials = credentials.NewStaticCredentials(accessKeyId, secretAccessKey, "")
+	}
+	if len(endpoint) > 0 {
+		config.Endpoint = aws.String(endpoint)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &AWSSecretsManagerProvider{client: secretsmanager.New(sess)}, nil
+}
+
+func (impl *AWSSecretsManagerProvider) GetSecretValue(key string, property string) ([]byte, error) {
+	output, err := impl.client.GetSecretValue(&secretsmanager.GetSecretValueInput{SecretId: aws.String(key)})
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	if output.SecretString != nil {
+		value = []byte(*output.SecretString)
+	} else {
+		value = output.SecretBinary
+	}
+	if len(property) == 0 {
+		return value, nil
+	}
+	fields := make(map[string]interface{})
+	if err = json.Unmarshal(value, &fields); err != nil {
+		return nil, fmt.Errorf("secret %q is not a json object, property %q can not be read", key, property)
+	}
+	field, ok := fields[property]
+	if !ok {
+		return nil, fmt.Errorf("property %q not found in secret %q", property, key)
+	}
+	return secretFieldValue(field)
+}
+
+func secretFieldValue(value interface{}) ([]byte, error) {
+	if stringValue, ok := value.(string); ok {
+		return []byte(stringValue), nil
+	}
+	return json.Marshal(value)
+}
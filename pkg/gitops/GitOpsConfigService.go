@@ -63,21 +63,23 @@ type GitOpsConfigService interface {
 }
 
 const (
-	GitOpsSecretName      = "devtron-gitops-secret"
-	DryrunRepoName        = "devtron-sample-repo-dryrun-"
-	DeleteRepoStage       = "Delete Repo"
-	CommitOnRestStage     = "Commit On Rest"
-	PushStage             = "Push"
-	CloneStage            = "Clone"
-	GetRepoUrlStage       = "Get Repo Url"
-	CreateRepoStage       = "Create Repo"
-	CloneHttp             = "Clone Http"
-	CreateReadmeStage     = "Create Readme"
-	GITHUB_PROVIDER       = "GITHUB"
-	GITLAB_PROVIDER       = "GITLAB"
-	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
-	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
-	BITBUCKET_API_HOST    = "https://api.bitbucket.org/2.0/"
+	GitOpsSecretName          = "devtron-gitops-secret"
+	DryrunRepoName            = "devtron-sample-repo-dryrun-"
+	DeleteRepoStage           = "Delete Repo"
+	CommitOnRestStage         = "Commit On Rest"
+	PushStage                 = "Push"
+	CloneStage                = "Clone"
+	GetRepoUrlStage           = "Get Repo Url"
+	CreateRepoStage           = "Create Repo"
+	CloneHttp                 = "Clone Http"
+	CreateReadmeStage         = "Create Readme"
+	GITHUB_PROVIDER           = "GITHUB"
+	GITLAB_PROVIDER           = "GITLAB"
+	BITBUCKET_PROVIDER        = "BITBUCKET_CLOUD"
+	AZURE_DEVOPS_PROVIDER     = "AZURE_DEVOPS"
+	GITEA_PROVIDER            = "GITEA"
+	BITBUCKET_SERVER_PROVIDER = "BITBUCKET_SERVER"
+	BITBUCKET_API_HOST        = "https://api.bitbucket.org/2.0/"
 )
 
 type DetailedErrorGitOpsConfigResponse struct {
@@ -169,6 +171,20 @@ func (impl *GitOpsConfigServiceImpl) buildGithubOrgUrl(host, orgId string) (orgU
 	return hostUrl.String(), nil
 }
 
+// buildSelfHostedRepoUrlPrefix returns the url all gitops repos of gitea and bitbucket server share, used as the
+// repository credentials prefix in argocd
+func (impl *GitOpsConfigServiceImpl) buildSelfHostedRepoUrlPrefix(request *bean2.GitOpsConfigDto) (string, error) {
+	if strings.ToUpper(request.Provider) == BITBUCKET_SERVER_PROVIDER {
+		return impl.buildGithubOrgUrl(request.Host, path.Join("scm", strings.ToLower(request.BitBucketProjectKey)))
+	}
+	// the same owner the gitea client creates repositories under, the token owner may differ from the username
+	owner, err := impl.gitFactory.GetGiteaOwner(request)
+	if err != nil {
+		return "", err
+	}
+	return impl.buildGithubOrgUrl(request.Host, owner)
+}
+
 func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(ctx context.Context, request *bean2.GitOpsConfigDto) (*bean2.GitOpsConfigDto, error) {
 	impl.logger.Debugw("gitops create request", "req", request)
 	dbConnection := impl.gitOpsRepository.GetConnection()
@@ -205,6 +221,7 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(ctx context.Context, req
 		AzureProject:         request.AzureProjectName,
 		BitBucketWorkspaceId: request.BitBucketWorkspaceId,
 		BitBucketProjectKey:  request.BitBucketProjectKey,
+		GiteaOrgId:           request.GiteaOrgId,
 		AuditLog:             sql.AuditLog{CreatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: request.UserId},
 	}
 	model, err = impl.gitOpsRepository.CreateGitOpsConfig(model, tx)
@@ -292,6 +309,13 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(ctx context.Context, req
 	if strings.ToUpper(request.Provider) == BITBUCKET_PROVIDER {
 		request.Host = util.BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
 	}
+	if strings.ToUpper(request.Provider) == GITEA_PROVIDER || strings.ToUpper(request.Provider) == BITBUCKET_SERVER_PROVIDER {
+		repoUrlPrefix, err := impl.buildSelfHostedRepoUrlPrefix(request)
+		if err != nil {
+			return nil, err
+		}
+		request.Host = repoUrlPrefix
+	}
 	operationComplete := false
 	retryCount := 0
 	for !operationComplete && retryCount < 3 {
@@ -406,6 +430,7 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	model.AzureProject = request.AzureProjectName
 	model.BitBucketWorkspaceId = request.BitBucketWorkspaceId
 	model.BitBucketProjectKey = request.BitBucketProjectKey
+	model.GiteaOrgId = request.GiteaOrgId
 	err = impl.gitOpsRepository.UpdateGitOpsConfig(model, tx)
 	if err != nil {
 		impl.logger.Errorw("error in updating team", "data", model, "err", err)
@@ -493,6 +518,13 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	if strings.ToUpper(request.Provider) == BITBUCKET_PROVIDER {
 		request.Host = util.BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
 	}
+	if strings.ToUpper(request.Provider) == GITEA_PROVIDER || strings.ToUpper(request.Provider) == BITBUCKET_SERVER_PROVIDER {
+		repoUrlPrefix, err := impl.buildSelfHostedRepoUrlPrefix(request)
+		if err != nil {
+			return err
+		}
+		request.Host = repoUrlPrefix
+	}
 	operationComplete := false
 	retryCount := 0
 	for !operationComplete && retryCount < 3 {
@@ -548,6 +580,7 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigById(id int) (*bean2.GitOpsC
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
 	}
 
 	return config, err
@@ -574,6 +607,7 @@ func (impl *GitOpsConfigServiceImpl) GetAllGitOpsConfig() ([]*bean2.GitOpsConfig
 			AzureProjectName:     model.AzureProject,
 			BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 			BitBucketProjectKey:  model.BitBucketProjectKey,
+			GiteaOrgId:           model.GiteaOrgId,
 		}
 		configs = append(configs, config)
 	}
@@ -599,6 +633,7 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigByProvider(provider string)
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
 	}
 
 	return config, err
@@ -679,6 +714,7 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigActive() (*bean2.GitOpsConfi
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
 	}
 	return config, err
 }
@@ -705,6 +741,9 @@ func (impl *GitOpsConfigServiceImpl) GitOpsValidateDryRun(config *bean2.GitOpsCo
 		config.Host = util.BITBUCKET_CLONE_BASE_URL
 		config.BitBucketProjectKey = strings.ToUpper(config.BitBucketProjectKey)
 	}
+	if strings.ToUpper(config.Provider) == BITBUCKET_SERVER_PROVIDER {
+		config.BitBucketProjectKey = strings.ToUpper(config.BitBucketProjectKey)
+	}
 	client, gitService, err := impl.gitFactory.NewClientForValidation(config)
 	if err != nil {
 		impl.logger.Errorw("error in creating new client for validation")
@@ -806,6 +845,14 @@ func (impl *GitOpsConfigServiceImpl) extractErrorMessageByProvider(err error, pr
 			errorMessage := fmt.Errorf("%s", *errorResponse.Message)
 			return errorMessage
 		}
+	} else if provider == GITEA_PROVIDER {
+		if errorResponse, ok := err.(*util.GiteaApiError); ok {
+			return fmt.Errorf("%s", errorResponse.Message)
+		}
+	} else if provider == BITBUCKET_SERVER_PROVIDER {
+		if errorResponse, ok := err.(*util.BitbucketServerApiError); ok {
+			return fmt.Errorf("%s", errorResponse.Message)
+		}
 	}
 	return err
 }
@@ -14,7 +14,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/duration"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -24,6 +26,7 @@ import (
 	resourcehelper "k8s.io/kubectl/pkg/util/resource"
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
@@ -39,6 +42,7 @@ type K8sCapacityService interface {
 	DrainNode(ctx context.Context, request *bean.NodeUpdateRequestDto) (string, error)
 	EditNodeTaints(ctx context.Context, request *bean.NodeUpdateRequestDto) (string, error)
 	GetNode(ctx context.Context, clusterId int, nodeName string) (*corev1.Node, error)
+	GetNodePlatformsByCluster(ctx context.Context, cluster *cluster.ClusterBean, nodeSelector map[string]string, nodeAffinity *corev1.NodeSelector) ([]string, error)
 }
 type K8sCapacityServiceImpl struct {
 	logger                *zap.SugaredLogger
@@ -300,6 +304,88 @@ func (impl *K8sCapacityServiceImpl) GetNodeCapacityDetailByNameAndCluster(ctx co
 	return nodeDetail, nil
 }
 
+// GetNodePlatformsByCluster returns the distinct os/arch platforms of the schedulable nodes of the cluster a pod with
+// the given node selector and required node affinity can be placed on
+func (impl *K8sCapacityServiceImpl) GetNodePlatformsByCluster(ctx context.Context, cluster *cluster.ClusterBean, nodeSelector map[string]string, nodeAffinity *corev1.NodeSelector) ([]string, error) {
+	_, _, k8sClientSet, err := impl.getK8sConfigAndClients(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	nodeList, err := impl.K8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		impl.logger.Errorw("error in getting node list", "err", err, "clusterId", cluster.Id)
+		return nil, err
+	}
+	platformMap := make(map[string]bool)
+	var platforms []string
+	for _, node := range nodeList.Items {
+		if node.Spec.Unschedulable {
+			//cordoned nodes do not receive new pods
+			continue
+		}
+		if !isNodeSelected(&node, nodeSelector, nodeAffinity) {
+			continue
+		}
+		platform := fmt.Sprintf("%s/%s", node.Status.NodeInfo.OperatingSystem, node.Status.NodeInfo.Architecture)
+		if !platformMap[platform] {
+			platformMap[platform] = true
+			platforms = append(platforms, platform)
+		}
+	}
+	sort.Strings(platforms)
+	return platforms, nil
+}
+
+// isNodeSelected tells if the node carries all the labels of the node selector and matches at least one term of
+// the required node affinity, the same way the scheduler filters nodes
+func isNodeSelected(node *corev1.Node, nodeSelector map[string]string, nodeAffinity *corev1.NodeSelector) bool {
+	nodeLabels := labels.Set(node.Labels)
+	if !labels.SelectorFromSet(nodeSelector).Matches(nodeLabels) {
+		return false
+	}
+	if nodeAffinity == nil || len(nodeAffinity.NodeSelectorTerms) == 0 {
+		return true
+	}
+	nodeFields := labels.Set{"metadata.name": node.Name}
+	for _, term := range nodeAffinity.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			//an empty term matches no node
+			continue
+		}
+		if matchesNodeSelectorRequirements(term.MatchExpressions, nodeLabels) && matchesNodeSelectorRequirements(term.MatchFields, nodeFields) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNodeSelectorRequirements(requirements []corev1.NodeSelectorRequirement, values labels.Set) bool {
+	if len(requirements) == 0 {
+		return true
+	}
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := operators[requirement.Operator]
+		if !ok {
+			return false
+		}
+		labelRequirement, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*labelRequirement)
+	}
+	return selector.Matches(values)
+}
+
 func (impl *K8sCapacityServiceImpl) getK8sConfigAndClients(ctx context.Context, cluster *cluster.ClusterBean) (*rest.Config, *http.Client, *kubernetes.Clientset, error) {
 	clusterConfig, err := cluster.GetClusterConfig()
 	if err != nil {
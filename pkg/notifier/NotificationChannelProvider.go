@@ -0,0 +1,318 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	util "github.com/devtron-labs/devtron/util/event"
+)
+
+const (
+	NotificationChannelWebhookUrlKey = "webhookUrl"
+	NotificationChannelRoutingKey    = "routingKey"
+	NotificationChannelSeverityKey   = "severity"
+
+	PagerDutyEventsUrl = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// NotificationChannelProvider delivers notifications to a single destination type. Adding a new channel only
+// needs an implementation registered in newNotificationChannelProviders, config CRUD and routing from
+// notification settings are shared by all providers
+type NotificationChannelProvider interface {
+	Channel() util.Channel
+	ValidateConfig(config map[string]string) error
+	Send(config map[string]string, message *NotificationChannelMessage) error
+}
+
+func newNotificationChannelProviders(client *http.Client) map[util.Channel]NotificationChannelProvider {
+	providers := []NotificationChannelProvider{
+		&TeamsChannelProvider{client: client},
+		&DiscordChannelProvider{client: client},
+		&PagerDutyChannelProvider{client: client, eventsUrl: PagerDutyEventsUrl},
+	}
+	providerMap := make(map[util.Channel]NotificationChannelProvider, len(providers))
+	for _, provider := range providers {
+		providerMap[provider.Channel()] = provider
+	}
+	return providerMap
+}
+
+type NotificationChannelMessage struct {
+	Summary       string //overrides the title derived from the event
+	EventType     util.EventType
+	PipelineType  util.PipelineType
+	PipelineId    int
+	AppName       string
+	EnvName       string
+	PipelineName  string
+	Stage         string
+	TriggeredBy   string
+	FailureReason string
+	DriftSummary  string
+	ApiTokenName  string
+	ExpiresOn     string
+	Link          string
+}
+
+type NotificationChannelMessageField struct {
+	Name  string
+	Value string
+}
+
+func (message *NotificationChannelMessage) Title() string {
+	if len(message.Summary) > 0 {
+		return message.Summary
+	}
+	action := "Build"
+	if message.PipelineType == util.CD {
+		action = "Deployment"
+	}
+	var status string
+	switch message.EventType {
+	case util.Trigger:
+		status = "triggered"
+	case util.Success:
+		status = "succeeded"
+	case util.Fail:
+		status = "failed"
+	case util.Approval:
+		status = "awaiting approval"
+	case util.ConfigDrift:
+		status = "drifted from live state"
+	case util.ApiTokenExpiry:
+		return fmt.Sprintf("API token expiring: %s", message.ApiTokenName)
+	default:
+		status = "updated"
+	}
+	title := fmt.Sprintf("%s %s", action, status)
+	if len(message.AppName) > 0 {
+		title = fmt.Sprintf("%s: %s", title, message.AppName)
+		if len(message.EnvName) > 0 {
+			title = fmt.Sprintf("%s / %s", title, message.EnvName)
+		}
+	}
+	return title
+}
+
+// Fields returns the non empty details of the message in display order
+func (message *NotificationChannelMessage) Fields() []NotificationChannelMessageField {
+	candidates := []NotificationChannelMessageField{
+		{Name: "Application", Value: message.AppName},
+		{Name: "Environment", Value: message.EnvName},
+		{Name: "Pipeline", Value: message.PipelineName},
+		{Name: "Stage", Value: message.Stage},
+		{Name: "Triggered by", Value: message.TriggeredBy},
+		{Name: "Failure reason", Value: message.FailureReason},
+		{Name: "Drift", Value: message.DriftSummary},
+		{Name: "API token", Value: message.ApiTokenName},
+		{Name: "Expires on", Value: message.ExpiresOn},
+	}
+	var fields []NotificationChannelMessageField
+	for _, field := range candidates {
+		if len(field.Value) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// TeamsChannelProvider posts adaptive cards to a Microsoft Teams incoming webhook
+type TeamsChannelProvider struct {
+	client *http.Client
+}
+
+func (impl *TeamsChannelProvider) Channel() util.Channel {
+	return util.Teams
+}
+
+func (impl *TeamsChannelProvider) ValidateConfig(config map[string]string) error {
+	return validateNotificationChannelUrl(config, NotificationChannelWebhookUrlKey)
+}
+
+func (impl *TeamsChannelProvider) Send(config map[string]string, message *NotificationChannelMessage) error {
+	var facts []map[string]string
+	for _, field := range message.Fields() {
+		facts = append(facts, map[string]string{"title": field.Name, "value": field.Value})
+	}
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": message.Title(), "weight": "Bolder", "size": "Medium", "wrap": true, "color": teamsColor(message.EventType)},
+	}
+	if len(facts) > 0 {
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if len(message.Link) > 0 {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": "View details", "url": message.Link}}
+	}
+	payload := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+	return postNotificationChannelPayload(impl.client, config[NotificationChannelWebhookUrlKey], payload)
+}
+
+func teamsColor(eventType util.EventType) string {
+	switch eventType {
+	case util.Success:
+		return "Good"
+	case util.Fail:
+		return "Attention"
+	case util.Approval, util.ConfigDrift, util.ApiTokenExpiry:
+		return "Warning"
+	default:
+		return "Accent"
+	}
+}
+
+// DiscordChannelProvider posts embeds to a Discord channel webhook
+type DiscordChannelProvider struct {
+	client *http.Client
+}
+
+func (impl *DiscordChannelProvider) Channel() util.Channel {
+	return util.Discord
+}
+
+func (impl *DiscordChannelProvider) ValidateConfig(config map[string]string) error {
+	return validateNotificationChannelUrl(config, NotificationChannelWebhookUrlKey)
+}
+
+func (impl *DiscordChannelProvider) Send(config map[string]string, message *NotificationChannelMessage) error {
+	fields := make([]map[string]interface{}, 0)
+	for _, field := range message.Fields() {
+		fields = append(fields, map[string]interface{}{"name": field.Name, "value": field.Value, "inline": true})
+	}
+	embed := map[string]interface{}{
+		"title":  message.Title(),
+		"color":  discordColor(message.EventType),
+		"fields": fields,
+	}
+	if len(message.Link) > 0 {
+		embed["url"] = message.Link
+	}
+	payload := map[string]interface{}{
+		"username": "Devtron",
+		"embeds":   []map[string]interface{}{embed},
+	}
+	return postNotificationChannelPayload(impl.client, config[NotificationChannelWebhookUrlKey], payload)
+}
+
+func discordColor(eventType util.EventType) int {
+	switch eventType {
+	case util.Success:
+		return 0x2ECC71
+	case util.Fail:
+		return 0xE74C3C
+	case util.Approval, util.ConfigDrift, util.ApiTokenExpiry:
+		return 0xE67E22
+	default:
+		return 0x3498DB
+	}
+}
+
+// PagerDutyChannelProvider raises incidents through the PagerDuty Events API v2. Failures trigger an alert
+// deduplicated per pipeline and the next success resolves it, other events are not paged
+type PagerDutyChannelProvider struct {
+	client    *http.Client
+	eventsUrl string
+}
+
+func (impl *PagerDutyChannelProvider) Channel() util.Channel {
+	return util.PagerDuty
+}
+
+func (impl *PagerDutyChannelProvider) ValidateConfig(config map[string]string) error {
+	if len(config[NotificationChannelRoutingKey]) == 0 {
+		return fmt.Errorf("%s is required", NotificationChannelRoutingKey)
+	}
+	if severity, ok := config[NotificationChannelSeverityKey]; ok && len(severity) > 0 {
+		switch severity {
+		case "critical", "error", "warning", "info":
+		default:
+			return fmt.Errorf("invalid %s %q, allowed values are critical, error, warning and info", NotificationChannelSeverityKey, severity)
+		}
+	}
+	return nil
+}
+
+func (impl *PagerDutyChannelProvider) Send(config map[string]string, message *NotificationChannelMessage) error {
+	var eventAction string
+	switch message.EventType {
+	case util.Fail:
+		eventAction = "trigger"
+	case util.Success:
+		eventAction = "resolve"
+	default:
+		return nil
+	}
+	payload := map[string]interface{}{
+		"routing_key":  config[NotificationChannelRoutingKey],
+		"event_action": eventAction,
+		"dedup_key":    fmt.Sprintf("devtron-%s-pipeline-%d", message.PipelineType, message.PipelineId),
+	}
+	if eventAction == "trigger" {
+		severity := config[NotificationChannelSeverityKey]
+		if len(severity) == 0 {
+			severity = "error"
+		}
+		details := make(map[string]string)
+		for _, field := range message.Fields() {
+			details[field.Name] = field.Value
+		}
+		payload["payload"] = map[string]interface{}{
+			"summary":        message.Title(),
+			"source":         "devtron",
+			"severity":       severity,
+			"custom_details": details,
+		}
+		if len(message.Link) > 0 {
+			payload["links"] = []map[string]string{{"href": message.Link, "text": "View in Devtron"}}
+		}
+	}
+	return postNotificationChannelPayload(impl.client, impl.eventsUrl, payload)
+}
+
+func validateNotificationChannelUrl(config map[string]string, key string) error {
+	value := config[key]
+	if len(value) == 0 {
+		return fmt.Errorf("%s is required", key)
+	}
+	parsedUrl, err := url.ParseRequestURI(value)
+	if err != nil || (parsedUrl.Scheme != "https" && parsedUrl.Scheme != "http") || len(parsedUrl.Host) == 0 {
+		return fmt.Errorf("%s is not a valid http url", key)
+	}
+	return nil
+}
+
+func postNotificationChannelPayload(client *http.Client, destinationUrl string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, destinationUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("notification channel responded with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
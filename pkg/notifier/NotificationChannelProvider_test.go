@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNotificationChannelTestServer(t *testing.T, status int, received *map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		err := json.NewDecoder(r.Body).Decode(received)
+		assert.Nil(t, err)
+		w.WriteHeader(status)
+	}))
+}
+
+func testNotificationChannelMessage(eventType util.EventType) *NotificationChannelMessage {
+	return &NotificationChannelMessage{
+		EventType:     eventType,
+		PipelineType:  util.CD,
+		PipelineId:    7,
+		AppName:       "payments",
+		EnvName:       "prod",
+		PipelineName:  "cd-prod",
+		FailureReason: "image pull backoff",
+		Link:          "https://devtron.example.com/dashboard/app/1/cd-details/2/7/11/source-code",
+	}
+}
+
+func TestNotificationChannelMessage_Title(t *testing.T) {
+	message := testNotificationChannelMessage(util.Fail)
+	assert.Equal(t, "Deployment failed: payments / prod", message.Title())
+	message = &NotificationChannelMessage{EventType: util.Success, PipelineType: util.CI, AppName: "payments"}
+	assert.Equal(t, "Build succeeded: payments", message.Title())
+	message.Summary = "Test notification from Devtron"
+	assert.Equal(t, "Test notification from Devtron", message.Title())
+}
+
+func TestTeamsChannelProvider_Send(t *testing.T) {
+	var received map[string]interface{}
+	server := newNotificationChannelTestServer(t, http.StatusOK, &received)
+	defer server.Close()
+
+	provider := &TeamsChannelProvider{client: server.Client()}
+	err := provider.Send(map[string]string{NotificationChannelWebhookUrlKey: server.URL}, testNotificationChannelMessage(util.Fail))
+	assert.Nil(t, err)
+	attachments := received["attachments"].([]interface{})
+	assert.Equal(t, 1, len(attachments))
+	attachment := attachments[0].(map[string]interface{})
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	card := attachment["content"].(map[string]interface{})
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	body := card["body"].([]interface{})
+	assert.Equal(t, "Deployment failed: payments / prod", body[0].(map[string]interface{})["text"])
+	assert.Equal(t, "Attention", body[0].(map[string]interface{})["color"])
+	assert.Equal(t, 4, len(body[1].(map[string]interface{})["facts"].([]interface{})))
+}
+
+func TestDiscordChannelProvider_Send(t *testing.T) {
+	var received map[string]interface{}
+	server := newNotificationChannelTestServer(t, http.StatusNoContent, &received)
+	defer server.Close()
+
+	provider := &DiscordChannelProvider{client: server.Client()}
+	err := provider.Send(map[string]string{NotificationChannelWebhookUrlKey: server.URL}, testNotificationChannelMessage(util.Success))
+	assert.Nil(t, err)
+	embed := received["embeds"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Deployment succeeded: payments / prod", embed["title"])
+	assert.Equal(t, float64(0x2ECC71), embed["color"])
+	assert.Equal(t, "https://devtron.example.com/dashboard/app/1/cd-details/2/7/11/source-code", embed["url"])
+}
+
+func TestDiscordChannelProvider_SendError(t *testing.T) {
+	var received map[string]interface{}
+	server := newNotificationChannelTestServer(t, http.StatusBadRequest, &received)
+	defer server.Close()
+
+	provider := &DiscordChannelProvider{client: server.Client()}
+	err := provider.Send(map[string]string{NotificationChannelWebhookUrlKey: server.URL}, testNotificationChannelMessage(util.Success))
+	assert.NotNil(t, err)
+}
+
+func TestPagerDutyChannelProvider_Send(t *testing.T) {
+	var received map[string]interface{}
+	server := newNotificationChannelTestServer(t, http.StatusAccepted, &received)
+	defer server.Close()
+	provider := &PagerDutyChannelProvider{client: server.Client(), eventsUrl: server.URL}
+	config := map[string]string{NotificationChannelRoutingKey: "routing-key", NotificationChannelSeverityKey: "critical"}
+
+	err := provider.Send(config, testNotificationChannelMessage(util.Fail))
+	assert.Nil(t, err)
+	assert.Equal(t, "routing-key", received["routing_key"])
+	assert.Equal(t, "trigger", received["event_action"])
+	assert.Equal(t, "devtron-CD-pipeline-7", received["dedup_key"])
+	payload := received["payload"].(map[string]interface{})
+	assert.Equal(t, "critical", payload["severity"])
+	assert.Equal(t, "Deployment failed: payments / prod", payload["summary"])
+
+	received = nil
+	err = provider.Send(config, testNotificationChannelMessage(util.Success))
+	assert.Nil(t, err)
+	assert.Equal(t, "resolve", received["event_action"])
+	assert.Equal(t, "devtron-CD-pipeline-7", received["dedup_key"])
+	assert.Nil(t, received["payload"])
+
+	received = nil
+	err = provider.Send(config, testNotificationChannelMessage(util.Trigger))
+	assert.Nil(t, err)
+	assert.Nil(t, received)
+}
+
+func TestNotificationChannelProviders_ValidateConfig(t *testing.T) {
+	providers := newNotificationChannelProviders(http.DefaultClient)
+	tests := []struct {
+		name    string
+		channel util.Channel
+		config  map[string]string
+		wantErr bool
+	}{
+		{name: "teams valid", channel: util.Teams, config: map[string]string{NotificationChannelWebhookUrlKey: "https://example.webhook.office.com/webhookb2/abc"}},
+		{name: "teams missing url", channel: util.Teams, config: map[string]string{}, wantErr: true},
+		{name: "discord invalid url", channel: util.Discord, config: map[string]string{NotificationChannelWebhookUrlKey: "discord.com/api/webhooks/1"}, wantErr: true},
+		{name: "pagerduty valid", channel: util.PagerDuty, config: map[string]string{NotificationChannelRoutingKey: "key"}},
+		{name: "pagerduty invalid severity", channel: util.PagerDuty, config: map[string]string{NotificationChannelRoutingKey: "key", NotificationChannelSeverityKey: "page"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := providers[tt.channel].ValidateConfig(tt.config)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+	_, ok := providers[util.Slack]
+	assert.False(t, ok)
+}
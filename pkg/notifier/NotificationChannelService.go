@@ -0,0 +1,299 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// NOTIFICATION_CHANNEL_REQUEST_TIMEOUT bounds every call to a channel provider, the shared default client has none
+const NOTIFICATION_CHANNEL_REQUEST_TIMEOUT = 10 * time.Second
+
+type NotificationChannelService interface {
+	IsSupportedChannel(channel util2.Channel) bool
+	SaveOrEditNotificationConfig(channelReq []*NotificationChannelConfigDto, userId int32) ([]int, error)
+	FetchNotificationConfigById(id int) (*NotificationChannelConfigDto, error)
+	FetchAllNotificationConfig() ([]*NotificationChannelConfigDto, error)
+	FetchAllNotificationConfigAutocomplete(channel util2.Channel) ([]*NotificationChannelAutoResponse, error)
+	DeleteNotificationConfig(deleteReq *NotificationChannelConfigDto, userId int32) error
+	ValidateNotificationConfig(config *NotificationChannelConfigDto) error
+	SendTestNotification(config *NotificationChannelConfigDto) error
+	// SendNotification delivers an event to every provider based channel configured on the matching notification settings
+	SendNotification(event *NotificationChannelEvent) error
+	// SendNotificationToConfigs delivers a message not tied to a pipeline to the given provider based channel configs
+	SendNotificationToConfigs(configIds []int, message *NotificationChannelMessage) error
+}
+
+type NotificationChannelServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	notificationChannelConfigRepository repository.NotificationChannelConfigRepository
+	notificationSettingsRepository      repository.NotificationSettingsRepository
+	providers                           map[util2.Channel]NotificationChannelProvider
+}
+
+type NotificationChannelConfigRequest struct {
+	Channel util2.Channel                   `json:"channel" validate:"required"`
+	Configs []*NotificationChannelConfigDto `json:"configs" validate:"required,min=1,dive"`
+}
+
+type NotificationChannelConfigDto struct {
+	Id          int               `json:"id" validate:"number"`
+	Channel     util2.Channel     `json:"channel"`
+	ConfigName  string            `json:"configName" validate:"required"`
+	Description string            `json:"description"`
+	Config      map[string]string `json:"config"`
+	OwnerId     int32             `json:"userId"`
+}
+
+type NotificationChannelEvent struct {
+	EventTypeId  int
+	PipelineType util2.PipelineType
+	PipelineId   int
+	TeamId       int
+	AppId        int
+	EnvId        int
+	Message      *NotificationChannelMessage
+}
+
+func NewNotificationChannelServiceImpl(logger *zap.SugaredLogger,
+	notificationChannelConfigRepository repository.NotificationChannelConfigRepository,
+	notificationSettingsRepository repository.NotificationSettingsRepository) *NotificationChannelServiceImpl {
+	return &NotificationChannelServiceImpl{
+		logger:                              logger,
+		notificationChannelConfigRepository: notificationChannelConfigRepository,
+		notificationSettingsRepository:      notificationSettingsRepository,
+		providers:                           newNotificationChannelProviders(&http.Client{Timeout: NOTIFICATION_CHANNEL_REQUEST_TIMEOUT}),
+	}
+}
+
+func (impl *NotificationChannelServiceImpl) IsSupportedChannel(channel util2.Channel) bool {
+	_, ok := impl.providers[channel]
+	return ok
+}
+
+func (impl *NotificationChannelServiceImpl) getProvider(channel util2.Channel) (NotificationChannelProvider, error) {
+	provider, ok := impl.providers[channel]
+	if !ok {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: "unsupported notification channel", UserMessage: fmt.Sprintf("notification channel %q is not supported", channel)}
+	}
+	return provider, nil
+}
+
+func (impl *NotificationChannelServiceImpl) ValidateNotificationConfig(config *NotificationChannelConfigDto) error {
+	provider, err := impl.getProvider(config.Channel)
+	if err != nil {
+		return err
+	}
+	err = provider.ValidateConfig(config.Config)
+	if err != nil {
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	return nil
+}
+
+func (impl *NotificationChannelServiceImpl) SaveOrEditNotificationConfig(channelReq []*NotificationChannelConfigDto, userId int32) ([]int, error) {
+	for _, config := range channelReq {
+		err := impl.ValidateNotificationConfig(config)
+		if err != nil {
+			impl.logger.Errorw("invalid notification channel config", "err", err, "configName", config.ConfigName)
+			return nil, err
+		}
+	}
+	var responseIds []int
+	for _, config := range channelReq {
+		if config.Id != 0 {
+			model, err := impl.notificationChannelConfigRepository.FindOne(config.Id)
+			if err != nil {
+				impl.logger.Errorw("err while fetching notification channel config", "err", err, "id", config.Id)
+				return nil, err
+			}
+			if model.ChannelType != string(config.Channel) {
+				return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: "channel type mismatch", UserMessage: "channel of an existing config cannot be changed"}
+			}
+			model.ConfigName = config.ConfigName
+			model.Description = config.Description
+			model.Config = config.Config
+			model.UpdatedOn = time.Now()
+			model.UpdatedBy = userId
+			_, err = impl.notificationChannelConfigRepository.UpdateConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while updating notification channel config", "err", err, "id", config.Id)
+				return nil, err
+			}
+			responseIds = append(responseIds, model.Id)
+		} else {
+			model := &repository.NotificationChannelConfig{
+				ChannelType: string(config.Channel),
+				ConfigName:  config.ConfigName,
+				Description: config.Description,
+				Config:      config.Config,
+				OwnerId:     userId,
+				AuditLog:    sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+			}
+			_, err := impl.notificationChannelConfigRepository.SaveConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while inserting notification channel config", "err", err, "configName", config.ConfigName)
+				return nil, err
+			}
+			responseIds = append(responseIds, model.Id)
+		}
+	}
+	return responseIds, nil
+}
+
+func (impl *NotificationChannelServiceImpl) FetchNotificationConfigById(id int) (*NotificationChannelConfigDto, error) {
+	config, err := impl.notificationChannelConfigRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("err while fetching notification channel config", "err", err, "id", id)
+		return nil, err
+	}
+	return adaptNotificationChannelConfig(config), nil
+}
+
+func (impl *NotificationChannelServiceImpl) FetchAllNotificationConfig() ([]*NotificationChannelConfigDto, error) {
+	configs, err := impl.notificationChannelConfigRepository.FindAll()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("err while fetching notification channel configs", "err", err)
+		return nil, err
+	}
+	responseDto := make([]*NotificationChannelConfigDto, 0, len(configs))
+	for _, config := range configs {
+		responseDto = append(responseDto, adaptNotificationChannelConfig(config))
+	}
+	return responseDto, nil
+}
+
+func (impl *NotificationChannelServiceImpl) FetchAllNotificationConfigAutocomplete(channel util2.Channel) ([]*NotificationChannelAutoResponse, error) {
+	configs, err := impl.notificationChannelConfigRepository.FindAllByChannelType(string(channel))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("err while fetching notification channel configs", "err", err, "channel", channel)
+		return nil, err
+	}
+	var responseDto []*NotificationChannelAutoResponse
+	for _, config := range configs {
+		responseDto = append(responseDto, &NotificationChannelAutoResponse{Id: config.Id, ConfigName: config.ConfigName})
+	}
+	return responseDto, nil
+}
+
+func (impl *NotificationChannelServiceImpl) DeleteNotificationConfig(deleteReq *NotificationChannelConfigDto, userId int32) error {
+	existingConfig, err := impl.notificationChannelConfigRepository.FindOne(deleteReq.Id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", deleteReq.Id)
+		return err
+	}
+	notifications, err := impl.notificationSettingsRepository.FindNotificationSettingsByConfigIdAndConfigType(deleteReq.Id, existingConfig.ChannelType)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching notifications using channel config", "err", err, "id", deleteReq.Id)
+		return err
+	}
+	if len(notifications) > 0 {
+		impl.logger.Errorw("found notifications using this config, cannot delete", "config", deleteReq)
+		return fmt.Errorf(" Please delete all notifications using this config before deleting")
+	}
+	existingConfig.UpdatedOn = time.Now()
+	existingConfig.UpdatedBy = userId
+	err = impl.notificationChannelConfigRepository.MarkConfigDeleted(existingConfig)
+	if err != nil {
+		impl.logger.Errorw("error in deleting notification channel config", "err", err, "id", existingConfig.Id)
+		return err
+	}
+	return nil
+}
+
+func (impl *NotificationChannelServiceImpl) SendTestNotification(config *NotificationChannelConfigDto) error {
+	if config.Id > 0 && len(config.Config) == 0 {
+		savedConfig, err := impl.FetchNotificationConfigById(config.Id)
+		if err != nil {
+			return err
+		}
+		config = savedConfig
+	}
+	err := impl.ValidateNotificationConfig(config)
+	if err != nil {
+		return err
+	}
+	message := &NotificationChannelMessage{
+		Summary:   "Test notification from Devtron",
+		EventType: util2.Fail,
+	}
+	err = impl.providers[config.Channel].Send(config.Config, message)
+	if err != nil {
+		impl.logger.Errorw("error in sending test notification", "err", err, "channel", config.Channel)
+		return &util.ApiError{HttpStatusCode: http.StatusBadGateway, Code: "502", InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	return nil
+}
+
+func (impl *NotificationChannelServiceImpl) SendNotification(event *NotificationChannelEvent) error {
+	settings, err := impl.notificationSettingsRepository.FindNotificationSettingsForEvent(event.EventTypeId, string(event.PipelineType), event.PipelineId, event.TeamId, event.AppId, event.EnvId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching notification settings for event", "err", err, "pipelineId", event.PipelineId)
+		return err
+	}
+	return impl.SendNotificationToConfigs(impl.getChannelConfigIds(settings), event.Message)
+}
+
+func (impl *NotificationChannelServiceImpl) SendNotificationToConfigs(configIds []int, message *NotificationChannelMessage) error {
+	if len(configIds) == 0 {
+		return nil
+	}
+	configs, err := impl.notificationChannelConfigRepository.FindByIds(configIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching notification channel configs", "err", err, "ids", configIds)
+		return err
+	}
+	var sendErr error
+	for _, config := range configs {
+		provider, ok := impl.providers[util2.Channel(config.ChannelType)]
+		if !ok {
+			continue
+		}
+		err = provider.Send(config.Config, message)
+		if err != nil {
+			impl.logger.Errorw("error in sending notification", "err", err, "channel", config.ChannelType, "configId", config.Id)
+			sendErr = err
+		}
+	}
+	return sendErr
+}
+
+// getChannelConfigIds collects unique config ids of provider based channels across the given settings
+func (impl *NotificationChannelServiceImpl) getChannelConfigIds(settings []*repository.NotificationSettings) []int {
+	var configIds []int
+	seen := make(map[int]bool)
+	for _, setting := range settings {
+		var providers []*Provider
+		err := json.Unmarshal([]byte(setting.Config), &providers)
+		if err != nil {
+			impl.logger.Errorw("error in parsing notification setting providers", "err", err, "settingId", setting.Id)
+			continue
+		}
+		for _, provider := range providers {
+			if provider.ConfigId > 0 && impl.IsSupportedChannel(provider.Destination) && !seen[provider.ConfigId] {
+				seen[provider.ConfigId] = true
+				configIds = append(configIds, provider.ConfigId)
+			}
+		}
+	}
+	return configIds
+}
+
+func adaptNotificationChannelConfig(config *repository.NotificationChannelConfig) *NotificationChannelConfigDto {
+	return &NotificationChannelConfigDto{
+		Id:          config.Id,
+		Channel:     util2.Channel(config.ChannelType),
+		ConfigName:  config.ConfigName,
+		Description: config.Description,
+		Config:      config.Config,
+		OwnerId:     config.OwnerId,
+	}
+}
@@ -59,6 +59,8 @@ type NotificationConfigServiceImpl struct {
 	appRepository                  app.AppRepository
 	userRepository                 repository4.UserRepository
 	ciPipelineMaterialRepository   pipelineConfig.CiPipelineMaterialRepository
+	//destinations other than slack, ses, smtp and webhook are served from the generic channel config
+	notificationChannelConfigRepository repository.NotificationChannelConfigRepository
 }
 
 type NotificationSettingRequest struct {
@@ -168,22 +170,24 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 	sesRepository repository.SESNotificationRepository, smtpRepository repository.SMTPNotificationRepository,
 	teamRepository repository2.TeamRepository,
 	environmentRepository repository3.EnvironmentRepository, appRepository app.AppRepository,
-	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository) *NotificationConfigServiceImpl {
+	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	notificationChannelConfigRepository repository.NotificationChannelConfigRepository) *NotificationConfigServiceImpl {
 	return &NotificationConfigServiceImpl{
-		logger:                         logger,
-		notificationSettingsRepository: notificationSettingsRepository,
-		notificationConfigBuilder:      notificationConfigBuilder,
-		pipelineRepository:             pipelineRepository,
-		ciPipelineRepository:           ciPipelineRepository,
-		sesRepository:                  sesRepository,
-		slackRepository:                slackRepository,
-		webhookRepository:              webhookRepository,
-		smtpRepository:                 smtpRepository,
-		teamRepository:                 teamRepository,
-		environmentRepository:          environmentRepository,
-		appRepository:                  appRepository,
-		userRepository:                 userRepository,
-		ciPipelineMaterialRepository:   ciPipelineMaterialRepository,
+		logger:                              logger,
+		notificationSettingsRepository:      notificationSettingsRepository,
+		notificationConfigBuilder:           notificationConfigBuilder,
+		pipelineRepository:                  pipelineRepository,
+		ciPipelineRepository:                ciPipelineRepository,
+		sesRepository:                       sesRepository,
+		slackRepository:                     slackRepository,
+		webhookRepository:                   webhookRepository,
+		smtpRepository:                      smtpRepository,
+		teamRepository:                      teamRepository,
+		environmentRepository:               environmentRepository,
+		appRepository:                       appRepository,
+		userRepository:                      userRepository,
+		ciPipelineMaterialRepository:        ciPipelineMaterialRepository,
+		notificationChannelConfigRepository: notificationChannelConfigRepository,
 	}
 }
 
@@ -370,6 +374,7 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 			var webhookIds []*int
 			var sesUserIds []int32
 			var smtpUserIds []int32
+			var channelConfigIds []int
 			var providerConfigs []*ProvidersConfig
 			for _, item := range config.Providers {
 				// if item.ConfigId > 0 that means, user is of user repository, else user email is custom
@@ -382,6 +387,8 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 						smtpUserIds = append(smtpUserIds, int32(item.ConfigId))
 					} else if item.Destination == util.Webhook {
 						webhookIds = append(webhookIds, &item.ConfigId)
+					} else {
+						channelConfigIds = append(channelConfigIds, item.ConfigId)
 					}
 				} else {
 					providerConfigs = append(providerConfigs, &ProvidersConfig{Dest: string(item.Destination), Recipient: item.Recipient})
@@ -428,6 +435,16 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 					providerConfigs = append(providerConfigs, &ProvidersConfig{Id: int(item.Id), ConfigName: item.EmailId, Dest: string(util.SMTP)})
 				}
 			}
+			if len(channelConfigIds) > 0 {
+				channelConfigs, err := impl.notificationChannelConfigRepository.FindByIds(channelConfigIds)
+				if err != nil && err != pg.ErrNoRows {
+					impl.logger.Errorw("error in fetching notification channel config", "err", err)
+					return notificationSettingsResponses, deletedItemCount, err
+				}
+				for _, item := range channelConfigs {
+					providerConfigs = append(providerConfigs, &ProvidersConfig{Id: item.Id, ConfigName: item.ConfigName, Dest: item.ChannelType})
+				}
+			}
 			notificationSettingsResponse.ProvidersConfig = providerConfigs
 		}
 
@@ -486,6 +503,7 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 		sesConfigNamesMap := map[int]string{}
 		slackConfigNameMap := map[int]string{}
 		smtpConfigNamesMap := map[int]string{}
+		channelConfigNamesMap := map[int]string{}
 		for _, c := range config.Providers {
 			if util.Slack == c.Destination {
 				if _, ok := slackConfigNameMap[c.ConfigId]; ok {
@@ -502,6 +520,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 					continue
 				}
 				smtpConfigNamesMap[c.ConfigId] = ""
+			} else if util.Webhook != c.Destination {
+				channelConfigNamesMap[c.ConfigId] = ""
 			}
 		}
 
@@ -518,6 +538,10 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 		for k := range smtpConfigNamesMap {
 			smtpIds = append(smtpIds, k)
 		}
+		channelConfigIds := make([]int, 0, len(channelConfigNamesMap))
+		for k := range channelConfigNamesMap {
+			channelConfigIds = append(channelConfigIds, k)
+		}
 
 		if len(slackIds) > 0 {
 			slackConfigs, err := impl.slackRepository.FindByIdsIn(slackIds)
@@ -549,6 +573,16 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 				smtpConfigNamesMap[s.Id] = s.ConfigName
 			}
 		}
+		if len(channelConfigIds) > 0 {
+			channelConfigs, err := impl.notificationChannelConfigRepository.FindByIds(channelConfigIds)
+			if err != nil {
+				impl.logger.Errorw("error on fetch notification channel configs", "err", err)
+				return []ProvidersConfig{}, err
+			}
+			for _, s := range channelConfigs {
+				channelConfigNamesMap[s.Id] = s.ConfigName
+			}
+		}
 		for _, c := range config.Providers {
 			var configName string
 			if c.Destination == util.Slack {
@@ -557,6 +591,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 				configName = sesConfigNamesMap[c.ConfigId]
 			} else if c.Destination == util.SMTP {
 				configName = smtpConfigNamesMap[c.ConfigId]
+			} else if c.Destination != util.Webhook {
+				configName = channelConfigNamesMap[c.ConfigId]
 			}
 			providerConfig := ProvidersConfig{
 				Id:         c.ConfigId,
@@ -0,0 +1,587 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	app2 "github.com/devtron-labs/devtron/pkg/app/status"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	"github.com/devtron-labs/devtron/pkg/prometheus"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	DEFAULT_CANARY_ANALYSIS_INTERVAL_IN_SECONDS = 60
+	DEFAULT_CANARY_ANALYSIS_EVALUATION_COUNT    = 5
+	DEFAULT_CANARY_ANALYSIS_RUNS_LIMIT          = 20
+	CANARY_ANALYSIS_QUERY_TIMEOUT               = 30 * time.Second
+	// CANARY_ANALYSIS_SYSTEM_USER_ID is the user for analysis updates, and for rollbacks when the deploying user is not known
+	CANARY_ANALYSIS_SYSTEM_USER_ID int32 = 1
+)
+
+type CanaryAnalysisService interface {
+	SaveConfig(request *bean.CanaryAnalysisConfigDto) (*bean.CanaryAnalysisConfigDto, error)
+	GetConfig(pipelineId int) (*bean.CanaryAnalysisConfigDto, error)
+	DeleteConfig(pipelineId int, userId int32) error
+	GetRuns(pipelineId int, limit int) ([]*pipelineConfig.CanaryAnalysisRun, error)
+	GetRunById(runId int) (*pipelineConfig.CanaryAnalysisRun, error)
+
+	// ScheduleAnalysis starts a post deploy analysis for a healthy deployment when the pipeline has analysis configured
+	// and the release used the canary or blue-green strategy
+	ScheduleAnalysis(pipelineOverride *chartConfig.PipelineOverride, cdWorkflowRunnerId int) error
+	// EvaluateDueRuns runs one evaluation of every analysis that is due and returns the failed runs which have to be rolled back
+	EvaluateDueRuns() []*pipelineConfig.CanaryAnalysisRun
+	// SetRollbackRunner records the deploy runner of the rollback of a failed analysis, before the rollback is deployed
+	SetRollbackRunner(runId int, cdWorkflowRunnerId int) error
+	// MarkRollbackTriggered records the outcome of the rollback of a failed analysis
+	MarkRollbackTriggered(run *pipelineConfig.CanaryAnalysisRun, rollbackErr error)
+}
+
+type CanaryAnalysisServiceImpl struct {
+	logger                            *zap.SugaredLogger
+	canaryAnalysisRepository          pipelineConfig.CanaryAnalysisRepository
+	pipelineRepository                pipelineConfig.PipelineRepository
+	cdWorkflowRepository              pipelineConfig.CdWorkflowRepository
+	pipelineOverrideRepository        chartConfig.PipelineOverrideRepository
+	pipelineStrategyHistoryRepository repository3.PipelineStrategyHistoryRepository
+	environmentRepository             repository2.EnvironmentRepository
+	pipelineStatusTimelineService     app2.PipelineStatusTimelineService
+	prometheusApi                     func(envName string, prometheusUrl string) (v1.API, error)
+}
+
+func NewCanaryAnalysisServiceImpl(logger *zap.SugaredLogger, canaryAnalysisRepository pipelineConfig.CanaryAnalysisRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, pipelineStrategyHistoryRepository repository3.PipelineStrategyHistoryRepository,
+	environmentRepository repository2.EnvironmentRepository, pipelineStatusTimelineService app2.PipelineStatusTimelineService) *CanaryAnalysisServiceImpl {
+	return &CanaryAnalysisServiceImpl{
+		logger:                            logger,
+		canaryAnalysisRepository:          canaryAnalysisRepository,
+		pipelineRepository:                pipelineRepository,
+		cdWorkflowRepository:              cdWorkflowRepository,
+		pipelineOverrideRepository:        pipelineOverrideRepository,
+		pipelineStrategyHistoryRepository: pipelineStrategyHistoryRepository,
+		environmentRepository:             environmentRepository,
+		pipelineStatusTimelineService:     pipelineStatusTimelineService,
+		prometheusApi:                     prometheus.ContextByEnv,
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) SaveConfig(request *bean.CanaryAnalysisConfigDto) (*bean.CanaryAnalysisConfigDto, error) {
+	if request.IntervalInSeconds == 0 {
+		request.IntervalInSeconds = DEFAULT_CANARY_ANALYSIS_INTERVAL_IN_SECONDS
+	}
+	if request.EvaluationCount == 0 {
+		request.EvaluationCount = DEFAULT_CANARY_ANALYSIS_EVALUATION_COUNT
+	}
+	if request.FailureLimit >= request.EvaluationCount {
+		errMsg := "failure limit must be less than evaluation count"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	existing, err := impl.canaryAnalysisRepository.FindActiveConfigByPipelineId(request.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	config := &pipelineConfig.CanaryAnalysisConfig{
+		PipelineId: request.PipelineId,
+		Active:     true,
+		AuditLog:   sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	if existing != nil && existing.Id > 0 {
+		config = existing
+		config.UpdatedOn = now
+		config.UpdatedBy = request.UserId
+	}
+	config.InitialDelayInSeconds = request.InitialDelayInSeconds
+	config.IntervalInSeconds = request.IntervalInSeconds
+	config.EvaluationCount = request.EvaluationCount
+	config.FailureLimit = request.FailureLimit
+	config.AutoRollback = request.AutoRollback
+
+	dbConnection := impl.canaryAnalysisRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	if config.Id > 0 {
+		err = impl.canaryAnalysisRepository.UpdateConfig(config, tx)
+		if err == nil {
+			err = impl.canaryAnalysisRepository.MarkMetricsDeletedByConfigId(config.Id, request.UserId, tx)
+		}
+	} else {
+		err = impl.canaryAnalysisRepository.SaveConfig(config, tx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis config", "err", err, "config", config)
+		return nil, err
+	}
+	var metrics []*pipelineConfig.CanaryAnalysisMetric
+	for _, metric := range request.Metrics {
+		metrics = append(metrics, &pipelineConfig.CanaryAnalysisMetric{
+			ConfigId:  config.Id,
+			Name:      metric.Name,
+			Query:     metric.Query,
+			Operator:  metric.Operator,
+			Threshold: metric.Threshold,
+			AuditLog:  sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+		})
+	}
+	err = impl.canaryAnalysisRepository.SaveMetrics(metrics, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis metrics", "err", err, "configId", config.Id)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return adaptCanaryAnalysisConfig(config, metrics), nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetConfig(pipelineId int) (*bean.CanaryAnalysisConfigDto, error) {
+	config, err := impl.canaryAnalysisRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "canary analysis config not found", UserMessage: "canary analysis is not configured for this pipeline"}
+		}
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	metrics, err := impl.canaryAnalysisRepository.FindMetricsByConfigId(config.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching canary analysis metrics", "err", err, "configId", config.Id)
+		return nil, err
+	}
+	return adaptCanaryAnalysisConfig(config, metrics), nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) DeleteConfig(pipelineId int, userId int32) error {
+	config, err := impl.canaryAnalysisRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	dbConnection := impl.canaryAnalysisRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	config.Active = false
+	config.UpdatedOn = time.Now()
+	config.UpdatedBy = userId
+	err = impl.canaryAnalysisRepository.UpdateConfig(config, tx)
+	if err == nil {
+		err = impl.canaryAnalysisRepository.MarkMetricsDeletedByConfigId(config.Id, userId, tx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in deleting canary analysis config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	err = impl.canaryAnalysisRepository.AbortRunningRunsByPipelineId(pipelineId, "canary analysis config deleted", userId)
+	if err != nil {
+		impl.logger.Errorw("error in aborting running canary analysis", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetRuns(pipelineId int, limit int) ([]*pipelineConfig.CanaryAnalysisRun, error) {
+	if limit <= 0 {
+		limit = DEFAULT_CANARY_ANALYSIS_RUNS_LIMIT
+	}
+	runs, err := impl.canaryAnalysisRepository.FindRunsByPipelineId(pipelineId, limit)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching canary analysis runs", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetRunById(runId int) (*pipelineConfig.CanaryAnalysisRun, error) {
+	run, err := impl.canaryAnalysisRepository.FindRunById(runId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "canary analysis run not found", UserMessage: "canary analysis run not found"}
+		}
+		impl.logger.Errorw("error in fetching canary analysis run", "err", err, "runId", runId)
+		return nil, err
+	}
+	run.Results, err = impl.canaryAnalysisRepository.FindResultsByRunId(runId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching canary analysis results", "err", err, "runId", runId)
+		return nil, err
+	}
+	return run, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) ScheduleAnalysis(pipelineOverride *chartConfig.PipelineOverride, cdWorkflowRunnerId int) error {
+	config, err := impl.canaryAnalysisRepository.FindActiveConfigByPipelineId(pipelineOverride.PipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", pipelineOverride.PipelineId)
+		return err
+	}
+	// a deployment which is itself the rollback of a failed analysis is not analysed again
+	isRollback, err := impl.canaryAnalysisRepository.ExistsByRollbackRunnerId(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in checking rollback deployment", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return err
+	}
+	if isRollback {
+		return nil
+	}
+	existingRun, err := impl.canaryAnalysisRepository.FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching canary analysis run", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return err
+	}
+	if existingRun != nil && existingRun.Id > 0 {
+		return nil
+	}
+	strategy, err := impl.pipelineStrategyHistoryRepository.GetHistoryByPipelineIdAndWfrId(pipelineOverride.PipelineId, cdWorkflowRunnerId)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	if strategy == nil || !isCanaryAnalysisStrategy(strategy.Strategy) {
+		return nil
+	}
+
+	now := time.Now()
+	err = impl.canaryAnalysisRepository.AbortRunningRunsByPipelineId(pipelineOverride.PipelineId, "superseded by a newer deployment", 1)
+	if err != nil {
+		impl.logger.Errorw("error in aborting running canary analysis", "err", err, "pipelineId", pipelineOverride.PipelineId)
+		return err
+	}
+	run := &pipelineConfig.CanaryAnalysisRun{
+		PipelineId:         pipelineOverride.PipelineId,
+		ConfigId:           config.Id,
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		PipelineOverrideId: pipelineOverride.Id,
+		Status:             pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING,
+		NextEvaluationAt:   now.Add(time.Duration(config.InitialDelayInSeconds) * time.Second),
+		AuditLog:           sql.AuditLog{CreatedOn: now, CreatedBy: 1, UpdatedOn: now, UpdatedBy: 1},
+	}
+	previousRunner, err := impl.cdWorkflowRepository.FindLastSucceededDeployRunnerBefore(pipelineOverride.PipelineId, cdWorkflowRunnerId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching previous successful deployment", "err", err, "pipelineId", pipelineOverride.PipelineId)
+		return err
+	}
+	if previousRunner != nil && previousRunner.Id > 0 {
+		previousOverride, err := impl.pipelineOverrideRepository.FindLatestByCdWorkflowId(previousRunner.CdWorkflowId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching previous pipeline override", "err", err, "cdWorkflowId", previousRunner.CdWorkflowId)
+			return err
+		}
+		if previousOverride != nil && previousOverride.Id > 0 {
+			run.RollbackCdWorkflowRunnerId = previousRunner.Id
+			run.RollbackPipelineOverrideId = previousOverride.Id
+		}
+	}
+	err = impl.canaryAnalysisRepository.SaveRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis run", "err", err, "run", run)
+		return err
+	}
+	impl.saveTimeline(cdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_CANARY_ANALYSIS_STARTED, pipelineConfig.TIMELINE_DESCRIPTION_CANARY_ANALYSIS_STARTED)
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) EvaluateDueRuns() []*pipelineConfig.CanaryAnalysisRun {
+	runs, err := impl.canaryAnalysisRepository.FindDueRuns(time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in fetching due canary analysis runs", "err", err)
+		return nil
+	}
+	var rollbackRuns []*pipelineConfig.CanaryAnalysisRun
+	for _, run := range runs {
+		rollback, err := impl.evaluateRun(run)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating canary analysis run", "err", err, "runId", run.Id)
+			continue
+		}
+		if rollback {
+			rollbackRuns = append(rollbackRuns, run)
+		}
+	}
+	return rollbackRuns
+}
+
+func (impl *CanaryAnalysisServiceImpl) evaluateRun(run *pipelineConfig.CanaryAnalysisRun) (bool, error) {
+	config, err := impl.canaryAnalysisRepository.FindConfigById(run.ConfigId)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	claimed, err := impl.canaryAnalysisRepository.ClaimRun(run.Id, run.NextEvaluationAt, now.Add(time.Duration(config.IntervalInSeconds)*time.Second))
+	if err != nil || !claimed {
+		return false, err
+	}
+	run, err = impl.canaryAnalysisRepository.FindRunById(run.Id)
+	if err != nil {
+		return false, err
+	}
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(run.CdWorkflowRunnerId)
+	if err != nil {
+		return false, err
+	}
+	isLatest, err := impl.cdWorkflowRepository.IsLatestWf(run.PipelineId, runner.CdWorkflowId)
+	if err != nil {
+		return false, err
+	}
+	if !isLatest || !config.Active {
+		run.Status = pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_ABORTED
+		run.Message = "analysed deployment is no longer the latest deployment of the pipeline"
+		if !config.Active {
+			run.Message = "canary analysis config deleted"
+		}
+		return false, impl.finishRun(run, pipelineConfig.TIMELINE_STATUS_CANARY_ANALYSIS_ABORTED)
+	}
+	metrics, err := impl.canaryAnalysisRepository.FindMetricsByConfigId(config.Id)
+	if err != nil {
+		return false, err
+	}
+
+	run.EvaluationsDone += 1
+	var results []*pipelineConfig.CanaryAnalysisResult
+	prometheusApi, err := impl.getPrometheusApi(run.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting prometheus client for canary analysis", "err", err, "pipelineId", run.PipelineId)
+		results = failedCanaryAnalysisResults(metrics, err)
+	} else {
+		results = evaluateCanaryMetrics(prometheusApi, metrics, now)
+	}
+	passed := true
+	for _, result := range results {
+		result.RunId = run.Id
+		result.Evaluation = run.EvaluationsDone
+		passed = passed && result.Passed
+	}
+	if !passed {
+		run.FailedEvaluations += 1
+	}
+	err = impl.canaryAnalysisRepository.SaveResults(results)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis results", "err", err, "runId", run.Id)
+		return false, err
+	}
+
+	run.Status = getCanaryAnalysisRunStatus(run.EvaluationsDone, run.FailedEvaluations, config)
+	switch run.Status {
+	case pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_SUCCEEDED:
+		run.Message = fmt.Sprintf("%d of %d evaluations passed", run.EvaluationsDone-run.FailedEvaluations, run.EvaluationsDone)
+		return false, impl.finishRun(run, pipelineConfig.TIMELINE_STATUS_CANARY_ANALYSIS_SUCCEEDED)
+	case pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED:
+		run.Message = fmt.Sprintf("%d of %d evaluations failed, failure limit is %d", run.FailedEvaluations, run.EvaluationsDone, config.FailureLimit)
+		rollback := config.AutoRollback && run.RollbackPipelineOverrideId > 0
+		if config.AutoRollback && !rollback {
+			run.Message = run.Message + ", no previous successful deployment to roll back to"
+		}
+		return rollback, impl.finishRun(run, pipelineConfig.TIMELINE_STATUS_CANARY_ANALYSIS_FAILED)
+	}
+	run.UpdatedOn = now
+	run.UpdatedBy = CANARY_ANALYSIS_SYSTEM_USER_ID
+	return false, impl.canaryAnalysisRepository.UpdateRun(run)
+}
+
+func (impl *CanaryAnalysisServiceImpl) finishRun(run *pipelineConfig.CanaryAnalysisRun, timelineStatus pipelineConfig.TimelineStatus) error {
+	now := time.Now()
+	run.FinishedOn = now
+	run.UpdatedOn = now
+	run.UpdatedBy = CANARY_ANALYSIS_SYSTEM_USER_ID
+	err := impl.canaryAnalysisRepository.UpdateRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in updating canary analysis run", "err", err, "runId", run.Id)
+		return err
+	}
+	impl.saveTimeline(run.CdWorkflowRunnerId, timelineStatus, run.Message)
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) SetRollbackRunner(runId int, cdWorkflowRunnerId int) error {
+	err := impl.canaryAnalysisRepository.SetRollbackRunnerId(runId, cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in saving rollback runner of canary analysis run", "err", err, "runId", runId, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) MarkRollbackTriggered(run *pipelineConfig.CanaryAnalysisRun, rollbackErr error) {
+	if rollbackErr != nil {
+		impl.logger.Errorw("error in rolling back deployment after failed canary analysis", "err", rollbackErr, "runId", run.Id)
+		impl.saveTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_ROLLBACK_FAILED, fmt.Sprintf("Rollback failed: %s", rollbackErr.Error()))
+		return
+	}
+	impl.saveTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_ROLLBACK_TRIGGERED,
+		fmt.Sprintf("Rolled back to the deployment of release %d.", run.RollbackPipelineOverrideId))
+}
+
+func (impl *CanaryAnalysisServiceImpl) saveTimeline(cdWorkflowRunnerId int, status pipelineConfig.TimelineStatus, description string) {
+	timeline := impl.pipelineStatusTimelineService.GetTimelineDbObjectByTimelineStatusAndTimelineDescription(cdWorkflowRunnerId, status, description, 1)
+	err := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis timeline", "err", err, "timeline", timeline)
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) getPrometheusApi(pipelineId int) (v1.API, error) {
+	cdPipeline, err := impl.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		return nil, err
+	}
+	env, err := impl.environmentRepository.FindById(cdPipeline.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	if env.Cluster == nil || len(env.Cluster.PrometheusEndpoint) == 0 {
+		return nil, fmt.Errorf("prometheus endpoint is not configured for cluster of environment %s", env.Name)
+	}
+	return impl.prometheusApi(env.Name, env.Cluster.PrometheusEndpoint)
+}
+
+func isCanaryAnalysisStrategy(strategy chartRepoRepository.DeploymentStrategy) bool {
+	return strategy == chartRepoRepository.DEPLOYMENT_STRATEGY_CANARY || strategy == chartRepoRepository.DEPLOYMENT_STRATEGY_BLUE_GREEN
+}
+
+func getCanaryAnalysisRunStatus(evaluationsDone int, failedEvaluations int, config *pipelineConfig.CanaryAnalysisConfig) pipelineConfig.CanaryAnalysisRunStatus {
+	if failedEvaluations > config.FailureLimit {
+		return pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED
+	}
+	if evaluationsDone >= config.EvaluationCount {
+		return pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_SUCCEEDED
+	}
+	return pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING
+}
+
+// evaluateCanaryMetrics queries every metric at the given time, a metric passes when the query returns data and
+// every returned sample satisfies the threshold, query errors count as failures
+func evaluateCanaryMetrics(prometheusApi v1.API, metrics []*pipelineConfig.CanaryAnalysisMetric, at time.Time) []*pipelineConfig.CanaryAnalysisResult {
+	var results []*pipelineConfig.CanaryAnalysisResult
+	for _, metric := range metrics {
+		result := &pipelineConfig.CanaryAnalysisResult{
+			MetricId:    metric.Id,
+			MetricName:  metric.Name,
+			EvaluatedOn: at,
+		}
+		results = append(results, result)
+		ctx, cancel := context.WithTimeout(context.Background(), CANARY_ANALYSIS_QUERY_TIMEOUT)
+		value, _, err := prometheusApi.Query(ctx, metric.Query, at)
+		cancel()
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		samples, err := getCanarySampleValues(value)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		result.Passed = true
+		result.Value = samples[0]
+		for _, sample := range samples {
+			if !isCanaryThresholdMet(sample, metric.Operator, metric.Threshold) {
+				result.Passed = false
+				result.Value = sample
+				break
+			}
+		}
+	}
+	return results
+}
+
+func failedCanaryAnalysisResults(metrics []*pipelineConfig.CanaryAnalysisMetric, err error) []*pipelineConfig.CanaryAnalysisResult {
+	var results []*pipelineConfig.CanaryAnalysisResult
+	for _, metric := range metrics {
+		results = append(results, &pipelineConfig.CanaryAnalysisResult{
+			MetricId:    metric.Id,
+			MetricName:  metric.Name,
+			Error:       err.Error(),
+			EvaluatedOn: time.Now(),
+		})
+	}
+	return results
+}
+
+func getCanarySampleValues(value model.Value) ([]float64, error) {
+	var samples []float64
+	switch v := value.(type) {
+	case *model.Scalar:
+		samples = append(samples, float64(v.Value))
+	case model.Vector:
+		for _, sample := range v {
+			samples = append(samples, float64(sample.Value))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported query result type %s, query must return a scalar or an instant vector", value.Type())
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("query returned no data")
+	}
+	for _, sample := range samples {
+		if math.IsNaN(sample) {
+			return nil, fmt.Errorf("query returned NaN")
+		}
+	}
+	return samples, nil
+}
+
+func isCanaryThresholdMet(value float64, operator pipelineConfig.CanaryAnalysisOperator, threshold float64) bool {
+	switch operator {
+	case pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT:
+		return value < threshold
+	case pipelineConfig.CANARY_ANALYSIS_OPERATOR_LTE:
+		return value <= threshold
+	case pipelineConfig.CANARY_ANALYSIS_OPERATOR_GT:
+		return value > threshold
+	case pipelineConfig.CANARY_ANALYSIS_OPERATOR_GTE:
+		return value >= threshold
+	}
+	return false
+}
+
+func adaptCanaryAnalysisConfig(config *pipelineConfig.CanaryAnalysisConfig, metrics []*pipelineConfig.CanaryAnalysisMetric) *bean.CanaryAnalysisConfigDto {
+	dto := &bean.CanaryAnalysisConfigDto{
+		Id:                    config.Id,
+		PipelineId:            config.PipelineId,
+		InitialDelayInSeconds: config.InitialDelayInSeconds,
+		IntervalInSeconds:     config.IntervalInSeconds,
+		EvaluationCount:       config.EvaluationCount,
+		FailureLimit:          config.FailureLimit,
+		AutoRollback:          config.AutoRollback,
+	}
+	for _, metric := range metrics {
+		dto.Metrics = append(dto.Metrics, &bean.CanaryAnalysisMetricDto{
+			Id:        metric.Id,
+			Name:      metric.Name,
+			Query:     metric.Query,
+			Operator:  metric.Operator,
+			Threshold: metric.Threshold,
+		})
+	}
+	return dto
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newFakePrometheus serves instant query responses keyed by promql
+func newFakePrometheus(t *testing.T, responses map[string]string) v1.API {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = r.ParseForm()
+		data, ok := responses[r.Form.Get("query")]
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	t.Cleanup(server.Close)
+	client, err := api.NewClient(api.Config{Address: server.URL})
+	assert.Nil(t, err)
+	return v1.NewAPI(client)
+}
+
+func TestEvaluateCanaryMetrics(t *testing.T) {
+	prometheusApi := newFakePrometheus(t, map[string]string{
+		"error_rate":   `{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1700000000,"0.01"]},{"metric":{"pod":"b"},"value":[1700000000,"0.02"]}]}`,
+		"latency_p99":  `{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1700000000,"0.2"]},{"metric":{"pod":"b"},"value":[1700000000,"0.9"]}]}`,
+		"success_rate": `{"resultType":"scalar","result":[1700000000,"0.99"]}`,
+		"no_data":      `{"resultType":"vector","result":[]}`,
+		"range":        `{"resultType":"matrix","result":[]}`,
+	})
+	metrics := []*pipelineConfig.CanaryAnalysisMetric{
+		{Id: 1, Name: "errors", Query: "error_rate", Operator: pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT, Threshold: 0.05},
+		{Id: 2, Name: "latency", Query: "latency_p99", Operator: pipelineConfig.CANARY_ANALYSIS_OPERATOR_LTE, Threshold: 0.5},
+		{Id: 3, Name: "success", Query: "success_rate", Operator: pipelineConfig.CANARY_ANALYSIS_OPERATOR_GTE, Threshold: 0.95},
+		{Id: 4, Name: "empty", Query: "no_data", Operator: pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT, Threshold: 1},
+		{Id: 5, Name: "matrix", Query: "range", Operator: pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT, Threshold: 1},
+		{Id: 6, Name: "invalid", Query: "sum(", Operator: pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT, Threshold: 1},
+	}
+	results := evaluateCanaryMetrics(prometheusApi, metrics, time.Now())
+	assert.Equal(t, 6, len(results))
+
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, 0.01, results[0].Value)
+
+	assert.False(t, results[1].Passed, "every sample has to satisfy the threshold")
+	assert.Equal(t, 0.9, results[1].Value)
+
+	assert.True(t, results[2].Passed)
+	assert.Equal(t, 0.99, results[2].Value)
+
+	for _, result := range results[3:] {
+		assert.False(t, result.Passed, result.MetricName)
+		assert.NotEmpty(t, result.Error, result.MetricName)
+	}
+}
+
+func TestGetCanaryAnalysisRunStatus(t *testing.T) {
+	config := &pipelineConfig.CanaryAnalysisConfig{EvaluationCount: 3, FailureLimit: 1}
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING, getCanaryAnalysisRunStatus(1, 0, config))
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING, getCanaryAnalysisRunStatus(2, 1, config))
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED, getCanaryAnalysisRunStatus(2, 2, config))
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_SUCCEEDED, getCanaryAnalysisRunStatus(3, 1, config))
+}
+
+func TestIsCanaryThresholdMet(t *testing.T) {
+	assert.True(t, isCanaryThresholdMet(1, pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT, 2))
+	assert.False(t, isCanaryThresholdMet(2, pipelineConfig.CANARY_ANALYSIS_OPERATOR_LT, 2))
+	assert.True(t, isCanaryThresholdMet(2, pipelineConfig.CANARY_ANALYSIS_OPERATOR_LTE, 2))
+	assert.True(t, isCanaryThresholdMet(3, pipelineConfig.CANARY_ANALYSIS_OPERATOR_GT, 2))
+	assert.False(t, isCanaryThresholdMet(2, pipelineConfig.CANARY_ANALYSIS_OPERATOR_GT, 2))
+	assert.True(t, isCanaryThresholdMet(2, pipelineConfig.CANARY_ANALYSIS_OPERATOR_GTE, 2))
+	assert.False(t, isCanaryThresholdMet(2, "==", 2))
+}
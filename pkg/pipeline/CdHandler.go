@@ -117,9 +117,10 @@ type CdHandlerImpl struct {
 	appGroupService                        appGroup2.AppGroupService
 	imageTaggingService                    ImageTaggingService
 	k8sUtil                                *k8s.K8sUtil
+	deploymentApprovalService              DeploymentApprovalService
 }
 
-func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService user.UserService, cdWorkflowRepository pipelineConfig.CdWorkflowRepository, cdWorkflowService CdWorkflowService, ciLogService CiLogService, ciArtifactRepository repository.CiArtifactRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, pipelineRepository pipelineConfig.PipelineRepository, envRepository repository2.EnvironmentRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, ciConfig *CiConfig, helmAppService client.HelmAppService, pipelineOverrideRepository chartConfig.PipelineOverrideRepository, workflowDagExecutor WorkflowDagExecutor, appListingService app.AppListingService, appListingRepository repository.AppListingRepository, pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository, application application.ServiceClient, argoUserService argo.ArgoUserService, deploymentEventHandler app.DeploymentEventHandler, eventClient client2.EventClient, pipelineStatusTimelineResourcesService status.PipelineStatusTimelineResourcesService, pipelineStatusSyncDetailService status.PipelineStatusSyncDetailService, pipelineStatusTimelineService status.PipelineStatusTimelineService, appService app.AppService, appStatusService app_status.AppStatusService, enforcerUtil rbac.EnforcerUtil, installedAppRepository repository3.InstalledAppRepository, installedAppVersionHistoryRepository repository3.InstalledAppVersionHistoryRepository, appRepository app2.AppRepository, appGroupService appGroup2.AppGroupService, imageTaggingService ImageTaggingService, k8sUtil *k8s.K8sUtil) *CdHandlerImpl {
+func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService user.UserService, cdWorkflowRepository pipelineConfig.CdWorkflowRepository, cdWorkflowService CdWorkflowService, ciLogService CiLogService, ciArtifactRepository repository.CiArtifactRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, pipelineRepository pipelineConfig.PipelineRepository, envRepository repository2.EnvironmentRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, ciConfig *CiConfig, helmAppService client.HelmAppService, pipelineOverrideRepository chartConfig.PipelineOverrideRepository, workflowDagExecutor WorkflowDagExecutor, appListingService app.AppListingService, appListingRepository repository.AppListingRepository, pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository, application application.ServiceClient, argoUserService argo.ArgoUserService, deploymentEventHandler app.DeploymentEventHandler, eventClient client2.EventClient, pipelineStatusTimelineResourcesService status.PipelineStatusTimelineResourcesService, pipelineStatusSyncDetailService status.PipelineStatusSyncDetailService, pipelineStatusTimelineService status.PipelineStatusTimelineService, appService app.AppService, appStatusService app_status.AppStatusService, enforcerUtil rbac.EnforcerUtil, installedAppRepository repository3.InstalledAppRepository, installedAppVersionHistoryRepository repository3.InstalledAppVersionHistoryRepository, appRepository app2.AppRepository, appGroupService appGroup2.AppGroupService, imageTaggingService ImageTaggingService, k8sUtil *k8s.K8sUtil, deploymentApprovalService DeploymentApprovalService) *CdHandlerImpl {
 	return &CdHandlerImpl{
 		Logger:                                 Logger,
 		cdConfig:                               cdConfig,
@@ -155,6 +156,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService
 		appGroupService:                        appGroupService,
 		imageTaggingService:                    imageTaggingService,
 		k8sUtil:                                k8sUtil,
+		deploymentApprovalService:              deploymentApprovalService,
 	}
 }
 
@@ -556,14 +558,19 @@ func (impl *CdHandlerImpl) CancelStage(workflowRunnerId int, userId int32) (int,
 		impl.Logger.Errorw("error in getting rest config by cluster id", "err", err)
 		return 0, err
 	}
-	runningWf, err := impl.cdService.GetWorkflow(workflowRunner.Name, workflowRunner.Namespace, restConfig, isExtCluster)
-	if err != nil {
-		impl.Logger.Errorw("cannot find workflow ", "name", workflowRunner.Name)
-		return 0, errors.New("cannot find workflow " + workflowRunner.Name)
+	workflowName, workflowNamespace := workflowRunner.Name, workflowRunner.Namespace
+	// system workflows run as k8s jobs, there is no argo workflow to look up
+	if workflowRunner.ExecutorType != pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+		runningWf, err := impl.cdService.GetWorkflow(workflowRunner.Name, workflowRunner.Namespace, restConfig, isExtCluster)
+		if err != nil {
+			impl.Logger.Errorw("cannot find workflow ", "name", workflowRunner.Name)
+			return 0, errors.New("cannot find workflow " + workflowRunner.Name)
+		}
+		workflowName, workflowNamespace = runningWf.Name, runningWf.Namespace
 	}
 
 	// Terminate workflow
-	err = impl.cdService.TerminateWorkflow(runningWf.Name, runningWf.Namespace, restConfig, isExtCluster)
+	err = impl.cdService.TerminateWorkflow(workflowRunner.ExecutorType, workflowName, workflowNamespace, restConfig, isExtCluster)
 	if err != nil {
 		impl.Logger.Error("cannot terminate wf runner", "err", err)
 		return 0, err
@@ -805,6 +812,15 @@ func (impl *CdHandlerImpl) GetCdBuildHistory(appId int, environmentId int, pipel
 		impl.Logger.Errorw("error in fetching imageCommentsDataMap", "err", err, "artifactIds", artifactIds, "appId", appId)
 		return cdWorkflowArtifact, err
 	}
+	var wfrIds []int
+	for _, item := range cdWorkflowArtifact {
+		wfrIds = append(wfrIds, item.Id)
+	}
+	approvalsByWfrId, err := impl.deploymentApprovalService.GetApprovalsByWorkflowRunnerIds(wfrIds)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching deployment approvals", "err", err, "wfrIds", wfrIds)
+		return cdWorkflowArtifact, err
+	}
 	for i, item := range cdWorkflowArtifact {
 
 		if imageTagsDataMap[item.CiArtifactId] != nil {
@@ -813,6 +829,9 @@ func (impl *CdHandlerImpl) GetCdBuildHistory(appId int, environmentId int, pipel
 		if imageCommentsDataMap[item.CiArtifactId] != nil {
 			item.ImageComment = imageCommentsDataMap[item.CiArtifactId]
 		}
+		if approvalsByWfrId[item.Id] != nil {
+			item.ApprovalRequest = approvalsByWfrId[item.Id]
+		}
 		cdWorkflowArtifact[i] = item
 	}
 	return cdWorkflowArtifact, nil
@@ -53,7 +53,7 @@ type CdWorkflowService interface {
 	GetWorkflow(name string, namespace string, restConfig *rest.Config, isExtRun bool) (*v1alpha1.Workflow, error)
 	ListAllWorkflows(namespace string) (*v1alpha1.WorkflowList, error)
 	UpdateWorkflow(wf *v1alpha1.Workflow) (*v1alpha1.Workflow, error)
-	TerminateWorkflow(name string, namespace string, restConfig *rest.Config, isExtRun bool) error
+	TerminateWorkflow(executorType pipelineConfig.WorkflowExecutorType, name string, namespace string, restConfig *rest.Config, isExtRun bool) error
 }
 
 const (
@@ -62,14 +62,15 @@ const (
 )
 
 type CdWorkflowServiceImpl struct {
-	Logger               *zap.SugaredLogger
-	config               *rest.Config
-	cdConfig             *CdConfig
-	appService           app.AppService
-	envRepository        repository.EnvironmentRepository
-	globalCMCSService    GlobalCMCSService
-	argoWorkflowExecutor ArgoWorkflowExecutor
-	k8sUtil              *k8s.K8sUtil
+	Logger                 *zap.SugaredLogger
+	config                 *rest.Config
+	cdConfig               *CdConfig
+	appService             app.AppService
+	envRepository          repository.EnvironmentRepository
+	globalCMCSService      GlobalCMCSService
+	argoWorkflowExecutor   ArgoWorkflowExecutor
+	systemWorkflowExecutor SystemWorkflowExecutor
+	k8sUtil                *k8s.K8sUtil
 }
 
 type CdWorkflowRequest struct {
@@ -129,14 +130,15 @@ const POST = "POST"
 
 func NewCdWorkflowServiceImpl(Logger *zap.SugaredLogger, envRepository repository.EnvironmentRepository, cdConfig *CdConfig,
 	appService app.AppService, globalCMCSService GlobalCMCSService, argoWorkflowExecutor ArgoWorkflowExecutor,
-	k8sUtil *k8s.K8sUtil) (*CdWorkflowServiceImpl, error) {
+	systemWorkflowExecutor SystemWorkflowExecutor, k8sUtil *k8s.K8sUtil) (*CdWorkflowServiceImpl, error) {
 	cdWorkflowService := &CdWorkflowServiceImpl{Logger: Logger,
-		cdConfig:             cdConfig,
-		appService:           appService,
-		envRepository:        envRepository,
-		globalCMCSService:    globalCMCSService,
-		argoWorkflowExecutor: argoWorkflowExecutor,
-		k8sUtil:              k8sUtil,
+		cdConfig:               cdConfig,
+		appService:             appService,
+		envRepository:          envRepository,
+		globalCMCSService:      globalCMCSService,
+		argoWorkflowExecutor:   argoWorkflowExecutor,
+		systemWorkflowExecutor: systemWorkflowExecutor,
+		k8sUtil:                k8sUtil,
 	}
 	restConfig, err := k8sUtil.GetK8sInClusterRestConfig()
 	if err != nil {
@@ -178,6 +180,7 @@ func (impl *CdWorkflowServiceImpl) SubmitWorkflow(workflowRequest *CdWorkflowReq
 	workflowTemplate.TTLValue = &ttl
 	workflowTemplate.WorkflowId = workflowRequest.WorkflowId
 	workflowTemplate.WorkflowRunnerId = workflowRequest.WorkflowRunnerId
+	workflowTemplate.WorkflowType = CD_WORKFLOW_NAME
 	workflowTemplate.WorkflowRequestJson = string(workflowJson)
 	workflowTemplate.PrePostDeploySteps = workflowRequest.PrePostDeploySteps
 	workflowTemplate.RefPlugins = workflowRequest.RefPlugins
@@ -320,6 +323,8 @@ func (impl *CdWorkflowServiceImpl) updateBlobStorageConfig(workflowRequest *CdWo
 func (impl *CdWorkflowServiceImpl) getWorkflowExecutor(executorType pipelineConfig.WorkflowExecutorType) WorkflowExecutor {
 	if executorType == pipelineConfig.WORKFLOW_EXECUTOR_TYPE_AWF {
 		return impl.argoWorkflowExecutor
+	} else if executorType == pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+		return impl.systemWorkflowExecutor
 	}
 	impl.Logger.Warnw("workflow executor not found", "type", executorType)
 	return nil
@@ -378,8 +383,14 @@ func (impl *CdWorkflowServiceImpl) GetWorkflow(name string, namespace string, re
 	return workflow, err
 }
 
-func (impl *CdWorkflowServiceImpl) TerminateWorkflow(name string, namespace string, restConfig *rest.Config, isExtRun bool) error {
+func (impl *CdWorkflowServiceImpl) TerminateWorkflow(executorType pipelineConfig.WorkflowExecutorType, name string, namespace string, restConfig *rest.Config, isExtRun bool) error {
 	impl.Logger.Debugw("terminating wf", "name", name)
+	if executorType == pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+		if !isExtRun {
+			restConfig = impl.config
+		}
+		return impl.systemWorkflowExecutor.TerminateWorkflow(name, namespace, restConfig)
+	}
 	var wfClient v1alpha12.WorkflowInterface
 	var err error
 	if isExtRun {
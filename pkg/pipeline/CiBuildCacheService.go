@@ -0,0 +1,504 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	BUILD_CACHE_KEY_PREFIX                = "cache"
+	BUILD_CACHE_KEY_HASH_LENGTH           = 12
+	BUILD_CACHE_MAX_BRANCH_LENGTH         = 80
+	BUILD_CACHE_MAX_IMPORT_REFS           = 3
+	BUILD_CACHE_DEFAULT_EXPORT_MODE       = "max"
+	BUILD_CACHE_SYSTEM_USER_ID      int32 = 1
+)
+
+var buildCacheTagInvalidCharRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+type CiBuildCacheService interface {
+	SaveConfig(request *bean.CiBuildCacheConfigDto) (*bean.CiBuildCacheConfigDto, error)
+	GetConfig(ciPipelineId int) (*bean.CiBuildCacheConfigDto, error)
+	DeleteConfig(ciPipelineId int, userId int32) error
+	// InvalidateCache makes the next build of the pipeline miss every existing cache, existing cache images are pruned
+	InvalidateCache(ciPipelineId int, userId int32) (*bean.CiBuildCacheConfigDto, error)
+	GetCacheEntries(ciPipelineId int) ([]*bean.CiBuildCacheEntryDto, error)
+
+	// GetBuildCacheRequestForCi gives the cache instructions for the ci runner of the workflow, nil when the pipeline
+	// has no build cache configured and the runner defaults apply
+	GetBuildCacheRequestForCi(ciPipelineId int, ciWorkflowId int, registryUrl string, dockerRepository string, ciProjectDetails []CiProjectDetails, skipImport bool) (*bean.BuildCacheRequest, error)
+	// SaveBuildCacheMetrics records the cache key and the layer hits and misses of a build on its workflow, indexes
+	// the exported cache image and marks the cache images the build removed pruned
+	SaveBuildCacheMetrics(ciWorkflow *pipelineConfig.CiWorkflow, metrics *bean.BuildCacheMetrics) error
+	// ApplyRetentionPolicy expires cache images unused for longer than the retention period and those beyond the
+	// per pipeline limit
+	ApplyRetentionPolicy()
+}
+
+type CiBuildCacheServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	ciBuildCacheRepository       pipelineConfig.CiBuildCacheRepository
+	ciPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciWorkflowRepository         pipelineConfig.CiWorkflowRepository
+	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository
+	ciConfig                     *CiConfig
+}
+
+func NewCiBuildCacheServiceImpl(logger *zap.SugaredLogger, ciBuildCacheRepository pipelineConfig.CiBuildCacheRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
+	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository, ciConfig *CiConfig) *CiBuildCacheServiceImpl {
+	return &CiBuildCacheServiceImpl{
+		logger:                       logger,
+		ciBuildCacheRepository:       ciBuildCacheRepository,
+		ciPipelineRepository:         ciPipelineRepository,
+		ciWorkflowRepository:         ciWorkflowRepository,
+		ciTemplateOverrideRepository: ciTemplateOverrideRepository,
+		ciConfig:                     ciConfig,
+	}
+}
+
+func (impl *CiBuildCacheServiceImpl) SaveConfig(request *bean.CiBuildCacheConfigDto) (*bean.CiBuildCacheConfigDto, error) {
+	ciPipeline, err := impl.ciPipelineRepository.FindById(request.CiPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline > 0 {
+		errMsg := "build cache can only be configured on a pipeline which builds from source"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	if len(request.ExportMode) == 0 {
+		request.ExportMode = BUILD_CACHE_DEFAULT_EXPORT_MODE
+	}
+	request.CacheRepository = strings.TrimSpace(request.CacheRepository)
+	if len(request.CacheRepository) > 0 {
+		registryUrl, dockerRepository, err := impl.getDockerRepository(ciPipeline)
+		if err != nil {
+			return nil, err
+		}
+		err = validateBuildCacheRepository(request.CacheRepository, registryUrl, dockerRepository)
+		if err != nil {
+			return nil, err
+		}
+	}
+	existing, err := impl.ciBuildCacheRepository.FindActiveConfigByCiPipelineId(request.CiPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching build cache config", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	model := &pipelineConfig.CiBuildCacheConfig{
+		CiPipelineId: request.CiPipelineId,
+		Active:       true,
+		AuditLog:     sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	if existing != nil && existing.Id > 0 {
+		model = existing
+		model.UpdatedOn = now
+		model.UpdatedBy = request.UserId
+	}
+	model.Mode = request.Mode
+	model.CacheRepository = request.CacheRepository
+	model.ExportMode = request.ExportMode
+	model.KeyByBranch = request.KeyByBranch
+	model.KeyFiles = strings.Join(cleanBuildCacheKeyFiles(request.KeyFiles), ",")
+	if model.Id > 0 {
+		err = impl.ciBuildCacheRepository.UpdateConfig(model)
+	} else {
+		err = impl.ciBuildCacheRepository.SaveConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving build cache config", "err", err, "config", model)
+		return nil, err
+	}
+	if model.Mode == pipelineConfig.BUILD_CACHE_MODE_DISABLED {
+		// caches are not imported anymore, hand them to the next build for removal
+		err = impl.ciBuildCacheRepository.ExpireLiveEntriesByCiPipelineId(model.CiPipelineId, request.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in expiring build cache entries", "err", err, "ciPipelineId", model.CiPipelineId)
+			return nil, err
+		}
+	}
+	return adaptCiBuildCacheConfig(model), nil
+}
+
+// getDockerRepository gives the registry url and repository the pipeline pushes the built image to
+func (impl *CiBuildCacheServiceImpl) getDockerRepository(ciPipeline *pipelineConfig.CiPipeline) (string, string, error) {
+	if ciPipeline.IsDockerConfigOverridden {
+		templateOverride, err := impl.ciTemplateOverrideRepository.FindByCiPipelineId(ciPipeline.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ci template override", "err", err, "ciPipelineId", ciPipeline.Id)
+			return "", "", err
+		}
+		registryUrl := ""
+		if templateOverride.DockerRegistry != nil {
+			registryUrl = templateOverride.DockerRegistry.RegistryURL
+		}
+		return registryUrl, templateOverride.DockerRepository, nil
+	}
+	if ciPipeline.CiTemplate == nil {
+		return "", "", nil
+	}
+	registryUrl := ""
+	if ciPipeline.CiTemplate.DockerRegistry != nil {
+		registryUrl = ciPipeline.CiTemplate.DockerRegistry.RegistryURL
+	}
+	return registryUrl, ciPipeline.CiTemplate.DockerRepository, nil
+}
+
+func (impl *CiBuildCacheServiceImpl) GetConfig(ciPipelineId int) (*bean.CiBuildCacheConfigDto, error) {
+	model, err := impl.ciBuildCacheRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "build cache config not found", UserMessage: "no build cache configured for this pipeline"}
+		}
+		impl.logger.Errorw("error in fetching build cache config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return adaptCiBuildCacheConfig(model), nil
+}
+
+func (impl *CiBuildCacheServiceImpl) DeleteConfig(ciPipelineId int, userId int32) error {
+	model, err := impl.ciBuildCacheRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching build cache config", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.ciBuildCacheRepository.UpdateConfig(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting build cache config", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+	// entries stay live, they are expired by retention as builds no longer use them
+	return nil
+}
+
+func (impl *CiBuildCacheServiceImpl) InvalidateCache(ciPipelineId int, userId int32) (*bean.CiBuildCacheConfigDto, error) {
+	model, err := impl.ciBuildCacheRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			errMsg := "no build cache configured for this pipeline, trigger the build with cache invalidation instead"
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+		}
+		impl.logger.Errorw("error in fetching build cache config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	model.Generation = model.Generation + 1
+	model.InvalidatedOn = now
+	model.UpdatedOn = now
+	model.UpdatedBy = userId
+	err = impl.ciBuildCacheRepository.UpdateConfig(model)
+	if err != nil {
+		impl.logger.Errorw("error in invalidating build cache", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	err = impl.ciBuildCacheRepository.ExpireLiveEntriesByCiPipelineId(ciPipelineId, userId)
+	if err != nil {
+		impl.logger.Errorw("error in expiring build cache entries", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	impl.logger.Infow("build cache invalidated", "ciPipelineId", ciPipelineId, "generation", model.Generation, "userId", userId)
+	return adaptCiBuildCacheConfig(model), nil
+}
+
+func (impl *CiBuildCacheServiceImpl) GetCacheEntries(ciPipelineId int) ([]*bean.CiBuildCacheEntryDto, error) {
+	entries, err := impl.ciBuildCacheRepository.FindLiveEntriesByCiPipelineId(ciPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching build cache entries", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	result := make([]*bean.CiBuildCacheEntryDto, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &bean.CiBuildCacheEntryDto{
+			Id:         entry.Id,
+			CacheKey:   entry.CacheKey,
+			CacheRef:   entry.CacheRef,
+			LastUsedOn: entry.LastUsedOn,
+		})
+	}
+	return result, nil
+}
+
+func (impl *CiBuildCacheServiceImpl) GetBuildCacheRequestForCi(ciPipelineId int, ciWorkflowId int, registryUrl string, dockerRepository string,
+	ciProjectDetails []CiProjectDetails, skipImport bool) (*bean.BuildCacheRequest, error) {
+	config, err := impl.ciBuildCacheRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching build cache config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	pruneRefs, err := impl.getPruneRefs(ciPipelineId, ciWorkflowId)
+	if err != nil {
+		return nil, err
+	}
+	request := &bean.BuildCacheRequest{
+		Mode:      config.Mode,
+		PruneRefs: pruneRefs,
+	}
+	if config.Mode == pipelineConfig.BUILD_CACHE_MODE_DISABLED {
+		return request, nil
+	}
+	cacheRepository := config.CacheRepository
+	if len(cacheRepository) == 0 {
+		cacheRepository = dockerRepository
+	}
+	branch := ""
+	if config.KeyByBranch {
+		branch = getBuildCacheBranch(ciProjectDetails)
+	}
+	request.CacheRepository = GetBuildCacheRepository(registryUrl, cacheRepository)
+	request.KeyPrefix = GetBuildCacheKeyPrefix(config.Generation, branch)
+	request.ExportMode = config.ExportMode
+	if len(config.KeyFiles) > 0 {
+		request.KeyFiles = strings.Split(config.KeyFiles, ",")
+	}
+	request.SkipImport = skipImport
+	if !skipImport {
+		entries, err := impl.ciBuildCacheRepository.FindLiveEntriesByCiPipelineId(ciPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching build cache entries", "err", err, "ciPipelineId", ciPipelineId)
+			return nil, err
+		}
+		request.ImportRefs = GetBuildCacheImportRefs(request.KeyPrefix, config.Generation, entries)
+	}
+	return request, nil
+}
+
+// getPruneRefs hands the expired cache images to the build being triggered, they are marked pruned once the build
+// reports them removed and are handed to a later build otherwise
+func (impl *CiBuildCacheServiceImpl) getPruneRefs(ciPipelineId int, ciWorkflowId int) ([]string, error) {
+	expired, err := impl.ciBuildCacheRepository.ClaimExpiredEntriesForPrune(ciPipelineId, ciWorkflowId, getFinishedCiWorkflowStatuses(), BUILD_CACHE_SYSTEM_USER_ID)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in claiming expired build cache entries", "err", err, "ciPipelineId", ciPipelineId, "ciWorkflowId", ciWorkflowId)
+		return nil, err
+	}
+	var pruneRefs []string
+	for _, entry := range expired {
+		pruneRefs = append(pruneRefs, entry.CacheRef)
+	}
+	return pruneRefs, nil
+}
+
+func getFinishedCiWorkflowStatuses() []string {
+	return []string{string(v1alpha1.NodeSucceeded), string(v1alpha1.NodeError), string(v1alpha1.NodeFailed), WorkflowCancel, pipelineConfig.WorkflowAborted}
+}
+
+func (impl *CiBuildCacheServiceImpl) SaveBuildCacheMetrics(ciWorkflow *pipelineConfig.CiWorkflow, metrics *bean.BuildCacheMetrics) error {
+	if ciWorkflow == nil || metrics == nil {
+		return nil
+	}
+	err := impl.ciBuildCacheRepository.MarkEntriesPruned(ciWorkflow.CiPipelineId, ciWorkflow.Id, metrics.PrunedRefs, BUILD_CACHE_SYSTEM_USER_ID)
+	if err != nil {
+		impl.logger.Errorw("error in marking build cache entries pruned", "err", err, "ciWorkflowId", ciWorkflow.Id)
+		return err
+	}
+	ciWorkflow.BuildCacheKey = metrics.CacheKey
+	ciWorkflow.BuildCacheHits = metrics.Hits
+	ciWorkflow.BuildCacheMisses = metrics.Misses
+	err = impl.ciWorkflowRepository.UpdateWorkFlow(ciWorkflow)
+	if err != nil {
+		impl.logger.Errorw("error in saving build cache metrics", "err", err, "ciWorkflowId", ciWorkflow.Id)
+		return err
+	}
+	if !metrics.Exported || len(metrics.CacheRef) == 0 {
+		return nil
+	}
+	now := time.Now()
+	entry, err := impl.ciBuildCacheRepository.FindLiveEntryByCacheRef(ciWorkflow.CiPipelineId, metrics.CacheRef)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching build cache entry", "err", err, "cacheRef", metrics.CacheRef)
+		return err
+	}
+	if entry != nil && entry.Id > 0 {
+		entry.LastUsedOn = now
+		entry.UpdatedOn = now
+		entry.UpdatedBy = ciWorkflow.TriggeredBy
+		err = impl.ciBuildCacheRepository.UpdateEntry(entry)
+	} else {
+		entry = &pipelineConfig.CiBuildCacheEntry{
+			CiPipelineId: ciWorkflow.CiPipelineId,
+			CacheKey:     metrics.CacheKey,
+			CacheRef:     metrics.CacheRef,
+			Status:       pipelineConfig.BUILD_CACHE_ENTRY_STATUS_LIVE,
+			LastUsedOn:   now,
+			AuditLog:     sql.AuditLog{CreatedOn: now, CreatedBy: ciWorkflow.TriggeredBy, UpdatedOn: now, UpdatedBy: ciWorkflow.TriggeredBy},
+		}
+		err = impl.ciBuildCacheRepository.SaveEntry(entry)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving build cache entry", "err", err, "cacheRef", metrics.CacheRef)
+		return err
+	}
+	return nil
+}
+
+func (impl *CiBuildCacheServiceImpl) ApplyRetentionPolicy() {
+	if impl.ciConfig.BuildCacheRetentionDays > 0 {
+		lastUsedBefore := time.Now().AddDate(0, 0, -impl.ciConfig.BuildCacheRetentionDays)
+		expired, err := impl.ciBuildCacheRepository.ExpireLiveEntriesNotUsedSince(lastUsedBefore, BUILD_CACHE_SYSTEM_USER_ID)
+		if err != nil {
+			impl.logger.Errorw("error in expiring build cache entries past retention", "err", err)
+			return
+		}
+		if expired > 0 {
+			impl.logger.Infow("build cache entries expired by retention", "count", expired, "retentionDays", impl.ciConfig.BuildCacheRetentionDays)
+		}
+	}
+	maxEntries := impl.ciConfig.BuildCacheMaxEntriesPerPipeline
+	if maxEntries <= 0 {
+		return
+	}
+	ciPipelineIds, err := impl.ciBuildCacheRepository.FindCiPipelineIdsWithLiveEntries()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci pipelines with build cache", "err", err)
+		return
+	}
+	for _, ciPipelineId := range ciPipelineIds {
+		entries, err := impl.ciBuildCacheRepository.FindLiveEntriesByCiPipelineId(ciPipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching build cache entries", "err", err, "ciPipelineId", ciPipelineId)
+			continue
+		}
+		if len(entries) <= maxEntries {
+			continue
+		}
+		var ids []int
+		for _, entry := range entries[maxEntries:] {
+			ids = append(ids, entry.Id)
+		}
+		err = impl.ciBuildCacheRepository.UpdateEntriesStatus(ids, pipelineConfig.BUILD_CACHE_ENTRY_STATUS_EXPIRED, BUILD_CACHE_SYSTEM_USER_ID)
+		if err != nil {
+			impl.logger.Errorw("error in expiring build cache entries beyond limit", "err", err, "ciPipelineId", ciPipelineId)
+		}
+	}
+}
+
+// GetBuildCacheKeyPrefix gives the part of the cache key decided by the orchestrator, the ci runner appends the
+// hash of the key files to it
+func GetBuildCacheKeyPrefix(generation int, branch string) string {
+	prefix := fmt.Sprintf("%s-g%d", BUILD_CACHE_KEY_PREFIX, generation)
+	branch = strings.Trim(buildCacheTagInvalidCharRegex.ReplaceAllString(branch, "-"), "-.")
+	if len(branch) > BUILD_CACHE_MAX_BRANCH_LENGTH {
+		branch = strings.Trim(branch[:BUILD_CACHE_MAX_BRANCH_LENGTH], "-.")
+	}
+	if len(branch) > 0 {
+		prefix = prefix + "-" + branch
+	}
+	return prefix
+}
+
+// GetBuildCacheRepository qualifies the cache repository with the registry host, the registry of the built image is used
+func GetBuildCacheRepository(registryUrl string, repository string) string {
+	host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registryUrl, "https://"), "http://"), "/")
+	if len(host) == 0 || strings.HasPrefix(repository, host+"/") {
+		return repository
+	}
+	return host + "/" + repository
+}
+
+// validateBuildCacheRepository keeps cache images within the repository of the built image, they are pushed with the
+// credentials of the pipeline's registry and would otherwise overwrite images of other repositories
+func validateBuildCacheRepository(cacheRepository string, registryUrl string, dockerRepository string) error {
+	imageRepository := GetBuildCacheRepository(registryUrl, dockerRepository)
+	cacheRepository = GetBuildCacheRepository(registryUrl, cacheRepository)
+	if len(dockerRepository) > 0 && (cacheRepository == imageRepository || strings.HasPrefix(cacheRepository, imageRepository+"/")) &&
+		!strings.ContainsAny(strings.TrimPrefix(cacheRepository, imageRepository), ":@") {
+		return nil
+	}
+	errMsg := fmt.Sprintf("cache repository should be the repository of the built image %s or one nested under it", imageRepository)
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+}
+
+// GetBuildCacheImportRefs picks the fallbacks for a miss of the exact key, the latest caches of the same key prefix
+// (same branch with other key file contents) and then the latest cache of the generation
+func GetBuildCacheImportRefs(keyPrefix string, generation int, entries []*pipelineConfig.CiBuildCacheEntry) []string {
+	generationPrefix := fmt.Sprintf("%s-g%d", BUILD_CACHE_KEY_PREFIX, generation)
+	var importRefs []string
+	var generationRef string
+	for _, entry := range entries {
+		if isBuildCacheKeyOfPrefix(entry.CacheKey, keyPrefix) {
+			if len(importRefs) < BUILD_CACHE_MAX_IMPORT_REFS-1 {
+				importRefs = append(importRefs, entry.CacheRef)
+			}
+		} else if len(generationRef) == 0 && (entry.CacheKey == generationPrefix || strings.HasPrefix(entry.CacheKey, generationPrefix+"-")) {
+			generationRef = entry.CacheRef
+		}
+	}
+	if len(generationRef) > 0 {
+		importRefs = append(importRefs, generationRef)
+	}
+	return importRefs
+}
+
+func isBuildCacheKeyOfPrefix(cacheKey string, keyPrefix string) bool {
+	if cacheKey == keyPrefix {
+		return true
+	}
+	hash := strings.TrimPrefix(cacheKey, keyPrefix+"-")
+	return hash != cacheKey && len(hash) == BUILD_CACHE_KEY_HASH_LENGTH && !strings.Contains(hash, "-")
+}
+
+// getBuildCacheBranch gives the branch being built, the source branch for pull request builds
+func getBuildCacheBranch(ciProjectDetails []CiProjectDetails) string {
+	for _, ciProjectDetail := range ciProjectDetails {
+		switch ciProjectDetail.SourceType {
+		case pipelineConfig.SOURCE_TYPE_BRANCH_FIXED, pipelineConfig.SOURCE_TYPE_BRANCH_REGEX:
+			return ciProjectDetail.SourceValue
+		case pipelineConfig.SOURCE_TYPE_WEBHOOK:
+			if branch := ciProjectDetail.WebhookData.Data[bean2.WEBHOOK_SELECTOR_SOURCE_BRANCH_NAME_NAME]; len(branch) > 0 {
+				return branch
+			}
+		}
+	}
+	return ""
+}
+
+func cleanBuildCacheKeyFiles(keyFiles []string) []string {
+	var result []string
+	for _, keyFile := range keyFiles {
+		keyFile = strings.TrimSpace(keyFile)
+		if len(keyFile) > 0 && !strings.Contains(keyFile, ",") {
+			result = append(result, keyFile)
+		}
+	}
+	return result
+}
+
+func adaptCiBuildCacheConfig(model *pipelineConfig.CiBuildCacheConfig) *bean.CiBuildCacheConfigDto {
+	dto := &bean.CiBuildCacheConfigDto{
+		Id:              model.Id,
+		CiPipelineId:    model.CiPipelineId,
+		Mode:            model.Mode,
+		CacheRepository: model.CacheRepository,
+		ExportMode:      model.ExportMode,
+		KeyByBranch:     model.KeyByBranch,
+		Generation:      model.Generation,
+	}
+	if len(model.KeyFiles) > 0 {
+		dto.KeyFiles = strings.Split(model.KeyFiles, ",")
+	}
+	if !model.InvalidatedOn.IsZero() {
+		invalidatedOn := model.InvalidatedOn
+		dto.InvalidatedOn = &invalidatedOn
+	}
+	return dto
+}
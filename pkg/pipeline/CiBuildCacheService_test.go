@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGetBuildCacheKeyPrefix(t *testing.T) {
+	assert.Equal(t, "cache-g0", GetBuildCacheKeyPrefix(0, ""))
+	assert.Equal(t, "cache-g2-main", GetBuildCacheKeyPrefix(2, "main"))
+	assert.Equal(t, "cache-g1-feature-payments-api", GetBuildCacheKeyPrefix(1, "feature/payments api"))
+
+	prefix := GetBuildCacheKeyPrefix(1, strings.Repeat("a", 200))
+	assert.Equal(t, len("cache-g1-")+BUILD_CACHE_MAX_BRANCH_LENGTH, len(prefix))
+
+	assert.Equal(t, "registry.example.com/org/app", GetBuildCacheRepository("https://registry.example.com/", "org/app"))
+	assert.Equal(t, "registry.example.com/org/app", GetBuildCacheRepository("registry.example.com", "registry.example.com/org/app"))
+	assert.Equal(t, "org/app", GetBuildCacheRepository("", "org/app"))
+}
+
+func TestGetBuildCacheImportRefs(t *testing.T) {
+	entry := func(key string) *pipelineConfig.CiBuildCacheEntry {
+		return &pipelineConfig.CiBuildCacheEntry{CacheKey: key, CacheRef: "registry/app:" + key}
+	}
+	// most recently used first
+	entries := []*pipelineConfig.CiBuildCacheEntry{
+		entry("cache-g1-feature-0123456789ab"),
+		entry("cache-g1-main-0123456789ab"),
+		entry("cache-g1-main-ba9876543210"),
+		entry("cache-g1-main-aaaaaaaaaaaa"),
+		entry("cache-g1-main-x-0123456789ab"),
+	}
+	importRefs := GetBuildCacheImportRefs("cache-g1-main", 1, entries)
+	assert.Equal(t, []string{"registry/app:cache-g1-main-0123456789ab", "registry/app:cache-g1-main-ba9876543210",
+		"registry/app:cache-g1-feature-0123456789ab"}, importRefs)
+
+	// caches of an older generation are never offered
+	importRefs = GetBuildCacheImportRefs("cache-g2-main", 2, entries)
+	assert.Empty(t, importRefs)
+
+	importRefs = GetBuildCacheImportRefs("cache-g1", 1, []*pipelineConfig.CiBuildCacheEntry{entry("cache-g1"), entry("cache-g10")})
+	assert.Equal(t, []string{"registry/app:cache-g1"}, importRefs)
+}
+
+func TestGetBuildCacheBranch(t *testing.T) {
+	assert.Equal(t, "main", getBuildCacheBranch([]CiProjectDetails{{SourceType: pipelineConfig.SOURCE_TYPE_BRANCH_FIXED, SourceValue: "main"}}))
+	assert.Equal(t, "feature/payments", getBuildCacheBranch([]CiProjectDetails{{
+		SourceType:  pipelineConfig.SOURCE_TYPE_WEBHOOK,
+		WebhookData: pipelineConfig.WebhookData{Data: map[string]string{"source branch name": "feature/payments"}},
+	}}))
+	assert.Equal(t, "", getBuildCacheBranch(nil))
+}
+
+func TestValidateBuildCacheRepository(t *testing.T) {
+	assert.Nil(t, validateBuildCacheRepository("org/app", "https://registry.example.com", "org/app"))
+	assert.Nil(t, validateBuildCacheRepository("org/app/cache", "https://registry.example.com", "org/app"))
+	assert.Nil(t, validateBuildCacheRepository("registry.example.com/org/app/cache", "https://registry.example.com", "org/app"))
+	// repositories of other images, also of the same registry, are not to be overwritten
+	assert.NotNil(t, validateBuildCacheRepository("org/other", "https://registry.example.com", "org/app"))
+	assert.NotNil(t, validateBuildCacheRepository("org/app-cache", "https://registry.example.com", "org/app"))
+	assert.NotNil(t, validateBuildCacheRepository("other.example.com/org/app", "https://registry.example.com", "org/app"))
+	assert.NotNil(t, validateBuildCacheRepository("org/app:cache", "https://registry.example.com", "org/app"))
+	assert.NotNil(t, validateBuildCacheRepository("org/app", "https://registry.example.com", ""))
+}
@@ -28,6 +28,10 @@ func NewCiBuildConfigServiceImpl(logger *zap.SugaredLogger, ciBuildConfigReposit
 }
 
 func (impl *CiBuildConfigServiceImpl) Save(templateId int, overrideTemplateId int, ciBuildConfigBean *bean.CiBuildConfigBean, userId int32) error {
+	if err := bean.ValidateCiBuildConfig(ciBuildConfigBean); err != nil {
+		impl.Logger.Errorw("invalid build config", "ciBuildConfigBean", ciBuildConfigBean, "err", err)
+		return err
+	}
 	ciBuildConfigEntity, err := bean.ConvertBuildConfigBeanToDbEntity(templateId, overrideTemplateId, ciBuildConfigBean, userId)
 	if err != nil {
 		impl.Logger.Errorw("error occurred while converting build config to db entity", "templateId", templateId,
@@ -50,6 +54,10 @@ func (impl *CiBuildConfigServiceImpl) UpdateOrSave(templateId int, overrideTempl
 		impl.Logger.Warnw("not updating build config as object is empty", "ciBuildConfig", ciBuildConfig)
 		return nil, nil
 	}
+	if err := bean.ValidateCiBuildConfig(ciBuildConfig); err != nil {
+		impl.Logger.Errorw("invalid build config", "ciBuildConfig", ciBuildConfig, "err", err)
+		return nil, err
+	}
 	ciBuildConfigEntity, err := bean.ConvertBuildConfigBeanToDbEntity(templateId, overrideTemplateId, ciBuildConfig, userId)
 	if err != nil {
 		impl.Logger.Errorw("error occurred while converting build config to db entity", "templateId", templateId,
@@ -1367,6 +1367,7 @@ func (impl CiCdPipelineOrchestratorImpl) CreateCDPipelines(pipelineRequest *bean
 		PostStageConfigMapSecretNames: string(postStageConfigMapSecretNames),
 		RunPreStageInEnv:              pipelineRequest.RunPreStageInEnv,
 		RunPostStageInEnv:             pipelineRequest.RunPostStageInEnv,
+		ValidateImagePlatforms:        pipelineRequest.ValidateImagePlatforms,
 		DeploymentAppCreated:          false,
 		DeploymentAppType:             pipelineRequest.DeploymentAppType,
 		DeploymentAppName:             fmt.Sprintf("%s-%s", appName, env.Name),
@@ -1444,6 +1445,7 @@ func (impl CiCdPipelineOrchestratorImpl) UpdateCDPipeline(pipelineRequest *bean.
 	pipeline.PostStageConfigMapSecretNames = string(postStageConfigMapSecretNames)
 	pipeline.RunPreStageInEnv = pipelineRequest.RunPreStageInEnv
 	pipeline.RunPostStageInEnv = pipelineRequest.RunPostStageInEnv
+	pipeline.ValidateImagePlatforms = pipelineRequest.ValidateImagePlatforms
 	pipeline.UpdatedBy = userId
 	pipeline.UpdatedOn = time.Now()
 	err = impl.pipelineRepository.Update(pipeline, tx)
@@ -1564,6 +1566,7 @@ func (impl CiCdPipelineOrchestratorImpl) GetCdPipelinesForApp(appId int) (cdPipe
 			PostStage:                     postStage,
 			RunPreStageInEnv:              dbPipeline.RunPreStageInEnv,
 			RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
+			ValidateImagePlatforms:        dbPipeline.ValidateImagePlatforms,
 			PreStageConfigMapSecretNames:  preStageConfigmapSecrets,
 			PostStageConfigMapSecretNames: postStageConfigmapSecrets,
 			DeploymentAppType:             dbPipeline.DeploymentAppType,
@@ -1749,6 +1752,7 @@ func (impl CiCdPipelineOrchestratorImpl) GetCdPipelinesForAppAndEnv(appId int, e
 			PostStageConfigMapSecretNames: postStageConfigmapSecrets,
 			RunPreStageInEnv:              dbPipeline.RunPreStageInEnv,
 			RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
+			ValidateImagePlatforms:        dbPipeline.ValidateImagePlatforms,
 			CdArgoSetup:                   env.Cluster.CdArgoSetup,
 		}
 		if pipelineStages, ok := pipelineIdAndPrePostStageMapping[dbPipeline.Id]; ok {
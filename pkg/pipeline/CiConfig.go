@@ -20,6 +20,7 @@ package pipeline
 import (
 	"fmt"
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"strings"
 
 	"github.com/caarlos0/env"
@@ -79,11 +80,17 @@ type CiConfig struct {
 	DefaultTargetPlatform            string                       `env:"DEFAULT_TARGET_PLATFORM" envDefault:""`
 	UseBuildx                        bool                         `env:"USE_BUILDX" envDefault:"false"`
 	NodeLabel                        map[string]string
-	EnableBuildContext               bool   `env:"ENABLE_BUILD_CONTEXT" envDefault:"false"`
-	ImageRetryCount                  int    `env:"IMAGE_RETRY_COUNT" envDefault:"0"`
-	ImageRetryInterval               int    `env:"IMAGE_RETRY_INTERVAL" envDefault:"5"` //image retry interval takes value in seconds
-	OrchestratorHost                 string `env:"ORCH_HOST" envDefault:"http://devtroncd-orchestrator-service-prod.devtroncd/webhook/msg/nats"`
-	OrchestratorToken                string `env:"ORCH_TOKEN" envDefault:""`
+	EnableBuildContext               bool                                `env:"ENABLE_BUILD_CONTEXT" envDefault:"false"`
+	ImageRetryCount                  int                                 `env:"IMAGE_RETRY_COUNT" envDefault:"0"`
+	ImageRetryInterval               int                                 `env:"IMAGE_RETRY_INTERVAL" envDefault:"5"` //image retry interval takes value in seconds
+	OrchestratorHost                 string                              `env:"ORCH_HOST" envDefault:"http://devtroncd-orchestrator-service-prod.devtroncd/webhook/msg/nats"`
+	OrchestratorToken                string                              `env:"ORCH_TOKEN" envDefault:""`
+	CiWorkflowExecutorType           pipelineConfig.WorkflowExecutorType `env:"CI_WORKFLOW_EXECUTOR_TYPE" envDefault:"AWF"`
+	// build cache retention, applies to the registry caches of every ci pipeline
+	BuildCacheRetentionDays         int `env:"BUILD_CACHE_RETENTION_DAYS" envDefault:"14"`
+	BuildCacheMaxEntriesPerPipeline int `env:"BUILD_CACHE_MAX_ENTRIES_PER_PIPELINE" envDefault:"10"`
+	// SbomFormats are the comma separated formats sboms of built images are generated in, spdx-json and cyclonedx-json
+	SbomFormats string `env:"SBOM_FORMATS" envDefault:""`
 }
 
 type CiVolumeMount struct {
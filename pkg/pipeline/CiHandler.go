@@ -157,6 +157,9 @@ type WorkflowResponse struct {
 	EnvironmentName      string                                      `json:"environmentName"`
 	ImageReleaseTags     []*repository2.ImageTag                     `json:"imageReleaseTags"`
 	ImageComment         *repository2.ImageComment                   `json:"imageComment"`
+	BuildCacheKey        string                                      `json:"buildCacheKey,omitempty"`
+	BuildCacheHits       int                                         `json:"buildCacheHits"`
+	BuildCacheMisses     int                                         `json:"buildCacheMisses"`
 }
 
 type GitTriggerInfoResponse struct {
@@ -533,14 +536,19 @@ func (impl *CiHandlerImpl) CancelBuild(workflowId int) (int, error) {
 		}
 	}
 
-	runningWf, err := impl.workflowService.GetWorkflow(workflow.Name, workflow.Namespace, isExt, env)
-	if err != nil {
-		impl.Logger.Errorw("cannot find workflow ", "err", err)
-		return 0, errors.New("cannot find workflow " + workflow.Name)
+	workflowName, workflowNamespace := workflow.Name, workflow.Namespace
+	// system workflows run as k8s jobs, there is no argo workflow to look up
+	if workflow.ExecutorType != pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+		runningWf, err := impl.workflowService.GetWorkflow(workflow.Name, workflow.Namespace, isExt, env)
+		if err != nil {
+			impl.Logger.Errorw("cannot find workflow ", "err", err)
+			return 0, errors.New("cannot find workflow " + workflow.Name)
+		}
+		workflowName, workflowNamespace = runningWf.Name, runningWf.Namespace
 	}
 
 	// Terminate workflow
-	err = impl.workflowService.TerminateWorkflow(runningWf.Name, runningWf.Namespace, isExt, env)
+	err = impl.workflowService.TerminateWorkflow(workflow.ExecutorType, workflowName, workflowNamespace, isExt, env)
 	if err != nil {
 		impl.Logger.Errorw("cannot terminate wf", "err", err)
 		return 0, err
@@ -627,6 +635,9 @@ func (impl *CiHandlerImpl) FetchWorkflowDetails(appId int, pipelineId int, build
 		IsArtifactUploaded: ciArtifact.IsArtifactUploaded,
 		EnvironmentId:      workflow.EnvironmentId,
 		EnvironmentName:    environmentName,
+		BuildCacheKey:      workflow.BuildCacheKey,
+		BuildCacheHits:     workflow.BuildCacheHits,
+		BuildCacheMisses:   workflow.BuildCacheMisses,
 	}
 	return workflowResponse, nil
 }
@@ -1445,8 +1456,11 @@ func (impl *CiHandlerImpl) UpdateCiWorkflowStatusFailure(timeoutForFailureCiBuil
 
 		isEligibleToMarkFailed := false
 		if time.Since(ciWorkflow.StartedOn) > (time.Minute * time.Duration(timeoutForFailureCiBuild)) {
-			//check weather pod is exists or not, if exits check its status
-			_, err := impl.workflowService.GetWorkflow(ciWorkflow.Name, ciWorkflow.Namespace, isExt, env)
+			//check weather pod is exists or not, if exits check its status, system workflows have no argo workflow
+			var err error
+			if ciWorkflow.ExecutorType != pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+				_, err = impl.workflowService.GetWorkflow(ciWorkflow.Name, ciWorkflow.Namespace, isExt, env)
+			}
 			if err != nil {
 				impl.Logger.Warnw("unable to fetch ci workflow", "err", err)
 				statusError, ok := err.(*errors2.StatusError)
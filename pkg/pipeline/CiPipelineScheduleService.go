@@ -0,0 +1,369 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	DEFAULT_SCHEDULE_TIMEZONE = "UTC"
+	// SCHEDULE_SYSTEM_USER_ID triggers scheduled builds, the user who last edited a schedule may have lost access since
+	SCHEDULE_SYSTEM_USER_ID int32 = 1
+)
+
+type CiPipelineScheduleService interface {
+	SaveSchedule(request *bean.CiPipelineScheduleDto) (*bean.CiPipelineScheduleDto, error)
+	GetSchedule(ciPipelineId int) (*bean.CiPipelineScheduleDto, error)
+	DeleteSchedule(ciPipelineId int, userId int32) error
+	UpdatePauseState(ciPipelineId int, paused bool, userId int32) (*bean.CiPipelineScheduleDto, error)
+	GetScheduleRuns(ciPipelineId int, limit int) ([]*bean.CiPipelineScheduleRunDto, error)
+	// ProcessDueSchedules triggers every schedule whose next run is due, runs which are late by more than
+	// missedRunGrace are handled as per the catch-up policy of the schedule
+	ProcessDueSchedules(missedRunGrace time.Duration)
+}
+
+type CiPipelineScheduleServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	ciPipelineScheduleRepository pipelineConfig.CiPipelineScheduleRepository
+	ciPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciWorkflowRepository         pipelineConfig.CiWorkflowRepository
+	ciHandler                    CiHandler
+}
+
+func NewCiPipelineScheduleServiceImpl(logger *zap.SugaredLogger, ciPipelineScheduleRepository pipelineConfig.CiPipelineScheduleRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
+	ciHandler CiHandler) *CiPipelineScheduleServiceImpl {
+	return &CiPipelineScheduleServiceImpl{
+		logger:                       logger,
+		ciPipelineScheduleRepository: ciPipelineScheduleRepository,
+		ciPipelineRepository:         ciPipelineRepository,
+		ciWorkflowRepository:         ciWorkflowRepository,
+		ciHandler:                    ciHandler,
+	}
+}
+
+func (impl *CiPipelineScheduleServiceImpl) SaveSchedule(request *bean.CiPipelineScheduleDto) (*bean.CiPipelineScheduleDto, error) {
+	if len(request.Timezone) == 0 {
+		request.Timezone = DEFAULT_SCHEDULE_TIMEZONE
+	}
+	if len(request.CommitPolicy) == 0 {
+		request.CommitPolicy = pipelineConfig.SCHEDULE_COMMIT_POLICY_LATEST
+	}
+	if len(request.CatchUpPolicy) == 0 {
+		request.CatchUpPolicy = pipelineConfig.SCHEDULE_CATCH_UP_POLICY_SKIP
+	}
+	schedule, err := parseCronSchedule(request.CronExpression, request.Timezone)
+	if err != nil {
+		impl.logger.Errorw("invalid cron schedule", "err", err, "cronExpression", request.CronExpression, "timezone", request.Timezone)
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(request.CiPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline > 0 {
+		errMsg := "schedule can only be configured on a pipeline which builds from source"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	err = validateScheduleMaterials(ciPipeline.CiPipelineMaterials)
+	if err != nil {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+
+	existing, err := impl.ciPipelineScheduleRepository.FindActiveByCiPipelineId(request.CiPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	model := &pipelineConfig.CiPipelineSchedule{
+		CiPipelineId: request.CiPipelineId,
+		Active:       true,
+		AuditLog:     sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	if existing != nil && existing.Id > 0 {
+		model = existing
+		model.UpdatedOn = now
+		model.UpdatedBy = request.UserId
+	}
+	model.EnvironmentId = request.EnvironmentId
+	model.CronExpression = request.CronExpression
+	model.Timezone = request.Timezone
+	model.CommitPolicy = request.CommitPolicy
+	model.CatchUpPolicy = request.CatchUpPolicy
+	model.Paused = request.Paused
+	model.NextRunAt = schedule.Next(now)
+	if model.Id > 0 {
+		err = impl.ciPipelineScheduleRepository.Update(model)
+	} else {
+		err = impl.ciPipelineScheduleRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedule", "err", err, "schedule", model)
+		return nil, err
+	}
+	return adaptCiPipelineSchedule(model), nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetSchedule(ciPipelineId int) (*bean.CiPipelineScheduleDto, error) {
+	model, err := impl.ciPipelineScheduleRepository.FindActiveByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "schedule not found", UserMessage: "no schedule configured for this pipeline"}
+		}
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return adaptCiPipelineSchedule(model), nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) DeleteSchedule(ciPipelineId int, userId int32) error {
+	model, err := impl.ciPipelineScheduleRepository.FindActiveByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.ciPipelineScheduleRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting ci pipeline schedule", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) UpdatePauseState(ciPipelineId int, paused bool, userId int32) (*bean.CiPipelineScheduleDto, error) {
+	model, err := impl.ciPipelineScheduleRepository.FindActiveByCiPipelineId(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	if !paused && model.Paused {
+		// runs which fell inside the paused window are not treated as missed
+		schedule, err := parseCronSchedule(model.CronExpression, model.Timezone)
+		if err != nil {
+			impl.logger.Errorw("invalid cron schedule", "err", err, "scheduleId", model.Id)
+			return nil, err
+		}
+		model.NextRunAt = schedule.Next(now)
+	}
+	model.Paused = paused
+	model.UpdatedOn = now
+	model.UpdatedBy = userId
+	err = impl.ciPipelineScheduleRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating ci pipeline schedule", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return adaptCiPipelineSchedule(model), nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetScheduleRuns(ciPipelineId int, limit int) ([]*bean.CiPipelineScheduleRunDto, error) {
+	model, err := impl.ciPipelineScheduleRepository.FindActiveByCiPipelineId(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	runs, err := impl.ciPipelineScheduleRepository.FindRunsByScheduleId(model.Id, limit)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedule runs", "err", err, "scheduleId", model.Id)
+		return nil, err
+	}
+	runDtos := make([]*bean.CiPipelineScheduleRunDto, 0, len(runs))
+	for _, run := range runs {
+		runDtos = append(runDtos, &bean.CiPipelineScheduleRunDto{
+			Id:           run.Id,
+			CiWorkflowId: run.CiWorkflowId,
+			ScheduledAt:  run.ScheduledAt,
+			TriggeredAt:  run.TriggeredAt,
+			TriggeredBy:  run.TriggeredBy,
+			Status:       run.Status,
+			Message:      run.Message,
+		})
+	}
+	return runDtos, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) ProcessDueSchedules(missedRunGrace time.Duration) {
+	now := time.Now()
+	schedules, err := impl.ciPipelineScheduleRepository.FindDueSchedules(now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching due ci pipeline schedules", "err", err)
+		return
+	}
+	for _, schedule := range schedules {
+		impl.processDueSchedule(schedule, now, missedRunGrace)
+	}
+}
+
+func (impl *CiPipelineScheduleServiceImpl) processDueSchedule(schedule *pipelineConfig.CiPipelineSchedule, now time.Time, missedRunGrace time.Duration) {
+	cronSchedule, err := parseCronSchedule(schedule.CronExpression, schedule.Timezone)
+	if err != nil {
+		impl.logger.Errorw("invalid cron schedule, skipping", "err", err, "scheduleId", schedule.Id)
+		return
+	}
+	trigger, nextRunAt := getScheduledRunAction(cronSchedule, schedule.NextRunAt, now, missedRunGrace, schedule.CatchUpPolicy)
+	// only the replica which moves next_run_at forward gets to trigger this run
+	claimed, err := impl.ciPipelineScheduleRepository.ClaimRun(schedule.Id, schedule.NextRunAt, nextRunAt, now)
+	if err != nil || !claimed {
+		return
+	}
+	run := &pipelineConfig.CiPipelineScheduleRun{
+		CiPipelineScheduleId: schedule.Id,
+		ScheduledAt:          schedule.NextRunAt,
+		TriggeredAt:          now,
+		TriggeredBy:          SCHEDULE_SYSTEM_USER_ID,
+	}
+	if !trigger {
+		run.Status = pipelineConfig.SCHEDULE_RUN_STATUS_SKIPPED
+		run.Message = "run missed while orchestrator was unavailable, skipped as per catch-up policy"
+	} else {
+		ciWorkflowId, err := impl.triggerScheduledBuild(schedule)
+		if err != nil {
+			impl.logger.Errorw("error in triggering scheduled build", "err", err, "scheduleId", schedule.Id, "ciPipelineId", schedule.CiPipelineId)
+			run.Status = pipelineConfig.SCHEDULE_RUN_STATUS_FAILED
+			run.Message = err.Error()
+		} else {
+			run.Status = pipelineConfig.SCHEDULE_RUN_STATUS_TRIGGERED
+			run.CiWorkflowId = ciWorkflowId
+		}
+	}
+	err = impl.ciPipelineScheduleRepository.SaveRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedule run", "err", err, "run", run)
+	}
+}
+
+func (impl *CiPipelineScheduleServiceImpl) triggerScheduledBuild(schedule *pipelineConfig.CiPipelineSchedule) (int, error) {
+	var ciMaterials []bean2.CiPipelineMaterial
+	var err error
+	if schedule.CommitPolicy == pipelineConfig.SCHEDULE_COMMIT_POLICY_LAST_BUILT {
+		ciMaterials, err = impl.getLastBuiltCommits(schedule.CiPipelineId)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(ciMaterials) == 0 {
+		ciMaterials, err = impl.getLatestCommits(schedule.CiPipelineId)
+		if err != nil {
+			return 0, err
+		}
+	}
+	ciTriggerRequest := bean2.CiTriggerRequest{
+		PipelineId:         schedule.CiPipelineId,
+		CiPipelineMaterial: ciMaterials,
+		TriggeredBy:        SCHEDULE_SYSTEM_USER_ID,
+		EnvironmentId:      schedule.EnvironmentId,
+	}
+	return impl.ciHandler.HandleCIManual(ciTriggerRequest)
+}
+
+func (impl *CiPipelineScheduleServiceImpl) getLatestCommits(ciPipelineId int) ([]bean2.CiPipelineMaterial, error) {
+	materials, err := impl.ciHandler.FetchMaterialsByPipelineId(ciPipelineId, false)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline materials", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	var ciMaterials []bean2.CiPipelineMaterial
+	for _, material := range materials {
+		if material.Type != string(pipelineConfig.SOURCE_TYPE_BRANCH_FIXED) {
+			return nil, fmt.Errorf("scheduled builds are not supported for source type %s", material.Type)
+		}
+		if len(material.History) == 0 {
+			return nil, fmt.Errorf("no commit found for material %s", material.GitMaterialName)
+		}
+		ciMaterials = append(ciMaterials, bean2.CiPipelineMaterial{
+			Id:        material.Id,
+			GitCommit: bean2.GitCommit{Commit: material.History[0].Commit},
+		})
+	}
+	return ciMaterials, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) getLastBuiltCommits(ciPipelineId int) ([]bean2.CiPipelineMaterial, error) {
+	ciWorkflow, err := impl.ciWorkflowRepository.FindLastTriggeredWorkflow(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			// nothing built yet, caller falls back to latest commits
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching last triggered workflow", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	var ciMaterials []bean2.CiPipelineMaterial
+	for ciPipelineMaterialId, gitCommit := range ciWorkflow.GitTriggers {
+		ciMaterials = append(ciMaterials, bean2.CiPipelineMaterial{
+			Id:        ciPipelineMaterialId,
+			GitCommit: bean2.GitCommit{Commit: gitCommit.Commit},
+		})
+	}
+	return ciMaterials, nil
+}
+
+// validateScheduleMaterials allows schedules on fixed branches only, a regex branch or webhook material has no
+// commit to build without a user picking one
+func validateScheduleMaterials(materials []*pipelineConfig.CiPipelineMaterial) error {
+	for _, material := range materials {
+		if material.Active && material.Type != pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			return fmt.Errorf("schedule can only be configured on a pipeline which builds fixed branches, found source type %s", material.Type)
+		}
+	}
+	return nil
+}
+
+func parseCronSchedule(cronExpression string, timezone string) (cron.Schedule, error) {
+	if len(timezone) == 0 {
+		timezone = DEFAULT_SCHEDULE_TIMEZONE
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone %s", timezone)
+	}
+	schedule, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", timezone, cronExpression))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %s: %s", cronExpression, err.Error())
+	}
+	return schedule, nil
+}
+
+// getScheduledRunAction decides whether the run due at nextRunAt should be triggered and returns the following run time.
+// A run is considered missed when it is picked up more than missedRunGrace after its scheduled time, in that case
+// it is triggered once or skipped depending on the catch-up policy; older missed slots are always collapsed into it.
+func getScheduledRunAction(schedule cron.Schedule, nextRunAt time.Time, now time.Time, missedRunGrace time.Duration,
+	catchUpPolicy pipelineConfig.ScheduleCatchUpPolicy) (bool, time.Time) {
+	following := schedule.Next(now)
+	missed := now.Sub(nextRunAt) > missedRunGrace
+	if missed && catchUpPolicy != pipelineConfig.SCHEDULE_CATCH_UP_POLICY_RUN_ONCE {
+		return false, following
+	}
+	return true, following
+}
+
+func adaptCiPipelineSchedule(model *pipelineConfig.CiPipelineSchedule) *bean.CiPipelineScheduleDto {
+	return &bean.CiPipelineScheduleDto{
+		Id:             model.Id,
+		CiPipelineId:   model.CiPipelineId,
+		EnvironmentId:  model.EnvironmentId,
+		CronExpression: model.CronExpression,
+		Timezone:       model.Timezone,
+		CommitPolicy:   model.CommitPolicy,
+		CatchUpPolicy:  model.CatchUpPolicy,
+		Paused:         model.Paused,
+		NextRunAt:      model.NextRunAt,
+		LastRunAt:      model.LastRunAt,
+	}
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	schedule, err := parseCronSchedule("30 2 * * *", "Asia/Kolkata")
+	assert.Nil(t, err)
+	now := time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC)
+	// 02:30 IST is 21:00 UTC of the previous day
+	assert.Equal(t, time.Date(2023, 5, 10, 21, 0, 0, 0, time.UTC), schedule.Next(now).UTC())
+
+	schedule, err = parseCronSchedule("0 * * * *", "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 5, 10, 1, 0, 0, 0, time.UTC), schedule.Next(now).UTC())
+
+	_, err = parseCronSchedule("0 * * * *", "Mars/Olympus")
+	assert.NotNil(t, err)
+	_, err = parseCronSchedule("every minute", "UTC")
+	assert.NotNil(t, err)
+}
+
+func TestGetScheduledRunAction(t *testing.T) {
+	schedule, err := parseCronSchedule("0 * * * *", "UTC")
+	assert.Nil(t, err)
+	nextRunAt := time.Date(2023, 5, 10, 10, 0, 0, 0, time.UTC)
+	grace := 5 * time.Minute
+	tests := []struct {
+		name          string
+		now           time.Time
+		catchUpPolicy pipelineConfig.ScheduleCatchUpPolicy
+		wantTrigger   bool
+		wantNextRunAt time.Time
+	}{
+		{
+			name:          "picked up on time",
+			now:           nextRunAt.Add(30 * time.Second),
+			catchUpPolicy: pipelineConfig.SCHEDULE_CATCH_UP_POLICY_SKIP,
+			wantTrigger:   true,
+			wantNextRunAt: time.Date(2023, 5, 10, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "missed runs skipped",
+			now:           nextRunAt.Add(3*time.Hour + 10*time.Minute),
+			catchUpPolicy: pipelineConfig.SCHEDULE_CATCH_UP_POLICY_SKIP,
+			wantTrigger:   false,
+			wantNextRunAt: time.Date(2023, 5, 10, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "missed runs collapsed into one",
+			now:           nextRunAt.Add(3*time.Hour + 10*time.Minute),
+			catchUpPolicy: pipelineConfig.SCHEDULE_CATCH_UP_POLICY_RUN_ONCE,
+			wantTrigger:   true,
+			wantNextRunAt: time.Date(2023, 5, 10, 14, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, next := getScheduledRunAction(schedule, nextRunAt, tt.now, grace, tt.catchUpPolicy)
+			assert.Equal(t, tt.wantTrigger, trigger)
+			assert.Equal(t, tt.wantNextRunAt, next.UTC())
+		})
+	}
+}
+
+func TestValidateScheduleMaterials(t *testing.T) {
+	assert.Nil(t, validateScheduleMaterials([]*pipelineConfig.CiPipelineMaterial{
+		{Type: pipelineConfig.SOURCE_TYPE_BRANCH_FIXED, Value: "main", Active: true},
+		//inactive materials are not built
+		{Type: pipelineConfig.SOURCE_TYPE_WEBHOOK, Active: false},
+	}))
+	assert.NotNil(t, validateScheduleMaterials([]*pipelineConfig.CiPipelineMaterial{
+		{Type: pipelineConfig.SOURCE_TYPE_BRANCH_FIXED, Value: "main", Active: true},
+		{Type: pipelineConfig.SOURCE_TYPE_BRANCH_REGEX, Regex: "release-.*", Active: true},
+	}))
+	assert.NotNil(t, validateScheduleMaterials([]*pipelineConfig.CiPipelineMaterial{{Type: pipelineConfig.SOURCE_TYPE_WEBHOOK, Active: true}}))
+}
@@ -69,6 +69,9 @@ type CiServiceImpl struct {
 	appCrudOperationService       app.AppCrudOperationService
 	envRepository                 repository1.EnvironmentRepository
 	appRepository                 appRepository.AppRepository
+	imageSigningService           ImageSigningService
+	ciBuildCacheService           CiBuildCacheService
+	sbomService                   SbomService
 }
 
 func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService,
@@ -78,7 +81,8 @@ func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService
 	prePostCiScriptHistoryService history.PrePostCiScriptHistoryService,
 	pipelineStageService PipelineStageService,
 	userService user.UserService,
-	ciTemplateService CiTemplateService, appCrudOperationService app.AppCrudOperationService, envRepository repository1.EnvironmentRepository, appRepository appRepository.AppRepository) *CiServiceImpl {
+	ciTemplateService CiTemplateService, appCrudOperationService app.AppCrudOperationService, envRepository repository1.EnvironmentRepository, appRepository appRepository.AppRepository,
+	imageSigningService ImageSigningService, ciBuildCacheService CiBuildCacheService, sbomService SbomService) *CiServiceImpl {
 	return &CiServiceImpl{
 		Logger:                        Logger,
 		workflowService:               workflowService,
@@ -96,6 +100,9 @@ func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService
 		appCrudOperationService:       appCrudOperationService,
 		envRepository:                 envRepository,
 		appRepository:                 appRepository,
+		imageSigningService:           imageSigningService,
+		ciBuildCacheService:           ciBuildCacheService,
+		sbomService:                   sbomService,
 	}
 }
 
@@ -171,6 +178,8 @@ func (impl *CiServiceImpl) TriggerCiPipeline(trigger Trigger) (int, error) {
 		return 0, err
 	}
 
+	workflowRequest.WorkflowExecutor = impl.getWorkflowExecutorType(env)
+	savedCiWf.ExecutorType = workflowRequest.WorkflowExecutor
 	//savedCiWf.LogLocation = impl.ciConfig.DefaultBuildLogsKeyPrefix + "/" + workflowRequest.WorkflowNamePrefix + "/main.log"
 	savedCiWf.LogLocation = fmt.Sprintf("%s/%s/main.log", impl.ciConfig.DefaultBuildLogsKeyPrefix, workflowRequest.WorkflowNamePrefix)
 	err = impl.updateCiWorkflow(workflowRequest, savedCiWf)
@@ -192,6 +201,14 @@ func (impl *CiServiceImpl) TriggerCiPipeline(trigger Trigger) (int, error) {
 	return savedCiWf.Id, err
 }
 
+// getWorkflowExecutorType gives the executor configured on the cluster the workflow runs in, falling back to the global one
+func (impl *CiServiceImpl) getWorkflowExecutorType(env *repository1.Environment) pipelineConfig.WorkflowExecutorType {
+	if env != nil && env.Cluster != nil && len(env.Cluster.WorkflowExecutorType) > 0 {
+		return pipelineConfig.WorkflowExecutorType(env.Cluster.WorkflowExecutorType)
+	}
+	return impl.ciConfig.CiWorkflowExecutorType
+}
+
 func (impl *CiServiceImpl) getEnvironmentForJob(pipeline *pipelineConfig.CiPipeline, trigger Trigger) (*repository1.Environment, bool, error) {
 	app, err := impl.appRepository.FindById(pipeline.AppId)
 	if err != nil {
@@ -516,6 +533,11 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 		checkoutPath = filepath.Join(checkoutPath, buildPackConfig.ProjectPath)
 	}
 
+	if ciBuildConfigBean.DockerBuildConfig != nil && len(ciBuildConfigBean.DockerBuildConfig.TargetPlatforms) > 0 {
+		// multi-arch images are built by buildx, the runner pushes a manifest list covering all the platforms
+		ciBuildConfigBean.DockerBuildConfig.TargetPlatform = strings.Join(ciBuildConfigBean.DockerBuildConfig.TargetPlatforms, ",")
+		ciBuildConfigBean.DockerBuildConfig.UseBuildx = true
+	}
 	defaultTargetPlatform := impl.ciConfig.DefaultTargetPlatform
 	useBuildx := impl.ciConfig.UseBuildx
 
@@ -575,6 +597,30 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 		workflowRequest.DockerCert = dockerRegistry.Cert
 
 	}
+	imageSigningRequest, err := impl.imageSigningService.GetSigningRequestForCi(pipeline.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching image signing config", "err", err, "ciPipelineId", pipeline.Id)
+		return nil, err
+	}
+	workflowRequest.ImageSigning = imageSigningRequest
+	buildCacheRequest, err := impl.ciBuildCacheService.GetBuildCacheRequestForCi(pipeline.Id, savedWf.Id, workflowRequest.DockerRegistryURL, dockerRepository, ciProjectDetails, trigger.InvalidateCache)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching build cache request", "err", err, "ciPipelineId", pipeline.Id)
+		return nil, err
+	}
+	if buildCacheRequest != nil {
+		// configured build cache replaces the docker cache kept in blob storage
+		workflowRequest.IgnoreDockerCachePush = true
+		workflowRequest.IgnoreDockerCachePull = true
+		if buildCacheRequest.Mode == pipelineConfig.BUILD_CACHE_MODE_REGISTRY && ciBuildConfigBean.DockerBuildConfig != nil {
+			// registry cache export is supported by buildx only
+			ciBuildConfigBean.DockerBuildConfig.UseBuildx = true
+		}
+	}
+	workflowRequest.BuildCache = buildCacheRequest
+	if ciBuildConfigBean.CiBuildType != bean2.SKIP_BUILD_BUILD_TYPE {
+		workflowRequest.Sbom = impl.sbomService.GetSbomRequestForCi(savedWf.Id, savedWf.BlobStorageEnabled)
+	}
 	if ciWorkflowConfig.LogsBucket == "" {
 		ciWorkflowConfig.LogsBucket = impl.ciConfig.DefaultBuildLogsBucket
 	}
@@ -0,0 +1,586 @@
+package pipeline
+
+import (
+	"fmt"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	DEFAULT_DEPLOYMENT_APPROVAL_EXPIRY_IN_HOURS = 24
+	DEFAULT_APPROVAL_REQUESTS_LIMIT             = 20
+)
+
+type DeploymentApprovalService interface {
+	SaveApprovalConfig(request *bean.DeploymentApprovalConfigDto) (*bean.DeploymentApprovalConfigDto, error)
+	GetApprovalConfig(pipelineId int) (*bean.DeploymentApprovalConfigDto, error)
+	DeleteApprovalConfig(pipelineId int, userId int32) error
+
+	// RaiseApprovalRequest creates an approval request for the artifact and notifies approvers, an open request
+	// for the same artifact is returned as is
+	RaiseApprovalRequest(request *bean.DeploymentApprovalRequestDto) (*pipelineConfig.DeploymentApprovalRequest, error)
+	ActOnApprovalRequest(request *bean.DeploymentApprovalActionDto) (*pipelineConfig.DeploymentApprovalRequest, error)
+	CancelApprovalRequest(approvalRequestId int, userId int32) error
+	GetApprovalRequestById(approvalRequestId int) (*pipelineConfig.DeploymentApprovalRequest, error)
+	GetApprovalRequests(pipelineId int, limit int) ([]*pipelineConfig.DeploymentApprovalRequest, error)
+
+	// GetApprovedArtifactIds returns whether approval is required on the pipeline and which of the artifacts are approved
+	GetApprovedArtifactIds(pipelineId int, ciArtifactIds []int) (bool, map[int]bool, error)
+	// ConsumeApproval uses up the approval of the artifact for a deployment, returns whether approval is required
+	// on the pipeline and the id of the consumed approval request, 0 when the artifact is not approved
+	ConsumeApproval(pipelineId int, ciArtifactId int) (bool, int, error)
+	LinkApprovalToWorkflowRunner(approvalRequestId int, cdWorkflowRunnerId int) error
+	// RecordApprovalBypass saves a consumed approval with the reason for a deployment which skipped the approval gate,
+	// nothing is recorded when the pipeline does not require approval
+	RecordApprovalBypass(pipelineId int, ciArtifactId int, cdWorkflowRunnerId int, reason string, userId int32) error
+	// ReleaseApproval gives back an approval taken by ConsumeApproval when the deployment was blocked or failed to trigger
+	ReleaseApproval(approvalRequestId int) error
+	GetApprovalsByWorkflowRunnerIds(cdWorkflowRunnerIds []int) (map[int]*pipelineConfig.DeploymentApprovalRequest, error)
+}
+
+type DeploymentApprovalServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	deploymentApprovalRepository pipelineConfig.DeploymentApprovalRepository
+	pipelineRepository           pipelineConfig.PipelineRepository
+	userService                  user.UserService
+	roleGroupService             user.RoleGroupService
+	eventFactory                 client.EventFactory
+	eventClient                  client.EventClient
+}
+
+func NewDeploymentApprovalServiceImpl(logger *zap.SugaredLogger, deploymentApprovalRepository pipelineConfig.DeploymentApprovalRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, userService user.UserService, roleGroupService user.RoleGroupService,
+	eventFactory client.EventFactory, eventClient client.EventClient) *DeploymentApprovalServiceImpl {
+	return &DeploymentApprovalServiceImpl{
+		logger:                       logger,
+		deploymentApprovalRepository: deploymentApprovalRepository,
+		pipelineRepository:           pipelineRepository,
+		userService:                  userService,
+		roleGroupService:             roleGroupService,
+		eventFactory:                 eventFactory,
+		eventClient:                  eventClient,
+	}
+}
+
+func (impl *DeploymentApprovalServiceImpl) SaveApprovalConfig(request *bean.DeploymentApprovalConfigDto) (*bean.DeploymentApprovalConfigDto, error) {
+	if request.ExpiryInHours == 0 {
+		request.ExpiryInHours = DEFAULT_DEPLOYMENT_APPROVAL_EXPIRY_IN_HOURS
+	}
+	for _, groupId := range request.ApproverGroupIds {
+		_, err := impl.roleGroupService.FetchRoleGroupsById(groupId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching approver group", "err", err, "groupId", groupId)
+			if util.IsErrNoRows(err) {
+				errMsg := fmt.Sprintf("approver group %d not found", groupId)
+				return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+			}
+			return nil, err
+		}
+	}
+	existing, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(request.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	model := &pipelineConfig.DeploymentApprovalConfig{
+		PipelineId: request.PipelineId,
+		Active:     true,
+		AuditLog:   sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	if existing != nil && existing.Id > 0 {
+		model = existing
+		model.UpdatedOn = now
+		model.UpdatedBy = request.UserId
+	}
+	model.RequiredApprovals = request.RequiredApprovals
+	model.ApproverGroupIds = request.ApproverGroupIds
+	model.ExpiryInHours = request.ExpiryInHours
+	if model.Id > 0 {
+		err = impl.deploymentApprovalRepository.UpdateConfig(model)
+	} else {
+		err = impl.deploymentApprovalRepository.SaveConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment approval config", "err", err, "config", model)
+		return nil, err
+	}
+	return adaptDeploymentApprovalConfig(model), nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetApprovalConfig(pipelineId int) (*bean.DeploymentApprovalConfigDto, error) {
+	model, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "approval config not found", UserMessage: "deployment approval is not configured for this pipeline"}
+		}
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	return adaptDeploymentApprovalConfig(model), nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) DeleteApprovalConfig(pipelineId int, userId int32) error {
+	model, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.deploymentApprovalRepository.UpdateConfig(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment approval config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) RaiseApprovalRequest(request *bean.DeploymentApprovalRequestDto) (*pipelineConfig.DeploymentApprovalRequest, error) {
+	config, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(request.PipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			errMsg := "deployment approval is not configured for this pipeline"
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+		}
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	err = impl.deploymentApprovalRepository.MarkExpired(now)
+	if err != nil {
+		impl.logger.Errorw("error in expiring deployment approval requests", "err", err)
+		return nil, err
+	}
+	existing, err := impl.deploymentApprovalRepository.FindOpenRequestByPipelineIdAndArtifactId(request.PipelineId, request.CiArtifactId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment approval request", "err", err, "pipelineId", request.PipelineId, "ciArtifactId", request.CiArtifactId)
+		return nil, err
+	}
+	if existing != nil && existing.Id > 0 {
+		return existing, nil
+	}
+	approvalRequest := &pipelineConfig.DeploymentApprovalRequest{
+		PipelineId:        request.PipelineId,
+		CiArtifactId:      request.CiArtifactId,
+		Status:            pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED,
+		RequiredApprovals: config.RequiredApprovals,
+		Comment:           request.Comment,
+		ExpiresAt:         now.Add(time.Duration(config.ExpiryInHours) * time.Hour),
+		RequestedBy:       request.UserId,
+		AuditLog:          sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	err = impl.deploymentApprovalRepository.SaveRequest(approvalRequest)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment approval request", "err", err, "request", approvalRequest)
+		return nil, err
+	}
+	impl.sendApprovalNotification(approvalRequest)
+	return approvalRequest, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) sendApprovalNotification(approvalRequest *pipelineConfig.DeploymentApprovalRequest) {
+	cdPipeline, err := impl.pipelineRepository.FindById(approvalRequest.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline, approval notification not sent", "err", err, "pipelineId", approvalRequest.PipelineId)
+		return
+	}
+	event := impl.eventFactory.Build(util2.Approval, &cdPipeline.Id, cdPipeline.AppId, &cdPipeline.EnvironmentId, util2.CD)
+	event = impl.eventFactory.BuildExtraApprovalData(event, approvalRequest.CiArtifactId, approvalRequest.RequestedBy, approvalRequest.Comment)
+	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+	if evtErr != nil {
+		impl.logger.Errorw("CD approval event not sent", "error", evtErr, "approvalRequestId", approvalRequest.Id)
+	}
+}
+
+func (impl *DeploymentApprovalServiceImpl) ActOnApprovalRequest(request *bean.DeploymentApprovalActionDto) (*pipelineConfig.DeploymentApprovalRequest, error) {
+	approvalRequest, err := impl.deploymentApprovalRepository.FindRequestById(request.ApprovalRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment approval request", "err", err, "approvalRequestId", request.ApprovalRequestId)
+		return nil, err
+	}
+	if approvalRequest.RequestedBy == request.UserId {
+		errMsg := "approval request cannot be approved or rejected by the user who raised it"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	eligible, err := impl.isEligibleApprover(approvalRequest.PipelineId, request)
+	if err != nil {
+		return nil, err
+	}
+	if !eligible {
+		errMsg := "user is not an approver for this pipeline"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	// the request is locked till the action is saved, so that concurrent actions are counted one after the other
+	tx, err := impl.deploymentApprovalRepository.GetConnection().Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	approvalRequest, err = impl.deploymentApprovalRepository.FindRequestByIdForUpdate(request.ApprovalRequestId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in locking deployment approval request", "err", err, "approvalRequestId", request.ApprovalRequestId)
+		return nil, err
+	}
+	now := time.Now()
+	if approvalRequest.Status != pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED || !now.Before(approvalRequest.ExpiresAt) {
+		errMsg := fmt.Sprintf("approval request is not open for approval, current status %s", approvalRequest.Status)
+		return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	userData, err := impl.deploymentApprovalRepository.FindUserDataByRequestIds([]int{approvalRequest.Id})
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval user data", "err", err, "approvalRequestId", approvalRequest.Id)
+		return nil, err
+	}
+	for _, data := range userData {
+		if data.UserId == request.UserId {
+			errMsg := "user has already acted on this approval request"
+			return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: errMsg, UserMessage: errMsg}
+		}
+	}
+	action := &pipelineConfig.DeploymentApprovalUserData{
+		ApprovalRequestId: approvalRequest.Id,
+		UserId:            request.UserId,
+		Action:            request.Action,
+		Comment:           request.Comment,
+		ActedOn:           now,
+	}
+	err = impl.deploymentApprovalRepository.SaveUserData(action, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval user data", "err", err, "userData", action)
+		return nil, err
+	}
+	userData = append(userData, action)
+	status := getDeploymentApprovalStatus(approvalRequest.RequiredApprovals, userData)
+	updated, err := impl.deploymentApprovalRepository.UpdateRequestStatus(approvalRequest.Id,
+		[]pipelineConfig.DeploymentApprovalStatus{pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED}, status, request.UserId, now, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment approval request", "err", err, "approvalRequestId", approvalRequest.Id)
+		return nil, err
+	}
+	if !updated {
+		errMsg := "approval request is not open for approval anymore"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	err = tx.Commit()
+	if err != nil {
+		impl.logger.Errorw("error in committing deployment approval action", "err", err, "approvalRequestId", approvalRequest.Id)
+		return nil, err
+	}
+	approvalRequest.Status = status
+	approvalRequest.UpdatedOn = now
+	approvalRequest.UpdatedBy = request.UserId
+	err = impl.fillApprovalUserData([]*pipelineConfig.DeploymentApprovalRequest{approvalRequest})
+	if err != nil {
+		return nil, err
+	}
+	return approvalRequest, nil
+}
+
+// isEligibleApprover super admins can always approve, members of approver groups can approve when groups are
+// configured otherwise anyone with trigger access on the pipeline can
+func (impl *DeploymentApprovalServiceImpl) isEligibleApprover(pipelineId int, request *bean.DeploymentApprovalActionDto) (bool, error) {
+	config, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", pipelineId)
+		return false, err
+	}
+	userInfo, err := impl.userService.GetById(request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", request.UserId)
+		return false, err
+	}
+	var approverGroupNames []string
+	if config != nil {
+		for _, groupId := range config.ApproverGroupIds {
+			roleGroup, err := impl.roleGroupService.FetchRoleGroupsById(groupId)
+			if err != nil {
+				impl.logger.Warnw("approver group not found, skipping", "err", err, "groupId", groupId)
+				continue
+			}
+			approverGroupNames = append(approverGroupNames, roleGroup.Name)
+		}
+	}
+	return isEligibleApprover(userInfo.SuperAdmin, request.HasTriggerAccess, userInfo.Groups, approverGroupNames), nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) CancelApprovalRequest(approvalRequestId int, userId int32) error {
+	approvalRequest, err := impl.deploymentApprovalRepository.FindRequestById(approvalRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment approval request", "err", err, "approvalRequestId", approvalRequestId)
+		return err
+	}
+	if approvalRequest.Status != pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED && approvalRequest.Status != pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_APPROVED {
+		errMsg := fmt.Sprintf("approval request cannot be cancelled, current status %s", approvalRequest.Status)
+		return &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	if approvalRequest.RequestedBy != userId {
+		isSuperAdmin, err := impl.userService.IsSuperAdmin(int(userId))
+		if err != nil {
+			impl.logger.Errorw("error in checking super admin access", "err", err, "userId", userId)
+			return err
+		}
+		if !isSuperAdmin {
+			errMsg := "approval request can only be cancelled by the user who raised it"
+			return &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: errMsg, UserMessage: errMsg}
+		}
+	}
+	tx, err := impl.deploymentApprovalRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	// an approval consumed by a deployment meanwhile is not cancelled
+	updated, err := impl.deploymentApprovalRepository.UpdateRequestStatus(approvalRequestId,
+		[]pipelineConfig.DeploymentApprovalStatus{pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED, pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_APPROVED},
+		pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_CANCELLED, userId, time.Now(), tx)
+	if err != nil {
+		impl.logger.Errorw("error in cancelling deployment approval request", "err", err, "approvalRequestId", approvalRequestId)
+		return err
+	}
+	if !updated {
+		errMsg := "approval request cannot be cancelled, it has been acted upon meanwhile"
+		return &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	return tx.Commit()
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetApprovalRequestById(approvalRequestId int) (*pipelineConfig.DeploymentApprovalRequest, error) {
+	approvalRequest, err := impl.deploymentApprovalRepository.FindRequestById(approvalRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment approval request", "err", err, "approvalRequestId", approvalRequestId)
+		return nil, err
+	}
+	err = impl.fillApprovalUserData([]*pipelineConfig.DeploymentApprovalRequest{approvalRequest})
+	if err != nil {
+		return nil, err
+	}
+	return approvalRequest, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetApprovalRequests(pipelineId int, limit int) ([]*pipelineConfig.DeploymentApprovalRequest, error) {
+	err := impl.deploymentApprovalRepository.MarkExpired(time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in expiring deployment approval requests", "err", err)
+		return nil, err
+	}
+	approvalRequests, err := impl.deploymentApprovalRepository.FindRequestsByPipelineId(pipelineId, limit)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment approval requests", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	err = impl.fillApprovalUserData(approvalRequests)
+	if err != nil {
+		return nil, err
+	}
+	return approvalRequests, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetApprovedArtifactIds(pipelineId int, ciArtifactIds []int) (bool, map[int]bool, error) {
+	approvedArtifactIds := make(map[int]bool)
+	_, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return false, approvedArtifactIds, nil
+		}
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", pipelineId)
+		return false, approvedArtifactIds, err
+	}
+	artifactIds, err := impl.deploymentApprovalRepository.FindApprovedArtifactIds(pipelineId, ciArtifactIds, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in fetching approved artifacts", "err", err, "pipelineId", pipelineId)
+		return true, approvedArtifactIds, err
+	}
+	for _, artifactId := range artifactIds {
+		approvedArtifactIds[artifactId] = true
+	}
+	return true, approvedArtifactIds, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) ConsumeApproval(pipelineId int, ciArtifactId int) (bool, int, error) {
+	_, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return false, 0, nil
+		}
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", pipelineId)
+		return true, 0, err
+	}
+	approvalRequestId, err := impl.deploymentApprovalRepository.Consume(pipelineId, ciArtifactId, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in consuming deployment approval", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		return true, 0, err
+	}
+	return true, approvalRequestId, nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) LinkApprovalToWorkflowRunner(approvalRequestId int, cdWorkflowRunnerId int) error {
+	err := impl.deploymentApprovalRepository.SetCdWorkflowRunnerId(approvalRequestId, cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in linking approval to workflow runner", "err", err, "approvalRequestId", approvalRequestId, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) RecordApprovalBypass(pipelineId int, ciArtifactId int, cdWorkflowRunnerId int, reason string, userId int32) error {
+	config, err := impl.deploymentApprovalRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching deployment approval config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	now := time.Now()
+	approvalRequest := &pipelineConfig.DeploymentApprovalRequest{
+		PipelineId:         pipelineId,
+		CiArtifactId:       ciArtifactId,
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		Status:             pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_CONSUMED,
+		RequiredApprovals:  config.RequiredApprovals,
+		Comment:            reason,
+		ExpiresAt:          now,
+		RequestedBy:        userId,
+		AuditLog:           sql.AuditLog{CreatedOn: now, CreatedBy: userId, UpdatedOn: now, UpdatedBy: userId},
+	}
+	err = impl.deploymentApprovalRepository.SaveRequest(approvalRequest)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval bypass", "err", err, "request", approvalRequest)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) ReleaseApproval(approvalRequestId int) error {
+	err := impl.deploymentApprovalRepository.Release(approvalRequestId, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in releasing deployment approval", "err", err, "approvalRequestId", approvalRequestId)
+		return err
+	}
+	return nil
+}
+
+func (impl *DeploymentApprovalServiceImpl) GetApprovalsByWorkflowRunnerIds(cdWorkflowRunnerIds []int) (map[int]*pipelineConfig.DeploymentApprovalRequest, error) {
+	approvalsByRunnerId := make(map[int]*pipelineConfig.DeploymentApprovalRequest)
+	approvalRequests, err := impl.deploymentApprovalRepository.FindRequestsByCdWorkflowRunnerIds(cdWorkflowRunnerIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approvals by workflow runners", "err", err, "cdWorkflowRunnerIds", cdWorkflowRunnerIds)
+		return approvalsByRunnerId, err
+	}
+	err = impl.fillApprovalUserData(approvalRequests)
+	if err != nil {
+		return approvalsByRunnerId, err
+	}
+	for _, approvalRequest := range approvalRequests {
+		approvalsByRunnerId[approvalRequest.CdWorkflowRunnerId] = approvalRequest
+	}
+	return approvalsByRunnerId, nil
+}
+
+// fillApprovalUserData sets approver actions and user emails on the requests
+func (impl *DeploymentApprovalServiceImpl) fillApprovalUserData(approvalRequests []*pipelineConfig.DeploymentApprovalRequest) error {
+	if len(approvalRequests) == 0 {
+		return nil
+	}
+	var requestIds []int
+	userIdSet := make(map[int32]bool)
+	for _, approvalRequest := range approvalRequests {
+		requestIds = append(requestIds, approvalRequest.Id)
+		userIdSet[approvalRequest.RequestedBy] = true
+	}
+	userData, err := impl.deploymentApprovalRepository.FindUserDataByRequestIds(requestIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval user data", "err", err, "approvalRequestIds", requestIds)
+		return err
+	}
+	for _, data := range userData {
+		userIdSet[data.UserId] = true
+	}
+	var userIds []int32
+	for userId := range userIdSet {
+		userIds = append(userIds, userId)
+	}
+	users, err := impl.userService.GetByIds(userIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching users", "err", err, "userIds", userIds)
+		return err
+	}
+	emailByUserId := make(map[int32]string)
+	for _, userInfo := range users {
+		emailByUserId[userInfo.Id] = userInfo.EmailId
+	}
+	userDataByRequestId := make(map[int][]*pipelineConfig.DeploymentApprovalUserData)
+	for _, data := range userData {
+		data.UserEmail = emailByUserId[data.UserId]
+		userDataByRequestId[data.ApprovalRequestId] = append(userDataByRequestId[data.ApprovalRequestId], data)
+	}
+	for _, approvalRequest := range approvalRequests {
+		approvalRequest.RequestedByEmail = emailByUserId[approvalRequest.RequestedBy]
+		approvalRequest.ApprovalActions = userDataByRequestId[approvalRequest.Id]
+		if approvalRequest.ApprovalActions == nil {
+			approvalRequest.ApprovalActions = make([]*pipelineConfig.DeploymentApprovalUserData, 0)
+		}
+	}
+	return nil
+}
+
+// getDeploymentApprovalStatus a single rejection rejects the request, otherwise it is approved once enough approvals are in
+func getDeploymentApprovalStatus(requiredApprovals int, userData []*pipelineConfig.DeploymentApprovalUserData) pipelineConfig.DeploymentApprovalStatus {
+	approvals := 0
+	for _, data := range userData {
+		if data.Action == pipelineConfig.DEPLOYMENT_APPROVAL_ACTION_REJECT {
+			return pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REJECTED
+		}
+		approvals++
+	}
+	if approvals >= requiredApprovals {
+		return pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_APPROVED
+	}
+	return pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED
+}
+
+func isEligibleApprover(isSuperAdmin bool, hasTriggerAccess bool, userGroups []string, approverGroups []string) bool {
+	if isSuperAdmin {
+		return true
+	}
+	if len(approverGroups) == 0 {
+		return hasTriggerAccess
+	}
+	for _, approverGroup := range approverGroups {
+		for _, userGroup := range userGroups {
+			if userGroup == approverGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func adaptDeploymentApprovalConfig(model *pipelineConfig.DeploymentApprovalConfig) *bean.DeploymentApprovalConfigDto {
+	approverGroupIds := model.ApproverGroupIds
+	if approverGroupIds == nil {
+		approverGroupIds = make([]int32, 0)
+	}
+	return &bean.DeploymentApprovalConfigDto{
+		Id:                model.Id,
+		PipelineId:        model.PipelineId,
+		RequiredApprovals: model.RequiredApprovals,
+		ApproverGroupIds:  approverGroupIds,
+		ExpiryInHours:     model.ExpiryInHours,
+	}
+}
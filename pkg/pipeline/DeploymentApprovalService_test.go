@@ -0,0 +1,37 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetDeploymentApprovalStatus(t *testing.T) {
+	approve := &pipelineConfig.DeploymentApprovalUserData{Action: pipelineConfig.DEPLOYMENT_APPROVAL_ACTION_APPROVE}
+	reject := &pipelineConfig.DeploymentApprovalUserData{Action: pipelineConfig.DEPLOYMENT_APPROVAL_ACTION_REJECT}
+	tests := []struct {
+		name              string
+		requiredApprovals int
+		userData          []*pipelineConfig.DeploymentApprovalUserData
+		want              pipelineConfig.DeploymentApprovalStatus
+	}{
+		{name: "no action yet", requiredApprovals: 1, want: pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED},
+		{name: "partially approved", requiredApprovals: 2, userData: []*pipelineConfig.DeploymentApprovalUserData{approve}, want: pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REQUESTED},
+		{name: "approved", requiredApprovals: 2, userData: []*pipelineConfig.DeploymentApprovalUserData{approve, approve}, want: pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_APPROVED},
+		{name: "single rejection rejects", requiredApprovals: 2, userData: []*pipelineConfig.DeploymentApprovalUserData{approve, reject}, want: pipelineConfig.DEPLOYMENT_APPROVAL_STATUS_REJECTED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getDeploymentApprovalStatus(tt.requiredApprovals, tt.userData))
+		})
+	}
+}
+
+func TestIsEligibleApprover(t *testing.T) {
+	assert.True(t, isEligibleApprover(true, false, nil, []string{"release-managers"}))
+	assert.True(t, isEligibleApprover(false, true, nil, nil))
+	assert.False(t, isEligibleApprover(false, false, nil, nil))
+	assert.True(t, isEligibleApprover(false, false, []string{"devs", "release-managers"}, []string{"release-managers"}))
+	// trigger access is not enough once approver groups are configured
+	assert.False(t, isEligibleApprover(false, true, []string{"devs"}, []string{"release-managers"}))
+}
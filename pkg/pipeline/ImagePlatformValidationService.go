@@ -0,0 +1,281 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/client/ociRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+type ImagePlatformValidationService interface {
+	// ValidateArtifactPlatforms checks the platforms of the artifact cover the platforms of the cluster nodes the
+	// workload of the pipeline can be scheduled on, the reason is set when the artifact is not allowed to be deployed
+	ValidateArtifactPlatforms(artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline) (bool, string, error)
+}
+
+type ImagePlatformValidationServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	envRepository                repository2.EnvironmentRepository
+	clusterService               cluster.ClusterService
+	k8sCapacityService           capacity.K8sCapacityService
+	ciPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository
+	envConfigOverrideRepository  chartConfig.EnvConfigOverrideRepository
+	chartRepository              chartRepoRepository.ChartRepository
+	ociRegistryClient            ociRegistry.OCIRegistryClient
+}
+
+func NewImagePlatformValidationServiceImpl(logger *zap.SugaredLogger, envRepository repository2.EnvironmentRepository,
+	clusterService cluster.ClusterService, k8sCapacityService capacity.K8sCapacityService,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository, chartRepository chartRepoRepository.ChartRepository,
+	ociRegistryClient ociRegistry.OCIRegistryClient) *ImagePlatformValidationServiceImpl {
+	return &ImagePlatformValidationServiceImpl{
+		logger:                       logger,
+		envRepository:                envRepository,
+		clusterService:               clusterService,
+		k8sCapacityService:           k8sCapacityService,
+		ciPipelineRepository:         ciPipelineRepository,
+		ciTemplateOverrideRepository: ciTemplateOverrideRepository,
+		envConfigOverrideRepository:  envConfigOverrideRepository,
+		chartRepository:              chartRepository,
+		ociRegistryClient:            ociRegistryClient,
+	}
+}
+
+// WorkloadPlacement holds the node placement constraints of the workload, read from the deployment template values
+type WorkloadPlacement struct {
+	NodeSelector map[string]string `json:"nodeSelector"`
+	Affinity     struct {
+		NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity"`
+	} `json:"affinity"`
+	// Spec.Affinity is the node affinity of the devtron reference charts, rendered as a required In match on Key
+	Spec struct {
+		Affinity struct {
+			Key    string `json:"Key"`
+			Values string `json:"Values"`
+		} `json:"Affinity"`
+	} `json:"Spec"`
+}
+
+func (impl *ImagePlatformValidationServiceImpl) ValidateArtifactPlatforms(artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline) (bool, string, error) {
+	if !pipeline.ValidateImagePlatforms {
+		return true, "", nil
+	}
+	imagePlatforms := artifact.GetPlatforms()
+	if len(imagePlatforms) == 0 {
+		// platforms are recorded for multi-arch builds only, the others are read from the registry
+		var err error
+		imagePlatforms, err = impl.getImagePlatformsFromRegistry(artifact)
+		if err != nil {
+			impl.logger.Errorw("error in fetching platforms of image from registry", "err", err, "artifactId", artifact.Id, "image", artifact.Image)
+			return false, "", err
+		}
+		if len(imagePlatforms) == 0 {
+			impl.logger.Infow("skipping platform validation, platforms of artifact not known", "artifactId", artifact.Id, "pipelineId", pipeline.Id)
+			return true, "", nil
+		}
+	}
+	env, err := impl.envRepository.FindById(pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "err", err, "envId", pipeline.EnvironmentId)
+		return false, "", err
+	}
+	clusterBean, err := impl.clusterService.FindById(env.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster", "err", err, "clusterId", env.ClusterId)
+		return false, "", err
+	}
+	if clusterBean.IsVirtualCluster {
+		return true, "", nil
+	}
+	placement, err := impl.getWorkloadPlacement(pipeline)
+	if err != nil {
+		return false, "", err
+	}
+	nodePlatforms, err := impl.k8sCapacityService.GetNodePlatformsByCluster(context.Background(), clusterBean, placement.NodeSelector, placement.GetRequiredNodeAffinity())
+	if err != nil {
+		impl.logger.Errorw("error in fetching node platforms of cluster", "err", err, "clusterId", env.ClusterId)
+		return false, "", err
+	}
+	if len(nodePlatforms) == 0 {
+		// node pools scaled to zero have no nodes to compare with
+		impl.logger.Infow("skipping platform validation, no schedulable node matches the workload placement", "pipelineId", pipeline.Id, "clusterId", env.ClusterId)
+		return true, "", nil
+	}
+	uncoveredPlatforms := GetUncoveredPlatforms(imagePlatforms, nodePlatforms)
+	if len(uncoveredPlatforms) > 0 {
+		return false, fmt.Sprintf("image is not built for node platforms %s of cluster %s", strings.Join(uncoveredPlatforms, ", "), clusterBean.ClusterName), nil
+	}
+	return true, "", nil
+}
+
+// getImagePlatformsFromRegistry reads the platforms of the image from the registry the ci pipeline of the artifact
+// pushes to, nothing is returned for artifacts not built by a ci pipeline
+func (impl *ImagePlatformValidationServiceImpl) getImagePlatformsFromRegistry(artifact *repository.CiArtifact) ([]string, error) {
+	if artifact.PipelineId == 0 {
+		return nil, nil
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(artifact.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", artifact.PipelineId)
+		return nil, err
+	}
+	if ciPipeline.ParentCiPipeline > 0 {
+		// images of linked ci pipelines are built and pushed by the parent pipeline
+		ciPipeline, err = impl.ciPipelineRepository.FindById(ciPipeline.ParentCiPipeline)
+		if err != nil {
+			impl.logger.Errorw("error in fetching parent ci pipeline", "err", err, "ciPipelineId", artifact.PipelineId)
+			return nil, err
+		}
+	}
+	store, err := impl.getDockerRegistry(ciPipeline)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	credential, repositoryName, reference := GetImageRegistryReference(artifact.Image, artifact.ImageDigest, store.RegistryURL)
+	if len(repositoryName) == 0 || len(reference) == 0 {
+		return nil, nil
+	}
+	credential.Username, credential.Password = store.Username, store.Password
+	if store.RegistryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		credential.Username, credential.Password, err = dockerRegistry.CreateCredentialForEcr(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+		if err != nil {
+			impl.logger.Errorw("error in creating ecr credentials", "err", err, "registry", store.Id)
+			return nil, err
+		}
+	}
+	return impl.ociRegistryClient.GetImagePlatforms(credential, repositoryName, reference)
+}
+
+// getDockerRegistry gives the registry the ci pipeline pushes the built image to
+func (impl *ImagePlatformValidationServiceImpl) getDockerRegistry(ciPipeline *pipelineConfig.CiPipeline) (*dockerRegistryRepository.DockerArtifactStore, error) {
+	if ciPipeline.IsDockerConfigOverridden {
+		templateOverride, err := impl.ciTemplateOverrideRepository.FindByCiPipelineId(ciPipeline.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ci template override", "err", err, "ciPipelineId", ciPipeline.Id)
+			return nil, err
+		}
+		return templateOverride.DockerRegistry, nil
+	}
+	if ciPipeline.CiTemplate == nil {
+		return nil, nil
+	}
+	return ciPipeline.CiTemplate.DockerRegistry, nil
+}
+
+// getWorkloadPlacement reads the node placement of the workload from the deployment template values of the pipeline
+func (impl *ImagePlatformValidationServiceImpl) getWorkloadPlacement(pipeline *pipelineConfig.Pipeline) (*WorkloadPlacement, error) {
+	envOverride, err := impl.envConfigOverrideRepository.ActiveEnvConfigOverride(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching env config override", "err", err, "appId", pipeline.AppId, "envId", pipeline.EnvironmentId)
+		return nil, err
+	}
+	var values string
+	if envOverride != nil && envOverride.Id > 0 && envOverride.IsOverride {
+		values = envOverride.EnvOverrideValues
+	} else {
+		chart, err := impl.chartRepository.FindLatestChartForAppByAppId(pipeline.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching chart of app", "err", err, "appId", pipeline.AppId)
+			return nil, err
+		}
+		values = chart.GlobalOverride
+	}
+	return ParseWorkloadPlacement(values), nil
+}
+
+// ParseWorkloadPlacement reads the placement from json or yaml values, keys not holding the kubernetes types like the
+// enabled/values affinity of some charts are ignored
+func ParseWorkloadPlacement(values string) *WorkloadPlacement {
+	placement := &WorkloadPlacement{}
+	var fields map[string]json.RawMessage
+	if err := yaml.Unmarshal([]byte(values), &fields); err != nil {
+		return placement
+	}
+	_ = json.Unmarshal(fields["nodeSelector"], &placement.NodeSelector)
+	_ = json.Unmarshal(fields["affinity"], &placement.Affinity)
+	_ = json.Unmarshal(fields["Spec"], &placement.Spec)
+	return placement
+}
+
+// GetRequiredNodeAffinity merges the required node affinity of the values with the one of the reference chart, as
+// both are rendered into the pod spec by the charts only one of them is expected to be set
+func (placement *WorkloadPlacement) GetRequiredNodeAffinity() *corev1.NodeSelector {
+	chartAffinity := placement.Spec.Affinity
+	if len(chartAffinity.Key) > 0 && len(chartAffinity.Values) > 0 {
+		return &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: chartAffinity.Key, Operator: corev1.NodeSelectorOpIn, Values: []string{chartAffinity.Values}}},
+		}}}
+	}
+	if placement.Affinity.NodeAffinity == nil {
+		return nil
+	}
+	return placement.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+}
+
+// GetImageRegistryReference splits an image like registry.io/team/app:tag into the registry endpoint, the repository
+// and the digest or tag to fetch. The scheme of the endpoint is taken from the configured registry url
+func GetImageRegistryReference(image string, imageDigest string, registryUrl string) (*ociRegistry.RegistryCredential, string, string) {
+	scheme := "https"
+	if strings.HasPrefix(strings.ToLower(registryUrl), "http://") {
+		scheme = "http"
+	}
+	name, reference := image, ""
+	if index := strings.Index(name, "@"); index >= 0 {
+		name, reference = name[:index], name[index+1:]
+	} else if index = strings.LastIndex(name, ":"); index > strings.LastIndex(name, "/") {
+		name, reference = name[:index], name[index+1:]
+	}
+	if strings.HasPrefix(imageDigest, "sha256:") {
+		reference = imageDigest
+	}
+	host, repositoryName := "docker.io", name
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		host, repositoryName = parts[0], parts[1]
+	}
+	if host == "docker.io" || host == "index.docker.io" {
+		host = "registry-1.docker.io"
+		if !strings.Contains(repositoryName, "/") {
+			repositoryName = "library/" + repositoryName
+		}
+	}
+	return &ociRegistry.RegistryCredential{RegistryUrl: fmt.Sprintf("%s://%s", scheme, host)}, repositoryName, reference
+}
+
+// GetUncoveredPlatforms returns the node platforms none of the image platforms can run on. Platforms are compared on
+// os/arch, the variant of an image platform like linux/arm64/v8 is not reported by nodes
+func GetUncoveredPlatforms(imagePlatforms []string, nodePlatforms []string) []string {
+	covered := make(map[string]bool)
+	for _, imagePlatform := range imagePlatforms {
+		covered[getOsArch(imagePlatform)] = true
+	}
+	var uncoveredPlatforms []string
+	for _, nodePlatform := range nodePlatforms {
+		if !covered[getOsArch(nodePlatform)] {
+			uncoveredPlatforms = append(uncoveredPlatforms, nodePlatform)
+		}
+	}
+	return uncoveredPlatforms
+}
+
+func getOsArch(platform string) string {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(platform)), "/")
+	if len(parts) < 2 {
+		return strings.Join(parts, "/")
+	}
+	return parts[0] + "/" + parts[1]
+}
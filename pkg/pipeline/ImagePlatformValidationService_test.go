@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetUncoveredPlatforms(t *testing.T) {
+	nodePlatforms := []string{"linux/amd64", "linux/arm64"}
+	assert.Empty(t, GetUncoveredPlatforms([]string{"linux/amd64", "linux/arm64/v8"}, nodePlatforms))
+	assert.Equal(t, []string{"linux/arm64"}, GetUncoveredPlatforms([]string{"linux/amd64"}, nodePlatforms))
+	assert.Equal(t, []string{"linux/amd64", "linux/arm64"}, GetUncoveredPlatforms([]string{"windows/amd64"}, nodePlatforms))
+	assert.Empty(t, GetUncoveredPlatforms([]string{"linux/amd64"}, nil))
+}
+
+func TestValidateCiBuildConfigTargetPlatforms(t *testing.T) {
+	buildConfig := func(platforms ...string) *bean.CiBuildConfigBean {
+		return &bean.CiBuildConfigBean{
+			CiBuildType:       bean.SELF_DOCKERFILE_BUILD_TYPE,
+			DockerBuildConfig: &bean.DockerBuildConfig{TargetPlatforms: platforms},
+		}
+	}
+	assert.NoError(t, bean.ValidateCiBuildConfig(buildConfig("linux/amd64", "linux/arm64", "linux/arm/v7")))
+	assert.NoError(t, bean.ValidateCiBuildConfig(buildConfig()))
+	assert.NoError(t, bean.ValidateCiBuildConfig(&bean.CiBuildConfigBean{CiBuildType: bean.BUILDPACK_BUILD_TYPE}))
+	assert.Error(t, bean.ValidateCiBuildConfig(buildConfig("amd64")))
+	assert.Error(t, bean.ValidateCiBuildConfig(buildConfig("linux/amd64", "linux/amd64")))
+}
+
+func TestParseWorkloadPlacement(t *testing.T) {
+	placement := ParseWorkloadPlacement(`{"nodeSelector":{"kubernetes.io/arch":"arm64"},"Spec":{"Affinity":{"Key":"pool","Values":"graviton"}}}`)
+	assert.Equal(t, map[string]string{"kubernetes.io/arch": "arm64"}, placement.NodeSelector)
+	affinity := placement.GetRequiredNodeAffinity()
+	assert.Equal(t, "pool", affinity.NodeSelectorTerms[0].MatchExpressions[0].Key)
+	assert.Equal(t, []string{"graviton"}, affinity.NodeSelectorTerms[0].MatchExpressions[0].Values)
+
+	placement = ParseWorkloadPlacement("affinity:\n  nodeAffinity:\n    requiredDuringSchedulingIgnoredDuringExecution:\n" +
+		"      nodeSelectorTerms:\n      - matchExpressions:\n        - key: pool\n          operator: NotIn\n          values: [spot]\n")
+	assert.Empty(t, placement.NodeSelector)
+	assert.Equal(t, "NotIn", string(placement.GetRequiredNodeAffinity().NodeSelectorTerms[0].MatchExpressions[0].Operator))
+
+	//chart specific affinity flags are not node placement
+	placement = ParseWorkloadPlacement(`{"affinity":{"enabled":false,"values":"x"},"nodeSelector":{}}`)
+	assert.Nil(t, placement.GetRequiredNodeAffinity())
+	assert.Nil(t, ParseWorkloadPlacement("").GetRequiredNodeAffinity())
+}
+
+func TestGetImageRegistryReference(t *testing.T) {
+	credential, repositoryName, reference := GetImageRegistryReference("registry.io:5000/team/payments:a1b2", "", "http://registry.io:5000")
+	assert.Equal(t, "http://registry.io:5000", credential.RegistryUrl)
+	assert.Equal(t, "team/payments", repositoryName)
+	assert.Equal(t, "a1b2", reference)
+
+	credential, repositoryName, reference = GetImageRegistryReference("team/payments:a1b2", "sha256:abc", "docker.io")
+	assert.Equal(t, "https://registry-1.docker.io", credential.RegistryUrl)
+	assert.Equal(t, "team/payments", repositoryName)
+	assert.Equal(t, "sha256:abc", reference)
+
+	_, repositoryName, reference = GetImageRegistryReference("nginx", "", "")
+	assert.Equal(t, "library/nginx", repositoryName)
+	assert.Equal(t, "", reference)
+}
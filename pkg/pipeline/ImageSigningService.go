@@ -0,0 +1,498 @@
+package pipeline
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ImageSigningService interface {
+	SaveSigningKey(request *bean.ImageSigningKeyDto) (*bean.ImageSigningKeyDto, error)
+	GetSigningKeys() ([]*bean.ImageSigningKeyDto, error)
+	DeleteSigningKey(id int, userId int32) error
+
+	SaveCiSigningConfig(request *bean.ImageSigningConfigDto) (*bean.ImageSigningConfigDto, error)
+	GetCiSigningConfig(ciPipelineId int) (*bean.ImageSigningConfigDto, error)
+	DeleteCiSigningConfig(ciPipelineId int, userId int32) error
+	// GetSigningRequestForCi returns what the ci runner needs to sign the built image, nil when signing is not enabled
+	GetSigningRequestForCi(ciPipelineId int) (*bean.ImageSigningRequest, error)
+
+	SaveImageSignatures(artifact *repository.CiArtifact, signatures []*bean.ImageSignatureDto) error
+	GetImageSignatures(ciArtifactId int) ([]*bean.ImageSignatureDto, error)
+
+	// SavePolicy creates or updates the policy of the scope, inherit removes the policy of the scope
+	SavePolicy(request *bean.ImageSigningPolicyDto) (*bean.ImageSigningPolicyDto, error)
+	GetApplicablePolicy(clusterId, envId, appId int) (*bean.ImageSigningPolicyDto, error)
+	// VerifyArtifact checks the artifact against the signing policy applicable on the app and environment, the reason
+	// is set when the artifact is not allowed to be deployed
+	VerifyArtifact(artifact *repository.CiArtifact, envId int, appId int) (bool, string, error)
+}
+
+type ImageSigningServiceImpl struct {
+	logger                 *zap.SugaredLogger
+	imageSigningRepository security.ImageSigningRepository
+	envRepository          repository2.EnvironmentRepository
+}
+
+func NewImageSigningServiceImpl(logger *zap.SugaredLogger, imageSigningRepository security.ImageSigningRepository,
+	envRepository repository2.EnvironmentRepository) *ImageSigningServiceImpl {
+	return &ImageSigningServiceImpl{
+		logger:                 logger,
+		imageSigningRepository: imageSigningRepository,
+		envRepository:          envRepository,
+	}
+}
+
+func (impl *ImageSigningServiceImpl) SaveSigningKey(request *bean.ImageSigningKeyDto) (*bean.ImageSigningKeyDto, error) {
+	if _, err := parsePublicKey(request.PublicKey); err != nil {
+		impl.logger.Errorw("invalid public key for image signing", "err", err, "name", request.Name)
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	key := &security.ImageSigningKey{
+		Id:         request.Id,
+		Name:       request.Name,
+		PublicKey:  request.PublicKey,
+		SecretName: request.SecretName,
+		Active:     true,
+		AuditLog:   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+	}
+	if request.Id > 0 {
+		existing, err := impl.imageSigningRepository.FindKeyById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching image signing key", "err", err, "id", request.Id)
+			return nil, err
+		}
+		key.CreatedOn, key.CreatedBy = existing.CreatedOn, existing.CreatedBy
+	}
+	var err error
+	if key.Id > 0 {
+		err = impl.imageSigningRepository.UpdateKey(key)
+	} else {
+		err = impl.imageSigningRepository.SaveKey(key)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving image signing key", "err", err, "name", request.Name)
+		return nil, err
+	}
+	request.Id = key.Id
+	return request, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetSigningKeys() ([]*bean.ImageSigningKeyDto, error) {
+	keys, err := impl.imageSigningRepository.FindActiveKeys()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signing keys", "err", err)
+		return nil, err
+	}
+	keyDtos := make([]*bean.ImageSigningKeyDto, 0, len(keys))
+	for _, key := range keys {
+		keyDtos = append(keyDtos, &bean.ImageSigningKeyDto{
+			Id:         key.Id,
+			Name:       key.Name,
+			PublicKey:  key.PublicKey,
+			SecretName: key.SecretName,
+		})
+	}
+	return keyDtos, nil
+}
+
+func (impl *ImageSigningServiceImpl) DeleteSigningKey(id int, userId int32) error {
+	key, err := impl.imageSigningRepository.FindKeyById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signing key", "err", err, "id", id)
+		return err
+	}
+	key.Active = false
+	key.UpdatedOn = time.Now()
+	key.UpdatedBy = userId
+	return impl.imageSigningRepository.UpdateKey(key)
+}
+
+func (impl *ImageSigningServiceImpl) SaveCiSigningConfig(request *bean.ImageSigningConfigDto) (*bean.ImageSigningConfigDto, error) {
+	_, err := impl.imageSigningRepository.FindKeyById(request.SigningKeyId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signing key", "err", err, "id", request.SigningKeyId)
+		if util.IsErrNoRows(err) {
+			errMsg := fmt.Sprintf("image signing key %d not found", request.SigningKeyId)
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+		}
+		return nil, err
+	}
+	config, err := impl.imageSigningRepository.FindActiveConfigByCiPipelineId(request.CiPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signing config", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	config.CiPipelineId = request.CiPipelineId
+	config.SigningKeyId = request.SigningKeyId
+	config.GenerateAttestation = request.GenerateAttestation
+	config.Active = true
+	config.UpdatedOn = time.Now()
+	config.UpdatedBy = request.UserId
+	if config.Id > 0 {
+		err = impl.imageSigningRepository.UpdateConfig(config)
+	} else {
+		config.CreatedOn = time.Now()
+		config.CreatedBy = request.UserId
+		err = impl.imageSigningRepository.SaveConfig(config)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving image signing config", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	request.Id = config.Id
+	return request, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetCiSigningConfig(ciPipelineId int) (*bean.ImageSigningConfigDto, error) {
+	config, err := impl.imageSigningRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching image signing config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return &bean.ImageSigningConfigDto{
+		Id:                  config.Id,
+		CiPipelineId:        config.CiPipelineId,
+		SigningKeyId:        config.SigningKeyId,
+		GenerateAttestation: config.GenerateAttestation,
+	}, nil
+}
+
+func (impl *ImageSigningServiceImpl) DeleteCiSigningConfig(ciPipelineId int, userId int32) error {
+	config, err := impl.imageSigningRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signing config", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+	config.Active = false
+	config.UpdatedOn = time.Now()
+	config.UpdatedBy = userId
+	return impl.imageSigningRepository.UpdateConfig(config)
+}
+
+func (impl *ImageSigningServiceImpl) GetSigningRequestForCi(ciPipelineId int) (*bean.ImageSigningRequest, error) {
+	config, err := impl.imageSigningRepository.FindActiveConfigByCiPipelineId(ciPipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching image signing config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	key, err := impl.imageSigningRepository.FindKeyById(config.SigningKeyId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			// key was removed after signing was configured, images are built without signature
+			impl.logger.Warnw("image signing key not found, skipping signing", "ciPipelineId", ciPipelineId, "signingKeyId", config.SigningKeyId)
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching image signing key", "err", err, "id", config.SigningKeyId)
+		return nil, err
+	}
+	return &bean.ImageSigningRequest{
+		SigningKeyId:        key.Id,
+		KeySecretName:       key.SecretName,
+		GenerateAttestation: config.GenerateAttestation,
+	}, nil
+}
+
+func (impl *ImageSigningServiceImpl) SaveImageSignatures(artifact *repository.CiArtifact, signatures []*bean.ImageSignatureDto) error {
+	var models []*security.ImageSignature
+	for _, signature := range signatures {
+		if signature.SignatureType != security.IMAGE_SIGNATURE_TYPE_SIGNATURE && signature.SignatureType != security.IMAGE_SIGNATURE_TYPE_ATTESTATION {
+			impl.logger.Warnw("ignoring image signature of unknown type", "type", signature.SignatureType, "artifactId", artifact.Id)
+			continue
+		}
+		models = append(models, &security.ImageSignature{
+			CiArtifactId:  artifact.Id,
+			ImageDigest:   artifact.ImageDigest,
+			SignatureType: signature.SignatureType,
+			SigningKeyId:  signature.SigningKeyId,
+			PredicateType: signature.PredicateType,
+			Payload:       signature.Payload,
+			Signature:     signature.Signature,
+			AuditLog:      sql.AuditLog{CreatedOn: time.Now(), CreatedBy: artifact.CreatedBy, UpdatedOn: time.Now(), UpdatedBy: artifact.CreatedBy},
+		})
+	}
+	err := impl.imageSigningRepository.SaveSignatures(models)
+	if err != nil {
+		impl.logger.Errorw("error in saving image signatures", "err", err, "artifactId", artifact.Id)
+	}
+	return err
+}
+
+func (impl *ImageSigningServiceImpl) GetImageSignatures(ciArtifactId int) ([]*bean.ImageSignatureDto, error) {
+	signatures, err := impl.imageSigningRepository.FindSignaturesByCiArtifactId(ciArtifactId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signatures", "err", err, "artifactId", ciArtifactId)
+		return nil, err
+	}
+	signatureDtos := make([]*bean.ImageSignatureDto, 0, len(signatures))
+	for _, signature := range signatures {
+		signatureDtos = append(signatureDtos, &bean.ImageSignatureDto{
+			Id:            signature.Id,
+			SignatureType: signature.SignatureType,
+			SigningKeyId:  signature.SigningKeyId,
+			PredicateType: signature.PredicateType,
+			Payload:       signature.Payload,
+			Signature:     signature.Signature,
+			CreatedOn:     signature.CreatedOn,
+		})
+	}
+	return signatureDtos, nil
+}
+
+func (impl *ImageSigningServiceImpl) SavePolicy(request *bean.ImageSigningPolicyDto) (*bean.ImageSigningPolicyDto, error) {
+	action, err := parseImageSigningPolicyAction(request.Action)
+	if err != nil {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: err.Error()}
+	}
+	if request.AppId > 0 && request.EnvId == 0 {
+		errMsg := "environment is required for app level image signing policy"
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: errMsg, UserMessage: errMsg}
+	}
+	if request.EnvId > 0 {
+		//policies are stored at the most specific level only, like cve policies
+		request.ClusterId = 0
+	}
+	policy, err := impl.imageSigningRepository.FindPolicyByScope(request.ClusterId, request.EnvId, request.AppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signing policy", "err", err, "request", request)
+		return nil, err
+	}
+	if action == security.Inherit {
+		if policy.Id == 0 {
+			return request, nil
+		}
+		policy.Deleted = true
+		policy.UpdatedOn = time.Now()
+		policy.UpdatedBy = request.UserId
+		err = impl.imageSigningRepository.UpdatePolicy(policy)
+		if err != nil {
+			impl.logger.Errorw("error in deleting image signing policy", "err", err, "id", policy.Id)
+			return nil, err
+		}
+		request.Id = 0
+		return request, nil
+	}
+	policy.Global = request.ClusterId == 0 && request.EnvId == 0 && request.AppId == 0
+	policy.ClusterId = request.ClusterId
+	policy.EnvironmentId = request.EnvId
+	policy.AppId = request.AppId
+	policy.Action = action
+	policy.SigningKeyId = request.SigningKeyId
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = request.UserId
+	if policy.Id > 0 {
+		err = impl.imageSigningRepository.UpdatePolicy(policy)
+	} else {
+		policy.CreatedOn = time.Now()
+		policy.CreatedBy = request.UserId
+		err = impl.imageSigningRepository.SavePolicy(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving image signing policy", "err", err, "request", request)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetApplicablePolicy(clusterId, envId, appId int) (*bean.ImageSigningPolicyDto, error) {
+	policy, err := impl.getApplicablePolicy(clusterId, envId, appId)
+	if err != nil {
+		return nil, err
+	}
+	requestedLevel := security.Global
+	if appId > 0 {
+		requestedLevel = security.Application
+	} else if envId > 0 {
+		requestedLevel = security.Environment
+	} else if clusterId > 0 {
+		requestedLevel = security.Cluster
+	}
+	policyDto := &bean.ImageSigningPolicyDto{
+		ClusterId: clusterId,
+		EnvId:     envId,
+		AppId:     appId,
+		Action:    security.Inherit.String(),
+		Inherited: true,
+	}
+	if policy != nil {
+		policyDto.Id = policy.Id
+		policyDto.Action = policy.Action.String()
+		policyDto.SigningKeyId = policy.SigningKeyId
+		policyDto.PolicyOrigin = policy.PolicyLevel().String()
+		policyDto.Inherited = policy.PolicyLevel() != requestedLevel
+	}
+	return policyDto, nil
+}
+
+// getApplicablePolicy returns the policy of the most specific level, nil when no level has a policy
+func (impl *ImageSigningServiceImpl) getApplicablePolicy(clusterId, envId, appId int) (*security.ImageSigningPolicy, error) {
+	if envId > 0 && clusterId == 0 {
+		env, err := impl.envRepository.FindById(envId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "err", err, "envId", envId)
+			return nil, err
+		}
+		clusterId = env.ClusterId
+	}
+	policies, err := impl.imageSigningRepository.FindPoliciesForScope(clusterId, envId, appId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signing policies", "err", err, "clusterId", clusterId, "envId", envId, "appId", appId)
+		return nil, err
+	}
+	var applicablePolicy *security.ImageSigningPolicy
+	for _, policy := range policies {
+		if policy.Action == security.Inherit {
+			continue
+		}
+		if applicablePolicy == nil || policy.PolicyLevel() > applicablePolicy.PolicyLevel() {
+			applicablePolicy = policy
+		}
+	}
+	return applicablePolicy, nil
+}
+
+func (impl *ImageSigningServiceImpl) VerifyArtifact(artifact *repository.CiArtifact, envId int, appId int) (bool, string, error) {
+	policy, err := impl.getApplicablePolicy(0, envId, appId)
+	if err != nil {
+		return false, "", err
+	}
+	if policy == nil || policy.Action != security.Block {
+		return true, "", nil
+	}
+	if len(artifact.ImageDigest) == 0 {
+		return false, "image digest is not known, signature cannot be verified", nil
+	}
+	var keys []*security.ImageSigningKey
+	if policy.SigningKeyId > 0 {
+		key, err := impl.imageSigningRepository.FindKeyById(policy.SigningKeyId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching image signing key", "err", err, "id", policy.SigningKeyId)
+			return false, "", err
+		}
+		if key != nil && key.Id > 0 {
+			keys = append(keys, key)
+		}
+	} else {
+		keys, err = impl.imageSigningRepository.FindActiveKeys()
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching image signing keys", "err", err)
+			return false, "", err
+		}
+	}
+	if len(keys) == 0 {
+		return false, "no trusted signing key is configured", nil
+	}
+	signatures, err := impl.imageSigningRepository.FindSignaturesByImageDigest(artifact.ImageDigest)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching image signatures", "err", err, "digest", artifact.ImageDigest)
+		return false, "", err
+	}
+	for _, signature := range signatures {
+		if signature.SignatureType != security.IMAGE_SIGNATURE_TYPE_SIGNATURE {
+			continue
+		}
+		for _, key := range keys {
+			err = VerifyImageSignature(key.PublicKey, artifact.ImageDigest, signature.Payload, signature.Signature)
+			if err == nil {
+				return true, "", nil
+			}
+			impl.logger.Debugw("image signature not verified with key", "err", err, "signatureId", signature.Id, "keyId", key.Id)
+		}
+	}
+	return false, fmt.Sprintf("no valid signature found for image digest %s", artifact.ImageDigest), nil
+}
+
+func parseImageSigningPolicyAction(action string) (security.PolicyAction, error) {
+	switch action {
+	case security.Inherit.String():
+		return security.Inherit, nil
+	case security.Allow.String():
+		return security.Allow, nil
+	case security.Block.String():
+		return security.Block, nil
+	}
+	return security.Inherit, fmt.Errorf("unsupported action %s", action)
+}
+
+// cosignSimpleSigningPayload is the part of the cosign signature payload needed to tie a signature to an image
+type cosignSimpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// VerifyImageSignature verifies a cosign signature made with the private key of publicKeyPem, the signed payload
+// must be for the given image digest
+func VerifyImageSignature(publicKeyPem string, imageDigest string, payload string, signature string) error {
+	publicKey, err := parsePublicKey(publicKeyPem)
+	if err != nil {
+		return err
+	}
+	signedPayload := &cosignSimpleSigningPayload{}
+	if err = json.Unmarshal([]byte(payload), signedPayload); err != nil {
+		return fmt.Errorf("invalid signature payload, %v", err)
+	}
+	if signedPayload.Critical.Image.DockerManifestDigest != imageDigest {
+		return fmt.Errorf("signature is for image digest %q", signedPayload.Critical.Image.DockerManifestDigest)
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding, %v", err)
+	}
+	digest := sha256.Sum256([]byte(payload))
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], rawSignature) {
+			return fmt.Errorf("invalid ecdsa signature")
+		}
+	case *rsa.PublicKey:
+		if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], rawSignature); err != nil {
+			return fmt.Errorf("invalid rsa signature, %v", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, []byte(payload), rawSignature) {
+			return fmt.Errorf("invalid ed25519 signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+func parsePublicKey(publicKeyPem string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPem))
+	if block == nil {
+		return nil, fmt.Errorf("public key is not pem encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key, %v", err)
+	}
+	return publicKey, nil
+}
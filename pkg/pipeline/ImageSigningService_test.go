@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testImageDigest = "sha256:8f3c4b1d0e2a6b7c9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e"
+
+func newTestSigner(t *testing.T) (*ecdsa.PrivateKey, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.Nil(t, err)
+	return privateKey, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func signPayload(t *testing.T, privateKey *ecdsa.PrivateKey, payload string) string {
+	digest := sha256.Sum256([]byte(payload))
+	signature, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+	assert.Nil(t, err)
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestVerifyImageSignature(t *testing.T) {
+	privateKey, publicKey := newTestSigner(t)
+	payload := fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.local/app"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, testImageDigest)
+	signature := signPayload(t, privateKey, payload)
+
+	t.Run("valid signature", func(t *testing.T) {
+		assert.Nil(t, VerifyImageSignature(publicKey, testImageDigest, payload, signature))
+	})
+	t.Run("signature of another image", func(t *testing.T) {
+		err := VerifyImageSignature(publicKey, "sha256:0000", payload, signature)
+		assert.NotNil(t, err)
+	})
+	t.Run("tampered payload", func(t *testing.T) {
+		tampered := payload[:len(payload)-5] + `{}}` + "}"
+		err := VerifyImageSignature(publicKey, testImageDigest, tampered, signature)
+		assert.NotNil(t, err)
+	})
+	t.Run("signed with another key", func(t *testing.T) {
+		_, otherPublicKey := newTestSigner(t)
+		err := VerifyImageSignature(otherPublicKey, testImageDigest, payload, signature)
+		assert.NotNil(t, err)
+	})
+	t.Run("invalid public key", func(t *testing.T) {
+		err := VerifyImageSignature("not a key", testImageDigest, payload, signature)
+		assert.NotNil(t, err)
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/appStatus"
 	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
 	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	bean4 "github.com/devtron-labs/devtron/pkg/app/bean"
 	appGroup2 "github.com/devtron-labs/devtron/pkg/appGroup"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
@@ -45,6 +46,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -231,6 +233,8 @@ type PipelineBuilderImpl struct {
 	attributesRepository                            repository.AttributesRepository
 	securityConfig                                  *SecurityConfig
 	imageTaggingService                             ImageTaggingService
+	manifestPushConfigRepository                    repository5.ManifestPushConfigRepository
+	ociRegistryConfigRepository                     dockerRegistryRepository.OCIRegistryConfigRepository
 }
 
 func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
@@ -285,7 +289,9 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 	chartDeploymentService util.ChartDeploymentService,
 	K8sUtil *util4.K8sUtil,
 	attributesRepository repository.AttributesRepository,
-	imageTaggingService ImageTaggingService) *PipelineBuilderImpl {
+	imageTaggingService ImageTaggingService,
+	manifestPushConfigRepository repository5.ManifestPushConfigRepository,
+	ociRegistryConfigRepository dockerRegistryRepository.OCIRegistryConfigRepository) *PipelineBuilderImpl {
 	securityConfig := &SecurityConfig{}
 	err := env.Parse(securityConfig)
 	if err != nil {
@@ -354,6 +360,8 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 		attributesRepository:                            attributesRepository,
 		securityConfig:                                  securityConfig,
 		imageTaggingService:                             imageTaggingService,
+		manifestPushConfigRepository:                    manifestPushConfigRepository,
+		ociRegistryConfigRepository:                     ociRegistryConfigRepository,
 	}
 }
 
@@ -1827,6 +1835,10 @@ func (impl *PipelineBuilderImpl) CreateCdPipelines(pipelineCreateRequest *bean.C
 			impl.logger.Errorw("validation error in creating pipeline", "name", pipeline.Name, "err", err)
 			return nil, err
 		}
+		if err := impl.validateManifestStorageConfig(pipeline, pipeline.DeploymentAppType); err != nil {
+			impl.logger.Errorw("validation error in manifest storage config", "name", pipeline.Name, "err", err)
+			return nil, err
+		}
 	}
 
 	isGitOpsRequiredForCD := impl.IsGitOpsRequiredForCD(pipelineCreateRequest)
@@ -1915,6 +1927,134 @@ func (impl *PipelineBuilderImpl) validateDeploymentAppType(pipeline *bean.CDPipe
 	return err
 }
 
+var helmChartNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// validateManifestStorageConfig validates the OCI registry push target, only helm deployments can push charts to a registry
+func (impl *PipelineBuilderImpl) validateManifestStorageConfig(pipeline *bean.CDPipelineConfigObject, deploymentAppType string) error {
+	if len(pipeline.ManifestStorageType) == 0 || bean.IsGitStorage(pipeline.ManifestStorageType) {
+		return nil
+	}
+	if !bean.IsOCIHelmRepoStorage(pipeline.ManifestStorageType) {
+		return newManifestStorageBadRequestError(fmt.Sprintf("manifest storage type %s is not supported", pipeline.ManifestStorageType))
+	}
+	if !util.IsHelmApp(deploymentAppType) {
+		return newManifestStorageBadRequestError("charts can be pushed to an OCI registry only for helm deployments")
+	}
+	if len(pipeline.ContainerRegistryName) == 0 || len(strings.Trim(pipeline.RepoUrl, "/")) == 0 {
+		return newManifestStorageBadRequestError("container registry and repository are required to push charts to an OCI registry")
+	}
+	if !helmChartNameRegex.MatchString(pipeline.ChartName) {
+		return newManifestStorageBadRequestError(fmt.Sprintf("invalid chart name %q, it must consist of lower case alphanumeric characters or '-'", pipeline.ChartName))
+	}
+	if _, err := app.GetOCIChartVersion(pipeline.ChartBaseVersion, 0); err != nil {
+		return newManifestStorageBadRequestError(err.Error())
+	}
+	store, err := impl.dockerArtifactStoreRepository.FindOne(pipeline.ContainerRegistryName)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching container registry", "err", err, "registry", pipeline.ContainerRegistryName)
+		return err
+	}
+	if util.IsErrNoRows(err) || !store.IsOCICompliantRegistry {
+		return newManifestStorageBadRequestError(fmt.Sprintf("container registry %s is not an OCI compliant registry", pipeline.ContainerRegistryName))
+	}
+	ociConfig, err := impl.ociRegistryConfigRepository.FindOneByDockerRegistryIdAndRepositoryType(pipeline.ContainerRegistryName, dockerRegistryRepository.OCI_REGISRTY_REPO_TYPE_CHART)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching oci registry config", "err", err, "registry", pipeline.ContainerRegistryName)
+		return err
+	}
+	if util.IsErrNoRows(err) || !app.IsRepositoryActionAllowed(ociConfig.RepositoryAction, dockerRegistryRepository.STORAGE_ACTION_TYPE_PUSH) {
+		return newManifestStorageBadRequestError(fmt.Sprintf("container registry %s does not allow pushing helm charts", pipeline.ContainerRegistryName))
+	}
+	if pipeline.DeployFromOciChart && !app.IsRepositoryActionAllowed(ociConfig.RepositoryAction, dockerRegistryRepository.STORAGE_ACTION_TYPE_PULL) {
+		return newManifestStorageBadRequestError(fmt.Sprintf("container registry %s does not allow pulling helm charts", pipeline.ContainerRegistryName))
+	}
+	return nil
+}
+
+func newManifestStorageBadRequestError(message string) error {
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusBadRequest,
+		InternalMessage: message,
+		UserMessage:     message,
+	}
+}
+
+// saveManifestPushConfig creates or updates the OCI push target of the pipeline, selecting git storage removes it
+// and an empty storage type leaves the existing config untouched
+func (impl *PipelineBuilderImpl) saveManifestPushConfig(pipeline *bean.CDPipelineConfigObject, appId int, envId int, userId int32, tx *pg.Tx) error {
+	if len(pipeline.ManifestStorageType) == 0 {
+		return nil
+	}
+	if bean.IsGitStorage(pipeline.ManifestStorageType) {
+		return impl.deleteManifestPushConfig(appId, envId, userId, tx)
+	}
+	credentialsConfig, err := json.Marshal(&bean4.HelmRepositoryConfig{
+		RepositoryName:        strings.Trim(pipeline.RepoUrl, "/"),
+		ContainerRegistryName: pipeline.ContainerRegistryName,
+	})
+	if err != nil {
+		return err
+	}
+	manifestPushConfig, err := impl.manifestPushConfigRepository.GetManifestPushConfigByAppIdAndEnvId(appId, envId)
+	if err != nil {
+		return err
+	}
+	if manifestPushConfig == nil {
+		manifestPushConfig = &repository5.ManifestPushConfig{
+			AppId:    appId,
+			EnvId:    envId,
+			AuditLog: sql.AuditLog{CreatedBy: userId, CreatedOn: time.Now()},
+		}
+	}
+	manifestPushConfig.CredentialsConfig = string(credentialsConfig)
+	manifestPushConfig.ChartName = pipeline.ChartName
+	manifestPushConfig.ChartBaseVersion = pipeline.ChartBaseVersion
+	manifestPushConfig.StorageType = pipeline.ManifestStorageType
+	manifestPushConfig.DeployFromOciChart = pipeline.DeployFromOciChart
+	manifestPushConfig.UpdatedBy = userId
+	manifestPushConfig.UpdatedOn = time.Now()
+	if manifestPushConfig.Id == 0 {
+		_, err = impl.manifestPushConfigRepository.SaveConfig(manifestPushConfig, tx)
+		return err
+	}
+	return impl.manifestPushConfigRepository.UpdateConfig(manifestPushConfig, tx)
+}
+
+func (impl *PipelineBuilderImpl) deleteManifestPushConfig(appId int, envId int, userId int32, tx *pg.Tx) error {
+	manifestPushConfig, err := impl.manifestPushConfigRepository.GetManifestPushConfigByAppIdAndEnvId(appId, envId)
+	if err != nil || manifestPushConfig == nil {
+		return err
+	}
+	manifestPushConfig.Deleted = true
+	manifestPushConfig.UpdatedBy = userId
+	manifestPushConfig.UpdatedOn = time.Now()
+	return impl.manifestPushConfigRepository.UpdateConfig(manifestPushConfig, tx)
+}
+
+func (impl *PipelineBuilderImpl) setManifestStorageConfig(cdPipeline *bean.CDPipelineConfigObject, appId int, envId int) error {
+	manifestPushConfig, err := impl.manifestPushConfigRepository.GetManifestPushConfigByAppIdAndEnvId(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching manifest push config", "err", err, "appId", appId, "envId", envId)
+		return err
+	}
+	if manifestPushConfig == nil || !bean.IsOCIHelmRepoStorage(manifestPushConfig.StorageType) {
+		return nil
+	}
+	helmRepositoryConfig := &bean4.HelmRepositoryConfig{}
+	err = json.Unmarshal([]byte(manifestPushConfig.CredentialsConfig), helmRepositoryConfig)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling helm repository config", "err", err, "manifestPushConfigId", manifestPushConfig.Id)
+		return err
+	}
+	cdPipeline.ManifestStorageType = manifestPushConfig.StorageType
+	cdPipeline.ContainerRegistryName = helmRepositoryConfig.ContainerRegistryName
+	cdPipeline.RepoUrl = helmRepositoryConfig.RepositoryName
+	cdPipeline.ChartName = manifestPushConfig.ChartName
+	cdPipeline.ChartBaseVersion = manifestPushConfig.ChartBaseVersion
+	cdPipeline.DeployFromOciChart = manifestPushConfig.DeployFromOciChart
+	return nil
+}
+
 func allDeploymentConfigTrue(deploymentConfig map[string]bool) bool {
 	for _, value := range deploymentConfig {
 		if !value {
@@ -2202,6 +2342,11 @@ func (impl *PipelineBuilderImpl) DeleteCdPipeline(pipeline *pipelineConfig.Pipel
 			}
 		}
 	}
+	err = impl.deleteManifestPushConfig(pipeline.AppId, pipeline.EnvironmentId, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting manifest push config", "err", err, "pipelineId", pipeline.Id)
+		return deleteResponse, err
+	}
 	err = tx.Commit()
 	if err != nil {
 		impl.logger.Errorw("error in committing db transaction", "err", err)
@@ -3148,6 +3293,12 @@ func (impl *PipelineBuilderImpl) createCdPipeline(ctx context.Context, app *app2
 
 	}
 
+	err = impl.saveManifestPushConfig(pipeline, app.Id, pipeline.EnvironmentId, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving manifest push config", "err", err, "pipelineId", pipelineId)
+		return 0, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return 0, err
@@ -3175,6 +3326,16 @@ func (impl *PipelineBuilderImpl) updateCdPipeline(ctx context.Context, pipeline
 		}
 		return err
 	}
+	dbPipeline, err := impl.pipelineRepository.FindById(pipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	err = impl.validateManifestStorageConfig(pipeline, dbPipeline.DeploymentAppType)
+	if err != nil {
+		impl.logger.Errorw("validation error in manifest storage config", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
 	dbConnection := impl.pipelineRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -3187,6 +3348,11 @@ func (impl *PipelineBuilderImpl) updateCdPipeline(ctx context.Context, pipeline
 		impl.logger.Errorw("error in updating pipeline")
 		return err
 	}
+	err = impl.saveManifestPushConfig(pipeline, dbPipeline.AppId, dbPipeline.EnvironmentId, userID, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving manifest push config", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
 
 	// strategies for pipeline ids, there is only one is default
 	existingStrategies, err := impl.pipelineConfigRepository.GetAllStrategyByPipelineId(pipeline.Id)
@@ -3427,6 +3593,7 @@ func (impl *PipelineBuilderImpl) GetCdPipelinesForApp(appId int) (cdPipelines *b
 			PostStageConfigMapSecretNames: dbPipeline.PostStageConfigMapSecretNames,
 			RunPreStageInEnv:              dbPipeline.RunPreStageInEnv,
 			RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
+			ValidateImagePlatforms:        dbPipeline.ValidateImagePlatforms,
 			DeploymentAppType:             dbPipeline.DeploymentAppType,
 			ParentPipelineType:            appToWorkflowMapping.ParentType,
 			ParentPipelineId:              appToWorkflowMapping.ParentId,
@@ -3677,6 +3844,8 @@ func (impl *PipelineBuilderImpl) BuildArtifactsForCdStage(pipelineId int, stageT
 					Latest:                        latest,
 					Scanned:                       wfr.CdWorkflow.CiArtifact.Scanned,
 					ScanEnabled:                   wfr.CdWorkflow.CiArtifact.ScanEnabled,
+					ManifestListDigest:            wfr.CdWorkflow.CiArtifact.ManifestListDigest,
+					PlatformDigests:               wfr.CdWorkflow.CiArtifact.PlatformDigests,
 				}
 				if !parent {
 					ciArtifact.Deployed = true
@@ -3718,12 +3887,14 @@ func (impl *PipelineBuilderImpl) BuildArtifactsForCIParent(cdPipelineId int, par
 				impl.logger.Errorw("Error in parsing artifact material info", "err", err, "artifact", artifact)
 			}
 			ciArtifacts = append(ciArtifacts, bean.CiArtifactBean{
-				Id:           artifact.Id,
-				Image:        artifact.Image,
-				ImageDigest:  artifact.ImageDigest,
-				MaterialInfo: mInfo,
-				ScanEnabled:  artifact.ScanEnabled,
-				Scanned:      artifact.Scanned,
+				Id:                 artifact.Id,
+				Image:              artifact.Image,
+				ImageDigest:        artifact.ImageDigest,
+				MaterialInfo:       mInfo,
+				ScanEnabled:        artifact.ScanEnabled,
+				Scanned:            artifact.Scanned,
+				ManifestListDigest: artifact.ManifestListDigest,
+				PlatformDigests:    artifact.PlatformDigests,
 			})
 		}
 	}
@@ -3766,12 +3937,14 @@ func (impl *PipelineBuilderImpl) FetchArtifactForRollback(cdPipelineId, offset,
 		}
 		userEmail := userEmails[cdWfr.TriggeredBy]
 		deployedCiArtifacts = append(deployedCiArtifacts, bean.CiArtifactBean{
-			Id:           ciArtifact.Id,
-			Image:        ciArtifact.Image,
-			MaterialInfo: mInfo,
-			DeployedTime: formatDate(cdWfr.StartedOn, bean.LayoutRFC3339),
-			WfrId:        cdWfr.Id,
-			DeployedBy:   userEmail,
+			Id:                 ciArtifact.Id,
+			Image:              ciArtifact.Image,
+			MaterialInfo:       mInfo,
+			DeployedTime:       formatDate(cdWfr.StartedOn, bean.LayoutRFC3339),
+			WfrId:              cdWfr.Id,
+			DeployedBy:         userEmail,
+			ManifestListDigest: ciArtifact.ManifestListDigest,
+			PlatformDigests:    ciArtifact.PlatformDigests,
 		})
 	}
 
@@ -4042,6 +4215,7 @@ func (impl *PipelineBuilderImpl) GetCdPipelineById(pipelineId int) (cdPipeline *
 		PostStageConfigMapSecretNames: postStageConfigmapSecrets,
 		RunPreStageInEnv:              dbPipeline.RunPreStageInEnv,
 		RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
+		ValidateImagePlatforms:        dbPipeline.ValidateImagePlatforms,
 		CdArgoSetup:                   environment.Cluster.CdArgoSetup,
 		ParentPipelineId:              appWorkflowMapping.ParentId,
 		ParentPipelineType:            appWorkflowMapping.ParentType,
@@ -4058,6 +4232,10 @@ func (impl *PipelineBuilderImpl) GetCdPipelineById(pipelineId int) (cdPipeline *
 	}
 	cdPipeline.PreDeployStage = preDeployStage
 	cdPipeline.PostDeployStage = postDeployStage
+	err = impl.setManifestStorageConfig(cdPipeline, dbPipeline.AppId, dbPipeline.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
 
 	return cdPipeline, err
 }
@@ -4847,6 +5025,7 @@ func (impl *PipelineBuilderImpl) GetCdPipelinesByEnvironment(request appGroup2.A
 			PostStageConfigMapSecretNames: dbPipeline.PostStageConfigMapSecretNames,
 			RunPreStageInEnv:              dbPipeline.RunPreStageInEnv,
 			RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
+			ValidateImagePlatforms:        dbPipeline.ValidateImagePlatforms,
 			DeploymentAppType:             dbPipeline.DeploymentAppType,
 			ParentPipelineType:            pipelineWorkflowMapping[dbPipeline.Id].ParentType,
 			ParentPipelineId:              pipelineWorkflowMapping[dbPipeline.Id].ParentId,
@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"errors"
+	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
@@ -282,6 +283,7 @@ func (impl *PipelineStageServiceImpl) BuildRefPluginStepDataDeepCopy(step *repos
 	refPluginStepDetail := &bean.RefPluginStepDetailDto{
 		PluginId: step.RefPluginId,
 	}
+	refPluginStepDetail.PluginVersion = impl.getRefPluginVersion(step.RefPluginId)
 	inputVariablesDto, outputVariablesDto, conditionsDto, err := impl.BuildVariableAndConditionDataByStepIdDeepCopy(step.Id)
 	if err != nil {
 		impl.logger.Errorw("error in getting variables and conditions data by stepId", "err", err, "stepId", step.Id)
@@ -486,10 +488,44 @@ func (impl *PipelineStageServiceImpl) BuildInlineStepData(step *repository.Pipel
 	return inlineStepDetail, nil
 }
 
+// getRefPluginVersion returns the version a ref plugin step is pinned to, empty if the plugin is not found
+func (impl *PipelineStageServiceImpl) getRefPluginVersion(refPluginId int) string {
+	plugin, err := impl.globalPluginRepository.GetMetaDataByPluginId(refPluginId)
+	if err != nil {
+		impl.logger.Warnw("error in getting ref plugin metadata", "err", err, "refPluginId", refPluginId)
+		return ""
+	}
+	return plugin.PluginVersion
+}
+
+// resolveRefPluginVersion points the step to the requested version of the plugin family of PluginId
+func (impl *PipelineStageServiceImpl) resolveRefPluginVersion(refPluginStepDetail *bean.RefPluginStepDetailDto) error {
+	if refPluginStepDetail == nil || len(refPluginStepDetail.PluginVersion) == 0 {
+		return nil
+	}
+	plugin, err := impl.globalPluginRepository.GetMetaDataByPluginId(refPluginStepDetail.PluginId)
+	if err != nil {
+		impl.logger.Errorw("error in getting ref plugin metadata", "err", err, "refPluginId", refPluginStepDetail.PluginId)
+		return err
+	}
+	if plugin.PluginVersion == refPluginStepDetail.PluginVersion {
+		return nil
+	}
+	versionedPlugin, err := impl.globalPluginRepository.GetPluginByIdentifierAndVersion(plugin.PluginIdentifier, refPluginStepDetail.PluginVersion)
+	if err == pg.ErrNoRows {
+		return fmt.Errorf("version %s of plugin %s not found", refPluginStepDetail.PluginVersion, plugin.PluginIdentifier)
+	} else if err != nil {
+		return err
+	}
+	refPluginStepDetail.PluginId = versionedPlugin.Id
+	return nil
+}
+
 func (impl *PipelineStageServiceImpl) BuildRefPluginStepData(step *repository.PipelineStageStep) (*bean.RefPluginStepDetailDto, error) {
 	refPluginStepDetail := &bean.RefPluginStepDetailDto{
 		PluginId: step.RefPluginId,
 	}
+	refPluginStepDetail.PluginVersion = impl.getRefPluginVersion(step.RefPluginId)
 	inputVariablesDto, outputVariablesDto, conditionsDto, err := impl.BuildVariableAndConditionDataByStepId(step.Id)
 	if err != nil {
 		impl.logger.Errorw("error in getting variables and conditions data by stepId", "err", err, "stepId", step.Id)
@@ -662,6 +698,10 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 			conditionDetails = inlineStepDetail.ConditionDetails
 		} else if step.StepType == repository.PIPELINE_STEP_TYPE_REF_PLUGIN {
 			refPluginStepDetail := step.RefPluginStepDetail
+			err := impl.resolveRefPluginVersion(refPluginStepDetail)
+			if err != nil {
+				return err
+			}
 			refPluginStep := &repository.PipelineStageStep{
 				PipelineStageId:     stageId,
 				Name:                step.Name,
@@ -680,7 +720,7 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 				},
 				TriggerIfParentStageFail: step.TriggerIfParentStageFail,
 			}
-			refPluginStep, err := impl.pipelineStageRepository.CreatePipelineStageStep(refPluginStep, tx)
+			refPluginStep, err = impl.pipelineStageRepository.CreatePipelineStageStep(refPluginStep, tx)
 			if err != nil {
 				impl.logger.Errorw("error in creating ref plugin step", "err", err, "step", refPluginStep)
 				return err
@@ -1073,6 +1113,10 @@ func (impl *PipelineStageServiceImpl) UpdateStageSteps(steps []*bean.PipelineSta
 				}
 			}
 			//updating ref plugin id in step update req
+			err = impl.resolveRefPluginVersion(step.RefPluginStepDetail)
+			if err != nil {
+				return err
+			}
 			stepUpdateReq.RefPluginId = step.RefPluginStepDetail.PluginId
 			inputVariables = step.RefPluginStepDetail.InputVariables
 			outputVariables = step.RefPluginStepDetail.OutputVariables
@@ -0,0 +1,687 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	bean3 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/devtron-labs/devtron/util/k8s"
+	jsonpatch "github.com/evanphx/json-patch"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_PREVIEW_ENVIRONMENT_TTL_IN_HOURS = 72
+	PREVIEW_ENVIRONMENT_NAMESPACE_PREFIX_MAX = 40
+	PREVIEW_ENVIRONMENT_COMMENT_TIMEOUT      = 30 * time.Second
+	// PREVIEW_ENVIRONMENT_NAMESPACE_LABEL marks namespaces created for a preview environment, the value is its id
+	PREVIEW_ENVIRONMENT_NAMESPACE_LABEL = "devtron.ai/preview-environment"
+	// PREVIEW_ENVIRONMENT_SYSTEM_USER_ID previews are created and removed by devtron on git events, not by a user
+	PREVIEW_ENVIRONMENT_SYSTEM_USER_ID int32 = 1
+)
+
+var previewNamespacePrefixRegex = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+type PreviewEnvironmentService interface {
+	SaveConfig(request *bean.PreviewEnvironmentConfigDto) (*bean.PreviewEnvironmentConfigDto, error)
+	GetConfig(pipelineId int) (*bean.PreviewEnvironmentConfigDto, error)
+	// DeleteConfig turns preview mode off, previews already running are removed on pull request close or ttl as before
+	DeleteConfig(pipelineId int, userId int32) error
+	GetPreviewEnvironments(templatePipelineId int) ([]*pipelineConfig.PreviewEnvironment, error)
+	GetPreviewEnvironmentById(id int) (*pipelineConfig.PreviewEnvironment, error)
+	DeletePreviewEnvironment(id int, userId int32) (*pipelineConfig.PreviewEnvironment, error)
+
+	// HandleCiArtifact creates or updates the preview environment of every preview template pipeline of the ci
+	// pipeline when the artifact was built from a pull request
+	HandleCiArtifact(artifactId int) error
+	// HandlePullRequestEvent removes the preview environments of a pull request reported closed or merged by a git host webhook
+	HandlePullRequestEvent(eventType string, payload []byte)
+	DeleteExpiredPreviewEnvironments()
+}
+
+type PreviewEnvironmentServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	previewEnvironmentRepository pipelineConfig.PreviewEnvironmentRepository
+	pipelineRepository           pipelineConfig.PipelineRepository
+	ciArtifactRepository         repository.CiArtifactRepository
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository
+	appWorkflowRepository        appWorkflow.AppWorkflowRepository
+	chartRepository              chartRepoRepository.ChartRepository
+	environmentService           cluster.EnvironmentService
+	clusterService               cluster.ClusterService
+	propertiesConfigService      PropertiesConfigService
+	pipelineBuilder              PipelineBuilder
+	workflowDagExecutor          WorkflowDagExecutor
+	argoUserService              argo.ArgoUserService
+	k8sUtil                      *k8s.K8sUtil
+	httpClient                   *http.Client
+}
+
+func NewPreviewEnvironmentServiceImpl(logger *zap.SugaredLogger, previewEnvironmentRepository pipelineConfig.PreviewEnvironmentRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, ciArtifactRepository repository.CiArtifactRepository,
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, appWorkflowRepository appWorkflow.AppWorkflowRepository,
+	chartRepository chartRepoRepository.ChartRepository, environmentService cluster.EnvironmentService,
+	propertiesConfigService PropertiesConfigService, pipelineBuilder PipelineBuilder, workflowDagExecutor WorkflowDagExecutor,
+	argoUserService argo.ArgoUserService, k8sUtil *k8s.K8sUtil, clusterService cluster.ClusterService) *PreviewEnvironmentServiceImpl {
+	return &PreviewEnvironmentServiceImpl{
+		logger:                       logger,
+		previewEnvironmentRepository: previewEnvironmentRepository,
+		pipelineRepository:           pipelineRepository,
+		ciArtifactRepository:         ciArtifactRepository,
+		ciPipelineMaterialRepository: ciPipelineMaterialRepository,
+		appWorkflowRepository:        appWorkflowRepository,
+		chartRepository:              chartRepository,
+		environmentService:           environmentService,
+		clusterService:               clusterService,
+		propertiesConfigService:      propertiesConfigService,
+		pipelineBuilder:              pipelineBuilder,
+		workflowDagExecutor:          workflowDagExecutor,
+		argoUserService:              argoUserService,
+		k8sUtil:                      k8sUtil,
+		httpClient:                   &http.Client{Timeout: PREVIEW_ENVIRONMENT_COMMENT_TIMEOUT},
+	}
+}
+
+func (impl *PreviewEnvironmentServiceImpl) SaveConfig(request *bean.PreviewEnvironmentConfigDto) (*bean.PreviewEnvironmentConfigDto, error) {
+	templatePipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	if _, err = impl.getWebhookGitProvider(templatePipeline.CiPipelineId); err != nil {
+		return nil, newPreviewBadRequestError(err.Error())
+	}
+	if len(request.NamespacePrefix) == 0 {
+		request.NamespacePrefix = GetDefaultPreviewNamespacePrefix(templatePipeline.App.AppName)
+	}
+	if !previewNamespacePrefixRegex.MatchString(request.NamespacePrefix) {
+		return nil, newPreviewBadRequestError("namespace prefix must consist of lower case alphanumeric characters or '-'")
+	}
+	if string(request.ValuesOverride) == "null" {
+		request.ValuesOverride = nil
+	}
+	if len(request.ValuesOverride) > 0 {
+		var values map[string]interface{}
+		if err = json.Unmarshal(request.ValuesOverride, &values); err != nil {
+			return nil, newPreviewBadRequestError("values override must be a json object")
+		}
+	}
+	if request.TtlInHours == 0 {
+		request.TtlInHours = DEFAULT_PREVIEW_ENVIRONMENT_TTL_IN_HOURS
+	}
+	existing, err := impl.previewEnvironmentRepository.FindActiveConfigByPipelineId(request.PipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environment config", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	config := &pipelineConfig.PreviewEnvironmentConfig{
+		PipelineId: request.PipelineId,
+		Active:     true,
+		AuditLog:   sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	if existing != nil && existing.Id > 0 {
+		config = existing
+		config.UpdatedOn = now
+		config.UpdatedBy = request.UserId
+	}
+	config.ClusterId = request.ClusterId
+	config.NamespacePrefix = request.NamespacePrefix
+	config.ValuesOverride = string(request.ValuesOverride)
+	config.UrlTemplate = request.UrlTemplate
+	config.TtlInHours = request.TtlInHours
+	config.CommentOnPullRequest = request.CommentOnPullRequest
+	if config.Id > 0 {
+		err = impl.previewEnvironmentRepository.UpdateConfig(config)
+	} else {
+		err = impl.previewEnvironmentRepository.SaveConfig(config)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving preview environment config", "err", err, "config", config)
+		return nil, err
+	}
+	return adaptPreviewEnvironmentConfig(config), nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) GetConfig(pipelineId int) (*bean.PreviewEnvironmentConfigDto, error) {
+	config, err := impl.previewEnvironmentRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, nil
+		}
+		impl.logger.Errorw("error in fetching preview environment config", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	return adaptPreviewEnvironmentConfig(config), nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) DeleteConfig(pipelineId int, userId int32) error {
+	config, err := impl.previewEnvironmentRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		impl.logger.Errorw("error in fetching preview environment config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	config.Active = false
+	config.UpdatedOn = time.Now()
+	config.UpdatedBy = userId
+	return impl.previewEnvironmentRepository.UpdateConfig(config)
+}
+
+func (impl *PreviewEnvironmentServiceImpl) GetPreviewEnvironments(templatePipelineId int) ([]*pipelineConfig.PreviewEnvironment, error) {
+	previewEnvironments, err := impl.previewEnvironmentRepository.FindLiveByTemplatePipelineId(templatePipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environments", "err", err, "templatePipelineId", templatePipelineId)
+		return nil, err
+	}
+	return previewEnvironments, nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) GetPreviewEnvironmentById(id int) (*pipelineConfig.PreviewEnvironment, error) {
+	previewEnvironment, err := impl.previewEnvironmentRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment", "err", err, "id", id)
+		return nil, err
+	}
+	return previewEnvironment, nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) DeletePreviewEnvironment(id int, userId int32) (*pipelineConfig.PreviewEnvironment, error) {
+	previewEnvironment, err := impl.previewEnvironmentRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment", "err", err, "id", id)
+		return nil, err
+	}
+	if previewEnvironment.Status == pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_DELETED {
+		return previewEnvironment, nil
+	}
+	err = impl.deletePreviewEnvironment(previewEnvironment, userId, "deleted by user")
+	return previewEnvironment, err
+}
+
+func (impl *PreviewEnvironmentServiceImpl) HandleCiArtifact(artifactId int) error {
+	artifact, err := impl.ciArtifactRepository.Get(artifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "err", err, "artifactId", artifactId)
+		return err
+	}
+	pullRequest, err := GetPullRequest(artifact.MaterialInfo)
+	if err != nil || pullRequest == nil {
+		return err
+	}
+	pipelines, err := impl.pipelineRepository.FindByParentCiPipelineId(artifact.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipelines", "err", err, "ciPipelineId", artifact.PipelineId)
+		return err
+	}
+	pipelineById := make(map[int]*pipelineConfig.Pipeline)
+	var pipelineIds []int
+	for _, cdPipeline := range pipelines {
+		pipelineById[cdPipeline.Id] = cdPipeline
+		pipelineIds = append(pipelineIds, cdPipeline.Id)
+	}
+	configs, err := impl.previewEnvironmentRepository.FindActiveConfigsByPipelineIds(pipelineIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment configs", "err", err, "pipelineIds", pipelineIds)
+		return err
+	}
+	for _, config := range configs {
+		err = impl.deployPreviewEnvironment(config, pipelineById[config.PipelineId], artifact, pullRequest)
+		if err != nil {
+			impl.logger.Errorw("error in deploying preview environment", "err", err, "templatePipelineId", config.PipelineId, "pullRequest", pullRequest.Url)
+		}
+	}
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) HandlePullRequestEvent(eventType string, payload []byte) {
+	pullRequestUrl := GetClosedPullRequestUrl(eventType, payload)
+	if len(pullRequestUrl) == 0 {
+		return
+	}
+	previewEnvironments, err := impl.previewEnvironmentRepository.FindLiveByPullRequestUrl(pullRequestUrl)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environments of pull request", "err", err, "pullRequest", pullRequestUrl)
+		return
+	}
+	for _, previewEnvironment := range previewEnvironments {
+		err = impl.deletePreviewEnvironment(previewEnvironment, PREVIEW_ENVIRONMENT_SYSTEM_USER_ID, "pull request closed")
+		if err != nil {
+			impl.logger.Errorw("error in deleting preview environment", "err", err, "id", previewEnvironment.Id)
+		}
+	}
+}
+
+func (impl *PreviewEnvironmentServiceImpl) DeleteExpiredPreviewEnvironments() {
+	previewEnvironments, err := impl.previewEnvironmentRepository.FindExpired(time.Now())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching expired preview environments", "err", err)
+		return
+	}
+	for _, previewEnvironment := range previewEnvironments {
+		err = impl.deletePreviewEnvironment(previewEnvironment, PREVIEW_ENVIRONMENT_SYSTEM_USER_ID, "ttl expired")
+		if err != nil {
+			impl.logger.Errorw("error in deleting expired preview environment", "err", err, "id", previewEnvironment.Id)
+		}
+	}
+}
+
+// deployPreviewEnvironment creates the environment and the cd pipeline of the preview on the first build of the pull
+// request and deploys the artifact. Every step records its result so a failed preview is completed by the next build.
+func (impl *PreviewEnvironmentServiceImpl) deployPreviewEnvironment(config *pipelineConfig.PreviewEnvironmentConfig, templatePipeline *pipelineConfig.Pipeline,
+	artifact *repository.CiArtifact, pullRequest *PullRequest) error {
+	previewEnvironment, err := impl.previewEnvironmentRepository.FindLiveByTemplatePipelineIdAndPullRequestUrl(templatePipeline.Id, pullRequest.Url)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching preview environment", "err", err, "templatePipelineId", templatePipeline.Id)
+		return err
+	}
+	now := time.Now()
+	if previewEnvironment == nil || previewEnvironment.Id == 0 {
+		previewEnvironment = &pipelineConfig.PreviewEnvironment{
+			ConfigId:           config.Id,
+			TemplatePipelineId: templatePipeline.Id,
+			PullRequestUrl:     pullRequest.Url,
+			PullRequestNumber:  pullRequest.Number,
+			SourceBranch:       pullRequest.SourceBranch,
+			Namespace:          GetPreviewNamespace(config.NamespacePrefix, templatePipeline.AppId, pullRequest.Number),
+			Status:             pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_CREATING,
+			ExpiresOn:          now.Add(time.Duration(config.TtlInHours) * time.Hour),
+			AuditLog:           sql.AuditLog{CreatedOn: now, CreatedBy: PREVIEW_ENVIRONMENT_SYSTEM_USER_ID, UpdatedOn: now, UpdatedBy: PREVIEW_ENVIRONMENT_SYSTEM_USER_ID},
+		}
+		previewEnvironment.Url = RenderPreviewTemplate(config.UrlTemplate, previewEnvironment)
+		err = impl.previewEnvironmentRepository.Save(previewEnvironment)
+		if err != nil {
+			impl.logger.Errorw("error in saving preview environment", "err", err, "previewEnvironment", previewEnvironment)
+			return err
+		}
+	} else if previewEnvironment.Status == pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_DELETE_FAILED {
+		return fmt.Errorf("preview environment %s is being deleted", previewEnvironment.Namespace)
+	}
+	previewEnvironment.PullRequestTitle = pullRequest.Title
+	previewEnvironment.CiArtifactId = artifact.Id
+	previewEnvironment.ExpiresOn = now.Add(time.Duration(config.TtlInHours) * time.Hour)
+	previewEnvironment.Message = ""
+
+	ctx, err := impl.buildACDContext()
+	// the namespace is created first, creating the environment would otherwise create it unlabelled
+	if err == nil {
+		err = impl.createNamespace(config, previewEnvironment)
+	}
+	if err == nil {
+		err = impl.createEnvironment(config, previewEnvironment)
+	}
+	if err == nil {
+		err = impl.createPipeline(ctx, config, templatePipeline, previewEnvironment)
+	}
+	if err == nil {
+		_, err = impl.workflowDagExecutor.ManualCdTrigger(&bean3.ValuesOverrideRequest{
+			PipelineId:     previewEnvironment.PipelineId,
+			AppId:          templatePipeline.AppId,
+			CiArtifactId:   artifact.Id,
+			CdWorkflowType: bean3.CD_WORKFLOW_TYPE_DEPLOY,
+			UserId:         PREVIEW_ENVIRONMENT_SYSTEM_USER_ID,
+		}, ctx)
+	}
+	if err != nil {
+		previewEnvironment.Status = pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_FAILED
+		previewEnvironment.Message = err.Error()
+	} else {
+		previewEnvironment.Status = pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_DEPLOYED
+		if config.CommentOnPullRequest && !previewEnvironment.Commented {
+			if commentErr := impl.commentOnPullRequest(templatePipeline.CiPipelineId, previewEnvironment); commentErr != nil {
+				impl.logger.Errorw("error in commenting preview environment on pull request", "err", commentErr, "pullRequest", previewEnvironment.PullRequestUrl)
+			} else {
+				previewEnvironment.Commented = true
+			}
+		}
+	}
+	previewEnvironment.UpdatedOn = time.Now()
+	previewEnvironment.UpdatedBy = PREVIEW_ENVIRONMENT_SYSTEM_USER_ID
+	if updateErr := impl.previewEnvironmentRepository.Update(previewEnvironment); updateErr != nil {
+		impl.logger.Errorw("error in updating preview environment", "err", updateErr, "previewEnvironment", previewEnvironment)
+		if err == nil {
+			err = updateErr
+		}
+	}
+	return err
+}
+
+func (impl *PreviewEnvironmentServiceImpl) createEnvironment(config *pipelineConfig.PreviewEnvironmentConfig, previewEnvironment *pipelineConfig.PreviewEnvironment) error {
+	if previewEnvironment.EnvironmentId > 0 {
+		return nil
+	}
+	environment, err := impl.environmentService.Create(&cluster.EnvironmentBean{
+		Environment: previewEnvironment.Namespace,
+		ClusterId:   config.ClusterId,
+		Namespace:   previewEnvironment.Namespace,
+		Active:      true,
+		Description: fmt.Sprintf("preview of pull request #%d", previewEnvironment.PullRequestNumber),
+	}, PREVIEW_ENVIRONMENT_SYSTEM_USER_ID)
+	if err != nil {
+		impl.logger.Errorw("error in creating preview environment", "err", err, "namespace", previewEnvironment.Namespace)
+		return err
+	}
+	previewEnvironment.EnvironmentId = environment.Id
+	return impl.previewEnvironmentRepository.Update(previewEnvironment)
+}
+
+// createNamespace creates the namespace of the preview labelled as owned by it. A namespace which exists already
+// without the label is used as is and, not being owned by the preview, left in place on teardown.
+func (impl *PreviewEnvironmentServiceImpl) createNamespace(config *pipelineConfig.PreviewEnvironmentConfig, previewEnvironment *pipelineConfig.PreviewEnvironment) error {
+	if previewEnvironment.NamespaceCreated || previewEnvironment.EnvironmentId > 0 {
+		return nil
+	}
+	clusterBean, err := impl.clusterService.FindById(config.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster of preview environment", "err", err, "clusterId", config.ClusterId)
+		return err
+	}
+	clusterConfig, err := clusterBean.GetClusterConfig()
+	if err != nil {
+		return err
+	}
+	labels := map[string]string{PREVIEW_ENVIRONMENT_NAMESPACE_LABEL: strconv.Itoa(previewEnvironment.Id)}
+	owned, err := impl.k8sUtil.CreateNsWithLabelsIfNotExists(previewEnvironment.Namespace, labels, clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in creating preview namespace", "err", err, "namespace", previewEnvironment.Namespace)
+		return err
+	}
+	if !owned {
+		impl.logger.Warnw("preview namespace exists already, it will not be deleted with the preview", "namespace", previewEnvironment.Namespace)
+		return nil
+	}
+	previewEnvironment.NamespaceCreated = true
+	return impl.previewEnvironmentRepository.Update(previewEnvironment)
+}
+
+// createPipeline clones the template pipeline onto the preview environment, the clone is manual so that builds of
+// other pull requests are never deployed to it
+func (impl *PreviewEnvironmentServiceImpl) createPipeline(ctx context.Context, config *pipelineConfig.PreviewEnvironmentConfig,
+	templatePipeline *pipelineConfig.Pipeline, previewEnvironment *pipelineConfig.PreviewEnvironment) error {
+	if previewEnvironment.PipelineId > 0 {
+		return nil
+	}
+	template, err := impl.pipelineBuilder.GetCdPipelineById(templatePipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching template cd pipeline", "err", err, "pipelineId", templatePipeline.Id)
+		return err
+	}
+	workflowMapping, err := impl.appWorkflowRepository.FindWFCDMappingByCDPipelineId(templatePipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching workflow of template cd pipeline", "err", err, "pipelineId", templatePipeline.Id)
+		return err
+	}
+	err = impl.createEnvironmentProperties(config, templatePipeline, previewEnvironment)
+	if err != nil {
+		return err
+	}
+	previewPipeline := &bean2.CDPipelineConfigObject{
+		EnvironmentId:      previewEnvironment.EnvironmentId,
+		CiPipelineId:       template.CiPipelineId,
+		TriggerType:        pipelineConfig.TRIGGER_TYPE_MANUAL,
+		Name:               previewEnvironment.Namespace,
+		Strategies:         template.Strategies,
+		Namespace:          previewEnvironment.Namespace,
+		AppWorkflowId:      workflowMapping.AppWorkflowId,
+		DeploymentTemplate: template.DeploymentTemplate,
+		DeploymentAppType:  template.DeploymentAppType,
+		AppId:              templatePipeline.AppId,
+	}
+	res, err := impl.pipelineBuilder.CreateCdPipelines(&bean2.CdPipelines{
+		Pipelines: []*bean2.CDPipelineConfigObject{previewPipeline},
+		AppId:     templatePipeline.AppId,
+		UserId:    PREVIEW_ENVIRONMENT_SYSTEM_USER_ID,
+	}, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in creating preview cd pipeline", "err", err, "namespace", previewEnvironment.Namespace)
+		return err
+	}
+	previewEnvironment.PipelineId = res.Pipelines[0].Id
+	return impl.previewEnvironmentRepository.Update(previewEnvironment)
+}
+
+// createEnvironmentProperties overrides the deployment template of the preview environment with the values of the
+// template pipeline patched by the values override of the config
+func (impl *PreviewEnvironmentServiceImpl) createEnvironmentProperties(config *pipelineConfig.PreviewEnvironmentConfig,
+	templatePipeline *pipelineConfig.Pipeline, previewEnvironment *pipelineConfig.PreviewEnvironment) error {
+	var values string
+	var chartRefId int
+	templateProperties, err := impl.propertiesConfigService.GetLatestEnvironmentProperties(templatePipeline.AppId, templatePipeline.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching deployment template of template pipeline", "err", err, "pipelineId", templatePipeline.Id)
+		return err
+	}
+	if templateProperties != nil && templateProperties.IsOverride {
+		values = string(templateProperties.EnvOverrideValues)
+		chartRefId = templateProperties.ChartRefId
+	} else {
+		chart, err := impl.chartRepository.FindLatestChartForAppByAppId(templatePipeline.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching deployment template of app", "err", err, "appId", templatePipeline.AppId)
+			return err
+		}
+		values = chart.GlobalOverride
+		chartRefId = chart.ChartRefId
+	}
+	values, err = GetPreviewValues(values, config.ValuesOverride, previewEnvironment)
+	if err != nil {
+		return err
+	}
+	_, err = impl.propertiesConfigService.CreateEnvironmentProperties(templatePipeline.AppId, &EnvironmentProperties{
+		EnvOverrideValues: json.RawMessage(values),
+		Status:            models.CHARTSTATUS_SUCCESS,
+		ManualReviewed:    true,
+		Active:            true,
+		Namespace:         previewEnvironment.Namespace,
+		EnvironmentId:     previewEnvironment.EnvironmentId,
+		ChartRefId:        chartRefId,
+		IsOverride:        true,
+		UserId:            PREVIEW_ENVIRONMENT_SYSTEM_USER_ID,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating deployment template of preview environment", "err", err, "namespace", previewEnvironment.Namespace)
+	}
+	return err
+}
+
+func (impl *PreviewEnvironmentServiceImpl) deletePreviewEnvironment(previewEnvironment *pipelineConfig.PreviewEnvironment, userId int32, reason string) error {
+	err := impl.teardown(previewEnvironment, userId)
+	if err != nil {
+		previewEnvironment.Status = pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_DELETE_FAILED
+		previewEnvironment.Message = err.Error()
+	} else {
+		previewEnvironment.Status = pipelineConfig.PREVIEW_ENVIRONMENT_STATUS_DELETED
+		previewEnvironment.Message = reason
+	}
+	previewEnvironment.UpdatedOn = time.Now()
+	previewEnvironment.UpdatedBy = userId
+	if updateErr := impl.previewEnvironmentRepository.Update(previewEnvironment); updateErr != nil {
+		impl.logger.Errorw("error in updating preview environment", "err", updateErr, "previewEnvironment", previewEnvironment)
+		if err == nil {
+			err = updateErr
+		}
+	}
+	return err
+}
+
+// teardown removes the cd pipeline with its argocd app or helm release, the namespace and the environment, parts
+// already removed by an earlier attempt are skipped
+func (impl *PreviewEnvironmentServiceImpl) teardown(previewEnvironment *pipelineConfig.PreviewEnvironment, userId int32) error {
+	if previewEnvironment.PipelineId > 0 {
+		previewPipeline, err := impl.pipelineRepository.FindById(previewEnvironment.PipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching preview cd pipeline", "err", err, "pipelineId", previewEnvironment.PipelineId)
+			return err
+		}
+		if err == nil && previewPipeline.Id > 0 {
+			ctx, err := impl.buildACDContext()
+			if err != nil {
+				return err
+			}
+			_, err = impl.pipelineBuilder.DeleteCdPipeline(previewPipeline, ctx, bean2.FORCE_DELETE, true, userId)
+			if err != nil {
+				impl.logger.Errorw("error in deleting preview cd pipeline", "err", err, "pipelineId", previewEnvironment.PipelineId)
+				return err
+			}
+		}
+	}
+	if previewEnvironment.EnvironmentId > 0 {
+		clusterBean, err := impl.environmentService.FindClusterByEnvId(previewEnvironment.EnvironmentId)
+		if err != nil {
+			if util.IsErrNoRows(err) {
+				return nil
+			}
+			impl.logger.Errorw("error in fetching cluster of preview environment", "err", err, "envId", previewEnvironment.EnvironmentId)
+			return err
+		}
+		clusterConfig, err := clusterBean.GetClusterConfig()
+		if err != nil {
+			return err
+		}
+		// namespaces which existed before the preview are not ours to delete
+		if previewEnvironment.NamespaceCreated {
+			err = impl.k8sUtil.DeleteNsIfExists(previewEnvironment.Namespace, clusterConfig)
+			if err != nil {
+				impl.logger.Errorw("error in deleting preview namespace", "err", err, "namespace", previewEnvironment.Namespace)
+				return err
+			}
+		}
+		err = impl.environmentService.Delete(&cluster.EnvironmentBean{Id: previewEnvironment.EnvironmentId}, userId)
+		if err != nil {
+			impl.logger.Errorw("error in deleting preview environment", "err", err, "envId", previewEnvironment.EnvironmentId)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *PreviewEnvironmentServiceImpl) commentOnPullRequest(ciPipelineId int, previewEnvironment *pipelineConfig.PreviewEnvironment) error {
+	ref, err := ParsePullRequestUrl(previewEnvironment.PullRequestUrl)
+	if err != nil {
+		return err
+	}
+	gitProvider, err := impl.getWebhookGitProvider(ciPipelineId)
+	if err != nil {
+		return err
+	}
+	req, err := NewPullRequestCommentRequest(ref, gitProvider, GetPreviewComment(previewEnvironment))
+	if err != nil {
+		return err
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("pull request comment failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// getWebhookGitProvider returns the git provider of the webhook material of the ci pipeline, pull request builds come
+// only from webhook materials
+func (impl *PreviewEnvironmentServiceImpl) getWebhookGitProvider(ciPipelineId int) (*repository.GitProvider, error) {
+	materials, err := impl.ciPipelineMaterialRepository.GetByPipelineId(ciPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci pipeline materials", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	for _, material := range materials {
+		if material.Type == pipelineConfig.SOURCE_TYPE_WEBHOOK && material.GitMaterial != nil && material.GitMaterial.GitProvider != nil {
+			return material.GitMaterial.GitProvider, nil
+		}
+	}
+	return nil, fmt.Errorf("ci pipeline of the cd pipeline has no pull request webhook material")
+}
+
+func (impl *PreviewEnvironmentServiceImpl) buildACDContext() (context.Context, error) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	return context.WithValue(context.Background(), "token", acdToken), nil
+}
+
+func GetDefaultPreviewNamespacePrefix(appName string) string {
+	prefix := strings.ToLower(appName)
+	if len(prefix) > PREVIEW_ENVIRONMENT_NAMESPACE_PREFIX_MAX {
+		prefix = prefix[:PREVIEW_ENVIRONMENT_NAMESPACE_PREFIX_MAX]
+	}
+	return strings.Trim(prefix, "-")
+}
+
+// GetPreviewNamespace the namespace is also used as the name of the preview environment and of its cd pipeline, the app
+// id keeps previews of apps sharing a prefix apart
+func GetPreviewNamespace(prefix string, appId int, pullRequestNumber int) string {
+	return fmt.Sprintf("%s-%d-pr-%d", prefix, appId, pullRequestNumber)
+}
+
+func RenderPreviewTemplate(template string, previewEnvironment *pipelineConfig.PreviewEnvironment) string {
+	return strings.NewReplacer(
+		bean.PREVIEW_PLACEHOLDER_NAMESPACE, previewEnvironment.Namespace,
+		bean.PREVIEW_PLACEHOLDER_PR_NUMBER, fmt.Sprintf("%d", previewEnvironment.PullRequestNumber),
+	).Replace(template)
+}
+
+// GetPreviewValues applies the values override of the config as a json merge patch on the template values
+func GetPreviewValues(templateValues string, valuesOverride string, previewEnvironment *pipelineConfig.PreviewEnvironment) (string, error) {
+	if len(valuesOverride) == 0 {
+		return templateValues, nil
+	}
+	values, err := jsonpatch.MergePatch([]byte(templateValues), []byte(RenderPreviewTemplate(valuesOverride, previewEnvironment)))
+	if err != nil {
+		return "", err
+	}
+	return string(values), nil
+}
+
+func GetPreviewComment(previewEnvironment *pipelineConfig.PreviewEnvironment) string {
+	location := fmt.Sprintf("namespace `%s`", previewEnvironment.Namespace)
+	if len(previewEnvironment.Url) > 0 {
+		location = previewEnvironment.Url
+	}
+	return fmt.Sprintf("Preview environment of this pull request is deployed at %s.\n\nIt is removed when the pull request is closed or after %s.",
+		location, previewEnvironment.ExpiresOn.UTC().Format(time.RFC1123))
+}
+
+func adaptPreviewEnvironmentConfig(config *pipelineConfig.PreviewEnvironmentConfig) *bean.PreviewEnvironmentConfigDto {
+	dto := &bean.PreviewEnvironmentConfigDto{
+		Id:                   config.Id,
+		PipelineId:           config.PipelineId,
+		ClusterId:            config.ClusterId,
+		NamespacePrefix:      config.NamespacePrefix,
+		UrlTemplate:          config.UrlTemplate,
+		TtlInHours:           config.TtlInHours,
+		CommentOnPullRequest: config.CommentOnPullRequest,
+	}
+	if len(config.ValuesOverride) > 0 {
+		dto.ValuesOverride = json.RawMessage(config.ValuesOverride)
+	}
+	return dto
+}
+
+func newPreviewBadRequestError(message string) error {
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: message, UserMessage: message}
+}
@@ -0,0 +1,30 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetPreviewValues(t *testing.T) {
+	previewEnvironment := &pipelineConfig.PreviewEnvironment{Namespace: GetPreviewNamespace("payments", 7, 12), PullRequestNumber: 12}
+	assert.Equal(t, "payments-7-pr-12", previewEnvironment.Namespace)
+
+	values, err := GetPreviewValues(`{"replicaCount":3,"ingress":{"enabled":false,"className":"nginx"}}`,
+		`{"replicaCount":1,"ingress":{"enabled":true,"hosts":[{"host":"{{namespace}}.preview.example.com"}]}}`, previewEnvironment)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"replicaCount":1,"ingress":{"enabled":true,"className":"nginx","hosts":[{"host":"payments-7-pr-12.preview.example.com"}]}}`, values)
+
+	values, err = GetPreviewValues(`{"replicaCount":3}`, "", previewEnvironment)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"replicaCount":3}`, values)
+
+	assert.Equal(t, "https://payments-7-pr-12.preview.example.com/12", RenderPreviewTemplate("https://{{namespace}}.preview.example.com/{{prNumber}}", previewEnvironment))
+}
+
+func TestGetDefaultPreviewNamespacePrefix(t *testing.T) {
+	assert.Equal(t, "payments", GetDefaultPreviewNamespacePrefix("payments"))
+	prefix := GetDefaultPreviewNamespacePrefix("a-very-long-application-name-which-goes-past-the-limit")
+	assert.Equal(t, "a-very-long-application-name-which-goes", prefix)
+	assert.True(t, previewNamespacePrefixRegex.MatchString(prefix))
+}
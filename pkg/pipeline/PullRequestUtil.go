@@ -0,0 +1,206 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type PullRequestProvider string
+
+const (
+	PULL_REQUEST_PROVIDER_GITHUB    PullRequestProvider = "GITHUB"
+	PULL_REQUEST_PROVIDER_GITLAB    PullRequestProvider = "GITLAB"
+	PULL_REQUEST_PROVIDER_BITBUCKET PullRequestProvider = "BITBUCKET"
+)
+
+// PullRequest is the pull request an artifact was built from
+type PullRequest struct {
+	Url          string
+	Number       int
+	Title        string
+	SourceBranch string
+}
+
+// PullRequestRef identifies a pull request on its git host, parsed from the web url of the pull request
+type PullRequestRef struct {
+	Provider PullRequestProvider
+	Scheme   string
+	Host     string
+	RepoPath string //owner/repo on github, the full project path on gitlab, workspace/repo on bitbucket
+	Number   int
+}
+
+// ParsePullRequestUrl supports the web urls of github (/pull/<n>), gitlab (/-/merge_requests/<n>) and bitbucket cloud (/pull-requests/<n>)
+func ParsePullRequestUrl(pullRequestUrl string) (*PullRequestRef, error) {
+	u, err := url.Parse(strings.TrimSpace(pullRequestUrl))
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid pull request url %s", pullRequestUrl)
+	}
+	path := strings.Trim(u.Path, "/")
+	markers := []struct {
+		marker   string
+		provider PullRequestProvider
+	}{
+		{marker: "/-/merge_requests/", provider: PULL_REQUEST_PROVIDER_GITLAB},
+		{marker: "/merge_requests/", provider: PULL_REQUEST_PROVIDER_GITLAB},
+		{marker: "/pull-requests/", provider: PULL_REQUEST_PROVIDER_BITBUCKET},
+		{marker: "/pull/", provider: PULL_REQUEST_PROVIDER_GITHUB},
+	}
+	for _, m := range markers {
+		index := strings.Index(path, m.marker)
+		if index <= 0 {
+			continue
+		}
+		number, err := strconv.Atoi(strings.Split(path[index+len(m.marker):], "/")[0])
+		if err != nil || number <= 0 {
+			return nil, fmt.Errorf("invalid pull request number in url %s", pullRequestUrl)
+		}
+		return &PullRequestRef{
+			Provider: m.provider,
+			Scheme:   u.Scheme,
+			Host:     u.Host,
+			RepoPath: path[:index],
+			Number:   number,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported pull request url %s", pullRequestUrl)
+}
+
+// GetPullRequest returns the pull request of a webhook ci material the artifact was built from, nil when the
+// artifact was not built from a pull request
+func GetPullRequest(materialInfo string) (*PullRequest, error) {
+	if len(materialInfo) == 0 {
+		return nil, nil
+	}
+	var ciMaterials []repository.CiMaterialInfo
+	err := json.Unmarshal([]byte(materialInfo), &ciMaterials)
+	if err != nil {
+		return nil, err
+	}
+	for _, ciMaterial := range ciMaterials {
+		for _, modification := range ciMaterial.Modifications {
+			data := modification.WebhookData.Data
+			// tag events carry no target branch
+			if modification.WebhookData.Id == 0 || len(data[bean.WEBHOOK_SELECTOR_TARGET_BRANCH_NAME_NAME]) == 0 {
+				continue
+			}
+			pullRequestUrl := strings.TrimSuffix(data[bean.WEBHOOK_SELECTOR_GIT_URL_NAME], "/")
+			ref, err := ParsePullRequestUrl(pullRequestUrl)
+			if err != nil {
+				continue
+			}
+			return &PullRequest{
+				Url:          pullRequestUrl,
+				Number:       ref.Number,
+				Title:        data[bean.WEBHOOK_SELECTOR_HEADER_NAME],
+				SourceBranch: data[bean.WEBHOOK_SELECTOR_SOURCE_BRANCH_NAME_NAME],
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+type pullRequestEventPayload struct {
+	// github
+	Action      string `json:"action"`
+	PullRequest *struct {
+		HtmlUrl string `json:"html_url"`
+	} `json:"pull_request"`
+	// gitlab
+	ObjectKind       string `json:"object_kind"`
+	ObjectAttributes *struct {
+		Action string `json:"action"`
+		Url    string `json:"url"`
+	} `json:"object_attributes"`
+	// bitbucket
+	Pullrequest *struct {
+		State string `json:"state"`
+		Links struct {
+			Html struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	} `json:"pullrequest"`
+}
+
+// GetClosedPullRequestUrl returns the url of the pull request a git host webhook reports as merged or closed, empty
+// for every other event
+func GetClosedPullRequestUrl(eventType string, payload []byte) string {
+	event := &pullRequestEventPayload{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return ""
+	}
+	var closedUrl string
+	switch {
+	case event.PullRequest != nil && event.Action == "closed":
+		closedUrl = event.PullRequest.HtmlUrl
+	case event.ObjectKind == "merge_request" && event.ObjectAttributes != nil &&
+		(event.ObjectAttributes.Action == "close" || event.ObjectAttributes.Action == "merge"):
+		closedUrl = event.ObjectAttributes.Url
+	case event.Pullrequest != nil && (eventType == "pullrequest:fulfilled" || eventType == "pullrequest:rejected" ||
+		event.Pullrequest.State == "MERGED" || event.Pullrequest.State == "DECLINED"):
+		closedUrl = event.Pullrequest.Links.Html.Href
+	}
+	return strings.TrimSuffix(closedUrl, "/")
+}
+
+// NewPullRequestCommentRequest builds the api request posting a comment on the pull request, authenticated with the
+// credentials of the git provider of the webhook material
+func NewPullRequestCommentRequest(ref *PullRequestRef, gitProvider *repository.GitProvider, comment string) (*http.Request, error) {
+	var apiUrl string
+	var body interface{}
+	switch ref.Provider {
+	case PULL_REQUEST_PROVIDER_GITHUB:
+		apiBase := fmt.Sprintf("%s://%s/api/v3", ref.Scheme, ref.Host)
+		if ref.Host == "github.com" {
+			apiBase = "https://api.github.com"
+		}
+		apiUrl = fmt.Sprintf("%s/repos/%s/issues/%d/comments", apiBase, ref.RepoPath, ref.Number)
+		body = map[string]string{"body": comment}
+	case PULL_REQUEST_PROVIDER_GITLAB:
+		apiUrl = fmt.Sprintf("%s://%s/api/v4/projects/%s/merge_requests/%d/notes", ref.Scheme, ref.Host, url.PathEscape(ref.RepoPath), ref.Number)
+		body = map[string]string{"body": comment}
+	case PULL_REQUEST_PROVIDER_BITBUCKET:
+		apiUrl = fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/pullrequests/%d/comments", ref.RepoPath, ref.Number)
+		body = map[string]interface{}{"content": map[string]string{"raw": comment}}
+	default:
+		return nil, fmt.Errorf("unsupported pull request provider %s", ref.Provider)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, apiUrl, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch gitProvider.AuthMode {
+	case repository.AUTH_MODE_ACCESS_TOKEN:
+		if ref.Provider == PULL_REQUEST_PROVIDER_GITLAB {
+			req.Header.Set("PRIVATE-TOKEN", gitProvider.AccessToken)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+gitProvider.AccessToken)
+		}
+	case repository.AUTH_MODE_USERNAME_PASSWORD:
+		// gitlab api does not take basic auth, the password of a gitlab provider is a personal access token
+		if ref.Provider == PULL_REQUEST_PROVIDER_GITLAB {
+			req.Header.Set("PRIVATE-TOKEN", gitProvider.Password)
+		} else {
+			req.SetBasicAuth(gitProvider.UserName, gitProvider.Password)
+		}
+	default:
+		return nil, fmt.Errorf("git provider %s has no api credentials, auth mode %s", gitProvider.Name, gitProvider.AuthMode)
+	}
+	return req, nil
+}
@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestParsePullRequestUrl(t *testing.T) {
+	ref, err := ParsePullRequestUrl("https://github.com/devtron-labs/devtron/pull/42")
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_REQUEST_PROVIDER_GITHUB, ref.Provider)
+	assert.Equal(t, "devtron-labs/devtron", ref.RepoPath)
+	assert.Equal(t, 42, ref.Number)
+
+	ref, err = ParsePullRequestUrl("https://gitlab.example.com/group/sub/project/-/merge_requests/7")
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_REQUEST_PROVIDER_GITLAB, ref.Provider)
+	assert.Equal(t, "group/sub/project", ref.RepoPath)
+	assert.Equal(t, 7, ref.Number)
+
+	ref, err = ParsePullRequestUrl("https://bitbucket.org/workspace/repo/pull-requests/3")
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_REQUEST_PROVIDER_BITBUCKET, ref.Provider)
+	assert.Equal(t, "workspace/repo", ref.RepoPath)
+
+	_, err = ParsePullRequestUrl("https://github.com/devtron-labs/devtron/tree/main")
+	assert.NotNil(t, err)
+	_, err = ParsePullRequestUrl("https://github.com/devtron-labs/devtron/pull/abc")
+	assert.NotNil(t, err)
+}
+
+func TestGetPullRequest(t *testing.T) {
+	materialInfo := func(data map[string]string) string {
+		info, _ := json.Marshal([]repository.CiMaterialInfo{{
+			Material:      repository.Material{Type: "git", GitConfiguration: repository.GitConfiguration{URL: "https://github.com/org/repo.git"}},
+			Modifications: []repository.Modification{{WebhookData: repository.WebhookData{Id: 1, EventActionType: "merged", Data: data}}},
+		}})
+		return string(info)
+	}
+	pullRequest, err := GetPullRequest(materialInfo(map[string]string{
+		"git url":            "https://github.com/org/repo/pull/12",
+		"header":             "add payments api",
+		"source branch name": "feature/payments",
+		"target branch name": "main",
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://github.com/org/repo/pull/12", pullRequest.Url)
+	assert.Equal(t, 12, pullRequest.Number)
+	assert.Equal(t, "add payments api", pullRequest.Title)
+	assert.Equal(t, "feature/payments", pullRequest.SourceBranch)
+
+	// tag event
+	pullRequest, err = GetPullRequest(materialInfo(map[string]string{"git url": "https://github.com/org/repo/releases/tag/v1"}))
+	assert.Nil(t, err)
+	assert.Nil(t, pullRequest)
+
+	pullRequest, err = GetPullRequest("")
+	assert.Nil(t, err)
+	assert.Nil(t, pullRequest)
+}
+
+func TestGetClosedPullRequestUrl(t *testing.T) {
+	assert.Equal(t, "https://github.com/org/repo/pull/12", GetClosedPullRequestUrl("pull_request",
+		[]byte(`{"action":"closed","pull_request":{"html_url":"https://github.com/org/repo/pull/12"}}`)))
+	assert.Equal(t, "", GetClosedPullRequestUrl("pull_request",
+		[]byte(`{"action":"synchronize","pull_request":{"html_url":"https://github.com/org/repo/pull/12"}}`)))
+	assert.Equal(t, "https://gitlab.com/group/project/-/merge_requests/4", GetClosedPullRequestUrl("Merge Request Hook",
+		[]byte(`{"object_kind":"merge_request","object_attributes":{"action":"merge","url":"https://gitlab.com/group/project/-/merge_requests/4"}}`)))
+	assert.Equal(t, "https://bitbucket.org/ws/repo/pull-requests/5", GetClosedPullRequestUrl("pullrequest:rejected",
+		[]byte(`{"pullrequest":{"state":"DECLINED","links":{"html":{"href":"https://bitbucket.org/ws/repo/pull-requests/5"}}}}`)))
+	assert.Equal(t, "", GetClosedPullRequestUrl("push", []byte(`{"ref":"refs/heads/main"}`)))
+}
+
+func TestNewPullRequestCommentRequest(t *testing.T) {
+	gitProvider := &repository.GitProvider{Name: "gitlab", AuthMode: repository.AUTH_MODE_ACCESS_TOKEN, AccessToken: "token"}
+	ref, _ := ParsePullRequestUrl("https://gitlab.example.com/group/project/-/merge_requests/4")
+	req, err := NewPullRequestCommentRequest(ref, gitProvider, "preview ready")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://gitlab.example.com/api/v4/projects/group%2Fproject/merge_requests/4/notes", req.URL.String())
+	assert.Equal(t, "token", req.Header.Get("PRIVATE-TOKEN"))
+
+	ref, _ = ParsePullRequestUrl("https://github.com/org/repo/pull/12")
+	req, err = NewPullRequestCommentRequest(ref, gitProvider, "preview ready")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.github.com/repos/org/repo/issues/12/comments", req.URL.String())
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	body, _ := ioutil.ReadAll(req.Body)
+	assert.JSONEq(t, `{"body":"preview ready"}`, string(body))
+
+	gitProvider.AuthMode = repository.AUTH_MODE_SSH
+	_, err = NewPullRequestCommentRequest(ref, gitProvider, "preview ready")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,470 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Masterminds/semver/v3"
+	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type SbomService interface {
+	// GetSbomRequestForCi returns the formats the ci runner of the workflow generates sboms in, nil when sbom
+	// generation is not enabled or the workflow has no blob storage to upload them to
+	GetSbomRequestForCi(ciWorkflowId int, blobStorageConfigured bool) *bean.SbomRequest
+	// SaveSboms replaces the sboms of the image digest of the artifact in the formats given
+	SaveSboms(artifact *repository.CiArtifact, sboms []*bean.SbomDocumentDto, source security.SbomSource, userId int32) error
+	// SaveSbomRefs downloads the sboms the ci runner of the workflow uploaded to blob storage and saves them
+	SaveSbomRefs(artifact *repository.CiArtifact, ciPipelineId int, ciWorkflowId int, sbomRefs []*bean.SbomRefDto, userId int32) error
+	// UploadSboms saves sboms generated by the image scanner
+	UploadSboms(request *bean.SbomUploadRequest) ([]*bean.ImageSbomDto, error)
+	GetSbomsByArtifactId(ciArtifactId int) ([]*bean.ImageSbomDto, error)
+	GetSbomDocument(ciArtifactId int, format security.SbomFormat) (string, error)
+	// FindDeployedPackageExposure lists the deployed artifacts having the package in the version range
+	FindDeployedPackageExposure(request *bean.SbomPackageExposureRequest) ([]*bean.SbomPackageExposureDto, error)
+}
+
+type SbomServiceImpl struct {
+	logger               *zap.SugaredLogger
+	sbomRepository       security.SbomRepository
+	ciArtifactRepository repository.CiArtifactRepository
+	ciWorkflowRepository pipelineConfig.CiWorkflowRepository
+	ciConfig             *CiConfig
+}
+
+func NewSbomServiceImpl(logger *zap.SugaredLogger, sbomRepository security.SbomRepository,
+	ciArtifactRepository repository.CiArtifactRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
+	ciConfig *CiConfig) *SbomServiceImpl {
+	return &SbomServiceImpl{
+		logger:               logger,
+		sbomRepository:       sbomRepository,
+		ciArtifactRepository: ciArtifactRepository,
+		ciWorkflowRepository: ciWorkflowRepository,
+		ciConfig:             ciConfig,
+	}
+}
+
+func (impl *SbomServiceImpl) GetSbomRequestForCi(ciWorkflowId int, blobStorageConfigured bool) *bean.SbomRequest {
+	var formats []security.SbomFormat
+	for _, format := range strings.Split(impl.ciConfig.SbomFormats, ",") {
+		format = strings.TrimSpace(format)
+		if len(format) == 0 {
+			continue
+		}
+		if !isSupportedSbomFormat(security.SbomFormat(format)) {
+			impl.logger.Warnw("ignoring unsupported sbom format", "format", format)
+			continue
+		}
+		formats = append(formats, security.SbomFormat(format))
+	}
+	if len(formats) == 0 {
+		return nil
+	}
+	if !blobStorageConfigured {
+		impl.logger.Warnw("sboms are not generated without blob storage to upload them to", "ciWorkflowId", ciWorkflowId)
+		return nil
+	}
+	return &bean.SbomRequest{Formats: formats, BlobStorageKeyPrefix: impl.getSbomKeyPrefix(ciWorkflowId)}
+}
+
+func (impl *SbomServiceImpl) getSbomKeyPrefix(ciWorkflowId int) string {
+	return fmt.Sprintf("%s/%d/sbom", impl.ciConfig.DefaultArtifactKeyPrefix, ciWorkflowId)
+}
+
+func (impl *SbomServiceImpl) SaveSbomRefs(artifact *repository.CiArtifact, ciPipelineId int, ciWorkflowId int, sbomRefs []*bean.SbomRefDto, userId int32) error {
+	var sboms []*bean.SbomDocumentDto
+	for _, sbomRef := range sbomRefs {
+		// only the keys handed to the workflow are read
+		if !strings.HasPrefix(sbomRef.BlobStorageKey, impl.getSbomKeyPrefix(ciWorkflowId)+"/") || strings.Contains(sbomRef.BlobStorageKey, "..") {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("invalid sbom key %s", sbomRef.BlobStorageKey)}
+		}
+		document, err := impl.downloadSbom(ciPipelineId, ciWorkflowId, sbomRef)
+		if err != nil {
+			return err
+		}
+		sboms = append(sboms, &bean.SbomDocumentDto{Format: sbomRef.Format, Document: document})
+	}
+	return impl.SaveSboms(artifact, sboms, security.SBOM_SOURCE_CI, userId)
+}
+
+func (impl *SbomServiceImpl) downloadSbom(ciPipelineId int, ciWorkflowId int, sbomRef *bean.SbomRefDto) ([]byte, error) {
+	ciWorkflowConfig, err := impl.ciWorkflowRepository.FindConfigByPipelineId(ciPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching ci workflow config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	if ciWorkflowConfig.LogsBucket == "" {
+		ciWorkflowConfig.LogsBucket = impl.ciConfig.DefaultBuildLogsBucket
+	}
+	if ciWorkflowConfig.CiCacheRegion == "" {
+		ciWorkflowConfig.CiCacheRegion = impl.ciConfig.DefaultCacheBucketRegion
+	}
+	destinationKey := filepath.Join(impl.ciConfig.BaseLogLocationPath, fmt.Sprintf("sbom-%d-%s", ciWorkflowId, sbomRef.Format))
+	defer os.Remove(destinationKey)
+	request := &blob_storage.BlobStorageRequest{
+		StorageType:    impl.ciConfig.CloudProvider,
+		SourceKey:      sbomRef.BlobStorageKey,
+		DestinationKey: destinationKey,
+		AzureBlobBaseConfig: &blob_storage.AzureBlobBaseConfig{
+			Enabled:           impl.ciConfig.CloudProvider == BLOB_STORAGE_AZURE,
+			AccountName:       impl.ciConfig.AzureAccountName,
+			BlobContainerName: impl.ciConfig.AzureBlobContainerCiLog,
+			AccountKey:        impl.ciConfig.AzureAccountKey,
+		},
+		AwsS3BaseConfig: &blob_storage.AwsS3BaseConfig{
+			AccessKey:         impl.ciConfig.BlobStorageS3AccessKey,
+			Passkey:           impl.ciConfig.BlobStorageS3SecretKey,
+			EndpointUrl:       impl.ciConfig.BlobStorageS3Endpoint,
+			IsInSecure:        impl.ciConfig.BlobStorageS3EndpointInsecure,
+			BucketName:        ciWorkflowConfig.LogsBucket,
+			Region:            ciWorkflowConfig.CiCacheRegion,
+			VersioningEnabled: impl.ciConfig.BlobStorageS3BucketVersioned,
+		},
+		GcpBlobBaseConfig: &blob_storage.GcpBlobBaseConfig{
+			BucketName:             ciWorkflowConfig.LogsBucket,
+			CredentialFileJsonData: impl.ciConfig.BlobStorageGcpCredentialJson,
+		},
+	}
+	_, _, err = blob_storage.NewBlobStorageServiceImpl(nil).Get(request)
+	if err != nil {
+		impl.logger.Errorw("error in downloading sbom", "err", err, "key", sbomRef.BlobStorageKey)
+		return nil, err
+	}
+	document, err := os.ReadFile(destinationKey)
+	if err != nil {
+		impl.logger.Errorw("error in reading downloaded sbom", "err", err, "key", sbomRef.BlobStorageKey)
+		return nil, err
+	}
+	return document, nil
+}
+
+func (impl *SbomServiceImpl) SaveSboms(artifact *repository.CiArtifact, sboms []*bean.SbomDocumentDto, source security.SbomSource, userId int32) error {
+	if len(artifact.ImageDigest) == 0 {
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "image digest of artifact not known, sbom can not be saved"}
+	}
+	type parsedSbom struct {
+		sbom     *bean.SbomDocumentDto
+		version  string
+		packages []*security.ImageSbomPackage
+	}
+	var parsedSboms []*parsedSbom
+	for _, sbom := range sboms {
+		if !isSupportedSbomFormat(sbom.Format) {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("unsupported sbom format %s", sbom.Format)}
+		}
+		specVersion, packages, err := ParseSbomPackages(sbom.Format, sbom.Document)
+		if err != nil {
+			impl.logger.Errorw("error in parsing sbom", "err", err, "format", sbom.Format, "artifactId", artifact.Id)
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: fmt.Sprintf("invalid %s sbom", sbom.Format)}
+		}
+		parsedSboms = append(parsedSboms, &parsedSbom{sbom: sbom, version: specVersion, packages: packages})
+	}
+
+	dbConnection := impl.sbomRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	for _, parsed := range parsedSboms {
+		err = impl.sbomRepository.DeactivateSbomsByImageDigestAndFormat(artifact.ImageDigest, parsed.sbom.Format, userId, tx)
+		if err != nil {
+			impl.logger.Errorw("error in deactivating older sboms", "err", err, "imageDigest", artifact.ImageDigest, "format", parsed.sbom.Format)
+			return err
+		}
+		model := &security.ImageSbom{
+			CiArtifactId: artifact.Id,
+			ImageDigest:  artifact.ImageDigest,
+			Format:       parsed.sbom.Format,
+			SpecVersion:  parsed.version,
+			Source:       source,
+			Document:     string(parsed.sbom.Document),
+			PackageCount: len(parsed.packages),
+			Active:       true,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		err = impl.sbomRepository.SaveSbom(model, tx)
+		if err != nil {
+			impl.logger.Errorw("error in saving sbom", "err", err, "artifactId", artifact.Id, "format", parsed.sbom.Format)
+			return err
+		}
+		for _, pkg := range parsed.packages {
+			pkg.ImageSbomId = model.Id
+			pkg.ImageDigest = artifact.ImageDigest
+		}
+		err = impl.sbomRepository.SavePackages(parsed.packages, tx)
+		if err != nil {
+			impl.logger.Errorw("error in saving sbom packages", "err", err, "artifactId", artifact.Id, "format", parsed.sbom.Format)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (impl *SbomServiceImpl) UploadSboms(request *bean.SbomUploadRequest) ([]*bean.ImageSbomDto, error) {
+	var artifact *repository.CiArtifact
+	var err error
+	if request.CiArtifactId > 0 {
+		artifact, err = impl.ciArtifactRepository.Get(request.CiArtifactId)
+		if err == nil && artifact.ImageDigest != request.ImageDigest {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "image digest does not match the artifact"}
+		}
+	} else {
+		artifact, err = impl.ciArtifactRepository.GetByImageDigest(request.ImageDigest)
+	}
+	if util.IsErrNoRows(err) {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: "artifact not found for image digest"}
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "err", err, "imageDigest", request.ImageDigest, "artifactId", request.CiArtifactId)
+		return nil, err
+	}
+	err = impl.SaveSboms(artifact, request.Sboms, security.SBOM_SOURCE_SCAN, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetSbomsByArtifactId(artifact.Id)
+}
+
+func (impl *SbomServiceImpl) GetSbomsByArtifactId(ciArtifactId int) ([]*bean.ImageSbomDto, error) {
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "err", err, "artifactId", ciArtifactId)
+		return nil, err
+	}
+	sbomDtos := make([]*bean.ImageSbomDto, 0)
+	if len(artifact.ImageDigest) == 0 {
+		return sbomDtos, nil
+	}
+	// sboms are kept per image digest, artifacts of child pipelines share them with the parent
+	sboms, err := impl.sbomRepository.FindActiveSbomsByImageDigest(artifact.ImageDigest)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching sboms", "err", err, "artifactId", ciArtifactId)
+		return nil, err
+	}
+	for _, sbom := range sboms {
+		sbomDtos = append(sbomDtos, &bean.ImageSbomDto{
+			Id:           sbom.Id,
+			CiArtifactId: sbom.CiArtifactId,
+			ImageDigest:  sbom.ImageDigest,
+			Format:       sbom.Format,
+			SpecVersion:  sbom.SpecVersion,
+			Source:       sbom.Source,
+			PackageCount: sbom.PackageCount,
+			GeneratedOn:  sbom.CreatedOn,
+		})
+	}
+	return sbomDtos, nil
+}
+
+func (impl *SbomServiceImpl) GetSbomDocument(ciArtifactId int, format security.SbomFormat) (string, error) {
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "err", err, "artifactId", ciArtifactId)
+		return "", err
+	}
+	sbom, err := impl.sbomRepository.FindActiveSbomByImageDigestAndFormat(artifact.ImageDigest, format)
+	if util.IsErrNoRows(err) || (err == nil && len(artifact.ImageDigest) == 0) {
+		return "", &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("%s sbom not found for artifact", format)}
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching sbom", "err", err, "artifactId", ciArtifactId, "format", format)
+		return "", err
+	}
+	return sbom.Document, nil
+}
+
+func (impl *SbomServiceImpl) FindDeployedPackageExposure(request *bean.SbomPackageExposureRequest) ([]*bean.SbomPackageExposureDto, error) {
+	var constraint *semver.Constraints
+	if len(strings.TrimSpace(request.VersionRange)) > 0 {
+		var err error
+		constraint, err = semver.NewConstraint(request.VersionRange)
+		if err != nil {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: fmt.Sprintf("invalid version range %s", request.VersionRange)}
+		}
+	}
+	exposures, err := impl.sbomRepository.FindDeployedPackageExposure(&security.SbomPackageFilter{
+		PackageName:    request.PackageName,
+		PackageType:    request.PackageType,
+		EnvironmentIds: request.EnvIds,
+		ClusterIds:     request.ClusterIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exposureDtos := make([]*bean.SbomPackageExposureDto, 0)
+	for _, exposure := range exposures {
+		if constraint != nil && !MatchesVersionConstraint(exposure.PackageVersion, request.VersionRange, constraint) {
+			continue
+		}
+		exposureDtos = append(exposureDtos, &bean.SbomPackageExposureDto{
+			AppId:           exposure.AppId,
+			AppName:         exposure.AppName,
+			EnvId:           exposure.EnvId,
+			EnvironmentName: exposure.EnvironmentName,
+			ClusterId:       exposure.ClusterId,
+			ClusterName:     exposure.ClusterName,
+			CiArtifactId:    exposure.CiArtifactId,
+			Image:           exposure.Image,
+			ImageDigest:     exposure.ImageDigest,
+			PackageName:     exposure.PackageName,
+			PackageVersion:  exposure.PackageVersion,
+			PackageType:     exposure.PackageType,
+			Purl:            exposure.Purl,
+		})
+	}
+	return exposureDtos, nil
+}
+
+// MatchesVersionConstraint checks the package version against the constraint of the version range. Versions which
+// are not semver, like distro package versions, match only when the range is the version itself
+func MatchesVersionConstraint(version string, versionRange string, constraint *semver.Constraints) bool {
+	parsedVersion, err := semver.NewVersion(version)
+	if err != nil {
+		return strings.TrimSpace(versionRange) == version
+	}
+	return constraint.Check(parsedVersion)
+}
+
+func isSupportedSbomFormat(format security.SbomFormat) bool {
+	return format == security.SBOM_FORMAT_SPDX_JSON || format == security.SBOM_FORMAT_CYCLONEDX_JSON
+}
+
+type spdxDocument struct {
+	SpdxVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+		ExternalRefs     []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+type cycloneDxDocument struct {
+	BomFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Components  []cycloneDxComponent `json:"components"`
+}
+
+type cycloneDxComponent struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Purl     string `json:"purl"`
+	Licenses []struct {
+		License struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []cycloneDxComponent `json:"components"`
+}
+
+// ParseSbomPackages returns the spec version and the packages listed in an spdx or cyclonedx json document
+func ParseSbomPackages(format security.SbomFormat, document []byte) (string, []*security.ImageSbomPackage, error) {
+	var packages []*security.ImageSbomPackage
+	switch format {
+	case security.SBOM_FORMAT_SPDX_JSON:
+		spdx := &spdxDocument{}
+		if err := json.Unmarshal(document, spdx); err != nil {
+			return "", nil, err
+		}
+		if len(spdx.SpdxVersion) == 0 {
+			return "", nil, fmt.Errorf("spdxVersion not found in document")
+		}
+		for _, spdxPackage := range spdx.Packages {
+			pkg := &security.ImageSbomPackage{
+				Name:    spdxPackage.Name,
+				Version: spdxPackage.VersionInfo,
+				License: getSpdxLicense(spdxPackage.LicenseConcluded, spdxPackage.LicenseDeclared),
+			}
+			for _, ref := range spdxPackage.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					pkg.Purl = ref.ReferenceLocator
+					break
+				}
+			}
+			packages = appendSbomPackage(packages, pkg)
+		}
+		return spdx.SpdxVersion, packages, nil
+	case security.SBOM_FORMAT_CYCLONEDX_JSON:
+		cycloneDx := &cycloneDxDocument{}
+		if err := json.Unmarshal(document, cycloneDx); err != nil {
+			return "", nil, err
+		}
+		if cycloneDx.BomFormat != "CycloneDX" {
+			return "", nil, fmt.Errorf("bomFormat CycloneDX not found in document")
+		}
+		packages = appendCycloneDxPackages(packages, cycloneDx.Components)
+		return cycloneDx.SpecVersion, packages, nil
+	}
+	return "", nil, fmt.Errorf("unsupported sbom format %s", format)
+}
+
+func appendCycloneDxPackages(packages []*security.ImageSbomPackage, components []cycloneDxComponent) []*security.ImageSbomPackage {
+	for _, component := range components {
+		var licenses []string
+		for _, license := range component.Licenses {
+			if len(license.Expression) > 0 {
+				licenses = append(licenses, license.Expression)
+			} else if len(license.License.Id) > 0 {
+				licenses = append(licenses, license.License.Id)
+			} else if len(license.License.Name) > 0 {
+				licenses = append(licenses, license.License.Name)
+			}
+		}
+		packages = appendSbomPackage(packages, &security.ImageSbomPackage{
+			Name:    component.Name,
+			Version: component.Version,
+			Purl:    component.Purl,
+			License: strings.Join(licenses, " AND "),
+		})
+		// nested components, like the jars inside a fat jar
+		packages = appendCycloneDxPackages(packages, component.Components)
+	}
+	return packages
+}
+
+func appendSbomPackage(packages []*security.ImageSbomPackage, pkg *security.ImageSbomPackage) []*security.ImageSbomPackage {
+	if len(pkg.Name) == 0 {
+		return packages
+	}
+	pkg.Type = getPurlType(pkg.Purl)
+	return append(packages, pkg)
+}
+
+// getSpdxLicense prefers the concluded license, NOASSERTION and NONE are spdx placeholders for not known
+func getSpdxLicense(concluded, declared string) string {
+	for _, license := range []string{concluded, declared} {
+		if len(license) > 0 && license != "NOASSERTION" && license != "NONE" {
+			return license
+		}
+	}
+	return ""
+}
+
+// getPurlType returns the type of a package url, maven for pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1
+func getPurlType(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+	purlType := strings.TrimPrefix(purl, "pkg:")
+	if index := strings.Index(purlType, "/"); index > 0 {
+		return strings.ToLower(purlType[:index])
+	}
+	return ""
+}
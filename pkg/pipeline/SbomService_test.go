@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"github.com/Masterminds/semver/v3"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestParseSbomPackages(t *testing.T) {
+	spdx := `{"spdxVersion": "SPDX-2.3", "packages": [
+		{"name": "log4j-core", "versionInfo": "2.14.1", "licenseConcluded": "NOASSERTION", "licenseDeclared": "Apache-2.0",
+			"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]},
+		{"name": "", "versionInfo": "1.0"}]}`
+	specVersion, packages, err := ParseSbomPackages(security.SBOM_FORMAT_SPDX_JSON, []byte(spdx))
+	assert.Nil(t, err)
+	assert.Equal(t, "SPDX-2.3", specVersion)
+	assert.Equal(t, 1, len(packages))
+	assert.Equal(t, &security.ImageSbomPackage{Name: "log4j-core", Version: "2.14.1", Type: "maven", License: "Apache-2.0",
+		Purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}, packages[0])
+
+	cycloneDx := `{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": [
+		{"name": "app", "version": "1.0.0", "purl": "pkg:maven/com.example/app@1.0.0", "licenses": [{"license": {"id": "MIT"}}],
+			"components": [{"name": "log4j-api", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1"}]},
+		{"name": "openssl", "version": "3.0.2-0ubuntu1.10", "purl": "pkg:deb/ubuntu/openssl@3.0.2-0ubuntu1.10", "licenses": [{"expression": "Apache-2.0 OR OpenSSL"}]}]}`
+	specVersion, packages, err = ParseSbomPackages(security.SBOM_FORMAT_CYCLONEDX_JSON, []byte(cycloneDx))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4", specVersion)
+	var names []string
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+	assert.Equal(t, []string{"app", "log4j-api", "openssl"}, names)
+	assert.Equal(t, "MIT", packages[0].License)
+	assert.Equal(t, "deb", packages[2].Type)
+	assert.Equal(t, "Apache-2.0 OR OpenSSL", packages[2].License)
+
+	// a document of the other format is rejected
+	_, _, err = ParseSbomPackages(security.SBOM_FORMAT_SPDX_JSON, []byte(cycloneDx))
+	assert.NotNil(t, err)
+}
+
+func TestMatchesVersionConstraint(t *testing.T) {
+	versionRange := ">= 2.0.0, < 2.17.1"
+	constraint, err := semver.NewConstraint(versionRange)
+	assert.Nil(t, err)
+	assert.True(t, MatchesVersionConstraint("2.14.1", versionRange, constraint))
+	assert.True(t, MatchesVersionConstraint("2.15", versionRange, constraint))
+	assert.False(t, MatchesVersionConstraint("2.17.1", versionRange, constraint))
+	assert.False(t, MatchesVersionConstraint("1.2.17", versionRange, constraint))
+	assert.False(t, MatchesVersionConstraint("3.0.2-0ubuntu1.10", versionRange, constraint))
+
+	// versions which are not semver match the exact version only
+	versionRange = "1:1.2.11.dfsg-2"
+	constraint, _ = semver.NewConstraint(">= 0.0.0")
+	assert.True(t, MatchesVersionConstraint("1:1.2.11.dfsg-2", versionRange, constraint))
+	assert.False(t, MatchesVersionConstraint("1:1.2.13.dfsg-1", versionRange, constraint))
+}
+
+func TestSaveSbomRefsRejectsKeysOfOtherWorkflows(t *testing.T) {
+	impl := &SbomServiceImpl{logger: zap.NewNop().Sugar(), ciConfig: &CiConfig{DefaultArtifactKeyPrefix: "arsenal-v1/ci-artifacts", SbomFormats: "spdx-json"}}
+	request := impl.GetSbomRequestForCi(12, true)
+	assert.Equal(t, "arsenal-v1/ci-artifacts/12/sbom", request.BlobStorageKeyPrefix)
+	assert.Nil(t, impl.GetSbomRequestForCi(12, false))
+
+	for _, key := range []string{"arsenal-v1/ci-artifacts/13/sbom/spdx-json.json", "arsenal-v1/ci-artifacts/12/sbom/../../13/sbom/spdx-json.json"} {
+		err := impl.SaveSbomRefs(&repository.CiArtifact{Id: 1, ImageDigest: "sha256:abc"}, 1, 12,
+			[]*bean.SbomRefDto{{Format: security.SBOM_FORMAT_SPDX_JSON, BlobStorageKey: key}}, 1)
+		assert.NotNil(t, err)
+	}
+}
@@ -0,0 +1,641 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	k8s2 "github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/util/k8s"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WORKFLOW_PURPOSE_LABEL_KEY  = "devtron.ai/workflow-purpose"
+	WORKFLOW_EXECUTOR_LABEL_KEY = "devtron.ai/workflow-executor"
+	WORKFLOW_EXECUTOR_SYSTEM    = "system"
+	JOB_NAME_LABEL_KEY          = "job-name"
+	// extra time given to the status watcher over the job deadline so that the final status is always reported
+	SYSTEM_WORKFLOW_WATCH_GRACE_SECONDS = 300
+	// system workflows are watched only by the replica holding this lease, it is renewed on every sync
+	SYSTEM_WORKFLOW_WATCH_LEASE         = "system-workflow-watch"
+	SYSTEM_WORKFLOW_WATCH_LEASE_SECONDS = 180
+	SYSTEM_WORKFLOW_WATCH_SYNC_CRON     = "@every 1m"
+	SYSTEM_WORKFLOW_REWATCH_DELAY       = 5 * time.Second
+)
+
+// SystemWorkflowExecutor runs a bean.WorkflowTemplate as a plain batch/v1 Job instead of an argo Workflow.
+// Status changes of the job are converted to v1alpha1.WorkflowStatus and published on the same topics
+// kubewatch uses for argo workflows, so CiHandler.UpdateWorkflow and CdHandler.UpdateWorkflow work unchanged.
+type SystemWorkflowExecutor interface {
+	WorkflowExecutor
+	TerminateWorkflow(workflowName string, namespace string, clusterConfig *rest.Config) error
+	ResumeWorkflowWatches()
+}
+
+type SystemWorkflowExecutorImpl struct {
+	logger            *zap.SugaredLogger
+	k8sUtil           *k8s.K8sUtil
+	pubsubClient      *pubsub.PubSubClientServiceImpl
+	clusterService    cluster.ClusterService
+	k8sCommonService  k8s2.K8sCommonService
+	leaseRepository   repository.SchedulerLeaseRepository
+	clientSetProvider func(clusterConfig *rest.Config) (kubernetes.Interface, error)
+
+	watchLock sync.Mutex
+	// cancels the watches of this replica, nil while another replica holds the watch lease
+	watchCtx    context.Context
+	watchCancel context.CancelFunc
+	// jobs watched by this replica and finished jobs whose status is published, keyed by getJobKey
+	watchedJobs  map[string]bool
+	reportedJobs map[string]bool
+}
+
+func NewSystemWorkflowExecutorImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sUtil, pubsubClient *pubsub.PubSubClientServiceImpl,
+	clusterService cluster.ClusterService, k8sCommonService k8s2.K8sCommonService, leaseRepository repository.SchedulerLeaseRepository) *SystemWorkflowExecutorImpl {
+	impl := &SystemWorkflowExecutorImpl{
+		logger:           logger,
+		k8sUtil:          k8sUtil,
+		pubsubClient:     pubsubClient,
+		clusterService:   clusterService,
+		k8sCommonService: k8sCommonService,
+		leaseRepository:  leaseRepository,
+		watchedJobs:      make(map[string]bool),
+		reportedJobs:     make(map[string]bool),
+	}
+	impl.clientSetProvider = impl.getClientSet
+	watchCron := cron.New(cron.WithChain())
+	watchCron.Start()
+	_, err := watchCron.AddFunc(SYSTEM_WORKFLOW_WATCH_SYNC_CRON, impl.ResumeWorkflowWatches)
+	if err != nil {
+		logger.Errorw("error in starting system workflow watch sync cron", "err", err)
+	}
+	go impl.ResumeWorkflowWatches()
+	return impl
+}
+
+func (impl *SystemWorkflowExecutorImpl) ExecuteWorkflow(workflowTemplate bean.WorkflowTemplate) error {
+	clientSet, err := impl.clientSetProvider(workflowTemplate.ClusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting client set for system workflow", "err", err)
+		return err
+	}
+	job := impl.getJobTemplate(workflowTemplate)
+	createdJob, err := clientSet.BatchV1().Jobs(workflowTemplate.Namespace).Create(context.Background(), job, v1.CreateOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in creating job for system workflow", "namespace", workflowTemplate.Namespace, "err", err)
+		return err
+	}
+	impl.logger.Debugw("system workflow submitted", "name", createdJob.Name)
+
+	// cm and cs are created after the job so that they can be owned by it and get garbage collected with it,
+	// kubelet keeps retrying the pod till they are available
+	err = impl.createConfigMapsAndSecrets(clientSet, createdJob, workflowTemplate.ConfigMaps, workflowTemplate.Secrets)
+	if err != nil {
+		impl.logger.Errorw("error in creating cm/cs for system workflow, deleting job", "job", createdJob.Name, "err", err)
+		deleteErr := impl.deleteJob(clientSet, createdJob.Name, createdJob.Namespace)
+		if deleteErr != nil {
+			impl.logger.Errorw("error in deleting job", "job", createdJob.Name, "err", deleteErr)
+		}
+		return err
+	}
+	// replicas not holding the watch lease leave the job to the next sync of the holder
+	if watchCtx := impl.getWatchContext(); watchCtx != nil {
+		impl.startWatch(watchCtx, clientSet, getJobKey(workflowTemplate.ClusterConfig, createdJob), createdJob, workflowTemplate.WorkflowType)
+	}
+	return nil
+}
+
+func (impl *SystemWorkflowExecutorImpl) TerminateWorkflow(workflowName string, namespace string, clusterConfig *rest.Config) error {
+	clientSet, err := impl.clientSetProvider(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting client set for system workflow", "err", err)
+		return err
+	}
+	return impl.deleteJob(clientSet, workflowName, namespace)
+}
+
+func (impl *SystemWorkflowExecutorImpl) getJobTemplate(workflowTemplate bean.WorkflowTemplate) *batchv1.Job {
+	labels := map[string]string{
+		WORKFLOW_PURPOSE_LABEL_KEY:  workflowTemplate.WorkflowType,
+		WORKFLOW_EXECUTOR_LABEL_KEY: WORKFLOW_EXECUTOR_SYSTEM,
+	}
+	podSpec := workflowTemplate.PodSpec
+	// deadline is enforced at job level so that time spent in pending state is also accounted for
+	podSpec.ActiveDeadlineSeconds = nil
+	podSpec.RestartPolicy = v12.RestartPolicyNever
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: workflowTemplate.WorkflowNamePrefix + "-",
+			Namespace:    workflowTemplate.Namespace,
+			Labels:       labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   workflowTemplate.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: workflowTemplate.TTLValue,
+			Template: v12.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+func (impl *SystemWorkflowExecutorImpl) createConfigMapsAndSecrets(clientSet kubernetes.Interface, job *batchv1.Job, configMaps []bean2.ConfigSecretMap, secrets []bean2.ConfigSecretMap) error {
+	ownerRef := v1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: job.Name, UID: job.UID, BlockOwnerDeletion: &[]bool{true}[0]}
+	for _, configMap := range configMaps {
+		if configMap.External {
+			continue
+		}
+		configDataMap, err := configMap.GetDataMap()
+		if err != nil {
+			impl.logger.Errorw("error occurred while extracting data map", "name", configMap.Name, "err", err)
+			return err
+		}
+		configMapBody := GetConfigMapBody(ConfigMapSecretDto{Name: configMap.Name, Data: configDataMap, OwnerRef: ownerRef})
+		_, err = clientSet.CoreV1().ConfigMaps(job.Namespace).Create(context.Background(), &configMapBody, v1.CreateOptions{})
+		if err != nil {
+			impl.logger.Errorw("error in creating configmap", "name", configMap.Name, "err", err)
+			return err
+		}
+	}
+	for _, secret := range secrets {
+		if secret.External {
+			continue
+		}
+		secretDataMap, err := secret.GetDataMap()
+		if err != nil {
+			impl.logger.Errorw("error occurred while extracting data map", "name", secret.Name, "err", err)
+			return err
+		}
+		secretBody := GetSecretBody(ConfigMapSecretDto{Name: secret.Name, Data: secretDataMap, OwnerRef: ownerRef})
+		_, err = clientSet.CoreV1().Secrets(job.Namespace).Create(context.Background(), &secretBody, v1.CreateOptions{})
+		if err != nil {
+			impl.logger.Errorw("error in creating secret", "name", secret.Name, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// ResumeWorkflowWatches syncs the status watchers with the system workflow jobs present in the clusters. It runs on
+// start up and periodically on every replica, but only the replica holding the watch lease watches jobs, so statuses
+// are published once. Jobs which finished while nobody watched get their final status published, the handlers skip
+// statuses which are already saved.
+func (impl *SystemWorkflowExecutorImpl) ResumeWorkflowWatches() {
+	acquired, err := impl.leaseRepository.TryAcquire(SYSTEM_WORKFLOW_WATCH_LEASE, SYSTEM_WORKFLOW_WATCH_LEASE_SECONDS*time.Second)
+	if err != nil {
+		impl.logger.Errorw("error in acquiring system workflow watch lease", "err", err)
+		return
+	}
+	if !acquired {
+		impl.stopWatches()
+		return
+	}
+	watchCtx := impl.startWatches()
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting clusters for resuming system workflow watches", "err", err)
+		return
+	}
+	for _, clusterBean := range clusters {
+		if clusterBean.IsVirtualCluster {
+			continue
+		}
+		restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(context.Background(), clusterBean.Id)
+		if err != nil {
+			impl.logger.Errorw("error in getting rest config for resuming system workflow watches", "clusterId", clusterBean.Id, "err", err)
+			continue
+		}
+		err = impl.resumeWorkflowWatchesInCluster(watchCtx, restConfig)
+		if err != nil {
+			impl.logger.Errorw("error in resuming system workflow watches", "clusterId", clusterBean.Id, "err", err)
+		}
+	}
+}
+
+func (impl *SystemWorkflowExecutorImpl) resumeWorkflowWatchesInCluster(watchCtx context.Context, clusterConfig *rest.Config) error {
+	clientSet, err := impl.clientSetProvider(clusterConfig)
+	if err != nil {
+		return err
+	}
+	jobs, err := clientSet.BatchV1().Jobs(v1.NamespaceAll).List(context.Background(), v1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", WORKFLOW_EXECUTOR_LABEL_KEY, WORKFLOW_EXECUTOR_SYSTEM)})
+	if err != nil {
+		return err
+	}
+	finishedJobs := make(map[string]bool)
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		jobKey := getJobKey(clusterConfig, job)
+		workflowType := job.Labels[WORKFLOW_PURPOSE_LABEL_KEY]
+		if !isJobFinished(job) {
+			impl.startWatch(watchCtx, clientSet, jobKey, job, workflowType)
+			continue
+		}
+		finishedJobs[jobKey] = true
+		if impl.isJobReported(jobKey) {
+			continue
+		}
+		pod, err := impl.getWorkflowPod(context.Background(), clientSet, job)
+		if err != nil {
+			impl.logger.Errorw("error in listing system workflow pods", "job", job.Name, "err", err)
+			continue
+		}
+		impl.publishWorkflowStatus(workflowType, GetWorkflowStatusFromJob(job, pod, workflowType))
+		impl.markJobReported(jobKey)
+	}
+	impl.pruneReportedJobs(clusterConfig, finishedJobs)
+	return nil
+}
+
+// startWatches returns the context of the watches of this replica, creating it when this replica just took the lease
+func (impl *SystemWorkflowExecutorImpl) startWatches() context.Context {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	if impl.watchCtx == nil {
+		impl.logger.Infow("system workflow watch lease acquired, watching system workflows")
+		impl.watchCtx, impl.watchCancel = context.WithCancel(context.Background())
+	}
+	return impl.watchCtx
+}
+
+// stopWatches cancels the watches of this replica once another replica holds the lease
+func (impl *SystemWorkflowExecutorImpl) stopWatches() {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	if impl.watchCancel == nil {
+		return
+	}
+	impl.logger.Infow("system workflow watch lease lost, stopping system workflow watches")
+	impl.watchCancel()
+	impl.watchCtx, impl.watchCancel = nil, nil
+	impl.reportedJobs = make(map[string]bool)
+}
+
+func (impl *SystemWorkflowExecutorImpl) getWatchContext() context.Context {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	return impl.watchCtx
+}
+
+// startWatch watches the job unless it is already watched by this replica
+func (impl *SystemWorkflowExecutorImpl) startWatch(watchCtx context.Context, clientSet kubernetes.Interface, jobKey string, job *batchv1.Job, workflowType string) {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	if impl.watchedJobs[jobKey] {
+		return
+	}
+	impl.watchedJobs[jobKey] = true
+	impl.logger.Infow("watching system workflow", "job", job.Name, "namespace", job.Namespace)
+	go func() {
+		impl.watchWorkflow(watchCtx, clientSet, job, workflowType)
+		impl.watchLock.Lock()
+		defer impl.watchLock.Unlock()
+		delete(impl.watchedJobs, jobKey)
+		if watchCtx.Err() == nil {
+			impl.reportedJobs[jobKey] = true
+		}
+	}()
+}
+
+func (impl *SystemWorkflowExecutorImpl) isJobReported(jobKey string) bool {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	return impl.reportedJobs[jobKey]
+}
+
+func (impl *SystemWorkflowExecutorImpl) isJobWatched(jobKey string) bool {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	return impl.watchedJobs[jobKey]
+}
+
+func (impl *SystemWorkflowExecutorImpl) markJobReported(jobKey string) {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	impl.reportedJobs[jobKey] = true
+}
+
+// pruneReportedJobs forgets reported jobs of the cluster which are gone
+func (impl *SystemWorkflowExecutorImpl) pruneReportedJobs(clusterConfig *rest.Config, finishedJobs map[string]bool) {
+	impl.watchLock.Lock()
+	defer impl.watchLock.Unlock()
+	clusterPrefix := getClusterKey(clusterConfig) + "/"
+	for jobKey := range impl.reportedJobs {
+		if strings.HasPrefix(jobKey, clusterPrefix) && !finishedJobs[jobKey] {
+			delete(impl.reportedJobs, jobKey)
+		}
+	}
+}
+
+// watchWorkflow follows the job and publishes a workflow status on every change till the job finishes. The final
+// status is decided from the job conditions, pod events are followed only to report the workflow running. Watches
+// closed by the api server are opened again, a job not finished by its deadline plus the grace period is reported
+// failed and deleted.
+func (impl *SystemWorkflowExecutorImpl) watchWorkflow(watchCtx context.Context, clientSet kubernetes.Interface, job *batchv1.Job, workflowType string) {
+	ctx, cancel := context.WithTimeout(watchCtx, getWatchTimeout(job))
+	defer cancel()
+	var lastStatus *v1alpha1.WorkflowStatus
+	for ctx.Err() == nil {
+		finished, err := impl.followWorkflow(ctx, clientSet, job, workflowType, &lastStatus)
+		if finished {
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			impl.logger.Errorw("error in watching system workflow, watching again", "job", job.Name, "err", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(SYSTEM_WORKFLOW_REWATCH_DELAY):
+			}
+		}
+	}
+	if watchCtx.Err() != nil {
+		// watch is handed over to the replica holding the lease now
+		return
+	}
+	impl.logger.Errorw("system workflow not finished within its deadline, marking it failed", "job", job.Name)
+	impl.publishWorkflowStatus(workflowType, getTimedOutWorkflowStatus(job, workflowType, lastStatus))
+	_ = impl.deleteJob(clientSet, job.Name, job.Namespace)
+}
+
+// followWorkflow publishes the status of the workflow till it finishes or one of the watches is closed
+func (impl *SystemWorkflowExecutorImpl) followWorkflow(ctx context.Context, clientSet kubernetes.Interface, job *batchv1.Job, workflowType string, lastStatus **v1alpha1.WorkflowStatus) (bool, error) {
+	jobWatcher, err := clientSet.BatchV1().Jobs(job.Namespace).Watch(ctx, v1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", job.Name).String()})
+	if err != nil {
+		return false, err
+	}
+	defer jobWatcher.Stop()
+	podWatcher, err := clientSet.CoreV1().Pods(job.Namespace).Watch(ctx, v1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", JOB_NAME_LABEL_KEY, job.Name)})
+	if err != nil {
+		return false, err
+	}
+	defer podWatcher.Stop()
+	// changes made while no watch was open are caught by publishing the current status first
+	finished, err := impl.publishCurrentStatus(ctx, clientSet, job, workflowType, lastStatus)
+	for !finished && err == nil {
+		var event watch.Event
+		var ok bool
+		select {
+		case event, ok = <-jobWatcher.ResultChan():
+		case event, ok = <-podWatcher.ResultChan():
+		}
+		if !ok {
+			return false, nil
+		}
+		if event.Type == watch.Error {
+			return false, errors.FromObject(event.Object)
+		}
+		finished, err = impl.publishCurrentStatus(ctx, clientSet, job, workflowType, lastStatus)
+	}
+	return finished, err
+}
+
+// publishCurrentStatus publishes the status of the job when it changed, a deleted job is taken as finished
+func (impl *SystemWorkflowExecutorImpl) publishCurrentStatus(ctx context.Context, clientSet kubernetes.Interface, job *batchv1.Job, workflowType string, lastStatus **v1alpha1.WorkflowStatus) (bool, error) {
+	currentJob, err := clientSet.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		impl.logger.Infow("system workflow deleted, stopping watch", "job", job.Name)
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	pod, err := impl.getWorkflowPod(ctx, clientSet, currentJob)
+	if err != nil {
+		return false, err
+	}
+	workflowStatus := GetWorkflowStatusFromJob(currentJob, pod, workflowType)
+	if *lastStatus == nil || isWorkflowStatusChanged(*lastStatus, &workflowStatus) {
+		impl.publishWorkflowStatus(workflowType, workflowStatus)
+		*lastStatus = &workflowStatus
+	}
+	return workflowStatus.Phase.Completed(), nil
+}
+
+// getWorkflowPod gives the latest pod of the job, nil when it is not created yet or already removed
+func (impl *SystemWorkflowExecutorImpl) getWorkflowPod(ctx context.Context, clientSet kubernetes.Interface, job *batchv1.Job) (*v12.Pod, error) {
+	pods, err := clientSet.CoreV1().Pods(job.Namespace).List(ctx, v1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", JOB_NAME_LABEL_KEY, job.Name)})
+	if err != nil {
+		return nil, err
+	}
+	var pod *v12.Pod
+	for i := range pods.Items {
+		if pod == nil || pod.CreationTimestamp.Before(&pods.Items[i].CreationTimestamp) {
+			pod = &pods.Items[i]
+		}
+	}
+	return pod, nil
+}
+
+func (impl *SystemWorkflowExecutorImpl) publishWorkflowStatus(workflowType string, workflowStatus v1alpha1.WorkflowStatus) {
+	if impl.pubsubClient == nil {
+		return
+	}
+	topic := pubsub.WORKFLOW_STATUS_UPDATE_TOPIC
+	if workflowType == CD_WORKFLOW_NAME {
+		topic = pubsub.CD_WORKFLOW_STATUS_UPDATE
+	}
+	data, err := json.Marshal(workflowStatus)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling system workflow status", "err", err)
+		return
+	}
+	err = impl.pubsubClient.Publish(topic, string(data))
+	if err != nil {
+		impl.logger.Errorw("error in publishing system workflow status", "topic", topic, "err", err)
+	}
+}
+
+func (impl *SystemWorkflowExecutorImpl) deleteJob(clientSet kubernetes.Interface, name string, namespace string) error {
+	propagationPolicy := v1.DeletePropagationBackground
+	err := clientSet.BatchV1().Jobs(namespace).Delete(context.Background(), name, v1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+	if err != nil && !errors.IsNotFound(err) {
+		impl.logger.Errorw("error in deleting system workflow job", "name", name, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *SystemWorkflowExecutorImpl) getClientSet(clusterConfig *rest.Config) (kubernetes.Interface, error) {
+	_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return clientSet, nil
+}
+
+// GetWorkflowStatusFromJob builds the argo workflow status for a job, with a single node named after the pod whose
+// template name is the workflow type (ci/cd) and boundary id is the job name, which is what the handlers look for.
+func GetWorkflowStatusFromJob(job *batchv1.Job, pod *v12.Pod, workflowType string) v1alpha1.WorkflowStatus {
+	phase := v1alpha1.WorkflowPending
+	message := ""
+	var finishedAt v1.Time
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v12.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete {
+			phase = v1alpha1.WorkflowSucceeded
+			finishedAt = condition.LastTransitionTime
+		} else if condition.Type == batchv1.JobFailed {
+			phase = v1alpha1.WorkflowFailed
+			message = condition.Message
+			finishedAt = condition.LastTransitionTime
+		}
+	}
+	if phase == v1alpha1.WorkflowPending && job.Status.Active > 0 && pod != nil && pod.Status.Phase == v12.PodRunning {
+		phase = v1alpha1.WorkflowRunning
+	}
+	if job.Status.CompletionTime != nil {
+		finishedAt = *job.Status.CompletionTime
+	}
+	var startedAt v1.Time
+	if job.Status.StartTime != nil {
+		startedAt = *job.Status.StartTime
+	}
+
+	nodeName := job.Name
+	nodePhase := v1alpha1.NodePhase(phase)
+	if pod != nil {
+		nodeName = pod.Name
+		nodePhase, message = getNodePhaseAndMessage(pod, nodePhase, message)
+	}
+	return v1alpha1.WorkflowStatus{
+		Phase:      phase,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Message:    message,
+		Nodes: v1alpha1.Nodes{
+			nodeName: v1alpha1.NodeStatus{
+				ID:           nodeName,
+				Name:         nodeName,
+				DisplayName:  nodeName,
+				Type:         v1alpha1.NodeTypePod,
+				TemplateName: workflowType,
+				BoundaryID:   job.Name,
+				Phase:        nodePhase,
+				Message:      message,
+				StartedAt:    startedAt,
+				FinishedAt:   finishedAt,
+			},
+		},
+	}
+}
+
+func getNodePhaseAndMessage(pod *v12.Pod, defaultPhase v1alpha1.NodePhase, defaultMessage string) (v1alpha1.NodePhase, string) {
+	nodePhase := defaultPhase
+	switch pod.Status.Phase {
+	case v12.PodPending:
+		nodePhase = v1alpha1.NodePending
+	case v12.PodRunning:
+		nodePhase = v1alpha1.NodeRunning
+	case v12.PodSucceeded:
+		nodePhase = v1alpha1.NodeSucceeded
+	case v12.PodFailed:
+		nodePhase = v1alpha1.NodeFailed
+	}
+	message := defaultMessage
+	if len(message) == 0 {
+		message = pod.Status.Message
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if len(message) > 0 {
+			break
+		}
+		if containerStatus.State.Terminated != nil && containerStatus.State.Terminated.ExitCode != 0 {
+			message = fmt.Sprintf("%s (exit code %d)", containerStatus.State.Terminated.Reason, containerStatus.State.Terminated.ExitCode)
+		} else if containerStatus.State.Waiting != nil {
+			message = containerStatus.State.Waiting.Message
+		}
+	}
+	return nodePhase, message
+}
+
+// getWatchTimeout gives the time left till the job deadline plus the grace period, counted from the job start so
+// that resumed watches do not outlive the job
+func getWatchTimeout(job *batchv1.Job) time.Duration {
+	timeout := time.Duration(SYSTEM_WORKFLOW_WATCH_GRACE_SECONDS) * time.Second
+	if job.Spec.ActiveDeadlineSeconds != nil {
+		timeout += time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second
+		if job.Status.StartTime != nil {
+			timeout -= time.Since(job.Status.StartTime.Time)
+		}
+	}
+	return timeout
+}
+
+// getTimedOutWorkflowStatus gives the terminal status for a job not finished within its deadline, so that the
+// workflow does not stay running forever
+func getTimedOutWorkflowStatus(job *batchv1.Job, workflowType string, lastStatus *v1alpha1.WorkflowStatus) v1alpha1.WorkflowStatus {
+	workflowStatus := GetWorkflowStatusFromJob(job, nil, workflowType)
+	if lastStatus != nil {
+		workflowStatus = *lastStatus
+	}
+	message := "workflow did not finish within its deadline"
+	finishedAt := v1.Now()
+	nodes := make(v1alpha1.Nodes)
+	for name, node := range workflowStatus.Nodes {
+		node.Phase = v1alpha1.NodeFailed
+		node.Message = message
+		node.FinishedAt = finishedAt
+		nodes[name] = node
+	}
+	workflowStatus.Phase = v1alpha1.WorkflowFailed
+	workflowStatus.Message = message
+	workflowStatus.FinishedAt = finishedAt
+	workflowStatus.Nodes = nodes
+	return workflowStatus
+}
+
+func getJobKey(clusterConfig *rest.Config, job *batchv1.Job) string {
+	return fmt.Sprintf("%s/%s/%s", getClusterKey(clusterConfig), job.Namespace, job.Name)
+}
+
+func getClusterKey(clusterConfig *rest.Config) string {
+	if clusterConfig == nil {
+		return ""
+	}
+	return clusterConfig.Host
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status == v12.ConditionTrue && (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWorkflowStatusChanged(oldStatus *v1alpha1.WorkflowStatus, newStatus *v1alpha1.WorkflowStatus) bool {
+	if oldStatus.Phase != newStatus.Phase || oldStatus.Message != newStatus.Message {
+		return true
+	}
+	for name, node := range newStatus.Nodes {
+		oldNode, ok := oldStatus.Nodes[name]
+		if !ok || oldNode.Phase != node.Phase || oldNode.Message != node.Message {
+			return true
+		}
+	}
+	return false
+}
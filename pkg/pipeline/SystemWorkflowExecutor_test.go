@@ -0,0 +1,247 @@
+package pipeline
+
+import (
+	"context"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	v12 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"testing"
+	"time"
+)
+
+func getSystemWorkflowExecutorForTest(t *testing.T) (*SystemWorkflowExecutorImpl, *fake.Clientset) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	clientSet := fake.NewSimpleClientset()
+	executor := &SystemWorkflowExecutorImpl{
+		logger: logger,
+		clientSetProvider: func(clusterConfig *rest.Config) (kubernetes.Interface, error) {
+			return clientSet, nil
+		},
+		watchedJobs:  make(map[string]bool),
+		reportedJobs: make(map[string]bool),
+	}
+	return executor, clientSet
+}
+
+func TestSystemWorkflowExecutor_ExecuteWorkflow(t *testing.T) {
+	executor, clientSet := getSystemWorkflowExecutorForTest(t)
+	activeDeadlineSeconds := int64(600)
+	ttl := int32(3600)
+	workflowTemplate := bean.WorkflowTemplate{
+		WorkflowRunnerId:   12,
+		WorkflowType:       CD_WORKFLOW_NAME,
+		WorkflowNamePrefix: "12-pipeline",
+		Namespace:          "devtron-cd",
+		TTLValue:           &ttl,
+		ConfigMaps: []bean2.ConfigSecretMap{
+			{Name: "cm-12", Type: "environment", Data: []byte(`{"key":"value"}`)},
+			{Name: "external-cm", Type: "environment", External: true},
+		},
+		Secrets: []bean2.ConfigSecretMap{
+			{Name: "secret-12", Type: "volume", MountPath: "/etc/secret", Data: []byte(`{"key":"dmFsdWU="}`)},
+		},
+	}
+	workflowTemplate.ActiveDeadlineSeconds = &activeDeadlineSeconds
+	workflowTemplate.ServiceAccountName = "cd-runner"
+	workflowTemplate.Containers = []v12.Container{{Name: "main", Image: "cd-runner:latest"}}
+
+	err := executor.ExecuteWorkflow(workflowTemplate)
+	assert.Nil(t, err)
+
+	jobs, err := clientSet.BatchV1().Jobs("devtron-cd").List(context.Background(), v1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, jobs.Items, 1)
+	job := jobs.Items[0]
+	assert.Equal(t, "12-pipeline-", job.GenerateName)
+	assert.Equal(t, CD_WORKFLOW_NAME, job.Labels[WORKFLOW_PURPOSE_LABEL_KEY])
+	assert.Equal(t, WORKFLOW_EXECUTOR_SYSTEM, job.Labels[WORKFLOW_EXECUTOR_LABEL_KEY])
+	assert.Equal(t, activeDeadlineSeconds, *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, ttl, *job.Spec.TTLSecondsAfterFinished)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Nil(t, job.Spec.Template.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, v12.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	assert.Equal(t, "cd-runner", job.Spec.Template.Spec.ServiceAccountName)
+	assert.Equal(t, "cd-runner:latest", job.Spec.Template.Spec.Containers[0].Image)
+
+	configMaps, err := clientSet.CoreV1().ConfigMaps("devtron-cd").List(context.Background(), v1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, configMaps.Items, 1)
+	assert.Equal(t, "cm-12", configMaps.Items[0].Name)
+	assert.Equal(t, "value", configMaps.Items[0].Data["key"])
+	assert.Equal(t, "Job", configMaps.Items[0].OwnerReferences[0].Kind)
+
+	secrets, err := clientSet.CoreV1().Secrets("devtron-cd").List(context.Background(), v1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, secrets.Items, 1)
+	assert.Equal(t, "value", string(secrets.Items[0].Data["key"]))
+}
+
+func TestSystemWorkflowExecutor_TerminateWorkflow(t *testing.T) {
+	executor, clientSet := getSystemWorkflowExecutorForTest(t)
+	_, err := clientSet.BatchV1().Jobs("devtron-ci").Create(context.Background(), &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde", Namespace: "devtron-ci"}}, v1.CreateOptions{})
+	assert.Nil(t, err)
+
+	err = executor.TerminateWorkflow("5-ci-abcde", "devtron-ci", nil)
+	assert.Nil(t, err)
+	jobs, err := clientSet.BatchV1().Jobs("devtron-ci").List(context.Background(), v1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, jobs.Items, 0)
+
+	// already deleted workflow is not an error
+	err = executor.TerminateWorkflow("5-ci-abcde", "devtron-ci", nil)
+	assert.Nil(t, err)
+}
+
+func TestGetWorkflowStatusFromJob(t *testing.T) {
+	startTime := v1.Now()
+	tests := []struct {
+		name          string
+		job           *batchv1.Job
+		pod           *v12.Pod
+		wantPhase     v1alpha1.WorkflowPhase
+		wantNodeName  string
+		wantNodePhase v1alpha1.NodePhase
+		wantMessage   string
+	}{
+		{
+			name:          "job created, pod not scheduled yet",
+			job:           &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde"}},
+			wantPhase:     v1alpha1.WorkflowPending,
+			wantNodeName:  "5-ci-abcde",
+			wantNodePhase: v1alpha1.NodePending,
+		},
+		{
+			name: "pod running",
+			job: &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde"},
+				Status: batchv1.JobStatus{Active: 1, StartTime: &startTime}},
+			pod:           &v12.Pod{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde-xyz"}, Status: v12.PodStatus{Phase: v12.PodRunning}},
+			wantPhase:     v1alpha1.WorkflowRunning,
+			wantNodeName:  "5-ci-abcde-xyz",
+			wantNodePhase: v1alpha1.NodeRunning,
+		},
+		{
+			name: "job succeeded",
+			job: &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde"},
+				Status: batchv1.JobStatus{Succeeded: 1, StartTime: &startTime, CompletionTime: &startTime,
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v12.ConditionTrue}}}},
+			pod:           &v12.Pod{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde-xyz"}, Status: v12.PodStatus{Phase: v12.PodSucceeded}},
+			wantPhase:     v1alpha1.WorkflowSucceeded,
+			wantNodeName:  "5-ci-abcde-xyz",
+			wantNodePhase: v1alpha1.NodeSucceeded,
+		},
+		{
+			name: "job deadline exceeded, pod removed",
+			job: &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde"},
+				Status: batchv1.JobStatus{Failed: 1, StartTime: &startTime,
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v12.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"}}}},
+			wantPhase:     v1alpha1.WorkflowFailed,
+			wantNodeName:  "5-ci-abcde",
+			wantNodePhase: v1alpha1.NodeFailed,
+			wantMessage:   "Job was active longer than specified deadline",
+		},
+		{
+			name: "container failed",
+			job: &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde"},
+				Status: batchv1.JobStatus{Failed: 1, StartTime: &startTime,
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v12.ConditionTrue}}}},
+			pod: &v12.Pod{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde-xyz"}, Status: v12.PodStatus{Phase: v12.PodFailed,
+				ContainerStatuses: []v12.ContainerStatus{{State: v12.ContainerState{Terminated: &v12.ContainerStateTerminated{ExitCode: 2, Reason: "Error"}}}}}},
+			wantPhase:     v1alpha1.WorkflowFailed,
+			wantNodeName:  "5-ci-abcde-xyz",
+			wantNodePhase: v1alpha1.NodeFailed,
+			wantMessage:   "Error (exit code 2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflowStatus := GetWorkflowStatusFromJob(tt.job, tt.pod, CI_WORKFLOW_NAME)
+			assert.Equal(t, tt.wantPhase, workflowStatus.Phase)
+			node, ok := workflowStatus.Nodes[tt.wantNodeName]
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantNodePhase, node.Phase)
+			assert.Equal(t, tt.wantMessage, node.Message)
+			assert.Equal(t, CI_WORKFLOW_NAME, node.TemplateName)
+			assert.Equal(t, tt.job.Name, node.BoundaryID)
+		})
+	}
+}
+
+func TestSystemWorkflowExecutor_ResumeWorkflowWatches(t *testing.T) {
+	executor, clientSet := getSystemWorkflowExecutorForTest(t)
+	labels := map[string]string{WORKFLOW_PURPOSE_LABEL_KEY: CI_WORKFLOW_NAME, WORKFLOW_EXECUTOR_LABEL_KEY: WORKFLOW_EXECUTOR_SYSTEM}
+	runningJob := &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-running", Namespace: "devtron-ci", Labels: labels},
+		Status: batchv1.JobStatus{Active: 1}}
+	finishedJob := &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "6-ci-finished", Namespace: "devtron-ci", Labels: labels},
+		Status: batchv1.JobStatus{Succeeded: 1, Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v12.ConditionTrue}}}}
+	for _, job := range []*batchv1.Job{runningJob, finishedJob} {
+		_, err := clientSet.BatchV1().Jobs("devtron-ci").Create(context.Background(), job, v1.CreateOptions{})
+		assert.Nil(t, err)
+	}
+	clientSet.ClearActions()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	countJobWatches := func() int {
+		jobWatches := 0
+		for _, action := range clientSet.Actions() {
+			if action.GetVerb() == "watch" && action.GetResource().Resource == "jobs" {
+				jobWatches++
+			}
+		}
+		return jobWatches
+	}
+	err := executor.resumeWorkflowWatchesInCluster(watchCtx, &rest.Config{})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool { return countJobWatches() == 1 }, 5*time.Second, 50*time.Millisecond)
+	assert.True(t, executor.isJobReported(getJobKey(&rest.Config{}, finishedJob)))
+
+	// a running job is watched and a finished job reported only once across syncs
+	clientSet.ClearActions()
+	err = executor.resumeWorkflowWatchesInCluster(watchCtx, &rest.Config{})
+	assert.Nil(t, err)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 0, countJobWatches())
+	for _, action := range clientSet.Actions() {
+		assert.NotEqual(t, "pods", action.GetResource().Resource)
+	}
+
+	// the watch ends once the job is gone
+	err = clientSet.BatchV1().Jobs("devtron-ci").Delete(context.Background(), runningJob.Name, v1.DeleteOptions{})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool { return !executor.isJobWatched(getJobKey(&rest.Config{}, runningJob)) }, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestGetTimedOutWorkflowStatus(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde"}, Status: batchv1.JobStatus{Active: 1}}
+	runningStatus := GetWorkflowStatusFromJob(job, &v12.Pod{ObjectMeta: v1.ObjectMeta{Name: "5-ci-abcde-xyz"}, Status: v12.PodStatus{Phase: v12.PodRunning}}, CI_WORKFLOW_NAME)
+	workflowStatus := getTimedOutWorkflowStatus(job, CI_WORKFLOW_NAME, &runningStatus)
+	assert.Equal(t, v1alpha1.WorkflowFailed, workflowStatus.Phase)
+	assert.False(t, workflowStatus.FinishedAt.IsZero())
+	assert.Equal(t, v1alpha1.NodeFailed, workflowStatus.Nodes["5-ci-abcde-xyz"].Phase)
+	// last published status is left untouched
+	assert.Equal(t, v1alpha1.NodeRunning, runningStatus.Nodes["5-ci-abcde-xyz"].Phase)
+
+	workflowStatus = getTimedOutWorkflowStatus(job, CI_WORKFLOW_NAME, nil)
+	assert.Equal(t, v1alpha1.NodeFailed, workflowStatus.Nodes["5-ci-abcde"].Phase)
+}
+
+func TestGetWatchTimeout(t *testing.T) {
+	deadline := int64(600)
+	job := &batchv1.Job{Spec: batchv1.JobSpec{ActiveDeadlineSeconds: &deadline}}
+	assert.Equal(t, time.Duration(deadline+SYSTEM_WORKFLOW_WATCH_GRACE_SECONDS)*time.Second, getWatchTimeout(job))
+
+	startTime := v1.NewTime(time.Now().Add(-5 * time.Minute))
+	job.Status.StartTime = &startTime
+	timeout := getWatchTimeout(job)
+	assert.True(t, timeout <= time.Duration(deadline+SYSTEM_WORKFLOW_WATCH_GRACE_SECONDS-300)*time.Second)
+	assert.True(t, timeout > 0)
+}
@@ -26,8 +26,10 @@ import (
 	"github.com/devtron-labs/devtron/client/events"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/util/event"
 	"github.com/go-pg/pg"
@@ -47,6 +49,15 @@ type CiArtifactWebhookRequest struct {
 	UserId             int32           `json:"userId"`
 	IsArtifactUploaded bool            `json:"isArtifactUploaded"`
 	FailureReason      string          `json:"failureReason"`
+	// ImageSignatures are the signatures and attestations pushed by the image signing step of ci
+	ImageSignatures   []*bean.ImageSignatureDto `json:"imageSignatures,omitempty"`
+	BuildCacheMetrics *bean.BuildCacheMetrics   `json:"buildCacheMetrics,omitempty"`
+	// ManifestListDigest and PlatformDigests are reported for multi-arch builds
+	ManifestListDigest string                           `json:"manifestListDigest,omitempty"`
+	PlatformDigests    []repository.ImagePlatformDigest `json:"platformDigests,omitempty"`
+	// Sboms are sent inline by external ci, SbomRefs are the sboms the ci runner uploaded to blob storage
+	Sboms    []*bean.SbomDocumentDto `json:"sboms,omitempty"`
+	SbomRefs []*bean.SbomRefDto      `json:"sbomRefs,omitempty"`
 }
 
 type WebhookService interface {
@@ -66,6 +77,9 @@ type WebhookServiceImpl struct {
 	eventFactory         client.EventFactory
 	workflowDagExecutor  WorkflowDagExecutor
 	ciHandler            CiHandler
+	imageSigningService  ImageSigningService
+	ciBuildCacheService  CiBuildCacheService
+	sbomService          SbomService
 }
 
 func NewWebhookServiceImpl(
@@ -75,7 +89,8 @@ func NewWebhookServiceImpl(
 	appService app.AppService, eventClient client.EventClient,
 	eventFactory client.EventFactory,
 	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
-	workflowDagExecutor WorkflowDagExecutor, ciHandler CiHandler) *WebhookServiceImpl {
+	workflowDagExecutor WorkflowDagExecutor, ciHandler CiHandler, imageSigningService ImageSigningService,
+	ciBuildCacheService CiBuildCacheService, sbomService SbomService) *WebhookServiceImpl {
 	return &WebhookServiceImpl{
 		ciArtifactRepository: ciArtifactRepository,
 		logger:               logger,
@@ -86,6 +101,9 @@ func NewWebhookServiceImpl(
 		ciWorkflowRepository: ciWorkflowRepository,
 		workflowDagExecutor:  workflowDagExecutor,
 		ciHandler:            ciHandler,
+		imageSigningService:  imageSigningService,
+		ciBuildCacheService:  ciBuildCacheService,
+		sbomService:          sbomService,
 	}
 }
 
@@ -130,6 +148,11 @@ func (impl WebhookServiceImpl) HandleCiStepFailedEvent(ciPipelineId int, request
 		impl.logger.Errorw("unable to find pipeline", "ID", ciPipelineId, "err", err)
 		return err
 	}
+	err = impl.ciBuildCacheService.SaveBuildCacheMetrics(savedWorkflow, request.BuildCacheMetrics)
+	if err != nil {
+		// cache bookkeeping does not fail the event
+		impl.logger.Errorw("error in saving build cache metrics", "wfId", savedWorkflow.Id, "err", err)
+	}
 
 	go impl.WriteCIStepFailedEvent(pipeline, request, savedWorkflow)
 	return nil
@@ -150,6 +173,10 @@ func (impl WebhookServiceImpl) HandleCiSuccessEvent(ciPipelineId int, request *C
 			impl.logger.Errorw("update wf failed for id ", "err", err)
 			return 0, err
 		}
+		err = impl.ciBuildCacheService.SaveBuildCacheMetrics(savedWorkflow, request.BuildCacheMetrics)
+		if err != nil {
+			impl.logger.Errorw("error in saving build cache metrics", "wfId", savedWorkflow.Id, "err", err)
+		}
 	}
 
 	pipeline, err := impl.ciPipelineRepository.FindByCiAndAppDetailsById(ciPipelineId)
@@ -184,6 +211,8 @@ func (impl WebhookServiceImpl) HandleCiSuccessEvent(ciPipelineId int, request *C
 		ScanEnabled:        pipeline.ScanEnabled,
 		Scanned:            false,
 		IsArtifactUploaded: request.IsArtifactUploaded,
+		ManifestListDigest: request.ManifestListDigest,
+		PlatformDigests:    request.PlatformDigests,
 		AuditLog:           sql.AuditLog{CreatedBy: request.UserId, UpdatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now()},
 	}
 	if pipeline.ScanEnabled {
@@ -193,6 +222,23 @@ func (impl WebhookServiceImpl) HandleCiSuccessEvent(ciPipelineId int, request *C
 		impl.logger.Errorw("error in saving material", "err", err)
 		return 0, err
 	}
+	if len(request.ImageSignatures) > 0 {
+		// signatures are tied to the image digest, child pipelines share them through the digest
+		if err = impl.imageSigningService.SaveImageSignatures(artifact, request.ImageSignatures); err != nil {
+			return 0, err
+		}
+	}
+	if len(request.Sboms) > 0 {
+		// an sbom not saved does not fail the build, it can be uploaded again by the image scanner
+		if err = impl.sbomService.SaveSboms(artifact, request.Sboms, security.SBOM_SOURCE_CI, request.UserId); err != nil {
+			impl.logger.Errorw("error in saving sboms of artifact", "err", err, "artifactId", artifact.Id)
+		}
+	}
+	if len(request.SbomRefs) > 0 && request.WorkflowId != nil {
+		if err = impl.sbomService.SaveSbomRefs(artifact, ciPipelineId, *request.WorkflowId, request.SbomRefs, request.UserId); err != nil {
+			impl.logger.Errorw("error in saving sboms of artifact", "err", err, "artifactId", artifact.Id)
+		}
+	}
 
 	childrenCi, err := impl.ciPipelineRepository.FindByParentCiPipelineId(ciPipelineId)
 	if err != nil && !util2.IsErrNoRows(err) {
@@ -212,6 +258,8 @@ func (impl WebhookServiceImpl) HandleCiSuccessEvent(ciPipelineId int, request *C
 			ScanEnabled:        ci.ScanEnabled,
 			Scanned:            false,
 			IsArtifactUploaded: request.IsArtifactUploaded,
+			ManifestListDigest: request.ManifestListDigest,
+			PlatformDigests:    request.PlatformDigests,
 			AuditLog:           sql.AuditLog{CreatedBy: request.UserId, UpdatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now()},
 		}
 		if ci.ScanEnabled {
@@ -286,12 +334,25 @@ func (impl WebhookServiceImpl) HandleExternalCiWebhook(externalCiId int, request
 		ScanEnabled:          false,
 		Scanned:              false,
 		IsArtifactUploaded:   request.IsArtifactUploaded,
+		ManifestListDigest:   request.ManifestListDigest,
+		PlatformDigests:      request.PlatformDigests,
 		AuditLog:             sql.AuditLog{CreatedBy: request.UserId, UpdatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now()},
 	}
 	if err = impl.ciArtifactRepository.Save(artifact); err != nil {
 		impl.logger.Errorw("error in saving material", "err", err)
 		return 0, err
 	}
+	if len(request.ImageSignatures) > 0 {
+		if err = impl.imageSigningService.SaveImageSignatures(artifact, request.ImageSignatures); err != nil {
+			return 0, err
+		}
+	}
+	if len(request.Sboms) > 0 {
+		// an sbom not saved does not fail the build, it can be uploaded again by the image scanner
+		if err = impl.sbomService.SaveSboms(artifact, request.Sboms, security.SBOM_SOURCE_CI, request.UserId); err != nil {
+			impl.logger.Errorw("error in saving sboms of artifact", "err", err, "artifactId", artifact.Id)
+		}
+	}
 
 	hasAnyTriggered, err := impl.workflowDagExecutor.HandleWebhookExternalCiEvent(artifact, request.UserId, externalCiId, auth)
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
 	gitSensorClient "github.com/devtron-labs/devtron/client/gitSensor"
 	"github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
@@ -32,6 +33,7 @@ import (
 	"github.com/devtron-labs/devtron/util/argo"
 	util5 "github.com/devtron-labs/devtron/util/k8s"
 	"go.opentelemetry.io/otel"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -76,6 +78,8 @@ type WorkflowDagExecutor interface {
 	StopStartApp(stopRequest *StopAppRequest, ctx context.Context) (int, error)
 	TriggerBulkHibernateAsync(request StopDeploymentGroupRequest, ctx context.Context) (interface{}, error)
 	RotatePods(ctx context.Context, podRotateRequest *PodRotateRequest) (*k8s.RotatePodResponse, error)
+	TriggerDueScheduledDeployments()
+	ProcessCanaryAnalysis()
 }
 
 type WorkflowDagExecutorImpl struct {
@@ -113,6 +117,12 @@ type WorkflowDagExecutorImpl struct {
 	k8sCommonService              k8s.K8sCommonService
 	pipelineStageRepository       repository4.PipelineStageRepository
 	pipelineStageService          PipelineStageService
+	deploymentWindowService       deploymentWindow.DeploymentWindowService
+	deploymentApprovalService     DeploymentApprovalService
+	canaryAnalysisService         CanaryAnalysisService
+	imageSigningService           ImageSigningService
+	previewEnvironmentRepository  pipelineConfig.PreviewEnvironmentRepository
+	platformValidationService     ImagePlatformValidationService
 }
 
 const (
@@ -206,7 +216,12 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
 	appLabelRepository pipelineConfig.AppLabelRepository, gitSensorGrpcClient gitSensorClient.Client,
 	pipelineStageRepository repository4.PipelineStageRepository,
-	pipelineStageService PipelineStageService, k8sCommonService k8s.K8sCommonService) *WorkflowDagExecutorImpl {
+	pipelineStageService PipelineStageService, k8sCommonService k8s.K8sCommonService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	deploymentApprovalService DeploymentApprovalService,
+	canaryAnalysisService CanaryAnalysisService, imageSigningService ImageSigningService,
+	previewEnvironmentRepository pipelineConfig.PreviewEnvironmentRepository,
+	platformValidationService ImagePlatformValidationService) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:            pipelineRepository,
 		cdWorkflowRepository:          cdWorkflowRepository,
@@ -241,6 +256,12 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		k8sCommonService:              k8sCommonService,
 		pipelineStageRepository:       pipelineStageRepository,
 		pipelineStageService:          pipelineStageService,
+		deploymentWindowService:       deploymentWindowService,
+		deploymentApprovalService:     deploymentApprovalService,
+		canaryAnalysisService:         canaryAnalysisService,
+		imageSigningService:           imageSigningService,
+		previewEnvironmentRepository:  previewEnvironmentRepository,
+		platformValidationService:     platformValidationService,
 	}
 	err := wde.Subscribe()
 	if err != nil {
@@ -306,7 +327,12 @@ func (impl *WorkflowDagExecutorImpl) HandleCiSuccessEvent(artifact *repository.C
 		impl.logger.Errorw("error in fetching cd pipeline", "pipelineId", artifact.PipelineId, "err", err)
 		return err
 	}
+	previewTemplatePipelineIds := impl.getPreviewTemplatePipelineIds(artifact, pipelines)
 	for _, pipeline := range pipelines {
+		if previewTemplatePipelineIds[pipeline.Id] {
+			// pull request builds are deployed to the preview environments of the pipeline, not to its own environment
+			continue
+		}
 		err = impl.triggerStage(nil, pipeline, artifact, applyAuth, triggeredBy)
 		if err != nil {
 			impl.logger.Debugw("error on trigger cd pipeline", "err", err)
@@ -315,6 +341,27 @@ func (impl *WorkflowDagExecutorImpl) HandleCiSuccessEvent(artifact *repository.C
 	return nil
 }
 
+func (impl *WorkflowDagExecutorImpl) getPreviewTemplatePipelineIds(artifact *repository.CiArtifact, pipelines []*pipelineConfig.Pipeline) map[int]bool {
+	previewTemplatePipelineIds := make(map[int]bool)
+	pullRequest, err := GetPullRequest(artifact.MaterialInfo)
+	if err != nil || pullRequest == nil {
+		return previewTemplatePipelineIds
+	}
+	var pipelineIds []int
+	for _, pipeline := range pipelines {
+		pipelineIds = append(pipelineIds, pipeline.Id)
+	}
+	configs, err := impl.previewEnvironmentRepository.FindActiveConfigsByPipelineIds(pipelineIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching preview environment configs", "pipelineIds", pipelineIds, "err", err)
+		return previewTemplatePipelineIds
+	}
+	for _, config := range configs {
+		previewTemplatePipelineIds[config.PipelineId] = true
+	}
+	return previewTemplatePipelineIds
+}
+
 func (impl *WorkflowDagExecutorImpl) HandleWebhookExternalCiEvent(artifact *repository.CiArtifact, triggeredBy int32, externalCiId int, auth func(email string, projectObject string, envObject string) bool) (bool, error) {
 	hasAnyTriggered := false
 	appWorkflowMappings, err := impl.appWorkflowRepository.FindWFCDMappingByExternalCiId(externalCiId)
@@ -492,6 +539,7 @@ func (impl *WorkflowDagExecutorImpl) TriggerPreStage(ctx context.Context, cdWf *
 		}
 		impl.logger.Debugw("env", "env", env)
 		runner.Namespace = env.Namespace
+		runner.ExecutorType = impl.getWorkflowExecutorType(env)
 	}
 	_, span := otel.Tracer("orchestrator").Start(ctx, "cdWorkflowRepository.SaveWorkFlowRunner")
 	_, err = impl.cdWorkflowRepository.SaveWorkFlowRunner(runner)
@@ -526,6 +574,14 @@ func (impl *WorkflowDagExecutorImpl) TriggerPreStage(ctx context.Context, cdWf *
 	return nil
 }
 
+// getWorkflowExecutorType gives the executor configured on the cluster the stage runs in, falling back to the global one
+func (impl *WorkflowDagExecutorImpl) getWorkflowExecutorType(env *repository2.Environment) pipelineConfig.WorkflowExecutorType {
+	if env != nil && env.Cluster != nil && len(env.Cluster.WorkflowExecutorType) > 0 {
+		return pipelineConfig.WorkflowExecutorType(env.Cluster.WorkflowExecutorType)
+	}
+	return impl.cdConfig.CdWorkflowExecutorType
+}
+
 func (impl *WorkflowDagExecutorImpl) sendPreStageNotification(ctx context.Context, cdWf *pipelineConfig.CdWorkflow, pipeline *pipelineConfig.Pipeline) error {
 	wfr, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(ctx, cdWf.Id, bean.CD_WORKFLOW_TYPE_PRE)
 	if err != nil {
@@ -579,6 +635,7 @@ func (impl *WorkflowDagExecutorImpl) TriggerPostStage(cdWf *pipelineConfig.CdWor
 			return err
 		}
 		runner.Namespace = env.Namespace
+		runner.ExecutorType = impl.getWorkflowExecutorType(env)
 	}
 
 	_, err = impl.cdWorkflowRepository.SaveWorkFlowRunner(runner)
@@ -1113,6 +1170,17 @@ func (impl *WorkflowDagExecutorImpl) HandleDeploymentSuccessEvent(gitHash string
 		return err
 	}
 
+	if pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_STOP && pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_START {
+		deployRunner, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(context.Background(), cdWorkflow.Id, bean.CD_WORKFLOW_TYPE_DEPLOY)
+		if err == nil {
+			err = impl.canaryAnalysisService.ScheduleAnalysis(pipelineOverride, deployRunner.Id)
+		}
+		if err != nil && !util.IsErrNoRows(err) {
+			// analysis is best effort, post stage and children are triggered irrespective of it
+			impl.logger.Errorw("error in scheduling canary analysis", "err", err, "pipelineOverrideId", pipelineOverride.Id)
+		}
+	}
+
 	postStageStepType, err := impl.pipelineStageRepository.GetCdStageByCdPipelineIdAndStageType(pipelineOverride.Pipeline.Id, repository4.PIPELINE_STAGE_TYPE_POST_CD)
 	if err != nil && err != pg.ErrNoRows {
 		impl.logger.Errorw("error in fetching preStageStepType in GetCdStageByCdPipelineIdAndStageType ", "cdPipelineId", pipelineOverride.Pipeline, "err", err)
@@ -1195,6 +1263,35 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		}
 	}
 
+	//automatic deployments are held back till the deployment window of the environment opens
+	queued, err := impl.queueDeploymentIfWindowClosed(cdWf, artifact, pipeline, triggeredBy)
+	if err != nil || queued {
+		return err
+	}
+
+	//artifacts which are not approved are not deployed automatically, an approval request is raised for them instead
+	approvalRequired, approvalRequestId, err := impl.deploymentApprovalService.ConsumeApproval(pipeline.Id, artifact.Id)
+	if err != nil {
+		return err
+	}
+	if approvalRequired && approvalRequestId == 0 {
+		_, err = impl.deploymentApprovalService.RaiseApprovalRequest(&bean3.DeploymentApprovalRequestDto{
+			PipelineId:   pipeline.Id,
+			CiArtifactId: artifact.Id,
+			Comment:      "raised on automatic deployment",
+			UserId:       triggeredBy,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in raising approval request for auto deployment", "err", err, "pipelineId", pipeline.Id, "artifactId", artifact.Id)
+			return err
+		}
+		impl.logger.Infow("artifact not approved, auto deployment skipped and approval requested", "pipelineId", pipeline.Id, "artifactId", artifact.Id)
+		return nil
+	}
+	//the approval is taken up front so that concurrent triggers cannot use it twice, it is given back unless the deployment goes through
+	approvalUsed := false
+	defer impl.releaseApprovalIfUnused(approvalRequestId, &approvalUsed)
+
 	//setting triggeredAt variable to have consistent data for various audit log places in db for deployment time
 	triggeredAt := time.Now()
 
@@ -1228,6 +1325,14 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 	runner.CdWorkflow = &pipelineConfig.CdWorkflow{
 		Pipeline: pipeline,
 	}
+	if approvalRequestId > 0 {
+		err = impl.deploymentApprovalService.LinkApprovalToWorkflowRunner(approvalRequestId, savedWfr.Id)
+		if err != nil {
+			impl.logger.Errorw("error in linking approval to auto deployment", "err", err, "approvalRequestId", approvalRequestId, "wfrId", savedWfr.Id)
+			_ = impl.updatePreviousDeploymentStatus(runner, pipeline.Id, err, triggeredAt, triggeredBy)
+			return err
+		}
+	}
 	// creating cd pipeline status timeline for deployment initialisation
 	timeline := &pipelineConfig.PipelineStatusTimeline{
 		CdWorkflowRunnerId: runner.Id,
@@ -1310,8 +1415,27 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		}
 		return nil
 	}
+	verified, reason, err := impl.imageSigningService.VerifyArtifact(artifact, pipeline.EnvironmentId, pipeline.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in verifying image signature", "err", err, "artifactId", artifact.Id, "pipelineId", pipeline.Id)
+		return err
+	}
+	if !verified {
+		impl.markDeploymentBlocked(runner, fmt.Sprintf("Image signature verification failed: %s", reason), pipelineConfig.TIMELINE_DESCRIPTION_UNVERIFIED_IMAGE, triggeredBy)
+		return nil
+	}
+	platformsValid, reason, err := impl.platformValidationService.ValidateArtifactPlatforms(artifact, pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in validating image platforms", "err", err, "artifactId", artifact.Id, "pipelineId", pipeline.Id)
+		return err
+	}
+	if !platformsValid {
+		impl.markDeploymentBlocked(runner, fmt.Sprintf("Image platform validation failed: %s", reason), pipelineConfig.TIMELINE_DESCRIPTION_UNSUPPORTED_IMAGE_PLATFORM, triggeredBy)
+		return nil
+	}
 
 	err = impl.appService.TriggerCD(artifact, cdWf.Id, savedWfr.Id, pipeline, triggeredAt)
+	approvalUsed = err == nil
 	err1 := impl.updatePreviousDeploymentStatus(runner, pipeline.Id, err, triggeredAt, triggeredBy)
 	if err1 != nil || err != nil {
 		impl.logger.Errorw("error while update previous cd workflow runners", "err", err, "runner", runner, "pipelineId", pipeline.Id)
@@ -1320,6 +1444,43 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 	return nil
 }
 
+// releaseApprovalIfUnused gives back the consumed approval when the trigger was blocked by a later gate or failed
+func (impl *WorkflowDagExecutorImpl) releaseApprovalIfUnused(approvalRequestId int, approvalUsed *bool) {
+	if approvalRequestId == 0 || *approvalUsed {
+		return
+	}
+	err := impl.deploymentApprovalService.ReleaseApproval(approvalRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in releasing unused deployment approval", "err", err, "approvalRequestId", approvalRequestId)
+	}
+}
+
+// markDeploymentBlocked fails the runner which was blocked by a policy on the image before deployment
+func (impl *WorkflowDagExecutorImpl) markDeploymentBlocked(runner *pipelineConfig.CdWorkflowRunner, message string, timelineDescription string, triggeredBy int32) {
+	runner.Status = pipelineConfig.WorkflowFailed
+	runner.Message = message
+	runner.FinishedOn = time.Now()
+	runner.UpdatedOn = time.Now()
+	runner.UpdatedBy = triggeredBy
+	err := impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating status", "err", err, "runnerId", runner.Id)
+	}
+	cdMetrics := util4.CDMetrics{
+		AppName:         runner.CdWorkflow.Pipeline.DeploymentAppName,
+		Status:          runner.Status,
+		DeploymentType:  runner.CdWorkflow.Pipeline.DeploymentAppType,
+		EnvironmentName: runner.CdWorkflow.Pipeline.Environment.Name,
+		Time:            time.Since(runner.StartedOn).Seconds() - time.Since(runner.FinishedOn).Seconds(),
+	}
+	util4.TriggerCDMetrics(cdMetrics, impl.cdConfig.ExposeCDMetrics)
+	timeline := impl.pipelineStatusTimelineService.GetTimelineDbObjectByTimelineStatusAndTimelineDescription(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, timelineDescription, 1)
+	err = impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for blocked deployment", "err", err, "timeline", timeline)
+	}
+}
+
 func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunner *pipelineConfig.CdWorkflowRunner, pipelineId int, err error, triggeredAt time.Time, triggeredBy int32) error {
 	if err != nil {
 		//creating cd pipeline status timeline for deployment failed
@@ -1594,6 +1755,24 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			return 0, err
 		}
 	} else if overrideRequest.CdWorkflowType == bean.CD_WORKFLOW_TYPE_DEPLOY {
+		approvalRequired, approvalRequestId := false, 0
+		// automated rollbacks restore the previously healthy release and are not held back by deployment gates
+		if !overrideRequest.IsRollback {
+			err = impl.checkDeploymentWindowForManualTrigger(overrideRequest, cdPipeline)
+			if err != nil {
+				return 0, err
+			}
+			approvalRequired, approvalRequestId, err = impl.deploymentApprovalService.ConsumeApproval(cdPipeline.Id, overrideRequest.CiArtifactId)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if approvalRequired && approvalRequestId == 0 {
+			errMsg := "artifact is not approved for deployment on this pipeline, raise an approval request first"
+			return 0, &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: errMsg, UserMessage: errMsg}
+		}
+		approvalUsed := false
+		defer impl.releaseApprovalIfUnused(approvalRequestId, &approvalUsed)
 		if overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_UNKNOWN {
 			overrideRequest.DeploymentType = models.DEPLOYMENTTYPE_DEPLOY
 		}
@@ -1638,6 +1817,25 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 		runner.CdWorkflow = &pipelineConfig.CdWorkflow{
 			Pipeline: cdPipeline,
 		}
+		if overrideRequest.IsRollback {
+			impl.auditRollbackGateBypass(overrideRequest, cdPipeline, savedWfr.Id)
+		}
+		if overrideRequest.CanaryAnalysisRunId > 0 {
+			// saved before the deployment, so that the rollback is known as such when its analysis is scheduled
+			err = impl.canaryAnalysisService.SetRollbackRunner(overrideRequest.CanaryAnalysisRunId, savedWfr.Id)
+			if err != nil {
+				_ = impl.updatePreviousDeploymentStatus(runner, cdPipeline.Id, err, triggeredAt, overrideRequest.UserId)
+				return 0, err
+			}
+		}
+		if approvalRequestId > 0 {
+			err = impl.deploymentApprovalService.LinkApprovalToWorkflowRunner(approvalRequestId, savedWfr.Id)
+			if err != nil {
+				impl.logger.Errorw("error in linking approval to deployment", "err", err, "approvalRequestId", approvalRequestId, "wfrId", savedWfr.Id)
+				_ = impl.updatePreviousDeploymentStatus(runner, cdPipeline.Id, err, triggeredAt, overrideRequest.UserId)
+				return 0, err
+			}
+		}
 		overrideRequest.CdWorkflowId = cdWorkflowId
 		// creating cd pipeline status timeline for deployment initialisation
 		timeline := impl.pipelineStatusTimelineService.GetTimelineDbObjectByTimelineStatusAndTimelineDescription(savedWfr.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_INITIATED, pipelineConfig.TIMELINE_DESCRIPTION_DEPLOYMENT_INITIATED, overrideRequest.UserId)
@@ -1685,9 +1883,30 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			}
 			return 0, fmt.Errorf("found vulnerability for image digest %s", artifact.ImageDigest)
 		}
+		verified, reason, err := impl.imageSigningService.VerifyArtifact(artifact, cdPipeline.EnvironmentId, cdPipeline.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in verifying image signature", "err", err, "artifactId", artifact.Id, "pipelineId", cdPipeline.Id)
+			return 0, err
+		}
+		if !verified {
+			impl.markDeploymentBlocked(runner, fmt.Sprintf("Image signature verification failed: %s", reason), pipelineConfig.TIMELINE_DESCRIPTION_UNVERIFIED_IMAGE, overrideRequest.UserId)
+			errMsg := fmt.Sprintf("image signature verification failed: %s", reason)
+			return 0, &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: errMsg, UserMessage: errMsg}
+		}
+		platformsValid, reason, err := impl.platformValidationService.ValidateArtifactPlatforms(artifact, cdPipeline)
+		if err != nil {
+			impl.logger.Errorw("error in validating image platforms", "err", err, "artifactId", artifact.Id, "pipelineId", cdPipeline.Id)
+			return 0, err
+		}
+		if !platformsValid {
+			impl.markDeploymentBlocked(runner, fmt.Sprintf("Image platform validation failed: %s", reason), pipelineConfig.TIMELINE_DESCRIPTION_UNSUPPORTED_IMAGE_PLATFORM, overrideRequest.UserId)
+			errMsg := fmt.Sprintf("image platform validation failed: %s", reason)
+			return 0, &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: errMsg, UserMessage: errMsg}
+		}
 		_, span = otel.Tracer("orchestrator").Start(ctx, "appService.TriggerRelease")
 		releaseId, _, err = impl.appService.TriggerRelease(overrideRequest, ctx, triggeredAt, overrideRequest.UserId)
 		span.End()
+		approvalUsed = err == nil
 
 		if overrideRequest.DeploymentAppType == util.PIPELINE_DEPLOYMENT_TYPE_MANIFEST_DOWNLOAD {
 			runner := &pipelineConfig.CdWorkflowRunner{
@@ -1751,11 +1970,216 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 	return releaseId, err
 }
 
+func (impl *WorkflowDagExecutorImpl) queueDeploymentIfWindowClosed(cdWf *pipelineConfig.CdWorkflow, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, triggeredBy int32) (bool, error) {
+	state, err := impl.deploymentWindowService.GetDeploymentWindowState(pipeline.EnvironmentId, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window state", "err", err, "pipelineId", pipeline.Id)
+		return false, err
+	}
+	if state.Allowed {
+		return false, nil
+	}
+	if state.NextOpenAt == nil {
+		impl.logger.Warnw("deployment blocked and no upcoming deployment window found", "pipelineId", pipeline.Id, "reason", state.Reason)
+		return true, &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: state.Reason, UserMessage: "deployment blocked, " + state.Reason}
+	}
+	cdWorkflowId := 0
+	if cdWf != nil {
+		cdWorkflowId = cdWf.Id
+	}
+	err = impl.deploymentWindowService.QueueDeployment(pipeline.Id, artifact.Id, cdWorkflowId, *state.NextOpenAt, triggeredBy)
+	if err != nil {
+		impl.logger.Errorw("error in queueing deployment", "err", err, "pipelineId", pipeline.Id, "artifactId", artifact.Id)
+		return false, err
+	}
+	impl.logger.Infow("deployment queued till deployment window opens", "pipelineId", pipeline.Id, "artifactId", artifact.Id, "reason", state.Reason, "nextOpenAt", state.NextOpenAt)
+	return true, nil
+}
+
+func (impl *WorkflowDagExecutorImpl) checkDeploymentWindowForManualTrigger(overrideRequest *bean.ValuesOverrideRequest, cdPipeline *pipelineConfig.Pipeline) error {
+	state, err := impl.deploymentWindowService.GetDeploymentWindowState(cdPipeline.EnvironmentId, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window state", "err", err, "pipelineId", cdPipeline.Id)
+		return err
+	}
+	if state.Allowed {
+		return nil
+	}
+	if !overrideRequest.DeploymentWindowOverride {
+		userMessage := "deployment blocked, " + state.Reason
+		if state.NextOpenAt != nil {
+			userMessage = fmt.Sprintf("%s, next deployment window opens at %s", userMessage, state.NextOpenAt.Format(time.RFC3339))
+		}
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: state.Reason, UserMessage: userMessage}
+	}
+	isSuperAdmin, err := impl.user.IsSuperAdmin(int(overrideRequest.UserId))
+	if err != nil {
+		impl.logger.Errorw("error in checking super admin access", "err", err, "userId", overrideRequest.UserId)
+		return err
+	}
+	if !isSuperAdmin {
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, Code: "403", InternalMessage: "deployment window override by non super admin", UserMessage: "only super admin can override deployment window"}
+	}
+	err = impl.deploymentWindowService.SaveOverrideAudit(cdPipeline.Id, cdPipeline.EnvironmentId, overrideRequest.CiArtifactId, state.Reason, overrideRequest.DeploymentWindowOverrideReason, overrideRequest.UserId)
+	if err != nil {
+		return err
+	}
+	impl.logger.Infow("deployment window overridden", "pipelineId", cdPipeline.Id, "userId", overrideRequest.UserId, "reason", overrideRequest.DeploymentWindowOverrideReason)
+	return nil
+}
+
+// TriggerDueScheduledDeployments deploys scheduled and queued artifacts whose time has come, if the deployment
+// window is closed again by then they are moved to the next opening
+func (impl *WorkflowDagExecutorImpl) TriggerDueScheduledDeployments() {
+	scheduledDeployments, err := impl.deploymentWindowService.ClaimDueScheduledDeployments(time.Now())
+	if err != nil {
+		return
+	}
+	for _, scheduledDeployment := range scheduledDeployments {
+		impl.triggerScheduledDeployment(scheduledDeployment)
+	}
+}
+
+func (impl *WorkflowDagExecutorImpl) triggerScheduledDeployment(scheduledDeployment *deploymentWindow.ScheduledDeployment) {
+	cdPipeline, err := impl.pipelineRepository.FindById(scheduledDeployment.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline for scheduled deployment", "err", err, "scheduledDeployment", scheduledDeployment)
+		_ = impl.deploymentWindowService.UpdateScheduledDeploymentStatus(scheduledDeployment, deploymentWindow.SCHEDULED_DEPLOYMENT_STATUS_FAILED, "pipeline not found")
+		return
+	}
+	state, err := impl.deploymentWindowService.GetDeploymentWindowState(cdPipeline.EnvironmentId, time.Now())
+	if err != nil {
+		_ = impl.deploymentWindowService.RescheduleDeployment(scheduledDeployment, scheduledDeployment.ScheduledAt, "error in evaluating deployment window")
+		return
+	}
+	if !state.Allowed {
+		if state.NextOpenAt == nil {
+			_ = impl.deploymentWindowService.UpdateScheduledDeploymentStatus(scheduledDeployment, deploymentWindow.SCHEDULED_DEPLOYMENT_STATUS_FAILED, state.Reason)
+		} else {
+			_ = impl.deploymentWindowService.RescheduleDeployment(scheduledDeployment, *state.NextOpenAt, state.Reason)
+		}
+		return
+	}
+	if scheduledDeployment.DeploymentType == deploymentWindow.SCHEDULED_DEPLOYMENT_TYPE_QUEUED {
+		err = impl.triggerQueuedDeployment(scheduledDeployment, cdPipeline)
+	} else {
+		ctx := context.Background()
+		if util.IsAcdApp(cdPipeline.DeploymentAppType) {
+			ctx, err = impl.buildACDContext()
+		}
+		if err == nil {
+			overrideRequest := &bean.ValuesOverrideRequest{
+				PipelineId:           cdPipeline.Id,
+				AppId:                cdPipeline.AppId,
+				CiArtifactId:         scheduledDeployment.CiArtifactId,
+				CdWorkflowType:       bean.CD_WORKFLOW_TYPE_DEPLOY,
+				DeploymentWithConfig: bean.DEPLOYMENT_CONFIG_TYPE_LAST_SAVED,
+				UserId:               scheduledDeployment.TriggeredBy,
+			}
+			_, err = impl.ManualCdTrigger(overrideRequest, ctx)
+		}
+	}
+	if err != nil {
+		impl.logger.Errorw("error in triggering scheduled deployment", "err", err, "scheduledDeployment", scheduledDeployment)
+		_ = impl.deploymentWindowService.UpdateScheduledDeploymentStatus(scheduledDeployment, deploymentWindow.SCHEDULED_DEPLOYMENT_STATUS_FAILED, err.Error())
+		return
+	}
+	_ = impl.deploymentWindowService.UpdateScheduledDeploymentStatus(scheduledDeployment, deploymentWindow.SCHEDULED_DEPLOYMENT_STATUS_TRIGGERED, "")
+}
+
+func (impl *WorkflowDagExecutorImpl) triggerQueuedDeployment(scheduledDeployment *deploymentWindow.ScheduledDeployment, cdPipeline *pipelineConfig.Pipeline) error {
+	artifact, err := impl.ciArtifactRepository.Get(scheduledDeployment.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "err", err, "artifactId", scheduledDeployment.CiArtifactId)
+		return err
+	}
+	var cdWf *pipelineConfig.CdWorkflow
+	if scheduledDeployment.CdWorkflowId > 0 {
+		cdWf, err = impl.cdWorkflowRepository.FindById(scheduledDeployment.CdWorkflowId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching cd workflow", "err", err, "cdWorkflowId", scheduledDeployment.CdWorkflowId)
+			return err
+		}
+	}
+	return impl.TriggerDeployment(cdWf, artifact, cdPipeline, true, scheduledDeployment.TriggeredBy)
+}
+
 type BulkTriggerRequest struct {
 	CiArtifactId int `sql:"ci_artifact_id"`
 	PipelineId   int `sql:"pipeline_id"`
 }
 
+// ProcessCanaryAnalysis evaluates due canary analysis runs and rolls back deployments whose analysis failed to the
+// release deployed before them
+func (impl *WorkflowDagExecutorImpl) ProcessCanaryAnalysis() {
+	rollbackRuns := impl.canaryAnalysisService.EvaluateDueRuns()
+	for _, run := range rollbackRuns {
+		_, err := impl.rollbackCanaryDeployment(run)
+		impl.canaryAnalysisService.MarkRollbackTriggered(run, err)
+	}
+}
+
+func (impl *WorkflowDagExecutorImpl) rollbackCanaryDeployment(run *pipelineConfig.CanaryAnalysisRun) (int, error) {
+	rollbackOverride, err := impl.pipelineOverrideRepository.FindById(run.RollbackPipelineOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline override to roll back to", "err", err, "pipelineOverrideId", run.RollbackPipelineOverrideId)
+		return 0, err
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(run.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline to roll back", "err", err, "pipelineId", run.PipelineId)
+		return 0, err
+	}
+	ctx := context.Background()
+	if util.IsAcdApp(cdPipeline.DeploymentAppType) {
+		ctx, err = impl.buildACDContext()
+		if err != nil {
+			impl.logger.Errorw("error in building acd context for rollback", "err", err, "pipelineId", run.PipelineId)
+			return 0, err
+		}
+	}
+	// the rollback is attributed to the user who deployed the release which failed the analysis
+	userId := CANARY_ANALYSIS_SYSTEM_USER_ID
+	failedRunner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(run.CdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Warnw("error in fetching analysed workflow runner, attributing rollback to system user", "err", err, "wfrId", run.CdWorkflowRunnerId)
+	} else if failedRunner.TriggeredBy > 0 {
+		userId = failedRunner.TriggeredBy
+	}
+	overrideRequest := &bean.ValuesOverrideRequest{
+		PipelineId:                            run.PipelineId,
+		CiArtifactId:                          rollbackOverride.CiArtifactId,
+		CdWorkflowType:                        bean.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentWithConfig:                  bean.DEPLOYMENT_CONFIG_TYPE_SPECIFIC_TRIGGER,
+		WfrIdForDeploymentWithSpecificTrigger: run.RollbackCdWorkflowRunnerId,
+		IsRollback:                            true,
+		CanaryAnalysisRunId:                   run.Id,
+		RollbackReason:                        fmt.Sprintf("automated rollback after canary analysis run %d failed: %s", run.Id, run.Message),
+		UserId:                                userId,
+	}
+	impl.logger.Infow("rolling back deployment after failed canary analysis", "runId", run.Id, "pipelineId", run.PipelineId, "rollbackPipelineOverrideId", rollbackOverride.Id, "userId", userId)
+	return impl.ManualCdTrigger(overrideRequest, ctx)
+}
+
+// auditRollbackGateBypass records the deployment window and approval gates an automated rollback skipped, next to the
+// regular window overrides and approvals. Failures are only logged so that the audit never holds back a rollback.
+func (impl *WorkflowDagExecutorImpl) auditRollbackGateBypass(overrideRequest *bean.ValuesOverrideRequest, cdPipeline *pipelineConfig.Pipeline, wfrId int) {
+	state, err := impl.deploymentWindowService.GetDeploymentWindowState(cdPipeline.EnvironmentId, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment window for rollback audit", "err", err, "pipelineId", cdPipeline.Id)
+	} else if !state.Allowed {
+		err = impl.deploymentWindowService.SaveOverrideAudit(cdPipeline.Id, cdPipeline.EnvironmentId, overrideRequest.CiArtifactId, state.Reason, overrideRequest.RollbackReason, overrideRequest.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in auditing deployment window bypass of rollback", "err", err, "pipelineId", cdPipeline.Id)
+		}
+	}
+	err = impl.deploymentApprovalService.RecordApprovalBypass(cdPipeline.Id, overrideRequest.CiArtifactId, wfrId, overrideRequest.RollbackReason, overrideRequest.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in auditing approval bypass of rollback", "err", err, "pipelineId", cdPipeline.Id, "wfrId", wfrId)
+	}
+	impl.logger.Infow("deployment gates bypassed for rollback", "pipelineId", cdPipeline.Id, "wfrId", wfrId, "reason", overrideRequest.RollbackReason)
+}
+
 func (impl *WorkflowDagExecutorImpl) TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) (interface{}, error) {
 	var cdWorkflows []*pipelineConfig.CdWorkflow
 	for _, request := range requests {
@@ -24,6 +24,7 @@ import (
 	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 	"github.com/argoproj/argo-workflows/v3/pkg/client/clientset/versioned"
 	v1alpha12 "github.com/argoproj/argo-workflows/v3/pkg/client/clientset/versioned/typed/workflow/v1alpha1"
+	"github.com/argoproj/argo-workflows/v3/workflow/common"
 	"github.com/argoproj/argo-workflows/v3/workflow/util"
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
 	bean3 "github.com/devtron-labs/devtron/api/bean"
@@ -53,7 +54,7 @@ type WorkflowService interface {
 	GetWorkflow(name string, namespace string, isExt bool, environment *repository2.Environment) (*v1alpha1.Workflow, error)
 	ListAllWorkflows(namespace string) (*v1alpha1.WorkflowList, error)
 	UpdateWorkflow(wf *v1alpha1.Workflow) (*v1alpha1.Workflow, error)
-	TerminateWorkflow(name string, namespace string, isExt bool, environment *repository2.Environment) error
+	TerminateWorkflow(executorType pipelineConfig.WorkflowExecutorType, name string, namespace string, isExt bool, environment *repository2.Environment) error
 }
 
 type CiCdTriggerEvent struct {
@@ -63,80 +64,85 @@ type CiCdTriggerEvent struct {
 }
 
 type WorkflowServiceImpl struct {
-	Logger              *zap.SugaredLogger
-	config              *rest.Config
-	ciConfig            *CiConfig
-	globalCMCSService   GlobalCMCSService
-	appService          app.AppService
-	configMapRepository chartConfig.ConfigMapRepository
-	k8sUtil             *k8s.K8sUtil
-	k8sCommonService    k8s2.K8sCommonService
+	Logger                 *zap.SugaredLogger
+	config                 *rest.Config
+	ciConfig               *CiConfig
+	globalCMCSService      GlobalCMCSService
+	appService             app.AppService
+	configMapRepository    chartConfig.ConfigMapRepository
+	k8sUtil                *k8s.K8sUtil
+	k8sCommonService       k8s2.K8sCommonService
+	systemWorkflowExecutor SystemWorkflowExecutor
 }
 
 type WorkflowRequest struct {
-	WorkflowNamePrefix         string                            `json:"workflowNamePrefix"`
-	PipelineName               string                            `json:"pipelineName"`
-	PipelineId                 int                               `json:"pipelineId"`
-	DockerImageTag             string                            `json:"dockerImageTag"`
-	DockerRegistryId           string                            `json:"dockerRegistryId"`
-	DockerRegistryType         string                            `json:"dockerRegistryType"`
-	DockerRegistryURL          string                            `json:"dockerRegistryURL"`
-	DockerConnection           string                            `json:"dockerConnection"`
-	DockerCert                 string                            `json:"dockerCert"`
-	DockerRepository           string                            `json:"dockerRepository"`
-	CheckoutPath               string                            `json:"checkoutPath"`
-	DockerUsername             string                            `json:"dockerUsername"`
-	DockerPassword             string                            `json:"dockerPassword"`
-	AwsRegion                  string                            `json:"awsRegion"`
-	AccessKey                  string                            `json:"accessKey"`
-	SecretKey                  string                            `json:"secretKey"`
-	CiCacheLocation            string                            `json:"ciCacheLocation"`
-	CiCacheRegion              string                            `json:"ciCacheRegion"`
-	CiCacheFileName            string                            `json:"ciCacheFileName"`
-	CiProjectDetails           []CiProjectDetails                `json:"ciProjectDetails"`
-	ContainerResources         ContainerResources                `json:"containerResources"`
-	ActiveDeadlineSeconds      int64                             `json:"activeDeadlineSeconds"`
-	CiImage                    string                            `json:"ciImage"`
-	Namespace                  string                            `json:"namespace"`
-	WorkflowId                 int                               `json:"workflowId"`
-	TriggeredBy                int32                             `json:"triggeredBy"`
-	CacheLimit                 int64                             `json:"cacheLimit"`
-	BeforeDockerBuildScripts   []*bean.CiScript                  `json:"beforeDockerBuildScripts"`
-	AfterDockerBuildScripts    []*bean.CiScript                  `json:"afterDockerBuildScripts"`
-	CiArtifactLocation         string                            `json:"ciArtifactLocation"`
-	CiArtifactBucket           string                            `json:"ciArtifactBucket"`
-	CiArtifactFileName         string                            `json:"ciArtifactFileName"`
-	CiArtifactRegion           string                            `json:"ciArtifactRegion"`
-	ScanEnabled                bool                              `json:"scanEnabled"`
-	CloudProvider              blob_storage.BlobStorageType      `json:"cloudProvider"`
-	BlobStorageConfigured      bool                              `json:"blobStorageConfigured"`
-	BlobStorageS3Config        *blob_storage.BlobStorageS3Config `json:"blobStorageS3Config"`
-	AzureBlobConfig            *blob_storage.AzureBlobConfig     `json:"azureBlobConfig"`
-	GcpBlobConfig              *blob_storage.GcpBlobConfig       `json:"gcpBlobConfig"`
-	BlobStorageLogsKey         string                            `json:"blobStorageLogsKey"`
-	InAppLoggingEnabled        bool                              `json:"inAppLoggingEnabled"`
-	DefaultAddressPoolBaseCidr string                            `json:"defaultAddressPoolBaseCidr"`
-	DefaultAddressPoolSize     int                               `json:"defaultAddressPoolSize"`
-	PreCiSteps                 []*bean2.StepObject               `json:"preCiSteps"`
-	PostCiSteps                []*bean2.StepObject               `json:"postCiSteps"`
-	RefPlugins                 []*bean2.RefPluginObject          `json:"refPlugins"`
-	AppName                    string                            `json:"appName"`
-	TriggerByAuthor            string                            `json:"triggerByAuthor"`
-	CiBuildConfig              *bean2.CiBuildConfigBean          `json:"ciBuildConfig"`
-	CiBuildDockerMtuValue      int                               `json:"ciBuildDockerMtuValue"`
-	IgnoreDockerCachePush      bool                              `json:"ignoreDockerCachePush"`
-	IgnoreDockerCachePull      bool                              `json:"ignoreDockerCachePull"`
-	CacheInvalidate            bool                              `json:"cacheInvalidate"`
-	IsPvcMounted               bool                              `json:"IsPvcMounted"`
-	ExtraEnvironmentVariables  map[string]string                 `json:"extraEnvironmentVariables"`
-	EnableBuildContext         bool                              `json:"enableBuildContext"`
-	AppId                      int                               `json:"appId"`
-	EnvironmentId              int                               `json:"environmentId"`
-	OrchestratorHost           string                            `json:"orchestratorHost"`
-	OrchestratorToken          string                            `json:"orchestratorToken"`
-	IsExtRun                   bool                              `json:"isExtRun"`
-	ImageRetryCount            int                               `json:"imageRetryCount"`
-	ImageRetryInterval         int                               `json:"imageRetryInterval"`
+	WorkflowNamePrefix         string                              `json:"workflowNamePrefix"`
+	PipelineName               string                              `json:"pipelineName"`
+	PipelineId                 int                                 `json:"pipelineId"`
+	DockerImageTag             string                              `json:"dockerImageTag"`
+	DockerRegistryId           string                              `json:"dockerRegistryId"`
+	DockerRegistryType         string                              `json:"dockerRegistryType"`
+	DockerRegistryURL          string                              `json:"dockerRegistryURL"`
+	DockerConnection           string                              `json:"dockerConnection"`
+	DockerCert                 string                              `json:"dockerCert"`
+	DockerRepository           string                              `json:"dockerRepository"`
+	CheckoutPath               string                              `json:"checkoutPath"`
+	DockerUsername             string                              `json:"dockerUsername"`
+	DockerPassword             string                              `json:"dockerPassword"`
+	AwsRegion                  string                              `json:"awsRegion"`
+	AccessKey                  string                              `json:"accessKey"`
+	SecretKey                  string                              `json:"secretKey"`
+	CiCacheLocation            string                              `json:"ciCacheLocation"`
+	CiCacheRegion              string                              `json:"ciCacheRegion"`
+	CiCacheFileName            string                              `json:"ciCacheFileName"`
+	CiProjectDetails           []CiProjectDetails                  `json:"ciProjectDetails"`
+	ContainerResources         ContainerResources                  `json:"containerResources"`
+	ActiveDeadlineSeconds      int64                               `json:"activeDeadlineSeconds"`
+	CiImage                    string                              `json:"ciImage"`
+	Namespace                  string                              `json:"namespace"`
+	WorkflowId                 int                                 `json:"workflowId"`
+	TriggeredBy                int32                               `json:"triggeredBy"`
+	CacheLimit                 int64                               `json:"cacheLimit"`
+	BeforeDockerBuildScripts   []*bean.CiScript                    `json:"beforeDockerBuildScripts"`
+	AfterDockerBuildScripts    []*bean.CiScript                    `json:"afterDockerBuildScripts"`
+	CiArtifactLocation         string                              `json:"ciArtifactLocation"`
+	CiArtifactBucket           string                              `json:"ciArtifactBucket"`
+	CiArtifactFileName         string                              `json:"ciArtifactFileName"`
+	CiArtifactRegion           string                              `json:"ciArtifactRegion"`
+	ScanEnabled                bool                                `json:"scanEnabled"`
+	CloudProvider              blob_storage.BlobStorageType        `json:"cloudProvider"`
+	BlobStorageConfigured      bool                                `json:"blobStorageConfigured"`
+	BlobStorageS3Config        *blob_storage.BlobStorageS3Config   `json:"blobStorageS3Config"`
+	AzureBlobConfig            *blob_storage.AzureBlobConfig       `json:"azureBlobConfig"`
+	GcpBlobConfig              *blob_storage.GcpBlobConfig         `json:"gcpBlobConfig"`
+	BlobStorageLogsKey         string                              `json:"blobStorageLogsKey"`
+	InAppLoggingEnabled        bool                                `json:"inAppLoggingEnabled"`
+	DefaultAddressPoolBaseCidr string                              `json:"defaultAddressPoolBaseCidr"`
+	DefaultAddressPoolSize     int                                 `json:"defaultAddressPoolSize"`
+	PreCiSteps                 []*bean2.StepObject                 `json:"preCiSteps"`
+	PostCiSteps                []*bean2.StepObject                 `json:"postCiSteps"`
+	RefPlugins                 []*bean2.RefPluginObject            `json:"refPlugins"`
+	AppName                    string                              `json:"appName"`
+	TriggerByAuthor            string                              `json:"triggerByAuthor"`
+	CiBuildConfig              *bean2.CiBuildConfigBean            `json:"ciBuildConfig"`
+	CiBuildDockerMtuValue      int                                 `json:"ciBuildDockerMtuValue"`
+	IgnoreDockerCachePush      bool                                `json:"ignoreDockerCachePush"`
+	IgnoreDockerCachePull      bool                                `json:"ignoreDockerCachePull"`
+	CacheInvalidate            bool                                `json:"cacheInvalidate"`
+	IsPvcMounted               bool                                `json:"IsPvcMounted"`
+	ExtraEnvironmentVariables  map[string]string                   `json:"extraEnvironmentVariables"`
+	EnableBuildContext         bool                                `json:"enableBuildContext"`
+	AppId                      int                                 `json:"appId"`
+	EnvironmentId              int                                 `json:"environmentId"`
+	OrchestratorHost           string                              `json:"orchestratorHost"`
+	OrchestratorToken          string                              `json:"orchestratorToken"`
+	IsExtRun                   bool                                `json:"isExtRun"`
+	ImageRetryCount            int                                 `json:"imageRetryCount"`
+	ImageRetryInterval         int                                 `json:"imageRetryInterval"`
+	WorkflowExecutor           pipelineConfig.WorkflowExecutorType `json:"workflowExecutor"`
+	ImageSigning               *bean2.ImageSigningRequest          `json:"imageSigning,omitempty"`
+	BuildCache                 *bean2.BuildCacheRequest            `json:"buildCache,omitempty"`
+	Sbom                       *bean2.SbomRequest                  `json:"sbom,omitempty"`
 }
 
 const (
@@ -204,14 +210,15 @@ type GitOptions struct {
 
 func NewWorkflowServiceImpl(Logger *zap.SugaredLogger, ciConfig *CiConfig, globalCMCSService GlobalCMCSService,
 	appService app.AppService, configMapRepository chartConfig.ConfigMapRepository,
-	k8sUtil *k8s.K8sUtil, k8sCommonService k8s2.K8sCommonService) (*WorkflowServiceImpl, error) {
+	k8sUtil *k8s.K8sUtil, k8sCommonService k8s2.K8sCommonService, systemWorkflowExecutor SystemWorkflowExecutor) (*WorkflowServiceImpl, error) {
 	workflowService := &WorkflowServiceImpl{
-		Logger:              Logger,
-		ciConfig:            ciConfig,
-		globalCMCSService:   globalCMCSService,
-		appService:          appService,
-		configMapRepository: configMapRepository,
-		k8sCommonService:    k8sCommonService,
+		Logger:                 Logger,
+		ciConfig:               ciConfig,
+		globalCMCSService:      globalCMCSService,
+		appService:             appService,
+		configMapRepository:    configMapRepository,
+		k8sCommonService:       k8sCommonService,
+		systemWorkflowExecutor: systemWorkflowExecutor,
 	}
 	restConfig, err := k8sUtil.GetK8sInClusterRestConfig()
 	if err != nil {
@@ -248,7 +255,7 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 		workflowRequest.IsExtRun = true
 	}
 	ciCdTriggerEvent.CiRequest.BlobStorageLogsKey = fmt.Sprintf("%s/%s", impl.ciConfig.DefaultBuildLogsKeyPrefix, workflowRequest.WorkflowNamePrefix)
-	ciCdTriggerEvent.CiRequest.InAppLoggingEnabled = impl.ciConfig.InAppLoggingEnabled
+	ciCdTriggerEvent.CiRequest.InAppLoggingEnabled = impl.ciConfig.InAppLoggingEnabled || (workflowRequest.WorkflowExecutor == pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM)
 	workflowJson, err := json.Marshal(&ciCdTriggerEvent)
 	if err != nil {
 		impl.Logger.Errorw("err", err)
@@ -343,7 +350,7 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 	})
 
 	eventEnv := v12.EnvVar{Name: "CI_CD_EVENT", Value: string(workflowJson)}
-	inAppLoggingEnv := v12.EnvVar{Name: "IN_APP_LOGGING", Value: strconv.FormatBool(impl.ciConfig.InAppLoggingEnabled)}
+	inAppLoggingEnv := v12.EnvVar{Name: "IN_APP_LOGGING", Value: strconv.FormatBool(ciCdTriggerEvent.CiRequest.InAppLoggingEnabled)}
 	containerEnvVariables = append(containerEnvVariables, eventEnv, inAppLoggingEnv)
 	ciTemplate := v1alpha1.Template{
 		Name: CI_WORKFLOW_NAME,
@@ -573,6 +580,9 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 	if len(impl.ciConfig.NodeLabel) > 0 && !(isJob && workflowRequest.IsExtRun) {
 		ciWorkflow.Spec.NodeSelector = impl.ciConfig.NodeLabel
 	}
+	if workflowRequest.WorkflowExecutor == pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+		return impl.submitSystemWorkflow(workflowRequest, ciWorkflow, ciTemplate, globalCmCsConfigs, configMaps, secrets, isJob, env)
+	}
 	wfTemplate, err := json.Marshal(ciWorkflow)
 	if err != nil {
 		impl.Logger.Errorw("marshal error", "err", err)
@@ -585,6 +595,63 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 	return createdWf, err
 }
 
+// submitSystemWorkflow runs the already built ci template as a k8s job through the system workflow executor
+func (impl *WorkflowServiceImpl) submitSystemWorkflow(workflowRequest *WorkflowRequest, ciWorkflow v1alpha1.Workflow, ciTemplate v1alpha1.Template,
+	globalCmCsConfigs []*bean2.GlobalCMCSDto, configMaps bean3.ConfigMapJson, secrets bean3.ConfigSecretJson, isJob bool, env *repository2.Environment) (*v1alpha1.Workflow, error) {
+	workflowConfigMaps, workflowSecrets, err := GetFromGlobalCmCsDtos(globalCmCsConfigs)
+	if err != nil {
+		impl.Logger.Errorw("error in getting global cm/cs for system workflow", "err", err)
+		return nil, err
+	}
+	workflowConfigMaps = append(workflowConfigMaps, configMaps.Maps...)
+	for _, secret := range secrets.Secrets {
+		workflowSecrets = append(workflowSecrets, *secret)
+	}
+
+	workflowTemplate := bean2.WorkflowTemplate{}
+	workflowTemplate.WorkflowId = workflowRequest.WorkflowId
+	workflowTemplate.WorkflowType = CI_WORKFLOW_NAME
+	workflowTemplate.WorkflowNamePrefix = workflowRequest.WorkflowNamePrefix
+	workflowTemplate.Namespace = workflowRequest.Namespace
+	workflowTemplate.ConfigMaps = workflowConfigMaps
+	workflowTemplate.Secrets = workflowSecrets
+	workflowTemplate.TTLValue = ciWorkflow.Spec.TTLStrategy.SecondsAfterCompletion
+	workflowTemplate.ActiveDeadlineSeconds = &workflowRequest.ActiveDeadlineSeconds
+	workflowTemplate.ServiceAccountName = ciWorkflow.Spec.ServiceAccountName
+	workflowTemplate.Tolerations = ciWorkflow.Spec.Tolerations
+	workflowTemplate.NodeSelector = ciWorkflow.Spec.NodeSelector
+	if len(ciTemplate.NodeSelector) > 0 {
+		workflowTemplate.NodeSelector = ciTemplate.NodeSelector
+	}
+	workflowTemplate.Volumes = append(ciWorkflow.Spec.Volumes, ciTemplate.Volumes...)
+	mainContainer := *ciTemplate.Container
+	mainContainer.Name = common.MainContainerName
+	workflowTemplate.Containers = []v12.Container{mainContainer}
+	workflowTemplate.RestartPolicy = v12.RestartPolicyNever
+	workflowTemplate.ClusterConfig = impl.config
+	if isJob && env != nil && env.Id != 0 {
+		restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(context.Background(), env.ClusterId)
+		if err != nil {
+			impl.Logger.Errorw("error in getting rest config by cluster id", "clusterId", env.ClusterId, "err", err)
+			return nil, err
+		}
+		workflowTemplate.ClusterConfig = restConfig
+		workflowTemplate.Namespace = env.Namespace
+	}
+
+	err = impl.systemWorkflowExecutor.ExecuteWorkflow(workflowTemplate)
+	if err != nil {
+		impl.Logger.Errorw("error in submitting system workflow", "workflowId", workflowRequest.WorkflowId, "err", err)
+		return nil, err
+	}
+	return &v1alpha1.Workflow{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: workflowTemplate.WorkflowNamePrefix + "-",
+			Namespace:    workflowTemplate.Namespace,
+		},
+	}, nil
+}
+
 func getConfigMapsAndSecrets(workflowRequest *WorkflowRequest, existingConfigMap *bean3.ConfigMapJson, existingSecrets *bean3.ConfigSecretJson) (bean3.ConfigMapJson, bean3.ConfigSecretJson, error) {
 	configMaps := bean3.ConfigMapJson{}
 	secrets := bean3.ConfigSecretJson{}
@@ -806,8 +873,21 @@ func (impl *WorkflowServiceImpl) GetWorkflow(name string, namespace string, isEx
 	return workflow, err
 }
 
-func (impl *WorkflowServiceImpl) TerminateWorkflow(name string, namespace string, isExt bool, environment *repository2.Environment) error {
+func (impl *WorkflowServiceImpl) TerminateWorkflow(executorType pipelineConfig.WorkflowExecutorType, name string, namespace string, isExt bool, environment *repository2.Environment) error {
 	impl.Logger.Debugw("terminating wf", "name", name)
+	if executorType == pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM {
+		restConfig := impl.config
+		if isExt {
+			var err error
+			restConfig, err, _ = impl.k8sCommonService.GetRestConfigByClusterId(context.Background(), environment.ClusterId)
+			if err != nil {
+				impl.Logger.Errorw("error in getting rest config by cluster id", "err", err)
+				return err
+			}
+			namespace = environment.Namespace
+		}
+		return impl.systemWorkflowExecutor.TerminateWorkflow(name, namespace, restConfig)
+	}
 	var wfClient v1alpha12.WorkflowInterface
 	var err error
 	if isExt {
@@ -0,0 +1,23 @@
+package bean
+
+import "github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+
+type CanaryAnalysisConfigDto struct {
+	Id                    int                        `json:"id"`
+	PipelineId            int                        `json:"pipelineId" validate:"required"`
+	InitialDelayInSeconds int                        `json:"initialDelayInSeconds" validate:"min=0"`
+	IntervalInSeconds     int                        `json:"intervalInSeconds" validate:"min=0"`
+	EvaluationCount       int                        `json:"evaluationCount" validate:"min=0"`
+	FailureLimit          int                        `json:"failureLimit" validate:"min=0"` //failed evaluations tolerated before the analysis fails
+	AutoRollback          bool                       `json:"autoRollback"`
+	Metrics               []*CanaryAnalysisMetricDto `json:"metrics" validate:"required,min=1,dive"`
+	UserId                int32                      `json:"-"`
+}
+
+type CanaryAnalysisMetricDto struct {
+	Id        int                                   `json:"id"`
+	Name      string                                `json:"name" validate:"required"`
+	Query     string                                `json:"query" validate:"required"` //promql, every returned sample has to satisfy the threshold
+	Operator  pipelineConfig.CanaryAnalysisOperator `json:"operator" validate:"oneof=< <= > >="`
+	Threshold float64                               `json:"threshold"`
+}
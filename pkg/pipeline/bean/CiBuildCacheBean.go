@@ -0,0 +1,57 @@
+package bean
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+)
+
+type CiBuildCacheConfigDto struct {
+	Id           int                           `json:"id"`
+	CiPipelineId int                           `json:"ciPipelineId" validate:"required"`
+	Mode         pipelineConfig.BuildCacheMode `json:"mode" validate:"oneof=registry disabled"`
+	// CacheRepository is the repository cache images are pushed to, defaults to the repository of the built image
+	CacheRepository string `json:"cacheRepository,omitempty"`
+	// ExportMode is the buildkit cache export mode, max also exports the layers of intermediate stages
+	ExportMode  string   `json:"exportMode,omitempty" validate:"omitempty,oneof=min max"`
+	KeyByBranch bool     `json:"keyByBranch"`
+	KeyFiles    []string `json:"keyFiles,omitempty"` //lockfiles hashed into the cache key, relative to the checkout path
+	Generation  int      `json:"generation"`
+	// InvalidatedOn is the time of the last invalidation
+	InvalidatedOn *time.Time `json:"invalidatedOn,omitempty"`
+	UserId        int32      `json:"-"`
+}
+
+type CiBuildCacheEntryDto struct {
+	Id         int       `json:"id"`
+	CacheKey   string    `json:"cacheKey"`
+	CacheRef   string    `json:"cacheRef"`
+	LastUsedOn time.Time `json:"lastUsedOn"`
+}
+
+// BuildCacheRequest is sent to the ci runner. The runner computes the cache key as KeyPrefix followed by the first
+// 12 characters of the sha256 of the KeyFiles contents, imports from CacheRepository:<key> and then from each of
+// ImportRefs, and exports the cache of the build to CacheRepository:<key>
+type BuildCacheRequest struct {
+	Mode            pipelineConfig.BuildCacheMode `json:"mode"`
+	CacheRepository string                        `json:"cacheRepository,omitempty"`
+	KeyPrefix       string                        `json:"keyPrefix,omitempty"`
+	KeyFiles        []string                      `json:"keyFiles,omitempty"`
+	ExportMode      string                        `json:"exportMode,omitempty"`
+	// ImportRefs are fallbacks for a key miss, most relevant first
+	ImportRefs []string `json:"importRefs,omitempty"`
+	SkipImport bool     `json:"skipImport"`
+	// PruneRefs are cache images past retention or invalidated, the runner removes them from the registry
+	PruneRefs []string `json:"pruneRefs,omitempty"`
+}
+
+// BuildCacheMetrics is reported by the ci runner on completion of the build
+type BuildCacheMetrics struct {
+	CacheKey string `json:"cacheKey"`
+	CacheRef string `json:"cacheRef"`
+	Exported bool   `json:"exported"`
+	Hits     int    `json:"hits"`   //layers served from cache
+	Misses   int    `json:"misses"` //layers built
+	// PrunedRefs are the PruneRefs of the request the runner removed from the registry
+	PrunedRefs []string `json:"prunedRefs,omitempty"`
+}
@@ -2,8 +2,10 @@ package bean
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/sql"
+	"regexp"
 	"time"
 )
 
@@ -17,6 +19,9 @@ const (
 )
 const Main = "main"
 
+// platformRegex matches os/arch with an optional variant, like linux/amd64 or linux/arm/v7
+var platformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
 type CiBuildConfigBean struct {
 	Id                        int                `json:"id"`
 	GitMaterialId             int                `json:"gitMaterialId,omitempty" validate:"required"`
@@ -28,10 +33,12 @@ type CiBuildConfigBean struct {
 }
 
 type DockerBuildConfig struct {
-	DockerfilePath     string            `json:"dockerfileRelativePath,omitempty"`
-	DockerfileContent  string            `json:"dockerfileContent"`
-	Args               map[string]string `json:"args,omitempty"`
-	TargetPlatform     string            `json:"targetPlatform,omitempty"`
+	DockerfilePath    string            `json:"dockerfileRelativePath,omitempty"`
+	DockerfileContent string            `json:"dockerfileContent"`
+	Args              map[string]string `json:"args,omitempty"`
+	TargetPlatform    string            `json:"targetPlatform,omitempty"`
+	// TargetPlatforms are built in a single buildx build and pushed as a manifest list, takes precedence over TargetPlatform
+	TargetPlatforms    []string          `json:"targetPlatforms,omitempty"`
 	Language           string            `json:"language,omitempty"`
 	LanguageFramework  string            `json:"languageFramework,omitempty"`
 	DockerBuildOptions map[string]string `json:"dockerBuildOptions,omitempty"`
@@ -48,6 +55,23 @@ type BuildPackConfig struct {
 	ProjectPath     string            `json:"projectPath,omitempty"`
 }
 
+func ValidateCiBuildConfig(ciBuildConfigBean *CiBuildConfigBean) error {
+	if ciBuildConfigBean == nil || ciBuildConfigBean.DockerBuildConfig == nil {
+		return nil
+	}
+	platforms := make(map[string]bool)
+	for _, platform := range ciBuildConfigBean.DockerBuildConfig.TargetPlatforms {
+		if !platformRegex.MatchString(platform) {
+			return fmt.Errorf("invalid target platform %q, expected os/arch[/variant]", platform)
+		}
+		if platforms[platform] {
+			return fmt.Errorf("duplicate target platform %q", platform)
+		}
+		platforms[platform] = true
+	}
+	return nil
+}
+
 func ConvertBuildConfigBeanToDbEntity(templateId int, overrideTemplateId int, ciBuildConfigBean *CiBuildConfigBean, userId int32) (*pipelineConfig.CiBuildConfig, error) {
 	buildMetadata := ""
 	ciBuildType := ciBuildConfigBean.CiBuildType
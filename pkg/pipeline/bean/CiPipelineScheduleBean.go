@@ -0,0 +1,30 @@
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"time"
+)
+
+type CiPipelineScheduleDto struct {
+	Id             int                                  `json:"id"`
+	CiPipelineId   int                                  `json:"ciPipelineId" validate:"required"`
+	EnvironmentId  int                                  `json:"environmentId"` //only used for job pipelines
+	CronExpression string                               `json:"cronExpression" validate:"required"`
+	Timezone       string                               `json:"timezone"` //IANA timezone name, defaults to UTC
+	CommitPolicy   pipelineConfig.ScheduleCommitPolicy  `json:"commitPolicy" validate:"omitempty,oneof=LATEST_COMMIT LAST_BUILT_COMMIT"`
+	CatchUpPolicy  pipelineConfig.ScheduleCatchUpPolicy `json:"catchUpPolicy" validate:"omitempty,oneof=SKIP RUN_ONCE"`
+	Paused         bool                                 `json:"paused"`
+	NextRunAt      time.Time                            `json:"nextRunAt"`
+	LastRunAt      time.Time                            `json:"lastRunAt"`
+	UserId         int32                                `json:"-"`
+}
+
+type CiPipelineScheduleRunDto struct {
+	Id           int                              `json:"id"`
+	CiWorkflowId int                              `json:"ciWorkflowId"`
+	ScheduledAt  time.Time                        `json:"scheduledAt"`
+	TriggeredAt  time.Time                        `json:"triggeredAt"`
+	TriggeredBy  int32                            `json:"triggeredBy"`
+	Status       pipelineConfig.ScheduleRunStatus `json:"status"`
+	Message      string                           `json:"message"`
+}
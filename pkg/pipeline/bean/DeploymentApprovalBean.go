@@ -0,0 +1,28 @@
+package bean
+
+import "github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+
+type DeploymentApprovalConfigDto struct {
+	Id                int     `json:"id"`
+	PipelineId        int     `json:"pipelineId" validate:"required"`
+	RequiredApprovals int     `json:"requiredApprovals" validate:"min=1,max=10"`
+	ApproverGroupIds  []int32 `json:"approverGroupIds"` //role groups whose members can approve, any user with trigger access when empty
+	ExpiryInHours     int     `json:"expiryInHours" validate:"min=0"`
+	UserId            int32   `json:"-"`
+}
+
+type DeploymentApprovalRequestDto struct {
+	PipelineId   int    `json:"pipelineId" validate:"required"`
+	CiArtifactId int    `json:"ciArtifactId" validate:"required"`
+	Comment      string `json:"comment"`
+	UserId       int32  `json:"-"`
+}
+
+type DeploymentApprovalActionDto struct {
+	ApprovalRequestId int                                     `json:"approvalRequestId" validate:"required"`
+	Action            pipelineConfig.DeploymentApprovalAction `json:"action" validate:"oneof=APPROVE REJECT"`
+	Comment           string                                  `json:"comment"`
+	UserId            int32                                   `json:"-"`
+	// HasTriggerAccess is resolved by the caller from rbac, used when no approver groups are configured
+	HasTriggerAccess bool `json:"-"`
+}
@@ -0,0 +1,55 @@
+package bean
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+)
+
+type ImageSigningKeyDto struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name" validate:"required"`
+	PublicKey  string `json:"publicKey" validate:"required"`
+	SecretName string `json:"secretName" validate:"required"` //secret in ci namespace having cosign.key and cosign.password
+	UserId     int32  `json:"-"`
+}
+
+type ImageSigningConfigDto struct {
+	Id                  int   `json:"id"`
+	CiPipelineId        int   `json:"ciPipelineId" validate:"required"`
+	SigningKeyId        int   `json:"signingKeyId" validate:"required"`
+	GenerateAttestation bool  `json:"generateAttestation"`
+	UserId              int32 `json:"-"`
+}
+
+// ImageSigningRequest is sent to the ci runner, the built image is signed after push when present
+type ImageSigningRequest struct {
+	SigningKeyId        int    `json:"signingKeyId"`
+	KeySecretName       string `json:"keySecretName"`
+	GenerateAttestation bool   `json:"generateAttestation"`
+}
+
+// ImageSignatureDto is a cosign signature or attestation as reported by the ci runner, payload is the signed
+// content and signature is base64 encoded
+type ImageSignatureDto struct {
+	Id            int                         `json:"id,omitempty"`
+	SignatureType security.ImageSignatureType `json:"signatureType"`
+	SigningKeyId  int                         `json:"signingKeyId"`
+	PredicateType string                      `json:"predicateType,omitempty"`
+	Payload       string                      `json:"payload"`
+	Signature     string                      `json:"signature"`
+	CreatedOn     time.Time                   `json:"createdOn,omitempty"`
+}
+
+type ImageSigningPolicyDto struct {
+	Id           int    `json:"id"`
+	ClusterId    int    `json:"clusterId"`
+	EnvId        int    `json:"envId"`
+	AppId        int    `json:"appId"`
+	Action       string `json:"action" validate:"oneof=inherit allow block"`
+	SigningKeyId int    `json:"signingKeyId"`
+	// PolicyOrigin is the level the applicable policy is defined at, Inherited is set when it is not the requested level
+	PolicyOrigin string `json:"policyOrigin,omitempty"`
+	Inherited    bool   `json:"inherited"`
+	UserId       int32  `json:"-"`
+}
@@ -0,0 +1,23 @@
+package bean
+
+import "encoding/json"
+
+const (
+	// PREVIEW_PLACEHOLDER_NAMESPACE and PREVIEW_PLACEHOLDER_PR_NUMBER are replaced in the values override and the url template
+	PREVIEW_PLACEHOLDER_NAMESPACE = "{{namespace}}"
+	PREVIEW_PLACEHOLDER_PR_NUMBER = "{{prNumber}}"
+)
+
+type PreviewEnvironmentConfigDto struct {
+	Id         int `json:"id"`
+	PipelineId int `json:"pipelineId" validate:"required"`
+	ClusterId  int `json:"clusterId" validate:"required"`
+	// NamespacePrefix previews are created in namespace <prefix>-pr-<number>, defaults to the app name
+	NamespacePrefix string `json:"namespacePrefix" validate:"omitempty,max=40"`
+	// ValuesOverride json merge patch applied on the deployment template of the template pipeline
+	ValuesOverride       json.RawMessage `json:"valuesOverride,omitempty"`
+	UrlTemplate          string          `json:"urlTemplate,omitempty"` //e.g. https://{{namespace}}.preview.example.com
+	TtlInHours           int             `json:"ttlInHours" validate:"min=0"`
+	CommentOnPullRequest bool            `json:"commentOnPullRequest"`
+	UserId               int32           `json:"-"`
+}
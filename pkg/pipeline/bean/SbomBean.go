@@ -0,0 +1,74 @@
+package bean
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+)
+
+// SbomRequest is sent to the ci runner, an sbom of the built image is generated in each of the formats after push.
+// Sboms exceed the size of a ci complete event, the runner uploads each to <BlobStorageKeyPrefix>/<format>.json in
+// the blob storage of the ci artifacts and reports the keys as SbomRefs
+type SbomRequest struct {
+	Formats              []security.SbomFormat `json:"formats"`
+	BlobStorageKeyPrefix string                `json:"blobStorageKeyPrefix"`
+}
+
+// SbomRefDto is an sbom uploaded to blob storage by the ci runner
+type SbomRefDto struct {
+	Format         security.SbomFormat `json:"format"`
+	BlobStorageKey string              `json:"blobStorageKey"`
+}
+
+// SbomDocumentDto is an sbom as generated by the ci runner or the image scanner, document is the json as generated
+type SbomDocumentDto struct {
+	Format   security.SbomFormat `json:"format" validate:"oneof=spdx-json cyclonedx-json"`
+	Document json.RawMessage     `json:"document" validate:"required"`
+}
+
+// SbomUploadRequest is used by the image scanner to upload sboms of an image, the artifact is looked up by digest
+// when CiArtifactId is not given
+type SbomUploadRequest struct {
+	ImageDigest  string             `json:"imageDigest" validate:"required"`
+	CiArtifactId int                `json:"ciArtifactId"`
+	Sboms        []*SbomDocumentDto `json:"sboms" validate:"required,min=1,dive"`
+	UserId       int32              `json:"-"`
+}
+
+type ImageSbomDto struct {
+	Id           int                 `json:"id"`
+	CiArtifactId int                 `json:"ciArtifactId"`
+	ImageDigest  string              `json:"imageDigest"`
+	Format       security.SbomFormat `json:"format"`
+	SpecVersion  string              `json:"specVersion"`
+	Source       security.SbomSource `json:"source"`
+	PackageCount int                 `json:"packageCount"`
+	GeneratedOn  time.Time           `json:"generatedOn"`
+}
+
+type SbomPackageExposureRequest struct {
+	PackageName string `json:"packageName" validate:"required"`
+	// VersionRange is a semver constraint like ">= 2.0.0, < 2.17.1", all versions match when empty. As per semver
+	// pre-release versions match only a range having pre-release bounds
+	VersionRange string `json:"versionRange,omitempty"`
+	PackageType  string `json:"packageType,omitempty"` //purl type like maven, npm, golang
+	EnvIds       []int  `json:"envIds,omitempty"`
+	ClusterIds   []int  `json:"clusterIds,omitempty"`
+}
+
+type SbomPackageExposureDto struct {
+	AppId           int    `json:"appId"`
+	AppName         string `json:"appName"`
+	EnvId           int    `json:"envId"`
+	EnvironmentName string `json:"environmentName"`
+	ClusterId       int    `json:"clusterId"`
+	ClusterName     string `json:"clusterName"`
+	CiArtifactId    int    `json:"ciArtifactId"`
+	Image           string `json:"image"`
+	ImageDigest     string `json:"imageDigest"`
+	PackageName     string `json:"packageName"`
+	PackageVersion  string `json:"packageVersion"`
+	PackageType     string `json:"packageType"`
+	Purl            string `json:"purl"`
+}
@@ -10,6 +10,7 @@ import (
 type WorkflowTemplate struct {
 	WorkflowId       int
 	WorkflowRunnerId int
+	WorkflowType     string
 	v1.PodSpec
 	ConfigMaps             []bean.ConfigSecretMap
 	Secrets                []bean.ConfigSecretMap
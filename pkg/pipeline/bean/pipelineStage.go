@@ -49,6 +49,7 @@ type InlineStepDetailDto struct {
 
 type RefPluginStepDetailDto struct {
 	PluginId         int                   `json:"pluginId"`
+	PluginVersion    string                `json:"pluginVersion,omitempty"` //if set, step is pinned to this version of the plugin family of PluginId
 	InputVariables   []*StepVariableDto    `json:"inputVariables"`
 	OutputVariables  []*StepVariableDto    `json:"outputVariables"`
 	ConditionDetails []*ConditionDetailDto `json:"conditionDetails"`
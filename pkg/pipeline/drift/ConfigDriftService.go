@@ -0,0 +1,666 @@
+package drift
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	client2 "github.com/devtron-labs/devtron/api/helm-app"
+	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/pipeline/drift/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/history"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	k8s2 "github.com/devtron-labs/devtron/util/k8s"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ConfigDriftService interface {
+	// GetEnvironmentDrift compares the configuration last deployed on two environments of an app
+	GetEnvironmentDrift(appId, baseEnvId, compareEnvId int) (*ConfigDriftResponse, error)
+	// GetReleaseDrift compares the configuration of two deployments, the pipelines may differ but must belong to the app
+	GetReleaseDrift(appId, basePipelineId, baseWfrId, comparePipelineId, compareWfrId int) (*ConfigDriftResponse, error)
+	// GetLiveDrift compares the manifests last deployed by a pipeline with the resources live in the cluster
+	GetLiveDrift(ctx context.Context, appId, pipelineId int) (*ConfigDriftResponse, error)
+	// ScanLiveDrift checks live drift of all deployed pipelines and notifies drift once per change
+	ScanLiveDrift()
+}
+
+type ConfigDriftServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	deployedConfigurationHistoryService history.DeployedConfigurationHistoryService
+	pipelineRepository                  pipelineConfig.PipelineRepository
+	cdWorkflowRepository                pipelineConfig.CdWorkflowRepository
+	configDriftScanRepository           repository.ConfigDriftScanRepository
+	acdClient                           application.ServiceClient
+	argoUserService                     argo.ArgoUserService
+	helmAppService                      client2.HelmAppService
+	k8sCommonService                    k8s.K8sCommonService
+	eventClient                         client.EventClient
+	eventFactory                        client.EventFactory
+}
+
+func NewConfigDriftServiceImpl(logger *zap.SugaredLogger,
+	deployedConfigurationHistoryService history.DeployedConfigurationHistoryService,
+	pipelineRepository pipelineConfig.PipelineRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	configDriftScanRepository repository.ConfigDriftScanRepository, acdClient application.ServiceClient,
+	argoUserService argo.ArgoUserService, helmAppService client2.HelmAppService, k8sCommonService k8s.K8sCommonService,
+	eventClient client.EventClient, eventFactory client.EventFactory) *ConfigDriftServiceImpl {
+	return &ConfigDriftServiceImpl{
+		logger:                              logger,
+		deployedConfigurationHistoryService: deployedConfigurationHistoryService,
+		pipelineRepository:                  pipelineRepository,
+		cdWorkflowRepository:                cdWorkflowRepository,
+		configDriftScanRepository:           configDriftScanRepository,
+		acdClient:                           acdClient,
+		argoUserService:                     argoUserService,
+		helmAppService:                      helmAppService,
+		k8sCommonService:                    k8sCommonService,
+		eventClient:                         eventClient,
+		eventFactory:                        eventFactory,
+	}
+}
+
+// configComponent is a single deployed configuration, like the deployment template or a configmap, in generic json form
+type configComponent struct {
+	kind   string
+	name   string
+	value  map[string]interface{}
+	secret bool
+}
+
+func (component *configComponent) key() string {
+	return component.kind + "/" + component.name
+}
+
+func (impl *ConfigDriftServiceImpl) GetEnvironmentDrift(appId, baseEnvId, compareEnvId int) (*ConfigDriftResponse, error) {
+	basePipeline, err := impl.getPipelineByAppIdAndEnvId(appId, baseEnvId)
+	if err != nil {
+		return nil, err
+	}
+	comparePipeline, err := impl.getPipelineByAppIdAndEnvId(appId, compareEnvId)
+	if err != nil {
+		return nil, err
+	}
+	baseWfr, err := impl.getLatestDeployRunner(basePipeline)
+	if err != nil {
+		return nil, err
+	}
+	compareWfr, err := impl.getLatestDeployRunner(comparePipeline)
+	if err != nil {
+		return nil, err
+	}
+	return impl.getConfigDrift(DRIFT_TYPE_ENVIRONMENT, basePipeline, baseWfr.Id, comparePipeline, compareWfr.Id)
+}
+
+func (impl *ConfigDriftServiceImpl) GetReleaseDrift(appId, basePipelineId, baseWfrId, comparePipelineId, compareWfrId int) (*ConfigDriftResponse, error) {
+	basePipeline, err := impl.getPipelineByAppId(appId, basePipelineId)
+	if err != nil {
+		return nil, err
+	}
+	comparePipeline, err := impl.getPipelineByAppId(appId, comparePipelineId)
+	if err != nil {
+		return nil, err
+	}
+	if err = impl.validateDeployRunner(basePipelineId, baseWfrId); err != nil {
+		return nil, err
+	}
+	if err = impl.validateDeployRunner(comparePipelineId, compareWfrId); err != nil {
+		return nil, err
+	}
+	return impl.getConfigDrift(DRIFT_TYPE_RELEASE, basePipeline, baseWfrId, comparePipeline, compareWfrId)
+}
+
+func (impl *ConfigDriftServiceImpl) getConfigDrift(driftType DriftType, basePipeline *pipelineConfig.Pipeline, baseWfrId int,
+	comparePipeline *pipelineConfig.Pipeline, compareWfrId int) (*ConfigDriftResponse, error) {
+	baseComponents, err := impl.getDeployedComponents(basePipeline.Id, baseWfrId)
+	if err != nil {
+		return nil, err
+	}
+	compareComponents, err := impl.getDeployedComponents(comparePipeline.Id, compareWfrId)
+	if err != nil {
+		return nil, err
+	}
+	base := newDriftTarget(basePipeline, baseWfrId)
+	compare := newDriftTarget(comparePipeline, compareWfrId)
+	components, err := DiffConfigComponents(baseComponents, compareComponents, base.label(), compare.label())
+	if err != nil {
+		impl.logger.Errorw("error in computing config drift", "err", err, "basePipelineId", basePipeline.Id, "comparePipelineId", comparePipeline.Id)
+		return nil, err
+	}
+	return newConfigDriftResponse(driftType, base, compare, components), nil
+}
+
+func (impl *ConfigDriftServiceImpl) getPipelineByAppIdAndEnvId(appId, envId int) (*pipelineConfig.Pipeline, error) {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentId(appId, envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching pipeline by app and environment", "err", err, "appId", appId, "envId", envId)
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, newDriftApiError(http.StatusNotFound, fmt.Sprintf("no cd pipeline found for environment %d", envId))
+	}
+	return pipelines[0], nil
+}
+
+func (impl *ConfigDriftServiceImpl) getPipelineByAppId(appId, pipelineId int) (*pipelineConfig.Pipeline, error) {
+	pipeline, err := impl.pipelineRepository.FindById(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || pipeline.AppId != appId {
+		return nil, newDriftApiError(http.StatusNotFound, fmt.Sprintf("cd pipeline %d not found in app", pipelineId))
+	}
+	return pipeline, nil
+}
+
+func (impl *ConfigDriftServiceImpl) getLatestDeployRunner(pipeline *pipelineConfig.Pipeline) (*pipelineConfig.CdWorkflowRunner, error) {
+	wfr, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(pipeline.Id, bean2.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching latest deployment of pipeline", "err", err, "pipelineId", pipeline.Id)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || wfr.Id == 0 {
+		return nil, newDriftApiError(http.StatusNotFound, fmt.Sprintf("nothing deployed on environment %s yet", pipeline.Environment.Name))
+	}
+	return &wfr, nil
+}
+
+func (impl *ConfigDriftServiceImpl) validateDeployRunner(pipelineId, wfrId int) error {
+	wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(wfrId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching workflow runner", "err", err, "wfrId", wfrId)
+		return err
+	}
+	if err == pg.ErrNoRows || wfr.CdWorkflow == nil || wfr.CdWorkflow.PipelineId != pipelineId || wfr.WorkflowType != bean2.CD_WORKFLOW_TYPE_DEPLOY {
+		return newDriftApiError(http.StatusNotFound, fmt.Sprintf("deployment %d not found for cd pipeline %d", wfrId, pipelineId))
+	}
+	return nil
+}
+
+// getDeployedComponents reads the deployed configuration with secret values, values are masked when diffing
+func (impl *ConfigDriftServiceImpl) getDeployedComponents(pipelineId, wfrId int) (map[string]*configComponent, error) {
+	deployed, err := impl.deployedConfigurationHistoryService.GetAllDeployedConfigurationByPipelineIdAndWfrId(pipelineId, wfrId, true)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed configuration", "err", err, "pipelineId", pipelineId, "wfrId", wfrId)
+		return nil, err
+	}
+	return BuildConfigComponents(deployed), nil
+}
+
+// BuildConfigComponents converts the deployed configuration of a deployment into comparable components
+func BuildConfigComponents(deployed *history.AllDeploymentConfigurationDetail) map[string]*configComponent {
+	components := make(map[string]*configComponent)
+	if deployed == nil {
+		return components
+	}
+	add := func(component *configComponent) {
+		components[component.key()] = component
+	}
+	if template := deployed.DeploymentTemplateConfig; template != nil && template.CodeEditorValue != nil {
+		value := map[string]interface{}{"values": ParseJsonOrYaml(template.CodeEditorValue.Value)}
+		setIfNotEmpty(value, "templateName", template.TemplateName)
+		setIfNotEmpty(value, "templateVersion", template.TemplateVersion)
+		if template.IsAppMetricsEnabled != nil {
+			value["isAppMetricsEnabled"] = *template.IsAppMetricsEnabled
+		}
+		add(&configComponent{kind: COMPONENT_DEPLOYMENT_TEMPLATE, value: value})
+	}
+	for _, configMap := range deployed.ConfigMapConfig {
+		if configMap == nil || configMap.HistoryConfig == nil {
+			continue
+		}
+		add(&configComponent{kind: COMPONENT_CONFIGMAP, name: configMap.ComponentName, value: cmcsComponentValue(configMap.HistoryConfig)})
+	}
+	for _, secret := range deployed.SecretConfig {
+		if secret == nil || secret.HistoryConfig == nil {
+			continue
+		}
+		external := secret.HistoryConfig.External != nil && *secret.HistoryConfig.External
+		//external secrets hold references to the secret store and not the values
+		add(&configComponent{kind: COMPONENT_SECRET, name: secret.ComponentName, value: cmcsComponentValue(secret.HistoryConfig), secret: !external})
+	}
+	if strategy := deployed.StrategyConfig; strategy != nil {
+		value := make(map[string]interface{})
+		setIfNotEmpty(value, "triggerType", string(strategy.PipelineTriggerType))
+		setIfNotEmpty(value, "strategy", strategy.Strategy)
+		if strategy.CodeEditorValue != nil {
+			value["config"] = ParseJsonOrYaml(strategy.CodeEditorValue.Value)
+		}
+		add(&configComponent{kind: COMPONENT_PIPELINE_STRATEGY, value: value})
+	}
+	return components
+}
+
+func cmcsComponentValue(config *history.HistoryDetailDto) map[string]interface{} {
+	value := make(map[string]interface{})
+	setIfNotEmpty(value, "type", config.Type)
+	setIfNotEmpty(value, "externalType", config.ExternalSecretType)
+	setIfNotEmpty(value, "roleARN", config.RoleARN)
+	setIfNotEmpty(value, "mountPath", config.MountPath)
+	setIfNotEmpty(value, "filePermission", config.FilePermission)
+	if config.External != nil {
+		value["external"] = *config.External
+	}
+	if config.SubPath != nil && *config.SubPath {
+		value["subPath"] = true
+	}
+	if config.CodeEditorValue != nil {
+		if data := ParseJsonOrYaml(config.CodeEditorValue.Value); data != nil {
+			value["data"] = data
+		}
+	}
+	return value
+}
+
+func setIfNotEmpty(value map[string]interface{}, key string, field string) {
+	if len(field) > 0 {
+		value[key] = field
+	}
+}
+
+// DiffConfigComponents diffs the components present on either side, secret data is masked in the result
+func DiffConfigComponents(base map[string]*configComponent, compare map[string]*configComponent, baseLabel string, compareLabel string) ([]*ComponentDrift, error) {
+	keys := make(map[string]bool)
+	for key := range base {
+		keys[key] = true
+	}
+	for key := range compare {
+		keys[key] = true
+	}
+	var componentKeys []string
+	for key := range keys {
+		componentKeys = append(componentKeys, key)
+	}
+	sort.Slice(componentKeys, func(i, j int) bool {
+		return componentOrder(componentKeys[i]) < componentOrder(componentKeys[j]) ||
+			(componentOrder(componentKeys[i]) == componentOrder(componentKeys[j]) && componentKeys[i] < componentKeys[j])
+	})
+	var drifts []*ComponentDrift
+	for _, key := range componentKeys {
+		baseComponent, compareComponent := base[key], compare[key]
+		component := baseComponent
+		if component == nil {
+			component = compareComponent
+		}
+		var baseValue, compareValue map[string]interface{}
+		if baseComponent != nil {
+			baseValue = baseComponent.value
+		}
+		if compareComponent != nil {
+			compareValue = compareComponent.value
+		}
+		masked := (baseComponent != nil && baseComponent.secret) || (compareComponent != nil && compareComponent.secret)
+		if masked {
+			baseValue, compareValue = maskSecretComponents(baseValue, compareValue)
+		}
+		drift, err := diffValues(component.kind, component.name, "", baseValue, compareValue, baseLabel, compareLabel)
+		if err != nil {
+			return nil, err
+		}
+		drift.Masked = masked
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+func componentOrder(key string) int {
+	for i, kind := range []string{COMPONENT_DEPLOYMENT_TEMPLATE, COMPONENT_CONFIGMAP, COMPONENT_SECRET, COMPONENT_PIPELINE_STRATEGY} {
+		if strings.HasPrefix(key, kind+"/") {
+			return i
+		}
+	}
+	return len(key)
+}
+
+func maskSecretComponents(base map[string]interface{}, compare map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	baseData, _ := base["data"].(map[string]interface{})
+	compareData, _ := compare["data"].(map[string]interface{})
+	maskedBaseData, maskedCompareData := MaskSecretData(baseData, compareData)
+	return withData(base, maskedBaseData), withData(compare, maskedCompareData)
+}
+
+func withData(value map[string]interface{}, data map[string]interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(value))
+	for key, field := range value {
+		copied[key] = field
+	}
+	if data != nil {
+		copied["data"] = data
+	}
+	return copied
+}
+
+func diffValues(kind, name, namespace string, base map[string]interface{}, compare map[string]interface{}, baseLabel, compareLabel string) (*ComponentDrift, error) {
+	drift := &ComponentDrift{Kind: kind, Name: name, Namespace: namespace}
+	var baseValue, compareValue interface{}
+	if base != nil {
+		baseValue = base
+	}
+	if compare != nil {
+		compareValue = compare
+	}
+	switch {
+	case base == nil:
+		drift.Status = DRIFT_STATUS_ADDED
+		drift.JsonPatch = []*JsonPatchOperation{{Op: JSON_PATCH_ADD, Path: "", Value: compare}}
+	case compare == nil:
+		drift.Status = DRIFT_STATUS_REMOVED
+		drift.JsonPatch = []*JsonPatchOperation{{Op: JSON_PATCH_REMOVE, Path: ""}}
+	default:
+		drift.JsonPatch = CreateJsonPatch(baseValue, compareValue)
+		drift.Status = DRIFT_STATUS_UNCHANGED
+		if len(drift.JsonPatch) > 0 {
+			drift.Status = DRIFT_STATUS_MODIFIED
+		}
+	}
+	if drift.Status == DRIFT_STATUS_UNCHANGED {
+		return drift, nil
+	}
+	unifiedDiff, err := UnifiedYamlDiff(baseLabel, compareLabel, baseValue, compareValue)
+	if err != nil {
+		return nil, err
+	}
+	drift.UnifiedDiff = unifiedDiff
+	return drift, nil
+}
+
+func (impl *ConfigDriftServiceImpl) GetLiveDrift(ctx context.Context, appId, pipelineId int) (*ConfigDriftResponse, error) {
+	pipeline, err := impl.getPipelineByAppId(appId, pipelineId)
+	if err != nil {
+		return nil, err
+	}
+	if !pipeline.DeploymentAppCreated {
+		return nil, newDriftApiError(http.StatusNotFound, fmt.Sprintf("nothing deployed on environment %s yet", pipeline.Environment.Name))
+	}
+	components, err := impl.getLiveStateDrift(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	base := newDriftTarget(pipeline, 0)
+	compare := newDriftTarget(pipeline, 0)
+	compare.Live = true
+	return newConfigDriftResponse(DRIFT_TYPE_LIVE, base, compare, components), nil
+}
+
+// liveResource is a resource of a deployment with the manifest devtron deployed and the one live in the cluster
+type liveResource struct {
+	kind      string
+	name      string
+	namespace string
+	desired   map[string]interface{}
+	live      map[string]interface{}
+}
+
+func (impl *ConfigDriftServiceImpl) getLiveStateDrift(ctx context.Context, pipeline *pipelineConfig.Pipeline) ([]*ComponentDrift, error) {
+	var resources []*liveResource
+	var err error
+	if util.IsAcdApp(pipeline.DeploymentAppType) {
+		resources, err = impl.getArgoCdResources(ctx, pipeline)
+	} else if util.IsHelmApp(pipeline.DeploymentAppType) {
+		resources, err = impl.getHelmResources(ctx, pipeline)
+	} else {
+		return nil, newDriftApiError(http.StatusBadRequest, fmt.Sprintf("live drift is not supported for %s deployments", pipeline.DeploymentAppType))
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed resources", "err", err, "pipelineId", pipeline.Id)
+		return nil, err
+	}
+	return DiffLiveResources(resources)
+}
+
+// DiffLiveResources diffs the desired manifest of each resource with the live manifest projected on the desired fields
+func DiffLiveResources(resources []*liveResource) ([]*ComponentDrift, error) {
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].kind+"/"+resources[i].namespace+"/"+resources[i].name < resources[j].kind+"/"+resources[j].namespace+"/"+resources[j].name
+	})
+	var drifts []*ComponentDrift
+	for _, resource := range resources {
+		desired := NormalizeManifest(resource.desired)
+		live := NormalizeManifest(resource.live)
+		if desired != nil && live != nil {
+			projected, _ := ProjectLiveState(desired, live).(map[string]interface{})
+			live = projected
+		}
+		masked := resource.kind == "Secret"
+		if masked {
+			desired, live = maskSecretComponents(desired, live)
+		}
+		drift, err := diffValues(resource.kind, resource.name, resource.namespace, desired, live, "desired", "live")
+		if err != nil {
+			return nil, err
+		}
+		drift.Masked = masked
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+func (impl *ConfigDriftServiceImpl) getArgoCdResources(ctx context.Context, pipeline *pipelineConfig.Pipeline) ([]*liveResource, error) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "token", acdToken)
+	response, err := impl.acdClient.ManagedResources(ctx, &application2.ResourcesQuery{ApplicationName: &pipeline.DeploymentAppName})
+	if err != nil {
+		impl.logger.Errorw("error in fetching managed resources of argocd app", "err", err, "argoAppName", pipeline.DeploymentAppName)
+		return nil, err
+	}
+	var resources []*liveResource
+	for _, item := range response.Items {
+		if item == nil || item.Hook {
+			continue
+		}
+		liveState := item.NormalizedLiveState
+		if len(liveState) == 0 {
+			liveState = item.LiveState
+		}
+		resources = append(resources, &liveResource{
+			kind:      item.Kind,
+			name:      item.Name,
+			namespace: item.Namespace,
+			desired:   toManifest(ParseJsonOrYaml(item.TargetState)),
+			live:      toManifest(ParseJsonOrYaml(liveState)),
+		})
+	}
+	return resources, nil
+}
+
+func (impl *ConfigDriftServiceImpl) getHelmResources(ctx context.Context, pipeline *pipelineConfig.Pipeline) ([]*liveResource, error) {
+	appIdentifier := &client2.AppIdentifier{
+		ClusterId:   pipeline.Environment.ClusterId,
+		ReleaseName: pipeline.DeploymentAppName,
+		Namespace:   pipeline.Environment.Namespace,
+	}
+	appDetail, err := impl.helmAppService.GetApplicationDetail(ctx, appIdentifier)
+	if err != nil {
+		impl.logger.Errorw("error in fetching helm release detail", "err", err, "appIdentifier", appIdentifier)
+		return nil, err
+	}
+	if appDetail.GetResourceTreeResponse() == nil {
+		return nil, nil
+	}
+	var resources []*liveResource
+	for _, node := range appDetail.GetResourceTreeResponse().GetNodes() {
+		//resources created by controllers are not part of the release
+		if node == nil || len(node.ParentRefs) > 0 {
+			continue
+		}
+		group, version, kind, name, namespace := node.Group, node.Version, node.Kind, node.Name, node.Namespace
+		desiredManifest, err := impl.helmAppService.GetDesiredManifest(ctx, appIdentifier, &openapi.ResourceIdentifier{
+			Group: &group, Version: &version, Kind: &kind, Name: &name, Namespace: &namespace,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in fetching desired manifest", "err", err, "kind", kind, "name", name)
+			return nil, err
+		}
+		resource := &liveResource{kind: kind, name: name, namespace: namespace, desired: toManifest(ParseJsonOrYaml(desiredManifest.GetManifest()))}
+		liveManifest, err := impl.k8sCommonService.GetResource(ctx, &k8s.ResourceRequestBean{
+			ClusterId: appIdentifier.ClusterId,
+			K8sRequest: &k8s2.K8sRequestBean{
+				ResourceIdentifier: k8s2.ResourceIdentifier{
+					Name:             name,
+					Namespace:        namespace,
+					GroupVersionKind: schema.GroupVersionKind{Group: group, Version: version, Kind: kind},
+				},
+			},
+		})
+		if err != nil {
+			impl.logger.Errorw("error in fetching live manifest", "err", err, "kind", kind, "name", name)
+			return nil, err
+		}
+		resource.live = liveManifest.Manifest.Object
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func toManifest(value interface{}) map[string]interface{} {
+	manifest, _ := value.(map[string]interface{})
+	return manifest
+}
+
+func (impl *ConfigDriftServiceImpl) ScanLiveDrift() {
+	pipelines, err := impl.pipelineRepository.FindActiveWithDeploymentAppCreated()
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed pipelines for drift scan", "err", err)
+		return
+	}
+	for _, pipeline := range pipelines {
+		if !util.IsAcdApp(pipeline.DeploymentAppType) && !util.IsHelmApp(pipeline.DeploymentAppType) {
+			continue
+		}
+		impl.scanPipeline(pipeline)
+	}
+}
+
+func (impl *ConfigDriftServiceImpl) scanPipeline(pipeline *pipelineConfig.Pipeline) {
+	wfr, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(pipeline.Id, bean2.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching latest deployment for drift scan", "err", err, "pipelineId", pipeline.Id)
+		return
+	}
+	//resources differ from the desired state while a deployment rolls out
+	if wfr.Status == pipelineConfig.WorkflowStarting || wfr.Status == pipelineConfig.WorkflowInProgress {
+		return
+	}
+	scan, err := impl.configDriftScanRepository.FindByPipelineId(pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching config drift scan", "err", err, "pipelineId", pipeline.Id)
+		return
+	}
+	isNewScan := err == pg.ErrNoRows
+	if isNewScan {
+		scan = &repository.ConfigDriftScan{PipelineId: pipeline.Id, AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: 1}}
+	}
+	scan.ScannedOn = time.Now()
+	scan.UpdatedOn = time.Now()
+	scan.UpdatedBy = 1
+	components, err := impl.getLiveStateDrift(context.Background(), pipeline)
+	if err != nil {
+		scan.ScanError = err.Error()
+	} else {
+		scan.ScanError = ""
+		driftedResources, digest := summarizeLiveDrift(components)
+		scan.Drifted = len(driftedResources) > 0
+		scan.DriftedResources = strings.Join(driftedResources, ",")
+		scan.DriftDigest = digest
+		if !scan.Drifted {
+			//drift showing up again after being fixed is notified again
+			scan.NotifiedDigest = ""
+		} else if scan.NotifiedDigest != digest {
+			if impl.sendDriftNotification(pipeline, driftedResources) {
+				scan.NotifiedDigest = digest
+			}
+		}
+	}
+	if isNewScan {
+		err = impl.configDriftScanRepository.Save(scan)
+	} else {
+		err = impl.configDriftScanRepository.Update(scan)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving config drift scan", "err", err, "pipelineId", pipeline.Id)
+	}
+}
+
+// summarizeLiveDrift returns the drifted resources and a digest of their drift which changes only when the drift does
+func summarizeLiveDrift(components []*ComponentDrift) ([]string, string) {
+	var driftedResources []string
+	var drifted []*ComponentDrift
+	for _, component := range components {
+		if component.Status == DRIFT_STATUS_UNCHANGED {
+			continue
+		}
+		driftedResources = append(driftedResources, fmt.Sprintf("%s/%s", component.Kind, component.Name))
+		drifted = append(drifted, &ComponentDrift{Kind: component.Kind, Name: component.Name, Namespace: component.Namespace, Status: component.Status, JsonPatch: component.JsonPatch})
+	}
+	if len(drifted) == 0 {
+		return nil, ""
+	}
+	content, _ := json.Marshal(drifted)
+	sum := sha256.Sum256(content)
+	return driftedResources, hex.EncodeToString(sum[:])
+}
+
+func (impl *ConfigDriftServiceImpl) sendDriftNotification(pipeline *pipelineConfig.Pipeline, driftedResources []string) bool {
+	event := impl.eventFactory.Build(util2.ConfigDrift, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+	event.Payload = &client.Payload{DriftSummary: fmt.Sprintf("%d resource(s) differ from the deployed state: %s", len(driftedResources), strings.Join(driftedResources, ", "))}
+	_, err := impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("config drift event not sent", "err", err, "pipelineId", pipeline.Id)
+		return false
+	}
+	return true
+}
+
+func newDriftTarget(pipeline *pipelineConfig.Pipeline, wfrId int) *DriftTarget {
+	return &DriftTarget{
+		PipelineId:      pipeline.Id,
+		EnvironmentId:   pipeline.EnvironmentId,
+		EnvironmentName: pipeline.Environment.Name,
+		WfrId:           wfrId,
+	}
+}
+
+func (target *DriftTarget) label() string {
+	if target.Live {
+		return fmt.Sprintf("%s (live)", target.EnvironmentName)
+	}
+	return fmt.Sprintf("%s (deployment %d)", target.EnvironmentName, target.WfrId)
+}
+
+func newConfigDriftResponse(driftType DriftType, base *DriftTarget, compare *DriftTarget, components []*ComponentDrift) *ConfigDriftResponse {
+	response := &ConfigDriftResponse{Type: driftType, Base: base, Compare: compare, Components: components}
+	for _, component := range components {
+		if component.Status != DRIFT_STATUS_UNCHANGED {
+			response.Drifted = true
+		}
+	}
+	return response
+}
+
+func newDriftApiError(statusCode int, message string) *util.ApiError {
+	return &util.ApiError{HttpStatusCode: statusCode, InternalMessage: message, UserMessage: message}
+}
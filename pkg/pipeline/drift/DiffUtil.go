@@ -0,0 +1,214 @@
+package drift
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"reflect"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// CreateJsonPatch returns the RFC 6902 operations which transform base into compare. Both values are expected in
+// their generic json form (maps, slices and scalars as produced by json.Unmarshal)
+func CreateJsonPatch(base interface{}, compare interface{}) []*JsonPatchOperation {
+	var operations []*JsonPatchOperation
+	appendJsonPatch("", base, compare, &operations)
+	return operations
+}
+
+func appendJsonPatch(path string, base interface{}, compare interface{}, operations *[]*JsonPatchOperation) {
+	switch baseValue := base.(type) {
+	case map[string]interface{}:
+		compareValue, ok := compare.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(baseValue) {
+			childPath := path + "/" + escapeJsonPointer(key)
+			if compareChild, exists := compareValue[key]; exists {
+				appendJsonPatch(childPath, baseValue[key], compareChild, operations)
+			} else {
+				*operations = append(*operations, &JsonPatchOperation{Op: JSON_PATCH_REMOVE, Path: childPath})
+			}
+		}
+		for _, key := range sortedKeys(compareValue) {
+			if _, exists := baseValue[key]; !exists {
+				*operations = append(*operations, &JsonPatchOperation{Op: JSON_PATCH_ADD, Path: path + "/" + escapeJsonPointer(key), Value: compareValue[key]})
+			}
+		}
+		return
+	case []interface{}:
+		compareValue, ok := compare.([]interface{})
+		if !ok {
+			break
+		}
+		common := len(baseValue)
+		if len(compareValue) < common {
+			common = len(compareValue)
+		}
+		for i := 0; i < common; i++ {
+			appendJsonPatch(fmt.Sprintf("%s/%d", path, i), baseValue[i], compareValue[i], operations)
+		}
+		//removing from the end keeps the indexes of the remaining operations valid
+		for i := len(baseValue) - 1; i >= common; i-- {
+			*operations = append(*operations, &JsonPatchOperation{Op: JSON_PATCH_REMOVE, Path: fmt.Sprintf("%s/%d", path, i)})
+		}
+		for i := common; i < len(compareValue); i++ {
+			*operations = append(*operations, &JsonPatchOperation{Op: JSON_PATCH_ADD, Path: path + "/-", Value: compareValue[i]})
+		}
+		return
+	}
+	if !reflect.DeepEqual(base, compare) {
+		*operations = append(*operations, &JsonPatchOperation{Op: JSON_PATCH_REPLACE, Path: path, Value: compare})
+	}
+}
+
+func escapeJsonPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UnifiedYamlDiff renders both values as yaml and returns their unified diff, empty when they are equal
+func UnifiedYamlDiff(baseName string, compareName string, base interface{}, compare interface{}) (string, error) {
+	baseYaml, err := toYaml(base)
+	if err != nil {
+		return "", err
+	}
+	compareYaml, err := toYaml(compare)
+	if err != nil {
+		return "", err
+	}
+	if baseYaml == compareYaml {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(baseYaml),
+		B:        difflib.SplitLines(compareYaml),
+		FromFile: baseName,
+		ToFile:   compareName,
+		Context:  3,
+	})
+}
+
+func toYaml(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ParseJsonOrYaml converts a json or yaml document to its generic json form, documents which are neither are kept as
+// plain strings
+func ParseJsonOrYaml(document string) interface{} {
+	if len(strings.TrimSpace(document)) == 0 {
+		return nil
+	}
+	jsonDocument, err := yaml.YAMLToJSON([]byte(document))
+	if err != nil {
+		return document
+	}
+	var value interface{}
+	if err = json.Unmarshal(jsonDocument, &value); err != nil {
+		return document
+	}
+	return value
+}
+
+// ProjectLiveState keeps only the parts of live that are set in desired, fields defaulted or added by the cluster
+// and controllers are not drift. Lists of different length are compared as a whole
+func ProjectLiveState(desired interface{}, live interface{}) interface{} {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		projected := make(map[string]interface{}, len(desiredValue))
+		for key, desiredChild := range desiredValue {
+			if liveChild, exists := liveValue[key]; exists {
+				projected[key] = ProjectLiveState(desiredChild, liveChild)
+			}
+		}
+		return projected
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(desiredValue) {
+			return live
+		}
+		projected := make([]interface{}, len(liveValue))
+		for i := range liveValue {
+			projected[i] = ProjectLiveState(desiredValue[i], liveValue[i])
+		}
+		return projected
+	}
+	return live
+}
+
+// NormalizeManifest drops the fields of a kubernetes manifest which are owned by the cluster and converts secret
+// stringData to data, so that desired and live manifests can be compared
+func NormalizeManifest(manifest map[string]interface{}) map[string]interface{} {
+	if manifest == nil {
+		return nil
+	}
+	delete(manifest, "status")
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp", "selfLink"} {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		}
+	}
+	if manifest["kind"] == "Secret" {
+		if stringData, ok := manifest["stringData"].(map[string]interface{}); ok {
+			data, _ := manifest["data"].(map[string]interface{})
+			if data == nil {
+				data = make(map[string]interface{}, len(stringData))
+			}
+			for key, value := range stringData {
+				data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+			}
+			manifest["data"] = data
+			delete(manifest, "stringData")
+		}
+	}
+	return manifest
+}
+
+// MaskSecretData replaces the values of both data maps, keys with different values are marked as modified on the
+// compared side so that the drift is visible without revealing the values
+func MaskSecretData(base map[string]interface{}, compare map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	var maskedBase, maskedCompare map[string]interface{}
+	if base != nil {
+		maskedBase = make(map[string]interface{}, len(base))
+		for key := range base {
+			maskedBase[key] = MaskedSecretValue
+		}
+	}
+	if compare != nil {
+		maskedCompare = make(map[string]interface{}, len(compare))
+		for key, value := range compare {
+			if baseValue, ok := base[key]; ok && !reflect.DeepEqual(baseValue, value) {
+				maskedCompare[key] = MaskedModifiedSecretValue
+			} else {
+				maskedCompare[key] = MaskedSecretValue
+			}
+		}
+	}
+	return maskedBase, maskedCompare
+}
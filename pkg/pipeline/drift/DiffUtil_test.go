@@ -0,0 +1,123 @@
+package drift
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/pipeline/history"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func parseJson(t *testing.T, document string) interface{} {
+	var value interface{}
+	assert.Nil(t, json.Unmarshal([]byte(document), &value))
+	return value
+}
+
+func TestCreateJsonPatch(t *testing.T) {
+	base := `{"replicaCount":1,"image":{"tag":"v1"},"env":[{"name":"A","value":"1"},{"name":"B","value":"2"}],"a/b":"x","removed":true}`
+	compare := `{"replicaCount":3,"image":{"tag":"v1","pullPolicy":"Always"},"env":[{"name":"A","value":"10"}],"a/b":"y"}`
+	operations := CreateJsonPatch(parseJson(t, base), parseJson(t, compare))
+	patch, err := json.Marshal(operations)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[
+		{"op":"replace","path":"/a~1b","value":"y"},
+		{"op":"replace","path":"/env/0/value","value":"10"},
+		{"op":"remove","path":"/env/1"},
+		{"op":"add","path":"/image/pullPolicy","value":"Always"},
+		{"op":"remove","path":"/removed"},
+		{"op":"replace","path":"/replicaCount","value":3}
+	]`, string(patch))
+
+	//applying the patch on base must give compare
+	decoded, err := jsonpatch.DecodePatch(patch)
+	assert.Nil(t, err)
+	patched, err := decoded.Apply([]byte(base))
+	assert.Nil(t, err)
+	assert.JSONEq(t, compare, string(patched))
+
+	assert.Empty(t, CreateJsonPatch(parseJson(t, base), parseJson(t, base)))
+}
+
+func TestUnifiedYamlDiff(t *testing.T) {
+	diff, err := UnifiedYamlDiff("dev", "prod", parseJson(t, `{"replicaCount":1,"image":"app:v1"}`), parseJson(t, `{"replicaCount":2,"image":"app:v1"}`))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(diff, "--- dev\n+++ prod\n"))
+	assert.Contains(t, diff, "-replicaCount: 1\n+replicaCount: 2\n")
+
+	diff, err = UnifiedYamlDiff("dev", "prod", parseJson(t, `{"a":1}`), parseJson(t, `{"a":1}`))
+	assert.Nil(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffConfigComponentsMasksSecrets(t *testing.T) {
+	external := false
+	deployed := func(template string, secretData string) *history.AllDeploymentConfigurationDetail {
+		return &history.AllDeploymentConfigurationDetail{
+			DeploymentTemplateConfig: &history.HistoryDetailDto{TemplateName: "Deployment", TemplateVersion: "4.17.0", CodeEditorValue: &history.HistoryDetailConfig{Value: template}},
+			SecretConfig: []*history.ComponentLevelHistoryDetailDto{{ComponentName: "db",
+				HistoryConfig: &history.HistoryDetailDto{Type: "environment", External: &external, CodeEditorValue: &history.HistoryDetailConfig{Value: secretData}}}},
+		}
+	}
+	base := BuildConfigComponents(deployed(`{"replicaCount":1}`, `{"USER":"YWRtaW4=","PASSWORD":"c2VjcmV0"}`))
+	compare := BuildConfigComponents(deployed(`{"replicaCount":1}`, `{"USER":"YWRtaW4=","PASSWORD":"b3RoZXI="}`))
+	compare["ConfigMap/app"] = &configComponent{kind: COMPONENT_CONFIGMAP, name: "app", value: map[string]interface{}{"data": map[string]interface{}{"LOG_LEVEL": "debug"}}}
+
+	drifts, err := DiffConfigComponents(base, compare, "dev", "prod")
+	assert.Nil(t, err)
+	assert.Len(t, drifts, 3)
+	assert.Equal(t, COMPONENT_DEPLOYMENT_TEMPLATE, drifts[0].Kind)
+	assert.Equal(t, DRIFT_STATUS_UNCHANGED, drifts[0].Status)
+	assert.Equal(t, COMPONENT_CONFIGMAP, drifts[1].Kind)
+	assert.Equal(t, DRIFT_STATUS_ADDED, drifts[1].Status)
+
+	secretDrift := drifts[2]
+	assert.Equal(t, DRIFT_STATUS_MODIFIED, secretDrift.Status)
+	assert.True(t, secretDrift.Masked)
+	content, err := json.Marshal(secretDrift)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(content), "c2VjcmV0")
+	assert.NotContains(t, string(content), "b3RoZXI=")
+	assert.Len(t, secretDrift.JsonPatch, 1)
+	assert.Equal(t, "/data/PASSWORD", secretDrift.JsonPatch[0].Path)
+	assert.Equal(t, MaskedModifiedSecretValue, secretDrift.JsonPatch[0].Value)
+}
+
+func TestDiffLiveResources(t *testing.T) {
+	desired := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"prod","labels":{"app":"app"}},
+		"spec":{"replicas":2,"template":{"spec":{"containers":[{"name":"app","image":"app:v1"}]}}}}`
+	//defaults, status and metadata set by the cluster are not drift, the scaled replicas are
+	live := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"prod","uid":"1","resourceVersion":"10",
+		"labels":{"app":"app"},"annotations":{"deployment.kubernetes.io/revision":"3"}},
+		"spec":{"replicas":5,"progressDeadlineSeconds":600,"template":{"spec":{"containers":[{"name":"app","image":"app:v1","imagePullPolicy":"IfNotPresent"}]}}},
+		"status":{"readyReplicas":5}}`
+	secret := `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db","namespace":"prod"},"stringData":{"PASSWORD":"secret"}}`
+	liveSecret := `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db","namespace":"prod"},"type":"Opaque","data":{"PASSWORD":"c2VjcmV0"}}`
+	resources := []*liveResource{
+		{kind: "Deployment", name: "app", namespace: "prod", desired: toManifest(ParseJsonOrYaml(desired)), live: toManifest(ParseJsonOrYaml(live))},
+		{kind: "Secret", name: "db", namespace: "prod", desired: toManifest(ParseJsonOrYaml(secret)), live: toManifest(ParseJsonOrYaml(liveSecret))},
+		{kind: "Service", name: "app", namespace: "prod", desired: toManifest(ParseJsonOrYaml(`{"kind":"Service","metadata":{"name":"app"}}`)), live: toManifest(ParseJsonOrYaml("null"))},
+	}
+	drifts, err := DiffLiveResources(resources)
+	assert.Nil(t, err)
+	assert.Len(t, drifts, 3)
+
+	assert.Equal(t, "Deployment", drifts[0].Kind)
+	assert.Equal(t, DRIFT_STATUS_MODIFIED, drifts[0].Status)
+	assert.Len(t, drifts[0].JsonPatch, 1)
+	assert.Equal(t, &JsonPatchOperation{Op: JSON_PATCH_REPLACE, Path: "/spec/replicas", Value: float64(5)}, drifts[0].JsonPatch[0])
+	assert.Contains(t, drifts[0].UnifiedDiff, "+  replicas: 5")
+
+	assert.Equal(t, "Secret", drifts[1].Kind)
+	assert.Equal(t, DRIFT_STATUS_UNCHANGED, drifts[1].Status)
+	assert.True(t, drifts[1].Masked)
+
+	assert.Equal(t, "Service", drifts[2].Kind)
+	assert.Equal(t, DRIFT_STATUS_REMOVED, drifts[2].Status)
+
+	driftedResources, digest := summarizeLiveDrift(drifts)
+	assert.Equal(t, []string{"Deployment/app", "Service/app"}, driftedResources)
+	_, sameDigest := summarizeLiveDrift(drifts)
+	assert.Equal(t, digest, sameDigest)
+}
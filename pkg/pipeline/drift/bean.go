@@ -0,0 +1,82 @@
+package drift
+
+import "encoding/json"
+
+type DriftType string
+
+const (
+	DRIFT_TYPE_ENVIRONMENT DriftType = "ENVIRONMENT"
+	DRIFT_TYPE_RELEASE     DriftType = "RELEASE"
+	DRIFT_TYPE_LIVE        DriftType = "LIVE"
+)
+
+// DriftStatus describes the compared side relative to the base side of a drift
+type DriftStatus string
+
+const (
+	DRIFT_STATUS_UNCHANGED DriftStatus = "UNCHANGED"
+	DRIFT_STATUS_MODIFIED  DriftStatus = "MODIFIED"
+	DRIFT_STATUS_ADDED     DriftStatus = "ADDED"
+	DRIFT_STATUS_REMOVED   DriftStatus = "REMOVED"
+)
+
+// components of a deployed configuration, kubernetes kinds are used as component kind for live state drift
+const (
+	COMPONENT_DEPLOYMENT_TEMPLATE = "DeploymentTemplate"
+	COMPONENT_CONFIGMAP           = "ConfigMap"
+	COMPONENT_SECRET              = "Secret"
+	COMPONENT_PIPELINE_STRATEGY   = "PipelineStrategy"
+)
+
+const (
+	MaskedSecretValue         = "*****"
+	MaskedModifiedSecretValue = "*****(modified)"
+)
+
+type ConfigDriftResponse struct {
+	Type       DriftType         `json:"type"`
+	Base       *DriftTarget      `json:"base"`
+	Compare    *DriftTarget      `json:"compare"`
+	Drifted    bool              `json:"drifted"`
+	Components []*ComponentDrift `json:"components"`
+}
+
+// DriftTarget is one side of a drift, a deployment of a cd pipeline or the live state of its resources
+type DriftTarget struct {
+	PipelineId      int    `json:"pipelineId"`
+	EnvironmentId   int    `json:"environmentId"`
+	EnvironmentName string `json:"environmentName"`
+	WfrId           int    `json:"wfrId,omitempty"`
+	Live            bool   `json:"live,omitempty"`
+}
+
+type ComponentDrift struct {
+	Kind        string                `json:"kind"`
+	Name        string                `json:"name,omitempty"`
+	Namespace   string                `json:"namespace,omitempty"`
+	Status      DriftStatus           `json:"status"`
+	JsonPatch   []*JsonPatchOperation `json:"jsonPatch,omitempty"`
+	UnifiedDiff string                `json:"unifiedDiff,omitempty"`
+	Masked      bool                  `json:"masked,omitempty"`
+}
+
+// JsonPatchOperation is an RFC 6902 operation which transforms the base side into the compared side
+type JsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+func (operation *JsonPatchOperation) MarshalJSON() ([]byte, error) {
+	if operation.Op == JSON_PATCH_REMOVE {
+		return json.Marshal(map[string]string{"op": operation.Op, "path": operation.Path})
+	}
+	type jsonPatchOperation JsonPatchOperation
+	return json.Marshal((*jsonPatchOperation)(operation))
+}
+
+const (
+	JSON_PATCH_ADD     = "add"
+	JSON_PATCH_REMOVE  = "remove"
+	JSON_PATCH_REPLACE = "replace"
+)
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ConfigDriftScanRepository interface {
+	Save(model *ConfigDriftScan) error
+	Update(model *ConfigDriftScan) error
+	FindByPipelineId(pipelineId int) (*ConfigDriftScan, error)
+}
+
+type ConfigDriftScanRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewConfigDriftScanRepositoryImpl(logger *zap.SugaredLogger, dbConnection *pg.DB) *ConfigDriftScanRepositoryImpl {
+	return &ConfigDriftScanRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+// ConfigDriftScan holds the result of the last live state scan of a cd pipeline, the digest of the notified drift is
+// kept so that the same drift is notified only once
+type ConfigDriftScan struct {
+	TableName        struct{}  `sql:"config_drift_scan" pg:",discard_unknown_columns"`
+	Id               int       `sql:"id,pk"`
+	PipelineId       int       `sql:"pipeline_id,notnull"`
+	Drifted          bool      `sql:"drifted,notnull"`
+	DriftedResources string    `sql:"drifted_resources"`
+	DriftDigest      string    `sql:"drift_digest"`
+	NotifiedDigest   string    `sql:"notified_digest"`
+	ScanError        string    `sql:"scan_error"`
+	ScannedOn        time.Time `sql:"scanned_on"`
+	sql.AuditLog
+}
+
+func (impl ConfigDriftScanRepositoryImpl) Save(model *ConfigDriftScan) error {
+	err := impl.dbConnection.Insert(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving config drift scan", "err", err, "pipelineId", model.PipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl ConfigDriftScanRepositoryImpl) Update(model *ConfigDriftScan) error {
+	err := impl.dbConnection.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating config drift scan", "err", err, "pipelineId", model.PipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl ConfigDriftScanRepositoryImpl) FindByPipelineId(pipelineId int) (*ConfigDriftScan, error) {
+	model := &ConfigDriftScan{}
+	err := impl.dbConnection.Model(model).Where("pipeline_id = ?", pipelineId).Select()
+	return model, err
+}
@@ -290,6 +290,14 @@ func (impl ConfigMapHistoryServiceImpl) MergeAppLevelAndEnvLevelConfigs(appLevel
 			}
 		}
 	}
+	if configType == repository.SECRET_TYPE {
+		//values of external secrets may be resolved at deploy time, only their references are kept in history
+		for _, item := range finalConfigs {
+			if item.External {
+				item.Data = nil
+			}
+		}
+	}
 	var finalConfigDataByte []byte
 	if configType == repository.CONFIGMAP_TYPE {
 		var finalConfigList ConfigList
@@ -449,7 +457,7 @@ func (impl ConfigMapHistoryServiceImpl) GetHistoryForDeployedCMCSById(id, pipeli
 	}
 	if configType == repository.SECRET_TYPE {
 		if config.Data != nil {
-			if !userHasAdminAccess {
+			if !userHasAdminAccess || config.External {
 				//removing keys and sending
 				resultMap := make(map[string]string)
 				resultMapFinal := make(map[string]string)
@@ -538,7 +546,7 @@ func (impl ConfigMapHistoryServiceImpl) ConvertConfigDataToComponentLevelDto(con
 	var err error
 	if configType == repository.SECRET_TYPE {
 		if config.Data != nil {
-			if !userHasAdminAccess {
+			if !userHasAdminAccess || config.External {
 				//removing keys and sending
 				resultMap := make(map[string]string)
 				resultMapFinal := make(map[string]string)
@@ -7,20 +7,23 @@ import (
 )
 
 type ManifestPushConfig struct {
-	tableName         struct{} `sql:"manifest_push_config" pg:",discard_unknown_columns"`
-	Id                int      `sql:"id,pk"`
-	AppId             int      `sql:"app_id"`
-	EnvId             int      `sql:"env_id"`
-	CredentialsConfig string   `sql:"credentials_config"`
-	ChartName         string   `sql:"chart_name"`
-	ChartBaseVersion  string   `sql:"chart_base_version"`
-	StorageType       string   `sql:"storage_type"`
-	Deleted           bool     `sql:"deleted, notnull"`
+	tableName          struct{} `sql:"manifest_push_config" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	AppId              int      `sql:"app_id"`
+	EnvId              int      `sql:"env_id"`
+	CredentialsConfig  string   `sql:"credentials_config"`
+	ChartName          string   `sql:"chart_name"`
+	ChartBaseVersion   string   `sql:"chart_base_version"`
+	StorageType        string   `sql:"storage_type"`
+	DeployFromOciChart bool     `sql:"deploy_from_oci_chart,notnull"`
+	Deleted            bool     `sql:"deleted, notnull"`
 	sql.AuditLog
 }
 
 type ManifestPushConfigRepository interface {
-	SaveConfig(manifestPushConfig *ManifestPushConfig) (*ManifestPushConfig, error)
+	GetConnection() *pg.DB
+	SaveConfig(manifestPushConfig *ManifestPushConfig, tx *pg.Tx) (*ManifestPushConfig, error)
+	UpdateConfig(manifestPushConfig *ManifestPushConfig, tx *pg.Tx) error
 	GetManifestPushConfigByAppIdAndEnvId(appId, envId int) (*ManifestPushConfig, error)
 }
 
@@ -38,22 +41,36 @@ func NewManifestPushConfigRepository(logger *zap.SugaredLogger,
 	}
 }
 
-func (impl ManifestPushConfigRepositoryImpl) SaveConfig(manifestPushConfig *ManifestPushConfig) (*ManifestPushConfig, error) {
-	err := impl.dbConnection.Insert(manifestPushConfig)
+func (impl ManifestPushConfigRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl ManifestPushConfigRepositoryImpl) SaveConfig(manifestPushConfig *ManifestPushConfig, tx *pg.Tx) (*ManifestPushConfig, error) {
+	err := tx.Insert(manifestPushConfig)
 	if err != nil {
 		return manifestPushConfig, err
 	}
 	return manifestPushConfig, err
 }
 
+func (impl ManifestPushConfigRepositoryImpl) UpdateConfig(manifestPushConfig *ManifestPushConfig, tx *pg.Tx) error {
+	return tx.Update(manifestPushConfig)
+}
+
+// GetManifestPushConfigByAppIdAndEnvId returns nil without error if no active config is found
 func (impl ManifestPushConfigRepositoryImpl) GetManifestPushConfigByAppIdAndEnvId(appId, envId int) (*ManifestPushConfig, error) {
-	var manifestPushConfig *ManifestPushConfig
+	manifestPushConfig := &ManifestPushConfig{}
 	err := impl.dbConnection.Model(manifestPushConfig).
 		Where("app_id = ? ", appId).
 		Where("env_id = ? ", envId).
+		Where("deleted = ? ", false).
+		Limit(1).
 		Select()
-	if err != nil && err != pg.ErrNoRows {
-		return manifestPushConfig, err
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 	return manifestPushConfig, nil
 }
@@ -1,10 +1,20 @@
 package plugin
 
 import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
 )
 
 type GlobalVariable struct {
@@ -19,6 +29,14 @@ type GlobalPluginService interface {
 	GetAllGlobalVariables() ([]*GlobalVariable, error)
 	ListAllPlugins(stageType int) ([]*PluginListComponentDto, error)
 	GetPluginDetailById(pluginId int) (*PluginDetailDto, error)
+
+	CreatePlugin(definition *PluginDefinitionDto, userId int32) (*PluginMetadataDto, error)
+	UpdatePluginMetadata(pluginId int, request *PluginMetadataUpdateDto, userId int32) (*PluginMetadataDto, error)
+	DeletePlugin(pluginId int, userId int32) error
+	GetPluginVersions(pluginId int) ([]*PluginMetadataDto, error)
+	GetPluginDefinition(pluginId int) (*PluginDefinitionDto, error)
+	ExportPlugin(pluginId int) ([]byte, error)
+	ParsePluginDefinition(data []byte) (*PluginDefinitionDto, error)
 }
 
 func NewGlobalPluginService(logger *zap.SugaredLogger, globalPluginRepository repository.GlobalPluginRepository) *GlobalPluginServiceImpl {
@@ -167,13 +185,7 @@ func (impl *GlobalPluginServiceImpl) ListAllPlugins(stageType int) ([]*PluginLis
 		return nil, err
 	}
 	for _, pluginMetadata := range pluginsMetadata {
-		pluginMetadataDto := &PluginMetadataDto{
-			Id:          pluginMetadata.Id,
-			Name:        pluginMetadata.Name,
-			Type:        string(pluginMetadata.Type),
-			Description: pluginMetadata.Description,
-			Icon:        pluginMetadata.Icon,
-		}
+		pluginMetadataDto := getPluginMetadataDto(pluginMetadata)
 		tags, ok := pluginIdTagsMap[pluginMetadata.Id]
 		if ok {
 			pluginMetadataDto.Tags = tags
@@ -197,13 +209,7 @@ func (impl *GlobalPluginServiceImpl) GetPluginDetailById(pluginId int) (*PluginD
 		impl.logger.Errorw("error in getting plugins", "err", err, "pluginId", pluginId)
 		return nil, err
 	}
-	metadataDto := &PluginMetadataDto{
-		Id:          pluginMetadata.Id,
-		Name:        pluginMetadata.Name,
-		Type:        string(pluginMetadata.Type),
-		Description: pluginMetadata.Description,
-		Icon:        pluginMetadata.Icon,
-	}
+	metadataDto := getPluginMetadataDto(pluginMetadata)
 	pluginDetail := &PluginDetailDto{
 		Metadata: metadataDto,
 	}
@@ -278,6 +284,20 @@ func (impl *GlobalPluginServiceImpl) getIOVariablesOfAPlugin(pluginId int) (inpu
 	return inputVariablesDto, outputVariablesDto, nil
 }
 
+func getPluginMetadataDto(pluginMetadata *repository.PluginMetadata) *PluginMetadataDto {
+	return &PluginMetadataDto{
+		Id:               pluginMetadata.Id,
+		Name:             pluginMetadata.Name,
+		Type:             string(pluginMetadata.Type),
+		Description:      pluginMetadata.Description,
+		Icon:             pluginMetadata.Icon,
+		PluginIdentifier: pluginMetadata.PluginIdentifier,
+		PluginVersion:    pluginMetadata.PluginVersion,
+		IsLatest:         pluginMetadata.IsLatest,
+		IsDeprecated:     pluginMetadata.IsDeprecated,
+	}
+}
+
 func getVariableDto(pluginVariable *repository.PluginStepVariable) *PluginVariableDto {
 	return &PluginVariableDto{
 		Id:                    pluginVariable.Id,
@@ -294,3 +314,605 @@ func getVariableDto(pluginVariable *repository.PluginStepVariable) *PluginVariab
 		ReferenceVariableName: pluginVariable.ReferenceVariableName,
 	}
 }
+
+var pluginIdentifierRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+var stageTypeNameMap = map[string]int{"ci": repository.CI, "cd": repository.CD, "ci_cd": repository.CI_CD, "": repository.CI_CD}
+
+func newPluginBadRequestError(message string) *util.ApiError {
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: message, UserMessage: message}
+}
+
+// validatePluginDefinition checks the parts of a definition which the struct validator can not express
+func validatePluginDefinition(definition *PluginDefinitionDto) error {
+	if !pluginIdentifierRegex.MatchString(definition.PluginIdentifier) {
+		return newPluginBadRequestError("pluginIdentifier must contain only lowercase alphanumeric characters and '-'")
+	}
+	if _, err := semver.StrictNewVersion(definition.PluginVersion); err != nil {
+		return newPluginBadRequestError(fmt.Sprintf("pluginVersion %q is not a valid semantic version", definition.PluginVersion))
+	}
+	if _, ok := stageTypeNameMap[definition.StageType]; !ok {
+		return newPluginBadRequestError(fmt.Sprintf("invalid stageType %q", definition.StageType))
+	}
+	stepIndexes := make(map[int]bool)
+	for _, step := range definition.Steps {
+		if step.Index < 1 || step.Index > len(definition.Steps) || stepIndexes[step.Index] {
+			return newPluginBadRequestError("step indexes must be unique and run from 1 to the number of steps")
+		}
+		stepIndexes[step.Index] = true
+		switch step.StepType {
+		case repository.PLUGIN_STEP_TYPE_INLINE, "":
+			if step.Script == nil {
+				return newPluginBadRequestError(fmt.Sprintf("script is required for inline step %q", step.Name))
+			}
+		case repository.PLUGIN_STEP_TYPE_REF_PLUGIN:
+			if len(step.RefPluginIdentifier) == 0 || len(step.RefPluginVersion) == 0 {
+				return newPluginBadRequestError(fmt.Sprintf("refPluginIdentifier and refPluginVersion are required for step %q", step.Name))
+			}
+			if step.RefPluginIdentifier == definition.PluginIdentifier {
+				return newPluginBadRequestError(fmt.Sprintf("step %q can not refer to its own plugin", step.Name))
+			}
+		default:
+			return newPluginBadRequestError(fmt.Sprintf("invalid stepType %q for step %q", step.StepType, step.Name))
+		}
+		variableNames := make(map[string]bool)
+		for _, variable := range append(append([]*PluginVariableDto{}, step.InputVariables...), step.OutputVariables...) {
+			if len(variable.Name) == 0 || variableNames[variable.Name] {
+				return newPluginBadRequestError(fmt.Sprintf("variable names of step %q must be non empty and unique", step.Name))
+			}
+			variableNames[variable.Name] = true
+		}
+		for _, condition := range step.ConditionDetails {
+			if !variableNames[condition.ConditionOnVariable] {
+				return newPluginBadRequestError(fmt.Sprintf("condition of step %q refers to unknown variable %q", step.Name, condition.ConditionOnVariable))
+			}
+			switch condition.ConditionType {
+			case repository.PLUGIN_CONDITION_TYPE_SKIP, repository.PLUGIN_CONDITION_TYPE_TRIGGER,
+				repository.PLUGIN_CONDITION_TYPE_SUCCESS, repository.PLUGIN_CONDITION_TYPE_FAIL:
+			default:
+				return newPluginBadRequestError(fmt.Sprintf("invalid conditionType %q for step %q", condition.ConditionType, step.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// getLatestPluginVersion returns the plugin with the highest semantic version, versions which can not be parsed rank lowest
+func getLatestPluginVersion(plugins []*repository.PluginMetadata) *repository.PluginMetadata {
+	var latest *repository.PluginMetadata
+	var latestVersion *semver.Version
+	for _, plugin := range plugins {
+		version, err := semver.NewVersion(plugin.PluginVersion)
+		if err != nil {
+			if latest == nil {
+				latest = plugin
+			}
+			continue
+		}
+		if latestVersion == nil || version.GreaterThan(latestVersion) {
+			latest, latestVersion = plugin, version
+		}
+	}
+	return latest
+}
+
+// sortPluginsByVersion orders plugins from the highest to the lowest semantic version
+func sortPluginsByVersion(plugins []*repository.PluginMetadata) {
+	sort.SliceStable(plugins, func(i, j int) bool {
+		vi, errI := semver.NewVersion(plugins[i].PluginVersion)
+		vj, errJ := semver.NewVersion(plugins[j].PluginVersion)
+		if errI != nil || errJ != nil {
+			return errJ != nil && errI == nil
+		}
+		return vi.GreaterThan(vj)
+	})
+}
+
+func (impl *GlobalPluginServiceImpl) CreatePlugin(definition *PluginDefinitionDto, userId int32) (*PluginMetadataDto, error) {
+	err := validatePluginDefinition(definition)
+	if err != nil {
+		impl.logger.Errorw("invalid plugin definition", "err", err, "pluginIdentifier", definition.PluginIdentifier)
+		return nil, err
+	}
+	_, err = impl.globalPluginRepository.GetPluginByIdentifierAndVersion(definition.PluginIdentifier, definition.PluginVersion)
+	if err == nil {
+		return nil, newPluginVersionExistsError(definition)
+	} else if err != pg.ErrNoRows {
+		return nil, err
+	}
+	refPluginIds := make(map[int]int)
+	for _, step := range definition.Steps {
+		if step.StepType != repository.PLUGIN_STEP_TYPE_REF_PLUGIN {
+			continue
+		}
+		refPlugin, err := impl.globalPluginRepository.GetPluginByIdentifierAndVersion(step.RefPluginIdentifier, step.RefPluginVersion)
+		if err == pg.ErrNoRows {
+			return nil, newPluginBadRequestError(fmt.Sprintf("referenced plugin %s@%s not found", step.RefPluginIdentifier, step.RefPluginVersion))
+		} else if err != nil {
+			return nil, err
+		}
+		refPluginIds[step.Index] = refPlugin.Id
+	}
+	dbConnection := impl.globalPluginRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	pluginType := definition.Type
+	if len(pluginType) == 0 {
+		pluginType = repository.PLUGIN_TYPE_SHARED
+	}
+	auditLog := sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId}
+	pluginMetadata := &repository.PluginMetadata{
+		Name:             definition.Name,
+		Description:      definition.Description,
+		Type:             pluginType,
+		Icon:             definition.Icon,
+		PluginIdentifier: definition.PluginIdentifier,
+		PluginVersion:    definition.PluginVersion,
+		IsDeprecated:     definition.IsDeprecated,
+		AuditLog:         auditLog,
+	}
+	pluginMetadata, err = impl.globalPluginRepository.SavePluginMetadata(pluginMetadata, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving plugin metadata", "err", err, "pluginIdentifier", definition.PluginIdentifier)
+		// the same version got created concurrently after the check above
+		if isUniqueViolation(err) {
+			return nil, newPluginVersionExistsError(definition)
+		}
+		return nil, err
+	}
+	err = impl.globalPluginRepository.SavePluginStageMapping(&repository.PluginStageMapping{PluginId: pluginMetadata.Id, StageType: stageTypeNameMap[definition.StageType], AuditLog: auditLog}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving plugin stage mapping", "err", err, "pluginId", pluginMetadata.Id)
+		return nil, err
+	}
+	err = impl.savePluginTags(pluginMetadata.Id, definition.Tags, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range definition.Steps {
+		err = impl.savePluginStep(pluginMetadata.Id, step, refPluginIds[step.Index], userId, tx)
+		if err != nil {
+			impl.logger.Errorw("error in saving plugin step", "err", err, "pluginId", pluginMetadata.Id, "step", step.Name)
+			return nil, err
+		}
+	}
+	latestPluginId, err := impl.getLatestPluginIdAfterSave(pluginMetadata)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.globalPluginRepository.UpdateLatestPluginVersion(pluginMetadata.PluginIdentifier, latestPluginId, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		impl.logger.Errorw("error in committing plugin", "err", err, "pluginId", pluginMetadata.Id)
+		return nil, err
+	}
+	pluginMetadata.IsLatest = latestPluginId == pluginMetadata.Id
+	metadataDto := getPluginMetadataDto(pluginMetadata)
+	metadataDto.Tags = definition.Tags
+	return metadataDto, nil
+}
+
+// getLatestPluginIdAfterSave finds the latest version among the already published versions and the one being saved
+func (impl *GlobalPluginServiceImpl) getLatestPluginIdAfterSave(pluginMetadata *repository.PluginMetadata) (int, error) {
+	plugins, err := impl.globalPluginRepository.GetPluginsByIdentifier(pluginMetadata.PluginIdentifier)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting plugin versions", "err", err, "pluginIdentifier", pluginMetadata.PluginIdentifier)
+		return 0, err
+	}
+	return getLatestPluginVersion(append(plugins, pluginMetadata)).Id, nil
+}
+
+func (impl *GlobalPluginServiceImpl) savePluginTags(pluginId int, tags []string, userId int32, tx *pg.Tx) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	existingTags, err := impl.globalPluginRepository.GetPluginTagsByNames(tags)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting plugin tags", "err", err, "tags", tags)
+		return err
+	}
+	tagIdMap := make(map[string]int)
+	for _, tag := range existingTags {
+		tagIdMap[tag.Name] = tag.Id
+	}
+	auditLog := sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId}
+	var relations []*repository.PluginTagRelation
+	for _, tagName := range tags {
+		tagId, ok := tagIdMap[tagName]
+		if !ok {
+			tag, err := impl.globalPluginRepository.SavePluginTag(&repository.PluginTag{Name: tagName, AuditLog: auditLog}, tx)
+			if err != nil {
+				return err
+			}
+			tagId = tag.Id
+			tagIdMap[tagName] = tagId
+		}
+		relations = append(relations, &repository.PluginTagRelation{TagId: tagId, PluginId: pluginId, AuditLog: auditLog})
+	}
+	return impl.globalPluginRepository.SavePluginTagRelations(relations, tx)
+}
+
+func (impl *GlobalPluginServiceImpl) savePluginStep(pluginId int, step *PluginStepDefinitionDto, refPluginId int, userId int32, tx *pg.Tx) error {
+	auditLog := sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId}
+	stepType := step.StepType
+	if len(stepType) == 0 {
+		stepType = repository.PLUGIN_STEP_TYPE_INLINE
+	}
+	pluginStep := &repository.PluginStep{
+		PluginId:            pluginId,
+		Name:                step.Name,
+		Description:         step.Description,
+		Index:               step.Index,
+		StepType:            stepType,
+		RefPluginId:         refPluginId,
+		OutputDirectoryPath: step.OutputDirectoryPath,
+		AuditLog:            auditLog,
+	}
+	if stepType == repository.PLUGIN_STEP_TYPE_INLINE {
+		script := &repository.PluginPipelineScript{
+			Script:                   step.Script.Script,
+			StoreScriptAt:            step.Script.StoreScriptAt,
+			Type:                     step.Script.Type,
+			DockerfileExists:         step.Script.DockerfileExists,
+			MountPath:                step.Script.MountPath,
+			MountCodeToContainer:     step.Script.MountCodeToContainer,
+			MountCodeToContainerPath: step.Script.MountCodeToContainerPath,
+			MountDirectoryFromHost:   step.Script.MountDirectoryFromHost,
+			ContainerImagePath:       step.Script.ContainerImagePath,
+			ImagePullSecretType:      step.Script.ImagePullSecretType,
+			ImagePullSecret:          step.Script.ImagePullSecret,
+			AuditLog:                 auditLog,
+		}
+		script, err := impl.globalPluginRepository.SavePluginPipelineScript(script, tx)
+		if err != nil {
+			return err
+		}
+		if len(step.Script.Command) > 0 || len(step.Script.Args) > 0 {
+			mapping := &repository.ScriptPathArgPortMapping{
+				TypeOfMapping: repository.SCRIPT_MAPPING_TYPE_DOCKER_ARG,
+				Command:       step.Script.Command,
+				Args:          step.Script.Args,
+				ScriptId:      script.Id,
+				AuditLog:      auditLog,
+			}
+			err = impl.globalPluginRepository.SaveScriptMappings([]*repository.ScriptPathArgPortMapping{mapping}, tx)
+			if err != nil {
+				return err
+			}
+		}
+		pluginStep.ScriptId = script.Id
+	}
+	pluginStep, err := impl.globalPluginRepository.SavePluginStep(pluginStep, tx)
+	if err != nil {
+		return err
+	}
+	var variables []*repository.PluginStepVariable
+	for _, variable := range step.InputVariables {
+		variables = append(variables, getPluginStepVariable(pluginStep.Id, variable, repository.PLUGIN_VARIABLE_TYPE_INPUT, auditLog))
+	}
+	for _, variable := range step.OutputVariables {
+		variables = append(variables, getPluginStepVariable(pluginStep.Id, variable, repository.PLUGIN_VARIABLE_TYPE_OUTPUT, auditLog))
+	}
+	err = impl.globalPluginRepository.SavePluginStepVariables(variables, tx)
+	if err != nil {
+		return err
+	}
+	variableNameIdMap := make(map[string]int)
+	for _, variable := range variables {
+		variableNameIdMap[variable.Name] = variable.Id
+	}
+	var conditions []*repository.PluginStepCondition
+	for _, condition := range step.ConditionDetails {
+		conditions = append(conditions, &repository.PluginStepCondition{
+			PluginStepId:        pluginStep.Id,
+			ConditionVariableId: variableNameIdMap[condition.ConditionOnVariable],
+			ConditionType:       condition.ConditionType,
+			ConditionalOperator: condition.ConditionalOperator,
+			ConditionalValue:    condition.ConditionalValue,
+			AuditLog:            auditLog,
+		})
+	}
+	return impl.globalPluginRepository.SavePluginStepConditions(conditions, tx)
+}
+
+func getPluginStepVariable(stepId int, variable *PluginVariableDto, variableType repository.PluginStepVariableType, auditLog sql.AuditLog) *repository.PluginStepVariable {
+	return &repository.PluginStepVariable{
+		PluginStepId:          stepId,
+		Name:                  variable.Name,
+		Format:                variable.Format,
+		Description:           variable.Description,
+		IsExposed:             variable.IsExposed,
+		AllowEmptyValue:       variable.AllowEmptyValue,
+		DefaultValue:          variable.DefaultValue,
+		Value:                 variable.Value,
+		VariableType:          variableType,
+		ValueType:             variable.ValueType,
+		PreviousStepIndex:     variable.PreviousStepIndex,
+		VariableStepIndex:     variable.VariableStepIndex,
+		ReferenceVariableName: variable.ReferenceVariableName,
+		AuditLog:              auditLog,
+	}
+}
+
+func (impl *GlobalPluginServiceImpl) UpdatePluginMetadata(pluginId int, request *PluginMetadataUpdateDto, userId int32) (*PluginMetadataDto, error) {
+	pluginMetadata, err := impl.globalPluginRepository.GetMetaDataByPluginId(pluginId)
+	if err != nil {
+		impl.logger.Errorw("error in getting plugin", "err", err, "pluginId", pluginId)
+		return nil, err
+	}
+	dbConnection := impl.globalPluginRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	pluginMetadata.Name = request.Name
+	pluginMetadata.Description = request.Description
+	pluginMetadata.Icon = request.Icon
+	pluginMetadata.IsDeprecated = request.IsDeprecated
+	pluginMetadata.UpdatedOn = time.Now()
+	pluginMetadata.UpdatedBy = userId
+	err = impl.globalPluginRepository.UpdatePluginMetadata(pluginMetadata, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.globalPluginRepository.DeletePluginTagRelationsByPluginId(pluginId, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.savePluginTags(pluginId, request.Tags, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		impl.logger.Errorw("error in committing plugin metadata update", "err", err, "pluginId", pluginId)
+		return nil, err
+	}
+	metadataDto := getPluginMetadataDto(pluginMetadata)
+	metadataDto.Tags = request.Tags
+	return metadataDto, nil
+}
+
+func (impl *GlobalPluginServiceImpl) DeletePlugin(pluginId int, userId int32) error {
+	pluginMetadata, err := impl.globalPluginRepository.GetMetaDataByPluginId(pluginId)
+	if err != nil {
+		impl.logger.Errorw("error in getting plugin", "err", err, "pluginId", pluginId)
+		return err
+	}
+	usageCount, err := impl.globalPluginRepository.GetPluginUsageCount(pluginId)
+	if err != nil {
+		return err
+	}
+	if usageCount > 0 {
+		message := fmt.Sprintf("plugin version is used by %d step(s), deprecate it instead of deleting", usageCount)
+		return &util.ApiError{HttpStatusCode: http.StatusConflict, Code: "409", InternalMessage: message, UserMessage: message}
+	}
+	plugins, err := impl.globalPluginRepository.GetPluginsByIdentifier(pluginMetadata.PluginIdentifier)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	var remainingPlugins []*repository.PluginMetadata
+	for _, plugin := range plugins {
+		if plugin.Id != pluginId {
+			remainingPlugins = append(remainingPlugins, plugin)
+		}
+	}
+	dbConnection := impl.globalPluginRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	pluginMetadata.Deleted = true
+	pluginMetadata.IsLatest = false
+	pluginMetadata.UpdatedOn = time.Now()
+	pluginMetadata.UpdatedBy = userId
+	err = impl.globalPluginRepository.UpdatePluginMetadata(pluginMetadata, tx)
+	if err != nil {
+		return err
+	}
+	if latestPlugin := getLatestPluginVersion(remainingPlugins); latestPlugin != nil {
+		err = impl.globalPluginRepository.UpdateLatestPluginVersion(pluginMetadata.PluginIdentifier, latestPlugin.Id, tx)
+		if err != nil {
+			return err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		impl.logger.Errorw("error in committing plugin delete", "err", err, "pluginId", pluginId)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginServiceImpl) GetPluginVersions(pluginId int) ([]*PluginMetadataDto, error) {
+	pluginMetadata, err := impl.globalPluginRepository.GetMetaDataByPluginId(pluginId)
+	if err != nil {
+		impl.logger.Errorw("error in getting plugin", "err", err, "pluginId", pluginId)
+		return nil, err
+	}
+	plugins, err := impl.globalPluginRepository.GetPluginsByIdentifier(pluginMetadata.PluginIdentifier)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	sortPluginsByVersion(plugins)
+	versions := make([]*PluginMetadataDto, 0, len(plugins))
+	for _, plugin := range plugins {
+		versions = append(versions, getPluginMetadataDto(plugin))
+	}
+	return versions, nil
+}
+
+func (impl *GlobalPluginServiceImpl) GetPluginDefinition(pluginId int) (*PluginDefinitionDto, error) {
+	pluginMetadata, err := impl.globalPluginRepository.GetMetaDataByPluginId(pluginId)
+	if err != nil {
+		impl.logger.Errorw("error in getting plugin", "err", err, "pluginId", pluginId)
+		return nil, err
+	}
+	definition := &PluginDefinitionDto{
+		Id:               pluginMetadata.Id,
+		PluginIdentifier: pluginMetadata.PluginIdentifier,
+		PluginVersion:    pluginMetadata.PluginVersion,
+		Name:             pluginMetadata.Name,
+		Description:      pluginMetadata.Description,
+		Type:             pluginMetadata.Type,
+		Icon:             pluginMetadata.Icon,
+		IsDeprecated:     pluginMetadata.IsDeprecated,
+	}
+	stageMapping, err := impl.globalPluginRepository.GetStageMappingByPluginId(pluginId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	definition.StageType = "ci_cd"
+	if stageMapping != nil {
+		for name, stageType := range stageTypeNameMap {
+			if len(name) > 0 && stageType == stageMapping.StageType {
+				definition.StageType = name
+			}
+		}
+	}
+	definition.Tags, err = impl.globalPluginRepository.GetTagsByPluginId(pluginId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	steps, err := impl.globalPluginRepository.GetStepsByPluginIds([]int{pluginId})
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Index < steps[j].Index })
+	for _, step := range steps {
+		stepDefinition, err := impl.getPluginStepDefinition(step)
+		if err != nil {
+			impl.logger.Errorw("error in getting plugin step definition", "err", err, "stepId", step.Id)
+			return nil, err
+		}
+		definition.Steps = append(definition.Steps, stepDefinition)
+	}
+	return definition, nil
+}
+
+func (impl *GlobalPluginServiceImpl) getPluginStepDefinition(step *repository.PluginStep) (*PluginStepDefinitionDto, error) {
+	stepDefinition := &PluginStepDefinitionDto{
+		Name:                step.Name,
+		Description:         step.Description,
+		Index:               step.Index,
+		StepType:            step.StepType,
+		OutputDirectoryPath: step.OutputDirectoryPath,
+	}
+	if step.StepType == repository.PLUGIN_STEP_TYPE_REF_PLUGIN {
+		refPlugin, err := impl.globalPluginRepository.GetMetaDataByPluginId(step.RefPluginId)
+		if err != nil {
+			return nil, err
+		}
+		stepDefinition.RefPluginIdentifier = refPlugin.PluginIdentifier
+		stepDefinition.RefPluginVersion = refPlugin.PluginVersion
+	} else if step.ScriptId > 0 {
+		script, err := impl.globalPluginRepository.GetScriptDetailById(step.ScriptId)
+		if err != nil {
+			return nil, err
+		}
+		stepDefinition.Script = &PluginScriptDto{
+			Type:                     script.Type,
+			Script:                   script.Script,
+			StoreScriptAt:            script.StoreScriptAt,
+			DockerfileExists:         script.DockerfileExists,
+			MountPath:                script.MountPath,
+			MountCodeToContainer:     script.MountCodeToContainer,
+			MountCodeToContainerPath: script.MountCodeToContainerPath,
+			MountDirectoryFromHost:   script.MountDirectoryFromHost,
+			ContainerImagePath:       script.ContainerImagePath,
+			ImagePullSecretType:      script.ImagePullSecretType,
+			ImagePullSecret:          script.ImagePullSecret,
+		}
+		mappings, err := impl.globalPluginRepository.GetScriptMappingDetailByScriptId(script.Id)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, mapping := range mappings {
+			if mapping.TypeOfMapping == repository.SCRIPT_MAPPING_TYPE_DOCKER_ARG {
+				stepDefinition.Script.Command = mapping.Command
+				stepDefinition.Script.Args = mapping.Args
+			}
+		}
+	}
+	variables, err := impl.globalPluginRepository.GetVariablesByStepId(step.Id)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	variableIdNameMap := make(map[int]string)
+	for _, variable := range variables {
+		variableIdNameMap[variable.Id] = variable.Name
+		variableDto := getVariableDto(variable)
+		variableDto.Id = 0
+		if variable.VariableType == repository.PLUGIN_VARIABLE_TYPE_INPUT {
+			stepDefinition.InputVariables = append(stepDefinition.InputVariables, variableDto)
+		} else if variable.VariableType == repository.PLUGIN_VARIABLE_TYPE_OUTPUT {
+			stepDefinition.OutputVariables = append(stepDefinition.OutputVariables, variableDto)
+		}
+	}
+	conditions, err := impl.globalPluginRepository.GetConditionsByStepId(step.Id)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	for _, condition := range conditions {
+		stepDefinition.ConditionDetails = append(stepDefinition.ConditionDetails, &PluginConditionDto{
+			ConditionOnVariable: variableIdNameMap[condition.ConditionVariableId],
+			ConditionType:       condition.ConditionType,
+			ConditionalOperator: condition.ConditionalOperator,
+			ConditionalValue:    condition.ConditionalValue,
+		})
+	}
+	return stepDefinition, nil
+}
+
+func (impl *GlobalPluginServiceImpl) ExportPlugin(pluginId int) ([]byte, error) {
+	definition, err := impl.GetPluginDefinition(pluginId)
+	if err != nil {
+		return nil, err
+	}
+	//ids are local to this installation, the exported definition is identified by pluginIdentifier and pluginVersion
+	definition.Id = 0
+	redactPluginSecrets(definition)
+	return yaml.Marshal(definition)
+}
+
+// redactPluginSecrets drops credentials from a definition leaving the installation, the secret type is kept so that
+// it can be filled in after import
+func redactPluginSecrets(definition *PluginDefinitionDto) {
+	for _, step := range definition.Steps {
+		if step.Script != nil {
+			step.Script.ImagePullSecret = ""
+		}
+	}
+}
+
+func newPluginVersionExistsError(definition *PluginDefinitionDto) *util.ApiError {
+	message := fmt.Sprintf("version %s of plugin %s already exists, published versions can not be changed", definition.PluginVersion, definition.PluginIdentifier)
+	return &util.ApiError{HttpStatusCode: http.StatusConflict, Code: "409", InternalMessage: message, UserMessage: message}
+}
+
+func isUniqueViolation(err error) bool {
+	pgErr, ok := err.(pg.Error)
+	return ok && pgErr.Field('C') == "23505"
+}
+
+func (impl *GlobalPluginServiceImpl) ParsePluginDefinition(data []byte) (*PluginDefinitionDto, error) {
+	definition := &PluginDefinitionDto{}
+	err := yaml.UnmarshalStrict(data, definition)
+	if err != nil {
+		impl.logger.Errorw("error in parsing plugin definition", "err", err)
+		return nil, newPluginBadRequestError(fmt.Sprintf("invalid plugin definition: %s", err.Error()))
+	}
+	definition.Id = 0
+	return definition, nil
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+)
+
+func testPluginDefinition() *PluginDefinitionDto {
+	return &PluginDefinitionDto{
+		PluginIdentifier: "k6-load-test",
+		PluginVersion:    "1.2.0",
+		Name:             "K6 Load Test",
+		Type:             repository.PLUGIN_TYPE_SHARED,
+		Tags:             []string{"testing"},
+		StageType:        "ci_cd",
+		Steps: []*PluginStepDefinitionDto{
+			{
+				Name:     "run",
+				Index:    1,
+				StepType: repository.PLUGIN_STEP_TYPE_INLINE,
+				Script: &PluginScriptDto{
+					Type:               repository.SCRIPT_TYPE_CONTAINER_IMAGE,
+					ContainerImagePath: "grafana/k6:0.45.0",
+					Command:            "k6",
+					Args:               []string{"run", "script.js"},
+				},
+				InputVariables:   []*PluginVariableDto{{Name: "VUS", Format: repository.PLUGIN_VARIABLE_FORMAT_TYPE_NUMBER, DefaultValue: "10"}},
+				OutputVariables:  []*PluginVariableDto{{Name: "P95", Format: repository.PLUGIN_VARIABLE_FORMAT_TYPE_NUMBER}},
+				ConditionDetails: []*PluginConditionDto{{ConditionOnVariable: "P95", ConditionType: repository.PLUGIN_CONDITION_TYPE_FAIL, ConditionalOperator: ">", ConditionalValue: "500"}},
+			},
+			{
+				Name:                "notify",
+				Index:               2,
+				StepType:            repository.PLUGIN_STEP_TYPE_REF_PLUGIN,
+				RefPluginIdentifier: "slack-notify",
+				RefPluginVersion:    "1.0.0",
+			},
+		},
+	}
+}
+
+func TestValidatePluginDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(definition *PluginDefinitionDto)
+		wantErr bool
+	}{
+		{name: "valid definition", modify: func(definition *PluginDefinitionDto) {}},
+		{name: "invalid identifier", modify: func(definition *PluginDefinitionDto) { definition.PluginIdentifier = "K6 Load" }, wantErr: true},
+		{name: "non semantic version", modify: func(definition *PluginDefinitionDto) { definition.PluginVersion = "v1" }, wantErr: true},
+		{name: "invalid stage type", modify: func(definition *PluginDefinitionDto) { definition.StageType = "pre-cd" }, wantErr: true},
+		{name: "duplicate step index", modify: func(definition *PluginDefinitionDto) { definition.Steps[1].Index = 1 }, wantErr: true},
+		{name: "step index out of range", modify: func(definition *PluginDefinitionDto) { definition.Steps[1].Index = 3 }, wantErr: true},
+		{name: "inline step without script", modify: func(definition *PluginDefinitionDto) { definition.Steps[0].Script = nil }, wantErr: true},
+		{name: "ref step without version", modify: func(definition *PluginDefinitionDto) { definition.Steps[1].RefPluginVersion = "" }, wantErr: true},
+		{name: "ref step referring to itself", modify: func(definition *PluginDefinitionDto) { definition.Steps[1].RefPluginIdentifier = "k6-load-test" }, wantErr: true},
+		{name: "duplicate variable name", modify: func(definition *PluginDefinitionDto) { definition.Steps[0].OutputVariables[0].Name = "VUS" }, wantErr: true},
+		{name: "condition on unknown variable", modify: func(definition *PluginDefinitionDto) {
+			definition.Steps[0].ConditionDetails[0].ConditionOnVariable = "P99"
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := testPluginDefinition()
+			tt.modify(definition)
+			err := validatePluginDefinition(definition)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err != nil {
+				assert.IsType(t, &util.ApiError{}, err)
+			}
+		})
+	}
+}
+
+func TestGetLatestPluginVersion(t *testing.T) {
+	plugins := []*repository.PluginMetadata{
+		{Id: 1, PluginVersion: "1.0.0"},
+		{Id: 2, PluginVersion: "1.10.0"},
+		{Id: 3, PluginVersion: "1.9.3"},
+		{Id: 4, PluginVersion: "not-a-version"},
+	}
+	assert.Equal(t, 2, getLatestPluginVersion(plugins).Id)
+	assert.Equal(t, 4, getLatestPluginVersion(plugins[3:]).Id)
+	assert.Nil(t, getLatestPluginVersion(nil))
+
+	sortPluginsByVersion(plugins)
+	var ids []int
+	for _, plugin := range plugins {
+		ids = append(ids, plugin.Id)
+	}
+	assert.Equal(t, []int{2, 3, 1, 4}, ids)
+}
+
+func TestParsePluginDefinition(t *testing.T) {
+	impl := &GlobalPluginServiceImpl{logger: zap.NewNop().Sugar()}
+	definition := testPluginDefinition()
+	data, err := yaml.Marshal(definition)
+	assert.Nil(t, err)
+
+	parsed, err := impl.ParsePluginDefinition(data)
+	assert.Nil(t, err)
+	assert.Equal(t, definition, parsed)
+
+	_, err = impl.ParsePluginDefinition([]byte("pluginIdentifier: k6\nunknownField: true\n"))
+	assert.NotNil(t, err)
+}
+
+func TestRedactPluginSecrets(t *testing.T) {
+	definition := testPluginDefinition()
+	definition.Steps[0].Script.ImagePullSecretType = repository.IMAGE_PULL_TYPE_SECRET_PATH
+	definition.Steps[0].Script.ImagePullSecret = "registry-credentials"
+	redactPluginSecrets(definition)
+	assert.Empty(t, definition.Steps[0].Script.ImagePullSecret)
+	assert.Equal(t, repository.IMAGE_PULL_TYPE_SECRET_PATH, definition.Steps[0].Script.ImagePullSecretType)
+	assert.Nil(t, definition.Steps[1].Script)
+}
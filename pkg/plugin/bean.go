@@ -15,12 +15,78 @@ type PluginListComponentDto struct { //created new struct for backward compatibi
 }
 
 type PluginMetadataDto struct {
-	Id          int      `json:"id"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Type        string   `json:"type"` // SHARED, PRESET etc
-	Icon        string   `json:"icon"`
-	Tags        []string `json:"tags"`
+	Id               int      `json:"id"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Type             string   `json:"type"` // SHARED, PRESET etc
+	Icon             string   `json:"icon"`
+	Tags             []string `json:"tags"`
+	PluginIdentifier string   `json:"pluginIdentifier"`
+	PluginVersion    string   `json:"pluginVersion"`
+	IsLatest         bool     `json:"isLatest"`
+	IsDeprecated     bool     `json:"isDeprecated"`
+}
+
+// PluginDefinitionDto is the complete, portable definition of a plugin version. It is used for creating plugins
+// and for yaml import/export, so it refers to other plugins by identifier and version instead of ids
+type PluginDefinitionDto struct {
+	Id               int                        `json:"id,omitempty"`
+	PluginIdentifier string                     `json:"pluginIdentifier" validate:"required"`
+	PluginVersion    string                     `json:"pluginVersion" validate:"required"`
+	Name             string                     `json:"name" validate:"required"`
+	Description      string                     `json:"description"`
+	Type             repository.PluginType      `json:"type" validate:"omitempty,oneof=SHARED PRESET"`
+	Icon             string                     `json:"icon,omitempty"`
+	Tags             []string                   `json:"tags,omitempty"`
+	StageType        string                     `json:"stageType" validate:"omitempty,oneof=ci cd ci_cd"`
+	IsDeprecated     bool                       `json:"isDeprecated"`
+	Steps            []*PluginStepDefinitionDto `json:"steps" validate:"required,min=1,dive"`
+}
+
+type PluginStepDefinitionDto struct {
+	Name                string                    `json:"name" validate:"required"`
+	Description         string                    `json:"description,omitempty"`
+	Index               int                       `json:"index" validate:"min=1"`
+	StepType            repository.PluginStepType `json:"stepType" validate:"omitempty,oneof=INLINE REF_PLUGIN"`
+	RefPluginIdentifier string                    `json:"refPluginIdentifier,omitempty"`
+	RefPluginVersion    string                    `json:"refPluginVersion,omitempty"`
+	Script              *PluginScriptDto          `json:"script,omitempty"`
+	OutputDirectoryPath []string                  `json:"outputDirectoryPath,omitempty"`
+	InputVariables      []*PluginVariableDto      `json:"inputVariables,omitempty"`
+	OutputVariables     []*PluginVariableDto      `json:"outputVariables,omitempty"`
+	ConditionDetails    []*PluginConditionDto     `json:"conditionDetails,omitempty"`
+}
+
+type PluginScriptDto struct {
+	Type                     repository.ScriptType                `json:"type" validate:"oneof=SHELL DOCKERFILE CONTAINER_IMAGE"`
+	Script                   string                               `json:"script,omitempty"`
+	StoreScriptAt            string                               `json:"storeScriptAt,omitempty"`
+	DockerfileExists         bool                                 `json:"dockerfileExists,omitempty"`
+	MountPath                string                               `json:"mountPath,omitempty"`
+	MountCodeToContainer     bool                                 `json:"mountCodeToContainer,omitempty"`
+	MountCodeToContainerPath string                               `json:"mountCodeToContainerPath,omitempty"`
+	MountDirectoryFromHost   bool                                 `json:"mountDirectoryFromHost,omitempty"`
+	ContainerImagePath       string                               `json:"containerImagePath,omitempty"`
+	ImagePullSecretType      repository.ScriptImagePullSecretType `json:"imagePullSecretType,omitempty"`
+	ImagePullSecret          string                               `json:"imagePullSecret,omitempty"`
+	Command                  string                               `json:"command,omitempty"`
+	Args                     []string                             `json:"args,omitempty"`
+}
+
+type PluginConditionDto struct {
+	ConditionOnVariable string                             `json:"conditionOnVariable"` //name of input or output variable of the step
+	ConditionType       repository.PluginStepConditionType `json:"conditionType"`
+	ConditionalOperator string                             `json:"conditionOperator"`
+	ConditionalValue    string                             `json:"conditionalValue"`
+}
+
+// PluginMetadataUpdateDto holds the fields that can change on a published version, steps are immutable and need a new version
+type PluginMetadataUpdateDto struct {
+	Name         string   `json:"name" validate:"required"`
+	Description  string   `json:"description"`
+	Icon         string   `json:"icon"`
+	Tags         []string `json:"tags"`
+	IsDeprecated bool     `json:"isDeprecated"`
 }
 
 type PluginVariableDto struct {
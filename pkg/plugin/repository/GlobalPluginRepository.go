@@ -52,13 +52,17 @@ const (
 )
 
 type PluginMetadata struct {
-	tableName   struct{}   `sql:"plugin_metadata" pg:",discard_unknown_columns"`
-	Id          int        `sql:"id,pk"`
-	Name        string     `sql:"name"`
-	Description string     `sql:"description"`
-	Type        PluginType `sql:"type"`
-	Icon        string     `sql:"icon"`
-	Deleted     bool       `sql:"deleted, notnull"`
+	tableName        struct{}   `sql:"plugin_metadata" pg:",discard_unknown_columns"`
+	Id               int        `sql:"id,pk"`
+	Name             string     `sql:"name"`
+	Description      string     `sql:"description"`
+	Type             PluginType `sql:"type"`
+	Icon             string     `sql:"icon"`
+	PluginIdentifier string     `sql:"plugin_identifier"` //shared by all versions of a plugin
+	PluginVersion    string     `sql:"plugin_version"`    //semver
+	IsLatest         bool       `sql:"is_latest,notnull"`
+	IsDeprecated     bool       `sql:"is_deprecated,notnull"`
+	Deleted          bool       `sql:"deleted, notnull"`
 	sql.AuditLog
 }
 
@@ -109,6 +113,7 @@ type ScriptPathArgPortMapping struct {
 	PortOnLocal         int               `sql:"port_on_local"`
 	PortOnContainer     int               `sql:"port_on_container"`
 	ScriptId            int               `sql:"script_id"`
+	Deleted             bool              `sql:"deleted,notnull"`
 	sql.AuditLog
 }
 
@@ -184,6 +189,25 @@ type GlobalPluginRepository interface {
 	GetExposedVariablesByPluginId(pluginId int) ([]*PluginStepVariable, error)
 	GetExposedVariablesForAllPlugins() ([]*PluginStepVariable, error)
 	GetConditionsByStepId(stepId int) ([]*PluginStepCondition, error)
+
+	GetConnection() *pg.DB
+	GetPluginsByIdentifier(pluginIdentifier string) ([]*PluginMetadata, error)
+	GetPluginByIdentifierAndVersion(pluginIdentifier string, pluginVersion string) (*PluginMetadata, error)
+	GetStageMappingByPluginId(pluginId int) (*PluginStageMapping, error)
+	GetPluginTagsByNames(names []string) ([]*PluginTag, error)
+	GetPluginUsageCount(pluginId int) (int, error)
+	SavePluginMetadata(pluginMetadata *PluginMetadata, tx *pg.Tx) (*PluginMetadata, error)
+	UpdatePluginMetadata(pluginMetadata *PluginMetadata, tx *pg.Tx) error
+	UpdateLatestPluginVersion(pluginIdentifier string, latestPluginId int, tx *pg.Tx) error
+	SavePluginStageMapping(mapping *PluginStageMapping, tx *pg.Tx) error
+	SavePluginTag(tag *PluginTag, tx *pg.Tx) (*PluginTag, error)
+	SavePluginTagRelations(relations []*PluginTagRelation, tx *pg.Tx) error
+	DeletePluginTagRelationsByPluginId(pluginId int, tx *pg.Tx) error
+	SavePluginPipelineScript(script *PluginPipelineScript, tx *pg.Tx) (*PluginPipelineScript, error)
+	SaveScriptMappings(mappings []*ScriptPathArgPortMapping, tx *pg.Tx) error
+	SavePluginStep(step *PluginStep, tx *pg.Tx) (*PluginStep, error)
+	SavePluginStepVariables(variables []*PluginStepVariable, tx *pg.Tx) error
+	SavePluginStepConditions(conditions []*PluginStepCondition, tx *pg.Tx) error
 }
 
 func NewGlobalPluginRepository(logger *zap.SugaredLogger, dbConnection *pg.DB) *GlobalPluginRepositoryImpl {
@@ -203,6 +227,8 @@ func (impl *GlobalPluginRepositoryImpl) GetMetaDataForAllPlugins(stageType int)
 	err := impl.dbConnection.Model(&plugins).
 		Join("INNER JOIN plugin_stage_mapping psm on psm.plugin_id=plugin_metadata.id").
 		Where("plugin_metadata.deleted = ?", false).
+		Where("plugin_metadata.is_latest = ?", true).
+		Where("plugin_metadata.is_deprecated = ?", false).
 		Where("psm.stage_type= 2 or psm.stage_type= ?", stageType).
 		Select()
 	if err != nil {
@@ -368,3 +394,194 @@ func (impl *GlobalPluginRepositoryImpl) GetConditionsByStepId(stepId int) ([]*Pl
 	}
 	return conditions, nil
 }
+
+func (impl *GlobalPluginRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *GlobalPluginRepositoryImpl) GetPluginsByIdentifier(pluginIdentifier string) ([]*PluginMetadata, error) {
+	var plugins []*PluginMetadata
+	err := impl.dbConnection.Model(&plugins).
+		Where("plugin_identifier = ?", pluginIdentifier).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting plugins by identifier", "err", err, "pluginIdentifier", pluginIdentifier)
+		return nil, err
+	}
+	return plugins, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) GetPluginByIdentifierAndVersion(pluginIdentifier string, pluginVersion string) (*PluginMetadata, error) {
+	var plugin PluginMetadata
+	err := impl.dbConnection.Model(&plugin).
+		Where("plugin_identifier = ?", pluginIdentifier).
+		Where("plugin_version = ?", pluginVersion).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting plugin by identifier and version", "err", err, "pluginIdentifier", pluginIdentifier, "pluginVersion", pluginVersion)
+		return nil, err
+	}
+	return &plugin, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) GetStageMappingByPluginId(pluginId int) (*PluginStageMapping, error) {
+	var mapping PluginStageMapping
+	err := impl.dbConnection.Model(&mapping).
+		Where("plugin_id = ?", pluginId).Limit(1).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting stage mapping by pluginId", "err", err, "pluginId", pluginId)
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) GetPluginTagsByNames(names []string) ([]*PluginTag, error) {
+	var tags []*PluginTag
+	if len(names) == 0 {
+		return tags, nil
+	}
+	err := impl.dbConnection.Model(&tags).
+		Where("name in (?)", pg.In(names)).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting tags by names", "err", err, "names", names)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetPluginUsageCount counts pipeline steps and other plugins' steps which reference the plugin
+func (impl *GlobalPluginRepositoryImpl) GetPluginUsageCount(pluginId int) (int, error) {
+	var count int
+	query := `SELECT (SELECT count(*) FROM pipeline_stage_step WHERE ref_plugin_id = ? AND deleted = false) +
+					(SELECT count(*) FROM plugin_step WHERE ref_plugin_id = ? AND deleted = false);`
+	_, err := impl.dbConnection.Query(pg.Scan(&count), query, pluginId, pluginId)
+	if err != nil {
+		impl.logger.Errorw("err in getting plugin usage count", "err", err, "pluginId", pluginId)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginMetadata(pluginMetadata *PluginMetadata, tx *pg.Tx) (*PluginMetadata, error) {
+	err := tx.Insert(pluginMetadata)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin metadata", "err", err, "pluginMetadata", pluginMetadata)
+		return nil, err
+	}
+	return pluginMetadata, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) UpdatePluginMetadata(pluginMetadata *PluginMetadata, tx *pg.Tx) error {
+	err := tx.Update(pluginMetadata)
+	if err != nil {
+		impl.logger.Errorw("err in updating plugin metadata", "err", err, "pluginMetadata", pluginMetadata)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) UpdateLatestPluginVersion(pluginIdentifier string, latestPluginId int, tx *pg.Tx) error {
+	_, err := tx.Model((*PluginMetadata)(nil)).
+		Set("is_latest = (id = ?)", latestPluginId).
+		Where("plugin_identifier = ?", pluginIdentifier).
+		Where("deleted = ?", false).Update()
+	if err != nil {
+		impl.logger.Errorw("err in updating latest plugin version", "err", err, "pluginIdentifier", pluginIdentifier, "latestPluginId", latestPluginId)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginStageMapping(mapping *PluginStageMapping, tx *pg.Tx) error {
+	err := tx.Insert(mapping)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin stage mapping", "err", err, "mapping", mapping)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginTag(tag *PluginTag, tx *pg.Tx) (*PluginTag, error) {
+	err := tx.Insert(tag)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin tag", "err", err, "tag", tag)
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginTagRelations(relations []*PluginTagRelation, tx *pg.Tx) error {
+	if len(relations) == 0 {
+		return nil
+	}
+	err := tx.Insert(&relations)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin tag relations", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) DeletePluginTagRelationsByPluginId(pluginId int, tx *pg.Tx) error {
+	_, err := tx.Model((*PluginTagRelation)(nil)).Where("plugin_id = ?", pluginId).Delete()
+	if err != nil {
+		impl.logger.Errorw("err in deleting plugin tag relations", "err", err, "pluginId", pluginId)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginPipelineScript(script *PluginPipelineScript, tx *pg.Tx) (*PluginPipelineScript, error) {
+	err := tx.Insert(script)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin pipeline script", "err", err)
+		return nil, err
+	}
+	return script, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SaveScriptMappings(mappings []*ScriptPathArgPortMapping, tx *pg.Tx) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	err := tx.Insert(&mappings)
+	if err != nil {
+		impl.logger.Errorw("err in saving script mappings", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginStep(step *PluginStep, tx *pg.Tx) (*PluginStep, error) {
+	err := tx.Insert(step)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin step", "err", err, "step", step)
+		return nil, err
+	}
+	return step, nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginStepVariables(variables []*PluginStepVariable, tx *pg.Tx) error {
+	if len(variables) == 0 {
+		return nil
+	}
+	err := tx.Insert(&variables)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin step variables", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *GlobalPluginRepositoryImpl) SavePluginStepConditions(conditions []*PluginStepCondition, tx *pg.Tx) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+	err := tx.Insert(&conditions)
+	if err != nil {
+		impl.logger.Errorw("err in saving plugin step conditions", "err", err)
+		return err
+	}
+	return nil
+}
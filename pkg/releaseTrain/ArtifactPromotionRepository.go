@@ -0,0 +1,75 @@
+package releaseTrain
+
+import (
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type PromotionStatus string
+
+const (
+	PROMOTION_STATUS_PROMOTED PromotionStatus = "PROMOTED"
+	// PROMOTION_STATUS_BLOCKED the artifact did not satisfy the policy of the destination stage
+	PROMOTION_STATUS_BLOCKED PromotionStatus = "BLOCKED"
+	// PROMOTION_STATUS_FAILED policies were satisfied but the deployment on the destination environment could not be triggered
+	PROMOTION_STATUS_FAILED PromotionStatus = "FAILED"
+)
+
+// ArtifactPromotion is an audit of a promotion attempt of an artifact, PolicyResult is the json encoded list of
+// policy evaluations at the time of the attempt
+type ArtifactPromotion struct {
+	tableName                struct{}        `sql:"artifact_promotion" pg:",discard_unknown_columns"`
+	Id                       int             `sql:"id,pk"`
+	ReleaseTrainId           int             `sql:"release_train_id,notnull"`
+	AppId                    int             `sql:"app_id,notnull"`
+	CiArtifactId             int             `sql:"ci_artifact_id,notnull"`
+	SourceEnvironmentId      int             `sql:"source_environment_id,notnull"`
+	DestinationEnvironmentId int             `sql:"destination_environment_id,notnull"`
+	DestinationPipelineId    int             `sql:"destination_pipeline_id"`
+	AppGroupId               int             `sql:"app_group_id"`
+	Status                   PromotionStatus `sql:"status,notnull"`
+	PolicyResult             string          `sql:"policy_result"`
+	Message                  string          `sql:"message"`
+	ReleaseId                int             `sql:"release_id"`
+	PromotedBy               int32           `sql:"promoted_by,notnull"`
+	PromotedOn               time.Time       `sql:"promoted_on,notnull"`
+}
+
+type ArtifactPromotionRepository interface {
+	Save(promotion *ArtifactPromotion) error
+	FindByAppIdAndCiArtifactId(appId int, ciArtifactId int) ([]*ArtifactPromotion, error)
+	FindByAppId(appId int, offset int, limit int) ([]*ArtifactPromotion, error)
+}
+
+type ArtifactPromotionRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewArtifactPromotionRepositoryImpl(dbConnection *pg.DB) *ArtifactPromotionRepositoryImpl {
+	return &ArtifactPromotionRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl ArtifactPromotionRepositoryImpl) Save(promotion *ArtifactPromotion) error {
+	return impl.dbConnection.Insert(promotion)
+}
+
+func (impl ArtifactPromotionRepositoryImpl) FindByAppIdAndCiArtifactId(appId int, ciArtifactId int) ([]*ArtifactPromotion, error) {
+	var promotions []*ArtifactPromotion
+	err := impl.dbConnection.Model(&promotions).
+		Where("app_id = ?", appId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Order("id DESC").
+		Select()
+	return promotions, err
+}
+
+func (impl ArtifactPromotionRepositoryImpl) FindByAppId(appId int, offset int, limit int) ([]*ArtifactPromotion, error) {
+	var promotions []*ArtifactPromotion
+	err := impl.dbConnection.Model(&promotions).
+		Where("app_id = ?", appId).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Select()
+	return promotions, err
+}
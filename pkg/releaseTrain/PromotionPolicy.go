@@ -0,0 +1,153 @@
+package releaseTrain
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"strings"
+	"time"
+)
+
+// PromotionPolicy is the declarative gate of a release train stage, an artifact is promoted to the stage only when
+// every configured check passes on the environment of the previous stage
+type PromotionPolicy struct {
+	// RequireTestsPassed needs the post deployment stage of the source pipeline, when configured, to have succeeded for the artifact
+	RequireTestsPassed bool `json:"requireTestsPassed"`
+	// SoakTimeMinutes is the minimum time the artifact has to be deployed on the source environment
+	SoakTimeMinutes              int  `json:"soakTimeMinutes" validate:"min=0"`
+	BlockCriticalVulnerabilities bool `json:"blockCriticalVulnerabilities"`
+	// RequiredImageTags are image release tags which the artifact must carry
+	RequiredImageTags []string `json:"requiredImageTags"`
+}
+
+type PolicyCheck string
+
+const (
+	// POLICY_CHECK_DEPLOYED_ON_SOURCE is implicit for every promotion, the artifact must be successfully deployed on the previous stage
+	POLICY_CHECK_DEPLOYED_ON_SOURCE        PolicyCheck = "DEPLOYED_ON_SOURCE"
+	POLICY_CHECK_TESTS_PASSED              PolicyCheck = "TESTS_PASSED"
+	POLICY_CHECK_SOAK_TIME                 PolicyCheck = "SOAK_TIME"
+	POLICY_CHECK_NO_CRITICAL_VULNERABILITY PolicyCheck = "NO_CRITICAL_VULNERABILITY"
+	POLICY_CHECK_IMAGE_TAGS                PolicyCheck = "IMAGE_TAGS"
+)
+
+type PolicyEvaluation struct {
+	Check   PolicyCheck `json:"check"`
+	Passed  bool        `json:"passed"`
+	Message string      `json:"message"`
+}
+
+// promotionFacts is the state of an artifact on the source environment which a promotion policy is evaluated against
+type promotionFacts struct {
+	deployedOnSource bool
+	sourceDeployedOn time.Time
+	// postStageConfigured and postStageStatus describe the post deployment stage of the source pipeline, the status
+	// is empty when the stage never ran for the artifact
+	postStageConfigured     bool
+	postStageStatus         string
+	scanned                 bool
+	criticalVulnerabilities []string
+	imageTags               []string
+}
+
+func evaluatePromotionPolicy(policy *PromotionPolicy, facts *promotionFacts, now time.Time) []*PolicyEvaluation {
+	evaluations := []*PolicyEvaluation{evaluateDeployedOnSource(facts)}
+	if policy == nil {
+		return evaluations
+	}
+	if policy.RequireTestsPassed {
+		evaluations = append(evaluations, evaluateTestsPassed(facts))
+	}
+	if policy.SoakTimeMinutes > 0 {
+		evaluations = append(evaluations, evaluateSoakTime(facts, time.Duration(policy.SoakTimeMinutes)*time.Minute, now))
+	}
+	if policy.BlockCriticalVulnerabilities {
+		evaluations = append(evaluations, evaluateVulnerabilities(facts))
+	}
+	if len(policy.RequiredImageTags) > 0 {
+		evaluations = append(evaluations, evaluateImageTags(facts, policy.RequiredImageTags))
+	}
+	return evaluations
+}
+
+func isEligible(evaluations []*PolicyEvaluation) bool {
+	for _, evaluation := range evaluations {
+		if !evaluation.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func evaluateDeployedOnSource(facts *promotionFacts) *PolicyEvaluation {
+	if !facts.deployedOnSource {
+		return &PolicyEvaluation{Check: POLICY_CHECK_DEPLOYED_ON_SOURCE, Message: "artifact is not successfully deployed on the source environment"}
+	}
+	return &PolicyEvaluation{Check: POLICY_CHECK_DEPLOYED_ON_SOURCE, Passed: true, Message: "artifact is deployed on the source environment"}
+}
+
+func evaluateTestsPassed(facts *promotionFacts) *PolicyEvaluation {
+	evaluation := &PolicyEvaluation{Check: POLICY_CHECK_TESTS_PASSED}
+	switch {
+	case !facts.deployedOnSource:
+		evaluation.Message = "tests can not pass before the artifact is deployed on the source environment"
+	case !facts.postStageConfigured:
+		evaluation.Passed = true
+		evaluation.Message = "no post deployment stage configured on the source pipeline"
+	case facts.postStageStatus == "":
+		evaluation.Message = "post deployment stage has not run for the artifact"
+	case facts.postStageStatus != pipelineConfig.WorkflowSucceeded:
+		evaluation.Message = fmt.Sprintf("post deployment stage is %s", facts.postStageStatus)
+	default:
+		evaluation.Passed = true
+		evaluation.Message = "post deployment stage succeeded"
+	}
+	return evaluation
+}
+
+func evaluateSoakTime(facts *promotionFacts, soakTime time.Duration, now time.Time) *PolicyEvaluation {
+	evaluation := &PolicyEvaluation{Check: POLICY_CHECK_SOAK_TIME}
+	if !facts.deployedOnSource {
+		evaluation.Message = "soak time starts once the artifact is deployed on the source environment"
+		return evaluation
+	}
+	soakedFor := now.Sub(facts.sourceDeployedOn)
+	if soakedFor < soakTime {
+		evaluation.Message = fmt.Sprintf("artifact has to soak for %s more", (soakTime - soakedFor).Round(time.Minute))
+		return evaluation
+	}
+	evaluation.Passed = true
+	evaluation.Message = fmt.Sprintf("artifact soaked for %s", soakedFor.Round(time.Minute))
+	return evaluation
+}
+
+func evaluateVulnerabilities(facts *promotionFacts) *PolicyEvaluation {
+	evaluation := &PolicyEvaluation{Check: POLICY_CHECK_NO_CRITICAL_VULNERABILITY}
+	if !facts.scanned {
+		evaluation.Message = "artifact has not been scanned for vulnerabilities"
+		return evaluation
+	}
+	if len(facts.criticalVulnerabilities) > 0 {
+		evaluation.Message = fmt.Sprintf("artifact has %d critical vulnerabilities: %s", len(facts.criticalVulnerabilities), strings.Join(facts.criticalVulnerabilities, ", "))
+		return evaluation
+	}
+	evaluation.Passed = true
+	evaluation.Message = "no critical vulnerabilities found"
+	return evaluation
+}
+
+func evaluateImageTags(facts *promotionFacts, requiredTags []string) *PolicyEvaluation {
+	tags := make(map[string]bool, len(facts.imageTags))
+	for _, tag := range facts.imageTags {
+		tags[tag] = true
+	}
+	var missingTags []string
+	for _, tag := range requiredTags {
+		if !tags[tag] {
+			missingTags = append(missingTags, tag)
+		}
+	}
+	if len(missingTags) > 0 {
+		return &PolicyEvaluation{Check: POLICY_CHECK_IMAGE_TAGS, Message: fmt.Sprintf("artifact is missing image tags: %s", strings.Join(missingTags, ", "))}
+	}
+	return &PolicyEvaluation{Check: POLICY_CHECK_IMAGE_TAGS, Passed: true, Message: "artifact has the required image tags"}
+}
@@ -0,0 +1,89 @@
+package releaseTrain
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEvaluatePromotionPolicy(t *testing.T) {
+	now := time.Date(2023, 6, 5, 12, 0, 0, 0, time.UTC)
+	policy := &PromotionPolicy{
+		RequireTestsPassed:           true,
+		SoakTimeMinutes:              60,
+		BlockCriticalVulnerabilities: true,
+		RequiredImageTags:            []string{"qa-approved"},
+	}
+	tests := []struct {
+		name         string
+		policy       *PromotionPolicy
+		facts        *promotionFacts
+		wantEligible bool
+		wantFailed   []PolicyCheck
+	}{
+		{
+			name:         "no policy only needs the artifact on the source environment",
+			facts:        &promotionFacts{deployedOnSource: true, sourceDeployedOn: now},
+			wantEligible: true,
+		},
+		{
+			name:         "not deployed on source",
+			facts:        &promotionFacts{},
+			wantEligible: false,
+			wantFailed:   []PolicyCheck{POLICY_CHECK_DEPLOYED_ON_SOURCE},
+		},
+		{
+			name:   "all checks pass",
+			policy: policy,
+			facts: &promotionFacts{deployedOnSource: true, sourceDeployedOn: now.Add(-2 * time.Hour), postStageConfigured: true,
+				postStageStatus: pipelineConfig.WorkflowSucceeded, scanned: true, imageTags: []string{"qa-approved", "v1"}},
+			wantEligible: true,
+		},
+		{
+			name:         "tests pass without a post stage",
+			policy:       &PromotionPolicy{RequireTestsPassed: true},
+			facts:        &promotionFacts{deployedOnSource: true, sourceDeployedOn: now},
+			wantEligible: true,
+		},
+		{
+			name:   "failing checks are all reported",
+			policy: policy,
+			facts: &promotionFacts{deployedOnSource: true, sourceDeployedOn: now.Add(-30 * time.Minute), postStageConfigured: true,
+				postStageStatus: pipelineConfig.WorkflowFailed, scanned: true, criticalVulnerabilities: []string{"CVE-2023-1234"}},
+			wantEligible: false,
+			wantFailed:   []PolicyCheck{POLICY_CHECK_TESTS_PASSED, POLICY_CHECK_SOAK_TIME, POLICY_CHECK_NO_CRITICAL_VULNERABILITY, POLICY_CHECK_IMAGE_TAGS},
+		},
+		{
+			name:         "unscanned artifact is blocked",
+			policy:       &PromotionPolicy{BlockCriticalVulnerabilities: true},
+			facts:        &promotionFacts{deployedOnSource: true, sourceDeployedOn: now},
+			wantEligible: false,
+			wantFailed:   []PolicyCheck{POLICY_CHECK_NO_CRITICAL_VULNERABILITY},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluations := evaluatePromotionPolicy(tt.policy, tt.facts, now)
+			assert.Equal(t, tt.wantEligible, isEligible(evaluations))
+			var failed []PolicyCheck
+			for _, evaluation := range evaluations {
+				if !evaluation.Passed {
+					failed = append(failed, evaluation.Check)
+				}
+			}
+			assert.Equal(t, tt.wantFailed, failed)
+		})
+	}
+}
+
+func TestValidateReleaseTrain(t *testing.T) {
+	stages := func(stages ...*ReleaseTrainStageDto) *ReleaseTrainDto {
+		return &ReleaseTrainDto{Name: "train", Stages: stages}
+	}
+	assert.Nil(t, validateReleaseTrain(stages(&ReleaseTrainStageDto{EnvironmentId: 1}, &ReleaseTrainStageDto{EnvironmentId: 2, Policy: &PromotionPolicy{SoakTimeMinutes: 10}})))
+	assert.NotNil(t, validateReleaseTrain(stages(&ReleaseTrainStageDto{EnvironmentId: 1})))
+	assert.NotNil(t, validateReleaseTrain(stages(&ReleaseTrainStageDto{EnvironmentId: 1}, &ReleaseTrainStageDto{EnvironmentId: 1})))
+	assert.NotNil(t, validateReleaseTrain(stages(&ReleaseTrainStageDto{EnvironmentId: 1, Policy: &PromotionPolicy{}}, &ReleaseTrainStageDto{EnvironmentId: 2})))
+	assert.NotNil(t, validateReleaseTrain(stages(&ReleaseTrainStageDto{EnvironmentId: 1}, &ReleaseTrainStageDto{EnvironmentId: 2, Policy: &PromotionPolicy{RequiredImageTags: []string{""}}})))
+}
@@ -0,0 +1,124 @@
+package releaseTrain
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+// ReleaseTrain is an ordered chain of environments, artifacts enter the train through regular cd on the first
+// environment and are promoted to the next environments under the policies of their stages
+type ReleaseTrain struct {
+	tableName   struct{} `sql:"release_train" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// ReleaseTrainStage is an environment of a release train, Policy is the json encoded PromotionPolicy an artifact
+// has to satisfy to be promoted to this environment from the environment of the previous stage
+type ReleaseTrainStage struct {
+	tableName      struct{} `sql:"release_train_stage" pg:",discard_unknown_columns"`
+	Id             int      `sql:"id,pk"`
+	ReleaseTrainId int      `sql:"release_train_id,notnull"`
+	EnvironmentId  int      `sql:"environment_id,notnull"`
+	StageOrder     int      `sql:"stage_order,notnull"`
+	Policy         string   `sql:"policy"`
+	Deleted        bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+type ReleaseTrainRepository interface {
+	GetConnection() *pg.DB
+	Save(releaseTrain *ReleaseTrain, tx *pg.Tx) error
+	Update(releaseTrain *ReleaseTrain, tx *pg.Tx) error
+	FindActiveById(id int) (*ReleaseTrain, error)
+	FindActiveByName(name string) (*ReleaseTrain, error)
+	FindAllActive() ([]*ReleaseTrain, error)
+
+	SaveStages(stages []*ReleaseTrainStage, tx *pg.Tx) error
+	MarkStagesDeletedByReleaseTrainId(releaseTrainId int, userId int32, tx *pg.Tx) error
+	// FindStagesByReleaseTrainIds returns the stages of the trains ordered by stage order
+	FindStagesByReleaseTrainIds(releaseTrainIds []int) ([]*ReleaseTrainStage, error)
+}
+
+type ReleaseTrainRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewReleaseTrainRepositoryImpl(dbConnection *pg.DB) *ReleaseTrainRepositoryImpl {
+	return &ReleaseTrainRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl ReleaseTrainRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl ReleaseTrainRepositoryImpl) Save(releaseTrain *ReleaseTrain, tx *pg.Tx) error {
+	return tx.Insert(releaseTrain)
+}
+
+func (impl ReleaseTrainRepositoryImpl) Update(releaseTrain *ReleaseTrain, tx *pg.Tx) error {
+	return tx.Update(releaseTrain)
+}
+
+func (impl ReleaseTrainRepositoryImpl) FindActiveById(id int) (*ReleaseTrain, error) {
+	releaseTrain := &ReleaseTrain{}
+	err := impl.dbConnection.Model(releaseTrain).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return releaseTrain, err
+}
+
+func (impl ReleaseTrainRepositoryImpl) FindActiveByName(name string) (*ReleaseTrain, error) {
+	releaseTrain := &ReleaseTrain{}
+	err := impl.dbConnection.Model(releaseTrain).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return releaseTrain, err
+}
+
+func (impl ReleaseTrainRepositoryImpl) FindAllActive() ([]*ReleaseTrain, error) {
+	var releaseTrains []*ReleaseTrain
+	err := impl.dbConnection.Model(&releaseTrains).
+		Where("active = ?", true).
+		Order("name ASC").
+		Select()
+	return releaseTrains, err
+}
+
+func (impl ReleaseTrainRepositoryImpl) SaveStages(stages []*ReleaseTrainStage, tx *pg.Tx) error {
+	if len(stages) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&stages).Insert()
+	return err
+}
+
+func (impl ReleaseTrainRepositoryImpl) MarkStagesDeletedByReleaseTrainId(releaseTrainId int, userId int32, tx *pg.Tx) error {
+	_, err := tx.Model((*ReleaseTrainStage)(nil)).
+		Set("deleted = ?", true).
+		Set("updated_by = ?", userId).
+		Set("updated_on = now()").
+		Where("release_train_id = ?", releaseTrainId).
+		Where("deleted = ?", false).
+		Update()
+	return err
+}
+
+func (impl ReleaseTrainRepositoryImpl) FindStagesByReleaseTrainIds(releaseTrainIds []int) ([]*ReleaseTrainStage, error) {
+	var stages []*ReleaseTrainStage
+	if len(releaseTrainIds) == 0 {
+		return stages, nil
+	}
+	err := impl.dbConnection.Model(&stages).
+		Where("release_train_id in (?)", pg.In(releaseTrainIds)).
+		Where("deleted = ?", false).
+		Order("release_train_id ASC").
+		Order("stage_order ASC").
+		Select()
+	return stages, err
+}
@@ -0,0 +1,859 @@
+package releaseTrain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appGroup"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	// number of latest deployments on the source environment looked at for promotion candidates
+	ELIGIBILITY_DEPLOYMENT_LOOKBACK = 20
+	PROMOTION_HISTORY_DEFAULT_SIZE  = 20
+)
+
+var deploySucceededStatuses = []string{pipelineConfig.WorkflowSucceeded, string(health.HealthStatusHealthy)}
+
+type ReleaseTrainService interface {
+	CreateReleaseTrain(request *ReleaseTrainDto) (*ReleaseTrainDto, error)
+	UpdateReleaseTrain(request *ReleaseTrainDto) (*ReleaseTrainDto, error)
+	DeleteReleaseTrain(id int, userId int32) error
+	GetReleaseTrain(id int) (*ReleaseTrainDto, error)
+	GetAllReleaseTrains() ([]*ReleaseTrainDto, error)
+
+	// GetEligibility evaluates, for every environment of the train after the first, the artifacts deployed on the
+	// previous environment, only the given artifact is evaluated when ciArtifactId is set
+	GetEligibility(releaseTrainId int, appId int, ciArtifactId int) ([]*EnvironmentEligibility, error)
+	Promote(ctx context.Context, request *PromotionRequest) (*PromotionResponse, error)
+	BulkPromote(ctx context.Context, request *BulkPromotionRequest) ([]*PromotionResponse, error)
+	GetPromotionHistory(appId int, ciArtifactId int, offset int, size int) ([]*PromotionHistoryDto, error)
+}
+
+type ReleaseTrainServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	releaseTrainRepository      ReleaseTrainRepository
+	artifactPromotionRepository ArtifactPromotionRepository
+	environmentRepository       repository2.EnvironmentRepository
+	pipelineRepository          pipelineConfig.PipelineRepository
+	cdWorkflowRepository        pipelineConfig.CdWorkflowRepository
+	pipelineStageRepository     repository3.PipelineStageRepository
+	ciArtifactRepository        repository.CiArtifactRepository
+	scanResultRepository        security.ImageScanResultRepository
+	imageTaggingService         pipeline.ImageTaggingService
+	appGroupService             appGroup.AppGroupService
+	workflowDagExecutor         pipeline.WorkflowDagExecutor
+}
+
+func NewReleaseTrainServiceImpl(logger *zap.SugaredLogger, releaseTrainRepository ReleaseTrainRepository,
+	artifactPromotionRepository ArtifactPromotionRepository, environmentRepository repository2.EnvironmentRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineStageRepository repository3.PipelineStageRepository, ciArtifactRepository repository.CiArtifactRepository,
+	scanResultRepository security.ImageScanResultRepository, imageTaggingService pipeline.ImageTaggingService,
+	appGroupService appGroup.AppGroupService, workflowDagExecutor pipeline.WorkflowDagExecutor) *ReleaseTrainServiceImpl {
+	return &ReleaseTrainServiceImpl{
+		logger:                      logger,
+		releaseTrainRepository:      releaseTrainRepository,
+		artifactPromotionRepository: artifactPromotionRepository,
+		environmentRepository:       environmentRepository,
+		pipelineRepository:          pipelineRepository,
+		cdWorkflowRepository:        cdWorkflowRepository,
+		pipelineStageRepository:     pipelineStageRepository,
+		ciArtifactRepository:        ciArtifactRepository,
+		scanResultRepository:        scanResultRepository,
+		imageTaggingService:         imageTaggingService,
+		appGroupService:             appGroupService,
+		workflowDagExecutor:         workflowDagExecutor,
+	}
+}
+
+func (impl *ReleaseTrainServiceImpl) CreateReleaseTrain(request *ReleaseTrainDto) (*ReleaseTrainDto, error) {
+	err := validateReleaseTrain(request)
+	if err != nil {
+		return nil, newBadRequestError(err.Error())
+	}
+	existing, err := impl.releaseTrainRepository.FindActiveByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching release train by name", "err", err, "name", request.Name)
+		return nil, err
+	}
+	if existing.Id > 0 {
+		return nil, newBadRequestError(fmt.Sprintf("release train %s already exists", request.Name))
+	}
+	tx, err := impl.releaseTrainRepository.GetConnection().Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	now := time.Now()
+	releaseTrain := &ReleaseTrain{
+		Name:        request.Name,
+		Description: request.Description,
+		Active:      true,
+		AuditLog:    sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	err = impl.releaseTrainRepository.Save(releaseTrain, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train", "err", err, "releaseTrain", releaseTrain)
+		return nil, err
+	}
+	err = impl.saveStages(releaseTrain.Id, request, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetReleaseTrain(releaseTrain.Id)
+}
+
+func (impl *ReleaseTrainServiceImpl) UpdateReleaseTrain(request *ReleaseTrainDto) (*ReleaseTrainDto, error) {
+	err := validateReleaseTrain(request)
+	if err != nil {
+		return nil, newBadRequestError(err.Error())
+	}
+	releaseTrain, err := impl.findReleaseTrain(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if releaseTrain.Name != request.Name {
+		existing, err := impl.releaseTrainRepository.FindActiveByName(request.Name)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching release train by name", "err", err, "name", request.Name)
+			return nil, err
+		}
+		if existing.Id > 0 {
+			return nil, newBadRequestError(fmt.Sprintf("release train %s already exists", request.Name))
+		}
+	}
+	tx, err := impl.releaseTrainRepository.GetConnection().Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	releaseTrain.Name = request.Name
+	releaseTrain.Description = request.Description
+	releaseTrain.UpdatedOn = time.Now()
+	releaseTrain.UpdatedBy = request.UserId
+	err = impl.releaseTrainRepository.Update(releaseTrain, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating release train", "err", err, "releaseTrain", releaseTrain)
+		return nil, err
+	}
+	err = impl.releaseTrainRepository.MarkStagesDeletedByReleaseTrainId(releaseTrain.Id, request.UserId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting release train stages", "err", err, "releaseTrainId", releaseTrain.Id)
+		return nil, err
+	}
+	err = impl.saveStages(releaseTrain.Id, request, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetReleaseTrain(releaseTrain.Id)
+}
+
+func (impl *ReleaseTrainServiceImpl) saveStages(releaseTrainId int, request *ReleaseTrainDto, tx *pg.Tx) error {
+	now := time.Now()
+	stages := make([]*ReleaseTrainStage, 0, len(request.Stages))
+	for i, stageDto := range request.Stages {
+		stage := &ReleaseTrainStage{
+			ReleaseTrainId: releaseTrainId,
+			EnvironmentId:  stageDto.EnvironmentId,
+			StageOrder:     i,
+			AuditLog:       sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+		}
+		if stageDto.Policy != nil {
+			policy, err := json.Marshal(stageDto.Policy)
+			if err != nil {
+				return err
+			}
+			stage.Policy = string(policy)
+		}
+		stages = append(stages, stage)
+	}
+	err := impl.releaseTrainRepository.SaveStages(stages, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving release train stages", "err", err, "releaseTrainId", releaseTrainId)
+	}
+	return err
+}
+
+func (impl *ReleaseTrainServiceImpl) DeleteReleaseTrain(id int, userId int32) error {
+	releaseTrain, err := impl.findReleaseTrain(id)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.releaseTrainRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	releaseTrain.Active = false
+	releaseTrain.UpdatedOn = time.Now()
+	releaseTrain.UpdatedBy = userId
+	err = impl.releaseTrainRepository.Update(releaseTrain, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting release train", "err", err, "id", id)
+		return err
+	}
+	err = impl.releaseTrainRepository.MarkStagesDeletedByReleaseTrainId(id, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting release train stages", "err", err, "releaseTrainId", id)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *ReleaseTrainServiceImpl) GetReleaseTrain(id int) (*ReleaseTrainDto, error) {
+	releaseTrain, err := impl.findReleaseTrain(id)
+	if err != nil {
+		return nil, err
+	}
+	releaseTrains, err := impl.adaptReleaseTrains([]*ReleaseTrain{releaseTrain})
+	if err != nil {
+		return nil, err
+	}
+	return releaseTrains[0], nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetAllReleaseTrains() ([]*ReleaseTrainDto, error) {
+	releaseTrains, err := impl.releaseTrainRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching release trains", "err", err)
+		return nil, err
+	}
+	return impl.adaptReleaseTrains(releaseTrains)
+}
+
+func (impl *ReleaseTrainServiceImpl) findReleaseTrain(id int) (*ReleaseTrain, error) {
+	releaseTrain, err := impl.releaseTrainRepository.FindActiveById(id)
+	if err == pg.ErrNoRows {
+		errMsg := fmt.Sprintf("release train %d not found", id)
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, Code: "404", InternalMessage: errMsg, UserMessage: errMsg}
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching release train", "err", err, "id", id)
+		return nil, err
+	}
+	return releaseTrain, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) adaptReleaseTrains(releaseTrains []*ReleaseTrain) ([]*ReleaseTrainDto, error) {
+	releaseTrainIds := make([]int, 0, len(releaseTrains))
+	for _, releaseTrain := range releaseTrains {
+		releaseTrainIds = append(releaseTrainIds, releaseTrain.Id)
+	}
+	stages, err := impl.releaseTrainRepository.FindStagesByReleaseTrainIds(releaseTrainIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train stages", "err", err, "releaseTrainIds", releaseTrainIds)
+		return nil, err
+	}
+	envIds := make([]int, 0, len(stages))
+	for _, stage := range stages {
+		envIds = append(envIds, stage.EnvironmentId)
+	}
+	envs, err := impl.getEnvironments(envIds)
+	if err != nil {
+		return nil, err
+	}
+	stagesByTrain := make(map[int][]*ReleaseTrainStageDto)
+	for _, stage := range stages {
+		policy, err := decodePolicy(stage)
+		if err != nil {
+			impl.logger.Errorw("error in decoding promotion policy", "err", err, "stageId", stage.Id)
+			return nil, err
+		}
+		stageDto := &ReleaseTrainStageDto{
+			EnvironmentId: stage.EnvironmentId,
+			Policy:        policy,
+		}
+		if env, ok := envs[stage.EnvironmentId]; ok {
+			stageDto.EnvironmentName = env.Name
+			stageDto.EnvironmentIdentifier = env.EnvironmentIdentifier
+		}
+		stagesByTrain[stage.ReleaseTrainId] = append(stagesByTrain[stage.ReleaseTrainId], stageDto)
+	}
+	result := make([]*ReleaseTrainDto, 0, len(releaseTrains))
+	for _, releaseTrain := range releaseTrains {
+		result = append(result, &ReleaseTrainDto{
+			Id:          releaseTrain.Id,
+			Name:        releaseTrain.Name,
+			Description: releaseTrain.Description,
+			Stages:      stagesByTrain[releaseTrain.Id],
+		})
+	}
+	return result, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) getEnvironmentNames(envIds []int) (map[int]string, error) {
+	envs, err := impl.getEnvironments(envIds)
+	if err != nil {
+		return nil, err
+	}
+	envNames := make(map[int]string)
+	for id, env := range envs {
+		envNames[id] = env.Name
+	}
+	return envNames, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) getEnvironments(envIds []int) (map[int]*repository2.Environment, error) {
+	envsById := make(map[int]*repository2.Environment)
+	if len(envIds) == 0 {
+		return envsById, nil
+	}
+	ids := make([]*int, 0, len(envIds))
+	for i := range envIds {
+		ids = append(ids, &envIds[i])
+	}
+	envs, err := impl.environmentRepository.FindByIds(ids)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "err", err, "envIds", envIds)
+		return nil, err
+	}
+	for _, env := range envs {
+		envsById[env.Id] = env
+	}
+	return envsById, nil
+}
+
+// resolveStages returns the stage of the destination environment and the stage before it
+func (impl *ReleaseTrainServiceImpl) resolveStages(releaseTrainId int, destinationEnvironmentId int) (source *ReleaseTrainStage, destination *ReleaseTrainStage, err error) {
+	_, err = impl.findReleaseTrain(releaseTrainId)
+	if err != nil {
+		return nil, nil, err
+	}
+	stages, err := impl.releaseTrainRepository.FindStagesByReleaseTrainIds([]int{releaseTrainId})
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train stages", "err", err, "releaseTrainId", releaseTrainId)
+		return nil, nil, err
+	}
+	for i, stage := range stages {
+		if stage.EnvironmentId != destinationEnvironmentId {
+			continue
+		}
+		if i == 0 {
+			return nil, nil, newBadRequestError("artifacts can not be promoted to the first environment of a release train")
+		}
+		return stages[i-1], stage, nil
+	}
+	return nil, nil, newBadRequestError(fmt.Sprintf("environment %d is not part of release train %d", destinationEnvironmentId, releaseTrainId))
+}
+
+func (impl *ReleaseTrainServiceImpl) findPipeline(appId int, envId int) (*pipelineConfig.Pipeline, error) {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentId(appId, envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching cd pipeline", "err", err, "appId", appId, "envId", envId)
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, nil
+	}
+	return pipelines[0], nil
+}
+
+// findDeployedArtifacts returns the distinct artifacts successfully deployed by the latest deployments of the pipeline, latest first
+func (impl *ReleaseTrainServiceImpl) findDeployedArtifacts(pipelineId int) ([]*repository.CiArtifact, error) {
+	runners, err := impl.cdWorkflowRepository.FindArtifactByPipelineIdAndRunnerType(pipelineId, bean2.CD_WORKFLOW_TYPE_DEPLOY, ELIGIBILITY_DEPLOYMENT_LOOKBACK)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployments of pipeline", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	var artifacts []*repository.CiArtifact
+	seen := make(map[int]bool)
+	for _, runner := range runners {
+		if !isDeploySucceeded(runner.Status) || runner.CdWorkflow == nil || runner.CdWorkflow.CiArtifact == nil || seen[runner.CdWorkflow.CiArtifactId] {
+			continue
+		}
+		seen[runner.CdWorkflow.CiArtifactId] = true
+		artifacts = append(artifacts, runner.CdWorkflow.CiArtifact)
+	}
+	return artifacts, nil
+}
+
+func isDeploySucceeded(status string) bool {
+	for _, succeededStatus := range deploySucceededStatuses {
+		if status == succeededStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func (impl *ReleaseTrainServiceImpl) collectFacts(policy *PromotionPolicy, sourcePipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact) (*promotionFacts, error) {
+	facts := &promotionFacts{}
+	deployRunner, err := impl.cdWorkflowRepository.FindLatestRunnerByPipelineIdAndArtifactId(sourcePipeline.Id, artifact.Id, bean2.CD_WORKFLOW_TYPE_DEPLOY, deploySucceededStatuses)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment of artifact", "err", err, "pipelineId", sourcePipeline.Id, "ciArtifactId", artifact.Id)
+		return nil, err
+	}
+	if err == nil {
+		facts.deployedOnSource = true
+		facts.sourceDeployedOn = deployRunner.FinishedOn
+		if facts.sourceDeployedOn.IsZero() {
+			facts.sourceDeployedOn = deployRunner.StartedOn
+		}
+	}
+	if policy == nil {
+		return facts, nil
+	}
+	if policy.RequireTestsPassed {
+		postStage, err := impl.pipelineStageRepository.GetCdStageByCdPipelineIdAndStageType(sourcePipeline.Id, repository3.PIPELINE_STAGE_TYPE_POST_CD)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching post stage of pipeline", "err", err, "pipelineId", sourcePipeline.Id)
+			return nil, err
+		}
+		facts.postStageConfigured = len(sourcePipeline.PostStageConfig) > 0 || postStage != nil
+		if facts.postStageConfigured {
+			postRunner, err := impl.cdWorkflowRepository.FindLatestRunnerByPipelineIdAndArtifactId(sourcePipeline.Id, artifact.Id, bean2.CD_WORKFLOW_TYPE_POST, nil)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in fetching post stage run of artifact", "err", err, "pipelineId", sourcePipeline.Id, "ciArtifactId", artifact.Id)
+				return nil, err
+			}
+			if err == nil {
+				facts.postStageStatus = postRunner.Status
+			}
+		}
+	}
+	if policy.BlockCriticalVulnerabilities && len(artifact.ImageDigest) > 0 {
+		scanResults, err := impl.scanResultRepository.FindByImageDigest(artifact.ImageDigest)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching scan result of artifact", "err", err, "ciArtifactId", artifact.Id)
+			return nil, err
+		}
+		facts.scanned = artifact.Scanned || len(scanResults) > 0
+		seen := make(map[string]bool)
+		for _, scanResult := range scanResults {
+			if scanResult.CveStore.Severity == security.Critical && !seen[scanResult.CveStore.Name] {
+				seen[scanResult.CveStore.Name] = true
+				facts.criticalVulnerabilities = append(facts.criticalVulnerabilities, scanResult.CveStore.Name)
+			}
+		}
+	}
+	if len(policy.RequiredImageTags) > 0 {
+		imageTags, err := impl.imageTaggingService.GetTagsByArtifactId(artifact.Id)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching image tags of artifact", "err", err, "ciArtifactId", artifact.Id)
+			return nil, err
+		}
+		for _, imageTag := range imageTags {
+			if !imageTag.Deleted {
+				facts.imageTags = append(facts.imageTags, imageTag.TagName)
+			}
+		}
+	}
+	return facts, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) evaluate(destinationStage *ReleaseTrainStage, sourcePipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact) ([]*PolicyEvaluation, error) {
+	policy, err := decodePolicy(destinationStage)
+	if err != nil {
+		impl.logger.Errorw("error in decoding promotion policy", "err", err, "stageId", destinationStage.Id)
+		return nil, err
+	}
+	facts, err := impl.collectFacts(policy, sourcePipeline, artifact)
+	if err != nil {
+		return nil, err
+	}
+	return evaluatePromotionPolicy(policy, facts, time.Now()), nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetEligibility(releaseTrainId int, appId int, ciArtifactId int) ([]*EnvironmentEligibility, error) {
+	_, err := impl.findReleaseTrain(releaseTrainId)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := impl.releaseTrainRepository.FindStagesByReleaseTrainIds([]int{releaseTrainId})
+	if err != nil {
+		impl.logger.Errorw("error in fetching release train stages", "err", err, "releaseTrainId", releaseTrainId)
+		return nil, err
+	}
+	var requestedArtifact *repository.CiArtifact
+	if ciArtifactId > 0 {
+		requestedArtifact, err = impl.getArtifactOfApp(ciArtifactId, appId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	envIds := make([]int, 0, len(stages))
+	for _, stage := range stages {
+		envIds = append(envIds, stage.EnvironmentId)
+	}
+	envNames, err := impl.getEnvironmentNames(envIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*EnvironmentEligibility, 0, len(stages))
+	for i := 1; i < len(stages); i++ {
+		sourceStage, destinationStage := stages[i-1], stages[i]
+		eligibility := &EnvironmentEligibility{
+			EnvironmentId:       destinationStage.EnvironmentId,
+			EnvironmentName:     envNames[destinationStage.EnvironmentId],
+			SourceEnvironmentId: sourceStage.EnvironmentId,
+			Artifacts:           []*ArtifactEligibility{},
+		}
+		result = append(result, eligibility)
+		sourcePipeline, err := impl.findPipeline(appId, sourceStage.EnvironmentId)
+		if err != nil {
+			return nil, err
+		}
+		destinationPipeline, err := impl.findPipeline(appId, destinationStage.EnvironmentId)
+		if err != nil {
+			return nil, err
+		}
+		if sourcePipeline == nil || destinationPipeline == nil {
+			eligibility.Message = "app has no cd pipeline on the source or destination environment"
+			continue
+		}
+		eligibility.PipelineId = destinationPipeline.Id
+		var candidates []*repository.CiArtifact
+		if requestedArtifact != nil {
+			candidates = []*repository.CiArtifact{requestedArtifact}
+		} else {
+			candidates, err = impl.findDeployedArtifacts(sourcePipeline.Id)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, artifact := range candidates {
+			evaluations, err := impl.evaluate(destinationStage, sourcePipeline, artifact)
+			if err != nil {
+				return nil, err
+			}
+			eligibility.Artifacts = append(eligibility.Artifacts, &ArtifactEligibility{
+				CiArtifactId:      artifact.Id,
+				Image:             artifact.Image,
+				Eligible:          isEligible(evaluations),
+				PolicyEvaluations: evaluations,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) Promote(ctx context.Context, request *PromotionRequest) (*PromotionResponse, error) {
+	sourceStage, destinationStage, err := impl.resolveStages(request.ReleaseTrainId, request.DestinationEnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	sourcePipeline, err := impl.findPipeline(request.AppId, sourceStage.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	destinationPipeline, err := impl.findPipeline(request.AppId, destinationStage.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	if sourcePipeline == nil || destinationPipeline == nil {
+		return nil, newBadRequestError("app has no cd pipeline on the source or destination environment of the promotion")
+	}
+	artifact, err := impl.getArtifactOfApp(request.CiArtifactId, request.AppId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.promote(ctx, request.ReleaseTrainId, 0, sourceStage, destinationStage, sourcePipeline, destinationPipeline, artifact, request.UserId)
+}
+
+// getArtifactOfApp fetches the artifact, artifacts built by pipelines of other apps are rejected as access is checked
+// on the app of the request only
+func (impl *ReleaseTrainServiceImpl) getArtifactOfApp(ciArtifactId int, appId int) (*repository.CiArtifact, error) {
+	artifactAppId, err := impl.ciArtifactRepository.FindAppIdById(ciArtifactId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app of artifact", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || artifactAppId != appId {
+		return nil, newBadRequestError(fmt.Sprintf("artifact %d is not an artifact of app %d", ciArtifactId, appId))
+	}
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// promote evaluates the destination policy and deploys the artifact on the destination pipeline when it is satisfied,
+// deployment windows and approvals of the destination pipeline still apply. Every attempt is recorded in the history
+func (impl *ReleaseTrainServiceImpl) promote(ctx context.Context, releaseTrainId int, appGroupId int, sourceStage *ReleaseTrainStage,
+	destinationStage *ReleaseTrainStage, sourcePipeline *pipelineConfig.Pipeline, destinationPipeline *pipelineConfig.Pipeline,
+	artifact *repository.CiArtifact, userId int32) (*PromotionResponse, error) {
+	evaluations, err := impl.evaluate(destinationStage, sourcePipeline, artifact)
+	if err != nil {
+		return nil, err
+	}
+	promotion := &ArtifactPromotion{
+		ReleaseTrainId:           releaseTrainId,
+		AppId:                    destinationPipeline.AppId,
+		CiArtifactId:             artifact.Id,
+		SourceEnvironmentId:      sourceStage.EnvironmentId,
+		DestinationEnvironmentId: destinationStage.EnvironmentId,
+		DestinationPipelineId:    destinationPipeline.Id,
+		AppGroupId:               appGroupId,
+		PromotedBy:               userId,
+		PromotedOn:               time.Now(),
+	}
+	policyResult, err := json.Marshal(evaluations)
+	if err != nil {
+		return nil, err
+	}
+	promotion.PolicyResult = string(policyResult)
+	if !isEligible(evaluations) {
+		promotion.Status = PROMOTION_STATUS_BLOCKED
+		promotion.Message = "artifact does not satisfy the promotion policy of the destination environment"
+	} else {
+		overrideRequest := &bean2.ValuesOverrideRequest{
+			PipelineId:     destinationPipeline.Id,
+			AppId:          destinationPipeline.AppId,
+			CiArtifactId:   artifact.Id,
+			CdWorkflowType: bean2.CD_WORKFLOW_TYPE_DEPLOY,
+			UserId:         userId,
+		}
+		releaseId, err := impl.workflowDagExecutor.ManualCdTrigger(overrideRequest, ctx)
+		if err != nil {
+			impl.logger.Errorw("error in deploying promoted artifact", "err", err, "pipelineId", destinationPipeline.Id, "ciArtifactId", artifact.Id)
+			promotion.Status = PROMOTION_STATUS_FAILED
+			promotion.Message = getErrorMessage(err)
+		} else {
+			promotion.Status = PROMOTION_STATUS_PROMOTED
+			promotion.ReleaseId = releaseId
+		}
+	}
+	err = impl.artifactPromotionRepository.Save(promotion)
+	if err != nil {
+		impl.logger.Errorw("error in saving artifact promotion", "err", err, "promotion", promotion)
+		return nil, err
+	}
+	return &PromotionResponse{
+		Id:                       promotion.Id,
+		AppId:                    promotion.AppId,
+		AppName:                  destinationPipeline.App.AppName,
+		CiArtifactId:             promotion.CiArtifactId,
+		SourceEnvironmentId:      promotion.SourceEnvironmentId,
+		DestinationEnvironmentId: promotion.DestinationEnvironmentId,
+		DestinationPipelineId:    promotion.DestinationPipelineId,
+		Status:                   promotion.Status,
+		Message:                  promotion.Message,
+		ReleaseId:                promotion.ReleaseId,
+		PolicyEvaluations:        evaluations,
+	}, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) BulkPromote(ctx context.Context, request *BulkPromotionRequest) ([]*PromotionResponse, error) {
+	sourceStage, destinationStage, err := impl.resolveStages(request.ReleaseTrainId, request.DestinationEnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+	appIds, err := impl.appGroupService.GetAppIdsByAppGroupId(request.AppGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching apps of app group", "err", err, "appGroupId", request.AppGroupId)
+		return nil, err
+	}
+	if len(appIds) == 0 {
+		return nil, newBadRequestError(fmt.Sprintf("app group %d has no apps", request.AppGroupId))
+	}
+	sourcePipelines, err := impl.pipelineRepository.FindActiveByInFilter(sourceStage.EnvironmentId, appIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching source pipelines", "err", err, "envId", sourceStage.EnvironmentId)
+		return nil, err
+	}
+	destinationPipelines, err := impl.pipelineRepository.FindActiveByInFilter(destinationStage.EnvironmentId, appIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching destination pipelines", "err", err, "envId", destinationStage.EnvironmentId)
+		return nil, err
+	}
+	sourcePipelineByApp := make(map[int]*pipelineConfig.Pipeline)
+	for _, sourcePipeline := range sourcePipelines {
+		sourcePipelineByApp[sourcePipeline.AppId] = sourcePipeline
+	}
+	destinationPipelineByApp := make(map[int]*pipelineConfig.Pipeline)
+	for _, destinationPipeline := range destinationPipelines {
+		destinationPipelineByApp[destinationPipeline.AppId] = destinationPipeline
+	}
+	responses := make([]*PromotionResponse, 0, len(appIds))
+	for _, appId := range appIds {
+		skipped := &PromotionResponse{
+			AppId:                    appId,
+			SourceEnvironmentId:      sourceStage.EnvironmentId,
+			DestinationEnvironmentId: destinationStage.EnvironmentId,
+			Status:                   PROMOTION_STATUS_SKIPPED,
+		}
+		sourcePipeline, destinationPipeline := sourcePipelineByApp[appId], destinationPipelineByApp[appId]
+		if sourcePipeline == nil || destinationPipeline == nil {
+			skipped.Message = "app has no cd pipeline on the source or destination environment"
+			responses = append(responses, skipped)
+			continue
+		}
+		skipped.AppName = destinationPipeline.App.AppName
+		skipped.DestinationPipelineId = destinationPipeline.Id
+		if request.CheckTriggerAuth != nil && !request.CheckTriggerAuth(appId, destinationStage.EnvironmentId) {
+			skipped.Message = "unauthorized to deploy on the destination environment"
+			responses = append(responses, skipped)
+			continue
+		}
+		artifacts, err := impl.findDeployedArtifacts(sourcePipeline.Id)
+		if err != nil {
+			return nil, err
+		}
+		if len(artifacts) == 0 {
+			skipped.Message = "no artifact deployed on the source environment"
+			responses = append(responses, skipped)
+			continue
+		}
+		artifact := artifacts[0]
+		skipped.CiArtifactId = artifact.Id
+		deployedOnDestination, err := impl.isLatestDeployment(destinationPipeline.Id, artifact.Id)
+		if err != nil {
+			return nil, err
+		}
+		if deployedOnDestination {
+			skipped.Message = "artifact is already deployed on the destination environment"
+			responses = append(responses, skipped)
+			continue
+		}
+		response, err := impl.promote(ctx, request.ReleaseTrainId, request.AppGroupId, sourceStage, destinationStage, sourcePipeline, destinationPipeline, artifact, request.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in promoting artifact of app group", "err", err, "appId", appId, "ciArtifactId", artifact.Id)
+			skipped.Message = getErrorMessage(err)
+			responses = append(responses, skipped)
+			continue
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) isLatestDeployment(pipelineId int, ciArtifactId int) (bool, error) {
+	runner, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(pipelineId, bean2.CD_WORKFLOW_TYPE_DEPLOY)
+	if err == pg.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching latest deployment of pipeline", "err", err, "pipelineId", pipelineId)
+		return false, err
+	}
+	return runner.CdWorkflow != nil && runner.CdWorkflow.CiArtifactId == ciArtifactId && runner.Status != pipelineConfig.WorkflowFailed && runner.Status != pipelineConfig.WorkflowAborted, nil
+}
+
+func (impl *ReleaseTrainServiceImpl) GetPromotionHistory(appId int, ciArtifactId int, offset int, size int) ([]*PromotionHistoryDto, error) {
+	var promotions []*ArtifactPromotion
+	var err error
+	if ciArtifactId > 0 {
+		promotions, err = impl.artifactPromotionRepository.FindByAppIdAndCiArtifactId(appId, ciArtifactId)
+	} else {
+		if size <= 0 {
+			size = PROMOTION_HISTORY_DEFAULT_SIZE
+		}
+		promotions, err = impl.artifactPromotionRepository.FindByAppId(appId, offset, size)
+	}
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion history", "err", err, "appId", appId, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	var envIds []int
+	for _, promotion := range promotions {
+		envIds = append(envIds, promotion.SourceEnvironmentId, promotion.DestinationEnvironmentId)
+	}
+	envNames, err := impl.getEnvironmentNames(envIds)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*PromotionHistoryDto, 0, len(promotions))
+	for _, promotion := range promotions {
+		var evaluations []*PolicyEvaluation
+		if len(promotion.PolicyResult) > 0 {
+			err = json.Unmarshal([]byte(promotion.PolicyResult), &evaluations)
+			if err != nil {
+				impl.logger.Errorw("error in decoding promotion policy result", "err", err, "promotionId", promotion.Id)
+			}
+		}
+		history = append(history, &PromotionHistoryDto{
+			Id:                         promotion.Id,
+			ReleaseTrainId:             promotion.ReleaseTrainId,
+			AppId:                      promotion.AppId,
+			CiArtifactId:               promotion.CiArtifactId,
+			SourceEnvironmentId:        promotion.SourceEnvironmentId,
+			SourceEnvironmentName:      envNames[promotion.SourceEnvironmentId],
+			DestinationEnvironmentId:   promotion.DestinationEnvironmentId,
+			DestinationEnvironmentName: envNames[promotion.DestinationEnvironmentId],
+			AppGroupId:                 promotion.AppGroupId,
+			Status:                     promotion.Status,
+			Message:                    promotion.Message,
+			ReleaseId:                  promotion.ReleaseId,
+			PolicyEvaluations:          evaluations,
+			PromotedBy:                 promotion.PromotedBy,
+			PromotedOn:                 promotion.PromotedOn,
+		})
+	}
+	return history, nil
+}
+
+func validateReleaseTrain(request *ReleaseTrainDto) error {
+	if len(request.Stages) < 2 {
+		return fmt.Errorf("release train needs at least two environments")
+	}
+	envIds := make(map[int]bool, len(request.Stages))
+	for i, stage := range request.Stages {
+		if envIds[stage.EnvironmentId] {
+			return fmt.Errorf("environment %d is added more than once", stage.EnvironmentId)
+		}
+		envIds[stage.EnvironmentId] = true
+		if stage.Policy == nil {
+			continue
+		}
+		if i == 0 {
+			return fmt.Errorf("first environment of a release train can not have a promotion policy")
+		}
+		if stage.Policy.SoakTimeMinutes < 0 {
+			return fmt.Errorf("soak time of environment %d can not be negative", stage.EnvironmentId)
+		}
+		for _, tag := range stage.Policy.RequiredImageTags {
+			if len(tag) == 0 {
+				return fmt.Errorf("required image tag of environment %d can not be empty", stage.EnvironmentId)
+			}
+		}
+	}
+	return nil
+}
+
+func decodePolicy(stage *ReleaseTrainStage) (*PromotionPolicy, error) {
+	if len(stage.Policy) == 0 {
+		return nil, nil
+	}
+	policy := &PromotionPolicy{}
+	err := json.Unmarshal([]byte(stage.Policy), policy)
+	return policy, err
+}
+
+func newBadRequestError(errMsg string) error {
+	return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", InternalMessage: errMsg, UserMessage: errMsg}
+}
+
+func getErrorMessage(err error) string {
+	if apiError, ok := err.(*util.ApiError); ok {
+		if userMessage, ok := apiError.UserMessage.(string); ok && len(userMessage) > 0 {
+			return userMessage
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,55 @@
+package releaseTrain
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// ciArtifactRepositoryStub serves artifacts of apps, the other methods are not used by the tests
+type ciArtifactRepositoryStub struct {
+	repository.CiArtifactRepository
+	artifacts map[int]*repository.CiArtifact
+	appIds    map[int]int
+}
+
+func (repo *ciArtifactRepositoryStub) FindAppIdById(id int) (int, error) {
+	appId, ok := repo.appIds[id]
+	if !ok {
+		return 0, pg.ErrNoRows
+	}
+	return appId, nil
+}
+
+func (repo *ciArtifactRepositoryStub) Get(id int) (*repository.CiArtifact, error) {
+	artifact, ok := repo.artifacts[id]
+	if !ok {
+		return nil, pg.ErrNoRows
+	}
+	return artifact, nil
+}
+
+func TestGetArtifactOfApp(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	ciArtifactRepository := &ciArtifactRepositoryStub{
+		artifacts: map[int]*repository.CiArtifact{7: {Id: 7, Image: "payments:a1"}},
+		appIds:    map[int]int{7: 3},
+	}
+	impl := &ReleaseTrainServiceImpl{logger: logger, ciArtifactRepository: ciArtifactRepository}
+
+	artifact, err := impl.getArtifactOfApp(7, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "payments:a1", artifact.Image)
+
+	//artifacts of other apps and unknown artifacts are rejected
+	for _, ciArtifactId := range []int{7, 8} {
+		_, err = impl.getArtifactOfApp(ciArtifactId, 4)
+		apiError, ok := err.(*util.ApiError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, apiError.HttpStatusCode)
+	}
+}
@@ -0,0 +1,93 @@
+package releaseTrain
+
+import "time"
+
+// PROMOTION_STATUS_SKIPPED is only reported in bulk promotion responses, skipped apps are not part of the promotion history
+const PROMOTION_STATUS_SKIPPED PromotionStatus = "SKIPPED"
+
+type ReleaseTrainDto struct {
+	Id          int                     `json:"id"`
+	Name        string                  `json:"name" validate:"required,max=50"`
+	Description string                  `json:"description" validate:"max=250"`
+	Stages      []*ReleaseTrainStageDto `json:"stages" validate:"required,min=2,dive"`
+	UserId      int32                   `json:"-"`
+}
+
+// ReleaseTrainStageDto the order of stages in a release train is the order in which artifacts are promoted, the
+// first stage is fed by regular cd and can not have a policy
+type ReleaseTrainStageDto struct {
+	EnvironmentId   int              `json:"environmentId" validate:"required"`
+	EnvironmentName string           `json:"environmentName"`
+	Policy          *PromotionPolicy `json:"policy,omitempty"`
+	//EnvironmentIdentifier is used for rbac of the stage
+	EnvironmentIdentifier string `json:"-"`
+}
+
+type PromotionRequest struct {
+	ReleaseTrainId           int   `json:"releaseTrainId" validate:"required"`
+	AppId                    int   `json:"appId" validate:"required"`
+	CiArtifactId             int   `json:"ciArtifactId" validate:"required"`
+	DestinationEnvironmentId int   `json:"destinationEnvironmentId" validate:"required"`
+	UserId                   int32 `json:"-"`
+}
+
+// BulkPromotionRequest promotes, for every app of the app group, the artifact last deployed on the stage before the
+// destination environment
+type BulkPromotionRequest struct {
+	ReleaseTrainId           int   `json:"releaseTrainId" validate:"required"`
+	AppGroupId               int   `json:"appGroupId" validate:"required"`
+	DestinationEnvironmentId int   `json:"destinationEnvironmentId" validate:"required"`
+	UserId                   int32 `json:"-"`
+	// CheckTriggerAuth reports whether the user can deploy the app on the destination environment
+	CheckTriggerAuth func(appId int, envId int) bool `json:"-"`
+}
+
+type PromotionResponse struct {
+	Id                       int                 `json:"id,omitempty"`
+	AppId                    int                 `json:"appId"`
+	AppName                  string              `json:"appName,omitempty"`
+	CiArtifactId             int                 `json:"ciArtifactId,omitempty"`
+	SourceEnvironmentId      int                 `json:"sourceEnvironmentId"`
+	DestinationEnvironmentId int                 `json:"destinationEnvironmentId"`
+	DestinationPipelineId    int                 `json:"destinationPipelineId,omitempty"`
+	Status                   PromotionStatus     `json:"status"`
+	Message                  string              `json:"message,omitempty"`
+	ReleaseId                int                 `json:"releaseId,omitempty"`
+	PolicyEvaluations        []*PolicyEvaluation `json:"policyEvaluations,omitempty"`
+}
+
+type PromotionHistoryDto struct {
+	Id                         int                 `json:"id"`
+	ReleaseTrainId             int                 `json:"releaseTrainId"`
+	AppId                      int                 `json:"appId"`
+	CiArtifactId               int                 `json:"ciArtifactId"`
+	SourceEnvironmentId        int                 `json:"sourceEnvironmentId"`
+	SourceEnvironmentName      string              `json:"sourceEnvironmentName"`
+	DestinationEnvironmentId   int                 `json:"destinationEnvironmentId"`
+	DestinationEnvironmentName string              `json:"destinationEnvironmentName"`
+	AppGroupId                 int                 `json:"appGroupId,omitempty"`
+	Status                     PromotionStatus     `json:"status"`
+	Message                    string              `json:"message,omitempty"`
+	ReleaseId                  int                 `json:"releaseId,omitempty"`
+	PolicyEvaluations          []*PolicyEvaluation `json:"policyEvaluations"`
+	PromotedBy                 int32               `json:"promotedBy"`
+	PromotedOn                 time.Time           `json:"promotedOn"`
+}
+
+// EnvironmentEligibility lists the artifacts deployed on the previous stage of a release train and whether they can
+// be promoted to this environment
+type EnvironmentEligibility struct {
+	EnvironmentId       int                    `json:"environmentId"`
+	EnvironmentName     string                 `json:"environmentName"`
+	SourceEnvironmentId int                    `json:"sourceEnvironmentId"`
+	PipelineId          int                    `json:"pipelineId,omitempty"`
+	Message             string                 `json:"message,omitempty"`
+	Artifacts           []*ArtifactEligibility `json:"artifacts"`
+}
+
+type ArtifactEligibility struct {
+	CiArtifactId      int                 `json:"ciArtifactId"`
+	Image             string              `json:"image"`
+	Eligible          bool                `json:"eligible"`
+	PolicyEvaluations []*PolicyEvaluation `json:"policyEvaluations"`
+}
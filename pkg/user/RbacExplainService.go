@@ -0,0 +1,252 @@
+package user
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RbacExplainService answers what a user can do and why, on top of the policies loaded in the enforcer. Checks are
+// matched lower cased, the way the policies are stored
+type RbacExplainService interface {
+	// Explain evaluates a single check for the user and lists the policy lines which decided it
+	Explain(request *bean.RbacExplainRequest) (*bean.RbacExplainDto, error)
+	// GetEffectivePermissions lists the roles the user has directly and through groups, with their policy lines
+	GetEffectivePermissions(userId int32, emailId string) (*bean.EffectivePermissionsDto, error)
+	// GetUsersWithAccess lists the active users and api tokens allowed all the checks, for access reviews
+	GetUsersWithAccess(request *bean.RbacAccessReviewRequest) ([]*bean.RbacSubjectAccessDto, error)
+}
+
+type RbacExplainServiceImpl struct {
+	logger              *zap.SugaredLogger
+	userRepository      repository2.UserRepository
+	userAuthRepository  repository2.UserAuthRepository
+	roleGroupRepository repository2.RoleGroupRepository
+}
+
+func NewRbacExplainServiceImpl(logger *zap.SugaredLogger, userRepository repository2.UserRepository,
+	userAuthRepository repository2.UserAuthRepository, roleGroupRepository repository2.RoleGroupRepository) *RbacExplainServiceImpl {
+	return &RbacExplainServiceImpl{
+		logger:              logger,
+		userRepository:      userRepository,
+		userAuthRepository:  userAuthRepository,
+		roleGroupRepository: roleGroupRepository,
+	}
+}
+
+func (impl *RbacExplainServiceImpl) Explain(request *bean.RbacExplainRequest) (*bean.RbacExplainDto, error) {
+	user, err := impl.getUser(request.UserId, request.EmailId)
+	if err != nil {
+		return nil, err
+	}
+	check := lowerRbacCheck(request.RbacCheck)
+	result := casbin2.GetRbacSnapshot().Explain(strings.ToLower(user.EmailId), check.Resource, check.Action, check.Object)
+	explanation := &bean.RbacExplainDto{
+		UserId:          user.Id,
+		EmailId:         user.EmailId,
+		RbacCheck:       check,
+		Allowed:         result.Allowed,
+		Reason:          getExplainReason(result),
+		MatchedPolicies: getRbacPolicyDtos(result.Matches),
+	}
+	if result.Allowed && !casbin2.IsWithinApiTokenScope(strings.ToLower(user.EmailId), check.Resource, check.Action, check.Object) {
+		explanation.Allowed = false
+		explanation.Reason = "denied, outside the scopes of the api token"
+	}
+	return explanation, nil
+}
+
+func (impl *RbacExplainServiceImpl) GetEffectivePermissions(userId int32, emailId string) (*bean.EffectivePermissionsDto, error) {
+	user, err := impl.getUser(userId, emailId)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := casbin2.GetRbacSnapshot()
+	subject := strings.ToLower(user.EmailId)
+	permissions := &bean.EffectivePermissionsDto{
+		UserId:   user.Id,
+		EmailId:  user.EmailId,
+		UserType: user.UserType,
+		Roles:    make([]*bean.EffectiveRoleDto, 0),
+	}
+	var groupCasbinNames []string
+	for role := range snapshot.GetRolePaths(subject) {
+		if role == bean.SUPERADMIN {
+			permissions.SuperAdmin = true
+		} else if strings.HasPrefix(role, "group:") {
+			groupCasbinNames = append(groupCasbinNames, role)
+		}
+	}
+	groupNames := make(map[string]string)
+	if len(groupCasbinNames) > 0 {
+		roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(groupCasbinNames)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching role groups", "err", err, "casbinNames", groupCasbinNames)
+			return nil, err
+		}
+		for _, roleGroup := range roleGroups {
+			groupNames[strings.ToLower(roleGroup.CasbinName)] = roleGroup.Name
+		}
+	}
+
+	rolesByName := make(map[string]*bean.EffectiveRoleDto)
+	var roleNames []string
+	for _, match := range snapshot.GetPermissions(subject) {
+		role, ok := rolesByName[match.Role]
+		if !ok {
+			role = &bean.EffectiveRoleDto{Role: match.Role, Path: match.Path}
+			for _, pathEntry := range match.Path {
+				if groupName, ok := groupNames[pathEntry]; ok {
+					role.Groups = append(role.Groups, groupName)
+				}
+			}
+			rolesByName[match.Role] = role
+			roleNames = append(roleNames, match.Role)
+			permissions.Roles = append(permissions.Roles, role)
+		}
+		role.Policies = append(role.Policies, getRbacPolicyDto(match))
+	}
+	if len(roleNames) > 0 {
+		roleModels, err := impl.userAuthRepository.GetRoleByRoles(roleNames)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching roles", "err", err, "userId", user.Id)
+			return nil, err
+		}
+		for i := range roleModels {
+			if role, ok := rolesByName[strings.ToLower(roleModels[i].Role)]; ok {
+				role.RoleFilter = getRoleFilterForRole(&roleModels[i])
+			}
+		}
+	}
+	return permissions, nil
+}
+
+func (impl *RbacExplainServiceImpl) GetUsersWithAccess(request *bean.RbacAccessReviewRequest) ([]*bean.RbacSubjectAccessDto, error) {
+	snapshot := casbin2.GetRbacSnapshot()
+	var matchesBySubject map[string][]*casbin2.PolicyMatch
+	for _, rbacCheck := range request.Checks {
+		check := lowerRbacCheck(*rbacCheck)
+		results := snapshot.GetSubjectsAllowed(check.Resource, check.Action, check.Object)
+		if matchesBySubject == nil {
+			matchesBySubject = make(map[string][]*casbin2.PolicyMatch)
+			for subject, result := range results {
+				if casbin2.IsWithinApiTokenScope(subject, check.Resource, check.Action, check.Object) {
+					matchesBySubject[subject] = result.Matches
+				}
+			}
+			continue
+		}
+		for subject := range matchesBySubject {
+			result, ok := results[subject]
+			if !ok || !casbin2.IsWithinApiTokenScope(subject, check.Resource, check.Action, check.Object) {
+				delete(matchesBySubject, subject)
+				continue
+			}
+			matchesBySubject[subject] = append(matchesBySubject[subject], result.Matches...)
+		}
+	}
+	subjectAccess := make([]*bean.RbacSubjectAccessDto, 0)
+	if len(matchesBySubject) == 0 {
+		return subjectAccess, nil
+	}
+	subjects := make([]string, 0, len(matchesBySubject))
+	for subject := range matchesBySubject {
+		subjects = append(subjects, subject)
+	}
+	users, err := impl.userRepository.FetchActiveUsersByEmails(subjects)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching users", "err", err)
+		return nil, err
+	}
+	for _, user := range users {
+		subjectAccess = append(subjectAccess, &bean.RbacSubjectAccessDto{
+			UserId:          user.Id,
+			EmailId:         user.EmailId,
+			UserType:        user.UserType,
+			MatchedPolicies: getRbacPolicyDtos(matchesBySubject[strings.ToLower(user.EmailId)]),
+		})
+	}
+	sort.Slice(subjectAccess, func(i, j int) bool {
+		return subjectAccess[i].EmailId < subjectAccess[j].EmailId
+	})
+	return subjectAccess, nil
+}
+
+func (impl *RbacExplainServiceImpl) getUser(userId int32, emailId string) (*repository2.UserModel, error) {
+	if userId > 0 {
+		user, err := impl.userRepository.GetById(userId)
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("user %d not found", userId)}
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching user", "err", err, "userId", userId)
+			return nil, err
+		}
+		return user, nil
+	}
+	if len(emailId) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "user id or email id is required"}
+	}
+	users, err := impl.userRepository.FetchActiveUsersByEmails([]string{strings.ToLower(emailId)})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching user", "err", err, "emailId", emailId)
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("user %s not found", emailId)}
+	}
+	return &users[0], nil
+}
+
+func lowerRbacCheck(check bean.RbacCheck) bean.RbacCheck {
+	return bean.RbacCheck{
+		Resource: strings.ToLower(check.Resource),
+		Action:   strings.ToLower(check.Action),
+		Object:   strings.ToLower(check.Object),
+	}
+}
+
+// getExplainReason describes the decision, the first deny line when denied by one, else the first allow line
+func getExplainReason(result *casbin2.ExplainResult) string {
+	if len(result.Matches) == 0 {
+		return "denied, no policy of the user matches"
+	}
+	for _, match := range result.Matches {
+		if match.Effect == casbin2.PolicyEffectDeny {
+			return "denied by " + describePolicyMatch(match)
+		}
+	}
+	return "allowed by " + describePolicyMatch(result.Matches[0])
+}
+
+func describePolicyMatch(match *casbin2.PolicyMatch) string {
+	description := fmt.Sprintf("policy %s, %s, %s of %s", match.Resource, match.Action, match.Object, match.Role)
+	if len(match.Path) > 1 {
+		description += " through " + strings.Join(match.Path[:len(match.Path)-1], " > ")
+	}
+	return description
+}
+
+func getRbacPolicyDtos(matches []*casbin2.PolicyMatch) []*bean.RbacPolicyDto {
+	policies := make([]*bean.RbacPolicyDto, 0, len(matches))
+	for _, match := range matches {
+		policies = append(policies, getRbacPolicyDto(match))
+	}
+	return policies
+}
+
+func getRbacPolicyDto(match *casbin2.PolicyMatch) *bean.RbacPolicyDto {
+	return &bean.RbacPolicyDto{
+		Role:     match.Role,
+		Path:     match.Path,
+		Resource: match.Resource,
+		Action:   match.Action,
+		Object:   match.Object,
+		Effect:   match.Effect,
+	}
+}
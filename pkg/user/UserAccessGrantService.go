@@ -0,0 +1,1051 @@
+package user
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	bean2 "github.com/devtron-labs/devtron/pkg/user/bean"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type UserAccessGrantService interface {
+	// GrantAccess grants the roles of the role filters and the groups to the user for the window of the request, access
+	// starting now is effective immediately, later ones are started by ProcessDueGrants
+	GrantAccess(request *bean.AccessGrantRequest, token string, managerAuth func(resource, token, object string) bool) ([]*bean.AccessGrantDto, error)
+	RevokeGrant(grantId int, userId int32, token string, managerAuth func(resource, token, object string) bool) error
+	GetGrantsByUserId(userId int32) ([]*bean.AccessGrantDto, error)
+
+	CreateAccessRequest(request *bean.AccessRequestDto) (*bean.AccessRequestDto, error)
+	CancelAccessRequest(id int, userId int32) error
+	GetAccessRequestsByUserId(userId int32) ([]*bean.AccessRequestDto, error)
+	// GetAccessRequestsForReview lists the pending requests the reviewer is allowed to approve
+	GetAccessRequestsForReview(reviewerId int32, token string, managerAuth func(resource, token, object string) bool) ([]*bean.AccessRequestDto, error)
+	// ReviewAccessRequest approves or rejects a pending request, the reviewer needs the same access as for granting it
+	ReviewAccessRequest(review *bean.AccessRequestReviewDto, token string, managerAuth func(resource, token, object string) bool) (*bean.AccessRequestDto, error)
+
+	// ProcessDueGrants starts scheduled grants and expires the ones past their window, called periodically
+	ProcessDueGrants()
+	GetAuditTrail(userId int32, offset int, size int) ([]*bean.AccessAuditDto, error)
+}
+
+type UserAccessGrantConfig struct {
+	// MaxAccessRequestDurationHours caps the window a user can ask access for
+	MaxAccessRequestDurationHours int `env:"MAX_ACCESS_REQUEST_DURATION_HOURS" envDefault:"168"`
+}
+
+type UserAccessGrantServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	userAccessGrantRepository repository2.UserAccessGrantRepository
+	userAuthRepository        repository2.UserAuthRepository
+	userRepository            repository2.UserRepository
+	roleGroupRepository       repository2.RoleGroupRepository
+	userCommonService         UserCommonService
+	userService               UserService
+	enforcer                  casbin2.Enforcer
+	config                    *UserAccessGrantConfig
+}
+
+func NewUserAccessGrantServiceImpl(logger *zap.SugaredLogger, userAccessGrantRepository repository2.UserAccessGrantRepository,
+	userAuthRepository repository2.UserAuthRepository, userRepository repository2.UserRepository,
+	roleGroupRepository repository2.RoleGroupRepository, userCommonService UserCommonService, userService UserService,
+	enforcer casbin2.Enforcer) *UserAccessGrantServiceImpl {
+	config := &UserAccessGrantConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Fatal("error occurred while parsing user access grant config", err)
+	}
+	return &UserAccessGrantServiceImpl{
+		logger:                    logger,
+		userAccessGrantRepository: userAccessGrantRepository,
+		userAuthRepository:        userAuthRepository,
+		userRepository:            userRepository,
+		roleGroupRepository:       roleGroupRepository,
+		userCommonService:         userCommonService,
+		userService:               userService,
+		enforcer:                  enforcer,
+		config:                    config,
+	}
+}
+
+func (impl *UserAccessGrantServiceImpl) GrantAccess(request *bean.AccessGrantRequest, token string, managerAuth func(resource, token, object string) bool) ([]*bean.AccessGrantDto, error) {
+	if len(request.RoleFilters) == 0 && len(request.Groups) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "role filters or groups are required"}
+	}
+	startsOn, err := getAccessWindowStart(request.StartsOn, request.ExpiresOn)
+	if err != nil {
+		return nil, err
+	}
+	user, err := impl.userRepository.GetById(request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", request.UserId)
+		return nil, err
+	}
+	if user.UserType == bean.USER_TYPE_API_TOKEN {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "access can not be granted to api tokens for a window"}
+	}
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(request.GrantedBy))
+	if err != nil {
+		return nil, err
+	}
+	for _, roleFilter := range request.RoleFilters {
+		if !impl.hasRoleFilterGrantAuth(roleFilter, token, managerAuth, isSuperAdmin) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "unauthorized to grant the requested access"}
+		}
+	}
+	var roleGroups []*repository2.RoleGroup
+	for _, groupName := range request.Groups {
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(groupName)
+		if err != nil {
+			impl.logger.Errorw("error in fetching role group", "err", err, "group", groupName)
+			return nil, err
+		}
+		if !impl.hasGroupGrantAuth(roleGroup, token, managerAuth, isSuperAdmin) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "unauthorized to grant the requested access"}
+		}
+		roleGroups = append(roleGroups, roleGroup)
+	}
+
+	var roles []*repository2.RoleModel
+	var rolePolicies []casbin2.Policy
+	for _, roleFilter := range request.RoleFilters {
+		filterRoles, policies, err := impl.resolveRoles(roleFilter, request.GrantedBy)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, filterRoles...)
+		rolePolicies = append(rolePolicies, policies...)
+	}
+	tx, err := impl.userAccessGrantRepository.GetConnection().Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	grants, err := impl.saveGrants(user.Id, roles, roleGroups, startsOn, request.ExpiresOn, request.Reason, 0, request.GrantedBy, tx)
+	if err != nil {
+		return nil, err
+	}
+	// policies of roles created for the grant
+	err = impl.commitWithPolicies(tx, rolePolicies)
+	if err != nil {
+		return nil, err
+	}
+	impl.startGrants(grants)
+	return impl.GetGrantsByUserId(user.Id)
+}
+
+func (impl *UserAccessGrantServiceImpl) saveGrants(userId int32, roles []*repository2.RoleModel, roleGroups []*repository2.RoleGroup,
+	startsOn time.Time, expiresOn time.Time, reason string, accessRequestId int, grantedBy int32, tx *pg.Tx) ([]*repository2.UserAccessGrant, error) {
+	var grants []*repository2.UserAccessGrant
+	var audits []*repository2.UserAccessAudit
+	newGrant := func(roleId int, roleGroupId int32) *repository2.UserAccessGrant {
+		return &repository2.UserAccessGrant{
+			UserId:          userId,
+			RoleId:          roleId,
+			RoleGroupId:     roleGroupId,
+			AccessRequestId: accessRequestId,
+			StartsOn:        startsOn,
+			ExpiresOn:       expiresOn,
+			Status:          repository2.ACCESS_GRANT_STATUS_SCHEDULED,
+			Reason:          reason,
+			AuditLog:        sql.AuditLog{CreatedOn: time.Now(), CreatedBy: grantedBy, UpdatedOn: time.Now(), UpdatedBy: grantedBy},
+		}
+	}
+	details := make(map[*repository2.UserAccessGrant]string)
+	for _, role := range roles {
+		grant := newGrant(role.Id, 0)
+		grants = append(grants, grant)
+		details[grant] = role.Role
+	}
+	for _, roleGroup := range roleGroups {
+		grant := newGrant(0, roleGroup.Id)
+		grants = append(grants, grant)
+		details[grant] = roleGroup.CasbinName
+	}
+	for _, grant := range grants {
+		err := impl.userAccessGrantRepository.SaveGrant(grant, tx)
+		if err != nil {
+			impl.logger.Errorw("error in saving access grant", "err", err, "userId", userId, "roleId", grant.RoleId, "roleGroupId", grant.RoleGroupId)
+			return nil, err
+		}
+		audits = append(audits, newAccessAudit(grant, repository2.ACCESS_AUDIT_ACTION_GRANTED,
+			fmt.Sprintf("%s from %s till %s", details[grant], startsOn.Format(time.RFC3339), expiresOn.Format(time.RFC3339)), grantedBy))
+	}
+	err := impl.userAccessGrantRepository.SaveAudit(audits, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving access audit", "err", err, "userId", userId)
+		return nil, err
+	}
+	return grants, nil
+}
+
+// commitWithPolicies adds the policies of roles created for the grants and then commits the grants. Policies added here
+// are removed again if the commit fails, so that casbin is not left with roles of grants which were never saved
+func (impl *UserAccessGrantServiceImpl) commitWithPolicies(tx *pg.Tx, policies []casbin2.Policy) error {
+	var addedPolicies []casbin2.Policy
+	if len(policies) > 0 {
+		failedPolicies := casbin2.AddPolicy(policies)
+		addedPolicies = excludePolicies(policies, failedPolicies)
+	}
+	err := tx.Commit()
+	if err != nil {
+		impl.logger.Errorw("error in committing access grants, removing added policies", "err", err, "policies", len(addedPolicies))
+		if len(addedPolicies) > 0 {
+			failedPolicies := casbin2.RemovePolicy(addedPolicies)
+			if len(failedPolicies) > 0 {
+				impl.logger.Errorw("error in removing policies of failed access grants", "policies", failedPolicies)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// excludePolicies returns the policies which are not part of excluded
+func excludePolicies(policies []casbin2.Policy, excluded []casbin2.Policy) []casbin2.Policy {
+	excludedSet := make(map[casbin2.Policy]bool, len(excluded))
+	for _, policy := range excluded {
+		excludedSet[policy] = true
+	}
+	var result []casbin2.Policy
+	for _, policy := range policies {
+		if !excludedSet[policy] {
+			result = append(result, policy)
+		}
+	}
+	return result
+}
+
+// startGrants starts the grants whose window has begun, failures are retried by ProcessDueGrants
+func (impl *UserAccessGrantServiceImpl) startGrants(grants []*repository2.UserAccessGrant) {
+	now := time.Now()
+	for _, grant := range grants {
+		if grant.StartsOn.After(now) {
+			continue
+		}
+		err := impl.activateGrant(grant)
+		if err != nil {
+			impl.logger.Errorw("error in starting access grant", "err", err, "grantId", grant.Id)
+		}
+	}
+}
+
+func (impl *UserAccessGrantServiceImpl) RevokeGrant(grantId int, userId int32, token string, managerAuth func(resource, token, object string) bool) error {
+	grant, err := impl.userAccessGrantRepository.FindGrantById(grantId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching access grant", "err", err, "grantId", grantId)
+		return err
+	}
+	if grant.Status != repository2.ACCESS_GRANT_STATUS_SCHEDULED && grant.Status != repository2.ACCESS_GRANT_STATUS_ACTIVE {
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("access grant is already %s", strings.ToLower(string(grant.Status)))}
+	}
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(userId))
+	if err != nil {
+		return err
+	}
+	allowed, err := impl.hasGrantAuth(grant.RoleId, grant.RoleGroupId, token, managerAuth, isSuperAdmin)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "unauthorized to revoke the access"}
+	}
+	return impl.endGrant(grant, repository2.ACCESS_GRANT_STATUS_REVOKED, userId)
+}
+
+func (impl *UserAccessGrantServiceImpl) GetGrantsByUserId(userId int32) ([]*bean.AccessGrantDto, error) {
+	grants, err := impl.userAccessGrantRepository.FindGrantsByUserId(userId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching access grants", "err", err, "userId", userId)
+		return nil, err
+	}
+	grantDtos := make([]*bean.AccessGrantDto, 0, len(grants))
+	for _, grant := range grants {
+		grantDto := &bean.AccessGrantDto{
+			Id:              grant.Id,
+			UserId:          grant.UserId,
+			AccessRequestId: grant.AccessRequestId,
+			StartsOn:        grant.StartsOn,
+			ExpiresOn:       grant.ExpiresOn,
+			Status:          string(grant.Status),
+			Reason:          grant.Reason,
+			GrantedBy:       grant.CreatedBy,
+			GrantedOn:       grant.CreatedOn,
+			RevokedBy:       grant.RevokedBy,
+		}
+		if !grant.RevokedOn.IsZero() {
+			revokedOn := grant.RevokedOn
+			grantDto.RevokedOn = &revokedOn
+		}
+		if grant.RoleId > 0 {
+			role, err := impl.userAuthRepository.GetRoleById(grant.RoleId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching role", "err", err, "roleId", grant.RoleId)
+				return nil, err
+			}
+			grantDto.RoleFilter = getRoleFilterForRole(role)
+		} else {
+			roleGroup, err := impl.getRoleGroupIncludingInactive(grant.RoleGroupId)
+			if err != nil {
+				return nil, err
+			}
+			grantDto.Group = roleGroup.Name
+		}
+		grantDtos = append(grantDtos, grantDto)
+	}
+	return grantDtos, nil
+}
+
+func (impl *UserAccessGrantServiceImpl) CreateAccessRequest(request *bean.AccessRequestDto) (*bean.AccessRequestDto, error) {
+	if (request.RoleFilter == nil) == (len(request.Group) == 0) {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "either a role filter or a group is to be requested"}
+	}
+	startsOn, err := getAccessWindowStart(request.StartsOn, request.ExpiresOn)
+	if err != nil {
+		return nil, err
+	}
+	maxDuration := time.Duration(impl.config.MaxAccessRequestDurationHours) * time.Hour
+	if request.ExpiresOn.Sub(startsOn) > maxDuration {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("access can be requested for at most %d hours", impl.config.MaxAccessRequestDurationHours)}
+	}
+	model := &repository2.UserAccessRequest{
+		UserId:     request.UserId,
+		RoleFilter: request.RoleFilter,
+		Reason:     request.Reason,
+		StartsOn:   startsOn,
+		ExpiresOn:  request.ExpiresOn,
+		Status:     repository2.ACCESS_REQUEST_STATUS_PENDING,
+		AuditLog:   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+	}
+	details := ""
+	if request.RoleFilter != nil {
+		if len(request.RoleFilter.Action) == 0 {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "action of the role filter is required"}
+		}
+		details = getRoleFilterDescription(*request.RoleFilter)
+	} else {
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(request.Group)
+		if util.IsErrNoRows(err) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, UserMessage: fmt.Sprintf("group %s not found", request.Group)}
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching role group", "err", err, "group", request.Group)
+			return nil, err
+		}
+		model.RoleGroupId = roleGroup.Id
+		details = roleGroup.CasbinName
+	}
+	tx, err := impl.userAccessGrantRepository.GetConnection().Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = impl.userAccessGrantRepository.SaveRequest(model, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving access request", "err", err, "userId", request.UserId)
+		return nil, err
+	}
+	audit := &repository2.UserAccessAudit{
+		UserId:          model.UserId,
+		AccessRequestId: model.Id,
+		Action:          repository2.ACCESS_AUDIT_ACTION_REQUESTED,
+		Details:         fmt.Sprintf("%s till %s: %s", details, model.ExpiresOn.Format(time.RFC3339), model.Reason),
+		PerformedBy:     model.UserId,
+		PerformedOn:     time.Now(),
+	}
+	err = impl.userAccessGrantRepository.SaveAudit([]*repository2.UserAccessAudit{audit}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving access audit", "err", err, "userId", request.UserId)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return impl.getAccessRequestDto(model)
+}
+
+func (impl *UserAccessGrantServiceImpl) CancelAccessRequest(id int, userId int32) error {
+	request, err := impl.userAccessGrantRepository.FindRequestById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching access request", "err", err, "id", id)
+		return err
+	}
+	if request.UserId != userId {
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "access request of another user can not be cancelled"}
+	}
+	if request.Status != repository2.ACCESS_REQUEST_STATUS_PENDING {
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("access request is already %s", strings.ToLower(string(request.Status)))}
+	}
+	return impl.updateRequestStatus(request, repository2.ACCESS_REQUEST_STATUS_CANCELLED, repository2.ACCESS_AUDIT_ACTION_CANCELLED, "", userId)
+}
+
+func (impl *UserAccessGrantServiceImpl) GetAccessRequestsByUserId(userId int32) ([]*bean.AccessRequestDto, error) {
+	requests, err := impl.userAccessGrantRepository.FindRequests(userId, nil)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching access requests", "err", err, "userId", userId)
+		return nil, err
+	}
+	return impl.getAccessRequestDtos(requests)
+}
+
+func (impl *UserAccessGrantServiceImpl) GetAccessRequestsForReview(reviewerId int32, token string, managerAuth func(resource, token, object string) bool) ([]*bean.AccessRequestDto, error) {
+	requests, err := impl.userAccessGrantRepository.FindRequests(0, []repository2.AccessRequestStatus{repository2.ACCESS_REQUEST_STATUS_PENDING})
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching pending access requests", "err", err)
+		return nil, err
+	}
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(reviewerId))
+	if err != nil {
+		return nil, err
+	}
+	var reviewableRequests []*repository2.UserAccessRequest
+	for _, request := range requests {
+		if request.UserId == reviewerId {
+			continue
+		}
+		allowed, err := impl.hasRequestGrantAuth(request, token, managerAuth, isSuperAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			reviewableRequests = append(reviewableRequests, request)
+		}
+	}
+	return impl.getAccessRequestDtos(reviewableRequests)
+}
+
+func (impl *UserAccessGrantServiceImpl) ReviewAccessRequest(review *bean.AccessRequestReviewDto, token string, managerAuth func(resource, token, object string) bool) (*bean.AccessRequestDto, error) {
+	request, err := impl.userAccessGrantRepository.FindRequestById(review.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching access request", "err", err, "id", review.Id)
+		return nil, err
+	}
+	if request.Status != repository2.ACCESS_REQUEST_STATUS_PENDING {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("access request is already %s", strings.ToLower(string(request.Status)))}
+	}
+	if request.UserId == review.ReviewedBy {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "own access request can not be reviewed"}
+	}
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(review.ReviewedBy))
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := impl.hasRequestGrantAuth(request, token, managerAuth, isSuperAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "unauthorized to review the access request"}
+	}
+	if !review.Approve {
+		err = impl.updateRequestStatus(request, repository2.ACCESS_REQUEST_STATUS_REJECTED, repository2.ACCESS_AUDIT_ACTION_REJECTED, review.Comment, review.ReviewedBy)
+		if err != nil {
+			return nil, err
+		}
+		return impl.getAccessRequestDto(request)
+	}
+
+	if review.ExpiresOn != nil {
+		if review.ExpiresOn.After(request.ExpiresOn) {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "access can not be approved beyond the expiry requested"}
+		}
+		request.ExpiresOn = *review.ExpiresOn
+	}
+	startsOn := request.StartsOn
+	if startsOn.Before(time.Now()) {
+		startsOn = time.Now()
+	}
+	if !request.ExpiresOn.After(startsOn) {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "window of the access request has already passed"}
+	}
+	var roles []*repository2.RoleModel
+	var roleGroups []*repository2.RoleGroup
+	var rolePolicies []casbin2.Policy
+	if request.RoleFilter != nil {
+		filterRoles, policies, err := impl.resolveRoles(*request.RoleFilter, review.ReviewedBy)
+		if err != nil {
+			return nil, err
+		}
+		roles = filterRoles
+		rolePolicies = policies
+	} else {
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(request.RoleGroupId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching role group", "err", err, "roleGroupId", request.RoleGroupId)
+			return nil, err
+		}
+		roleGroups = append(roleGroups, roleGroup)
+	}
+	// status and grants of the request are saved together, so an approved request always has its grants
+	tx, err := impl.userAccessGrantRepository.GetConnection().Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = impl.saveRequestStatus(request, repository2.ACCESS_REQUEST_STATUS_APPROVED, repository2.ACCESS_AUDIT_ACTION_APPROVED, review.Comment, review.ReviewedBy, tx)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := impl.saveGrants(request.UserId, roles, roleGroups, startsOn, request.ExpiresOn, request.Reason, request.Id, review.ReviewedBy, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.commitWithPolicies(tx, rolePolicies)
+	if err != nil {
+		return nil, err
+	}
+	impl.startGrants(grants)
+	return impl.getAccessRequestDto(request)
+}
+
+func (impl *UserAccessGrantServiceImpl) updateRequestStatus(request *repository2.UserAccessRequest, status repository2.AccessRequestStatus,
+	action repository2.AccessAuditAction, comment string, userId int32) error {
+	tx, err := impl.userAccessGrantRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = impl.saveRequestStatus(request, status, action, comment, userId, tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *UserAccessGrantServiceImpl) saveRequestStatus(request *repository2.UserAccessRequest, status repository2.AccessRequestStatus,
+	action repository2.AccessAuditAction, comment string, userId int32, tx *pg.Tx) error {
+	request.Status = status
+	request.UpdatedOn = time.Now()
+	request.UpdatedBy = userId
+	if status != repository2.ACCESS_REQUEST_STATUS_CANCELLED {
+		request.ReviewedBy = userId
+		request.ReviewedOn = time.Now()
+		request.ReviewComment = comment
+	}
+	err := impl.userAccessGrantRepository.UpdateRequest(request, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating access request", "err", err, "id", request.Id, "status", status)
+		return err
+	}
+	audit := &repository2.UserAccessAudit{
+		UserId:          request.UserId,
+		AccessRequestId: request.Id,
+		Action:          action,
+		Details:         comment,
+		PerformedBy:     userId,
+		PerformedOn:     time.Now(),
+	}
+	err = impl.userAccessGrantRepository.SaveAudit([]*repository2.UserAccessAudit{audit}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving access audit", "err", err, "id", request.Id)
+		return err
+	}
+	return nil
+}
+
+func (impl *UserAccessGrantServiceImpl) ProcessDueGrants() {
+	grants, err := impl.userAccessGrantRepository.FindDueGrants(time.Now())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching due access grants", "err", err)
+		return
+	}
+	for _, grant := range grants {
+		if !grant.ExpiresOn.After(time.Now()) {
+			err = impl.endGrant(grant, repository2.ACCESS_GRANT_STATUS_EXPIRED, 1)
+		} else {
+			err = impl.activateGrant(grant)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in processing access grant", "err", err, "grantId", grant.Id, "status", grant.Status)
+		}
+	}
+}
+
+// activateGrant maps the user to the role or group of the grant. The grant owns the mapping only when the user did not
+// already have it, so that access given otherwise is not taken away on expiry. The grant is claimed in the tx, so that
+// it is activated once when due grants are processed on more than one instance
+func (impl *UserAccessGrantServiceImpl) activateGrant(grant *repository2.UserAccessGrant) error {
+	user, err := impl.userRepository.GetById(grant.UserId)
+	if util.IsErrNoRows(err) {
+		// user deleted before the grant started
+		return impl.endGrant(grant, repository2.ACCESS_GRANT_STATUS_EXPIRED, 1)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", grant.UserId)
+		return err
+	}
+	var roleGroup *repository2.RoleGroup
+	if grant.RoleId == 0 {
+		roleGroup, err = impl.getRoleGroupIncludingInactive(grant.RoleGroupId)
+		if err != nil {
+			return err
+		}
+		if !roleGroup.Active {
+			// group deleted before the grant started
+			return impl.endGrant(grant, repository2.ACCESS_GRANT_STATUS_EXPIRED, 1)
+		}
+	}
+	tx, err := impl.userAccessGrantRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	claimed, err := impl.userAccessGrantRepository.ClaimGrant(grant, []repository2.AccessGrantStatus{repository2.ACCESS_GRANT_STATUS_SCHEDULED}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in claiming access grant", "err", err, "grantId", grant.Id)
+		return err
+	}
+	if !claimed {
+		// started or ended elsewhere
+		return nil
+	}
+	var policy casbin2.Policy
+	mappingOwned := false
+	if grant.RoleId > 0 {
+		role, err := impl.userAuthRepository.GetRoleById(grant.RoleId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching role", "err", err, "roleId", grant.RoleId)
+			return err
+		}
+		userRoleModel, err := impl.getUserRoleMapping(user.Id, role.Id)
+		if err != nil {
+			return err
+		}
+		if userRoleModel == nil {
+			mappingOwned = true
+			userRoleModel = &repository2.UserRoleModel{
+				UserId:   user.Id,
+				RoleId:   role.Id,
+				AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: grant.CreatedBy, UpdatedOn: time.Now(), UpdatedBy: grant.CreatedBy},
+			}
+			_, err = impl.userAuthRepository.CreateUserRoleMapping(userRoleModel, tx)
+			if err != nil {
+				impl.logger.Errorw("error in creating user role mapping", "err", err, "userId", user.Id, "roleId", role.Id)
+				return err
+			}
+		}
+		policy = casbin2.Policy{Type: "g", Sub: casbin2.Subject(user.EmailId), Obj: casbin2.Object(role.Role)}
+	} else {
+		casbinRoles, err := casbin2.GetRolesForUser(user.EmailId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching casbin roles of user", "err", err, "userId", user.Id)
+			return err
+		}
+		mappingOwned = !containsArr(casbinRoles, strings.ToLower(roleGroup.CasbinName))
+		policy = casbin2.Policy{Type: "g", Sub: casbin2.Subject(user.EmailId), Obj: casbin2.Object(roleGroup.CasbinName)}
+	}
+	grant.Status = repository2.ACCESS_GRANT_STATUS_ACTIVE
+	grant.MappingOwned = mappingOwned
+	grant.UpdatedOn = time.Now()
+	err = impl.userAccessGrantRepository.UpdateGrant(grant, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "err", err, "grantId", grant.Id)
+		return err
+	}
+	err = impl.userAccessGrantRepository.SaveAudit([]*repository2.UserAccessAudit{newAccessAudit(grant, repository2.ACCESS_AUDIT_ACTION_ACTIVATED, string(policy.Obj), 1)}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving access audit", "err", err, "grantId", grant.Id)
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if mappingOwned {
+		casbin2.AddPolicy([]casbin2.Policy{policy})
+	}
+	impl.enforcer.InvalidateCache(strings.ToLower(user.EmailId))
+	return nil
+}
+
+// endGrant expires or revokes the grant. The mapping owned by the grant is removed unless another active grant of the
+// user has the same role or group, that grant then takes over the mapping
+func (impl *UserAccessGrantServiceImpl) endGrant(grant *repository2.UserAccessGrant, status repository2.AccessGrantStatus, userId int32) error {
+	user, err := impl.userRepository.GetByIdIncludeDeleted(grant.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", grant.UserId)
+		return err
+	}
+	tx, err := impl.userAccessGrantRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	claimed, err := impl.userAccessGrantRepository.ClaimGrant(grant, []repository2.AccessGrantStatus{repository2.ACCESS_GRANT_STATUS_SCHEDULED, repository2.ACCESS_GRANT_STATUS_ACTIVE}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in claiming access grant", "err", err, "grantId", grant.Id)
+		return err
+	}
+	if !claimed {
+		if status == repository2.ACCESS_GRANT_STATUS_REVOKED {
+			return &util.ApiError{HttpStatusCode: http.StatusConflict, UserMessage: "access grant has already ended or is being updated"}
+		}
+		// ended elsewhere
+		return nil
+	}
+	var policies []casbin2.Policy
+	details := ""
+	if grant.Status == repository2.ACCESS_GRANT_STATUS_ACTIVE && grant.MappingOwned {
+		otherGrants, err := impl.userAccessGrantRepository.FindActiveGrantsForMapping(grant.UserId, grant.RoleId, grant.RoleGroupId, grant.Id)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching active access grants", "err", err, "grantId", grant.Id)
+			return err
+		}
+		// the mapping is handed over to another active grant which is not being ended itself
+		var successor *repository2.UserAccessGrant
+		for _, otherGrant := range otherGrants {
+			claimed, err = impl.userAccessGrantRepository.ClaimGrant(otherGrant, []repository2.AccessGrantStatus{repository2.ACCESS_GRANT_STATUS_ACTIVE}, tx)
+			if err != nil {
+				impl.logger.Errorw("error in claiming access grant", "err", err, "grantId", otherGrant.Id)
+				return err
+			}
+			if claimed {
+				successor = otherGrant
+				break
+			}
+		}
+		if successor != nil {
+			err = impl.userAccessGrantRepository.UpdateMappingOwned(successor.Id, true, tx)
+			if err != nil {
+				impl.logger.Errorw("error in updating access grant", "err", err, "grantId", successor.Id)
+				return err
+			}
+			details = fmt.Sprintf("access retained by grant %d", successor.Id)
+		} else if grant.RoleId > 0 {
+			role, err := impl.userAuthRepository.GetRoleById(grant.RoleId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching role", "err", err, "roleId", grant.RoleId)
+				return err
+			}
+			userRoleModel, err := impl.getUserRoleMapping(user.Id, role.Id)
+			if err != nil {
+				return err
+			}
+			if userRoleModel != nil {
+				_, err = impl.userAuthRepository.DeleteUserRoleMapping(userRoleModel, tx)
+				if err != nil {
+					impl.logger.Errorw("error in deleting user role mapping", "err", err, "userId", user.Id, "roleId", role.Id)
+					return err
+				}
+			}
+			policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(user.EmailId), Obj: casbin2.Object(role.Role)})
+			details = role.Role
+		} else {
+			roleGroup, err := impl.getRoleGroupIncludingInactive(grant.RoleGroupId)
+			if err != nil {
+				return err
+			}
+			policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(user.EmailId), Obj: casbin2.Object(roleGroup.CasbinName)})
+			details = roleGroup.CasbinName
+		}
+	}
+	grant.Status = status
+	grant.UpdatedOn = time.Now()
+	grant.UpdatedBy = userId
+	if status == repository2.ACCESS_GRANT_STATUS_REVOKED {
+		grant.RevokedBy = userId
+		grant.RevokedOn = time.Now()
+	}
+	err = impl.userAccessGrantRepository.UpdateGrant(grant, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "err", err, "grantId", grant.Id)
+		return err
+	}
+	action := repository2.ACCESS_AUDIT_ACTION_EXPIRED
+	if status == repository2.ACCESS_GRANT_STATUS_REVOKED {
+		action = repository2.ACCESS_AUDIT_ACTION_REVOKED
+	}
+	err = impl.userAccessGrantRepository.SaveAudit([]*repository2.UserAccessAudit{newAccessAudit(grant, action, details, userId)}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving access audit", "err", err, "grantId", grant.Id)
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if len(policies) > 0 {
+		casbin2.RemovePolicy(policies)
+	}
+	impl.enforcer.InvalidateCache(strings.ToLower(user.EmailId))
+	return nil
+}
+
+func (impl *UserAccessGrantServiceImpl) GetAuditTrail(userId int32, offset int, size int) ([]*bean.AccessAuditDto, error) {
+	audits, err := impl.userAccessGrantRepository.FindAuditByUserId(userId, offset, size)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching access audit", "err", err, "userId", userId)
+		return nil, err
+	}
+	auditDtos := make([]*bean.AccessAuditDto, 0, len(audits))
+	for _, audit := range audits {
+		auditDtos = append(auditDtos, &bean.AccessAuditDto{
+			Id:              audit.Id,
+			UserId:          audit.UserId,
+			AccessGrantId:   audit.AccessGrantId,
+			AccessRequestId: audit.AccessRequestId,
+			Action:          string(audit.Action),
+			Details:         audit.Details,
+			PerformedBy:     audit.PerformedBy,
+			PerformedOn:     audit.PerformedOn,
+		})
+	}
+	return auditDtos, nil
+}
+
+// resolveRoles returns the roles of the role filter creating the ones not present, along with the policies of the
+// roles created
+func (impl *UserAccessGrantServiceImpl) resolveRoles(roleFilter bean.RoleFilter, userId int32) ([]*repository2.RoleModel, []casbin2.Policy, error) {
+	var roles []*repository2.RoleModel
+	var policies []casbin2.Policy
+	roleFilter = impl.userCommonService.ReplacePlaceHolderForEmptyEntriesInRoleFilter(roleFilter)
+	resolveRole := func(team, entityName, environment, cluster, namespace, group, kind, resource string) error {
+		entityName = impl.userCommonService.RemovePlaceHolderInRoleFilterField(entityName)
+		environment = impl.userCommonService.RemovePlaceHolderInRoleFilterField(environment)
+		namespace = impl.userCommonService.RemovePlaceHolderInRoleFilterField(namespace)
+		group = impl.userCommonService.RemovePlaceHolderInRoleFilterField(group)
+		kind = impl.userCommonService.RemovePlaceHolderInRoleFilterField(kind)
+		resource = impl.userCommonService.RemovePlaceHolderInRoleFilterField(resource)
+		actionType := roleFilter.Action
+		if roleFilter.Entity == bean2.CLUSTER {
+			// role of cluster entity is looked up by the action alone
+			actionType = ""
+		}
+		roleModel, err := impl.userAuthRepository.GetRoleByFilterForAllTypes(roleFilter.Entity, team, entityName, environment, actionType, roleFilter.AccessType, cluster, namespace, group, kind, resource, roleFilter.Action, false)
+		if err != nil {
+			impl.logger.Errorw("error in getting role by filter", "err", err, "roleFilter", roleFilter)
+			return err
+		}
+		if roleModel.Id == 0 {
+			flag, err, policiesAdded := impl.userCommonService.CreateDefaultPoliciesForAllTypes(team, entityName, environment, roleFilter.Entity, cluster, namespace, group, kind, resource, roleFilter.Action, roleFilter.AccessType, userId)
+			if err != nil || !flag {
+				impl.logger.Errorw("error in creating default policies", "err", err, "roleFilter", roleFilter)
+				return err
+			}
+			policies = append(policies, policiesAdded...)
+			roleModel, err = impl.userAuthRepository.GetRoleByFilterForAllTypes(roleFilter.Entity, team, entityName, environment, actionType, roleFilter.AccessType, cluster, namespace, group, kind, resource, roleFilter.Action, false)
+			if err != nil {
+				return err
+			}
+		}
+		if roleModel.Id > 0 {
+			role := roleModel
+			roles = append(roles, &role)
+		}
+		return nil
+	}
+	var err error
+	if roleFilter.Entity == bean2.CLUSTER {
+		for _, namespace := range strings.Split(roleFilter.Namespace, ",") {
+			for _, group := range strings.Split(roleFilter.Group, ",") {
+				for _, kind := range strings.Split(roleFilter.Kind, ",") {
+					for _, resource := range strings.Split(roleFilter.Resource, ",") {
+						if err = resolveRole("", "", "", roleFilter.Cluster, namespace, group, kind, resource); err != nil {
+							return nil, nil, err
+						}
+					}
+				}
+			}
+		}
+	} else {
+		for _, environment := range strings.Split(roleFilter.Environment, ",") {
+			for _, entityName := range strings.Split(roleFilter.EntityName, ",") {
+				if err = resolveRole(roleFilter.Team, entityName, environment, "", "", "", "", ""); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+	if len(roles) == 0 {
+		return nil, nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: bean2.RoleNotFoundStatusPrefix + getRoleFilterDescription(roleFilter)}
+	}
+	return roles, policies, nil
+}
+
+// hasRoleFilterGrantAuth checks the user has the access needed to give the roles of the role filter to other users
+func (impl *UserAccessGrantServiceImpl) hasRoleFilterGrantAuth(roleFilter bean.RoleFilter, token string, managerAuth func(resource, token, object string) bool, isSuperAdmin bool) bool {
+	if isSuperAdmin {
+		return true
+	}
+	if roleFilter.AccessType == bean.APP_ACCESS_TYPE_HELM {
+		return false
+	}
+	if roleFilter.Entity == bean.CLUSTER_ENTITIY {
+		return impl.userCommonService.CheckRbacForClusterEntity(roleFilter.Cluster, roleFilter.Namespace, roleFilter.Group, roleFilter.Kind, roleFilter.Resource, token, managerAuth)
+	}
+	if len(roleFilter.Team) > 0 {
+		return managerAuth(casbin2.ResourceUser, token, strings.ToLower(roleFilter.Team))
+	}
+	return managerAuth(casbin2.ResourceUser, token, "*")
+}
+
+func (impl *UserAccessGrantServiceImpl) hasGroupGrantAuth(roleGroup *repository2.RoleGroup, token string, managerAuth func(resource, token, object string) bool, isSuperAdmin bool) bool {
+	if isSuperAdmin {
+		return true
+	}
+	roles, err := impl.roleGroupRepository.GetRolesByGroupCasbinName(roleGroup.CasbinName)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching roles of group", "err", err, "group", roleGroup.Name)
+		return false
+	}
+	for _, role := range roles {
+		if !impl.hasRoleFilterGrantAuth(*getRoleFilterForRole(role), token, managerAuth, isSuperAdmin) {
+			return false
+		}
+	}
+	return true
+}
+
+func (impl *UserAccessGrantServiceImpl) hasGrantAuth(roleId int, roleGroupId int32, token string, managerAuth func(resource, token, object string) bool, isSuperAdmin bool) (bool, error) {
+	if roleId > 0 {
+		role, err := impl.userAuthRepository.GetRoleById(roleId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching role", "err", err, "roleId", roleId)
+			return false, err
+		}
+		return impl.hasRoleFilterGrantAuth(*getRoleFilterForRole(role), token, managerAuth, isSuperAdmin), nil
+	}
+	roleGroup, err := impl.getRoleGroupIncludingInactive(roleGroupId)
+	if err != nil {
+		return false, err
+	}
+	return impl.hasGroupGrantAuth(roleGroup, token, managerAuth, isSuperAdmin), nil
+}
+
+func (impl *UserAccessGrantServiceImpl) hasRequestGrantAuth(request *repository2.UserAccessRequest, token string, managerAuth func(resource, token, object string) bool, isSuperAdmin bool) (bool, error) {
+	if request.RoleFilter != nil {
+		return impl.hasRoleFilterGrantAuth(*request.RoleFilter, token, managerAuth, isSuperAdmin), nil
+	}
+	return impl.hasGrantAuth(0, request.RoleGroupId, token, managerAuth, isSuperAdmin)
+}
+
+func (impl *UserAccessGrantServiceImpl) getUserRoleMapping(userId int32, roleId int) (*repository2.UserRoleModel, error) {
+	userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingByUserId(userId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching user role mappings", "err", err, "userId", userId)
+		return nil, err
+	}
+	for _, userRoleModel := range userRoleModels {
+		if userRoleModel.RoleId == roleId {
+			return userRoleModel, nil
+		}
+	}
+	return nil, nil
+}
+
+// getRoleGroupIncludingInactive fetches the group of a grant, the group may have been deleted since
+func (impl *UserAccessGrantServiceImpl) getRoleGroupIncludingInactive(roleGroupId int32) (*repository2.RoleGroup, error) {
+	roleGroup := &repository2.RoleGroup{}
+	err := impl.roleGroupRepository.GetConnection().Model(roleGroup).Where("id = ?", roleGroupId).Select()
+	if err != nil {
+		impl.logger.Errorw("error in fetching role group", "err", err, "roleGroupId", roleGroupId)
+		return nil, err
+	}
+	return roleGroup, nil
+}
+
+func (impl *UserAccessGrantServiceImpl) getAccessRequestDtos(requests []*repository2.UserAccessRequest) ([]*bean.AccessRequestDto, error) {
+	requestDtos := make([]*bean.AccessRequestDto, 0, len(requests))
+	for _, request := range requests {
+		requestDto, err := impl.getAccessRequestDto(request)
+		if err != nil {
+			return nil, err
+		}
+		requestDtos = append(requestDtos, requestDto)
+	}
+	return requestDtos, nil
+}
+
+func (impl *UserAccessGrantServiceImpl) getAccessRequestDto(request *repository2.UserAccessRequest) (*bean.AccessRequestDto, error) {
+	startsOn := request.StartsOn
+	requestDto := &bean.AccessRequestDto{
+		Id:            request.Id,
+		UserId:        request.UserId,
+		RoleFilter:    request.RoleFilter,
+		Reason:        request.Reason,
+		StartsOn:      &startsOn,
+		ExpiresOn:     request.ExpiresOn,
+		Status:        string(request.Status),
+		ReviewedBy:    request.ReviewedBy,
+		ReviewComment: request.ReviewComment,
+		RequestedOn:   request.CreatedOn,
+	}
+	if !request.ReviewedOn.IsZero() {
+		reviewedOn := request.ReviewedOn
+		requestDto.ReviewedOn = &reviewedOn
+	}
+	user, err := impl.userRepository.GetByIdIncludeDeleted(request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", request.UserId)
+		return nil, err
+	}
+	requestDto.EmailId = user.EmailId
+	if request.RoleGroupId > 0 {
+		roleGroup, err := impl.getRoleGroupIncludingInactive(request.RoleGroupId)
+		if err != nil {
+			return nil, err
+		}
+		requestDto.Group = roleGroup.Name
+	}
+	return requestDto, nil
+}
+
+// getAccessWindowStart returns the start of the window, now when not given or past
+func getAccessWindowStart(startsOn *time.Time, expiresOn time.Time) (time.Time, error) {
+	start := time.Now()
+	if startsOn != nil && startsOn.After(start) {
+		start = *startsOn
+	}
+	if !expiresOn.After(start) {
+		return start, &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "expiry of access is to be after its start"}
+	}
+	return start, nil
+}
+
+func getRoleFilterForRole(role *repository2.RoleModel) *bean.RoleFilter {
+	return &bean.RoleFilter{
+		Entity:      role.Entity,
+		Team:        role.Team,
+		EntityName:  role.EntityName,
+		Environment: role.Environment,
+		Action:      role.Action,
+		AccessType:  role.AccessType,
+		Cluster:     role.Cluster,
+		Namespace:   role.Namespace,
+		Group:       role.Group,
+		Kind:        role.Kind,
+		Resource:    role.Resource,
+	}
+}
+
+// getRoleFilterDescription describes the access of a role filter for the audit trail, like trigger on team/app/env
+func getRoleFilterDescription(roleFilter bean.RoleFilter) string {
+	if roleFilter.Entity == bean.CLUSTER_ENTITIY {
+		return fmt.Sprintf("%s on %s/%s/%s/%s/%s", roleFilter.Action, roleFilter.Cluster, roleFilter.Namespace, roleFilter.Group, roleFilter.Kind, roleFilter.Resource)
+	}
+	return fmt.Sprintf("%s on %s/%s/%s", roleFilter.Action, roleFilter.Team, roleFilter.EntityName, roleFilter.Environment)
+}
+
+func newAccessAudit(grant *repository2.UserAccessGrant, action repository2.AccessAuditAction, details string, userId int32) *repository2.UserAccessAudit {
+	return &repository2.UserAccessAudit{
+		UserId:          grant.UserId,
+		AccessGrantId:   grant.Id,
+		AccessRequestId: grant.AccessRequestId,
+		Action:          action,
+		Details:         details,
+		PerformedBy:     userId,
+		PerformedOn:     time.Now(),
+	}
+}
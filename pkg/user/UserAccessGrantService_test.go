@@ -0,0 +1,52 @@
+package user
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetAccessWindowStart(t *testing.T) {
+	now := time.Now()
+	expiresOn := now.Add(2 * time.Hour)
+
+	// start not given or in past is now
+	start, err := getAccessWindowStart(nil, expiresOn)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, now, start, time.Minute)
+	past := now.Add(-time.Hour)
+	start, err = getAccessWindowStart(&past, expiresOn)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, now, start, time.Minute)
+
+	future := now.Add(time.Hour)
+	start, err = getAccessWindowStart(&future, expiresOn)
+	assert.Nil(t, err)
+	assert.Equal(t, future, start)
+
+	// expiry is to be after the start
+	later := now.Add(3 * time.Hour)
+	_, err = getAccessWindowStart(&later, expiresOn)
+	assert.NotNil(t, err)
+	_, err = getAccessWindowStart(nil, now.Add(-time.Minute))
+	assert.NotNil(t, err)
+}
+
+func TestGetRoleFilterDescription(t *testing.T) {
+	assert.Equal(t, "trigger on payments/payment-api/prod", getRoleFilterDescription(bean.RoleFilter{
+		Team: "payments", EntityName: "payment-api", Environment: "prod", Action: "trigger"}))
+	assert.Equal(t, "exec on prod-cluster/payments///", getRoleFilterDescription(bean.RoleFilter{
+		Entity: bean.CLUSTER_ENTITIY, Cluster: "prod-cluster", Namespace: "payments", Action: "exec"}))
+}
+
+func TestExcludePolicies(t *testing.T) {
+	view := casbin2.Policy{Type: "p", Sub: "role:view", Res: "applications", Act: "get", Obj: "a/b"}
+	admin := casbin2.Policy{Type: "p", Sub: "role:admin", Res: "applications", Act: "*", Obj: "a/b"}
+
+	// only the policies which were not excluded are kept
+	assert.Equal(t, []casbin2.Policy{admin}, excludePolicies([]casbin2.Policy{view, admin}, []casbin2.Policy{view}))
+	assert.Equal(t, []casbin2.Policy{view, admin}, excludePolicies([]casbin2.Policy{view, admin}, nil))
+	assert.Nil(t, excludePolicies([]casbin2.Policy{view}, []casbin2.Policy{view}))
+}
@@ -397,6 +397,8 @@ func WhitelistChecker(url string) bool {
 		"/orchestrator/auth/login",
 		"/dashboard",
 		"/orchestrator/webhook/git",
+		// SCIM clients send a bearer token, verified by the scim handler
+		"/orchestrator/scim/v2",
 	}
 	for _, a := range prefixUrls {
 		if strings.Contains(url, a) {
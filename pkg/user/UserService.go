@@ -71,30 +71,33 @@ type UserServiceImpl struct {
 	userReqLock sync.RWMutex
 	//map of userId and current lock-state of their serving ability;
 	//if TRUE then it means that some request is ongoing & unable to serve and FALSE then it is open to serve
-	userReqState        map[int32]bool
-	userAuthRepository  repository2.UserAuthRepository
-	logger              *zap.SugaredLogger
-	userRepository      repository2.UserRepository
-	roleGroupRepository repository2.RoleGroupRepository
-	sessionManager2     *middleware.SessionManager
-	userCommonService   UserCommonService
-	userAuditService    UserAuditService
+	userReqState              map[int32]bool
+	userAuthRepository        repository2.UserAuthRepository
+	logger                    *zap.SugaredLogger
+	userRepository            repository2.UserRepository
+	roleGroupRepository       repository2.RoleGroupRepository
+	sessionManager2           *middleware.SessionManager
+	userCommonService         UserCommonService
+	userAuditService          UserAuditService
+	userAccessGrantRepository repository2.UserAccessGrantRepository
 }
 
 func NewUserServiceImpl(userAuthRepository repository2.UserAuthRepository,
 	logger *zap.SugaredLogger,
 	userRepository repository2.UserRepository,
 	userGroupRepository repository2.RoleGroupRepository,
-	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService) *UserServiceImpl {
+	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService,
+	userAccessGrantRepository repository2.UserAccessGrantRepository) *UserServiceImpl {
 	serviceImpl := &UserServiceImpl{
-		userReqState:        make(map[int32]bool),
-		userAuthRepository:  userAuthRepository,
-		logger:              logger,
-		userRepository:      userRepository,
-		roleGroupRepository: userGroupRepository,
-		sessionManager2:     sessionManager2,
-		userCommonService:   userCommonService,
-		userAuditService:    userAuditService,
+		userReqState:              make(map[int32]bool),
+		userAuthRepository:        userAuthRepository,
+		logger:                    logger,
+		userRepository:            userRepository,
+		roleGroupRepository:       userGroupRepository,
+		sessionManager2:           sessionManager2,
+		userCommonService:         userCommonService,
+		userAuditService:          userAuditService,
+		userAccessGrantRepository: userAccessGrantRepository,
 	}
 	cStore = sessions.NewCookieStore(randKey())
 	return serviceImpl
@@ -676,7 +679,7 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 		}
 	}
 	//validating if action user is not admin and trying to update user who has super admin polices, return 403
-	isUserSuperAdmin, err := impl.IsSuperAdmin(int(userInfo.Id))
+	isUserSuperAdmin, err := impl.hasSuperAdminRole(userInfo.Id)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
@@ -726,10 +729,18 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 		if err != nil {
 			return nil, false, false, nil, err
 		}
+		roleGrants, groupGrants, err := impl.getGrantOwnedAccess(model.Id)
+		if err != nil {
+			return nil, false, false, nil, err
+		}
 		existingRoleIds := make(map[int]repository2.UserRoleModel)
 		eliminatedRoleIds := make(map[int]*repository2.UserRoleModel)
 		for i := range userRoleModels {
 			existingRoleIds[userRoleModels[i].RoleId] = *userRoleModels[i]
+			if _, ok := roleGrants[userRoleModels[i].RoleId]; ok {
+				// removed by the access grant on its expiry
+				continue
+			}
 			eliminatedRoleIds[userRoleModels[i].RoleId] = userRoleModels[i]
 		}
 
@@ -763,6 +774,20 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 			rolesChanged = rolesChangedFromRoleUpdate
 
 		}
+		// roles of access grants given in the request are made permanent, the grants no longer remove them on expiry
+		for roleId, grant := range roleGrants {
+			role, err := impl.userAuthRepository.GetRoleById(roleId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching role", "err", err, "roleId", roleId)
+				return nil, false, false, nil, err
+			}
+			if containsPolicy(addedPolicies, userInfo.EmailId, role.Role) {
+				err = impl.releaseGrantMapping(grant, tx)
+				if err != nil {
+					return nil, false, false, nil, err
+				}
+			}
+		}
 
 		//ROLE GROUP SETUP
 		newGroupMap := make(map[string]string)
@@ -782,6 +807,12 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 				return nil, false, false, nil, err
 			}
 			newGroupMap[userGroup.CasbinName] = userGroup.CasbinName
+			if grant, ok := groupGrants[strings.ToLower(userGroup.CasbinName)]; ok {
+				err = impl.releaseGrantMapping(grant, tx)
+				if err != nil {
+					return nil, false, false, nil, err
+				}
+			}
 			if _, ok := oldGroupMap[userGroup.CasbinName]; !ok {
 				//check permission for new group which is going to add
 				hasAccessToGroup := impl.checkGroupAuth(userGroup.CasbinName, token, managerAuth, isActionPerformingUserSuperAdmin)
@@ -797,6 +828,10 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 
 		for _, item := range userCasbinRoles {
 			if _, ok := newGroupMap[item]; !ok {
+				if _, ok := groupGrants[strings.ToLower(item)]; ok {
+					// removed by the access grant on its expiry
+					continue
+				}
 				if item != bean.SUPERADMIN {
 					//check permission for group which is going to eliminate
 					hasAccessToGroup := impl.checkGroupAuth(item, token, managerAuth, isActionPerformingUserSuperAdmin)
@@ -869,6 +904,62 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 	return userInfo, rolesChanged, groupsModified, restrictedGroups, nil
 }
 
+// getGrantOwnedAccess returns the active access grants owning a role or group mapping of the user, keyed by role id and
+// by lower cased group casbin name
+func (impl *UserServiceImpl) getGrantOwnedAccess(userId int32) (map[int]*repository2.UserAccessGrant, map[string]*repository2.UserAccessGrant, error) {
+	roleGrants := make(map[int]*repository2.UserAccessGrant)
+	groupGrants := make(map[string]*repository2.UserAccessGrant)
+	grants, err := impl.userAccessGrantRepository.FindActiveOwnedGrantsByUserId(userId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching access grants of user", "err", err, "userId", userId)
+		return nil, nil, err
+	}
+	for _, grant := range grants {
+		if grant.RoleId > 0 {
+			roleGrants[grant.RoleId] = grant
+			continue
+		}
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(grant.RoleGroupId)
+		if util.IsErrNoRows(err) {
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching role group", "err", err, "roleGroupId", grant.RoleGroupId)
+			return nil, nil, err
+		}
+		groupGrants[strings.ToLower(roleGroup.CasbinName)] = grant
+	}
+	return roleGrants, groupGrants, nil
+}
+
+// releaseGrantMapping hands the mapping of the grant over to the user, so that it is kept when the grant ends. The grant
+// is claimed first, a grant being ended at the same time fails the update
+func (impl *UserServiceImpl) releaseGrantMapping(grant *repository2.UserAccessGrant, tx *pg.Tx) error {
+	claimed, err := impl.userAccessGrantRepository.ClaimGrant(grant, []repository2.AccessGrantStatus{repository2.ACCESS_GRANT_STATUS_ACTIVE}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in claiming access grant", "err", err, "grantId", grant.Id)
+		return err
+	}
+	if !claimed {
+		return &util.ApiError{Code: "409", HttpStatusCode: http.StatusConflict, UserMessage: "an access grant of the user is being updated, please retry"}
+	}
+	err = impl.userAccessGrantRepository.UpdateMappingOwned(grant.Id, false, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating access grant", "err", err, "grantId", grant.Id)
+		return err
+	}
+	return nil
+}
+
+// containsPolicy checks the policies have the mapping of the user to the role
+func containsPolicy(policies []casbin2.Policy, emailId string, role string) bool {
+	for _, policy := range policies {
+		if policy.Type == "g" && strings.EqualFold(string(policy.Sub), emailId) && strings.EqualFold(string(policy.Obj), role) {
+			return true
+		}
+	}
+	return false
+}
+
 func (impl *UserServiceImpl) GetById(id int32) (*bean.UserInfo, error) {
 	model, err := impl.userRepository.GetById(id)
 	if err != nil {
@@ -902,10 +993,19 @@ func (impl *UserServiceImpl) getUserMetadata(model *repository2.UserModel) (bool
 		impl.logger.Debugw("No Roles Found for user", "id", model.Id)
 	}
 
+	// access of the user through access grants is listed with the grants
+	roleGrants, groupGrants, err := impl.getGrantOwnedAccess(model.Id)
+	if err != nil {
+		impl.logger.Warnw("error in fetching access grants of user", "id", model.Id, "err", err)
+	}
+
 	isSuperAdmin := false
 	var roleFilters []bean.RoleFilter
 	roleFilterMap := make(map[string]*bean.RoleFilter)
 	for _, role := range roles {
+		if _, ok := roleGrants[role.Id]; ok {
+			continue
+		}
 		key := ""
 		if len(role.Team) > 0 {
 			key = fmt.Sprintf("%s_%s_%s", role.Team, role.Action, role.AccessType)
@@ -914,7 +1014,7 @@ func (impl *UserServiceImpl) getUserMetadata(model *repository2.UserModel) (bool
 				key = fmt.Sprintf("%s_%s_%s_%s_%s_%s", role.Entity, role.Action, role.Cluster,
 					role.Namespace, role.Group, role.Kind)
 			} else {
-				key = fmt.Sprintf("%s_%s_%s", role.Entity, role.Action)
+				key = fmt.Sprintf("%s_%s", role.Entity, role.Action)
 			}
 		}
 		if _, ok := roleFilterMap[key]; ok {
@@ -989,6 +1089,9 @@ func (impl *UserServiceImpl) getUserMetadata(model *repository2.UserModel) (bool
 
 	var filterGroups []string
 	for _, item := range groups {
+		if _, ok := groupGrants[strings.ToLower(item)]; ok {
+			continue
+		}
 		if strings.Contains(item, "group:") {
 			filterGroups = append(filterGroups, item)
 		}
@@ -1304,7 +1407,16 @@ func (impl *UserServiceImpl) CheckUserRoles(id int32) ([]string, error) {
 		impl.logger.Errorw("No Roles Found for user", "id", model.Id)
 		return nil, err
 	}
-
+	// super admin of an api token narrowed down by scopes is limited to its scopes, same as on enforce
+	if !casbin2.IsWithinApiTokenScope(model.EmailId, "*", "*", "*") {
+		var scopedGroups []string
+		for _, group := range groups {
+			if group != bean.SUPERADMIN {
+				scopedGroups = append(scopedGroups, group)
+			}
+		}
+		groups = scopedGroups
+	}
 	return groups, nil
 }
 
@@ -1338,6 +1450,21 @@ func (impl *UserServiceImpl) SyncOrchestratorToCasbin() (bool, error) {
 	return true, nil
 }
 
+// hasSuperAdminRole tells whether the user is mapped to the super admin role, irrespective of the scopes of an api token
+func (impl *UserServiceImpl) hasSuperAdminRole(userId int32) (bool, error) {
+	model, err := impl.userRepository.GetByIdIncludeDeleted(userId)
+	if err != nil {
+		impl.logger.Errorw("error while fetching user from db", "error", err)
+		return false, err
+	}
+	roles, err := casbin2.GetRolesForUser(model.EmailId)
+	if err != nil {
+		impl.logger.Errorw("No Roles Found for user", "id", model.Id)
+		return false, err
+	}
+	return containsArr(roles, bean.SUPERADMIN), nil
+}
+
 func (impl *UserServiceImpl) IsSuperAdmin(userId int) (bool, error) {
 	//validating if action user is not admin and trying to update user who has super admin polices, return 403
 	isSuperAdmin := false
@@ -1434,7 +1561,7 @@ func (impl *UserServiceImpl) GetRoleFiltersByGroupNames(groupNames []string) ([]
 				key = fmt.Sprintf("%s_%s_%s_%s_%s_%s", role.Entity, role.Action, role.Cluster,
 					role.Namespace, role.Group, role.Kind)
 			} else {
-				key = fmt.Sprintf("%s_%s_%s", role.Entity, role.Action)
+				key = fmt.Sprintf("%s_%s", role.Entity, role.Action)
 			}
 		}
 		if _, ok := roleFilterMap[key]; ok {
@@ -0,0 +1,22 @@
+package casbin
+
+// ApiTokenScopeChecker tells whether a check is within the scopes of the subject, it is true for subjects which are not
+// api tokens and for api tokens without scopes
+type ApiTokenScopeChecker func(emailId string, resource string, action string, object string) bool
+
+// apiTokenScopeChecker is registered by the api token service, api tokens depend on users and so cannot be injected
+// into the enforcer
+var apiTokenScopeChecker ApiTokenScopeChecker
+
+func SetApiTokenScopeChecker(checker ApiTokenScopeChecker) {
+	apiTokenScopeChecker = checker
+}
+
+// IsWithinApiTokenScope is applied over the policies on every enforce, the roles of an api token user are narrowed down
+// to the scopes of the token
+func IsWithinApiTokenScope(emailId string, resource string, action string, object string) bool {
+	if apiTokenScopeChecker == nil {
+		return true
+	}
+	return apiTokenScopeChecker(emailId, resource, action, object)
+}
@@ -397,6 +397,9 @@ func (e *EnforcerImpl) enforcerEnforce(email string, resource string, action str
 		e.logger.Errorw("error occurred while enforcing safe", "email", email,
 			"resource", resource, "action", action, "resourceItem", resourceItem, "reason", err)
 	}
+	if response && !IsWithinApiTokenScope(email, resource, action, resourceItem) {
+		response = false
+	}
 	return response, err
 }
 
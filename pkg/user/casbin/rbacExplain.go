@@ -0,0 +1,179 @@
+package casbin
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxRoleHierarchyLevel is the depth up to which casbin's default role manager follows role links
+const maxRoleHierarchyLevel = 10
+
+const (
+	PolicyEffectAllow = "allow"
+	PolicyEffectDeny  = "deny"
+)
+
+// PolicyMatch is a policy line a subject has. Path is the chain of groups and roles through which the subject has the
+// line, outermost first and ending with Role, it is empty for lines defined on the subject itself
+type PolicyMatch struct {
+	Role     string
+	Path     []string
+	Resource string
+	Action   string
+	Object   string
+	Effect   string
+}
+
+type ExplainResult struct {
+	Allowed bool
+	// Matches are the lines matching the check, a deny line overrides the allow ones as per the policy effect
+	Matches []*PolicyMatch
+}
+
+// RbacSnapshot is a point in time copy of the policies and role links of the enforcer. Enforce only answers yes or no,
+// the snapshot is used to explain its decisions and for lookups across subjects
+type RbacSnapshot struct {
+	policiesBySubject map[string][][]string
+	rolesBySubject    map[string][]string
+	subjectsByRole    map[string][]string
+}
+
+func GetRbacSnapshot() *RbacSnapshot {
+	return NewRbacSnapshot(e.GetPolicy(), e.GetGroupingPolicy())
+}
+
+func NewRbacSnapshot(policies [][]string, groupingPolicies [][]string) *RbacSnapshot {
+	snapshot := &RbacSnapshot{
+		policiesBySubject: make(map[string][][]string),
+		rolesBySubject:    make(map[string][]string),
+		subjectsByRole:    make(map[string][]string),
+	}
+	for _, policy := range policies {
+		if len(policy) < 4 {
+			continue
+		}
+		snapshot.policiesBySubject[policy[0]] = append(snapshot.policiesBySubject[policy[0]], policy)
+	}
+	for _, groupingPolicy := range groupingPolicies {
+		if len(groupingPolicy) < 2 {
+			continue
+		}
+		snapshot.rolesBySubject[groupingPolicy[0]] = append(snapshot.rolesBySubject[groupingPolicy[0]], groupingPolicy[1])
+		snapshot.subjectsByRole[groupingPolicy[1]] = append(snapshot.subjectsByRole[groupingPolicy[1]], groupingPolicy[0])
+	}
+	return snapshot
+}
+
+// GetRolePaths returns the roles and groups the subject has directly or through other roles, along with the path to
+// each. The subject itself is included with an empty path
+func (s *RbacSnapshot) GetRolePaths(subject string) map[string][]string {
+	paths := map[string][]string{subject: {}}
+	queue := []string{subject}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if len(paths[current]) >= maxRoleHierarchyLevel {
+			continue
+		}
+		for _, role := range s.rolesBySubject[current] {
+			if _, ok := paths[role]; ok {
+				continue
+			}
+			path := make([]string, len(paths[current]), len(paths[current])+1)
+			copy(path, paths[current])
+			paths[role] = append(path, role)
+			queue = append(queue, role)
+		}
+	}
+	return paths
+}
+
+// GetPermissions returns all the policy lines the subject has
+func (s *RbacSnapshot) GetPermissions(subject string) []*PolicyMatch {
+	return s.getMatches(subject, func(policy []string) bool { return true })
+}
+
+// Explain evaluates the check the way Enforce does and returns the policy lines which decided it
+func (s *RbacSnapshot) Explain(subject string, resource string, action string, object string) *ExplainResult {
+	matches := s.getMatches(subject, func(policy []string) bool {
+		return MatchKeyByPart(resource, policy[1]) && MatchKeyByPart(action, policy[2]) && MatchKeyByPart(object, policy[3])
+	})
+	allowed := false
+	for _, match := range matches {
+		if match.Effect == PolicyEffectDeny {
+			return &ExplainResult{Allowed: false, Matches: matches}
+		}
+		allowed = true
+	}
+	return &ExplainResult{Allowed: allowed, Matches: matches}
+}
+
+// GetSubjectsAllowed returns the subjects allowed the check, subjects are the ones which are not roles of any other
+// subject, i.e. users and api tokens
+func (s *RbacSnapshot) GetSubjectsAllowed(resource string, action string, object string) map[string]*ExplainResult {
+	candidates := make(map[string]bool)
+	for subject, policies := range s.policiesBySubject {
+		for _, policy := range policies {
+			if getPolicyEffect(policy) == PolicyEffectAllow && MatchKeyByPart(resource, policy[1]) &&
+				MatchKeyByPart(action, policy[2]) && MatchKeyByPart(object, policy[3]) {
+				s.collectMembers(subject, candidates)
+				break
+			}
+		}
+	}
+	results := make(map[string]*ExplainResult)
+	for candidate := range candidates {
+		if len(s.subjectsByRole[candidate]) > 0 {
+			// a role or group having members
+			continue
+		}
+		result := s.Explain(candidate, resource, action, object)
+		if result.Allowed {
+			results[candidate] = result
+		}
+	}
+	return results
+}
+
+func (s *RbacSnapshot) collectMembers(role string, members map[string]bool) {
+	if members[role] {
+		return
+	}
+	members[role] = true
+	for _, member := range s.subjectsByRole[role] {
+		s.collectMembers(member, members)
+	}
+}
+
+func (s *RbacSnapshot) getMatches(subject string, matchFunc func(policy []string) bool) []*PolicyMatch {
+	paths := s.GetRolePaths(subject)
+	roles := make([]string, 0, len(paths))
+	for role := range paths {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	var matches []*PolicyMatch
+	for _, role := range roles {
+		for _, policy := range s.policiesBySubject[role] {
+			if !matchFunc(policy) {
+				continue
+			}
+			matches = append(matches, &PolicyMatch{
+				Role:     role,
+				Path:     paths[role],
+				Resource: policy[1],
+				Action:   policy[2],
+				Object:   policy[3],
+				Effect:   getPolicyEffect(policy),
+			})
+		}
+	}
+	return matches
+}
+
+func getPolicyEffect(policy []string) string {
+	if len(policy) > 4 && strings.ToLower(policy[4]) == PolicyEffectDeny {
+		return PolicyEffectDeny
+	}
+	return PolicyEffectAllow
+}
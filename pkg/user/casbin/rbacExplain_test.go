@@ -0,0 +1,52 @@
+package casbin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRbacSnapshot(t *testing.T) {
+	policies := [][]string{
+		{"role:trigger_payments_payment-api_prod", "applications", "trigger", "payments/payment-api", "allow"},
+		{"role:trigger_payments_payment-api_prod", "environment", "trigger", "prod/payment-api", "allow"},
+		{"role:view_payments_*_*", "applications", "get", "payments/*", "allow"},
+		{"role:deny_prod", "environment", "trigger", "prod/*", "deny"},
+		{"role:super-admin___", "*", "*", "*", "allow"},
+	}
+	groupingPolicies := [][]string{
+		{"alice@example.com", "role:trigger_payments_payment-api_prod"},
+		{"bob@example.com", "group:payments-viewers"},
+		{"group:payments-viewers", "role:view_payments_*_*"},
+		{"group:payments-viewers", "role:trigger_payments_payment-api_prod"},
+		{"carol@example.com", "group:payments-viewers"},
+		{"carol@example.com", "role:deny_prod"},
+		{"admin", "role:super-admin___"},
+	}
+	snapshot := NewRbacSnapshot(policies, groupingPolicies)
+
+	result := snapshot.Explain("alice@example.com", "applications", "trigger", "payments/payment-api")
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, len(result.Matches))
+	assert.Equal(t, []string{"role:trigger_payments_payment-api_prod"}, result.Matches[0].Path)
+
+	// through group
+	result = snapshot.Explain("bob@example.com", "applications", "get", "payments/ledger")
+	assert.True(t, result.Allowed)
+	assert.Equal(t, []string{"group:payments-viewers", "role:view_payments_*_*"}, result.Matches[0].Path)
+	assert.False(t, snapshot.Explain("bob@example.com", "applications", "get", "billing/ledger").Allowed)
+
+	// deny line overrides the allow one of the group
+	result = snapshot.Explain("carol@example.com", "environment", "trigger", "prod/payment-api")
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 2, len(result.Matches))
+
+	permissions := snapshot.GetPermissions("bob@example.com")
+	assert.Equal(t, 3, len(permissions))
+
+	subjects := snapshot.GetSubjectsAllowed("environment", "trigger", "prod/payment-api")
+	var allowed []string
+	for subject := range subjects {
+		allowed = append(allowed, subject)
+	}
+	assert.ElementsMatch(t, []string{"alice@example.com", "bob@example.com", "admin"}, allowed)
+}
@@ -364,6 +364,29 @@ func (_m *RoleGroupRepository) GetRolesByGroupNames(groupNames []string) ([]*rep
 	return r0, r1
 }
 
+// GetRolesByGroupNamesAndEntity provides a mock function with given fields: groupNames, entity
+func (_m *RoleGroupRepository) GetRolesByGroupNamesAndEntity(groupNames []string, entity string) ([]*repository.RoleModel, error) {
+	ret := _m.Called(groupNames, entity)
+
+	var r0 []*repository.RoleModel
+	if rf, ok := ret.Get(0).(func([]string, string) []*repository.RoleModel); ok {
+		r0 = rf(groupNames, entity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.RoleModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, string) error); ok {
+		r1 = rf(groupNames, entity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateRoleGroup provides a mock function with given fields: model, tx
 func (_m *RoleGroupRepository) UpdateRoleGroup(model *repository.RoleGroup, tx *pg.Tx) (*repository.RoleGroup, error) {
 	ret := _m.Called(model, tx)
@@ -387,6 +410,29 @@ func (_m *RoleGroupRepository) UpdateRoleGroup(model *repository.RoleGroup, tx *
 	return r0, r1
 }
 
+// UpdateRoleGroupIdForRoleGroupMappings provides a mock function with given fields: roleId, newRoleId
+func (_m *RoleGroupRepository) UpdateRoleGroupIdForRoleGroupMappings(roleId int, newRoleId int) (*repository.RoleGroupRoleMapping, error) {
+	ret := _m.Called(roleId, newRoleId)
+
+	var r0 *repository.RoleGroupRoleMapping
+	if rf, ok := ret.Get(0).(func(int, int) *repository.RoleGroupRoleMapping); ok {
+		r0 = rf(roleId, newRoleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.RoleGroupRoleMapping)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(roleId, newRoleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewRoleGroupRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -3,6 +3,7 @@
 package repomock
 
 import (
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
 	pg "github.com/go-pg/pg"
 	mock "github.com/stretchr/testify/mock"
 
@@ -14,41 +15,52 @@ type UserAuthRepository struct {
 	mock.Mock
 }
 
-// CreateDefaultHelmPolicies provides a mock function with given fields: team, entityName, env, tx
-func (_m *UserAuthRepository) CreateDefaultHelmPolicies(team string, entityName string, env string, tx *pg.Tx) (bool, error) {
-	ret := _m.Called(team, entityName, env, tx)
+// CreateDefaultPoliciesForAllTypes provides a mock function with given fields: team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId
+func (_m *UserAuthRepository) CreateDefaultPoliciesForAllTypes(team string, entityName string, env string, entity string, cluster string, namespace string, group string, kind string, resource string, actionType string, accessType string, UserId int32) (bool, error, []casbin2.Policy) {
+	ret := _m.Called(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, string, string, *pg.Tx) bool); ok {
-		r0 = rf(team, entityName, env, tx)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string, string, string, string, string, string, int32) bool); ok {
+		r0 = rf(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, *pg.Tx) error); ok {
-		r1 = rf(team, entityName, env, tx)
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string, string, string, string, string, string, int32) error); ok {
+		r1 = rf(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId)
 	} else {
 		r1 = ret.Error(1)
 	}
 
-	return r0, r1
+	var r2 []casbin2.Policy
+	if rf, ok := ret.Get(2).(func(string, string, string, string, string, string, string, string, string, string, string, int32) []casbin2.Policy); ok {
+		r2 = rf(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]casbin2.Policy)
+		}
+	}
+
+	return r0, r1, r2
 }
 
-// CreateDefaultPolicies provides a mock function with given fields: team, entityName, env, tx
-func (_m *UserAuthRepository) CreateDefaultPolicies(team string, entityName string, env string, tx *pg.Tx) (bool, error) {
-	ret := _m.Called(team, entityName, env, tx)
+// CreateRole provides a mock function with given fields: role
+func (_m *UserAuthRepository) CreateRole(role *repository.RoleModel) (*repository.RoleModel, error) {
+	ret := _m.Called(role)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, string, string, *pg.Tx) bool); ok {
-		r0 = rf(team, entityName, env, tx)
+	var r0 *repository.RoleModel
+	if rf, ok := ret.Get(0).(func(*repository.RoleModel) *repository.RoleModel); ok {
+		r0 = rf(role)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.RoleModel)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, *pg.Tx) error); ok {
-		r1 = rf(team, entityName, env, tx)
+	if rf, ok := ret.Get(1).(func(*repository.RoleModel) error); ok {
+		r1 = rf(role)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,20 +68,20 @@ func (_m *UserAuthRepository) CreateDefaultPolicies(team string, entityName stri
 	return r0, r1
 }
 
-// CreateDefaultPoliciesForGlobalEntity provides a mock function with given fields: entity, entityName, action, tx
-func (_m *UserAuthRepository) CreateDefaultPoliciesForGlobalEntity(entity string, entityName string, action string, tx *pg.Tx) (bool, error) {
-	ret := _m.Called(entity, entityName, action, tx)
+// CreateRoleForSuperAdminIfNotExists provides a mock function with given fields: tx, UserId
+func (_m *UserAuthRepository) CreateRoleForSuperAdminIfNotExists(tx *pg.Tx, UserId int32) (bool, error) {
+	ret := _m.Called(tx, UserId)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, string, string, *pg.Tx) bool); ok {
-		r0 = rf(entity, entityName, action, tx)
+	if rf, ok := ret.Get(0).(func(*pg.Tx, int32) bool); ok {
+		r0 = rf(tx, UserId)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, *pg.Tx) error); ok {
-		r1 = rf(entity, entityName, action, tx)
+	if rf, ok := ret.Get(1).(func(*pg.Tx, int32) error); ok {
+		r1 = rf(tx, UserId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -77,8 +89,8 @@ func (_m *UserAuthRepository) CreateDefaultPoliciesForGlobalEntity(entity string
 	return r0, r1
 }
 
-// CreateRole provides a mock function with given fields: userModel, tx
-func (_m *UserAuthRepository) CreateRole(userModel *repository.RoleModel, tx *pg.Tx) (*repository.RoleModel, error) {
+// CreateRoleWithTxn provides a mock function with given fields: userModel, tx
+func (_m *UserAuthRepository) CreateRoleWithTxn(userModel *repository.RoleModel, tx *pg.Tx) (*repository.RoleModel, error) {
 	ret := _m.Called(userModel, tx)
 
 	var r0 *repository.RoleModel
@@ -100,20 +112,20 @@ func (_m *UserAuthRepository) CreateRole(userModel *repository.RoleModel, tx *pg
 	return r0, r1
 }
 
-// CreateRoleForSuperAdminIfNotExists provides a mock function with given fields: tx
-func (_m *UserAuthRepository) CreateRoleForSuperAdminIfNotExists(tx *pg.Tx) (bool, error) {
-	ret := _m.Called(tx)
+// CreateRolesWithAccessTypeAndEntity provides a mock function with given fields: team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId, role
+func (_m *UserAuthRepository) CreateRolesWithAccessTypeAndEntity(team string, entityName string, env string, entity string, cluster string, namespace string, group string, kind string, resource string, actionType string, accessType string, UserId int32, role string) (bool, error) {
+	ret := _m.Called(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId, role)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(*pg.Tx) bool); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string, string, string, string, string, string, int32, string) bool); ok {
+		r0 = rf(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId, role)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*pg.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string, string, string, string, string, string, int32, string) error); ok {
+		r1 = rf(team, entityName, env, entity, cluster, namespace, group, kind, resource, actionType, accessType, UserId, role)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -216,20 +228,20 @@ func (_m *UserAuthRepository) GetAllRole() ([]repository.RoleModel, error) {
 	return r0, r1
 }
 
-// GetRoleByFilter provides a mock function with given fields: entity, team, app, env, act, accessType
-func (_m *UserAuthRepository) GetRoleByFilter(entity string, team string, app string, env string, act string, accessType string) (repository.RoleModel, error) {
-	ret := _m.Called(entity, team, app, env, act, accessType)
+// GetRoleByFilterForAllTypes provides a mock function with given fields: entity, team, app, env, act, accessType, cluster, namespace, group, kind, resource, action, oldValues
+func (_m *UserAuthRepository) GetRoleByFilterForAllTypes(entity string, team string, app string, env string, act string, accessType string, cluster string, namespace string, group string, kind string, resource string, action string, oldValues bool) (repository.RoleModel, error) {
+	ret := _m.Called(entity, team, app, env, act, accessType, cluster, namespace, group, kind, resource, action, oldValues)
 
 	var r0 repository.RoleModel
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string) repository.RoleModel); ok {
-		r0 = rf(entity, team, app, env, act, accessType)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string, string, string, string, string, string, string, bool) repository.RoleModel); ok {
+		r0 = rf(entity, team, app, env, act, accessType, cluster, namespace, group, kind, resource, action, oldValues)
 	} else {
 		r0 = ret.Get(0).(repository.RoleModel)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string) error); ok {
-		r1 = rf(entity, team, app, env, act, accessType)
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string, string, string, string, string, string, string, bool) error); ok {
+		r1 = rf(entity, team, app, env, act, accessType, cluster, namespace, group, kind, resource, action, oldValues)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -329,6 +341,29 @@ func (_m *UserAuthRepository) GetRolesByGroupId(userId int32) ([]*repository.Rol
 	return r0, r1
 }
 
+// GetRolesByIds provides a mock function with given fields: ids
+func (_m *UserAuthRepository) GetRolesByIds(ids []int) ([]repository.RoleModel, error) {
+	ret := _m.Called(ids)
+
+	var r0 []repository.RoleModel
+	if rf, ok := ret.Get(0).(func([]int) []repository.RoleModel); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.RoleModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRolesByUserId provides a mock function with given fields: userId
 func (_m *UserAuthRepository) GetRolesByUserId(userId int32) ([]repository.RoleModel, error) {
 	ret := _m.Called(userId)
@@ -352,6 +387,29 @@ func (_m *UserAuthRepository) GetRolesByUserId(userId int32) ([]repository.RoleM
 	return r0, r1
 }
 
+// GetRolesByUserIdAndEntityType provides a mock function with given fields: userId, entityType
+func (_m *UserAuthRepository) GetRolesByUserIdAndEntityType(userId int32, entityType string) ([]*repository.RoleModel, error) {
+	ret := _m.Called(userId, entityType)
+
+	var r0 []*repository.RoleModel
+	if rf, ok := ret.Get(0).(func(int32, string) []*repository.RoleModel); ok {
+		r0 = rf(userId, entityType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.RoleModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int32, string) error); ok {
+		r1 = rf(userId, entityType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRolesForApp provides a mock function with given fields: appName
 func (_m *UserAuthRepository) GetRolesForApp(appName string) ([]*repository.RoleModel, error) {
 	ret := _m.Called(appName)
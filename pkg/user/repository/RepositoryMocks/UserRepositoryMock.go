@@ -4,9 +4,8 @@ package repomock
 
 import (
 	bean "github.com/devtron-labs/devtron/api/bean"
-	mock "github.com/stretchr/testify/mock"
-
 	pg "github.com/go-pg/pg"
+	mock "github.com/stretchr/testify/mock"
 
 	repository "github.com/devtron-labs/devtron/pkg/user/repository"
 )
@@ -83,6 +82,29 @@ func (_m *UserRepository) FetchActiveUserByEmail(email string) (bean.UserInfo, e
 	return r0, r1
 }
 
+// FetchActiveUsersByEmails provides a mock function with given fields: emails
+func (_m *UserRepository) FetchActiveUsersByEmails(emails []string) ([]repository.UserModel, error) {
+	ret := _m.Called(emails)
+
+	var r0 []repository.UserModel
+	if rf, ok := ret.Get(0).(func([]string) []repository.UserModel); ok {
+		r0 = rf(emails)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.UserModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(emails)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchUserDetailByEmail provides a mock function with given fields: email
 func (_m *UserRepository) FetchUserDetailByEmail(email string) (bean.UserInfo, error) {
 	ret := _m.Called(email)
@@ -150,6 +172,29 @@ func (_m *UserRepository) GetAllExcludingApiTokenUser() ([]repository.UserModel,
 	return r0, r1
 }
 
+// GetAllIncludeDeletedExcludingApiTokenUser provides a mock function with given fields:
+func (_m *UserRepository) GetAllIncludeDeletedExcludingApiTokenUser() ([]repository.UserModel, error) {
+	ret := _m.Called()
+
+	var r0 []repository.UserModel
+	if rf, ok := ret.Get(0).(func() []repository.UserModel); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.UserModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetById provides a mock function with given fields: id
 func (_m *UserRepository) GetById(id int32) (*repository.UserModel, error) {
 	ret := _m.Called(id)
@@ -235,6 +280,29 @@ func (_m *UserRepository) GetConnection() *pg.DB {
 	return r0
 }
 
+// UpdateRoleIdForUserRolesMappings provides a mock function with given fields: roleId, newRoleId
+func (_m *UserRepository) UpdateRoleIdForUserRolesMappings(roleId int, newRoleId int) (*repository.UserRoleModel, error) {
+	ret := _m.Called(roleId, newRoleId)
+
+	var r0 *repository.UserRoleModel
+	if rf, ok := ret.Get(0).(func(int, int) *repository.UserRoleModel); ok {
+		r0 = rf(roleId, newRoleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.UserRoleModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(roleId, newRoleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateUser provides a mock function with given fields: userModel, tx
 func (_m *UserRepository) UpdateUser(userModel *repository.UserModel, tx *pg.Tx) (*repository.UserModel, error) {
 	ret := _m.Called(userModel, tx)
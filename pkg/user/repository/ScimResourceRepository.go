@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+const (
+	SCIM_RESOURCE_TYPE_USER  = "User"
+	SCIM_RESOURCE_TYPE_GROUP = "Group"
+)
+
+// ScimResource keeps the attributes an identity provider sets on a provisioned user or role group which devtron has no
+// column for. ResourceId is the id of the user or of the role group
+type ScimResource struct {
+	TableName    struct{} `sql:"scim_resource" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	ResourceType string   `sql:"resource_type,notnull"`
+	ResourceId   int32    `sql:"resource_id,notnull"`
+	ExternalId   string   `sql:"external_id"`
+	DisplayName  string   `sql:"display_name"`
+	GivenName    string   `sql:"given_name"`
+	FamilyName   string   `sql:"family_name"`
+	sql.AuditLog
+}
+
+type ScimResourceRepository interface {
+	Save(resource *ScimResource) error
+	Update(resource *ScimResource) error
+	Delete(resource *ScimResource) error
+	FindByResource(resourceType string, resourceId int32) (*ScimResource, error)
+	FindAllByResourceType(resourceType string) ([]*ScimResource, error)
+}
+
+type ScimResourceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewScimResourceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ScimResourceRepositoryImpl {
+	return &ScimResourceRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl ScimResourceRepositoryImpl) Save(resource *ScimResource) error {
+	return impl.dbConnection.Insert(resource)
+}
+
+func (impl ScimResourceRepositoryImpl) Update(resource *ScimResource) error {
+	return impl.dbConnection.Update(resource)
+}
+
+func (impl ScimResourceRepositoryImpl) Delete(resource *ScimResource) error {
+	return impl.dbConnection.Delete(resource)
+}
+
+func (impl ScimResourceRepositoryImpl) FindByResource(resourceType string, resourceId int32) (*ScimResource, error) {
+	resource := &ScimResource{}
+	err := impl.dbConnection.Model(resource).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceId).
+		Select()
+	return resource, err
+}
+
+func (impl ScimResourceRepositoryImpl) FindAllByResourceType(resourceType string) ([]*ScimResource, error) {
+	var resources []*ScimResource
+	err := impl.dbConnection.Model(&resources).Where("resource_type = ?", resourceType).Select()
+	return resources, err
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type AccessRequestStatus string
+
+const (
+	ACCESS_REQUEST_STATUS_PENDING   AccessRequestStatus = "PENDING"
+	ACCESS_REQUEST_STATUS_APPROVED  AccessRequestStatus = "APPROVED"
+	ACCESS_REQUEST_STATUS_REJECTED  AccessRequestStatus = "REJECTED"
+	ACCESS_REQUEST_STATUS_CANCELLED AccessRequestStatus = "CANCELLED"
+)
+
+type AccessGrantStatus string
+
+const (
+	ACCESS_GRANT_STATUS_SCHEDULED AccessGrantStatus = "SCHEDULED"
+	ACCESS_GRANT_STATUS_ACTIVE    AccessGrantStatus = "ACTIVE"
+	ACCESS_GRANT_STATUS_EXPIRED   AccessGrantStatus = "EXPIRED"
+	ACCESS_GRANT_STATUS_REVOKED   AccessGrantStatus = "REVOKED"
+)
+
+type AccessAuditAction string
+
+const (
+	ACCESS_AUDIT_ACTION_REQUESTED AccessAuditAction = "REQUESTED"
+	ACCESS_AUDIT_ACTION_APPROVED  AccessAuditAction = "APPROVED"
+	ACCESS_AUDIT_ACTION_REJECTED  AccessAuditAction = "REJECTED"
+	ACCESS_AUDIT_ACTION_CANCELLED AccessAuditAction = "CANCELLED"
+	ACCESS_AUDIT_ACTION_GRANTED   AccessAuditAction = "GRANTED"
+	ACCESS_AUDIT_ACTION_ACTIVATED AccessAuditAction = "ACTIVATED"
+	ACCESS_AUDIT_ACTION_EXPIRED   AccessAuditAction = "EXPIRED"
+	ACCESS_AUDIT_ACTION_REVOKED   AccessAuditAction = "REVOKED"
+)
+
+// UserAccessRequest is access asked for by a user, either the roles of a role filter or a role group
+type UserAccessRequest struct {
+	TableName     struct{}            `sql:"user_access_request" pg:",discard_unknown_columns"`
+	Id            int                 `sql:"id,pk"`
+	UserId        int32               `sql:"user_id,notnull"`
+	RoleFilter    *bean.RoleFilter    `sql:"role_filter"`
+	RoleGroupId   int32               `sql:"role_group_id"`
+	Reason        string              `sql:"reason"`
+	StartsOn      time.Time           `sql:"starts_on,notnull"`
+	ExpiresOn     time.Time           `sql:"expires_on,notnull"`
+	Status        AccessRequestStatus `sql:"status,notnull"`
+	ReviewedBy    int32               `sql:"reviewed_by"`
+	ReviewedOn    time.Time           `sql:"reviewed_on"`
+	ReviewComment string              `sql:"review_comment"`
+	sql.AuditLog
+}
+
+// UserAccessGrant is a time bound mapping of a user to a role or a role group. MappingOwned is set when the user_roles
+// mapping or the group policy was added by the grant, access the user had before the grant is left as is on expiry
+type UserAccessGrant struct {
+	TableName       struct{}          `sql:"user_access_grant" pg:",discard_unknown_columns"`
+	Id              int               `sql:"id,pk"`
+	UserId          int32             `sql:"user_id,notnull"`
+	RoleId          int               `sql:"role_id"`
+	RoleGroupId     int32             `sql:"role_group_id"`
+	AccessRequestId int               `sql:"access_request_id"`
+	StartsOn        time.Time         `sql:"starts_on,notnull"`
+	ExpiresOn       time.Time         `sql:"expires_on,notnull"`
+	Status          AccessGrantStatus `sql:"status,notnull"`
+	MappingOwned    bool              `sql:"mapping_owned,notnull"`
+	Reason          string            `sql:"reason"`
+	RevokedBy       int32             `sql:"revoked_by"`
+	RevokedOn       time.Time         `sql:"revoked_on"`
+	sql.AuditLog
+}
+
+type UserAccessAudit struct {
+	TableName       struct{}          `sql:"user_access_audit" pg:",discard_unknown_columns"`
+	Id              int               `sql:"id,pk"`
+	UserId          int32             `sql:"user_id,notnull"`
+	AccessGrantId   int               `sql:"access_grant_id"`
+	AccessRequestId int               `sql:"access_request_id"`
+	Action          AccessAuditAction `sql:"action,notnull"`
+	Details         string            `sql:"details"`
+	PerformedBy     int32             `sql:"performed_by,notnull"`
+	PerformedOn     time.Time         `sql:"performed_on,notnull"`
+}
+
+type UserAccessGrantRepository interface {
+	GetConnection() *pg.DB
+
+	SaveRequest(request *UserAccessRequest, tx *pg.Tx) error
+	UpdateRequest(request *UserAccessRequest, tx *pg.Tx) error
+	FindRequestById(id int) (*UserAccessRequest, error)
+	// FindRequests lists requests of the user when userId is set, all requests otherwise
+	FindRequests(userId int32, statuses []AccessRequestStatus) ([]*UserAccessRequest, error)
+
+	SaveGrant(grant *UserAccessGrant, tx *pg.Tx) error
+	UpdateGrant(grant *UserAccessGrant, tx *pg.Tx) error
+	// ClaimGrant locks the grant for the tx when it is still in one of the statuses given and reloads it, false is
+	// returned when the grant has moved on or is being processed by another tx
+	ClaimGrant(grant *UserAccessGrant, statuses []AccessGrantStatus, tx *pg.Tx) (bool, error)
+	UpdateMappingOwned(grantId int, mappingOwned bool, tx *pg.Tx) error
+	FindGrantById(id int) (*UserAccessGrant, error)
+	FindGrantsByUserId(userId int32) ([]*UserAccessGrant, error)
+	// FindDueGrants lists scheduled grants to be started and active grants to be expired as of the time given
+	FindDueGrants(now time.Time) ([]*UserAccessGrant, error)
+	// FindActiveGrantsForMapping lists the other active grants of the user on the same role or role group
+	FindActiveGrantsForMapping(userId int32, roleId int, roleGroupId int32, excludeGrantId int) ([]*UserAccessGrant, error)
+	// FindActiveOwnedGrantsByUserId lists the active grants of the user which own their role or group mapping
+	FindActiveOwnedGrantsByUserId(userId int32) ([]*UserAccessGrant, error)
+
+	SaveAudit(audits []*UserAccessAudit, tx *pg.Tx) error
+	FindAuditByUserId(userId int32, offset int, size int) ([]*UserAccessAudit, error)
+}
+
+type UserAccessGrantRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewUserAccessGrantRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *UserAccessGrantRepositoryImpl {
+	return &UserAccessGrantRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl UserAccessGrantRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl UserAccessGrantRepositoryImpl) SaveRequest(request *UserAccessRequest, tx *pg.Tx) error {
+	return tx.Insert(request)
+}
+
+func (impl UserAccessGrantRepositoryImpl) UpdateRequest(request *UserAccessRequest, tx *pg.Tx) error {
+	return tx.Update(request)
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindRequestById(id int) (*UserAccessRequest, error) {
+	request := &UserAccessRequest{}
+	err := impl.dbConnection.Model(request).Where("id = ?", id).Select()
+	return request, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindRequests(userId int32, statuses []AccessRequestStatus) ([]*UserAccessRequest, error) {
+	var requests []*UserAccessRequest
+	query := impl.dbConnection.Model(&requests)
+	if userId > 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status in (?)", pg.In(statuses))
+	}
+	err := query.Order("id desc").Select()
+	return requests, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) SaveGrant(grant *UserAccessGrant, tx *pg.Tx) error {
+	return tx.Insert(grant)
+}
+
+func (impl UserAccessGrantRepositoryImpl) UpdateGrant(grant *UserAccessGrant, tx *pg.Tx) error {
+	return tx.Update(grant)
+}
+
+func (impl UserAccessGrantRepositoryImpl) ClaimGrant(grant *UserAccessGrant, statuses []AccessGrantStatus, tx *pg.Tx) (bool, error) {
+	err := tx.Model(grant).
+		Where("id = ?", grant.Id).
+		Where("status in (?)", pg.In(statuses)).
+		For("UPDATE SKIP LOCKED").
+		Select()
+	if err == pg.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (impl UserAccessGrantRepositoryImpl) UpdateMappingOwned(grantId int, mappingOwned bool, tx *pg.Tx) error {
+	_, err := tx.Model(&UserAccessGrant{}).
+		Set("mapping_owned = ?", mappingOwned).
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", grantId).
+		Update()
+	return err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindGrantById(id int) (*UserAccessGrant, error) {
+	grant := &UserAccessGrant{}
+	err := impl.dbConnection.Model(grant).Where("id = ?", id).Select()
+	return grant, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindGrantsByUserId(userId int32) ([]*UserAccessGrant, error) {
+	var grants []*UserAccessGrant
+	err := impl.dbConnection.Model(&grants).Where("user_id = ?", userId).Order("id desc").Select()
+	return grants, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindDueGrants(now time.Time) ([]*UserAccessGrant, error) {
+	var grants []*UserAccessGrant
+	err := impl.dbConnection.Model(&grants).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("status = ? AND starts_on <= ?", ACCESS_GRANT_STATUS_SCHEDULED, now).
+				WhereOr("status = ? AND expires_on <= ?", ACCESS_GRANT_STATUS_ACTIVE, now)
+			return q, nil
+		}).
+		Order("id").
+		Select()
+	return grants, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindActiveGrantsForMapping(userId int32, roleId int, roleGroupId int32, excludeGrantId int) ([]*UserAccessGrant, error) {
+	var grants []*UserAccessGrant
+	query := impl.dbConnection.Model(&grants).
+		Where("user_id = ?", userId).
+		Where("status = ?", ACCESS_GRANT_STATUS_ACTIVE).
+		Where("id != ?", excludeGrantId)
+	if roleId > 0 {
+		query = query.Where("role_id = ?", roleId)
+	} else {
+		query = query.Where("role_group_id = ?", roleGroupId)
+	}
+	err := query.Order("expires_on desc").Select()
+	return grants, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindActiveOwnedGrantsByUserId(userId int32) ([]*UserAccessGrant, error) {
+	var grants []*UserAccessGrant
+	err := impl.dbConnection.Model(&grants).
+		Where("user_id = ?", userId).
+		Where("status = ?", ACCESS_GRANT_STATUS_ACTIVE).
+		Where("mapping_owned = ?", true).
+		Order("id").
+		Select()
+	return grants, err
+}
+
+func (impl UserAccessGrantRepositoryImpl) SaveAudit(audits []*UserAccessAudit, tx *pg.Tx) error {
+	if len(audits) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&audits).Insert()
+	return err
+}
+
+func (impl UserAccessGrantRepositoryImpl) FindAuditByUserId(userId int32, offset int, size int) ([]*UserAccessAudit, error) {
+	var audits []*UserAccessAudit
+	query := impl.dbConnection.Model(&audits)
+	if userId > 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+	err := query.Order("id desc").Offset(offset).Limit(size).Select()
+	return audits, err
+}
@@ -33,6 +33,7 @@ type UserRepository interface {
 	GetById(id int32) (*UserModel, error)
 	GetByIdIncludeDeleted(id int32) (*UserModel, error)
 	GetAllExcludingApiTokenUser() ([]UserModel, error)
+	GetAllIncludeDeletedExcludingApiTokenUser() ([]UserModel, error)
 	//GetAllUserRoleMappingsForRoleId(roleId int) ([]UserRoleModel, error)
 	FetchActiveUserByEmail(email string) (bean.UserInfo, error)
 	FetchUserDetailByEmail(email string) (bean.UserInfo, error)
@@ -40,6 +41,7 @@ type UserRepository interface {
 	GetConnection() (dbConnection *pg.DB)
 	FetchUserMatchesByEmailIdExcludingApiTokenUser(email string) ([]UserModel, error)
 	FetchActiveOrDeletedUserByEmail(email string) (*UserModel, error)
+	FetchActiveUsersByEmails(emails []string) ([]UserModel, error)
 	UpdateRoleIdForUserRolesMappings(roleId int, newRoleId int) (*UserRoleModel, error)
 }
 
@@ -112,6 +114,15 @@ func (impl UserRepositoryImpl) GetAllExcludingApiTokenUser() ([]UserModel, error
 	return userModel, err
 }
 
+// GetAllIncludeDeletedExcludingApiTokenUser also returns deactivated users, which identity providers still keep track of
+func (impl UserRepositoryImpl) GetAllIncludeDeletedExcludingApiTokenUser() ([]UserModel, error) {
+	var userModel []UserModel
+	err := impl.dbConnection.Model(&userModel).
+		Where("user_type is NULL or user_type != ?", bean.USER_TYPE_API_TOKEN).
+		Order("id asc").Select()
+	return userModel, err
+}
+
 func (impl UserRepositoryImpl) FetchActiveUserByEmail(email string) (bean.UserInfo, error) {
 	var users bean.UserInfo
 
@@ -161,6 +172,13 @@ func (impl *UserRepositoryImpl) GetConnection() (dbConnection *pg.DB) {
 	return impl.dbConnection
 }
 
+// FetchActiveUsersByEmails matches emails case insensitively, as casbin subjects are lower cased
+func (impl UserRepositoryImpl) FetchActiveUsersByEmails(emails []string) ([]UserModel, error) {
+	var model []UserModel
+	err := impl.dbConnection.Model(&model).Where("lower(email_id) in (?)", pg.In(emails)).Where("active = ?", true).Select()
+	return model, err
+}
+
 func (impl UserRepositoryImpl) FetchUserMatchesByEmailIdExcludingApiTokenUser(email string) ([]UserModel, error) {
 	var model []UserModel
 	err := impl.dbConnection.Model(&model).
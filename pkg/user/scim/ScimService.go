@@ -0,0 +1,723 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/clusterTerminalAccess"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScimService provisions users and role groups from an identity provider. Users are matched by email, which is the
+// SCIM userName, and are deactivated rather than deleted. Groups map to role groups, either existing ones with the
+// same name or new ones without roles, so the access of a group stays managed in devtron while the identity provider
+// manages its members
+type ScimService interface {
+	GetUser(id string) (*User, error)
+	ListUsers(filter string, startIndex int, count int) (*ListResponse, error)
+	CreateUser(scimUser *User, token string, userId int32) (*User, error)
+	ReplaceUser(id string, scimUser *User, token string, userId int32) (*User, error)
+	PatchUser(id string, patch *PatchRequest, token string, userId int32) (*User, error)
+	// DeleteUser deactivates the user, the user is not listed afterwards unless provisioned again
+	DeleteUser(id string, userId int32) error
+
+	GetGroup(id string) (*Group, error)
+	ListGroups(filter string, startIndex int, count int, excludeMembers bool) (*ListResponse, error)
+	CreateGroup(scimGroup *Group, userId int32) (*Group, error)
+	ReplaceGroup(id string, scimGroup *Group, userId int32) (*Group, error)
+	PatchGroup(id string, patch *PatchRequest, userId int32) (*Group, error)
+	// DeleteGroup removes all members and unlinks the role group, the role group and its roles are kept
+	DeleteGroup(id string, userId int32) error
+}
+
+type ScimServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	userService               user.UserService
+	roleGroupService          user.RoleGroupService
+	userRepository            repository.UserRepository
+	roleGroupRepository       repository.RoleGroupRepository
+	scimResourceRepository    repository.ScimResourceRepository
+	userTerminalAccessService clusterTerminalAccess.UserTerminalAccessService
+	enforcer                  casbin.Enforcer
+}
+
+func NewScimServiceImpl(logger *zap.SugaredLogger, userService user.UserService, roleGroupService user.RoleGroupService,
+	userRepository repository.UserRepository, roleGroupRepository repository.RoleGroupRepository,
+	scimResourceRepository repository.ScimResourceRepository,
+	userTerminalAccessService clusterTerminalAccess.UserTerminalAccessService, enforcer casbin.Enforcer) *ScimServiceImpl {
+	return &ScimServiceImpl{
+		logger:                    logger,
+		userService:               userService,
+		roleGroupService:          roleGroupService,
+		userRepository:            userRepository,
+		roleGroupRepository:       roleGroupRepository,
+		scimResourceRepository:    scimResourceRepository,
+		userTerminalAccessService: userTerminalAccessService,
+		enforcer:                  enforcer,
+	}
+}
+
+// linkedRoleGroup is a role group provisioned or linked through SCIM
+type linkedRoleGroup struct {
+	roleGroup *repository.RoleGroup
+	resource  *repository.ScimResource
+}
+
+func (impl *ScimServiceImpl) GetUser(id string) (*User, error) {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	linkedRoleGroups, err := impl.getLinkedRoleGroups()
+	if err != nil {
+		return nil, err
+	}
+	return adaptUser(model, resource, linkedRoleGroups), nil
+}
+
+func (impl *ScimServiceImpl) ListUsers(filter string, startIndex int, count int) (*ListResponse, error) {
+	parsedFilter, err := ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	models, err := impl.userRepository.GetAllIncludeDeletedExcludingApiTokenUser()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching users", "error", err)
+		return nil, err
+	}
+	resources, err := impl.scimResourceRepository.FindAllByResourceType(repository.SCIM_RESOURCE_TYPE_USER)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching scim users", "error", err)
+		return nil, err
+	}
+	resourceByUserId := make(map[int32]*repository.ScimResource)
+	for _, resource := range resources {
+		resourceByUserId[resource.ResourceId] = resource
+	}
+	linkedRoleGroups, err := impl.getLinkedRoleGroups()
+	if err != nil {
+		return nil, err
+	}
+	var scimUsers []interface{}
+	for i := range models {
+		model := &models[i]
+		resource := resourceByUserId[model.Id]
+		if !model.Active && resource == nil {
+			continue
+		}
+		scimUser := adaptUser(model, resource, linkedRoleGroups)
+		if parsedFilter.Matches(getUserAttributes(scimUser)) {
+			scimUsers = append(scimUsers, scimUser)
+		}
+	}
+	return paginate(scimUsers, startIndex, count), nil
+}
+
+func (impl *ScimServiceImpl) CreateUser(scimUser *User, token string, userId int32) (*User, error) {
+	emailId := strings.TrimSpace(scimUser.UserName)
+	if len(emailId) == 0 || strings.Contains(emailId, ",") {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "userName must be the email of the user")
+	}
+	existingUser, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching user", "emailId", emailId, "error", err)
+		return nil, err
+	}
+	if existingUser != nil && existingUser.Id > 0 && existingUser.Active {
+		return nil, NewError(http.StatusConflict, ScimTypeUniqueness, fmt.Sprintf("user %s already exists", emailId))
+	}
+	// reactivates the user when deactivated before
+	users, err := impl.userService.CreateUser(&bean.UserInfo{EmailId: emailId, UserId: userId}, token, impl.checkManagerAuth)
+	if err != nil {
+		impl.logger.Errorw("error while creating user", "emailId", emailId, "error", err)
+		return nil, err
+	}
+	model, err := impl.userRepository.GetById(users[0].Id)
+	if err != nil {
+		impl.logger.Errorw("error while fetching user", "userId", users[0].Id, "error", err)
+		return nil, err
+	}
+	// users deactivated through SCIM still have their resource
+	resource, err := impl.getResource(repository.SCIM_RESOURCE_TYPE_USER, model.Id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.updateUser(model, resource, scimUser, token, userId)
+}
+
+func (impl *ScimServiceImpl) ReplaceUser(id string, scimUser *User, token string, userId int32) (*User, error) {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(scimUser.UserName) > 0 && !strings.EqualFold(strings.TrimSpace(scimUser.UserName), model.EmailId) {
+		return nil, NewError(http.StatusBadRequest, ScimTypeMutability, "userName can not be changed")
+	}
+	return impl.updateUser(model, resource, scimUser, token, userId)
+}
+
+func (impl *ScimServiceImpl) PatchUser(id string, patch *PatchRequest, token string, userId int32) (*User, error) {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	scimUser := adaptUser(model, resource, nil)
+	err = applyUserPatch(scimUser, patch.Operations)
+	if err != nil {
+		return nil, err
+	}
+	return impl.updateUser(model, resource, scimUser, token, userId)
+}
+
+func (impl *ScimServiceImpl) DeleteUser(id string, userId int32) error {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return err
+	}
+	if model.Active {
+		err = impl.deactivateUser(model, userId)
+		if err != nil {
+			return err
+		}
+	}
+	if resource != nil {
+		err = impl.scimResourceRepository.Delete(resource)
+		if err != nil {
+			impl.logger.Errorw("error while deleting scim user", "userId", model.Id, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getUser returns active users and users deactivated through SCIM, which still have their resource
+func (impl *ScimServiceImpl) getUser(id string) (*repository.UserModel, *repository.ScimResource, error) {
+	resourceId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, NewError(http.StatusNotFound, "", fmt.Sprintf("user %s not found", id))
+	}
+	model, err := impl.userRepository.GetByIdIncludeDeleted(int32(resourceId))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching user", "userId", resourceId, "error", err)
+		return nil, nil, err
+	}
+	if err == pg.ErrNoRows || model.UserType == bean.USER_TYPE_API_TOKEN {
+		return nil, nil, NewError(http.StatusNotFound, "", fmt.Sprintf("user %s not found", id))
+	}
+	resource, err := impl.getResource(repository.SCIM_RESOURCE_TYPE_USER, model.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !model.Active && resource == nil {
+		return nil, nil, NewError(http.StatusNotFound, "", fmt.Sprintf("user %s not found", id))
+	}
+	return model, resource, nil
+}
+
+// updateUser saves the attributes of the SCIM user and (de)activates the user when active is set
+func (impl *ScimServiceImpl) updateUser(model *repository.UserModel, resource *repository.ScimResource, scimUser *User,
+	token string, userId int32) (*User, error) {
+	if resource == nil {
+		resource = &repository.ScimResource{ResourceType: repository.SCIM_RESOURCE_TYPE_USER, ResourceId: model.Id}
+	}
+	resource.ExternalId = scimUser.ExternalId
+	resource.DisplayName = scimUser.DisplayName
+	resource.GivenName, resource.FamilyName = "", ""
+	if scimUser.Name != nil {
+		resource.GivenName = scimUser.Name.GivenName
+		resource.FamilyName = scimUser.Name.FamilyName
+	}
+	err := impl.saveResource(resource, userId)
+	if err != nil {
+		return nil, err
+	}
+	if scimUser.Active != nil && *scimUser.Active != model.Active {
+		if *scimUser.Active {
+			_, err = impl.userService.CreateUser(&bean.UserInfo{EmailId: model.EmailId, UserId: userId}, token, impl.checkManagerAuth)
+		} else {
+			err = impl.deactivateUser(model, userId)
+		}
+		if err != nil {
+			impl.logger.Errorw("error while updating user status", "userId", model.Id, "active", *scimUser.Active, "error", err)
+			return nil, err
+		}
+	}
+	return impl.GetUser(strconv.Itoa(int(model.Id)))
+}
+
+// deactivateUser removes the roles of the user, so that the access is lost right away, and closes open terminal sessions
+func (impl *ScimServiceImpl) deactivateUser(model *repository.UserModel, userId int32) error {
+	_, err := impl.userService.DeleteUser(&bean.UserInfo{Id: model.Id, UserId: userId})
+	if err != nil {
+		impl.logger.Errorw("error while deactivating user", "userId", model.Id, "error", err)
+		return err
+	}
+	impl.userTerminalAccessService.DisconnectAllSessionsForUser(context.Background(), model.Id)
+	impl.enforcer.InvalidateCache(strings.ToLower(model.EmailId))
+	return nil
+}
+
+func (impl *ScimServiceImpl) GetGroup(id string) (*Group, error) {
+	roleGroup, resource, err := impl.getGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.adaptGroup(roleGroup, resource)
+}
+
+func (impl *ScimServiceImpl) ListGroups(filter string, startIndex int, count int, excludeMembers bool) (*ListResponse, error) {
+	parsedFilter, err := ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	linkedRoleGroups, err := impl.getLinkedRoleGroups()
+	if err != nil {
+		return nil, err
+	}
+	var scimGroups []interface{}
+	for _, linkedRoleGroup := range linkedRoleGroups {
+		scimGroup, err := impl.adaptGroup(linkedRoleGroup.roleGroup, linkedRoleGroup.resource)
+		if err != nil {
+			return nil, err
+		}
+		if !parsedFilter.Matches(getGroupAttributes(scimGroup)) {
+			continue
+		}
+		if excludeMembers {
+			scimGroup.Members = nil
+		}
+		scimGroups = append(scimGroups, scimGroup)
+	}
+	return paginate(scimGroups, startIndex, count), nil
+}
+
+func (impl *ScimServiceImpl) CreateGroup(scimGroup *Group, userId int32) (*Group, error) {
+	name := strings.TrimSpace(scimGroup.DisplayName)
+	if len(name) == 0 {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "displayName is required")
+	}
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role group", "name", name, "error", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		createdRoleGroup, err := impl.roleGroupService.CreateRoleGroup(&bean.RoleGroup{Name: name, UserId: userId,
+			Description: "provisioned through SCIM"})
+		if err != nil {
+			impl.logger.Errorw("error while creating role group", "name", name, "error", err)
+			return nil, err
+		}
+		roleGroup, err = impl.roleGroupRepository.GetRoleGroupById(createdRoleGroup.Id)
+		if err != nil {
+			impl.logger.Errorw("error while fetching role group", "roleGroupId", createdRoleGroup.Id, "error", err)
+			return nil, err
+		}
+	} else {
+		resource, err := impl.getResource(repository.SCIM_RESOURCE_TYPE_GROUP, roleGroup.Id)
+		if err != nil {
+			return nil, err
+		}
+		if resource != nil {
+			return nil, NewError(http.StatusConflict, ScimTypeUniqueness, fmt.Sprintf("group %s already exists", name))
+		}
+	}
+	resource := &repository.ScimResource{ResourceType: repository.SCIM_RESOURCE_TYPE_GROUP, ResourceId: roleGroup.Id}
+	return impl.updateGroup(roleGroup, resource, &Group{}, scimGroup, userId)
+}
+
+func (impl *ScimServiceImpl) ReplaceGroup(id string, scimGroup *Group, userId int32) (*Group, error) {
+	roleGroup, resource, err := impl.getGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	currentGroup, err := impl.adaptGroup(roleGroup, resource)
+	if err != nil {
+		return nil, err
+	}
+	return impl.updateGroup(roleGroup, resource, currentGroup, scimGroup, userId)
+}
+
+func (impl *ScimServiceImpl) PatchGroup(id string, patch *PatchRequest, userId int32) (*Group, error) {
+	roleGroup, resource, err := impl.getGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	currentGroup, err := impl.adaptGroup(roleGroup, resource)
+	if err != nil {
+		return nil, err
+	}
+	scimGroup := *currentGroup
+	scimGroup.Members = append([]Reference{}, currentGroup.Members...)
+	err = applyGroupPatch(&scimGroup, patch.Operations)
+	if err != nil {
+		return nil, err
+	}
+	return impl.updateGroup(roleGroup, resource, currentGroup, &scimGroup, userId)
+}
+
+func (impl *ScimServiceImpl) DeleteGroup(id string, userId int32) error {
+	roleGroup, resource, err := impl.getGroup(id)
+	if err != nil {
+		return err
+	}
+	currentGroup, err := impl.adaptGroup(roleGroup, resource)
+	if err != nil {
+		return err
+	}
+	err = impl.updateMembers(roleGroup, currentGroup.Members, nil)
+	if err != nil {
+		return err
+	}
+	err = impl.scimResourceRepository.Delete(resource)
+	if err != nil {
+		impl.logger.Errorw("error while deleting scim group", "roleGroupId", roleGroup.Id, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) getGroup(id string) (*repository.RoleGroup, *repository.ScimResource, error) {
+	resourceId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, NewError(http.StatusNotFound, "", fmt.Sprintf("group %s not found", id))
+	}
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(int32(resourceId))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role group", "roleGroupId", resourceId, "error", err)
+		return nil, nil, err
+	}
+	var resource *repository.ScimResource
+	if err == nil {
+		resource, err = impl.getResource(repository.SCIM_RESOURCE_TYPE_GROUP, roleGroup.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if resource == nil {
+		return nil, nil, NewError(http.StatusNotFound, "", fmt.Sprintf("group %s not found", id))
+	}
+	return roleGroup, resource, nil
+}
+
+// updateGroup renames the role group when the display name changed, saves the SCIM attributes and syncs the members
+func (impl *ScimServiceImpl) updateGroup(roleGroup *repository.RoleGroup, resource *repository.ScimResource,
+	currentGroup *Group, scimGroup *Group, userId int32) (*Group, error) {
+	name := strings.TrimSpace(scimGroup.DisplayName)
+	if len(name) == 0 {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "displayName is required")
+	}
+	if name != roleGroup.Name {
+		err := impl.renameRoleGroup(roleGroup, name, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resource.ExternalId = scimGroup.ExternalId
+	resource.DisplayName = name
+	err := impl.saveResource(resource, userId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.updateMembers(roleGroup, currentGroup.Members, scimGroup.Members)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetGroup(strconv.Itoa(int(roleGroup.Id)))
+}
+
+// renameRoleGroup only changes the name, the casbin name the policies are bound to stays as is
+func (impl *ScimServiceImpl) renameRoleGroup(roleGroup *repository.RoleGroup, name string, userId int32) error {
+	existingRoleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role group", "name", name, "error", err)
+		return err
+	}
+	if err == nil && existingRoleGroup.Id != roleGroup.Id {
+		return NewError(http.StatusConflict, ScimTypeUniqueness, fmt.Sprintf("group %s already exists", name))
+	}
+	tx, err := impl.roleGroupRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	roleGroup.Name = name
+	roleGroup.UpdatedBy = userId
+	roleGroup.UpdatedOn = time.Now()
+	_, err = impl.roleGroupRepository.UpdateRoleGroup(roleGroup, tx)
+	if err != nil {
+		impl.logger.Errorw("error while renaming role group", "roleGroupId", roleGroup.Id, "error", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateMembers adds and removes the group policies of users to match the members asked for, the enforcer cache of
+// the users is invalidated by the casbin adapter
+func (impl *ScimServiceImpl) updateMembers(roleGroup *repository.RoleGroup, currentMembers []Reference, members []Reference) error {
+	var addedUserIds, removedUserIds []int32
+	for _, member := range members {
+		if containsMember(currentMembers, member.Value) {
+			continue
+		}
+		memberUserId, err := strconv.Atoi(member.Value)
+		if err != nil {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, fmt.Sprintf("member %s not found", member.Value))
+		}
+		addedUserIds = append(addedUserIds, int32(memberUserId))
+	}
+	for _, member := range currentMembers {
+		if containsMember(members, member.Value) {
+			continue
+		}
+		memberUserId, err := strconv.Atoi(member.Value)
+		if err == nil {
+			removedUserIds = append(removedUserIds, int32(memberUserId))
+		}
+	}
+	addedPolicies, err := impl.getGroupPolicies(roleGroup, addedUserIds, true)
+	if err != nil {
+		return err
+	}
+	removedPolicies, err := impl.getGroupPolicies(roleGroup, removedUserIds, false)
+	if err != nil {
+		return err
+	}
+	if len(addedPolicies) > 0 {
+		casbin.AddPolicy(addedPolicies)
+	}
+	if len(removedPolicies) > 0 {
+		casbin.RemovePolicy(removedPolicies)
+	}
+	return nil
+}
+
+// getGroupPolicies returns the group policies of the users, all users need to be active members to be added
+func (impl *ScimServiceImpl) getGroupPolicies(roleGroup *repository.RoleGroup, userIds []int32, allRequired bool) ([]casbin.Policy, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	users, err := impl.userRepository.GetByIds(userIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching users", "userIds", userIds, "error", err)
+		return nil, err
+	}
+	if allRequired && len(users) != len(userIds) {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "members must be active users")
+	}
+	var policies []casbin.Policy
+	for _, model := range users {
+		policies = append(policies, casbin.Policy{Type: "g", Sub: casbin.Subject(model.EmailId), Obj: casbin.Object(roleGroup.CasbinName)})
+	}
+	return policies, nil
+}
+
+func (impl *ScimServiceImpl) adaptGroup(roleGroup *repository.RoleGroup, resource *repository.ScimResource) (*Group, error) {
+	emailIds, err := casbin.GetUserByRole(roleGroup.CasbinName)
+	if err != nil {
+		impl.logger.Errorw("error while fetching group members", "roleGroupId", roleGroup.Id, "error", err)
+		return nil, err
+	}
+	var members []Reference
+	if len(emailIds) > 0 {
+		for i := range emailIds {
+			emailIds[i] = strings.ToLower(emailIds[i])
+		}
+		users, err := impl.userRepository.FetchActiveUsersByEmails(emailIds)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while fetching group members", "roleGroupId", roleGroup.Id, "error", err)
+			return nil, err
+		}
+		for _, model := range users {
+			if model.UserType == bean.USER_TYPE_API_TOKEN {
+				continue
+			}
+			members = append(members, Reference{Value: strconv.Itoa(int(model.Id)), Display: model.EmailId})
+		}
+	}
+	return &Group{
+		Schemas:     []string{SchemaGroup},
+		Id:          strconv.Itoa(int(roleGroup.Id)),
+		ExternalId:  resource.ExternalId,
+		DisplayName: roleGroup.Name,
+		Members:     members,
+		Meta:        getMeta(ResourceTypeGroup, roleGroup.AuditLog, resource),
+	}, nil
+}
+
+// getLinkedRoleGroups returns the active role groups provisioned or linked through SCIM
+func (impl *ScimServiceImpl) getLinkedRoleGroups() ([]*linkedRoleGroup, error) {
+	resources, err := impl.scimResourceRepository.FindAllByResourceType(repository.SCIM_RESOURCE_TYPE_GROUP)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching scim groups", "error", err)
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role groups", "error", err)
+		return nil, err
+	}
+	roleGroupById := make(map[int32]*repository.RoleGroup)
+	for _, roleGroup := range roleGroups {
+		roleGroupById[roleGroup.Id] = roleGroup
+	}
+	var linkedRoleGroups []*linkedRoleGroup
+	for _, resource := range resources {
+		if roleGroup, ok := roleGroupById[resource.ResourceId]; ok {
+			linkedRoleGroups = append(linkedRoleGroups, &linkedRoleGroup{roleGroup: roleGroup, resource: resource})
+		}
+	}
+	return linkedRoleGroups, nil
+}
+
+func (impl *ScimServiceImpl) getResource(resourceType string, resourceId int32) (*repository.ScimResource, error) {
+	resource, err := impl.scimResourceRepository.FindByResource(resourceType, resourceId)
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		impl.logger.Errorw("error while fetching scim resource", "resourceType", resourceType, "resourceId", resourceId, "error", err)
+		return nil, err
+	}
+	return resource, nil
+}
+
+func (impl *ScimServiceImpl) saveResource(resource *repository.ScimResource, userId int32) error {
+	var err error
+	resource.UpdatedBy = userId
+	resource.UpdatedOn = time.Now()
+	if resource.Id == 0 {
+		resource.CreatedBy = userId
+		resource.CreatedOn = time.Now()
+		err = impl.scimResourceRepository.Save(resource)
+	} else {
+		err = impl.scimResourceRepository.Update(resource)
+	}
+	if err != nil {
+		impl.logger.Errorw("error while saving scim resource", "resourceType", resource.ResourceType, "resourceId", resource.ResourceId, "error", err)
+	}
+	return err
+}
+
+func (impl *ScimServiceImpl) checkManagerAuth(resource, token string, object string) bool {
+	return impl.enforcer.Enforce(token, resource, casbin.ActionUpdate, strings.ToLower(object))
+}
+
+// adaptUser builds the SCIM user, groups are listed for active users which are members of linked role groups
+func adaptUser(model *repository.UserModel, resource *repository.ScimResource, linkedRoleGroups []*linkedRoleGroup) *User {
+	active := model.Active
+	scimUser := &User{
+		Schemas:  []string{SchemaUser},
+		Id:       strconv.Itoa(int(model.Id)),
+		UserName: model.EmailId,
+		Emails:   []Email{{Value: model.EmailId, Type: "work", Primary: true}},
+		Active:   &active,
+		Meta:     getMeta(ResourceTypeUser, model.AuditLog, resource),
+	}
+	if resource != nil {
+		scimUser.ExternalId = resource.ExternalId
+		scimUser.DisplayName = resource.DisplayName
+		if len(resource.GivenName) > 0 || len(resource.FamilyName) > 0 {
+			scimUser.Name = &Name{GivenName: resource.GivenName, FamilyName: resource.FamilyName}
+		}
+	}
+	if active && len(linkedRoleGroups) > 0 {
+		roles, _ := casbin.GetRolesForUser(model.EmailId)
+		for _, role := range roles {
+			for _, linkedRoleGroup := range linkedRoleGroups {
+				if strings.EqualFold(role, linkedRoleGroup.roleGroup.CasbinName) {
+					scimUser.Groups = append(scimUser.Groups, Reference{Value: strconv.Itoa(int(linkedRoleGroup.roleGroup.Id)),
+						Display: linkedRoleGroup.roleGroup.Name})
+				}
+			}
+		}
+	}
+	return scimUser
+}
+
+func getMeta(resourceType string, auditLog sql.AuditLog, resource *repository.ScimResource) *Meta {
+	created := auditLog.CreatedOn
+	lastModified := auditLog.UpdatedOn
+	if resource != nil && resource.UpdatedOn.After(lastModified) {
+		lastModified = resource.UpdatedOn
+	}
+	return &Meta{ResourceType: resourceType, Created: &created, LastModified: &lastModified}
+}
+
+// getUserAttributes returns the attributes a user can be filtered on
+func getUserAttributes(scimUser *User) map[string][]string {
+	attributes := map[string][]string{
+		"id":                {scimUser.Id},
+		"externalid":        {scimUser.ExternalId},
+		"username":          {scimUser.UserName},
+		"displayname":       {scimUser.DisplayName},
+		"active":            {strconv.FormatBool(scimUser.Active != nil && *scimUser.Active)},
+		"meta.lastmodified": {scimUser.Meta.LastModified.UTC().Format(time.RFC3339)},
+	}
+	if scimUser.Name != nil {
+		attributes["name.givenname"] = []string{scimUser.Name.GivenName}
+		attributes["name.familyname"] = []string{scimUser.Name.FamilyName}
+	}
+	for _, email := range scimUser.Emails {
+		attributes["emails"] = append(attributes["emails"], email.Value)
+		attributes["emails.value"] = append(attributes["emails.value"], email.Value)
+	}
+	for _, group := range scimUser.Groups {
+		attributes["groups"] = append(attributes["groups"], group.Value)
+		attributes["groups.value"] = append(attributes["groups.value"], group.Value)
+	}
+	return attributes
+}
+
+// getGroupAttributes returns the attributes a group can be filtered on
+func getGroupAttributes(scimGroup *Group) map[string][]string {
+	attributes := map[string][]string{
+		"id":                {scimGroup.Id},
+		"externalid":        {scimGroup.ExternalId},
+		"displayname":       {scimGroup.DisplayName},
+		"meta.lastmodified": {scimGroup.Meta.LastModified.UTC().Format(time.RFC3339)},
+	}
+	for _, member := range scimGroup.Members {
+		attributes["members"] = append(attributes["members"], member.Value)
+		attributes["members.value"] = append(attributes["members.value"], member.Value)
+	}
+	return attributes
+}
+
+// paginate pages the resources with the 1-based startIndex of SCIM
+func paginate(resources []interface{}, startIndex int, count int) *ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	page := make([]interface{}, 0)
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = append(page, resources[startIndex-1:end]...)
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
@@ -0,0 +1,252 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Filter is a parsed SCIM filter expression like userName eq "jane@example.com" and active eq true. Attribute names
+// and string values are compared case insensitively, which is what identity providers expect for the attributes
+// devtron exposes
+type Filter struct {
+	root *filterNode
+}
+
+type filterNode struct {
+	// op is and, or, not or one of the attribute operators
+	op       string
+	attr     string
+	value    string
+	children []*filterNode
+}
+
+var attributeOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "pr": true, "gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// ParseFilter parses a filter, an empty filter matches every resource
+func ParseFilter(filter string) (*Filter, error) {
+	if len(strings.TrimSpace(filter)) == 0 {
+		return &Filter{}, nil
+	}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	parser := &filterParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position < len(parser.tokens) {
+		return nil, invalidFilter(fmt.Sprintf("unexpected %s", parser.tokens[parser.position]))
+	}
+	return &Filter{root: root}, nil
+}
+
+// Matches evaluates the filter on the attributes of a resource, keyed by the lower cased attribute path like
+// name.givenname or members.value
+func (f *Filter) Matches(attributes map[string][]string) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.matches(attributes)
+}
+
+func (node *filterNode) matches(attributes map[string][]string) bool {
+	switch node.op {
+	case "and":
+		return node.children[0].matches(attributes) && node.children[1].matches(attributes)
+	case "or":
+		return node.children[0].matches(attributes) || node.children[1].matches(attributes)
+	case "not":
+		return !node.children[0].matches(attributes)
+	}
+	values := attributes[node.attr]
+	if node.op == "pr" {
+		for _, value := range values {
+			if len(value) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if node.op == "ne" {
+		for _, value := range values {
+			if strings.EqualFold(value, node.value) {
+				return false
+			}
+		}
+		return true
+	}
+	expected := strings.ToLower(node.value)
+	for _, value := range values {
+		value = strings.ToLower(value)
+		var matched bool
+		switch node.op {
+		case "eq":
+			matched = value == expected
+		case "co":
+			matched = strings.Contains(value, expected)
+		case "sw":
+			matched = strings.HasPrefix(value, expected)
+		case "ew":
+			matched = strings.HasSuffix(value, expected)
+		case "gt":
+			matched = value > expected
+		case "ge":
+			matched = value >= expected
+		case "lt":
+			matched = value < expected
+		case "le":
+			matched = value <= expected
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+type filterParser struct {
+	tokens   []string
+	position int
+}
+
+func (p *filterParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	token := p.peek()
+	p.position++
+	return token
+}
+
+func (p *filterParser) parseOr() (*filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterNode{op: "or", children: []*filterNode{left, right}}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (*filterNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterNode{op: "and", children: []*filterNode{left, right}}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseFactor() (*filterNode, error) {
+	token := p.next()
+	switch {
+	case len(token) == 0:
+		return nil, invalidFilter("unexpected end of filter")
+	case strings.EqualFold(token, "not"):
+		child, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNode{op: "not", children: []*filterNode{child}}, nil
+	case token == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, invalidFilter("missing closing parenthesis")
+		}
+		return node, nil
+	case token == ")" || token[0] == '"':
+		return nil, invalidFilter(fmt.Sprintf("unexpected %s", token))
+	}
+	attr := strings.ToLower(token)
+	if strings.Contains(attr, ":") {
+		// attributes may be prefixed with the schema urn, the core schemas are the only ones supported
+		attr = attr[strings.LastIndex(attr, ":")+1:]
+	}
+	op := strings.ToLower(p.next())
+	if !attributeOperators[op] {
+		return nil, invalidFilter(fmt.Sprintf("unsupported operator %s for %s", op, token))
+	}
+	node := &filterNode{op: op, attr: attr}
+	if op == "pr" {
+		return node, nil
+	}
+	value := p.next()
+	if len(value) == 0 || value == "(" || value == ")" {
+		return nil, invalidFilter(fmt.Sprintf("missing value for %s", token))
+	}
+	if value[0] == '"' {
+		err := json.Unmarshal([]byte(value), &node.value)
+		if err != nil {
+			return nil, invalidFilter(fmt.Sprintf("invalid value %s", value))
+		}
+	} else {
+		// true, false, null and numbers
+		node.value = strings.ToLower(value)
+	}
+	return node, nil
+}
+
+func tokenizeFilter(filter string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(filter) && filter[end] != '"'; end++ {
+				if filter[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(filter) {
+				return nil, invalidFilter("unterminated string")
+			}
+			tokens = append(tokens, filter[i:end+1])
+			i = end + 1
+		case c == '[' || c == ']':
+			return nil, invalidFilter("value path filters are not supported")
+		default:
+			end := i
+			for ; end < len(filter) && !strings.ContainsRune(" \t\n\r()\"[]", rune(filter[end])); end++ {
+			}
+			tokens = append(tokens, filter[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func invalidFilter(detail string) *Error {
+	return NewError(http.StatusBadRequest, ScimTypeInvalidFilter, detail)
+}
@@ -0,0 +1,52 @@
+package scim
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	attributes := map[string][]string{
+		"username":       {"Jane.Doe@example.com"},
+		"active":         {"true"},
+		"name.givenname": {"Jane"},
+		"externalid":     {""},
+		"groups.value":   {"3", "7"},
+	}
+	matching := []string{
+		``,
+		`userName eq "jane.doe@example.com"`,
+		`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "jane.doe@example.com"`,
+		`userName sw "jane" and active eq true`,
+		`name.givenName co "AN" and (groups.value eq "9" or groups.value eq "7")`,
+		`not (userName ew "@example.org")`,
+		`userName pr and externalId ne "abc"`,
+	}
+	for _, filter := range matching {
+		parsedFilter, err := ParseFilter(filter)
+		assert.Nil(t, err, filter)
+		assert.True(t, parsedFilter.Matches(attributes), filter)
+	}
+	notMatching := []string{
+		`userName eq "john@example.com"`,
+		`active eq false`,
+		`externalId pr`,
+		`userName sw "jane" and groups.value eq "9"`,
+	}
+	for _, filter := range notMatching {
+		parsedFilter, err := ParseFilter(filter)
+		assert.Nil(t, err, filter)
+		assert.False(t, parsedFilter.Matches(attributes), filter)
+	}
+	invalid := []string{
+		`userName eq`,
+		`userName like "jane"`,
+		`(userName eq "jane"`,
+		`userName eq "jane`,
+		`emails[type eq "work"]`,
+	}
+	for _, filter := range invalid {
+		_, err := ParseFilter(filter)
+		assert.NotNil(t, err, filter)
+	}
+}
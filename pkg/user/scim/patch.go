@@ -0,0 +1,297 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// applyUserPatch applies the operations of a PATCH request on the user. Operations are matched case insensitively as
+// identity providers differ in casing, emails and groups are read only here as the email is the userName and group
+// membership is managed through the Group resource
+func applyUserPatch(user *User, operations []PatchOperation) error {
+	for _, operation := range operations {
+		op, err := getPatchOp(operation)
+		if err != nil {
+			return err
+		}
+		path := trimSchema(operation.Path, SchemaUser)
+		if len(path) == 0 {
+			attributes, err := getPathlessAttributes(op, operation.Value)
+			if err != nil {
+				return err
+			}
+			for attr, value := range attributes {
+				err = setUserAttribute(user, trimSchema(attr, SchemaUser), value)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		value := operation.Value
+		if op == PatchOpRemove {
+			value = nil
+		}
+		err = setUserAttribute(user, path, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUserAttribute sets the attribute on the user, a nil value removes it
+func setUserAttribute(user *User, attr string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(attr) {
+	case "active":
+		if value == nil {
+			return NewError(http.StatusBadRequest, ScimTypeMutability, "active can not be removed")
+		}
+		var active bool
+		active, err = decodeBool(value)
+		user.Active = &active
+	case "username":
+		var userName string
+		userName, err = decodeString(value)
+		if err == nil && !strings.EqualFold(strings.TrimSpace(userName), user.UserName) {
+			return NewError(http.StatusBadRequest, ScimTypeMutability, "userName can not be changed")
+		}
+	case "externalid":
+		user.ExternalId, err = decodeString(value)
+	case "displayname":
+		user.DisplayName, err = decodeString(value)
+	case "name":
+		user.Name = nil
+		if value != nil {
+			user.Name = &Name{}
+			err = json.Unmarshal(value, user.Name)
+		}
+	case "name.givenname":
+		user.Name = getName(user)
+		user.Name.GivenName, err = decodeString(value)
+	case "name.familyname":
+		user.Name = getName(user)
+		user.Name.FamilyName, err = decodeString(value)
+	case "name.formatted":
+		user.Name = getName(user)
+		user.Name.Formatted, err = decodeString(value)
+	default:
+		lowerAttr := strings.ToLower(attr)
+		if strings.HasPrefix(lowerAttr, "emails") || strings.HasPrefix(lowerAttr, "groups") ||
+			strings.HasPrefix(lowerAttr, "urn:") {
+			// emails and groups are derived, attributes of extension schemas are not kept
+			return nil
+		}
+		return NewError(http.StatusBadRequest, ScimTypeInvalidPath, fmt.Sprintf("unsupported attribute %s", attr))
+	}
+	if err != nil {
+		return NewError(http.StatusBadRequest, ScimTypeInvalidValue, fmt.Sprintf("invalid value for %s", attr))
+	}
+	return nil
+}
+
+// applyGroupPatch applies the operations of a PATCH request on the group, members are added and removed by user id
+func applyGroupPatch(group *Group, operations []PatchOperation) error {
+	for _, operation := range operations {
+		op, err := getPatchOp(operation)
+		if err != nil {
+			return err
+		}
+		path := trimSchema(operation.Path, SchemaGroup)
+		if len(path) == 0 {
+			attributes, err := getPathlessAttributes(op, operation.Value)
+			if err != nil {
+				return err
+			}
+			for attr, value := range attributes {
+				err = setGroupAttribute(group, op, trimSchema(attr, SchemaGroup), value)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		err = setGroupAttribute(group, op, path, operation.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setGroupAttribute(group *Group, op string, attr string, value json.RawMessage) error {
+	lowerAttr := strings.ToLower(attr)
+	if strings.HasPrefix(lowerAttr, "members[") {
+		return removeFilteredMembers(group, op, attr)
+	}
+	if op == PatchOpRemove && lowerAttr != "members" {
+		value = nil
+	}
+	var err error
+	switch lowerAttr {
+	case "displayname":
+		if value == nil {
+			return NewError(http.StatusBadRequest, ScimTypeMutability, "displayName can not be removed")
+		}
+		group.DisplayName, err = decodeString(value)
+	case "externalid":
+		group.ExternalId, err = decodeString(value)
+	case "members":
+		var members []Reference
+		if value != nil {
+			members, err = decodeMembers(value)
+			if err != nil {
+				break
+			}
+		}
+		switch op {
+		case PatchOpAdd:
+			group.Members = mergeMembers(group.Members, members)
+		case PatchOpReplace:
+			group.Members = mergeMembers(nil, members)
+		case PatchOpRemove:
+			if value == nil {
+				group.Members = nil
+			} else {
+				group.Members = removeMembers(group.Members, func(member Reference) bool {
+					return containsMember(members, member.Value)
+				})
+			}
+		}
+	default:
+		return NewError(http.StatusBadRequest, ScimTypeInvalidPath, fmt.Sprintf("unsupported attribute %s", attr))
+	}
+	if err != nil {
+		return NewError(http.StatusBadRequest, ScimTypeInvalidValue, fmt.Sprintf("invalid value for %s", attr))
+	}
+	return nil
+}
+
+// removeFilteredMembers handles paths like members[value eq "42"], used by identity providers to remove a single member
+func removeFilteredMembers(group *Group, op string, path string) error {
+	start := strings.Index(path, "[")
+	end := strings.LastIndex(path, "]")
+	if end < start || end != len(path)-1 {
+		return NewError(http.StatusBadRequest, ScimTypeInvalidPath, fmt.Sprintf("unsupported path %s", path))
+	}
+	if op != PatchOpRemove {
+		return NewError(http.StatusBadRequest, ScimTypeInvalidPath, fmt.Sprintf("only remove is supported for %s", path))
+	}
+	filter, err := ParseFilter(path[start+1 : end])
+	if err != nil {
+		return err
+	}
+	group.Members = removeMembers(group.Members, func(member Reference) bool {
+		return filter.Matches(map[string][]string{"value": {member.Value}, "display": {member.Display}})
+	})
+	return nil
+}
+
+func getPatchOp(operation PatchOperation) (string, error) {
+	op := strings.ToLower(operation.Op)
+	if op != PatchOpAdd && op != PatchOpReplace && op != PatchOpRemove {
+		return "", NewError(http.StatusBadRequest, ScimTypeInvalidValue, fmt.Sprintf("unsupported op %s", operation.Op))
+	}
+	return op, nil
+}
+
+// getPathlessAttributes returns the attributes of an add or replace operation without path, where the value holds
+// the attributes to set
+func getPathlessAttributes(op string, value json.RawMessage) (map[string]json.RawMessage, error) {
+	if op == PatchOpRemove {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidPath, "path is required for remove")
+	}
+	attributes := make(map[string]json.RawMessage)
+	err := json.Unmarshal(value, &attributes)
+	if err != nil {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "value of an operation without path must be an object")
+	}
+	return attributes, nil
+}
+
+// trimSchema drops the schema urn prefix from a path, like urn:ietf:params:scim:schemas:core:2.0:User:active
+func trimSchema(path string, schema string) string {
+	path = strings.TrimSpace(path)
+	if len(path) > len(schema) && strings.EqualFold(path[:len(schema)+1], schema+":") {
+		return path[len(schema)+1:]
+	}
+	return path
+}
+
+func getName(user *User) *Name {
+	if user.Name == nil {
+		return &Name{}
+	}
+	return user.Name
+}
+
+func decodeString(value json.RawMessage) (string, error) {
+	if value == nil || string(value) == "null" {
+		return "", nil
+	}
+	var s string
+	err := json.Unmarshal(value, &s)
+	return s, err
+}
+
+// decodeBool accepts strings as well, some identity providers send booleans like "False"
+func decodeBool(value json.RawMessage) (bool, error) {
+	var b bool
+	err := json.Unmarshal(value, &b)
+	if err == nil {
+		return b, nil
+	}
+	var s string
+	err = json.Unmarshal(value, &s)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// decodeMembers accepts a list of members or a single one
+func decodeMembers(value json.RawMessage) ([]Reference, error) {
+	var members []Reference
+	err := json.Unmarshal(value, &members)
+	if err == nil {
+		return members, nil
+	}
+	member := Reference{}
+	err = json.Unmarshal(value, &member)
+	if err != nil {
+		return nil, err
+	}
+	return []Reference{member}, nil
+}
+
+func mergeMembers(members []Reference, newMembers []Reference) []Reference {
+	for _, member := range newMembers {
+		if len(member.Value) > 0 && !containsMember(members, member.Value) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+func removeMembers(members []Reference, remove func(member Reference) bool) []Reference {
+	var remaining []Reference
+	for _, member := range members {
+		if !remove(member) {
+			remaining = append(remaining, member)
+		}
+	}
+	return remaining
+}
+
+func containsMember(members []Reference, value string) bool {
+	for _, member := range members {
+		if member.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package scim
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	active := true
+	user := &User{UserName: "jane@example.com", Active: &active}
+	var patch PatchRequest
+	err := json.Unmarshal([]byte(`{"Operations": [
+		{"op": "Replace", "path": "active", "value": "False"},
+		{"op": "add", "value": {"displayName": "Jane Doe", "name.givenName": "Jane"}},
+		{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "jane@example.com"},
+		{"op": "replace", "path": "urn:ietf:params:scim:schemas:core:2.0:User:externalId", "value": "00u1"}
+	]}`), &patch)
+	assert.Nil(t, err)
+	err = applyUserPatch(user, patch.Operations)
+	assert.Nil(t, err)
+	assert.False(t, *user.Active)
+	assert.Equal(t, "Jane Doe", user.DisplayName)
+	assert.Equal(t, "Jane", user.Name.GivenName)
+	assert.Equal(t, "00u1", user.ExternalId)
+
+	err = applyUserPatch(user, []PatchOperation{{Op: "replace", Path: "userName", Value: json.RawMessage(`"john@example.com"`)}})
+	assert.NotNil(t, err)
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	group := &Group{DisplayName: "developers", Members: []Reference{{Value: "1"}, {Value: "2"}}}
+	var patch PatchRequest
+	err := json.Unmarshal([]byte(`{"Operations": [
+		{"op": "add", "path": "members", "value": [{"value": "3"}, {"value": "1"}]},
+		{"op": "remove", "path": "members[value eq \"2\"]"},
+		{"op": "replace", "value": {"displayName": "platform developers"}}
+	]}`), &patch)
+	assert.Nil(t, err)
+	err = applyGroupPatch(group, patch.Operations)
+	assert.Nil(t, err)
+	assert.Equal(t, "platform developers", group.DisplayName)
+	assert.Equal(t, []Reference{{Value: "1"}, {Value: "3"}}, group.Members)
+
+	err = applyGroupPatch(group, []PatchOperation{{Op: "remove", Path: "members", Value: json.RawMessage(`[{"value": "1"}]`)}})
+	assert.Nil(t, err)
+	assert.Equal(t, []Reference{{Value: "3"}}, group.Members)
+
+	err = applyGroupPatch(group, []PatchOperation{{Op: "remove", Path: "members"}})
+	assert.Nil(t, err)
+	assert.Empty(t, group.Members)
+}
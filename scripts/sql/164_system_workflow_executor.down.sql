@@ -0,0 +1,5 @@
+ALTER TABLE cluster
+    DROP COLUMN IF EXISTS workflow_executor_type;
+
+ALTER TABLE ci_workflow
+    DROP COLUMN IF EXISTS executor_type;
@@ -0,0 +1,5 @@
+ALTER TABLE cluster
+    ADD COLUMN IF NOT EXISTS workflow_executor_type varchar(50);
+
+ALTER TABLE ci_workflow
+    ADD COLUMN IF NOT EXISTS executor_type varchar(50);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "public"."ci_pipeline_schedule_run";
+DROP SEQUENCE IF EXISTS id_seq_ci_pipeline_schedule_run;
+DROP TABLE IF EXISTS "public"."ci_pipeline_schedule";
+DROP SEQUENCE IF EXISTS id_seq_ci_pipeline_schedule;
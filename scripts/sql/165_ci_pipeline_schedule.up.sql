@@ -0,0 +1,44 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_schedule;
+
+CREATE TABLE IF NOT EXISTS "public"."ci_pipeline_schedule"
+(
+    "id"              integer      NOT NULL DEFAULT nextval('id_seq_ci_pipeline_schedule'::regclass),
+    "ci_pipeline_id"  integer      NOT NULL,
+    "environment_id"  integer,
+    "cron_expression" varchar(100) NOT NULL,
+    "timezone"        varchar(100) NOT NULL,
+    "commit_policy"   varchar(50)  NOT NULL,
+    "catch_up_policy" varchar(50)  NOT NULL,
+    "paused"          bool         NOT NULL DEFAULT FALSE,
+    "next_run_at"     timestamptz  NOT NULL,
+    "last_run_at"     timestamptz,
+    "active"          bool         NOT NULL,
+    "created_on"      timestamptz  NOT NULL,
+    "created_by"      integer      NOT NULL,
+    "updated_on"      timestamptz  NOT NULL,
+    "updated_by"      integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "ci_pipeline_schedule_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "ci_pipeline_schedule_active_ci_pipeline_id_key"
+    ON "public"."ci_pipeline_schedule" ("ci_pipeline_id") WHERE "active" = TRUE;
+
+CREATE INDEX IF NOT EXISTS "ci_pipeline_schedule_next_run_at_idx"
+    ON "public"."ci_pipeline_schedule" ("next_run_at") WHERE "active" = TRUE AND "paused" = FALSE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_schedule_run;
+
+CREATE TABLE IF NOT EXISTS "public"."ci_pipeline_schedule_run"
+(
+    "id"                      integer     NOT NULL DEFAULT nextval('id_seq_ci_pipeline_schedule_run'::regclass),
+    "ci_pipeline_schedule_id" integer     NOT NULL,
+    "ci_workflow_id"          integer,
+    "scheduled_at"            timestamptz NOT NULL,
+    "triggered_at"            timestamptz NOT NULL,
+    "triggered_by"            integer     NOT NULL,
+    "status"                  varchar(50) NOT NULL,
+    "message"                 text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "ci_pipeline_schedule_run_schedule_id_fkey" FOREIGN KEY ("ci_pipeline_schedule_id") REFERENCES "public"."ci_pipeline_schedule" ("id")
+);
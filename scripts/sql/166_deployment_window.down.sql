@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "public"."scheduled_deployment";
+DROP SEQUENCE IF EXISTS id_seq_scheduled_deployment;
+DROP TABLE IF EXISTS "public"."deployment_window_override_audit";
+DROP SEQUENCE IF EXISTS id_seq_deployment_window_override_audit;
+DROP TABLE IF EXISTS "public"."deployment_window";
+DROP SEQUENCE IF EXISTS id_seq_deployment_window;
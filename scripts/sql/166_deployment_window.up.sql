@@ -0,0 +1,66 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window;
+
+CREATE TABLE IF NOT EXISTS "public"."deployment_window"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_deployment_window'::regclass),
+    "environment_id" integer      NOT NULL,
+    "name"           varchar(250) NOT NULL,
+    "window_type"    varchar(50)  NOT NULL,
+    "weekdays"       integer[],
+    "start_time"     varchar(5),
+    "end_time"       varchar(5),
+    "timezone"       varchar(100),
+    "start_at"       timestamptz,
+    "end_at"         timestamptz,
+    "description"    text,
+    "active"         bool         NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "deployment_window_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "deployment_window_environment_id_idx"
+    ON "public"."deployment_window" ("environment_id") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window_override_audit;
+
+CREATE TABLE IF NOT EXISTS "public"."deployment_window_override_audit"
+(
+    "id"             integer     NOT NULL DEFAULT nextval('id_seq_deployment_window_override_audit'::regclass),
+    "pipeline_id"    integer     NOT NULL,
+    "environment_id" integer     NOT NULL,
+    "ci_artifact_id" integer,
+    "blocked_reason" text,
+    "reason"         text,
+    "overridden_by"  integer     NOT NULL,
+    "overridden_on"  timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_scheduled_deployment;
+
+CREATE TABLE IF NOT EXISTS "public"."scheduled_deployment"
+(
+    "id"              integer     NOT NULL DEFAULT nextval('id_seq_scheduled_deployment'::regclass),
+    "pipeline_id"     integer     NOT NULL,
+    "ci_artifact_id"  integer     NOT NULL,
+    "cd_workflow_id"  integer,
+    "deployment_type" varchar(50) NOT NULL,
+    "scheduled_at"    timestamptz NOT NULL,
+    "status"          varchar(50) NOT NULL,
+    "message"         text,
+    "triggered_by"    integer     NOT NULL,
+    "created_on"      timestamptz NOT NULL,
+    "created_by"      integer     NOT NULL,
+    "updated_on"      timestamptz NOT NULL,
+    "updated_by"      integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "scheduled_deployment_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "scheduled_deployment_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "scheduled_deployment_pending_idx"
+    ON "public"."scheduled_deployment" ("scheduled_at") WHERE "status" = 'PENDING';
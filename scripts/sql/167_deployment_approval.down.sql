@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "public"."deployment_approval_user_data";
+DROP SEQUENCE IF EXISTS id_seq_deployment_approval_user_data;
+DROP TABLE IF EXISTS "public"."deployment_approval_request";
+DROP SEQUENCE IF EXISTS id_seq_deployment_approval_request;
+DROP TABLE IF EXISTS "public"."deployment_approval_config";
+DROP SEQUENCE IF EXISTS id_seq_deployment_approval_config;
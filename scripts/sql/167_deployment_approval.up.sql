@@ -0,0 +1,63 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_approval_config;
+
+CREATE TABLE IF NOT EXISTS "public"."deployment_approval_config"
+(
+    "id"                 integer     NOT NULL DEFAULT nextval('id_seq_deployment_approval_config'::regclass),
+    "pipeline_id"        integer     NOT NULL,
+    "required_approvals" integer     NOT NULL,
+    "approver_group_ids" integer[],
+    "expiry_in_hours"    integer     NOT NULL,
+    "active"             bool        NOT NULL,
+    "created_on"         timestamptz NOT NULL,
+    "created_by"         integer     NOT NULL,
+    "updated_on"         timestamptz NOT NULL,
+    "updated_by"         integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "deployment_approval_config_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "deployment_approval_config_active_pipeline_id_key"
+    ON "public"."deployment_approval_config" ("pipeline_id") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_approval_request;
+
+CREATE TABLE IF NOT EXISTS "public"."deployment_approval_request"
+(
+    "id"                    integer     NOT NULL DEFAULT nextval('id_seq_deployment_approval_request'::regclass),
+    "pipeline_id"           integer     NOT NULL,
+    "ci_artifact_id"        integer     NOT NULL,
+    "cd_workflow_runner_id" integer,
+    "status"                varchar(50) NOT NULL,
+    "required_approvals"    integer     NOT NULL,
+    "comment"               text,
+    "expires_at"            timestamptz NOT NULL,
+    "requested_by"          integer     NOT NULL,
+    "created_on"            timestamptz NOT NULL,
+    "created_by"            integer     NOT NULL,
+    "updated_on"            timestamptz NOT NULL,
+    "updated_by"            integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "deployment_approval_request_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "deployment_approval_request_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "deployment_approval_request_pipeline_id_ci_artifact_id_idx"
+    ON "public"."deployment_approval_request" ("pipeline_id", "ci_artifact_id");
+
+CREATE INDEX IF NOT EXISTS "deployment_approval_request_cd_workflow_runner_id_idx"
+    ON "public"."deployment_approval_request" ("cd_workflow_runner_id");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_approval_user_data;
+
+CREATE TABLE IF NOT EXISTS "public"."deployment_approval_user_data"
+(
+    "id"                  integer     NOT NULL DEFAULT nextval('id_seq_deployment_approval_user_data'::regclass),
+    "approval_request_id" integer     NOT NULL,
+    "user_id"             integer     NOT NULL,
+    "action"              varchar(50) NOT NULL,
+    "comment"             text,
+    "acted_on"            timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "deployment_approval_user_data_approval_request_id_fkey" FOREIGN KEY ("approval_request_id") REFERENCES "public"."deployment_approval_request" ("id"),
+    CONSTRAINT "deployment_approval_user_data_request_user_key" UNIQUE ("approval_request_id", "user_id")
+);
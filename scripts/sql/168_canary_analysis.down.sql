@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "public"."canary_analysis_result";
+DROP SEQUENCE IF EXISTS id_seq_canary_analysis_result;
+DROP TABLE IF EXISTS "public"."canary_analysis_run";
+DROP SEQUENCE IF EXISTS id_seq_canary_analysis_run;
+DROP TABLE IF EXISTS "public"."canary_analysis_metric";
+DROP SEQUENCE IF EXISTS id_seq_canary_analysis_metric;
+DROP TABLE IF EXISTS "public"."canary_analysis_config";
+DROP SEQUENCE IF EXISTS id_seq_canary_analysis_config;
@@ -0,0 +1,92 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_config;
+
+CREATE TABLE IF NOT EXISTS "public"."canary_analysis_config"
+(
+    "id"                       integer     NOT NULL DEFAULT nextval('id_seq_canary_analysis_config'::regclass),
+    "pipeline_id"              integer     NOT NULL,
+    "initial_delay_in_seconds" integer     NOT NULL,
+    "interval_in_seconds"      integer     NOT NULL,
+    "evaluation_count"         integer     NOT NULL,
+    "failure_limit"            integer     NOT NULL,
+    "auto_rollback"            bool        NOT NULL,
+    "active"                   bool        NOT NULL,
+    "created_on"               timestamptz NOT NULL,
+    "created_by"               integer     NOT NULL,
+    "updated_on"               timestamptz NOT NULL,
+    "updated_by"               integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "canary_analysis_config_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "canary_analysis_config_active_pipeline_id_key"
+    ON "public"."canary_analysis_config" ("pipeline_id") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_metric;
+
+CREATE TABLE IF NOT EXISTS "public"."canary_analysis_metric"
+(
+    "id"         integer          NOT NULL DEFAULT nextval('id_seq_canary_analysis_metric'::regclass),
+    "config_id"  integer          NOT NULL,
+    "name"       varchar(250)     NOT NULL,
+    "query"      text             NOT NULL,
+    "operator"   varchar(5)       NOT NULL,
+    "threshold"  double precision NOT NULL,
+    "deleted"    bool             NOT NULL,
+    "created_on" timestamptz      NOT NULL,
+    "created_by" integer          NOT NULL,
+    "updated_on" timestamptz      NOT NULL,
+    "updated_by" integer          NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "canary_analysis_metric_config_id_fkey" FOREIGN KEY ("config_id") REFERENCES "public"."canary_analysis_config" ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_run;
+
+CREATE TABLE IF NOT EXISTS "public"."canary_analysis_run"
+(
+    "id"                             integer     NOT NULL DEFAULT nextval('id_seq_canary_analysis_run'::regclass),
+    "pipeline_id"                    integer     NOT NULL,
+    "config_id"                      integer     NOT NULL,
+    "cd_workflow_runner_id"          integer     NOT NULL,
+    "pipeline_override_id"           integer     NOT NULL,
+    "rollback_cd_workflow_runner_id" integer,
+    "rollback_pipeline_override_id"  integer,
+    "rollback_runner_id"             integer,
+    "status"                         varchar(50) NOT NULL,
+    "evaluations_done"               integer     NOT NULL,
+    "failed_evaluations"             integer     NOT NULL,
+    "next_evaluation_at"             timestamptz,
+    "finished_on"                    timestamptz,
+    "message"                        text,
+    "created_on"                     timestamptz NOT NULL,
+    "created_by"                     integer     NOT NULL,
+    "updated_on"                     timestamptz NOT NULL,
+    "updated_by"                     integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "canary_analysis_run_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "canary_analysis_run_config_id_fkey" FOREIGN KEY ("config_id") REFERENCES "public"."canary_analysis_config" ("id"),
+    CONSTRAINT "canary_analysis_run_cd_workflow_runner_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "canary_analysis_run_status_next_evaluation_at_idx"
+    ON "public"."canary_analysis_run" ("status", "next_evaluation_at");
+
+CREATE INDEX IF NOT EXISTS "canary_analysis_run_rollback_runner_id_idx"
+    ON "public"."canary_analysis_run" ("rollback_runner_id");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_result;
+
+CREATE TABLE IF NOT EXISTS "public"."canary_analysis_result"
+(
+    "id"           integer          NOT NULL DEFAULT nextval('id_seq_canary_analysis_result'::regclass),
+    "run_id"       integer          NOT NULL,
+    "metric_id"    integer          NOT NULL,
+    "metric_name"  varchar(250)     NOT NULL,
+    "evaluation"   integer          NOT NULL,
+    "value"        double precision,
+    "passed"       bool             NOT NULL,
+    "error"        text,
+    "evaluated_on" timestamptz      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "canary_analysis_result_run_id_fkey" FOREIGN KEY ("run_id") REFERENCES "public"."canary_analysis_run" ("id")
+);
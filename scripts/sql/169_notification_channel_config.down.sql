@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."notification_channel_config";
+DROP SEQUENCE IF EXISTS id_seq_notification_channel_config;
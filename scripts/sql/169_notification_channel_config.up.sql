@@ -0,0 +1,20 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_channel_config;
+
+CREATE TABLE IF NOT EXISTS "public"."notification_channel_config"
+(
+    "id"           integer      NOT NULL DEFAULT nextval('id_seq_notification_channel_config'::regclass),
+    "channel_type" varchar(50)  NOT NULL,
+    "config_name"  varchar(250) NOT NULL,
+    "description"  text,
+    "config"       jsonb        NOT NULL,
+    "owner_id"     integer,
+    "deleted"      bool         NOT NULL DEFAULT FALSE,
+    "created_on"   timestamptz  NOT NULL,
+    "created_by"   integer      NOT NULL,
+    "updated_on"   timestamptz  NOT NULL,
+    "updated_by"   integer      NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "notification_channel_config_channel_type_idx"
+    ON "public"."notification_channel_config" ("channel_type") WHERE "deleted" = FALSE;
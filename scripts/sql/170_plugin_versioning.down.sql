@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "public"."plugin_metadata_plugin_identifier_idx";
+
+ALTER TABLE "public"."plugin_metadata"
+    DROP COLUMN IF EXISTS "plugin_identifier",
+    DROP COLUMN IF EXISTS "plugin_version",
+    DROP COLUMN IF EXISTS "is_latest",
+    DROP COLUMN IF EXISTS "is_deprecated";
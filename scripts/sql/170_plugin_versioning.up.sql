@@ -0,0 +1,26 @@
+ALTER TABLE "public"."plugin_metadata"
+    ADD COLUMN IF NOT EXISTS "plugin_identifier" varchar(250),
+    ADD COLUMN IF NOT EXISTS "plugin_version"    varchar(50) NOT NULL DEFAULT '1.0.0',
+    ADD COLUMN IF NOT EXISTS "is_latest"         bool        NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS "is_deprecated"     bool        NOT NULL DEFAULT FALSE;
+
+-- existing plugins become version 1.0.0 of an identifier derived from their name
+UPDATE "public"."plugin_metadata"
+SET "plugin_identifier" = trim(BOTH '-' FROM lower(regexp_replace("name", '[^a-zA-Z0-9]+', '-', 'g')))
+WHERE "plugin_identifier" IS NULL;
+
+-- names differing only in case or punctuation derive the same identifier, the oldest plugin keeps it and the others
+-- (and names without any letter or digit) get the plugin id appended
+UPDATE "public"."plugin_metadata" pm
+SET "plugin_identifier" = trim(BOTH '-' FROM pm."plugin_identifier" || '-' || pm."id")
+WHERE pm."plugin_identifier" = ''
+   OR EXISTS (SELECT 1
+              FROM "public"."plugin_metadata" other
+              WHERE other."plugin_identifier" = pm."plugin_identifier"
+                AND other."plugin_version" = pm."plugin_version"
+                AND other."deleted" = FALSE
+                AND other."id" < pm."id");
+
+-- published versions are immutable, a version of a plugin can exist only once
+CREATE UNIQUE INDEX IF NOT EXISTS "plugin_metadata_plugin_identifier_idx"
+    ON "public"."plugin_metadata" ("plugin_identifier", "plugin_version") WHERE "deleted" = FALSE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_unique_manifest_push_config_app_env";
+
+ALTER TABLE "public"."manifest_push_config" DROP COLUMN IF EXISTS "deploy_from_oci_chart";
@@ -0,0 +1,8 @@
+ALTER TABLE "public"."manifest_push_config"
+    ADD COLUMN IF NOT EXISTS "deploy_from_oci_chart" bool NOT NULL DEFAULT FALSE;
+
+UPDATE "public"."manifest_push_config" SET "deleted" = FALSE WHERE "deleted" IS NULL;
+
+-- only one active push target per app and environment
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_unique_manifest_push_config_app_env"
+    ON "public"."manifest_push_config" ("app_id", "env_id") WHERE "deleted" = FALSE;
@@ -0,0 +1 @@
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "gitea_org_id";
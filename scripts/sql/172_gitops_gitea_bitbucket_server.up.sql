@@ -0,0 +1,2 @@
+ALTER TABLE "public"."gitops_config"
+    ADD COLUMN IF NOT EXISTS "gitea_org_id" varchar(250);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "public"."image_signing_policy";
+DROP SEQUENCE IF EXISTS id_seq_image_signing_policy;
+DROP TABLE IF EXISTS "public"."image_signature";
+DROP SEQUENCE IF EXISTS id_seq_image_signature;
+DROP TABLE IF EXISTS "public"."image_signing_config";
+DROP SEQUENCE IF EXISTS id_seq_image_signing_config;
+DROP TABLE IF EXISTS "public"."image_signing_key";
+DROP SEQUENCE IF EXISTS id_seq_image_signing_key;
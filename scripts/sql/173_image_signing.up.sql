@@ -0,0 +1,78 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signing_key;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signing_key"
+(
+    "id"          integer      NOT NULL DEFAULT nextval('id_seq_image_signing_key'::regclass),
+    "name"        varchar(250) NOT NULL,
+    "public_key"  text         NOT NULL,
+    "secret_name" varchar(250) NOT NULL,
+    "active"      bool         NOT NULL,
+    "created_on"  timestamptz  NOT NULL,
+    "created_by"  integer      NOT NULL,
+    "updated_on"  timestamptz  NOT NULL,
+    "updated_by"  integer      NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signing_config;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signing_config"
+(
+    "id"                   integer     NOT NULL DEFAULT nextval('id_seq_image_signing_config'::regclass),
+    "ci_pipeline_id"       integer     NOT NULL,
+    "signing_key_id"       integer     NOT NULL,
+    "generate_attestation" bool        NOT NULL,
+    "active"               bool        NOT NULL,
+    "created_on"           timestamptz NOT NULL,
+    "created_by"           integer     NOT NULL,
+    "updated_on"           timestamptz NOT NULL,
+    "updated_by"           integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "image_signing_config_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id"),
+    CONSTRAINT "image_signing_config_signing_key_id_fkey" FOREIGN KEY ("signing_key_id") REFERENCES "public"."image_signing_key" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "image_signing_config_active_ci_pipeline_id_key"
+    ON "public"."image_signing_config" ("ci_pipeline_id") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signature"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_image_signature'::regclass),
+    "ci_artifact_id" integer      NOT NULL,
+    "image_digest"   varchar(250) NOT NULL,
+    "signature_type" varchar(50)  NOT NULL,
+    "signing_key_id" integer,
+    "predicate_type" varchar(250),
+    "payload"        text         NOT NULL,
+    "signature"      text         NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "image_signature_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "image_signature_image_digest_idx"
+    ON "public"."image_signature" ("image_digest");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signing_policy;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signing_policy"
+(
+    "id"             integer     NOT NULL DEFAULT nextval('id_seq_image_signing_policy'::regclass),
+    "global"         bool        NOT NULL,
+    "cluster_id"     integer,
+    "env_id"         integer,
+    "app_id"         integer,
+    "action"         integer     NOT NULL,
+    "signing_key_id" integer,
+    "deleted"        bool        NOT NULL,
+    "created_on"     timestamptz NOT NULL,
+    "created_by"     integer     NOT NULL,
+    "updated_on"     timestamptz NOT NULL,
+    "updated_by"     integer     NOT NULL,
+    PRIMARY KEY ("id")
+);
@@ -0,0 +1,6 @@
+delete from "public"."notification_templates" where event_type_id=5;
+delete from notifier_event_log where event_type_id=5;
+delete from public.event where event_type='CONFIG_DRIFT';
+DROP INDEX IF EXISTS "public"."config_drift_scan_pipeline_id_idx";
+DROP TABLE IF EXISTS "public"."config_drift_scan";
+DROP SEQUENCE IF EXISTS id_seq_config_drift_scan;
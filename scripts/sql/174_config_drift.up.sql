@@ -0,0 +1,71 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_config_drift_scan;
+
+CREATE TABLE IF NOT EXISTS "public"."config_drift_scan"
+(
+    "id"                integer     NOT NULL DEFAULT nextval('id_seq_config_drift_scan'::regclass),
+    "pipeline_id"       integer     NOT NULL,
+    "drifted"           bool        NOT NULL,
+    "drifted_resources" text,
+    "drift_digest"      varchar(64),
+    "notified_digest"   varchar(64),
+    "scan_error"        text,
+    "scanned_on"        timestamptz,
+    "created_on"        timestamptz NOT NULL,
+    "created_by"        integer     NOT NULL,
+    "updated_on"        timestamptz NOT NULL,
+    "updated_by"        integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "config_drift_scan_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "config_drift_scan_pipeline_id_idx" ON "public"."config_drift_scan" ("pipeline_id");
+
+INSERT INTO public.event (id, event_type, description) VALUES (5, 'CONFIG_DRIFT', '');
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CD', 5, 'CD config drift smtp template', '{"from": "{{fromEmail}}",
+"to": "{{toEmail}}","subject": "⚠️ Live resources drifted from deployed state | Application > {{appName}} | Environment > {{envName}}","html": "<table style=\"width: 600px; border-collapse: collapse; padding: 20px;\"><tr style=\"background-color:#FFF8E5;\"><td colspan=\"2\" style=\"padding-left:16px;\"><h2 style=\"color:#000A14;\">Configuration drift detected</h2><span>{{eventTime}}</span><br><br>{{#appDetailLink}}<a href=\"{{&appDetailLink}}\" style=\" height: 32px; padding: 7px 12px; line-height: 32px; font-size: 12px; font-weight: 600; border-radius: 4px; text-decoration: none; outline: none; min-width: 64px; text-align: center; background: #0066CC; color: #fff; border: 1px solid transparent; cursor: pointer;\">View app details</a><br><br>{{/appDetailLink}}</td></tr><tr><td colspan=\"2\"><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Pipeline: <strong>{{pipelineName}}</strong></span><br><br><hr><h3>Drift</h3><span>{{driftSummary}}</span></td></tr></table>"}');
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CD', 5, 'CD config drift ses template', '{"from": "{{fromEmail}}",
+"to": "{{toEmail}}","subject": "⚠️ Live resources drifted from deployed state | Application > {{appName}} | Environment > {{envName}}","html": "<table style=\"width: 600px; border-collapse: collapse; padding: 20px;\"><tr style=\"background-color:#FFF8E5;\"><td colspan=\"2\" style=\"padding-left:16px;\"><h2 style=\"color:#000A14;\">Configuration drift detected</h2><span>{{eventTime}}</span><br><br>{{#appDetailLink}}<a href=\"{{&appDetailLink}}\" style=\" height: 32px; padding: 7px 12px; line-height: 32px; font-size: 12px; font-weight: 600; border-radius: 4px; text-decoration: none; outline: none; min-width: 64px; text-align: center; background: #0066CC; color: #fff; border: 1px solid transparent; cursor: pointer;\">View app details</a><br><br>{{/appDetailLink}}</td></tr><tr><td colspan=\"2\"><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Pipeline: <strong>{{pipelineName}}</strong></span><br><br><hr><h3>Drift</h3><span>{{driftSummary}}</span></td></tr></table>"}');
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CD', 5, 'CD config drift slack template', '{
+    "text": ":warning: Live resources drifted from deployed state | Application > {{appName}} | Environment > {{envName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":warning: *Configuration drift detected*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "section",
+            "fields": [{
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                }
+            ]
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "*Drift*\n{{driftSummary}}"
+            }
+        }{{#appDetailLink}},
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View app details"
+                },
+                "url": "{{& appDetailLink}}"
+            }]
+        }{{/appDetailLink}}
+    ]
+}');
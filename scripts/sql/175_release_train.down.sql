@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "public"."artifact_promotion";
+DROP SEQUENCE IF EXISTS id_seq_artifact_promotion;
+DROP TABLE IF EXISTS "public"."release_train_stage";
+DROP SEQUENCE IF EXISTS id_seq_release_train_stage;
+DROP TABLE IF EXISTS "public"."release_train";
+DROP SEQUENCE IF EXISTS id_seq_release_train;
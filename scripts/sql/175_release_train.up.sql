@@ -0,0 +1,62 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train;
+
+CREATE TABLE IF NOT EXISTS "public"."release_train"
+(
+    "id"          integer      NOT NULL DEFAULT nextval('id_seq_release_train'::regclass),
+    "name"        varchar(50)  NOT NULL,
+    "description" varchar(250),
+    "active"      bool         NOT NULL,
+    "created_on"  timestamptz  NOT NULL,
+    "created_by"  integer      NOT NULL,
+    "updated_on"  timestamptz  NOT NULL,
+    "updated_by"  integer      NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "release_train_name_active_idx" ON "public"."release_train" ("name") WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_release_train_stage;
+
+CREATE TABLE IF NOT EXISTS "public"."release_train_stage"
+(
+    "id"               integer     NOT NULL DEFAULT nextval('id_seq_release_train_stage'::regclass),
+    "release_train_id" integer     NOT NULL,
+    "environment_id"   integer     NOT NULL,
+    "stage_order"      integer     NOT NULL,
+    "policy"           text,
+    "deleted"          bool        NOT NULL,
+    "created_on"       timestamptz NOT NULL,
+    "created_by"       integer     NOT NULL,
+    "updated_on"       timestamptz NOT NULL,
+    "updated_by"       integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "release_train_stage_release_train_id_fkey" FOREIGN KEY ("release_train_id") REFERENCES "public"."release_train" ("id"),
+    CONSTRAINT "release_train_stage_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "release_train_stage_release_train_id_idx" ON "public"."release_train_stage" ("release_train_id") WHERE deleted = false;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_artifact_promotion;
+
+CREATE TABLE IF NOT EXISTS "public"."artifact_promotion"
+(
+    "id"                         integer     NOT NULL DEFAULT nextval('id_seq_artifact_promotion'::regclass),
+    "release_train_id"           integer     NOT NULL,
+    "app_id"                     integer     NOT NULL,
+    "ci_artifact_id"             integer     NOT NULL,
+    "source_environment_id"      integer     NOT NULL,
+    "destination_environment_id" integer     NOT NULL,
+    "destination_pipeline_id"    integer,
+    "app_group_id"               integer,
+    "status"                     varchar(20) NOT NULL,
+    "policy_result"              text,
+    "message"                    text,
+    "release_id"                 integer,
+    "promoted_by"                integer     NOT NULL,
+    "promoted_on"                timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "artifact_promotion_release_train_id_fkey" FOREIGN KEY ("release_train_id") REFERENCES "public"."release_train" ("id"),
+    CONSTRAINT "artifact_promotion_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "artifact_promotion_app_id_ci_artifact_id_idx" ON "public"."artifact_promotion" ("app_id", "ci_artifact_id");
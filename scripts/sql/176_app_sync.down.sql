@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "public"."app_sync_resource_status";
+DROP SEQUENCE IF EXISTS id_seq_app_sync_resource_status;
+DROP TABLE IF EXISTS "public"."app_sync_managed_app";
+DROP SEQUENCE IF EXISTS id_seq_app_sync_managed_app;
+DROP TABLE IF EXISTS "public"."app_sync_source";
+DROP SEQUENCE IF EXISTS id_seq_app_sync_source;
@@ -0,0 +1,74 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_sync_source;
+
+CREATE TABLE IF NOT EXISTS "public"."app_sync_source"
+(
+    "id"                 integer      NOT NULL DEFAULT nextval('id_seq_app_sync_source'::regclass),
+    "name"               varchar(50)  NOT NULL,
+    "git_repo_url"       varchar(250) NOT NULL,
+    "branch"             varchar(250) NOT NULL,
+    "path"               varchar(250),
+    "git_provider_id"    integer      NOT NULL,
+    "auto_sync"          bool         NOT NULL,
+    "prune"              bool         NOT NULL,
+    "active"             bool         NOT NULL,
+    "last_synced_commit" varchar(64),
+    "last_synced_on"     timestamptz,
+    "last_sync_status"   varchar(20),
+    "last_sync_message"  text,
+    -- set while a sync of the source runs, so that a source is synced by one replica at a time
+    "sync_started_on"    timestamptz,
+    "created_on"         timestamptz  NOT NULL,
+    "created_by"         integer      NOT NULL,
+    "updated_on"         timestamptz  NOT NULL,
+    "updated_by"         integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_sync_source_git_provider_id_fkey" FOREIGN KEY ("git_provider_id") REFERENCES "public"."git_provider" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "app_sync_source_name_active_idx" ON "public"."app_sync_source" ("name") WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_sync_managed_app;
+
+CREATE TABLE IF NOT EXISTS "public"."app_sync_managed_app"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_app_sync_managed_app'::regclass),
+    "sync_source_id" integer      NOT NULL,
+    "app_id"         integer      NOT NULL,
+    "file_path"      varchar(250) NOT NULL,
+    "active"         bool         NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_sync_managed_app_sync_source_id_fkey" FOREIGN KEY ("sync_source_id") REFERENCES "public"."app_sync_source" ("id"),
+    CONSTRAINT "app_sync_managed_app_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "app_sync_managed_app_app_id_active_idx" ON "public"."app_sync_managed_app" ("app_id") WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_sync_resource_status;
+
+CREATE TABLE IF NOT EXISTS "public"."app_sync_resource_status"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_app_sync_resource_status'::regclass),
+    "sync_source_id" integer      NOT NULL,
+    "app_name"       varchar(250) NOT NULL,
+    "app_id"         integer,
+    "file_path"      varchar(250),
+    "resource"       varchar(50)  NOT NULL,
+    "resource_name"  varchar(250),
+    "action"         varchar(20)  NOT NULL,
+    "status"         varchar(20)  NOT NULL,
+    "message"        text,
+    "diff"           text,
+    "commit_hash"    varchar(64),
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_sync_resource_status_sync_source_id_fkey" FOREIGN KEY ("sync_source_id") REFERENCES "public"."app_sync_source" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "app_sync_resource_status_sync_source_id_idx" ON "public"."app_sync_resource_status" ("sync_source_id");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "public"."preview_environment";
+DROP SEQUENCE IF EXISTS id_seq_preview_environment;
+DROP TABLE IF EXISTS "public"."preview_environment_config";
+DROP SEQUENCE IF EXISTS id_seq_preview_environment_config;
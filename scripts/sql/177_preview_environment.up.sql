@@ -0,0 +1,61 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_preview_environment_config;
+
+CREATE TABLE IF NOT EXISTS "public"."preview_environment_config"
+(
+    "id"                      integer     NOT NULL DEFAULT nextval('id_seq_preview_environment_config'::regclass),
+    "pipeline_id"             integer     NOT NULL,
+    "cluster_id"              integer     NOT NULL,
+    "namespace_prefix"        varchar(40) NOT NULL,
+    "values_override"         text,
+    "url_template"            text,
+    "ttl_in_hours"            integer     NOT NULL,
+    "comment_on_pull_request" bool        NOT NULL,
+    "active"                  bool        NOT NULL,
+    "created_on"              timestamptz NOT NULL,
+    "created_by"              integer     NOT NULL,
+    "updated_on"              timestamptz NOT NULL,
+    "updated_by"              integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "preview_environment_config_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "preview_environment_config_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "preview_environment_config_active_pipeline_id_key"
+    ON "public"."preview_environment_config" ("pipeline_id") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_preview_environment;
+
+CREATE TABLE IF NOT EXISTS "public"."preview_environment"
+(
+    "id"                   integer      NOT NULL DEFAULT nextval('id_seq_preview_environment'::regclass),
+    "config_id"            integer      NOT NULL,
+    "template_pipeline_id" integer      NOT NULL,
+    "pull_request_url"     varchar(500) NOT NULL,
+    "pull_request_number"  integer      NOT NULL,
+    "pull_request_title"   text,
+    "source_branch"        varchar(250),
+    "environment_id"       integer,
+    "pipeline_id"          integer,
+    "namespace"            varchar(250) NOT NULL,
+    "namespace_created"    bool         NOT NULL DEFAULT FALSE,
+    "url"                  text,
+    "ci_artifact_id"       integer,
+    "status"               varchar(50)  NOT NULL,
+    "message"              text,
+    "commented"            bool         NOT NULL,
+    "expires_on"           timestamptz  NOT NULL,
+    "created_on"           timestamptz  NOT NULL,
+    "created_by"           integer      NOT NULL,
+    "updated_on"           timestamptz  NOT NULL,
+    "updated_by"           integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "preview_environment_config_id_fkey" FOREIGN KEY ("config_id") REFERENCES "public"."preview_environment_config" ("id"),
+    CONSTRAINT "preview_environment_template_pipeline_id_fkey" FOREIGN KEY ("template_pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+-- one live preview per pull request and template pipeline, guards against concurrent ci completions of the same pull request
+CREATE UNIQUE INDEX IF NOT EXISTS "preview_environment_live_pull_request_key"
+    ON "public"."preview_environment" ("template_pipeline_id", "pull_request_url") WHERE "status" != 'DELETED';
+
+CREATE INDEX IF NOT EXISTS "preview_environment_pull_request_url_idx"
+    ON "public"."preview_environment" ("pull_request_url");
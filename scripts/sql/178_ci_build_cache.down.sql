@@ -0,0 +1,7 @@
+ALTER TABLE "public"."ci_workflow" DROP COLUMN IF EXISTS "build_cache_key";
+ALTER TABLE "public"."ci_workflow" DROP COLUMN IF EXISTS "build_cache_hits";
+ALTER TABLE "public"."ci_workflow" DROP COLUMN IF EXISTS "build_cache_misses";
+DROP TABLE IF EXISTS "public"."ci_build_cache_entry";
+DROP SEQUENCE IF EXISTS id_seq_ci_build_cache_entry;
+DROP TABLE IF EXISTS "public"."ci_build_cache_config";
+DROP SEQUENCE IF EXISTS id_seq_ci_build_cache_config;
@@ -0,0 +1,52 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_build_cache_config;
+
+CREATE TABLE IF NOT EXISTS "public"."ci_build_cache_config"
+(
+    "id"               integer      NOT NULL DEFAULT nextval('id_seq_ci_build_cache_config'::regclass),
+    "ci_pipeline_id"   integer      NOT NULL,
+    "mode"             varchar(20)  NOT NULL,
+    "cache_repository" varchar(250),
+    "export_mode"      varchar(10),
+    "key_by_branch"    bool         NOT NULL,
+    "key_files"        text,
+    "generation"       integer      NOT NULL DEFAULT 0,
+    "invalidated_on"   timestamptz,
+    "active"           bool         NOT NULL,
+    "created_on"       timestamptz  NOT NULL,
+    "created_by"       integer      NOT NULL,
+    "updated_on"       timestamptz  NOT NULL,
+    "updated_by"       integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "ci_build_cache_config_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "ci_build_cache_config_active_ci_pipeline_id_key"
+    ON "public"."ci_build_cache_config" ("ci_pipeline_id") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_build_cache_entry;
+
+-- cache images exported by ci, used for import fallbacks and retention
+CREATE TABLE IF NOT EXISTS "public"."ci_build_cache_entry"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_ci_build_cache_entry'::regclass),
+    "ci_pipeline_id" integer      NOT NULL,
+    "cache_key"      varchar(128) NOT NULL,
+    "cache_ref"      varchar(500) NOT NULL,
+    "status"         varchar(20)  NOT NULL,
+    "last_used_on"   timestamptz  NOT NULL,
+    -- build the expired cache image is handed to for removal
+    "prune_ci_workflow_id" integer,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "ci_build_cache_entry_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "ci_build_cache_entry_ci_pipeline_id_status_idx"
+    ON "public"."ci_build_cache_entry" ("ci_pipeline_id", "status");
+
+ALTER TABLE "public"."ci_workflow" ADD COLUMN IF NOT EXISTS "build_cache_key" varchar(128);
+ALTER TABLE "public"."ci_workflow" ADD COLUMN IF NOT EXISTS "build_cache_hits" integer;
+ALTER TABLE "public"."ci_workflow" ADD COLUMN IF NOT EXISTS "build_cache_misses" integer;
@@ -0,0 +1,4 @@
+ALTER TABLE "public"."pipeline" DROP COLUMN IF EXISTS "validate_image_platforms";
+
+ALTER TABLE "public"."ci_artifact" DROP COLUMN IF EXISTS "platform_digests";
+ALTER TABLE "public"."ci_artifact" DROP COLUMN IF EXISTS "manifest_list_digest";
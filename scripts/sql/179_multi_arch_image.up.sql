@@ -0,0 +1,4 @@
+ALTER TABLE "public"."ci_artifact" ADD COLUMN IF NOT EXISTS "manifest_list_digest" varchar(255);
+ALTER TABLE "public"."ci_artifact" ADD COLUMN IF NOT EXISTS "platform_digests" json;
+
+ALTER TABLE "public"."pipeline" ADD COLUMN IF NOT EXISTS "validate_image_platforms" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "public"."image_sbom_package";
+DROP SEQUENCE IF EXISTS id_seq_image_sbom_package;
+DROP TABLE IF EXISTS "public"."image_sbom";
+DROP SEQUENCE IF EXISTS id_seq_image_sbom;
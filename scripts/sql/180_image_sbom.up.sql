@@ -0,0 +1,45 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_sbom;
+
+CREATE TABLE IF NOT EXISTS "public"."image_sbom"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_image_sbom'::regclass),
+    "ci_artifact_id" integer      NOT NULL,
+    "image_digest"   varchar(255) NOT NULL,
+    "format"         varchar(50)  NOT NULL,
+    "spec_version"   varchar(50),
+    "source"         varchar(50)  NOT NULL,
+    "document"       text         NOT NULL,
+    "package_count"  integer      NOT NULL,
+    "active"         bool         NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "image_sbom_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "image_sbom_image_digest_idx"
+    ON "public"."image_sbom" ("image_digest") WHERE "active" = TRUE;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_sbom_package;
+
+CREATE TABLE IF NOT EXISTS "public"."image_sbom_package"
+(
+    "id"            integer      NOT NULL DEFAULT nextval('id_seq_image_sbom_package'::regclass),
+    "image_sbom_id" integer      NOT NULL,
+    "image_digest"  varchar(255) NOT NULL,
+    "name"          varchar(500) NOT NULL,
+    "version"       varchar(250),
+    "type"          varchar(100),
+    "purl"          text,
+    "license"       text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "image_sbom_package_image_sbom_id_fkey" FOREIGN KEY ("image_sbom_id") REFERENCES "public"."image_sbom" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "image_sbom_package_name_idx"
+    ON "public"."image_sbom_package" (lower("name"));
+
+CREATE INDEX IF NOT EXISTS "image_sbom_package_image_digest_idx"
+    ON "public"."image_sbom_package" ("image_digest");
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "public"."user_access_audit";
+DROP SEQUENCE IF EXISTS id_seq_user_access_audit;
+DROP TABLE IF EXISTS "public"."user_access_grant";
+DROP SEQUENCE IF EXISTS id_seq_user_access_grant;
+DROP TABLE IF EXISTS "public"."user_access_request";
+DROP SEQUENCE IF EXISTS id_seq_user_access_request;
@@ -0,0 +1,81 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_request;
+
+-- self-service request of a user for access, granted for the requested window on approval
+CREATE TABLE IF NOT EXISTS "public"."user_access_request"
+(
+    "id"             integer     NOT NULL DEFAULT nextval('id_seq_user_access_request'::regclass),
+    "user_id"        integer     NOT NULL,
+    "role_filter"    json,
+    "role_group_id"  integer,
+    "reason"         text,
+    "starts_on"      timestamptz NOT NULL,
+    "expires_on"     timestamptz NOT NULL,
+    "status"         varchar(50) NOT NULL,
+    "reviewed_by"    integer,
+    "reviewed_on"    timestamptz,
+    "review_comment" text,
+    "created_on"     timestamptz NOT NULL,
+    "created_by"     integer     NOT NULL,
+    "updated_on"     timestamptz NOT NULL,
+    "updated_by"     integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "user_access_request_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    CONSTRAINT "user_access_request_role_group_id_fkey" FOREIGN KEY ("role_group_id") REFERENCES "public"."role_group" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "user_access_request_status_idx"
+    ON "public"."user_access_request" ("status");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_grant;
+
+-- time bound mapping of a user to a role or to a role group
+CREATE TABLE IF NOT EXISTS "public"."user_access_grant"
+(
+    "id"                integer     NOT NULL DEFAULT nextval('id_seq_user_access_grant'::regclass),
+    "user_id"           integer     NOT NULL,
+    "role_id"           integer,
+    "role_group_id"     integer,
+    "access_request_id" integer,
+    "starts_on"         timestamptz NOT NULL,
+    "expires_on"        timestamptz NOT NULL,
+    "status"            varchar(50) NOT NULL,
+    -- set when the user_roles mapping or the group policy was added by the grant, only these are removed on expiry
+    "mapping_owned"     bool        NOT NULL DEFAULT FALSE,
+    "reason"            text,
+    "revoked_by"        integer,
+    "revoked_on"        timestamptz,
+    "created_on"        timestamptz NOT NULL,
+    "created_by"        integer     NOT NULL,
+    "updated_on"        timestamptz NOT NULL,
+    "updated_by"        integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "user_access_grant_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    CONSTRAINT "user_access_grant_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "public"."roles" ("id") ON DELETE CASCADE,
+    CONSTRAINT "user_access_grant_role_group_id_fkey" FOREIGN KEY ("role_group_id") REFERENCES "public"."role_group" ("id"),
+    CONSTRAINT "user_access_grant_access_request_id_fkey" FOREIGN KEY ("access_request_id") REFERENCES "public"."user_access_request" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "user_access_grant_status_idx"
+    ON "public"."user_access_grant" ("status");
+
+CREATE INDEX IF NOT EXISTS "user_access_grant_user_id_idx"
+    ON "public"."user_access_grant" ("user_id");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_access_audit;
+
+-- who requested, approved, granted and revoked what and when
+CREATE TABLE IF NOT EXISTS "public"."user_access_audit"
+(
+    "id"                integer     NOT NULL DEFAULT nextval('id_seq_user_access_audit'::regclass),
+    "user_id"           integer     NOT NULL,
+    "access_grant_id"   integer,
+    "access_request_id" integer,
+    "action"            varchar(50) NOT NULL,
+    "details"           text,
+    "performed_by"      integer     NOT NULL,
+    "performed_on"      timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "user_access_audit_user_id_idx"
+    ON "public"."user_access_audit" ("user_id");
@@ -0,0 +1,8 @@
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "expiry_notified_on";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "last_used_by_ip";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "last_used_at";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "previous_token_expire_at_in_ms";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "previous_token";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "expiry_notification_config_ids";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "ip_allowlist";
+ALTER TABLE "public"."api_token" DROP COLUMN IF EXISTS "scopes";
@@ -0,0 +1,12 @@
+-- scopes narrow down the roles of the token user, ip_allowlist holds ips or cidrs the token can be used from
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "scopes" json;
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "ip_allowlist" text[];
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "expiry_notification_config_ids" integer[];
+
+-- token replaced on rotation, accepted till previous_token_expire_at_in_ms
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "previous_token" text;
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "previous_token_expire_at_in_ms" bigint;
+
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "last_used_at" timestamptz;
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "last_used_by_ip" varchar(100);
+ALTER TABLE "public"."api_token" ADD COLUMN IF NOT EXISTS "expiry_notified_on" timestamptz;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS scim_resource_type_resource_id_idx;
+DROP TABLE IF EXISTS "public"."scim_resource";
+DROP SEQUENCE IF EXISTS id_seq_scim_resource;
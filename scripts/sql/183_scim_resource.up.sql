@@ -0,0 +1,20 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_scim_resource;
+
+-- attributes of users and role groups provisioned through SCIM which have no place on the users and role_group tables
+CREATE TABLE IF NOT EXISTS "public"."scim_resource"
+(
+    "id"            integer      NOT NULL DEFAULT nextval('id_seq_scim_resource'::regclass),
+    "resource_type" varchar(20)  NOT NULL,
+    "resource_id"   integer      NOT NULL,
+    "external_id"   varchar(250),
+    "display_name"  varchar(250),
+    "given_name"    varchar(250),
+    "family_name"   varchar(250),
+    "created_on"    timestamptz  NOT NULL,
+    "created_by"    integer      NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    "updated_by"    integer      NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scim_resource_type_resource_id_idx ON "public"."scim_resource" ("resource_type", "resource_id");
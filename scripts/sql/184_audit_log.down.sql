@@ -0,0 +1,5 @@
+DROP RULE IF EXISTS audit_log_no_update ON "public"."audit_log";
+DROP INDEX IF EXISTS audit_log_user_id_idx;
+DROP INDEX IF EXISTS audit_log_action_time_idx;
+DROP TABLE IF EXISTS "public"."audit_log";
+DROP SEQUENCE IF EXISTS id_seq_audit_log;
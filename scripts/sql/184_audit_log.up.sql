@@ -0,0 +1,27 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_audit_log;
+
+-- one row per mutating api call, rows are only ever inserted and deleted by the retention policy
+CREATE TABLE IF NOT EXISTS "public"."audit_log"
+(
+    "id"              integer      NOT NULL DEFAULT nextval('id_seq_audit_log'::regclass),
+    "action_time"     timestamptz  NOT NULL,
+    "user_id"         integer,
+    "email_id"        varchar(250),
+    "user_type"       varchar(50),
+    "api_token_name"  varchar(250),
+    "client_ip"       varchar(250),
+    "method"          varchar(10)  NOT NULL,
+    "path"            text         NOT NULL,
+    "resource"        text         NOT NULL,
+    "resource_params" jsonb,
+    "action"          varchar(20)  NOT NULL,
+    "status_code"     integer      NOT NULL,
+    "duration_in_ms"  bigint,
+    "config_changes"  jsonb,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_action_time_idx ON "public"."audit_log" ("action_time");
+CREATE INDEX IF NOT EXISTS audit_log_user_id_idx ON "public"."audit_log" ("user_id");
+
+CREATE RULE audit_log_no_update AS ON UPDATE TO "public"."audit_log" DO INSTEAD NOTHING;
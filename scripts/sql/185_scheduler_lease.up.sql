@@ -0,0 +1,8 @@
+-- one row per background job which runs on a single orchestrator replica at a time
+CREATE TABLE IF NOT EXISTS "public"."scheduler_lease"
+(
+    "name"       varchar(100) NOT NULL,
+    "holder"     varchar(250) NOT NULL,
+    "expires_on" timestamptz  NOT NULL,
+    PRIMARY KEY ("name")
+);
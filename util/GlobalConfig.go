@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/caarlos0/env"
 	"github.com/juju/errors"
+	"net"
+	"strings"
 )
 
 type GlobalEnvVariables struct {
@@ -31,3 +33,34 @@ func GetDevtronSecretName() (*DevtronSecretConfig, error) {
 	}
 	return secretConfig, err
 }
+
+type TrustedProxyConfig struct {
+	// TrustedProxies are the ips and cidrs of proxies in front of devtron whose X-Forwarded-For entries are trusted
+	TrustedProxies []string `env:"API_TOKEN_TRUSTED_PROXIES" envSeparator:","`
+}
+
+// GetTrustedProxies returns the validated trusted proxies, to be used with GetTrustedClientIp wherever the client ip
+// matters
+func GetTrustedProxies() ([]string, error) {
+	cfg := &TrustedProxyConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var trustedProxies []string
+	for _, entry := range cfg.TrustedProxies {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			if _, _, err = net.ParseCIDR(entry); err != nil {
+				return nil, fmt.Errorf("invalid cidr %s in trusted proxies", entry)
+			}
+		} else if net.ParseIP(entry) == nil {
+			return nil, fmt.Errorf("invalid ip %s in trusted proxies", entry)
+		}
+		trustedProxies = append(trustedProxies, entry)
+	}
+	return trustedProxies, nil
+}
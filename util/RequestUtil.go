@@ -18,7 +18,9 @@
 package util
 
 import (
+	"net"
 	"net/http"
+	"strings"
 )
 
 const xForwardedForHeaderName = "X-Forwarded-For"
@@ -32,3 +34,73 @@ func GetClientIP(r *http.Request) string {
 	}
 	return r.RemoteAddr
 }
+
+// GetBearerToken returns the token of an "Authorization: Bearer <token>" header, empty when the header is absent or
+// of another scheme
+func GetBearerToken(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+// GetRequestClientIp returns the ip a request came from, X-Forwarded-For is only trusted when sent by one of the
+// trusted proxies
+func GetRequestClientIp(r *http.Request, trustedProxies []string) string {
+	return GetTrustedClientIp(r.RemoteAddr, strings.Join(r.Header.Values(xForwardedForHeaderName), ","), trustedProxies)
+}
+
+// GetTrustedClientIp returns the ip a request came from. X-Forwarded-For is only taken into account when the remote
+// address is a trusted proxy, in which case the right-most hop which is not a trusted proxy is the client, as entries
+// left of it can be set by the client itself
+func GetTrustedClientIp(remoteAddr string, forwardedFor string, trustedProxies []string) string {
+	clientIp := getHost(remoteAddr)
+	if !isTrustedProxy(trustedProxies, clientIp) || len(strings.TrimSpace(forwardedFor)) == 0 {
+		return clientIp
+	}
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		clientIp = getHost(hops[i])
+		if !isTrustedProxy(trustedProxies, clientIp) {
+			return clientIp
+		}
+	}
+	// every hop is a trusted proxy, the left-most one is closest to the client
+	return clientIp
+}
+
+// getHost returns the ip of an address without the port
+func getHost(address string) string {
+	address = strings.TrimSpace(address)
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+func isTrustedProxy(trustedProxies []string, ip string) bool {
+	return len(trustedProxies) > 0 && IsIpAllowed(trustedProxies, ip)
+}
+
+// IsIpAllowed tells if the ip matches one of the ips and cidrs of the allowlist, an empty allowlist allows every ip
+func IsIpAllowed(ipAllowlist []string, clientIp string) bool {
+	if len(ipAllowlist) == 0 {
+		return true
+	}
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return false
+	}
+	for _, entry := range ipAllowlist {
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err == nil && ipNet.Contains(ip) {
+				return true
+			}
+		} else if allowedIp := net.ParseIP(entry); allowedIp != nil && allowedIp.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
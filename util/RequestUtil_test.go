@@ -0,0 +1,35 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRequestClientIp(t *testing.T) {
+	trustedProxies := []string{"172.16.0.0/12"}
+	newRequest := func(remoteAddr string, forwardedFor ...string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/orchestrator/app/list", nil)
+		r.RemoteAddr = remoteAddr
+		for _, value := range forwardedFor {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		return r
+	}
+
+	// spoofed header sent directly is ignored
+	r := newRequest("203.0.113.9:53412", "10.0.3.4")
+	assert.Equal(t, "203.0.113.9", GetRequestClientIp(r, nil))
+	assert.Equal(t, "203.0.113.9", GetRequestClientIp(r, trustedProxies))
+	// spoofed entry sent through a trusted proxy is left of the hop added by the proxy
+	r = newRequest("172.16.0.1:53412", "10.0.3.4, 203.0.113.9")
+	assert.Equal(t, "203.0.113.9", GetRequestClientIp(r, trustedProxies))
+	assert.Equal(t, "172.16.0.1", GetRequestClientIp(r, nil))
+	// trusted proxies chained are skipped, also when they send separate headers
+	r = newRequest("172.16.0.1:53412", "10.0.3.4, 203.0.113.9", "172.20.0.5")
+	assert.Equal(t, "203.0.113.9", GetRequestClientIp(r, trustedProxies))
+	// every hop trusted is the left-most one
+	r = newRequest("172.16.0.1:53412", "172.16.0.2")
+	assert.Equal(t, "172.16.0.2", GetRequestClientIp(r, trustedProxies))
+}
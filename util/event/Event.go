@@ -22,6 +22,9 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const Approval EventType = 4
+const ConfigDrift EventType = 5
+const ApiTokenExpiry EventType = 6
 
 type PipelineType string
 
@@ -37,6 +40,10 @@ const (
 	SES     Channel = "ses"
 	SMTP    Channel = "smtp"
 	Webhook Channel = "webhook"
+	// channels below are delivered by the pluggable notification channel providers
+	Teams     Channel = "teams"
+	Discord   Channel = "discord"
+	PagerDuty Channel = "pagerduty"
 )
 
 type UpdateType string
@@ -211,6 +211,53 @@ func (impl K8sUtil) CreateNsIfNotExists(namespace string, clusterConfig *Cluster
 	return err
 }
 
+// CreateNsWithLabelsIfNotExists creates the namespace with the given labels. owned is true when the namespace was
+// created now or carries the labels already, i.e. was created by an earlier call for the same owner
+func (impl K8sUtil) CreateNsWithLabelsIfNotExists(namespace string, labels map[string]string, clusterConfig *ClusterConfig) (owned bool, err error) {
+	v12Client, err := impl.GetCoreV1Client(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error", "error", err, "clusterConfig", clusterConfig)
+		return false, err
+	}
+	ns, err := v12Client.Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if err == nil {
+		for key, value := range labels {
+			if ns.Labels[key] != value {
+				return false, nil
+			}
+		}
+		return true, nil
+	} else if !errors.IsNotFound(err) {
+		impl.logger.Errorw("error in checking if ns exist", "err", err, "ns", namespace)
+		return false, err
+	}
+	nsSpec := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: labels}}
+	_, err = v12Client.Namespaces().Create(context.Background(), nsSpec, metav1.CreateOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in creating ns", "err", err, "ns", namespace)
+		return false, err
+	}
+	return true, nil
+}
+
+func (impl K8sUtil) DeleteNsIfExists(namespace string, clusterConfig *ClusterConfig) (err error) {
+	v12Client, err := impl.GetCoreV1Client(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error", "error", err, "clusterConfig", clusterConfig)
+		return err
+	}
+	exists, err := impl.checkIfNsExists(namespace, v12Client)
+	if err != nil {
+		impl.logger.Errorw("error", "error", err, "clusterConfig", clusterConfig)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	impl.logger.Infow("ns exists deleting", "ns", namespace)
+	return impl.deleteNs(namespace, v12Client)
+}
+
 func (impl K8sUtil) checkIfNsExists(namespace string, client *v12.CoreV1Client) (exists bool, err error) {
 	ns, err := client.Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
 	//ns, err := impl.k8sClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})